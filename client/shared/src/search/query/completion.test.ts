@@ -454,6 +454,7 @@ describe('getCompletionItems()', () => {
               "contains.content(\${1:TODO}) ",
               "contains(file:\${1:CHANGELOG} content:\${2:fix}) ",
               "contains.commit.after(\${1:1 month ago}) ",
+              "has.description(\${1:library}) ",
               "^repo/with\\\\ a\\\\ space$ "
             ]
         `)
@@ -472,7 +473,8 @@ describe('getCompletionItems()', () => {
               "contains.file(\${1:CHANGELOG}) ",
               "contains.content(\${1:TODO}) ",
               "contains(file:\${1:CHANGELOG} content:\${2:fix}) ",
-              "contains.commit.after(\${1:1 month ago}) "
+              "contains.commit.after(\${1:1 month ago}) ",
+              "has.description(\${1:library}) "
             ]
         `)
     })
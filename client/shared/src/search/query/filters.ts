@@ -244,8 +244,8 @@ export const FILTERS: Record<NegatableFilter, NegatableFilterDefinition> &
             `${negated ? 'Exclude' : 'Include only'} Commits with messages matching a certain string`,
     },
     [FilterType.patterntype]: {
-        discreteValues: () => ['regexp', 'literal', 'structural'].map(value => ({ label: value })),
-        description: 'The pattern type (regexp, literal, structural) in use',
+        discreteValues: () => ['regexp', 'literal', 'structural', 'fuzzy'].map(value => ({ label: value })),
+        description: 'The pattern type (regexp, literal, structural, fuzzy) in use',
         singular: true,
     },
     [FilterType.repo]: {
@@ -166,6 +166,10 @@ const toContainsHover = (token: MetaPredicate): string => {
             return `**Built-in predicate**. Search only inside repositories that contain **file content** matching the regular expression \`${parameters}\`.`
         case 'contains.commit.after':
             return `**Built-in predicate**. Search only inside repositories that have been committed to since \`${parameters}\`.`
+        case 'has.description':
+            return `**Built-in predicate**. Search only inside repositories whose **description** matches the regular expression \`${parameters}\`.`
+        case 'has.owner':
+            return `**Built-in predicate**. Search only inside files owned by \`${parameters}\` according to the repository's CODEOWNERS file.`
     }
     return ''
 }
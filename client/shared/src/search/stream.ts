@@ -135,6 +135,7 @@ export interface Skipped {
      * - repository-missing :: we could not search a repository because it is not cloned and we failed to find it on the remote code host.
      * - excluded-fork :: we did not search a repository because it is a fork.
      * - excluded-archive :: we did not search a repository because it is archived.
+     * - blame-truncated :: we did not check all matches in a repository against the blame filters of the query.
      * - display :: we hit the display limit, so we stopped sending results from the backend.
      */
     reason:
@@ -146,6 +147,7 @@ export interface Skipped {
         | 'repository-missing'
         | 'excluded-fork'
         | 'excluded-archive'
+        | 'blame-truncated'
         | 'display'
         | 'error'
     /**
@@ -5,7 +5,9 @@ import BitbucketIcon from 'mdi-react/BitbucketIcon'
 import GithubIcon from 'mdi-react/GithubIcon'
 import GitIcon from 'mdi-react/GitIcon'
 import GitLabIcon from 'mdi-react/GitlabIcon'
+import LanguageGoIcon from 'mdi-react/LanguageGoIcon'
 import LanguageJavaIcon from 'mdi-react/LanguageJavaIcon'
+import NpmIcon from 'mdi-react/NpmIcon'
 import React from 'react'
 
 import { PhabricatorIcon } from '@sourcegraph/shared/src/components/icons'
@@ -13,10 +15,14 @@ import { PhabricatorIcon } from '@sourcegraph/shared/src/components/icons'
 import awsCodeCommitSchemaJSON from '../../../../../schema/aws_codecommit.schema.json'
 import bitbucketCloudSchemaJSON from '../../../../../schema/bitbucket_cloud.schema.json'
 import bitbucketServerSchemaJSON from '../../../../../schema/bitbucket_server.schema.json'
+import gerritSchemaJSON from '../../../../../schema/gerrit.schema.json'
+import giteaSchemaJSON from '../../../../../schema/gitea.schema.json'
 import githubSchemaJSON from '../../../../../schema/github.schema.json'
 import gitlabSchemaJSON from '../../../../../schema/gitlab.schema.json'
 import gitoliteSchemaJSON from '../../../../../schema/gitolite.schema.json'
+import goModulesSchemaJSON from '../../../../../schema/go-modules.schema.json'
 import jvmPackagesSchemaJSON from '../../../../../schema/jvm-packages.schema.json'
+import npmPackagesSchemaJSON from '../../../../../schema/npm-packages.schema.json'
 import otherExternalServiceSchemaJSON from '../../../../../schema/other_external_service.schema.json'
 import perforceSchemaJSON from '../../../../../schema/perforce.schema.json'
 import phabricatorSchemaJSON from '../../../../../schema/phabricator.schema.json'
@@ -686,6 +692,15 @@ const BITBUCKET_CLOUD: AddExternalServiceOptions = {
                 return { edits, selectText: value }
             },
         },
+        {
+            id: 'enforcePermissions',
+            label: 'Enforce permissions',
+            run: (config: string) => {
+                const value = {}
+                const edits = setProperty(config, ['authorization'], value, defaultFormattingOptions)
+                return { edits, selectText: '"authorization": {}' }
+            },
+        },
     ],
     instructions: (
         <div>
@@ -1013,6 +1028,117 @@ const GITOLITE: AddExternalServiceOptions = {
         },
     ],
 }
+const GITEA: AddExternalServiceOptions = {
+    kind: ExternalServiceKind.GITEA,
+    title: 'Gitea',
+    icon: GitIcon,
+    jsonSchema: giteaSchemaJSON,
+    defaultDisplayName: 'Gitea',
+    defaultConfig: `{
+  "url": "https://gitea.example.com",
+  "token": "<access token>",
+  "orgs": []
+}`,
+    instructions: (
+        <div>
+            <ol>
+                <li>
+                    In the configuration below, set <Field>url</Field> to the URL of your Gitea instance.
+                </li>
+                <li>
+                    Create a Gitea access token in <strong>Settings &gt; Applications</strong> and set it as{' '}
+                    <Field>token</Field>.
+                </li>
+                <li>
+                    Set <Field>orgs</Field> or <Field>repos</Field> to the repositories you want to mirror. If neither
+                    is set, all repositories the token has access to are mirrored.
+                </li>
+            </ol>
+            <p>
+                See{' '}
+                <a
+                    rel="noopener noreferrer"
+                    target="_blank"
+                    href="https://docs.sourcegraph.com/admin/external_service/gitea#configuration"
+                >
+                    the docs for more advanced options
+                </a>
+                , or try one of the buttons below.
+            </p>
+        </div>
+    ),
+    editorActions: [
+        {
+            id: 'addOrg',
+            label: 'Add an organization',
+            run: (config: string) => {
+                const value = '<organization name>'
+                const edits = setProperty(config, ['orgs', -1], value, defaultFormattingOptions)
+                return { edits, selectText: value }
+            },
+        },
+        {
+            id: 'addRepo',
+            label: 'Add a repository',
+            run: (config: string) => {
+                const value = '<owner>/<repository>'
+                const edits = setProperty(config, ['repos', -1], value, defaultFormattingOptions)
+                return { edits, selectText: value }
+            },
+        },
+    ],
+}
+const GERRIT: AddExternalServiceOptions = {
+    kind: ExternalServiceKind.GERRIT,
+    title: 'Gerrit',
+    icon: GitIcon,
+    jsonSchema: gerritSchemaJSON,
+    defaultDisplayName: 'Gerrit',
+    defaultConfig: `{
+  "url": "https://gerrit.example.com",
+  "username": "<username>",
+  "password": "<HTTP password>"
+}`,
+    instructions: (
+        <div>
+            <ol>
+                <li>
+                    In the configuration below, set <Field>url</Field> to the URL of your Gerrit instance.
+                </li>
+                <li>
+                    Set <Field>username</Field> and <Field>password</Field> to the username and HTTP password of a
+                    Gerrit account.
+                </li>
+                <li>
+                    Optionally, set <Field>projects</Field> to the projects you want to mirror. If it is not set, all
+                    projects visible to the account are mirrored.
+                </li>
+            </ol>
+            <p>
+                See{' '}
+                <a
+                    rel="noopener noreferrer"
+                    target="_blank"
+                    href="https://docs.sourcegraph.com/admin/external_service/gerrit#configuration"
+                >
+                    the docs for more advanced options
+                </a>
+                , or try one of the buttons below.
+            </p>
+        </div>
+    ),
+    editorActions: [
+        {
+            id: 'addProject',
+            label: 'Add a project',
+            run: (config: string) => {
+                const value = '<project name>'
+                const edits = setProperty(config, ['projects', -1], value, defaultFormattingOptions)
+                return { edits, selectText: value }
+            },
+        },
+    ],
+}
 const PHABRICATOR_SERVICE: AddExternalServiceOptions = {
     kind: ExternalServiceKind.PHABRICATOR,
     title: 'Phabricator connection',
@@ -1208,6 +1334,59 @@ const JVM_PACKAGES: AddExternalServiceOptions = {
     ),
     editorActions: [],
 }
+const NPM_PACKAGES: AddExternalServiceOptions = {
+    kind: ExternalServiceKind.NPMPACKAGES,
+    title: 'npm Dependencies',
+    icon: NpmIcon,
+    jsonSchema: npmPackagesSchemaJSON,
+    defaultDisplayName: 'npm Dependencies',
+    defaultConfig: `{
+  "registry": "https://registry.npmjs.org",
+  "dependencies": []
+}`,
+    instructions: (
+        <div>
+            <ol>
+                <li>
+                    In the configuration below, set <Field>registry</Field> to the URL of the npm registry. For
+                    example, <code>"https://registry.npmjs.org"</code>.
+                </li>
+                <li>
+                    In the configuration below, set <Field>dependencies</Field> to the list of package versions that
+                    you want to manually add. For example, <code>"react@17.0.2"</code> or{' '}
+                    <code>"@types/node@16.4.13"</code>.
+                </li>
+            </ol>
+        </div>
+    ),
+    editorActions: [],
+}
+const GO_MODULES: AddExternalServiceOptions = {
+    kind: ExternalServiceKind.GOMODULES,
+    title: 'Go Dependencies',
+    icon: LanguageGoIcon,
+    jsonSchema: goModulesSchemaJSON,
+    defaultDisplayName: 'Go Dependencies',
+    defaultConfig: `{
+  "urls": ["https://proxy.golang.org"],
+  "dependencies": []
+}`,
+    instructions: (
+        <div>
+            <ol>
+                <li>
+                    In the configuration below, set <Field>urls</Field> to the list of Go module proxies, which are
+                    tried in order. For example, <code>"https://proxy.golang.org"</code>.
+                </li>
+                <li>
+                    In the configuration below, set <Field>dependencies</Field> to the list of module versions that
+                    you want to manually add. For example, <code>"github.com/sourcegraph/go-diff@v0.6.1"</code>.
+                </li>
+            </ol>
+        </div>
+    ),
+    editorActions: [],
+}
 
 export const codeHostExternalServices: Record<string, AddExternalServiceOptions> = {
     github: GITHUB_DOTCOM,
@@ -1219,9 +1398,13 @@ export const codeHostExternalServices: Record<string, AddExternalServiceOptions>
     aws_codecommit: AWS_CODE_COMMIT,
     srcservegit: SRC_SERVE_GIT,
     gitolite: GITOLITE,
+    gitea: GITEA,
+    gerrit: GERRIT,
     git: GENERIC_GIT,
     ...(window.context?.experimentalFeatures?.perforce === 'enabled' ? { perforce: PERFORCE } : {}),
     ...(window.context?.experimentalFeatures?.jvmPackages === 'enabled' ? { jvmPackages: JVM_PACKAGES } : {}),
+    ...(window.context?.experimentalFeatures?.npmPackages === 'enabled' ? { npmPackages: NPM_PACKAGES } : {}),
+    ...(window.context?.experimentalFeatures?.goModules === 'enabled' ? { goModules: GO_MODULES } : {}),
 }
 
 export const nonCodeHostExternalServices: Record<string, AddExternalServiceOptions> = {
@@ -1239,9 +1422,13 @@ export const defaultExternalServices: Record<ExternalServiceKind, AddExternalSer
     [ExternalServiceKind.BITBUCKETSERVER]: BITBUCKET_SERVER,
     [ExternalServiceKind.GITLAB]: GITLAB_DOTCOM,
     [ExternalServiceKind.GITOLITE]: GITOLITE,
+    [ExternalServiceKind.GITEA]: GITEA,
+    [ExternalServiceKind.GERRIT]: GERRIT,
     [ExternalServiceKind.PHABRICATOR]: PHABRICATOR_SERVICE,
     [ExternalServiceKind.OTHER]: GENERIC_GIT,
     [ExternalServiceKind.AWSCODECOMMIT]: AWS_CODE_COMMIT,
     [ExternalServiceKind.PERFORCE]: PERFORCE,
     [ExternalServiceKind.JVMPACKAGES]: JVM_PACKAGES,
+    [ExternalServiceKind.NPMPACKAGES]: NPM_PACKAGES,
+    [ExternalServiceKind.GOMODULES]: GO_MODULES,
 }
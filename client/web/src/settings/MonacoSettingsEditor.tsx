@@ -9,6 +9,7 @@ import { TelemetryService } from '@sourcegraph/shared/src/telemetry/telemetrySer
 import { ThemeProps } from '@sourcegraph/shared/src/theme'
 
 import jsonSchemaMetaSchema from '../../../../schema/json-schema-draft-07.schema.json'
+import partialCloneSchema from '../../../../schema/partial_clone.schema.json'
 import settingsSchema from '../../../../schema/settings.schema.json'
 import { MonacoEditor } from '../components/MonacoEditor'
 
@@ -284,6 +285,14 @@ function setDiagnosticsOptions(editor: typeof monaco, jsonSchema: any): void {
                 uri: 'settings.schema.json',
                 schema: settingsSchema,
             },
+            {
+                uri: 'partial_clone.schema.json#',
+                schema: partialCloneSchema,
+            },
+            {
+                uri: 'partial_clone.schema.json',
+                schema: partialCloneSchema,
+            },
         ],
     })
 }
@@ -123,6 +123,9 @@ func AllowAnonymousRequest(req *http.Request) bool {
 		"/.api/github-webhooks",
 		"/.api/gitlab-webhooks",
 		"/.api/bitbucket-server-webhooks",
+		"/.api/bitbucket-cloud-webhooks",
+		"/.api/aws-codecommit-webhooks",
+		"/.api/repo-update-webhooks",
 	} {
 		if strings.HasPrefix(req.URL.Path, prefix) {
 			return true
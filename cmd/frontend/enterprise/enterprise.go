@@ -20,6 +20,9 @@ type Services struct {
 	GitHubWebhook             webhooks.Registerer
 	GitLabWebhook             http.Handler
 	BitbucketServerWebhook    http.Handler
+	BitbucketCloudWebhook     http.Handler
+	AWSCodeCommitWebhook      http.Handler
+	SearchJobsResultsHandler  http.Handler
 	NewCodeIntelUploadHandler NewCodeIntelUploadHandler
 	NewExecutorProxyHandler   NewExecutorProxyHandler
 	AuthzResolver             graphqlbackend.AuthzResolver
@@ -29,6 +32,7 @@ type Services struct {
 	CodeMonitorsResolver      graphqlbackend.CodeMonitorsResolver
 	LicenseResolver           graphqlbackend.LicenseResolver
 	DotcomResolver            graphqlbackend.DotcomRootResolver
+	SearchJobsResolver        graphqlbackend.SearchJobsResolver
 }
 
 // NewCodeIntelUploadHandler creates a new handler for the LSIF upload endpoint. The
@@ -46,6 +50,9 @@ func DefaultServices() Services {
 		GitHubWebhook:             registerFunc(func(webhook *webhooks.GitHubWebhook) {}),
 		GitLabWebhook:             makeNotFoundHandler("gitlab webhook"),
 		BitbucketServerWebhook:    makeNotFoundHandler("bitbucket server webhook"),
+		BitbucketCloudWebhook:     makeNotFoundHandler("bitbucket cloud webhook"),
+		AWSCodeCommitWebhook:      makeNotFoundHandler("aws codecommit webhook"),
+		SearchJobsResultsHandler:  makeNotFoundHandler("search jobs results"),
 		NewCodeIntelUploadHandler: func(_ bool) http.Handler { return makeNotFoundHandler("code intel upload") },
 		NewExecutorProxyHandler:   func() http.Handler { return makeNotFoundHandler("executor proxy") },
 	}
@@ -73,6 +73,8 @@ type MonitorActionConnectionResolver interface {
 
 type MonitorAction interface {
 	ToMonitorEmail() (MonitorEmailResolver, bool)
+	ToMonitorSlackWebhook() (MonitorSlackWebhookResolver, bool)
+	ToMonitorWebhook() (MonitorWebhookResolver, bool)
 }
 
 type MonitorEmailResolver interface {
@@ -84,6 +86,20 @@ type MonitorEmailResolver interface {
 	Events(ctx context.Context, args *ListEventsArgs) (MonitorActionEventConnectionResolver, error)
 }
 
+type MonitorSlackWebhookResolver interface {
+	ID() graphql.ID
+	Enabled() bool
+	URL() string
+	Events(ctx context.Context, args *ListEventsArgs) (MonitorActionEventConnectionResolver, error)
+}
+
+type MonitorWebhookResolver interface {
+	ID() graphql.ID
+	Enabled() bool
+	URL() string
+	Events(ctx context.Context, args *ListEventsArgs) (MonitorActionEventConnectionResolver, error)
+}
+
 type MonitorEmailRecipient interface {
 	ToUser() (*UserResolver, bool)
 }
@@ -138,7 +154,9 @@ type CreateTriggerArgs struct {
 }
 
 type CreateActionArgs struct {
-	Email *CreateActionEmailArgs
+	Email        *CreateActionEmailArgs
+	SlackWebhook *CreateActionSlackWebhookArgs
+	Webhook      *CreateActionWebhookArgs
 }
 
 type CreateActionEmailArgs struct {
@@ -148,6 +166,16 @@ type CreateActionEmailArgs struct {
 	Header     string
 }
 
+type CreateActionSlackWebhookArgs struct {
+	Enabled bool
+	URL     string
+}
+
+type CreateActionWebhookArgs struct {
+	Enabled bool
+	URL     string
+}
+
 type ToggleCodeMonitorArgs struct {
 	Id      graphql.ID
 	Enabled bool
@@ -178,8 +206,20 @@ type EditActionEmailArgs struct {
 	Update *CreateActionEmailArgs
 }
 
+type EditActionSlackWebhookArgs struct {
+	Id     *graphql.ID
+	Update *CreateActionSlackWebhookArgs
+}
+
+type EditActionWebhookArgs struct {
+	Id     *graphql.ID
+	Update *CreateActionWebhookArgs
+}
+
 type EditActionArgs struct {
-	Email *EditActionEmailArgs
+	Email        *EditActionEmailArgs
+	SlackWebhook *EditActionSlackWebhookArgs
+	Webhook      *EditActionWebhookArgs
 }
 
 type EditTriggerArgs struct {
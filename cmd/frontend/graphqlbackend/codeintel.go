@@ -177,7 +177,8 @@ type LSIFQueryPositionArgs struct {
 type LSIFPagedQueryPositionArgs struct {
 	LSIFQueryPositionArgs
 	graphqlutil.ConnectionArgs
-	After *string
+	After    *string
+	MaxDepth *int32
 }
 
 type LSIFQueryDocumentationArgs struct {
@@ -75,6 +75,13 @@ func (fm *FileMatchResolver) LimitHit() bool {
 	return fm.FileMatch.LimitHit
 }
 
+func (fm *FileMatchResolver) RewriteDiff() *string {
+	if fm.FileMatch.RewriteDiff == "" {
+		return nil
+	}
+	return &fm.FileMatch.RewriteDiff
+}
+
 func (fm *FileMatchResolver) ToRepository() (*RepositoryResolver, bool) { return nil, false }
 func (fm *FileMatchResolver) ToFileMatch() (*FileMatchResolver, bool)   { return fm, true }
 func (fm *FileMatchResolver) ToCommitSearchResult() (*CommitSearchResultResolver, bool) {
@@ -330,7 +330,7 @@ func prometheusGraphQLRequestName(requestName string) string {
 	return "other"
 }
 
-func NewSchema(db dbutil.DB, batchChanges BatchChangesResolver, codeIntel CodeIntelResolver, insights InsightsResolver, authz AuthzResolver, codeMonitors CodeMonitorsResolver, license LicenseResolver, dotcom DotcomRootResolver) (*graphql.Schema, error) {
+func NewSchema(db dbutil.DB, batchChanges BatchChangesResolver, codeIntel CodeIntelResolver, insights InsightsResolver, authz AuthzResolver, codeMonitors CodeMonitorsResolver, license LicenseResolver, dotcom DotcomRootResolver, searchJobs SearchJobsResolver) (*graphql.Schema, error) {
 	resolver := newSchemaResolver(db)
 	schemas := []string{mainSchema}
 
@@ -393,6 +393,16 @@ func NewSchema(db dbutil.DB, batchChanges BatchChangesResolver, codeIntel CodeIn
 		}
 	}
 
+	if searchJobs != nil {
+		EnterpriseResolvers.searchJobsResolver = searchJobs
+		resolver.SearchJobsResolver = searchJobs
+		schemas = append(schemas, searchJobsSchema)
+		// Register NodeByID handlers.
+		for kind, res := range searchJobs.NodeResolvers() {
+			resolver.nodeByIDFns[kind] = res
+		}
+	}
+
 	return graphql.ParseSchema(
 		strings.Join(schemas, "\n"),
 		resolver,
@@ -412,6 +422,7 @@ type schemaResolver struct {
 	CodeMonitorsResolver
 	LicenseResolver
 	DotcomRootResolver
+	SearchJobsResolver
 
 	db                dbutil.DB
 	repoupdaterClient *repoupdater.Client
@@ -483,6 +494,7 @@ var EnterpriseResolvers = struct {
 	codeMonitorsResolver CodeMonitorsResolver
 	licenseResolver      LicenseResolver
 	dotcomResolver       DotcomRootResolver
+	searchJobsResolver   SearchJobsResolver
 }{}
 
 // DEPRECATED
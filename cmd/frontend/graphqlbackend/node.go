@@ -67,7 +67,17 @@ func (r *NodeResolver) ToMonitorEmail() (MonitorEmailResolver, bool) {
 	return n, ok
 }
 
-func (r *NodeResolver) ToMonitorActionEvent() (MonitorActionEventResolver, bool) {
+func (r *NodeResolver) ToMonitorSlackWebhook() (MonitorSlackWebhookResolver, bool) {
+	n, ok := r.Node.(MonitorSlackWebhookResolver)
+	return n, ok
+}
+
+func (r *NodeResolver) ToMonitorWebhook() (MonitorWebhookResolver, bool) {
+	n, ok := r.Node.(MonitorWebhookResolver)
+	return n, ok
+}
+
+func (r *NodeResolver) ToMonitorActionEvent()(MonitorActionEventResolver, bool) {
 	n, ok := r.Node.(MonitorActionEventResolver)
 	return n, ok
 }
@@ -253,3 +263,8 @@ func (r *NodeResolver) ToBatchSpecExecution() (BatchSpecExecutionResolver, bool)
 	n, ok := r.Node.(BatchSpecExecutionResolver)
 	return n, ok
 }
+
+func (r *NodeResolver) ToSearchJob() (SearchJobResolver, bool) {
+	n, ok := r.Node.(SearchJobResolver)
+	return n, ok
+}
@@ -28,6 +28,10 @@ var licenseSchema string
 //go:embed code_monitors.graphql
 var codeMonitorsSchema string
 
+// searchJobsSchema is the Search Jobs raw graqhql schema.
+//go:embed search_jobs.graphql
+var searchJobsSchema string
+
 // insightsSchema is the Code Insights raw graqhql schema.
 //go:embed insights.graphql
 var insightsSchema string
@@ -146,6 +146,8 @@ func detectSearchType(version string, patternType *string) (query.SearchType, er
 			searchType = query.SearchTypeRegex
 		case "structural":
 			searchType = query.SearchTypeStructural
+		case "fuzzy":
+			searchType = query.SearchTypeFuzzy
 		default:
 			return -1, errors.Errorf("unrecognized patternType: %v", patternType)
 		}
@@ -178,6 +180,8 @@ func overrideSearchType(input string, searchType query.SearchType) query.SearchT
 			searchType = query.SearchTypeLiteral
 		case "structural":
 			searchType = query.SearchTypeStructural
+		case "fuzzy":
+			searchType = query.SearchTypeFuzzy
 		}
 	})
 	return searchType
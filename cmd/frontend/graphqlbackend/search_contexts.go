@@ -36,12 +36,14 @@ type searchContextInputArgs struct {
 	Description string
 	Public      bool
 	Namespace   *graphql.ID
+	Query       *string
 }
 
 type searchContextEditInputArgs struct {
 	Name        string
 	Description string
 	Public      bool
+	Query       *string
 }
 
 type searchContextRepositoryRevisionsInputArgs struct {
@@ -155,6 +157,10 @@ func (r *searchContextResolver) Spec() string {
 	return searchcontexts.GetSearchContextSpec(r.sc)
 }
 
+func (r *searchContextResolver) Query(ctx context.Context) string {
+	return r.sc.Query
+}
+
 func (r *searchContextResolver) UpdatedAt(ctx context.Context) DateTime {
 	return DateTime{Time: r.sc.UpdatedAt}
 }
@@ -230,6 +236,7 @@ func (r *schemaResolver) CreateSearchContext(ctx context.Context, args createSea
 			Public:          args.SearchContext.Public,
 			NamespaceUserID: namespaceUserID,
 			NamespaceOrgID:  namespaceOrgID,
+			Query:           searchContextQueryFromArgs(args.SearchContext.Query),
 		},
 		repositoryRevisions,
 	)
@@ -259,6 +266,7 @@ func (r *schemaResolver) UpdateSearchContext(ctx context.Context, args updateSea
 	updated.Name = args.SearchContext.Name
 	updated.Description = args.SearchContext.Description
 	updated.Public = args.SearchContext.Public
+	updated.Query = searchContextQueryFromArgs(args.SearchContext.Query)
 
 	searchContext, err := searchcontexts.UpdateSearchContextWithRepositoryRevisions(
 		ctx,
@@ -272,6 +280,13 @@ func (r *schemaResolver) UpdateSearchContext(ctx context.Context, args updateSea
 	return &searchContextResolver{searchContext, r.db}, nil
 }
 
+func searchContextQueryFromArgs(query *string) string {
+	if query == nil {
+		return ""
+	}
+	return *query
+}
+
 func (r *schemaResolver) repositoryRevisionsFromInputArgs(ctx context.Context, args []searchContextRepositoryRevisionsInputArgs) ([]*types.SearchContextRepositoryRevisions, error) {
 	repositoryRevisions := make([]*types.SearchContextRepositoryRevisions, 0, len(args))
 	for _, repository := range args {
@@ -0,0 +1,125 @@
+package graphqlbackend
+
+import (
+	"context"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/codeowners"
+	"github.com/sourcegraph/sourcegraph/internal/search/query"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+)
+
+// fileHasOwnerResults evaluates the file:has.owner() predicate of parent. It
+// reads the CODEOWNERS file of every repository the parent query searches, and
+// then searches each repository only for the paths that match the rules of the
+// owner. This way the limit on the number of paths applies to the paths that
+// can be owned, not to every path.
+func (r *searchResolver) fileHasOwnerResults(ctx context.Context, parent query.Basic, pred *query.FileHasOwnerPredicate) (*SearchResults, error) {
+	plan, err := pred.Plan(parent)
+	if err != nil {
+		return nil, err
+	}
+	codeownersFiles, err := r.evaluatePlan(ctx, plan)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[api.RepoName]struct{}{}
+	var ownedPlan query.Plan
+	for _, match := range codeownersFiles.Matches {
+		fm, ok := match.(*result.FileMatch)
+		if !ok {
+			continue
+		}
+		if _, ok := seen[fm.Repo.Name]; ok {
+			continue
+		}
+		seen[fm.Repo.Name] = struct{}{}
+
+		rs, err := codeowners.Read(ctx, fm.Repo.Name, fm.CommitID)
+		if err != nil {
+			return nil, err
+		}
+		patterns := rs.OwnedPatterns(pred.Owner)
+		if len(patterns) == 0 {
+			continue
+		}
+		repoPlan, err := pred.OwnedPathsPlan(parent, string(fm.Repo.Name), patterns)
+		if err != nil {
+			return nil, err
+		}
+		ownedPlan = append(ownedPlan, repoPlan...)
+	}
+
+	return r.evaluatePlan(ctx, ownedPlan)
+}
+
+// evaluatePlan evaluates every query of a plan without predicates and returns
+// the union of their results. Unlike resultsRecursive, the results are not
+// limited by the count of the search.
+func (r *searchResolver) evaluatePlan(ctx context.Context, plan query.Plan) (*SearchResults, error) {
+	sr := &SearchResults{}
+	for _, q := range plan {
+		newResult, err := r.evaluate(ctx, q)
+		if err != nil {
+			return nil, err
+		}
+		if newResult == nil {
+			continue
+		}
+		newResult.Matches, err = filterFileHasOwner(ctx, q, newResult.Matches)
+		if err != nil {
+			return nil, err
+		}
+		sr = union(sr, newResult)
+	}
+	return sr, nil
+}
+
+// filterFileHasOwner removes file matches that are not owned by all of the
+// owners given by `filehasowner:` fields in q, according to the CODEOWNERS
+// file of the repository at the matched commit. Non-file matches are dropped,
+// since ownership is only defined for paths.
+func filterFileHasOwner(ctx context.Context, q query.Basic, matches []result.Match) ([]result.Match, error) {
+	owners, _ := q.ToParseTree().StringValues(query.FieldFileHasOwner)
+	if len(owners) == 0 {
+		return matches, nil
+	}
+
+	type repoCommit struct {
+		repo   api.RepoName
+		commit api.CommitID
+	}
+	rulesets := map[repoCommit]*codeowners.Ruleset{}
+
+	filtered := matches[:0]
+	for _, match := range matches {
+		fm, ok := match.(*result.FileMatch)
+		if !ok {
+			continue
+		}
+
+		key := repoCommit{repo: fm.Repo.Name, commit: fm.CommitID}
+		rs, ok := rulesets[key]
+		if !ok {
+			var err error
+			rs, err = codeowners.Read(ctx, key.repo, key.commit)
+			if err != nil {
+				return nil, err
+			}
+			rulesets[key] = rs
+		}
+
+		ownedByAll := true
+		for _, owner := range owners {
+			if !rs.IsOwnedBy(fm.Path, owner) {
+				ownedByAll = false
+				break
+			}
+		}
+		if ownedByAll {
+			filtered = append(filtered, match)
+		}
+	}
+	return filtered, nil
+}
@@ -0,0 +1,64 @@
+package graphqlbackend
+
+import (
+	"context"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/search/query"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+)
+
+func TestFilterFileHasOwner(t *testing.T) {
+	git.Mocks.ReadFile = func(commit api.CommitID, name string) ([]byte, error) {
+		if commit == "withowners" && name == ".github/CODEOWNERS" {
+			return []byte("*.go @backend\n/infra/ @org/infra\n"), nil
+		}
+		return nil, &os.PathError{Op: "open", Path: name, Err: os.ErrNotExist}
+	}
+	t.Cleanup(func() { git.Mocks.ReadFile = nil })
+
+	owned := types.RepoName{ID: 1, Name: "owned"}
+	unowned := types.RepoName{ID: 2, Name: "unowned"}
+	mkMatch := func(repo types.RepoName, commit api.CommitID, path string) *result.FileMatch {
+		fm := mkFileMatch(repo, path)
+		fm.CommitID = commit
+		return fm
+	}
+
+	matches := []result.Match{
+		mkMatch(owned, "withowners", "main.go"),
+		mkMatch(owned, "withowners", "infra/deploy.sh"),
+		mkMatch(owned, "withowners", "infra/main.go"), // the last matching rule wins
+		mkMatch(unowned, "withoutowners", "infra/deploy.sh"),
+		&result.RepoMatch{Name: "owned", ID: 1},
+	}
+
+	q, err := query.ParseLiteral(`filehasowner:@org/infra`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := query.ToBasicQuery(q)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	filtered, err := filterFileHasOwner(context.Background(), b, matches)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var paths []string
+	for _, m := range filtered {
+		fm := m.(*result.FileMatch)
+		paths = append(paths, string(fm.Repo.Name)+"/"+fm.Path)
+	}
+	want := []string{"owned/infra/deploy.sh", "owned/infra/main.go"}
+	if !reflect.DeepEqual(paths, want) {
+		t.Fatalf("unexpected filtered matches: want %v, have %v", want, paths)
+	}
+}
@@ -0,0 +1,67 @@
+package graphqlbackend
+
+import (
+	"context"
+
+	"github.com/graph-gophers/graphql-go"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
+)
+
+type SearchJobsResolver interface {
+	// Query
+	SearchJobs(ctx context.Context, args *ListSearchJobsArgs) (SearchJobConnectionResolver, error)
+
+	// Mutations
+	CreateSearchJob(ctx context.Context, args *CreateSearchJobArgs) (SearchJobResolver, error)
+	CancelSearchJob(ctx context.Context, args *SearchJobIDArgs) (SearchJobResolver, error)
+	DeleteSearchJob(ctx context.Context, args *SearchJobIDArgs) (*EmptyResponse, error)
+
+	NodeResolvers() map[string]NodeByIDFunc
+}
+
+type SearchJobConnectionResolver interface {
+	Nodes(ctx context.Context) ([]SearchJobResolver, error)
+	TotalCount(ctx context.Context) (int32, error)
+	PageInfo(ctx context.Context) (*graphqlutil.PageInfo, error)
+}
+
+type SearchJobResolver interface {
+	ID() graphql.ID
+	Query() string
+	PatternType() string
+	Creator(ctx context.Context) (*UserResolver, error)
+	State(ctx context.Context) (string, error)
+	FailureMessage() *string
+	CreatedAt() DateTime
+	StartedAt() *DateTime
+	FinishedAt(ctx context.Context) (*DateTime, error)
+	CanceledAt() *DateTime
+	RepoStats(ctx context.Context) (SearchJobRepoStatsResolver, error)
+	ResultCount(ctx context.Context) (int32, error)
+	ResultsURL() string
+}
+
+type SearchJobRepoStatsResolver interface {
+	Total() int32
+	Queued() int32
+	Processing() int32
+	Completed() int32
+	Failed() int32
+	Canceled() int32
+}
+
+type ListSearchJobsArgs struct {
+	First  int32
+	After  *string
+	UserID *graphql.ID
+}
+
+type CreateSearchJobArgs struct {
+	Query       string
+	PatternType string
+}
+
+type SearchJobIDArgs struct {
+	ID graphql.ID
+}
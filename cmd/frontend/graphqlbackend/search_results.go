@@ -35,6 +35,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/rcache"
 	"github.com/sourcegraph/sourcegraph/internal/search"
 	"github.com/sourcegraph/sourcegraph/internal/search/filter"
+	"github.com/sourcegraph/sourcegraph/internal/search/fuzzy"
 	"github.com/sourcegraph/sourcegraph/internal/search/query"
 	searchrepos "github.com/sourcegraph/sourcegraph/internal/search/repos"
 	"github.com/sourcegraph/sourcegraph/internal/search/result"
@@ -499,6 +500,7 @@ func (r *searchResolver) toRepoOptions(q query.Q, opts resolveRepositoriesOpts)
 	visibility := query.ParseVisibility(visibilityStr)
 
 	commitAfter, _ := q.StringValue(query.FieldRepoHasCommitAfter)
+	descriptionPatterns, _ := q.RegexpPatterns(query.FieldRepoHasDescription)
 	searchContextSpec, _ := q.StringValue(query.FieldContext)
 
 	var versionContextName string
@@ -513,23 +515,24 @@ func (r *searchResolver) toRepoOptions(q query.Q, opts resolveRepositoriesOpts)
 	}
 
 	return search.RepoOptions{
-		RepoFilters:        repoFilters,
-		MinusRepoFilters:   minusRepoFilters,
-		RepoGroupFilters:   repoGroupFilters,
-		VersionContextName: versionContextName,
-		SearchContextSpec:  searchContextSpec,
-		UserSettings:       r.UserSettings,
-		OnlyForks:          fork == query.Only,
-		NoForks:            fork == query.No,
-		OnlyArchived:       archived == query.Only,
-		NoArchived:         archived == query.No,
-		OnlyPrivate:        visibility == query.Private,
-		OnlyPublic:         visibility == query.Public,
-		CommitAfter:        commitAfter,
-		Query:              q,
-		Ranked:             true,
-		Limit:              opts.limit,
-		CacheLookup:        CacheLookup,
+		RepoFilters:         repoFilters,
+		MinusRepoFilters:    minusRepoFilters,
+		RepoGroupFilters:    repoGroupFilters,
+		VersionContextName:  versionContextName,
+		SearchContextSpec:   searchContextSpec,
+		UserSettings:        r.UserSettings,
+		OnlyForks:           fork == query.Only,
+		NoForks:             fork == query.No,
+		OnlyArchived:        archived == query.Only,
+		NoArchived:          archived == query.No,
+		OnlyPrivate:         visibility == query.Private,
+		OnlyPublic:          visibility == query.Public,
+		CommitAfter:         commitAfter,
+		DescriptionPatterns: descriptionPatterns,
+		Query:               q,
+		Ranked:              true,
+		Limit:               opts.limit,
+		CacheLookup:         CacheLookup,
 	}
 }
 
@@ -584,6 +587,19 @@ func (r *searchResolver) toTextParameters(q query.Q) (*search.TextParameters, er
 		}
 	}
 
+	patternType := r.PatternType
+	if patternType == query.SearchTypeFuzzy {
+		if p.Pattern == "" {
+			// Fallback to literal search for searching repos and files if
+			// the fuzzy search pattern is empty.
+			patternType = query.SearchTypeLiteral
+		} else if len(q.Values(query.FieldType)) == 0 {
+			// Fuzzy search matches file names unless type:symbol is
+			// given.
+			forceResultTypes = result.TypePath
+		}
+	}
+
 	args := search.TextParameters{
 		PatternInfo: p,
 		Query:       q,
@@ -597,7 +613,7 @@ func (r *searchResolver) toTextParameters(q query.Q) (*search.TextParameters, er
 		RepoPromise:  &search.RepoPromise{},
 	}
 	args = withResultTypes(args, forceResultTypes)
-	args = withMode(args, r.PatternType, r.VersionContext)
+	args = withMode(args, patternType, r.VersionContext)
 	return &args, nil
 }
 
@@ -1015,6 +1031,10 @@ func (r *searchResolver) resultsRecursive(ctx context.Context, plan query.Plan)
 	if count := r.Query.Count(); count != nil {
 		wantCount = *count
 	}
+	if fuzzy.QueryPattern(r.Query) != "" {
+		// Fuzzy search results are ranked once all of them are found.
+		wantCount = fuzzy.MaxCandidates
+	}
 
 	for _, q := range plan {
 		predicatePlan, err := substitutePredicates(q, func(pred query.Predicate) (*SearchResults, error) {
@@ -1025,6 +1045,9 @@ func (r *searchResolver) resultsRecursive(ctx context.Context, plan query.Plan)
 			defer func() { r.stream = orig }()
 
 			r.invalidateRepoCache = true
+			if p, ok := pred.(*query.FileHasOwnerPredicate); ok {
+				return r.fileHasOwnerResults(ctx, q, p)
+			}
 			plan, err := pred.Plan(q)
 			if err != nil {
 				return nil, err
@@ -1050,6 +1073,10 @@ func (r *searchResolver) resultsRecursive(ctx context.Context, plan query.Plan)
 		}
 
 		if newResult != nil {
+			newResult.Matches, err = filterFileHasOwner(ctx, q, newResult.Matches)
+			if err != nil {
+				return nil, err
+			}
 			newResult.Matches = result.Select(newResult.Matches, q)
 			sr = union(sr, newResult)
 			if len(sr.Matches) > wantCount {
@@ -1404,6 +1431,11 @@ func (r *searchResolver) doResults(ctx context.Context, args *search.TextParamet
 	defer cancel()
 
 	limit := r.MaxResults()
+	if fuzzy.QueryPattern(args.Query) != "" {
+		// Fuzzy search results are ranked once all of them are found, so
+		// the backends may not stop at the number of results shown.
+		limit = fuzzy.MaxCandidates
+	}
 	tr.LazyPrintf("resultTypes: %s", args.ResultTypes)
 	var (
 		requiredWg sync.WaitGroup
@@ -1723,6 +1755,11 @@ func compareSearchResults(left, right result.Match, exactFilePatterns map[string
 }
 
 func (r *searchResolver) sortResults(results []result.Match) {
+	if pattern := fuzzy.QueryPattern(r.Query); pattern != "" {
+		fuzzy.Sort(pattern, results)
+		return
+	}
+
 	var exactPatterns map[string]struct{}
 	if getBoolPtr(r.UserSettings.SearchGlobbing, false) {
 		exactPatterns = r.getExactFilePatterns()
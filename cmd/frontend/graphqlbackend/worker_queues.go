@@ -0,0 +1,190 @@
+package graphqlbackend
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker"
+	"github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store"
+)
+
+// WorkerQueues resolves all registered database-backed background job queues.
+func (r *schemaResolver) WorkerQueues(ctx context.Context) ([]*workerQueueResolver, error) {
+	// 🚨 SECURITY: Only site admins may view the failed records of background job queues
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx, r.db); err != nil {
+		return nil, err
+	}
+
+	names := dbworker.QueueNames()
+	resolvers := make([]*workerQueueResolver, 0, len(names))
+	for _, name := range names {
+		queueStore, _ := dbworker.QueueStore(name)
+		resolvers = append(resolvers, &workerQueueResolver{
+			db:          r.db,
+			name:        name,
+			store:       queueStore,
+			allowDelete: dbworker.QueueAllowsDelete(name),
+		})
+	}
+
+	return resolvers, nil
+}
+
+type workerQueueRecordsArgs struct {
+	Queue string
+	IDs   *[]int32
+}
+
+// RequeueFailedWorkerQueueRecords moves the given failed records of a queue back to the queued state.
+func (r *schemaResolver) RequeueFailedWorkerQueueRecords(ctx context.Context, args *workerQueueRecordsArgs) (int32, error) {
+	// 🚨 SECURITY: Only site admins may requeue the failed records of background job queues
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx, r.db); err != nil {
+		return 0, err
+	}
+
+	queueStore, err := workerQueueStore(args.Queue)
+	if err != nil {
+		return 0, err
+	}
+
+	count, err := queueStore.RequeueFailed(ctx, workerQueueRecordIDs(args.IDs))
+	return int32(count), err
+}
+
+// DeleteFailedWorkerQueueRecords deletes the given failed records of a queue.
+func (r *schemaResolver) DeleteFailedWorkerQueueRecords(ctx context.Context, args *workerQueueRecordsArgs) (int32, error) {
+	// 🚨 SECURITY: Only site admins may delete the failed records of background job queues
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx, r.db); err != nil {
+		return 0, err
+	}
+
+	queueStore, err := workerQueueStore(args.Queue)
+	if err != nil {
+		return 0, err
+	}
+	if !dbworker.QueueAllowsDelete(args.Queue) {
+		return 0, errors.Errorf("failed records of worker queue %q cannot be deleted", args.Queue)
+	}
+
+	count, err := queueStore.DeleteFailed(ctx, workerQueueRecordIDs(args.IDs))
+	return int32(count), err
+}
+
+func workerQueueStore(name string) (store.Store, error) {
+	queueStore, ok := dbworker.QueueStore(name)
+	if !ok {
+		return nil, errors.Errorf("unknown worker queue %q", name)
+	}
+
+	return queueStore, nil
+}
+
+func workerQueueRecordIDs(ids *[]int32) []int {
+	if ids == nil {
+		return nil
+	}
+
+	recordIDs := make([]int, 0, len(*ids))
+	for _, id := range *ids {
+		recordIDs = append(recordIDs, int(id))
+	}
+
+	return recordIDs
+}
+
+// workerQueueResolver implements the GraphQL type WorkerQueue.
+type workerQueueResolver struct {
+	db          dbutil.DB
+	name        string
+	store       store.Store
+	allowDelete bool
+}
+
+func (r *workerQueueResolver) Name() string                 { return r.name }
+func (r *workerQueueResolver) CanDeleteFailedRecords() bool { return r.allowDelete }
+
+type workerQueueFailedRecordsArgs struct {
+	First int32
+	After *string
+}
+
+func (r *workerQueueResolver) FailedRecords(ctx context.Context, args *workerQueueFailedRecordsArgs) (*workerQueueFailedRecordConnectionResolver, error) {
+	opts := store.ListFailedOptions{Limit: int(args.First)}
+	if args.After != nil {
+		cursor, err := strconv.Atoi(*args.After)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid cursor")
+		}
+		opts.Cursor = cursor
+	}
+
+	records, totalCount, err := r.store.ListFailed(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &workerQueueFailedRecordConnectionResolver{
+		db:         r.db,
+		records:    records,
+		totalCount: totalCount,
+		limit:      opts.Limit,
+	}, nil
+}
+
+// workerQueueFailedRecordConnectionResolver implements the GraphQL type WorkerQueueFailedRecordConnection.
+type workerQueueFailedRecordConnectionResolver struct {
+	db         dbutil.DB
+	records    []store.FailedRecord
+	totalCount int
+	limit      int
+}
+
+func (r *workerQueueFailedRecordConnectionResolver) Nodes() []*workerQueueFailedRecordResolver {
+	resolvers := make([]*workerQueueFailedRecordResolver, 0, len(r.records))
+	for _, record := range r.records {
+		resolvers = append(resolvers, &workerQueueFailedRecordResolver{db: r.db, record: record})
+	}
+
+	return resolvers
+}
+
+func (r *workerQueueFailedRecordConnectionResolver) TotalCount() int32 { return int32(r.totalCount) }
+
+func (r *workerQueueFailedRecordConnectionResolver) PageInfo() *graphqlutil.PageInfo {
+	if r.limit > 0 && len(r.records) == r.limit {
+		return graphqlutil.NextPageCursor(strconv.Itoa(r.records[len(r.records)-1].ID))
+	}
+
+	return graphqlutil.HasNextPage(false)
+}
+
+// workerQueueFailedRecordResolver implements the GraphQL type WorkerQueueFailedRecord.
+type workerQueueFailedRecordResolver struct {
+	db     dbutil.DB
+	record store.FailedRecord
+}
+
+func (r *workerQueueFailedRecordResolver) ID() int32               { return int32(r.record.ID) }
+func (r *workerQueueFailedRecordResolver) FailureMessage() *string { return r.record.FailureMessage }
+func (r *workerQueueFailedRecordResolver) StartedAt() *DateTime {
+	return DateTimeOrNil(r.record.StartedAt)
+}
+func (r *workerQueueFailedRecordResolver) FinishedAt() *DateTime {
+	return DateTimeOrNil(r.record.FinishedAt)
+}
+func (r *workerQueueFailedRecordResolver) NumFailures() int32 { return int32(r.record.NumFailures) }
+func (r *workerQueueFailedRecordResolver) NumResets() int32   { return int32(r.record.NumResets) }
+
+func (r *workerQueueFailedRecordResolver) ExecutionLogs() []ExecutionLogEntryResolver {
+	resolvers := make([]ExecutionLogEntryResolver, 0, len(r.record.ExecutionLogs))
+	for _, entry := range r.record.ExecutionLogs {
+		resolvers = append(resolvers, NewExecutionLogEntryResolver(r.db, entry))
+	}
+
+	return resolvers
+}
@@ -0,0 +1,143 @@
+package graphqlbackend
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker"
+	"github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store"
+	"github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store/mocks"
+)
+
+var (
+	registerTestQueueOnce sync.Once
+	testQueueStore        = &testQueue{}
+)
+
+// testQueue is the store of the test queue. Queues can only be registered once, so each test
+// replaces the embedded mock instead.
+type testQueue struct {
+	store.Store
+}
+
+func registerTestQueue(t *testing.T) *mocks.MockStore {
+	t.Helper()
+
+	registerTestQueueOnce.Do(func() {
+		dbworker.RegisterQueue("graphqlbackend_test", testQueueStore)
+	})
+
+	mockStore := mocks.NewMockStore()
+	testQueueStore.Store = mockStore
+	return mockStore
+}
+
+func TestWorkerQueues(t *testing.T) {
+	resetMocks()
+	database.Mocks.Users.GetByCurrentAuthUser = func(context.Context) (*types.User, error) {
+		return &types.User{SiteAdmin: true}, nil
+	}
+
+	queueStore := registerTestQueue(t)
+	failureMessage := "oops"
+	queueStore.ListFailedFunc.SetDefaultReturn([]store.FailedRecord{
+		{ID: 3, FailureMessage: &failureMessage, NumFailures: 2},
+		{ID: 2, FailureMessage: &failureMessage, NumFailures: 1},
+	}, 5, nil)
+
+	RunTest(t, &Test{
+		Schema: mustParseGraphQLSchema(t),
+		Query: `
+			{
+				workerQueues {
+					name
+					canDeleteFailedRecords
+					failedRecords(first: 2, after: "4") {
+						nodes { id failureMessage numFailures }
+						totalCount
+						pageInfo { hasNextPage endCursor }
+					}
+				}
+			}
+		`,
+		ExpectedResult: `
+			{
+				"workerQueues": [
+					{
+						"name": "graphqlbackend_test",
+						"canDeleteFailedRecords": true,
+						"failedRecords": {
+							"nodes": [
+								{"id": 3, "failureMessage": "oops", "numFailures": 2},
+								{"id": 2, "failureMessage": "oops", "numFailures": 1}
+							],
+							"totalCount": 5,
+							"pageInfo": {"hasNextPage": true, "endCursor": "2"}
+						}
+					}
+				]
+			}
+		`,
+	})
+
+	if len(queueStore.ListFailedFunc.History()) != 1 {
+		t.Fatalf("unexpected number of calls to ListFailed. want=%d have=%d", 1, len(queueStore.ListFailedFunc.History()))
+	}
+	if diff := cmp.Diff(store.ListFailedOptions{Limit: 2, Cursor: 4}, queueStore.ListFailedFunc.History()[0].Arg1); diff != "" {
+		t.Errorf("unexpected options (-want +got):\n%s", diff)
+	}
+}
+
+func TestRequeueFailedWorkerQueueRecords(t *testing.T) {
+	resetMocks()
+	database.Mocks.Users.GetByCurrentAuthUser = func(context.Context) (*types.User, error) {
+		return &types.User{SiteAdmin: true}, nil
+	}
+
+	queueStore := registerTestQueue(t)
+	queueStore.RequeueFailedFunc.SetDefaultReturn(2, nil)
+
+	RunTest(t, &Test{
+		Schema: mustParseGraphQLSchema(t),
+		Query: `
+			mutation {
+				requeueFailedWorkerQueueRecords(queue: "graphqlbackend_test", ids: [1, 2])
+			}
+		`,
+		ExpectedResult: `{"requeueFailedWorkerQueueRecords": 2}`,
+	})
+
+	if len(queueStore.RequeueFailedFunc.History()) != 1 {
+		t.Fatalf("unexpected number of calls to RequeueFailed. want=%d have=%d", 1, len(queueStore.RequeueFailedFunc.History()))
+	}
+	if diff := cmp.Diff([]int{1, 2}, queueStore.RequeueFailedFunc.History()[0].Arg1); diff != "" {
+		t.Errorf("unexpected ids (-want +got):\n%s", diff)
+	}
+}
+
+func TestDeleteFailedWorkerQueueRecordsUnknownQueue(t *testing.T) {
+	resetMocks()
+	database.Mocks.Users.GetByCurrentAuthUser = func(context.Context) (*types.User, error) {
+		return &types.User{SiteAdmin: true}, nil
+	}
+
+	if _, err := (&schemaResolver{}).DeleteFailedWorkerQueueRecords(context.Background(), &workerQueueRecordsArgs{Queue: "missing"}); err == nil {
+		t.Fatal("expected an error for an unknown queue")
+	}
+}
+
+func TestWorkerQueuesNonSiteAdmin(t *testing.T) {
+	resetMocks()
+	database.Mocks.Users.GetByCurrentAuthUser = func(context.Context) (*types.User, error) {
+		return &types.User{}, nil
+	}
+
+	if _, err := (&schemaResolver{}).WorkerQueues(context.Background()); err == nil {
+		t.Fatal("expected an error for a non site admin")
+	}
+}
@@ -0,0 +1,28 @@
+package bg
+
+import (
+	"context"
+	"time"
+
+	"github.com/inconshreveable/log15"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+)
+
+// DeleteOldWorkerutilFairnessDequeues deletes the fairness keys of dbworker queues that have not been
+// dequeued for a day, e.g. because the user or repository they belong to no longer has queued records.
+func DeleteOldWorkerutilFairnessDequeues(ctx context.Context, db dbutil.DB) {
+	for {
+		// Keys that have not been dequeued for a day are dequeued before all other keys whether or
+		// not they are deleted, so this only reorders them among each other. A deleted key is
+		// inserted again the next time it is dequeued.
+		_, err := db.ExecContext(
+			ctx,
+			`DELETE FROM workerutil_fairness_dequeues WHERE last_dequeued_at < now() - interval '1' day`,
+		)
+		if err != nil {
+			log15.Error("deleting expired rows from workerutil_fairness_dequeues table", "error", err)
+		}
+		time.Sleep(time.Hour)
+	}
+}
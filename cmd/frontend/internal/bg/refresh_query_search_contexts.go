@@ -0,0 +1,25 @@
+package bg
+
+import (
+	"context"
+	"time"
+
+	"github.com/inconshreveable/log15"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/searchcontexts"
+)
+
+// RefreshQuerySearchContexts periodically re-resolves the repositories of
+// search contexts which are defined by a query, so that they cover
+// repositories added after the search context was saved. Search contexts
+// refreshed recently by another frontend are skipped.
+func RefreshQuerySearchContexts(ctx context.Context, db dbutil.DB) {
+	for {
+		if err := searchcontexts.RefreshQuerySearchContexts(ctx, db, search.Indexed(), 10*time.Minute); err != nil {
+			log15.Error("refreshing repositories of search contexts defined by a query", "error", err)
+		}
+		time.Sleep(time.Minute)
+	}
+}
@@ -28,6 +28,9 @@ func newTest() *httptestutil.Client {
 		enterpriseServices.GitHubWebhook,
 		enterpriseServices.GitLabWebhook,
 		enterpriseServices.BitbucketServerWebhook,
+		enterpriseServices.BitbucketCloudWebhook,
+		enterpriseServices.AWSCodeCommitWebhook,
+		enterpriseServices.SearchJobsResultsHandler,
 		enterpriseServices.NewCodeIntelUploadHandler,
 		rateLimiter,
 	))
@@ -16,6 +16,7 @@ import (
 
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/globals"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/database"
@@ -645,6 +646,24 @@ func (s *gitServiceHandler) redirectToGitServer(w http.ResponseWriter, r *http.R
 	http.Redirect(w, r, u.String(), http.StatusTemporaryRedirect)
 }
 
+// withSearchActor runs h as the user given by the required "actor" query
+// parameter.
+//
+// 🚨 SECURITY: Internal API requests run as the internal actor, which bypasses
+// repository permissions. Searches on behalf of a user (e.g. search jobs) must
+// only see the repositories that user has access to.
+func withSearchActor(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userID, err := strconv.ParseInt(r.URL.Query().Get("actor"), 10, 32)
+		if err != nil || userID <= 0 {
+			http.Error(w, "actor must be a user ID", http.StatusBadRequest)
+			return
+		}
+		ctx := actor.WithActor(r.Context(), actor.FromUser(int32(userID)))
+		h.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
 func handlePing(w http.ResponseWriter, r *http.Request) {
 	if err := r.ParseForm(); err != nil {
 		http.Error(w, "could not parse form: "+err.Error(), http.StatusBadRequest)
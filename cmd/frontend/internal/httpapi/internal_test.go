@@ -15,6 +15,7 @@ import (
 	"github.com/gorilla/mux"
 
 	apirouter "github.com/sourcegraph/sourcegraph/cmd/frontend/internal/httpapi/router"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/types"
@@ -60,6 +61,43 @@ func (mockAddrForRepo) AddrForRepo(name api.RepoName) string {
 	return strings.ReplaceAll(string(name), "/", ".") + ".gitserver"
 }
 
+func TestWithSearchActor(t *testing.T) {
+	var got *actor.Actor
+	h := withSearchActor(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = actor.FromContext(r.Context())
+	}))
+
+	for _, tc := range []struct {
+		query      string
+		wantStatus int
+		wantUID    int32
+	}{
+		{query: "actor=42", wantStatus: http.StatusOK, wantUID: 42},
+		{query: "", wantStatus: http.StatusBadRequest},
+		{query: "actor=0", wantStatus: http.StatusBadRequest},
+		{query: "actor=foo", wantStatus: http.StatusBadRequest},
+	} {
+		got = nil
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "/search/export?"+tc.query, nil)
+		r = r.WithContext(actor.WithInternalActor(r.Context()))
+		h.ServeHTTP(w, r)
+
+		if w.Code != tc.wantStatus {
+			t.Errorf("%q: got status %d, want %d", tc.query, w.Code, tc.wantStatus)
+		}
+		if tc.wantStatus != http.StatusOK {
+			if got != nil {
+				t.Errorf("%q: handler should not have been called", tc.query)
+			}
+			continue
+		}
+		if got == nil || got.Internal || got.UID != tc.wantUID {
+			t.Errorf("%q: got actor %+v, want user %d", tc.query, got, tc.wantUID)
+		}
+	}
+}
+
 func TestReposIndex(t *testing.T) {
 	indexableRepos := []string{"github.com/popular/foo", "github.com/popular/bar"}
 	allRepos := append(indexableRepos, "github.com/alice/foo", "github.com/alice/bar")
@@ -11,7 +11,11 @@ const (
 	LSIFUpload = "lsif.upload"
 	GraphQL    = "graphql"
 
-	SearchStream = "search.stream"
+	SearchStream    = "search.stream"
+	SearchExport    = "search.export"
+	SearchAggregate = "search.aggregate"
+
+	SearchJobsResults = "search-jobs.results"
 
 	SrcCliVersion  = "src-cli.version"
 	SrcCliDownload = "src-cli.download"
@@ -25,6 +29,9 @@ const (
 	GitHubWebhooks          = "github.webhooks"
 	GitLabWebhooks          = "gitlab.webhooks"
 	BitbucketServerWebhooks = "bitbucketServer.webhooks"
+	BitbucketCloudWebhooks  = "bitbucketCloud.webhooks"
+	AWSCodeCommitWebhooks   = "awsCodeCommit.webhooks"
+	RepoUpdateWebhooks      = "repoUpdate.webhooks"
 
 	SavedQueriesListAll    = "internal.saved-queries.list-all"
 	SavedQueriesGetInfo    = "internal.saved-queries.get-info"
@@ -55,6 +62,7 @@ const (
 	SearchConfiguration    = "internal.search-configuration"
 	ExternalServiceConfigs = "internal.external-services.configs"
 	ExternalServicesList   = "internal.external-services.list"
+	InternalSearchExport   = "internal.search.export"
 )
 
 // New creates a new API router with route URL pattern definitions but
@@ -71,8 +79,14 @@ func New(base *mux.Router) *mux.Router {
 	base.Path("/github-webhooks").Methods("POST").Name(GitHubWebhooks)
 	base.Path("/gitlab-webhooks").Methods("POST").Name(GitLabWebhooks)
 	base.Path("/bitbucket-server-webhooks").Methods("POST").Name(BitbucketServerWebhooks)
+	base.Path("/bitbucket-cloud-webhooks").Methods("POST").Name(BitbucketCloudWebhooks)
+	base.Path("/aws-codecommit-webhooks").Methods("POST").Name(AWSCodeCommitWebhooks)
+	base.Path("/repo-update-webhooks").Methods("POST").Name(RepoUpdateWebhooks)
 	base.Path("/lsif/upload").Methods("POST").Name(LSIFUpload)
 	base.Path("/search/stream").Methods("GET").Name(SearchStream)
+	base.Path("/search/export").Methods("GET").Name(SearchExport)
+	base.Path("/search/aggregate").Methods("GET").Name(SearchAggregate)
+	base.Path("/search/jobs/{id:[0-9]+}/results").Methods("GET").Name(SearchJobsResults)
 	base.Path("/src-cli/version").Methods("GET").Name(SrcCliVersion)
 	base.Path("/src-cli/{rest:.*}").Methods("GET").Name(SrcCliDownload)
 
@@ -125,6 +139,7 @@ func NewInternal(base *mux.Router) *mux.Router {
 	base.Path("/repos/{RepoName:.*}").Methods("POST").Name(ReposGetByName)
 	base.Path("/configuration").Methods("POST").Name(Configuration)
 	base.Path("/search/configuration").Methods("GET", "POST").Name(SearchConfiguration)
+	base.Path("/search/export").Methods("GET").Name(InternalSearchExport)
 	base.Path("/telemetry").Methods("POST").Name(Telemetry)
 	base.Path("/lsif/upload").Methods("POST").Name(LSIFUpload)
 	addRegistryRoute(base)
@@ -0,0 +1,200 @@
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/search/query"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
+	"github.com/sourcegraph/sourcegraph/internal/trace"
+)
+
+// defaultAggregateLimit is the number of groups returned if the request
+// does not set "limit".
+const defaultAggregateLimit = 100
+
+// AggregateHandler is an http handler which runs a search without result
+// limits and responds with the number of results grouped by repository,
+// file path, commit author or regexp capture group.
+func AggregateHandler(db dbutil.DB) http.Handler {
+	return &aggregateHandler{
+		stream: &streamHandler{
+			db:                db,
+			newSearchResolver: defaultNewSearchResolver,
+		},
+	}
+}
+
+type aggregateHandler struct {
+	// stream is used to start searches and look up repository metadata.
+	stream *streamHandler
+}
+
+// aggregateResponse is the JSON body written by aggregateHandler.
+type aggregateResponse struct {
+	Mode streaming.AggregationMode `json:"mode"`
+
+	// Exhaustive is false if the counts are a lower bound, for example
+	// because a repository timed out.
+	Exhaustive bool `json:"exhaustive"`
+
+	Groups      []aggregateGroup `json:"groups"`
+	GroupCount  int              `json:"groupCount"`
+	ResultCount int              `json:"resultCount"`
+}
+
+type aggregateGroup struct {
+	Label      string `json:"label"`
+	Repository string `json:"repository,omitempty"`
+	Count      int    `json:"count"`
+}
+
+func (h *aggregateHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	args, err := parseAggregateURLQuery(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	tr, ctx := trace.New(ctx, "search.ServeAggregate", args.Query,
+		trace.Tag{Key: "version", Value: args.Version},
+		trace.Tag{Key: "pattern_type", Value: args.PatternType},
+		trace.Tag{Key: "mode", Value: string(args.Mode)},
+	)
+	defer func() {
+		tr.SetError(err)
+		tr.Finish()
+	}()
+
+	events, inputs, results := h.stream.startSearch(ctx, &args.args)
+	events = batchEvents(events, 50*time.Millisecond)
+
+	agg := streaming.Aggregation{Mode: args.Mode}
+	if args.Mode == streaming.AggregationModeCaptureGroup && inputs.Query != nil {
+		if agg.Pattern, err = captureGroupPattern(inputs.Query); err != nil {
+			// Stop the search, but drain events so it can exit.
+			cancel()
+			for range events {
+			}
+			_, _ = results()
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	for event := range events {
+		if len(event.Results) > 0 {
+			repoMetadata := h.stream.getEventRepoMetadata(ctx, event)
+			// See streamHandler.ServeHTTP.
+			filtered := make([]result.Match, 0, len(event.Results))
+			for _, match := range event.Results {
+				if md, ok := repoMetadata[match.RepoName().ID]; ok && md.Name == match.RepoName().Name {
+					filtered = append(filtered, match)
+				}
+			}
+			event.Results = filtered
+		}
+		agg.Update(event)
+	}
+
+	if _, err = results(); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	resp := aggregateResponse{
+		Mode:        agg.Mode,
+		Exhaustive:  agg.Exhaustive(),
+		Groups:      []aggregateGroup{},
+		GroupCount:  agg.GroupCount(),
+		ResultCount: agg.ResultCount,
+	}
+	for _, g := range agg.Compute(args.Limit) {
+		resp.Groups = append(resp.Groups, aggregateGroup{
+			Label:      g.Label,
+			Repository: g.Repository,
+			Count:      g.Count,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// captureGroupPattern returns the regexp used to label results in
+// AggregationModeCaptureGroup. The query must contain exactly one regexp
+// pattern with at least one capture group.
+func captureGroupPattern(q query.Q) (*regexp.Regexp, error) {
+	var patterns []string
+	var isRegexp bool
+	query.VisitPattern(q, func(value string, negated bool, annotation query.Annotation) {
+		if negated {
+			return
+		}
+		patterns = append(patterns, value)
+		isRegexp = annotation.Labels.IsSet(query.Regexp)
+	})
+	if len(patterns) != 1 || !isRegexp {
+		return nil, errors.New("mode capture_group requires a query with exactly one regexp pattern. Add patterntype:regexp to the query")
+	}
+
+	expr := patterns[0]
+	if !q.IsCaseSensitive() {
+		expr = "(?i:" + expr + ")"
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+	if re.NumSubexp() == 0 {
+		return nil, errors.Errorf("mode capture_group requires a capture group in the pattern %q", patterns[0])
+	}
+	return re, nil
+}
+
+type aggregateArgs struct {
+	args
+
+	Mode streaming.AggregationMode
+
+	// Limit is the maximum number of groups to return. All groups are
+	// returned if it is zero.
+	Limit int
+}
+
+func parseAggregateURLQuery(q url.Values) (*aggregateArgs, error) {
+	a, err := parseURLQuery(q)
+	if err != nil {
+		return nil, err
+	}
+
+	// Counts are only useful if they are exact, so we lift the default
+	// result limit unless the query asks for a specific count.
+	a.Query = withCountAll(a.Query)
+
+	aa := aggregateArgs{args: *a, Limit: defaultAggregateLimit}
+
+	if aa.Mode, err = streaming.ParseAggregationMode(q.Get("mode")); err != nil {
+		return nil, err
+	}
+
+	if limit := q.Get("limit"); limit != "" {
+		if aa.Limit, err = strconv.Atoi(limit); err != nil || aa.Limit < 0 {
+			return nil, errors.Errorf("limit must be a non-negative integer, got %q", limit)
+		}
+	}
+
+	return &aa, nil
+}
@@ -0,0 +1,199 @@
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	api2 "github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/search/query"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/internal/search/run"
+	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func TestServeAggregate(t *testing.T) {
+	// Repository 3 is not visible to the user.
+	database.Mocks.Repos.GetByIDs = func(ctx context.Context, ids ...api2.RepoID) ([]*types.Repo, error) {
+		res := make([]*types.Repo, 0, len(ids))
+		for _, id := range ids {
+			if id == 3 {
+				continue
+			}
+			res = append(res, &types.Repo{
+				ID:   id,
+				Name: api2.RepoName(fmt.Sprintf("repo%d", id)),
+			})
+		}
+		return res, nil
+	}
+	defer func() { database.Mocks.Repos.GetByIDs = nil }()
+
+	mkFileMatch := func(id int, path string, lines ...string) *result.FileMatch {
+		fm := &result.FileMatch{
+			File: result.File{
+				Repo: types.RepoName{ID: api2.RepoID(id), Name: api2.RepoName(fmt.Sprintf("repo%d", id))},
+				Path: path,
+			},
+		}
+		for _, l := range lines {
+			fm.LineMatches = append(fm.LineMatches, &result.LineMatch{
+				Preview:          l,
+				OffsetAndLengths: [][2]int32{{0, int32(len(l))}},
+			})
+		}
+		return fm
+	}
+
+	cases := []struct {
+		name  string
+		query string
+		want  aggregateResponse
+	}{{
+		name:  "repo",
+		query: "?q=log15",
+		want: aggregateResponse{
+			Mode:       streaming.AggregationModeRepo,
+			Exhaustive: true,
+			Groups: []aggregateGroup{
+				{Label: "repo1", Count: 3},
+				{Label: "repo2", Count: 1},
+			},
+			GroupCount:  2,
+			ResultCount: 4,
+		},
+	}, {
+		name:  "path with limit",
+		query: "?q=log15&mode=path&limit=1",
+		want: aggregateResponse{
+			Mode:       streaming.AggregationModePath,
+			Exhaustive: true,
+			Groups: []aggregateGroup{
+				{Label: "main.go", Repository: "repo1", Count: 2},
+			},
+			GroupCount:  3,
+			ResultCount: 4,
+		},
+	}, {
+		name:  "capture group",
+		query: "?q=log(\\d%2B)&t=regexp&mode=capture_group",
+		want: aggregateResponse{
+			Mode:       streaming.AggregationModeCaptureGroup,
+			Exhaustive: true,
+			Groups: []aggregateGroup{
+				{Label: "15", Count: 4},
+			},
+			GroupCount:  1,
+			ResultCount: 4,
+		},
+	}}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ts := httptest.NewServer(&aggregateHandler{
+				stream: &streamHandler{
+					newSearchResolver: func(_ context.Context, _ dbutil.DB, args *graphqlbackend.SearchArgs) (searchResolver, error) {
+						q, err := query.ParseRegexp(args.Query)
+						if err != nil {
+							t.Fatal(err)
+						}
+						mock := &mockSearchResolver{
+							done:   make(chan struct{}),
+							inputs: &run.SearchInputs{Query: q},
+						}
+						go func() {
+							args.Stream.Send(streaming.SearchEvent{
+								Results: []result.Match{
+									mkFileMatch(1, "main.go", "log15.New()", "log15.Root()"),
+									mkFileMatch(1, "util.go", "log15.Info()"),
+									mkFileMatch(2, "main.go", "log15.New()"),
+									mkFileMatch(3, "main.go", "log15.New()"),
+								},
+							})
+							mock.Close()
+						}()
+						return mock, nil
+					},
+				},
+			})
+			defer ts.Close()
+
+			res, err := http.Get(ts.URL + c.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer res.Body.Close()
+			if res.StatusCode != 200 {
+				t.Fatalf("expected status 200, got %d", res.StatusCode)
+			}
+
+			var got aggregateResponse
+			if err := json.NewDecoder(res.Body).Decode(&got); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(c.want, got); diff != "" {
+				t.Errorf("unexpected aggregation (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCaptureGroupPattern(t *testing.T) {
+	for _, raw := range []string{
+		"foo",
+		"foo|bar",
+	} {
+		q, err := query.ParseRegexp(raw)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := captureGroupPattern(q); err == nil {
+			t.Errorf("expected error for %q", raw)
+		}
+	}
+
+	q, err := query.ParseRegexp("import\\s(\\w+) case:yes")
+	if err != nil {
+		t.Fatal(err)
+	}
+	re, err := captureGroupPattern(q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := re.String(), `import\s(\w+)`; got != want {
+		t.Errorf("got pattern %q, want %q", got, want)
+	}
+}
+
+func TestParseAggregateURLQuery(t *testing.T) {
+	for _, raw := range []string{
+		"q=foo&mode=language",
+		"q=foo&limit=-1",
+		"q=foo&limit=x",
+		"mode=repo",
+	} {
+		q, _ := url.ParseQuery(raw)
+		if _, err := parseAggregateURLQuery(q); err == nil {
+			t.Errorf("expected error for %q", raw)
+		}
+	}
+
+	q, _ := url.ParseQuery("q=foo")
+	a, err := parseAggregateURLQuery(q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Query != "foo count:all" || a.Mode != streaming.AggregationModeRepo || a.Limit != defaultAggregateLimit {
+		t.Errorf("unexpected defaults %+v", a)
+	}
+}
@@ -0,0 +1,163 @@
+package search
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/search/query"
+	streamhttp "github.com/sourcegraph/sourcegraph/internal/search/streaming/http"
+	"github.com/sourcegraph/sourcegraph/internal/trace"
+)
+
+// ExportHandler is an http handler which runs a streaming search and writes
+// back every result as JSON lines or CSV. Unlike StreamHandler there is no
+// display limit, and results are written in a deterministic order so that an
+// interrupted export can be resumed with the "after" parameter.
+func ExportHandler(db dbutil.DB) http.Handler {
+	return &exportHandler{
+		stream: &streamHandler{
+			db:                db,
+			newSearchResolver: defaultNewSearchResolver,
+		},
+	}
+}
+
+type exportHandler struct {
+	// stream is used to start searches and look up repository metadata.
+	stream *streamHandler
+}
+
+// exportFlushInterval is the number of rows written between flushes.
+const exportFlushInterval = 1000
+
+func (h *exportHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	args, err := parseExportURLQuery(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	tr, ctx := trace.New(ctx, "search.ServeExport", args.Query,
+		trace.Tag{Key: "version", Value: args.Version},
+		trace.Tag{Key: "pattern_type", Value: args.PatternType},
+		trace.Tag{Key: "format", Value: string(args.Format)},
+	)
+	defer func() {
+		tr.SetError(err)
+		tr.Finish()
+	}()
+
+	exportWriter, err := streamhttp.NewExportWriter(w, args.Format)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	events, _, results := h.stream.startSearch(ctx, &args.args)
+
+	// Backends stream results in a different order every time, so we
+	// collect every row and sort them before writing. This is what makes
+	// the cursor of a row stable across requests.
+	var rows []streamhttp.ExportRow
+	for event := range events {
+		if len(event.Results) == 0 {
+			continue
+		}
+
+		repoMetadata := h.stream.getEventRepoMetadata(ctx, event)
+		for _, match := range event.Results {
+			// See streamHandler.ServeHTTP.
+			if md, ok := repoMetadata[match.RepoName().ID]; !ok || md.Name != match.RepoName().Name {
+				continue
+			}
+			for _, row := range streamhttp.ExportRows(fromMatch(match, repoMetadata)) {
+				if args.After != nil && !args.After.Before(&row) {
+					continue
+				}
+				rows = append(rows, row)
+			}
+		}
+	}
+
+	_, err = results()
+
+	streamhttp.SortExportRows(rows)
+	for i, row := range rows {
+		row.Seq = i + 1
+		if writeErr := exportWriter.Write(row); writeErr != nil {
+			return
+		}
+		if row.Seq%exportFlushInterval == 0 {
+			if writeErr := exportWriter.Flush(); writeErr != nil {
+				return
+			}
+		}
+	}
+	if writeErr := exportWriter.Flush(); writeErr != nil {
+		return
+	}
+
+	if err != nil {
+		exportWriter.Error(err)
+	}
+}
+
+type exportArgs struct {
+	args
+
+	Format streamhttp.ExportFormat
+
+	// After is the cursor of the last row the client received. Only rows
+	// after it are written.
+	After *streamhttp.ExportCursor
+}
+
+func parseExportURLQuery(q url.Values) (*exportArgs, error) {
+	a, err := parseURLQuery(q)
+	if err != nil {
+		return nil, err
+	}
+
+	// An export should contain every result, so we lift the default result
+	// limit unless the query asks for a specific count.
+	a.Query = withCountAll(a.Query)
+
+	ea := exportArgs{args: *a}
+
+	if ea.Format, err = streamhttp.ParseExportFormat(q.Get("format")); err != nil {
+		return nil, err
+	}
+
+	if after := q.Get("after"); after != "" {
+		if ea.After, err = streamhttp.DecodeExportCursor(after); err != nil {
+			return nil, err
+		}
+	}
+
+	return &ea, nil
+}
+
+// withCountAll returns rawQuery with count:all appended, unless it already
+// contains a count: parameter. The query is parsed so that "count:" inside a
+// quoted pattern is not mistaken for the parameter.
+func withCountAll(rawQuery string) string {
+	nodes, err := query.Parse(rawQuery, query.SearchTypeLiteral)
+	if err != nil {
+		// The search reports the parse error.
+		return rawQuery
+	}
+
+	hasCount := false
+	query.VisitField(query.LowercaseFieldNames(nodes), query.FieldCount, func(string, bool, query.Annotation) {
+		hasCount = true
+	})
+	if hasCount {
+		return rawQuery
+	}
+	return rawQuery + " count:all"
+}
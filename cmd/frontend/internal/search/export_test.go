@@ -0,0 +1,153 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	api2 "github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
+	streamhttp "github.com/sourcegraph/sourcegraph/internal/search/streaming/http"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func TestServeExport(t *testing.T) {
+	database.Mocks.Repos.GetByIDs = func(ctx context.Context, ids ...api2.RepoID) ([]*types.Repo, error) {
+		res := make([]*types.Repo, 0, len(ids))
+		for _, id := range ids {
+			res = append(res, &types.Repo{
+				ID:   id,
+				Name: api2.RepoName(fmt.Sprintf("repo%d", id)),
+			})
+		}
+		return res, nil
+	}
+	defer func() { database.Mocks.Repos.GetByIDs = nil }()
+
+	// cursor returns the cursor of the row for mkRepoMatch(id).
+	cursor := func(id int) string {
+		row := streamhttp.ExportRow{Type: streamhttp.RepoMatchType, Repository: fmt.Sprintf("repo%d", id)}
+		return row.EncodeCursor()
+	}
+
+	cases := []struct {
+		name  string
+		query string
+		want  string
+	}{{
+		name:  "jsonl",
+		query: "?q=foo",
+		want: fmt.Sprintf(`{"seq":1,"cursor":%q,"type":"repo","repository":"repo1"}
+{"seq":2,"cursor":%q,"type":"repo","repository":"repo2"}
+{"seq":3,"cursor":%q,"type":"repo","repository":"repo3"}
+`, cursor(1), cursor(2), cursor(3)),
+	}, {
+		name:  "csv",
+		query: "?q=foo&format=csv",
+		want: fmt.Sprintf(`seq,cursor,type,repository,path,branch,version,lineNumber,line,symbolName,symbolContainer,symbolKind,label,detail,url
+1,%s,repo,repo1,,,,,,,,,,,
+2,%s,repo,repo2,,,,,,,,,,,
+3,%s,repo,repo3,,,,,,,,,,,
+`, cursor(1), cursor(2), cursor(3)),
+	}, {
+		name:  "resume",
+		query: "?q=foo&after=" + cursor(2),
+		want: fmt.Sprintf(`{"seq":1,"cursor":%q,"type":"repo","repository":"repo3"}
+`, cursor(3)),
+	}}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var gotQuery string
+			ts := httptest.NewServer(&exportHandler{
+				stream: &streamHandler{
+					newSearchResolver: func(_ context.Context, _ dbutil.DB, args *graphqlbackend.SearchArgs) (searchResolver, error) {
+						gotQuery = args.Query
+						mock := &mockSearchResolver{
+							done: make(chan struct{}),
+						}
+						go func() {
+							// Results are sent out of order, the export sorts them.
+							args.Stream.Send(streaming.SearchEvent{
+								Results: []result.Match{mkRepoMatch(3), mkRepoMatch(1)},
+							})
+							args.Stream.Send(streaming.SearchEvent{
+								Results: []result.Match{mkRepoMatch(2)},
+							})
+							mock.Close()
+						}()
+						return mock, nil
+					},
+				},
+			})
+			defer ts.Close()
+
+			res, err := http.Get(ts.URL + c.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, err := io.ReadAll(res.Body)
+			res.Body.Close()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res.StatusCode != 200 {
+				t.Fatalf("expected status 200, got %d", res.StatusCode)
+			}
+
+			if diff := cmp.Diff(c.want, string(b)); diff != "" {
+				t.Errorf("unexpected export (-want +got):\n%s", diff)
+			}
+			if want := "foo count:all"; gotQuery != want {
+				t.Errorf("got query %q, want %q", gotQuery, want)
+			}
+		})
+	}
+}
+
+func TestParseExportURLQuery(t *testing.T) {
+	for _, raw := range []string{
+		"q=foo&format=xlsx",
+		"format=csv",
+		"q=foo&after=-1",
+		"q=foo&after=x",
+	} {
+		q, _ := url.ParseQuery(raw)
+		if _, err := parseExportURLQuery(q); err == nil {
+			t.Errorf("expected error for %q", raw)
+		}
+	}
+
+	q, _ := url.ParseQuery("q=foo+count:10")
+	a, err := parseExportURLQuery(q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Query != "foo count:10" {
+		t.Errorf("expected explicit count to be kept, got %q", a.Query)
+	}
+}
+
+func TestWithCountAll(t *testing.T) {
+	for in, want := range map[string]string{
+		"foo":                "foo count:all",
+		"foo count:10":       "foo count:10",
+		"foo COUNT:all":      "foo COUNT:all",
+		`"count:10" foo`:     `"count:10" foo count:all`,
+		"content:count:3 ok": "content:count:3 ok count:all",
+	} {
+		if got := withCountAll(in); got != want {
+			t.Errorf("withCountAll(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -51,6 +51,7 @@ func (p *progressAggregator) currentStats() api.ProgressStats {
 		Timedout:            getNames(p.Stats, searchshared.RepoStatusTimedout),
 		Missing:             getNames(p.Stats, searchshared.RepoStatusMissing),
 		Cloning:             getNames(p.Stats, searchshared.RepoStatusCloning),
+		BlameTruncated:      getNames(p.Stats, searchshared.RepoStatusBlameTruncated),
 		LimitHit:            p.Stats.IsLimitHit,
 		SuggestedLimit:      suggestedLimit,
 		Trace:               p.Trace,
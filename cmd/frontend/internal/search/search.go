@@ -29,6 +29,8 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
 	"github.com/sourcegraph/sourcegraph/internal/honey"
 	"github.com/sourcegraph/sourcegraph/internal/lazyregexp"
+	"github.com/sourcegraph/sourcegraph/internal/search/fuzzy"
+	"github.com/sourcegraph/sourcegraph/internal/search/query"
 	"github.com/sourcegraph/sourcegraph/internal/search/result"
 	"github.com/sourcegraph/sourcegraph/internal/search/run"
 	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
@@ -130,6 +132,14 @@ func (h *streamHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		Globbing: false, // TODO
 	}
 
+	var (
+		fuzzyPattern string
+		fuzzyMatches []result.Match
+	)
+	if inputs.PatternType == query.SearchTypeFuzzy {
+		fuzzyPattern = fuzzy.QueryPattern(inputs.Query)
+	}
+
 	// Store marshalled matches and flush periodically or when we go over
 	// 32kb.
 	matchesBuf := &jsonArrayBuf{
@@ -155,34 +165,8 @@ func (h *streamHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		_ = matchesBuf.Append(m)
 	}
 
-	flushTicker := time.NewTicker(h.flushTickerInternal)
-	defer flushTicker.Stop()
-
-	pingTicker := time.NewTicker(h.pingTickerInterval)
-	defer pingTicker.Stop()
-
 	first := true
-
-	for {
-		var event streaming.SearchEvent
-		var ok bool
-		select {
-		case event, ok = <-events:
-		case <-flushTicker.C:
-			ok = true
-			matchesFlush()
-		case <-pingTicker.C:
-			ok = true
-			sendProgress()
-		}
-
-		if !ok {
-			break
-		}
-
-		progress.Update(event)
-		filters.Update(event)
-
+	sendMatches := func(event streaming.SearchEvent) {
 		// Truncate the event to the match limit before fetching repo metadata
 		for i, match := range event.Results {
 			if display <= 0 {
@@ -216,6 +200,47 @@ func (h *streamHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	flushTicker := time.NewTicker(h.flushTickerInternal)
+	defer flushTicker.Stop()
+
+	pingTicker := time.NewTicker(h.pingTickerInterval)
+	defer pingTicker.Stop()
+
+	for {
+		var event streaming.SearchEvent
+		var ok bool
+		select {
+		case event, ok = <-events:
+		case <-flushTicker.C:
+			ok = true
+			matchesFlush()
+		case <-pingTicker.C:
+			ok = true
+			sendProgress()
+		}
+
+		if !ok {
+			break
+		}
+
+		progress.Update(event)
+		filters.Update(event)
+
+		// Fuzzy search results are ranked once all of them are found, and
+		// only then truncated to the match limit.
+		if fuzzyPattern != "" {
+			fuzzyMatches = append(fuzzyMatches, event.Results...)
+			continue
+		}
+
+		sendMatches(event)
+	}
+
+	if fuzzyPattern != "" {
+		fuzzy.Sort(fuzzyPattern, fuzzyMatches)
+		sendMatches(streaming.SearchEvent{Results: fuzzyMatches})
+	}
+
 	matchesFlush()
 
 	// Send dynamic filters once.
@@ -426,7 +451,9 @@ func fromMatch(match result.Match, repoCache map[api.RepoID]*types.Repo) streamh
 }
 
 func fromFileMatch(fm *result.FileMatch, repoCache map[api.RepoID]*types.Repo) streamhttp.EventMatch {
-	if len(fm.Symbols) > 0 {
+	if fm.RewriteDiff != "" {
+		return fromRewriteMatch(fm, repoCache)
+	} else if len(fm.Symbols) > 0 {
 		return fromSymbolMatch(fm, repoCache)
 	} else if len(fm.LineMatches) > 0 {
 		return fromContentMatch(fm, repoCache)
@@ -434,6 +461,28 @@ func fromFileMatch(fm *result.FileMatch, repoCache map[api.RepoID]*types.Repo) s
 	return fromPathMatch(fm, repoCache)
 }
 
+func fromRewriteMatch(fm *result.FileMatch, repoCache map[api.RepoID]*types.Repo) *streamhttp.EventRewriteMatch {
+	var branches []string
+	if fm.InputRev != nil {
+		branches = []string{*fm.InputRev}
+	}
+
+	var stars int
+	if r, ok := repoCache[fm.Repo.ID]; ok {
+		stars = r.Stars
+	}
+
+	return &streamhttp.EventRewriteMatch{
+		Type:       streamhttp.RewriteMatchType,
+		Path:       fm.Path,
+		Repository: string(fm.Repo.Name),
+		RepoStars:  stars,
+		Branches:   branches,
+		Version:    string(fm.CommitID),
+		Diff:       fm.RewriteDiff,
+	}
+}
+
 func fromPathMatch(fm *result.FileMatch, repoCache map[api.RepoID]*types.Repo) *streamhttp.EventPathMatch {
 	var branches []string
 	if fm.InputRev != nil {
@@ -212,6 +212,93 @@ func TestDisplayLimit(t *testing.T) {
 	}
 }
 
+func TestFuzzyRanking(t *testing.T) {
+	mock := &mockSearchResolver{
+		done: make(chan struct{}),
+	}
+
+	database.Mocks.Repos.GetByIDs = func(ctx context.Context, ids ...api2.RepoID) (_ []*types.Repo, err error) {
+		res := make([]*types.Repo, 0, len(ids))
+		for _, id := range ids {
+			res = append(res, &types.Repo{
+				ID:   id,
+				Name: api2.RepoName(fmt.Sprintf("repo%d", id)),
+			})
+		}
+		return res, nil
+	}
+	defer func() { database.Mocks.Repos.GetByIDs = nil }()
+
+	ts := httptest.NewServer(&streamHandler{
+		flushTickerInternal: 1 * time.Millisecond,
+		pingTickerInterval:  1 * time.Millisecond,
+		newSearchResolver: func(_ context.Context, _ dbutil.DB, args *graphqlbackend.SearchArgs) (searchResolver, error) {
+			mock.c = args.Stream
+			plan, err := query.Pipeline(query.InitFuzzy("usrsvc"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			mock.inputs = &run.SearchInputs{
+				Query:       plan.ToParseTree(),
+				PatternType: query.SearchTypeFuzzy,
+			}
+			return mock, nil
+		}})
+	defer ts.Close()
+
+	req, _ := streamhttp.NewRequest(ts.URL, "usrsvc")
+	q := req.URL.Query()
+	q.Add("display", "1")
+	req.URL.RawQuery = q.Encode()
+
+	var got []string
+	decoder := streamhttp.Decoder{
+		OnMatches: func(matches []streamhttp.EventMatch) {
+			for _, m := range matches {
+				if pm, ok := m.(*streamhttp.EventPathMatch); ok {
+					got = append(got, pm.Path)
+				}
+			}
+		},
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	g := errgroup.Group{}
+	g.Go(func() error {
+		return decoder.ReadAll(resp.Body)
+	})
+
+	// The best match arrives last, in a separate event.
+	mock.c.Send(streaming.SearchEvent{
+		Results: []result.Match{mkPathMatch(1, "user/service/main.go")},
+	})
+	mock.c.Send(streaming.SearchEvent{
+		Results: []result.Match{mkPathMatch(2, "user_service.go")},
+	})
+	mock.Close()
+	if err := g.Wait(); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []string{"user_service.go"}; fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func mkPathMatch(repoID int, path string) *result.FileMatch {
+	return &result.FileMatch{
+		File: result.File{
+			Repo: types.RepoName{ID: api2.RepoID(repoID), Name: api2.RepoName(fmt.Sprintf("repo%d", repoID))},
+			Path: path,
+		},
+	}
+}
+
 func mkRepoMatch(id int) *result.RepoMatch {
 	return &result.RepoMatch{
 		ID:   api2.RepoID(id),
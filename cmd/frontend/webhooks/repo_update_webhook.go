@@ -0,0 +1,354 @@
+package webhooks
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/cockroachdb/errors"
+	gh "github.com/google/go-github/v28/github"
+	"github.com/hashicorp/go-multierror"
+	"github.com/inconshreveable/log15"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketserver"
+	gitlabwebhooks "github.com/sourcegraph/sourcegraph/internal/extsvc/gitlab/webhooks"
+	"github.com/sourcegraph/sourcegraph/internal/repoupdater/protocol"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+var repoUpdateWebhookEvents = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "src_repo_update_webhook_events_total",
+	Help: "Total number of code host webhook events received to update repositories, by the action taken.",
+}, []string{"kind", "outcome"})
+
+// RepoUpdater is the subset of the repo-updater client used by
+// RepoUpdateWebhook.
+type RepoUpdater interface {
+	EnqueueRepoUpdate(ctx context.Context, repo api.RepoName) (*protocol.RepoUpdateResponse, error)
+	SyncExternalService(ctx context.Context, svc api.ExternalService) (*protocol.ExternalServiceSyncResult, error)
+}
+
+// RepoUpdateWebhook handles push and repository webhook events from GitHub,
+// GitLab and Bitbucket Server code host connections. Pushes schedule an
+// immediate update of the pushed repository, rather than waiting for it to be
+// polled. Repository creation, renames and deletions trigger a sync of the
+// code host connection.
+//
+// The code host connection is identified by the externalServiceID query
+// parameter. Requests are authenticated with the webhook secrets configured in
+// the connection.
+type RepoUpdateWebhook struct {
+	ExternalServices *database.ExternalServiceStore
+	Repos            *database.RepoStore
+	RepoUpdater      RepoUpdater
+}
+
+// repoUpdateEvent is what RepoUpdateWebhook does in response to an event.
+type repoUpdateEvent struct {
+	// Repos are the repositories to update.
+	Repos []api.ExternalRepoSpec
+	// Sync is true if the code host connection should be synced.
+	Sync bool
+}
+
+func (h *RepoUpdateWebhook) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	extSvc, err := h.getExternalService(r.Context(), r.FormValue(extsvc.IDParam))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	outcome := "error"
+	defer func() {
+		repoUpdateWebhookEvents.WithLabelValues(extSvc.Kind, outcome).Inc()
+	}()
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	cfg, err := extSvc.Configuration()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// 🚨 SECURITY: Only handle events which are authenticated by one of the
+	// webhook secrets configured in the code host connection.
+	if !validateRepoUpdateWebhook(r, body, cfg) {
+		outcome = "unauthorized"
+		http.Error(w, "webhook could not be authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	var event *repoUpdateEvent
+	switch c := cfg.(type) {
+	case *schema.GitHubConnection:
+		event, err = parseGitHubRepoUpdateEvent(gh.WebHookType(r), body, c.Url)
+	case *schema.GitLabConnection:
+		event, err = parseGitLabRepoUpdateEvent(body, c.Url)
+	case *schema.BitbucketServerConnection:
+		event, err = parseBitbucketServerRepoUpdateEvent(bitbucketserver.WebhookEventType(r), body, c.Url)
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if event == nil {
+		// We don't want the code host to retry events we don't care about.
+		outcome = "ignored"
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	if err := h.handleEvent(r.Context(), extSvc, event); err != nil {
+		log15.Error("Error handling repo update webhook event", "externalServiceID", extSvc.ID, "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	outcome = "repo_update"
+	if event.Sync {
+		outcome = "sync"
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// getExternalService returns the GitHub, GitLab or Bitbucket Server external
+// service with the given raw ID.
+func (h *RepoUpdateWebhook) getExternalService(ctx context.Context, rawID string) (*types.ExternalService, error) {
+	id, err := strconv.ParseInt(rawID, 10, 64)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid external service ID")
+	}
+	e, err := h.ExternalServices.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	switch e.Kind {
+	case extsvc.KindGitHub, extsvc.KindGitLab, extsvc.KindBitbucketServer:
+		return e, nil
+	default:
+		return nil, errors.Errorf("repo update webhooks are not supported for external services of kind %s", e.Kind)
+	}
+}
+
+func (h *RepoUpdateWebhook) handleEvent(ctx context.Context, extSvc *types.ExternalService, event *repoUpdateEvent) error {
+	if event.Sync {
+		_, err := h.RepoUpdater.SyncExternalService(ctx, api.ExternalService{
+			ID:              extSvc.ID,
+			Kind:            extSvc.Kind,
+			DisplayName:     extSvc.DisplayName,
+			Config:          extSvc.Config,
+			CreatedAt:       extSvc.CreatedAt,
+			UpdatedAt:       extSvc.UpdatedAt,
+			DeletedAt:       extSvc.DeletedAt,
+			LastSyncAt:      extSvc.LastSyncAt,
+			NextSyncAt:      extSvc.NextSyncAt,
+			NamespaceUserID: extSvc.NamespaceUserID,
+		})
+		if err != nil {
+			return errors.Wrap(err, "syncing external service")
+		}
+	}
+
+	if len(event.Repos) == 0 {
+		return nil
+	}
+
+	// 🚨 SECURITY: The event may be about a private repository, so we need to
+	// be able to find any repository here. We only use the repository to
+	// schedule an update, and don't reveal anything about it.
+	ctx = actor.WithInternalActor(ctx)
+	repos, err := h.Repos.ListRepoNames(ctx, database.ReposListOptions{
+		ExternalServiceIDs: []int64{extSvc.ID},
+		ExternalRepos:      event.Repos,
+	})
+	if err != nil {
+		return errors.Wrap(err, "listing repositories")
+	}
+
+	var errs error
+	for _, repo := range repos {
+		if _, err := h.RepoUpdater.EnqueueRepoUpdate(ctx, repo.Name); err != nil {
+			errs = multierror.Append(errs, errors.Wrapf(err, "enqueueing update of %s", repo.Name))
+		}
+	}
+	return errs
+}
+
+// validateRepoUpdateWebhook returns true if the request is authenticated by a
+// webhook secret in the code host connection cfg.
+func validateRepoUpdateWebhook(r *http.Request, body []byte, cfg interface{}) bool {
+	var secrets []string
+	switch c := cfg.(type) {
+	case *schema.GitHubConnection:
+		for _, hook := range c.Webhooks {
+			secrets = append(secrets, hook.Secret)
+		}
+	case *schema.GitLabConnection:
+		for _, hook := range c.Webhooks {
+			secrets = append(secrets, hook.Secret)
+		}
+	case *schema.BitbucketServerConnection:
+		secrets = append(secrets, c.WebhookSecret())
+	}
+
+	for _, secret := range secrets {
+		if secret == "" {
+			continue
+		}
+
+		switch cfg.(type) {
+		case *schema.GitLabConnection:
+			// GitLab sends the secret itself.
+			if subtle.ConstantTimeCompare([]byte(r.Header.Get(gitlabwebhooks.TokenHeaderName)), []byte(secret)) == 1 {
+				return true
+			}
+		default:
+			// GitHub and Bitbucket Server sign the payload with the secret.
+			sig := r.Header.Get("X-Hub-Signature-256")
+			if sig == "" {
+				sig = r.Header.Get("X-Hub-Signature")
+			}
+			if gh.ValidateSignature(sig, body, []byte(secret)) == nil {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// codeHostServiceID returns the external service ID of repositories on the
+// code host at rawURL.
+func codeHostServiceID(rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+	return extsvc.NormalizeBaseURL(u).String(), nil
+}
+
+func parseGitHubRepoUpdateEvent(eventType string, body []byte, rawURL string) (*repoUpdateEvent, error) {
+	switch eventType {
+	case "push", "create", "delete", "repository":
+	default:
+		return nil, nil
+	}
+
+	e, err := gh.ParseWebHook(eventType, body)
+	if err != nil {
+		return nil, err
+	}
+
+	var nodeID string
+	switch e := e.(type) {
+	case *gh.PushEvent:
+		nodeID = e.GetRepo().GetNodeID()
+	case *gh.CreateEvent:
+		nodeID = e.GetRepo().GetNodeID()
+	case *gh.DeleteEvent:
+		nodeID = e.GetRepo().GetNodeID()
+	case *gh.RepositoryEvent:
+		switch e.GetAction() {
+		case "created", "deleted", "renamed", "transferred", "archived", "unarchived", "publicized", "privatized":
+			return &repoUpdateEvent{Sync: true}, nil
+		}
+		return nil, nil
+	}
+	if nodeID == "" {
+		return nil, nil
+	}
+
+	serviceID, err := codeHostServiceID(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	return &repoUpdateEvent{Repos: []api.ExternalRepoSpec{{
+		ID:          nodeID,
+		ServiceType: extsvc.TypeGitHub,
+		ServiceID:   serviceID,
+	}}}, nil
+}
+
+func parseGitLabRepoUpdateEvent(body []byte, rawURL string) (*repoUpdateEvent, error) {
+	// Project lifecycle events are only sent as system hooks, which have an
+	// event_name rather than an object_kind.
+	var systemHook struct {
+		EventName string `json:"event_name"`
+	}
+	if err := json.Unmarshal(body, &systemHook); err != nil {
+		return nil, err
+	}
+	switch systemHook.EventName {
+	case "project_create", "project_destroy", "project_rename", "project_transfer":
+		return &repoUpdateEvent{Sync: true}, nil
+	}
+
+	e, err := gitlabwebhooks.UnmarshalEvent(body)
+	if err != nil {
+		if errors.Is(err, gitlabwebhooks.ErrObjectKindUnknown) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	push, ok := e.(*gitlabwebhooks.PushEvent)
+	if !ok || push.Project.ID == 0 {
+		return nil, nil
+	}
+
+	serviceID, err := codeHostServiceID(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	return &repoUpdateEvent{Repos: []api.ExternalRepoSpec{{
+		ID:          strconv.Itoa(push.Project.ID),
+		ServiceType: extsvc.TypeGitLab,
+		ServiceID:   serviceID,
+	}}}, nil
+}
+
+func parseBitbucketServerRepoUpdateEvent(eventType string, body []byte, rawURL string) (*repoUpdateEvent, error) {
+	switch eventType {
+	case "repo:refs_changed", "repo:modified", "repo:forked":
+	default:
+		return nil, nil
+	}
+
+	e, err := bitbucketserver.ParseWebhookEvent(eventType, body)
+	if err != nil {
+		return nil, err
+	}
+
+	switch e := e.(type) {
+	case *bitbucketserver.RefsChangedEvent:
+		if e.Repository.ID == 0 {
+			return nil, nil
+		}
+		serviceID, err := codeHostServiceID(rawURL)
+		if err != nil {
+			return nil, err
+		}
+		return &repoUpdateEvent{Repos: []api.ExternalRepoSpec{{
+			ID:          strconv.Itoa(e.Repository.ID),
+			ServiceType: extsvc.TypeBitbucketServer,
+			ServiceID:   serviceID,
+		}}}, nil
+	case *bitbucketserver.RepoModifiedEvent, *bitbucketserver.RepoForkedEvent:
+		return &repoUpdateEvent{Sync: true}, nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,244 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cockroachdb/errors"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/repoupdater/protocol"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+type fakeRepoUpdater struct {
+	updated []api.RepoName
+	synced  []int64
+}
+
+func (f *fakeRepoUpdater) EnqueueRepoUpdate(ctx context.Context, repo api.RepoName) (*protocol.RepoUpdateResponse, error) {
+	f.updated = append(f.updated, repo)
+	return &protocol.RepoUpdateResponse{}, nil
+}
+
+func (f *fakeRepoUpdater) SyncExternalService(ctx context.Context, svc api.ExternalService) (*protocol.ExternalServiceSyncResult, error) {
+	f.synced = append(f.synced, svc.ID)
+	return &protocol.ExternalServiceSyncResult{}, nil
+}
+
+func TestRepoUpdateWebhook(t *testing.T) {
+	const secret = "s3cr3t"
+
+	externalServices := map[int64]*types.ExternalService{
+		1: {ID: 1, Kind: extsvc.KindGitHub, Config: `{"url": "https://github.com", "webhooks": [{"org": "foo", "secret": "s3cr3t"}]}`},
+		2: {ID: 2, Kind: extsvc.KindGitLab, Config: `{"url": "https://gitlab.com/", "webhooks": [{"secret": "s3cr3t"}]}`},
+		3: {ID: 3, Kind: extsvc.KindBitbucketServer, Config: `{"url": "https://bitbucket.example.com", "webhooks": {"secret": "s3cr3t"}}`},
+		4: {ID: 4, Kind: extsvc.KindGitHub, Config: `{"url": "https://github.com"}`},
+		5: {ID: 5, Kind: extsvc.KindPhabricator, Config: `{}`},
+	}
+	database.Mocks.ExternalServices.GetByID = func(id int64) (*types.ExternalService, error) {
+		e, ok := externalServices[id]
+		if !ok {
+			return nil, errors.New("not found")
+		}
+		return e, nil
+	}
+	var listOpts []database.ReposListOptions
+	database.Mocks.Repos.ListRepoNames = func(ctx context.Context, opt database.ReposListOptions) ([]types.RepoName, error) {
+		listOpts = append(listOpts, opt)
+		return []types.RepoName{{ID: 1, Name: "repo"}}, nil
+	}
+	t.Cleanup(func() {
+		database.Mocks.ExternalServices = database.MockExternalServices{}
+		database.Mocks.Repos = database.MockRepos{}
+	})
+
+	sign := func(body string) string {
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write([]byte(body))
+		return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	}
+
+	for _, tc := range []struct {
+		name       string
+		id         string
+		headers    map[string]string
+		body       string
+		wantCode   int
+		wantRepos  []api.ExternalRepoSpec
+		wantSynced []int64
+	}{
+		{
+			name: "github push",
+			id:   "1",
+			headers: map[string]string{
+				"X-Github-Event":      "push",
+				"X-Hub-Signature-256": sign(`{"repository": {"node_id": "MDEwOlJlcG9zaXRvcnkx"}}`),
+			},
+			body:     `{"repository": {"node_id": "MDEwOlJlcG9zaXRvcnkx"}}`,
+			wantCode: http.StatusNoContent,
+			wantRepos: []api.ExternalRepoSpec{{
+				ID:          "MDEwOlJlcG9zaXRvcnkx",
+				ServiceType: extsvc.TypeGitHub,
+				ServiceID:   "https://github.com/",
+			}},
+		},
+		{
+			name: "github repository renamed",
+			id:   "1",
+			headers: map[string]string{
+				"X-Github-Event":      "repository",
+				"X-Hub-Signature-256": sign(`{"action": "renamed"}`),
+			},
+			body:       `{"action": "renamed"}`,
+			wantCode:   http.StatusNoContent,
+			wantSynced: []int64{1},
+		},
+		{
+			name: "github unhandled event",
+			id:   "1",
+			headers: map[string]string{
+				"X-Github-Event":      "issues",
+				"X-Hub-Signature-256": sign(`{}`),
+			},
+			body:     `{}`,
+			wantCode: http.StatusNoContent,
+		},
+		{
+			name: "github invalid signature",
+			id:   "1",
+			headers: map[string]string{
+				"X-Github-Event":      "push",
+				"X-Hub-Signature-256": sign(`{"other": "body"}`),
+			},
+			body:     `{"repository": {"node_id": "MDEwOlJlcG9zaXRvcnkx"}}`,
+			wantCode: http.StatusUnauthorized,
+		},
+		{
+			name: "github without webhook secrets",
+			id:   "4",
+			headers: map[string]string{
+				"X-Github-Event": "push",
+			},
+			body:     `{"repository": {"node_id": "MDEwOlJlcG9zaXRvcnkx"}}`,
+			wantCode: http.StatusUnauthorized,
+		},
+		{
+			name: "gitlab push",
+			id:   "2",
+			headers: map[string]string{
+				"X-Gitlab-Token": secret,
+			},
+			body:     `{"object_kind": "push", "project": {"id": 42}}`,
+			wantCode: http.StatusNoContent,
+			wantRepos: []api.ExternalRepoSpec{{
+				ID:          "42",
+				ServiceType: extsvc.TypeGitLab,
+				ServiceID:   "https://gitlab.com/",
+			}},
+		},
+		{
+			name: "gitlab project renamed",
+			id:   "2",
+			headers: map[string]string{
+				"X-Gitlab-Token": secret,
+			},
+			body:       `{"event_name": "project_rename", "project_id": 42}`,
+			wantCode:   http.StatusNoContent,
+			wantSynced: []int64{2},
+		},
+		{
+			name: "gitlab invalid token",
+			id:   "2",
+			headers: map[string]string{
+				"X-Gitlab-Token": "wrong",
+			},
+			body:     `{"object_kind": "push", "project": {"id": 42}}`,
+			wantCode: http.StatusUnauthorized,
+		},
+		{
+			name: "bitbucket server refs changed",
+			id:   "3",
+			headers: map[string]string{
+				"X-Event-Key":     "repo:refs_changed",
+				"X-Hub-Signature": sign(`{"repository": {"id": 7}}`),
+			},
+			body:     `{"repository": {"id": 7}}`,
+			wantCode: http.StatusNoContent,
+			wantRepos: []api.ExternalRepoSpec{{
+				ID:          "7",
+				ServiceType: extsvc.TypeBitbucketServer,
+				ServiceID:   "https://bitbucket.example.com/",
+			}},
+		},
+		{
+			name: "bitbucket server repo modified",
+			id:   "3",
+			headers: map[string]string{
+				"X-Event-Key":     "repo:modified",
+				"X-Hub-Signature": sign(`{"old": {"id": 7}, "new": {"id": 7}}`),
+			},
+			body:       `{"old": {"id": 7}, "new": {"id": 7}}`,
+			wantCode:   http.StatusNoContent,
+			wantSynced: []int64{3},
+		},
+		{
+			name:     "unknown external service",
+			id:       "10",
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "unsupported external service",
+			id:       "5",
+			wantCode: http.StatusBadRequest,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			listOpts = nil
+			updater := &fakeRepoUpdater{}
+			h := &RepoUpdateWebhook{
+				ExternalServices: database.ExternalServices(nil),
+				Repos:            database.Repos(nil),
+				RepoUpdater:      updater,
+			}
+
+			req := httptest.NewRequest("POST", "/.api/repo-update-webhooks?externalServiceID="+tc.id, bytes.NewBufferString(tc.body))
+			for k, v := range tc.headers {
+				req.Header.Set(k, v)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if rec.Code != tc.wantCode {
+				t.Fatalf("got status %d, want %d: %s", rec.Code, tc.wantCode, rec.Body.String())
+			}
+
+			var gotRepos []api.ExternalRepoSpec
+			for _, opt := range listOpts {
+				gotRepos = append(gotRepos, opt.ExternalRepos...)
+			}
+			if diff := cmp.Diff(tc.wantRepos, gotRepos); diff != "" {
+				t.Errorf("unexpected repos looked up (-want +got):\n%s", diff)
+			}
+			var wantUpdated []api.RepoName
+			if len(tc.wantRepos) > 0 {
+				wantUpdated = []api.RepoName{"repo"}
+			}
+			if diff := cmp.Diff(wantUpdated, updater.updated); diff != "" {
+				t.Errorf("unexpected repos updated (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.wantSynced, updater.synced); diff != "" {
+				t.Errorf("unexpected external services synced (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
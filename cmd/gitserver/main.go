@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
 	"time"
 
@@ -34,6 +35,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/trace"
 	"github.com/sourcegraph/sourcegraph/internal/trace/ot"
 	"github.com/sourcegraph/sourcegraph/internal/tracer"
+	"github.com/sourcegraph/sourcegraph/internal/types"
 	"github.com/sourcegraph/sourcegraph/schema"
 )
 
@@ -44,6 +46,7 @@ var (
 	syncRepoStateInterval        = env.MustGetDuration("SRC_REPOS_SYNC_STATE_INTERVAL", 10*time.Minute, "Interval between state syncs")
 	syncRepoStateBatchSize       = env.MustGetInt("SRC_REPOS_SYNC_STATE_BATCH_SIZE", 500, "Number of upserts to perform per batch")
 	syncRepoStateUpsertPerSecond = env.MustGetInt("SRC_REPOS_SYNC_STATE_UPSERT_PER_SEC", 500, "The number of upserted rows allowed per second across all gitserver instances")
+	rebalanceInterval            = env.MustGetDuration("SRC_REPOS_REBALANCE_INTERVAL", 10*time.Minute, "Interval between moving repos to the gitserver instance they are assigned to. Set to 0 to disable moving repos between gitserver instances.")
 )
 
 func main() {
@@ -78,6 +81,7 @@ func main() {
 	}
 	repoStore := database.Repos(db)
 	externalServiceStore := database.ExternalServices(db)
+	var partialCloneRules partialCloneRulesCache
 
 	err = keyring.Init(ctx)
 	if err != nil {
@@ -115,21 +119,8 @@ func main() {
 			case extsvc.TypePerforce:
 				// Extract options from external service config
 				var c schema.PerforceConnection
-				for _, info := range r.Sources {
-					es, err := externalServiceStore.GetByID(ctx, info.ExternalServiceID())
-					if err != nil {
-						return nil, errors.Wrap(err, "get external service")
-					}
-
-					normalized, err := jsonc.Parse(es.Config)
-					if err != nil {
-						return nil, errors.Wrap(err, "normalize JSON")
-					}
-
-					if err = jsoniter.Unmarshal(normalized, &c); err != nil {
-						return nil, errors.Wrap(err, "unmarshal JSON")
-					}
-					break
+				if err := unmarshalSourceConfig(ctx, externalServiceStore, r, &c); err != nil {
+					return nil, err
 				}
 
 				return &server.PerforceDepotSyncer{
@@ -137,29 +128,36 @@ func main() {
 				}, nil
 			case extsvc.TypeJVMPackages:
 				var c schema.JVMPackagesConnection
-				for _, info := range r.Sources {
-					es, err := externalServiceStore.GetByID(ctx, info.ExternalServiceID())
-					if err != nil {
-						return nil, errors.Wrap(err, "get external service")
-					}
-
-					normalized, err := jsonc.Parse(es.Config)
-					if err != nil {
-						return nil, errors.Wrap(err, "normalize JSON")
-					}
-
-					if err = jsoniter.Unmarshal(normalized, &c); err != nil {
-						return nil, errors.Wrap(err, "unmarshal JSON")
-					}
-					break
+				if err := unmarshalSourceConfig(ctx, externalServiceStore, r, &c); err != nil {
+					return nil, err
 				}
 
 				return &server.JVMPackagesSyncer{Config: &c}, nil
+			case extsvc.TypeNpmPackages:
+				var c schema.NpmPackagesConnection
+				if err := unmarshalSourceConfig(ctx, externalServiceStore, r, &c); err != nil {
+					return nil, err
+				}
+
+				return server.NewNpmPackagesSyncer(&c, nil), nil
+			case extsvc.TypeGoModules:
+				var c schema.GoModulesConnection
+				if err := unmarshalSourceConfig(ctx, externalServiceStore, r, &c); err != nil {
+					return nil, err
+				}
+
+				return server.NewGoModulesSyncer(&c, nil), nil
 			}
-			return &server.GitRepoSyncer{}, nil
+
+			partialClone, err := partialCloneRules.options(ctx, externalServiceStore, r)
+			if err != nil {
+				return nil, err
+			}
+			return &server.GitRepoSyncer{PartialClone: partialClone}, nil
 		},
-		Hostname: hostname.Get(),
-		DB:       db,
+		DisableRebalancing: rebalanceInterval <= 0,
+		Hostname:           hostname.Get(),
+		DB:                 db,
 	}
 	gitserver.RegisterMetrics()
 
@@ -180,6 +178,7 @@ func main() {
 	go debugserver.NewServerRoutine(ready).Start()
 	go gitserver.Janitor(janitorInterval)
 	go gitserver.SyncRepoState(syncRepoStateInterval, syncRepoStateBatchSize, syncRepoStateUpsertPerSecond)
+	go gitserver.RebalanceRepos(rebalanceInterval)
 
 	port := "3178"
 	host := ""
@@ -220,6 +219,89 @@ func main() {
 	gitserver.Stop()
 }
 
+// partialCloneRulesTTL is how long the "partialClone" setting of a code host
+// connection is cached.
+const partialCloneRulesTTL = time.Minute
+
+// partialCloneRulesCache caches the "partialClone" setting of code host
+// connections by external service ID, so that looking up the syncer of a git
+// repository doesn't read and parse its code host connection every time.
+type partialCloneRulesCache struct {
+	mu      sync.Mutex
+	entries map[int64]partialCloneRulesEntry
+}
+
+type partialCloneRulesEntry struct {
+	rules   []*schema.PartialCloneRule
+	expires time.Time
+}
+
+// options returns the partial clone options for repo configured in the
+// "partialClone" setting of the code host connection it comes from.
+func (c *partialCloneRulesCache) options(ctx context.Context, externalServiceStore *database.ExternalServiceStore, repo *types.Repo) (*server.PartialCloneOptions, error) {
+	for _, info := range repo.Sources {
+		rules, err := c.rules(ctx, externalServiceStore, info.ExternalServiceID())
+		if err != nil {
+			return nil, err
+		}
+		return server.PartialCloneOptionsForRepo(rules, repo.Name)
+	}
+	return nil, nil
+}
+
+func (c *partialCloneRulesCache) rules(ctx context.Context, externalServiceStore *database.ExternalServiceStore, id int64) ([]*schema.PartialCloneRule, error) {
+	c.mu.Lock()
+	e, ok := c.entries[id]
+	c.mu.Unlock()
+	if ok && time.Now().Before(e.expires) {
+		return e.rules, nil
+	}
+
+	var config struct {
+		PartialClone []*schema.PartialCloneRule `json:"partialClone"`
+	}
+	if err := unmarshalExternalServiceConfig(ctx, externalServiceStore, id, &config); err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	if c.entries == nil {
+		c.entries = map[int64]partialCloneRulesEntry{}
+	}
+	c.entries[id] = partialCloneRulesEntry{rules: config.PartialClone, expires: time.Now().Add(partialCloneRulesTTL)}
+	c.mu.Unlock()
+	return config.PartialClone, nil
+}
+
+// unmarshalSourceConfig unmarshals the config of the first code host
+// connection repo comes from into config. config is left unchanged if repo
+// has no sources.
+func unmarshalSourceConfig(ctx context.Context, externalServiceStore *database.ExternalServiceStore, repo *types.Repo, config interface{}) error {
+	for _, info := range repo.Sources {
+		return unmarshalExternalServiceConfig(ctx, externalServiceStore, info.ExternalServiceID(), config)
+	}
+	return nil
+}
+
+// unmarshalExternalServiceConfig unmarshals the config of the external
+// service with the given ID into config.
+func unmarshalExternalServiceConfig(ctx context.Context, externalServiceStore *database.ExternalServiceStore, id int64, config interface{}) error {
+	es, err := externalServiceStore.GetByID(ctx, id)
+	if err != nil {
+		return errors.Wrap(err, "get external service")
+	}
+
+	normalized, err := jsonc.Parse(es.Config)
+	if err != nil {
+		return errors.Wrap(err, "normalize JSON")
+	}
+
+	if err = jsoniter.Unmarshal(normalized, config); err != nil {
+		return errors.Wrap(err, "unmarshal JSON")
+	}
+	return nil
+}
+
 func getPercent(p int) (int, error) {
 	if p < 0 {
 		return 0, errors.Errorf("negative value given for percentage: %d", p)
@@ -81,8 +81,9 @@ const reposStatsName = "repos-stats.json"
 // 4. Ensure correct git attributes
 // 5. Scrub remote URLs
 // 6. Perform garbage collection
-// 7. Re-clone repos after a while. (simulate git gc)
-// 8. Remove repos based on disk pressure.
+// 7. Perform maintenance based on pack and loose object counts
+// 8. Re-clone repos after a while. (simulate git gc)
+// 9. Remove repos based on disk pressure.
 func (s *Server) cleanupRepos() {
 	janitorRunning.Set(1)
 	defer janitorRunning.Set(0)
@@ -108,7 +109,7 @@ func (s *Server) cleanupRepos() {
 		}
 
 		log15.Info("removing corrupt repo", "repo", dir)
-		if err := s.removeRepoDirectory(dir); err != nil {
+		if err := s.removeRepoDirectory(dir, true); err != nil {
 			return true, err
 		}
 		reposRemoved.Inc()
@@ -121,6 +122,10 @@ func (s *Server) cleanupRepos() {
 
 	scrubRemoteURL := func(dir GitDir) (done bool, err error) {
 		cmd := exec.Command("git", "remote", "remove", "origin")
+		if isPartialClone(dir) {
+			// Partial clones need the rest of their promisor remote config.
+			cmd = exec.Command("git", "config", "--unset", "remote."+promisorRemote+".url")
+		}
 		dir.Set(cmd)
 		// ignore error since we fail if the remote has already been scrubbed.
 		_ = cmd.Run()
@@ -221,12 +226,20 @@ func (s *Server) cleanupRepos() {
 	}
 
 	performGC := func(dir GitDir) (done bool, err error) {
-		if !enableGCAuto {
+		// Maintenance replaces git gc.
+		if !enableGCAuto || enableMaintenance {
 			return false, nil
 		}
 		return false, gitGC(dir)
 	}
 
+	performMaintenance := func(dir GitDir) (done bool, err error) {
+		if !enableMaintenance {
+			return false, nil
+		}
+		return false, maintainRepo(dir)
+	}
+
 	type cleanupFn struct {
 		Name string
 		Do   func(GitDir) (bool, error)
@@ -250,6 +263,11 @@ func (s *Server) cleanupRepos() {
 		// invocations of git add, packing refs, pruning reflog, rerere metadata or stale
 		// working trees. May also update ancillary indexes such as the commit-graph.
 		{"garbage collect", performGC},
+		// Writes commit-graphs and multi-pack-indexes, and incrementally repacks
+		// repositories once they have too many packfiles or loose objects. Unlike git
+		// gc, this never rewrites the whole repository, which is too slow for large
+		// repositories.
+		{"maintenance", performMaintenance},
 	}
 
 	if !conf.Get().DisableAutoGitUpdates {
@@ -404,7 +422,7 @@ func (s *Server) freeUpSpace(howManyBytesToFree int64) error {
 			return nil
 		}
 		delta := dirSize(d.Path("."))
-		if err := s.removeRepoDirectory(d); err != nil {
+		if err := s.removeRepoDirectory(d, true); err != nil {
 			return errors.Wrap(err, "removing repo directory")
 		}
 		spaceFreed += delta
@@ -484,7 +502,11 @@ func dirSize(d string) int64 {
 // the directory.
 //
 // Additionally it removes parent empty directories up until s.ReposDir.
-func (s *Server) removeRepoDirectory(gitDir GitDir) error {
+//
+// If updateCloneStatus is set, the repository is marked as not cloned in the
+// database. It is unset when the repository has been moved to another
+// gitserver instance, which then owns its database row.
+func (s *Server) removeRepoDirectory(gitDir GitDir, updateCloneStatus bool) error {
 	ctx := context.Background()
 	dir := string(gitDir)
 
@@ -502,7 +524,9 @@ func (s *Server) removeRepoDirectory(gitDir GitDir) error {
 	// should not be returned, just logged.
 
 	// Set as not_cloned in the database
-	s.setCloneStatusNonFatal(ctx, s.name(gitDir), types.CloneStatusNotCloned)
+	if updateCloneStatus {
+		s.setCloneStatusNonFatal(ctx, s.name(gitDir), types.CloneStatusNotCloned)
+	}
 
 	// Cleanup empty parent directories. We just attempt to remove and if we
 	// have a failure we assume it's due to the directory having other
@@ -124,6 +124,11 @@ func TestCleanupInactive(t *testing.T) {
 // They are stable today, but may become flaky in the future if/when the
 // relevant internal magic numbers and transformations change.
 func TestGitGCAuto(t *testing.T) {
+	// git gc is only run if maintenance is disabled.
+	old := enableMaintenance
+	enableMaintenance = false
+	t.Cleanup(func() { enableMaintenance = old })
+
 	// Create a test repository with detectable garbage that GC can prune.
 	root := t.TempDir()
 	repo := filepath.Join(root, "garbage-repo")
@@ -519,7 +524,7 @@ func TestRemoveRepoDirectory(t *testing.T) {
 		"github.com/bam/bam/.git",
 		"example.com/repo/.git",
 	} {
-		if err := s.removeRepoDirectory(GitDir(filepath.Join(root, d))); err != nil {
+		if err := s.removeRepoDirectory(GitDir(filepath.Join(root, d)), true); err != nil {
 			t.Fatalf("failed to remove %s: %s", d, err)
 		}
 	}
@@ -562,7 +567,7 @@ func TestRemoveRepoDirectory_Empty(t *testing.T) {
 		ReposDir: root,
 	}
 
-	if err := s.removeRepoDirectory(GitDir(filepath.Join(root, "github.com/foo/baz/.git"))); err != nil {
+	if err := s.removeRepoDirectory(GitDir(filepath.Join(root, "github.com/foo/baz/.git")), true); err != nil {
 		t.Fatal(err)
 	}
 
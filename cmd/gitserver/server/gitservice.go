@@ -35,7 +35,15 @@ func flowrateWriter(w io.Writer) io.Writer {
 func (s *Server) gitServiceHandler() *gitservice.Handler {
 	return &gitservice.Handler{
 		Dir: func(d string) string {
-			return string(s.dir(api.RepoName(d)))
+			dir := s.dir(api.RepoName(d))
+			// Partial clones can't serve the objects they are missing,
+			// since the URL of their promisor remote is not stored in the
+			// clone. Reporting them as not found makes other instances clone
+			// them from the code host instead.
+			if isPartialClone(dir) {
+				return ""
+			}
+			return string(dir)
 		},
 
 		// Limit rate of stdout from git.
@@ -0,0 +1,321 @@
+package server
+
+import (
+	"encoding/json"
+	"io/fs"
+	"os"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/inconshreveable/log15"
+
+	"github.com/sourcegraph/sourcegraph/internal/env"
+)
+
+var (
+	// enableMaintenance controls whether the janitor maintains repositories
+	// based on their pack and loose object counts. If it is set, `git gc` is
+	// not run by the janitor. Unreachable objects which were already packed
+	// are then only dropped by the periodic reclone.
+	enableMaintenance, _ = strconv.ParseBool(env.Get("SRC_ENABLE_REPO_MAINTENANCE", "false", "Maintain repositories with incremental repacks, multi-pack-indexes and commit-graphs instead of git-gc during janitorial cleanup phases"))
+
+	maintenanceMaxPacks        = env.MustGetInt("SRC_REPO_MAINTENANCE_MAX_PACKS", 16, "Number of packfiles in a repository above which it is incrementally repacked")
+	maintenanceMaxLooseObjects = env.MustGetInt("SRC_REPO_MAINTENANCE_MAX_LOOSE_OBJECTS", 1024, "Estimated number of loose objects in a repository above which they are packed")
+	maintenanceMaxLooseRefs    = env.MustGetInt("SRC_REPO_MAINTENANCE_MAX_LOOSE_REFS", 1024, "Number of loose refs in a repository above which they are packed")
+)
+
+// maintenanceStateName is the name of the file in a GIT_DIR which records
+// the state of the last maintenance run.
+const maintenanceStateName = "sg_maintenance.json"
+
+// maintenanceStats describes the on-disk state of a repository that decides
+// which maintenance tasks to run.
+type maintenanceStats struct {
+	// PackSizes are the sizes in bytes of the packfiles.
+	PackSizes []int64
+	// LooseObjects is an estimate of the number of loose objects.
+	LooseObjects int
+	// LooseRefs is the number of refs which are not in packed-refs.
+	LooseRefs int
+	// CommitGraphStale is true if there is no commit-graph or the refs
+	// changed since it was written.
+	CommitGraphStale bool
+	// MultiPackIndexStale is true if there is no multi-pack-index or a
+	// packfile was written after it.
+	MultiPackIndexStale bool
+}
+
+// maintenanceTask is a named list of git commands that are run in order.
+type maintenanceTask struct {
+	Name     string
+	Commands [][]string
+}
+
+// maintenanceState is the state of the last maintenance run, which is
+// recorded in maintenanceStateName.
+type maintenanceState struct {
+	LastRun      time.Time
+	Tasks        []string
+	Packs        int
+	LooseObjects int
+	LooseRefs    int
+	Error        string `json:",omitempty"`
+}
+
+// looseObjectHashRe matches the file names of loose objects within one of
+// the objects/?? fan-out directories.
+var looseObjectHashRe = regexp.MustCompile(`^[0-9a-f]{38}$`)
+
+// computeMaintenanceStats inspects dir. It only reads directory listings and
+// file metadata, so it is cheap enough to run on every janitor run.
+func computeMaintenanceStats(dir GitDir) (maintenanceStats, error) {
+	var stats maintenanceStats
+
+	packs, err := os.ReadDir(dir.Path("objects", "pack"))
+	if err != nil && !os.IsNotExist(err) {
+		return stats, err
+	}
+	var newestPack time.Time
+	for _, e := range packs {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".pack") {
+			continue
+		}
+		fi, err := e.Info()
+		if err != nil {
+			continue
+		}
+		stats.PackSizes = append(stats.PackSizes, fi.Size())
+		if fi.ModTime().After(newestPack) {
+			newestPack = fi.ModTime()
+		}
+	}
+
+	// Like git gc --auto, we estimate the number of loose objects by
+	// counting them in a single fan-out directory.
+	loose, err := os.ReadDir(dir.Path("objects", "17"))
+	if err != nil && !os.IsNotExist(err) {
+		return stats, err
+	}
+	for _, e := range loose {
+		if looseObjectHashRe.MatchString(e.Name()) {
+			stats.LooseObjects += 256
+		}
+	}
+
+	_ = bestEffortWalk(dir.Path("refs"), func(path string, fi fs.FileInfo) error {
+		if !fi.IsDir() && !strings.HasSuffix(path, ".lock") {
+			stats.LooseRefs++
+		}
+		return nil
+	})
+
+	// sg_refhash is updated whenever a fetch changes the refs.
+	refsChanged := modTime(dir.Path("sg_refhash"))
+	commitGraph := modTime(dir.Path("objects", "info", "commit-graphs", "commit-graph-chain"))
+	if t := modTime(dir.Path("objects", "info", "commit-graph")); t.After(commitGraph) {
+		commitGraph = t
+	}
+	stats.CommitGraphStale = commitGraph.IsZero() || refsChanged.After(commitGraph)
+
+	midx := modTime(dir.Path("objects", "pack", "multi-pack-index"))
+	stats.MultiPackIndexStale = midx.IsZero() || newestPack.After(midx)
+
+	return stats, nil
+}
+
+// modTime returns the modification time of path, or the zero time if it
+// does not exist.
+func modTime(path string) time.Time {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}
+	}
+	return fi.ModTime()
+}
+
+// maintenanceTasks returns the tasks to run for a repository with the given
+// stats. Expensive tasks are only run once the number of packs, loose objects
+// or loose refs crosses a threshold, rather than on a schedule. Tasks which
+// need a newer version of git than is installed are skipped.
+func maintenanceTasks(stats maintenanceStats, gitVersionAtLeast func(major, minor int) bool) []maintenanceTask {
+	var tasks []maintenanceTask
+
+	if stats.LooseObjects > maintenanceMaxLooseObjects {
+		tasks = append(tasks, maintenanceTask{
+			Name: "loose-objects",
+			Commands: [][]string{
+				// Pack reachable loose objects into a new pack and remove them.
+				{"repack", "-d", "-q"},
+				// Remove unreachable loose objects, with the same grace
+				// period as git gc.
+				{"prune", "--expire=2.weeks.ago"},
+			},
+		})
+	}
+
+	midxWrite := []string{"multi-pack-index", "write"}
+	if gitVersionAtLeast(2, 34) {
+		midxWrite = append(midxWrite, "--bitmap")
+	}
+
+	repacked := false
+	if len(stats.PackSizes) > maintenanceMaxPacks && gitVersionAtLeast(2, 25) {
+		// Repack every pack but the largest into a single pack. git only
+		// repacks packs smaller than the batch size, and stops once it has
+		// collected the batch size, so this batch size avoids rewriting the
+		// largest pack if it holds most of the repository. That is typically
+		// the case for large repositories, which only accumulate small packs
+		// from fetches after the initial clone.
+		sizes := append([]int64(nil), stats.PackSizes...)
+		sort.Slice(sizes, func(i, j int) bool { return sizes[i] > sizes[j] })
+		var batchSize int64
+		for _, size := range sizes[1:] {
+			batchSize += size
+		}
+		tasks = append(tasks, maintenanceTask{
+			Name: "incremental-repack",
+			Commands: [][]string{
+				midxWrite,
+				{"multi-pack-index", "expire"},
+				{"multi-pack-index", "repack", "--batch-size=" + strconv.FormatInt(batchSize, 10)},
+				// Remove the packs that were just repacked, and index the
+				// new one.
+				{"multi-pack-index", "expire"},
+				midxWrite,
+			},
+		})
+		repacked = true
+	}
+
+	if !repacked && stats.MultiPackIndexStale && len(stats.PackSizes) > 1 && gitVersionAtLeast(2, 21) {
+		tasks = append(tasks, maintenanceTask{
+			Name:     "multi-pack-index",
+			Commands: [][]string{midxWrite},
+		})
+	}
+
+	// There is nothing to write a commit-graph for until the repository has
+	// been fetched into.
+	if stats.CommitGraphStale && len(stats.PackSizes) > 0 && gitVersionAtLeast(2, 24) {
+		// A split commit-graph only writes the commits added since the
+		// last write, and merges layers as needed.
+		write := []string{"commit-graph", "write", "--reachable", "--split"}
+		if gitVersionAtLeast(2, 27) {
+			write = append(write, "--changed-paths")
+		}
+		tasks = append(tasks, maintenanceTask{
+			Name:     "commit-graph",
+			Commands: [][]string{write},
+		})
+	}
+
+	if stats.LooseRefs > maintenanceMaxLooseRefs {
+		tasks = append(tasks, maintenanceTask{
+			Name:     "pack-refs",
+			Commands: [][]string{{"pack-refs", "--all", "--prune"}},
+		})
+	}
+
+	return tasks
+}
+
+// maintainRepo runs the maintenance tasks dir needs, and records the run in
+// maintenanceStateName if any task ran.
+func maintainRepo(dir GitDir) error {
+	stats, err := computeMaintenanceStats(dir)
+	if err != nil {
+		return errors.Wrap(err, "computing maintenance stats")
+	}
+	maintenanceRepoPacks.Observe(float64(len(stats.PackSizes)))
+	maintenanceRepoLooseObjects.Observe(float64(stats.LooseObjects))
+
+	tasks := maintenanceTasks(stats, gitVersionAtLeast)
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	state := maintenanceState{
+		LastRun:      time.Now(),
+		Packs:        len(stats.PackSizes),
+		LooseObjects: stats.LooseObjects,
+		LooseRefs:    stats.LooseRefs,
+	}
+
+	var runErr error
+	for _, task := range tasks {
+		state.Tasks = append(state.Tasks, task.Name)
+
+		start := time.Now()
+		runErr = runMaintenanceTask(dir, task)
+		maintenanceTaskDuration.WithLabelValues(task.Name, strconv.FormatBool(runErr == nil)).Observe(time.Since(start).Seconds())
+		if runErr != nil {
+			state.Error = runErr.Error()
+			break
+		}
+	}
+
+	if err := writeMaintenanceState(dir, state); err != nil {
+		log15.Warn("failed to record maintenance state", "repo", dir, "error", err)
+	}
+	return runErr
+}
+
+func runMaintenanceTask(dir GitDir, task maintenanceTask) error {
+	for _, args := range task.Commands {
+		cmd := exec.Command("git", args...)
+		dir.Set(cmd)
+		if _, err := cmd.Output(); err != nil {
+			return errors.Wrapf(wrapCmdError(cmd, err), "maintenance task %s failed", task.Name)
+		}
+	}
+	return nil
+}
+
+func writeMaintenanceState(dir GitDir, state maintenanceState) error {
+	b, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	_, err = updateFileIfDifferent(dir.Path(maintenanceStateName), b)
+	return err
+}
+
+var (
+	gitVersionOnce sync.Once
+	gitVersion     [2]int
+)
+
+// gitVersionAtLeast reports whether the installed git is at least
+// major.minor. If the version cannot be determined, it is assumed to be too
+// old.
+func gitVersionAtLeast(major, minor int) bool {
+	gitVersionOnce.Do(func() {
+		out, err := exec.Command("git", "version").Output()
+		if err != nil {
+			log15.Warn("failed to determine git version", "error", err)
+			return
+		}
+		gitVersion = parseGitVersion(string(out))
+	})
+	return gitVersion[0] > major || (gitVersion[0] == major && gitVersion[1] >= minor)
+}
+
+var gitVersionRe = regexp.MustCompile(`^git version (\d+)\.(\d+)`)
+
+// parseGitVersion parses the major and minor version from the output of
+// `git version`, e.g. "git version 2.33.1".
+func parseGitVersion(out string) (v [2]int) {
+	m := gitVersionRe.FindStringSubmatch(strings.TrimSpace(out))
+	if m == nil {
+		return v
+	}
+	v[0], _ = strconv.Atoi(m[1])
+	v[1], _ = strconv.Atoi(m[2])
+	return v
+}
@@ -0,0 +1,161 @@
+package server
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestMaintenanceTasks(t *testing.T) {
+	newGit := func(major, minor int) bool { return true }
+	oldGit := func(major, minor int) bool { return major < 2 || (major == 2 && minor < 21) }
+
+	taskNames := func(tasks []maintenanceTask) []string {
+		var names []string
+		for _, task := range tasks {
+			names = append(names, task.Name)
+		}
+		return names
+	}
+
+	manyPacks := make([]int64, maintenanceMaxPacks+1)
+	for i := range manyPacks {
+		manyPacks[i] = int64(i + 1)
+	}
+
+	for _, tc := range []struct {
+		name  string
+		stats maintenanceStats
+		git   func(major, minor int) bool
+		want  []string
+	}{{
+		name:  "up to date",
+		stats: maintenanceStats{PackSizes: []int64{10, 20}},
+		git:   newGit,
+	}, {
+		name:  "single pack without commit-graph",
+		stats: maintenanceStats{PackSizes: []int64{10}, CommitGraphStale: true, MultiPackIndexStale: true},
+		git:   newGit,
+		want:  []string{"commit-graph"},
+	}, {
+		name:  "empty repository",
+		stats: maintenanceStats{CommitGraphStale: true, MultiPackIndexStale: true},
+		git:   newGit,
+	}, {
+		name:  "stale multi-pack-index",
+		stats: maintenanceStats{PackSizes: []int64{10, 20}, MultiPackIndexStale: true},
+		git:   newGit,
+		want:  []string{"multi-pack-index"},
+	}, {
+		name:  "too many packs",
+		stats: maintenanceStats{PackSizes: manyPacks, MultiPackIndexStale: true},
+		git:   newGit,
+		want:  []string{"incremental-repack"},
+	}, {
+		name:  "too many loose objects and refs",
+		stats: maintenanceStats{PackSizes: []int64{10}, LooseObjects: maintenanceMaxLooseObjects + 1, LooseRefs: maintenanceMaxLooseRefs + 1},
+		git:   newGit,
+		want:  []string{"loose-objects", "pack-refs"},
+	}, {
+		name:  "old git",
+		stats: maintenanceStats{PackSizes: manyPacks, CommitGraphStale: true, MultiPackIndexStale: true},
+		git:   oldGit,
+	}} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := taskNames(maintenanceTasks(tc.stats, tc.git)); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got tasks %v, want %v", got, tc.want)
+			}
+		})
+	}
+
+	// The batch size covers every pack but the largest.
+	tasks := maintenanceTasks(maintenanceStats{PackSizes: manyPacks}, newGit)
+	n := len(manyPacks)
+	want := "--batch-size=" + strconv.Itoa(n*(n+1)/2-n)
+	if got := tasks[0].Commands[2][2]; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestParseGitVersion(t *testing.T) {
+	for out, want := range map[string][2]int{
+		"git version 2.33.1\n":                 {2, 33},
+		"git version 2.30.1 (Apple Git-130)\n": {2, 30},
+		"git version 2.26.3.windows.1":         {2, 26},
+		"not git":                              {0, 0},
+	} {
+		if got := parseGitVersion(out); got != want {
+			t.Errorf("parseGitVersion(%q) = %v, want %v", out, got, want)
+		}
+	}
+}
+
+func TestMaintainRepo(t *testing.T) {
+	if !gitVersionAtLeast(2, 34) {
+		t.Skip("git 2.34 or later is required")
+	}
+
+	root := t.TempDir()
+	repo := filepath.Join(root, "repo")
+	runCmd(t, root, "git", "init", repo)
+
+	// Each commit followed by a repack of the new objects results in a new
+	// packfile.
+	for i := 0; i < maintenanceMaxPacks+2; i++ {
+		runCmd(t, repo, "sh", "-c", "echo "+strconv.Itoa(i)+" >> file")
+		runCmd(t, repo, "git", "add", "file")
+		runCmd(t, repo, "git", "commit", "-m", "commit "+strconv.Itoa(i))
+		runCmd(t, repo, "git", "repack", "-d", "-q")
+	}
+
+	dir := GitDir(filepath.Join(repo, ".git"))
+	stats, err := computeMaintenanceStats(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stats.PackSizes) <= maintenanceMaxPacks {
+		t.Fatalf("expected more than %d packs, got %d", maintenanceMaxPacks, len(stats.PackSizes))
+	}
+	if !stats.CommitGraphStale || !stats.MultiPackIndexStale {
+		t.Fatalf("expected commit-graph and multi-pack-index to be stale: %+v", stats)
+	}
+
+	if err := maintainRepo(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	stats, err = computeMaintenanceStats(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stats.PackSizes) > 2 {
+		t.Errorf("expected at most 2 packs after maintenance, got %d", len(stats.PackSizes))
+	}
+	if stats.CommitGraphStale {
+		t.Error("expected commit-graph to be written")
+	}
+	if _, err := os.Stat(dir.Path("objects", "pack", "multi-pack-index")); err != nil {
+		t.Errorf("expected multi-pack-index to be written: %v", err)
+	}
+	runCmd(t, repo, "git", "fsck")
+
+	b, err := os.ReadFile(dir.Path(maintenanceStateName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var state maintenanceState
+	if err := json.Unmarshal(b, &state); err != nil {
+		t.Fatal(err)
+	}
+	if state.Error != "" || !reflect.DeepEqual(state.Tasks, []string{"incremental-repack", "commit-graph"}) {
+		t.Fatalf("unexpected maintenance state %+v", state)
+	}
+
+	// Nothing is left to do.
+	if got := maintenanceTasks(stats, gitVersionAtLeast); len(got) != 0 {
+		t.Errorf("expected no tasks, got %+v", got)
+	}
+}
@@ -0,0 +1,220 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/vcs"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// PartialCloneOptions describes how to partially clone a repository.
+type PartialCloneOptions struct {
+	// Filter is the object filter passed to git fetch, e.g. "blob:none" or
+	// "blob:limit=1m".
+	Filter string
+	// ExcludePaths are paths in the repository whose files are left out of
+	// archives, so their blobs are never fetched for searching.
+	ExcludePaths []string
+}
+
+// PartialCloneOptionsForRepo returns the options of the first of rules whose
+// pattern matches repo, or nil if repo should be cloned in full.
+func PartialCloneOptionsForRepo(rules []*schema.PartialCloneRule, repo api.RepoName) (*PartialCloneOptions, error) {
+	for _, rule := range rules {
+		re, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid partialClone pattern %q", rule.Pattern)
+		}
+		if !re.MatchString(string(repo)) {
+			continue
+		}
+
+		opts := &PartialCloneOptions{
+			Filter:       "blob:none",
+			ExcludePaths: rule.ExcludePaths,
+		}
+		if rule.BlobSizeLimit != "" {
+			opts.Filter = "blob:limit=" + rule.BlobSizeLimit
+		}
+		return opts, nil
+	}
+	return nil, nil
+}
+
+// promisorRemote is the name of the remote partial clones fetch missing
+// objects from. Its URL is not stored in the repository, since it may contain
+// credentials. Instead it is passed to every command which may need to fetch
+// objects, see promisorRemoteEnv.
+const promisorRemote = "origin"
+
+// configurePartialClone turns the empty repository in dir into a partial clone
+// which fetches with filter.
+func configurePartialClone(dir GitDir, filter string) error {
+	for _, kv := range [][2]string{
+		{"core.repositoryformatversion", "1"},
+		{"extensions.partialClone", promisorRemote},
+		{"remote." + promisorRemote + ".promisor", "true"},
+		{"remote." + promisorRemote + ".partialclonefilter", filter},
+	} {
+		if err := gitConfigSet(dir, kv[0], kv[1]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isPartialClone returns true if dir may be missing objects which have to be
+// fetched from its promisor remote. It only looks for promisor packs, which
+// is cheap enough to do on every exec.
+func isPartialClone(dir GitDir) bool {
+	matches, _ := filepath.Glob(dir.Path("objects", "pack", "*.promisor"))
+	return len(matches) > 0
+}
+
+// promisorRemoteEnv returns the environment variable which configures the URL
+// of the promisor remote for a git command. It is passed in the environment
+// rather than as an argument, so that it is inherited by the git fetch git
+// runs to fetch missing objects, and is not visible in the process list.
+func promisorRemoteEnv(remoteURL *vcs.URL) string {
+	return "GIT_CONFIG_PARAMETERS=" + shellQuote("remote."+promisorRemote+".url="+remoteURL.String())
+}
+
+// shellQuote quotes s the way git quotes values in GIT_CONFIG_PARAMETERS.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// partialFetchCommand returns the command which fetches updates of a partial
+// clone from remoteURL. Unless args overrides it, the filter the repository
+// was cloned with is used.
+func partialFetchCommand(ctx context.Context, remoteURL *vcs.URL, args ...string) *exec.Cmd {
+	args = append([]string{"fetch", "--progress", "--prune"}, args...)
+	args = append(args, promisorRemote)
+	args = append(args, fetchRefspecs...)
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Env = append(os.Environ(), promisorRemoteEnv(remoteURL))
+	return cmd
+}
+
+// configurePromisorRemote configures cmd, which runs in a partial clone, to
+// fetch missing objects on demand from remoteURL.
+func configurePromisorRemote(cmd *exec.Cmd, remoteURL *vcs.URL) {
+	if cmd.Env == nil {
+		cmd.Env = os.Environ()
+	}
+	cmd.Env = append(cmd.Env, promisorRemoteEnv(remoteURL))
+	configureRemoteGitCommand(cmd, tlsExternal().(*tlsConfig))
+}
+
+// partialCloneCacheTTL is how long the partial clone state of a repository is
+// cached. It bounds how long changes to the remote URL or to the partialClone
+// setting of a code host connection take to apply to archives and execs.
+const partialCloneCacheTTL = time.Minute
+
+// partialCloneState is what gitserver needs to serve requests for a partial
+// clone.
+type partialCloneState struct {
+	// remoteURL is the URL missing objects are fetched from.
+	remoteURL *vcs.URL
+	// excludePaths are left out of archives.
+	excludePaths []string
+
+	expires time.Time
+}
+
+// partialCloneState returns the remote URL and excluded paths of the partial
+// clone of repo. They are cached for partialCloneCacheTTL, so that requests
+// for a partial clone don't each look up its remote URL and code host
+// connection.
+func (s *Server) partialCloneState(ctx context.Context, repo api.RepoName) (*partialCloneState, error) {
+	if v, ok := s.partialCloneCache.Load(repo); ok {
+		if state := v.(*partialCloneState); time.Now().Before(state.expires) {
+			return state, nil
+		}
+	}
+
+	remoteURL, err := s.getRemoteURL(ctx, repo)
+	if err != nil {
+		return nil, errors.Wrap(err, "get remote URL")
+	}
+	state := &partialCloneState{
+		remoteURL: remoteURL,
+		expires:   time.Now().Add(partialCloneCacheTTL),
+	}
+	if s.GetVCSSyncer != nil {
+		syncer, err := s.GetVCSSyncer(ctx, repo)
+		if err != nil {
+			return nil, errors.Wrap(err, "get VCS syncer")
+		}
+		if gs, ok := syncer.(*GitRepoSyncer); ok && gs.PartialClone != nil {
+			state.excludePaths = gs.PartialClone.ExcludePaths
+		}
+	}
+	s.partialCloneCache.Store(repo, state)
+	return state, nil
+}
+
+// excludePathspecs returns the pathspecs which exclude paths.
+func excludePathspecs(paths []string) []string {
+	pathspecs := make([]string, 0, len(paths))
+	for _, p := range paths {
+		pathspecs = append(pathspecs, ":(exclude,literal)"+strings.Trim(p, "/"))
+	}
+	return pathspecs
+}
+
+// prefetchBatchSize is the maximum number of objects prefetchBlobs requests
+// in a single git fetch.
+const prefetchBatchSize = 1000
+
+// prefetchBlobs fetches the blobs below paths in treeish which are missing
+// from the partial clone in dir, except for those below excludePaths. Without
+// this, git fetches them one by one when a command like git archive reads
+// them. Only the trees below paths are walked, so prefetching for an archive
+// of a few files is cheap even in a large repository.
+func prefetchBlobs(ctx context.Context, dir GitDir, remoteURL *vcs.URL, treeish string, paths, excludePaths []string) error {
+	// List missing objects without fetching them. Trees are never filtered
+	// out, so all missing objects are blobs.
+	args := []string{"rev-list", "--objects", "--missing=print", treeish + "^{tree}", "--"}
+	args = append(args, paths...)
+	args = append(args, excludePathspecs(excludePaths)...)
+	cmd := exec.CommandContext(ctx, "git", args...)
+	dir.Set(cmd)
+	out, err := cmd.Output()
+	if err != nil {
+		return errors.Wrap(wrapCmdError(cmd, err), "list missing objects")
+	}
+	var want []string
+	for _, line := range bytes.Split(out, []byte("\n")) {
+		if len(line) > 1 && line[0] == '?' {
+			want = append(want, string(line[1:]))
+		}
+	}
+
+	for len(want) > 0 {
+		n := len(want)
+		if n > prefetchBatchSize {
+			n = prefetchBatchSize
+		}
+		// This mirrors how git fetches missing objects itself.
+		args := append([]string{"-c", "fetch.negotiationAlgorithm=noop", "fetch", promisorRemote, "--no-tags", "--recurse-submodules=no", "--filter=blob:none"}, want[:n]...)
+		cmd := exec.CommandContext(ctx, "git", args...)
+		dir.Set(cmd)
+		configurePromisorRemote(cmd, remoteURL)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			return errors.Wrapf(err, "fetch missing blobs with output %q", newURLRedactor(remoteURL).redact(string(out)))
+		}
+		want = want[n:]
+	}
+	return nil
+}
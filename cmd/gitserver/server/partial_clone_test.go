@@ -0,0 +1,173 @@
+package server
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+	"github.com/sourcegraph/sourcegraph/internal/vcs"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestPartialCloneOptionsForRepo(t *testing.T) {
+	rules := []*schema.PartialCloneRule{
+		{Pattern: `^github\.com/foo/big$`, BlobSizeLimit: "1m", ExcludePaths: []string{"vendor"}},
+		{Pattern: `^github\.com/foo/`},
+	}
+
+	for repo, want := range map[api.RepoName]*PartialCloneOptions{
+		"github.com/foo/big":   {Filter: "blob:limit=1m", ExcludePaths: []string{"vendor"}},
+		"github.com/foo/small": {Filter: "blob:none"},
+		"github.com/bar/baz":   nil,
+	} {
+		got, err := PartialCloneOptionsForRepo(rules, repo)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("%s: unexpected options (-want +got):\n%s", repo, diff)
+		}
+	}
+
+	if _, err := PartialCloneOptionsForRepo([]*schema.PartialCloneRule{{Pattern: "("}}, "github.com/foo/big"); err == nil {
+		t.Error("expected error for invalid pattern")
+	}
+}
+
+func TestPartialClone(t *testing.T) {
+	ctx := context.Background()
+	repoName := api.RepoName("example.com/foo/big")
+
+	remote := t.TempDir()
+	remoteCmd := func(name string, arg ...string) string {
+		t.Helper()
+		return runCmd(t, remote, name, arg...)
+	}
+	remoteCmd("git", "init", ".")
+	// Serve partial clones like GitHub does.
+	remoteCmd("git", "config", "uploadpack.allowFilter", "true")
+	remoteCmd("git", "config", "uploadpack.allowAnySHA1InWant", "true")
+	remoteCmd("mkdir", "vendor")
+	remoteCmd("sh", "-c", "head -c 100000 /dev/urandom > big.bin")
+	remoteCmd("sh", "-c", "head -c 100000 /dev/urandom > vendor/big.bin")
+	remoteCmd("sh", "-c", "echo hello > small.txt")
+	remoteCmd("git", "add", ".")
+	remoteCmd("git", "commit", "-m", "initial")
+
+	s := makeTestServer(ctx, t.TempDir(), "file://"+remote, nil)
+	var syncerCalls int
+	s.GetVCSSyncer = func(ctx context.Context, name api.RepoName) (VCSSyncer, error) {
+		syncerCalls++
+		return &GitRepoSyncer{PartialClone: &PartialCloneOptions{
+			Filter:       "blob:limit=1k",
+			ExcludePaths: []string{"vendor/"},
+		}}, nil
+	}
+	if _, err := s.cloneRepo(ctx, repoName, &cloneOptions{Block: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	dir := s.dir(repoName)
+	if !isPartialClone(dir) {
+		t.Fatal("expected a partial clone")
+	}
+	missingBlobs := func() int {
+		t.Helper()
+		out := runCmd(t, string(dir), "git", "rev-list", "--objects", "--all", "--missing=print")
+		return strings.Count(out, "\n?")
+	}
+	if got := missingBlobs(); got != 2 {
+		t.Fatalf("expected 2 missing blobs after clone, got %d", got)
+	}
+
+	archive := func(query string) []string {
+		t.Helper()
+		w := httptest.NewRecorder()
+		s.handleArchive(w, httptest.NewRequest("GET", "/archive?repo="+string(repoName)+"&treeish=HEAD&format=tar"+query, nil))
+		if w.Code != http.StatusOK || w.Result().Trailer.Get("X-Exec-Exit-Status") != "0" {
+			t.Fatalf("archive failed with status %d: %s", w.Code, w.Result().Trailer.Get("X-Exec-Stderr"))
+		}
+		var names []string
+		tr := tar.NewReader(w.Body)
+		for {
+			hdr, err := tr.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if hdr.Typeflag == tar.TypeReg {
+				names = append(names, hdr.Name)
+			}
+		}
+		sort.Strings(names)
+		return names
+	}
+
+	// Archives of some paths only fetch the blobs below them.
+	syncerCalls = 0
+	if diff := cmp.Diff([]string{"small.txt"}, archive("&path=small.txt")); diff != "" {
+		t.Fatalf("unexpected archive contents (-want +got):\n%s", diff)
+	}
+	if got := missingBlobs(); got != 2 {
+		t.Fatalf("expected 2 missing blobs after archive of small.txt, got %d", got)
+	}
+
+	// Archives leave out excluded paths, and only fetch the blobs they need.
+	if diff := cmp.Diff([]string{"big.bin", "small.txt"}, archive("")); diff != "" {
+		t.Fatalf("unexpected archive contents (-want +got):\n%s", diff)
+	}
+	if got := missingBlobs(); got != 1 {
+		t.Fatalf("expected 1 missing blob after archive, got %d", got)
+	}
+
+	// The partial clone options are cached between requests.
+	if syncerCalls != 1 {
+		t.Fatalf("expected the VCS syncer to be looked up once, got %d", syncerCalls)
+	}
+
+	// Other instances can't copy partial clones.
+	w := httptest.NewRecorder()
+	s.gitServiceHandler().ServeHTTP(w, httptest.NewRequest("GET", "/"+string(repoName)+"/info/refs?service=git-upload-pack", nil))
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected partial clone to not be served to other instances, got status %d", w.Code)
+	}
+
+	// Other commands fetch missing blobs on demand.
+	body, _ := json.Marshal(&protocol.ExecRequest{Repo: repoName, Args: []string{"cat-file", "-s", "HEAD:vendor/big.bin"}})
+	w = httptest.NewRecorder()
+	s.handleExec(w, httptest.NewRequest("POST", "/exec", bytes.NewReader(body)))
+	if got := strings.TrimSpace(w.Body.String()); got != "100000" {
+		t.Fatalf("unexpected output %q: %s", got, w.Result().Trailer.Get("X-Exec-Stderr"))
+	}
+
+	// Fetches keep the clone partial.
+	remoteCmd("sh", "-c", "head -c 100000 /dev/urandom > big.bin")
+	remoteCmd("git", "commit", "-am", "update")
+	remoteURL, err := vcs.ParseURL("file://" + remote)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := (&GitRepoSyncer{}).Fetch(ctx, remoteURL, dir); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := runCmd(t, filepath.Dir(string(dir)), "git", "--git-dir", string(dir), "rev-parse", "HEAD"), remoteCmd("git", "rev-parse", "HEAD"); got != want {
+		t.Fatalf("got HEAD %q, want %q", got, want)
+	}
+	if got := missingBlobs(); got != 1 {
+		t.Fatalf("expected 1 missing blob after fetch, got %d", got)
+	}
+}
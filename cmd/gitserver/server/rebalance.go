@@ -0,0 +1,190 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/inconshreveable/log15"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+var repoRebalanceCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "src_gitserver_repo_rebalanced",
+	Help: "Incremented each time we move a repository to the gitserver instance it is assigned to",
+}, []string{"success"})
+
+// handleRepoCopy copies a repository clone from another gitserver instance,
+// rather than cloning it from its code host. It blocks until the copy has
+// finished. Once the copy has succeeded, the repository is assigned to this
+// instance in the database.
+func (s *Server) handleRepoCopy(w http.ResponseWriter, r *http.Request) {
+	var req protocol.RepoCopyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if req.SourceAddr == "" {
+		http.Error(w, "SourceAddr must be non-empty", http.StatusBadRequest)
+		return
+	}
+	// Otherwise this could be used to make gitserver fetch from any host.
+	if !isGitserverAddr(req.SourceAddr, conf.Get().ServiceConnections.GitServers) {
+		http.Error(w, fmt.Sprintf("SourceAddr %q is not a gitserver instance", req.SourceAddr), http.StatusBadRequest)
+		return
+	}
+
+	dir := s.dir(req.Repo)
+	if repoCloned(dir) {
+		// We already have a clone, e.g. from before the repository was
+		// assigned to the source instance, which may be out of date. We
+		// update it before claiming the repository, after which the source
+		// instance removes its clone.
+		if err := s.updateFromPeer(r.Context(), req.Repo, req.SourceAddr, dir); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if err := s.setCloneStatus(r.Context(), req.Repo, types.CloneStatusCloned); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	if _, err := s.cloneRepo(r.Context(), req.Repo, &cloneOptions{Block: true, SourceAddr: req.SourceAddr}); err != nil {
+		// The source instance may be unable to serve the repository, e.g.
+		// because it is a partial clone. Cloning it from the code host
+		// instead keeps rebalancing from getting stuck on it.
+		log15.Warn("failed to copy repository, cloning it from the code host", "repo", req.Repo, "source", req.SourceAddr, "error", err)
+		if _, err := s.cloneRepo(r.Context(), req.Repo, &cloneOptions{Block: true}); err != nil {
+			log15.Error("failed to clone repository", "repo", req.Repo, "error", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	// cloneRepo returns early without an error if a clone is already in
+	// progress.
+	if !repoCloned(dir) {
+		http.Error(w, "clone in progress", http.StatusServiceUnavailable)
+		return
+	}
+}
+
+// isGitserverAddr returns true if addr is one of the gitserver addresses.
+func isGitserverAddr(addr string, addrs []string) bool {
+	for _, a := range addrs {
+		if a == addr {
+			return true
+		}
+	}
+	return false
+}
+
+// updateFromPeer fetches repo into its clone in dir from the gitserver
+// instance at sourceAddr, so that it is at least as up to date as the clone
+// there. Partial clones, and clones the source instance can't serve, are
+// updated from the code host instead.
+func (s *Server) updateFromPeer(ctx context.Context, repo api.RepoName, sourceAddr string, dir GitDir) error {
+	if !isPartialClone(dir) {
+		remoteURL, err := peerRemoteURL(sourceAddr, repo)
+		if err != nil {
+			return err
+		}
+		err = (&GitRepoSyncer{}).Fetch(ctx, remoteURL, dir)
+		if err == nil {
+			return nil
+		}
+		log15.Warn("failed to fetch repository from gitserver instance, updating it from the code host", "repo", repo, "source", sourceAddr, "error", err)
+	}
+	return s.doRepoUpdate(ctx, repo)
+}
+
+// RebalanceRepos moves repositories that are cloned on this instance but
+// assigned to another gitserver instance, e.g. because a gitserver instance
+// was added. It is expected to run in a background goroutine.
+func (s *Server) RebalanceRepos(interval time.Duration) {
+	if s.DisableRebalancing {
+		return
+	}
+	for {
+		addrs := conf.Get().ServiceConnections.GitServers
+		if err := s.rebalanceRepos(s.ctx, addrs, gitserver.DefaultClient.RequestRepoCopy); err != nil {
+			log15.Error("Rebalancing repos", "error", err)
+		}
+
+		time.Sleep(interval)
+	}
+}
+
+// rebalanceRepos asks the gitserver instance each misplaced repository is
+// assigned to, to copy it from this instance by calling requestCopy. The local
+// clone is only removed once the copy has succeeded, so that the repository is
+// never missing from both instances.
+func (s *Server) rebalanceRepos(ctx context.Context, addrs []string, requestCopy func(ctx context.Context, repo api.RepoName, sourceAddr string) error) error {
+	if s.DB == nil {
+		return nil
+	}
+
+	self := addrForHostname(s.Hostname, addrs)
+	if self == "" {
+		return errors.Errorf("gitserver hostname, %q, not found in list", s.Hostname)
+	}
+
+	// Collect the repositories first, since copying them takes a while and
+	// we don't want to hold the rows open.
+	var misplaced []api.RepoName
+	options := database.IterateRepoGitserverStatusOptions{ShardID: s.Hostname}
+	err := database.GitserverRepos(s.DB).IterateRepoGitserverStatus(ctx, options, func(repo types.RepoGitserverStatus) error {
+		if repo.GitserverRepo == nil || repo.CloneStatus != types.CloneStatusCloned {
+			return nil
+		}
+		if s.hostnameMatch(gitserver.AddrForRepo(repo.Name, addrs)) {
+			return nil
+		}
+		misplaced = append(misplaced, repo.Name)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(misplaced) > 0 {
+		log15.Info("rebalancing repos", "count", len(misplaced))
+	}
+
+	for _, repo := range misplaced {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		dir := s.dir(repo)
+		if !repoCloned(dir) {
+			continue
+		}
+
+		if err := requestCopy(ctx, repo, self); err != nil {
+			repoRebalanceCounter.WithLabelValues("false").Inc()
+			log15.Error("failed to copy repository to assigned gitserver", "repo", repo, "error", err)
+			continue
+		}
+		repoRebalanceCounter.WithLabelValues("true").Inc()
+
+		// The other instance now owns the database row, so leave it alone.
+		if err := s.removeRepoDirectory(dir, false); err != nil {
+			log15.Error("failed to remove rebalanced repository", "repo", repo, "error", err)
+		}
+	}
+
+	return nil
+}
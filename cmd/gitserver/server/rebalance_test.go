@@ -0,0 +1,111 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/conf/conftypes"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+	"github.com/sourcegraph/sourcegraph/internal/vcs"
+)
+
+func TestHandleRepoCopy(t *testing.T) {
+	ctx := context.Background()
+	repoName := api.RepoName("example.com/foo/bar")
+
+	remote := t.TempDir()
+	remoteCmd := func(name string, arg ...string) string {
+		t.Helper()
+		return runCmd(t, remote, name, arg...)
+	}
+	wantCommit := makeSingleCommitRepo(remoteCmd)
+
+	// The source instance clones the repository from the code host.
+	source := makeTestServer(ctx, t.TempDir(), remote, nil)
+	if _, err := source.cloneRepo(ctx, repoName, &cloneOptions{Block: true}); err != nil {
+		t.Fatal(err)
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/git/", http.StripPrefix("/git", source.gitServiceHandler()))
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+	u, _ := url.Parse(srv.URL)
+
+	// The destination instance has no way to reach the code host, so it can
+	// only get the repository from the source instance.
+	dest := makeTestServer(ctx, t.TempDir(), "https://invalid.example.com/foo/bar", nil)
+
+	copyRepo := func(req *protocol.RepoCopyRequest) *httptest.ResponseRecorder {
+		t.Helper()
+		body, err := json.Marshal(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w := httptest.NewRecorder()
+		dest.handleRepoCopy(w, httptest.NewRequest("POST", "/repo-copy", bytes.NewReader(body)))
+		return w
+	}
+
+	conf.Mock(&conf.Unified{ServiceConnections: conftypes.ServiceConnections{GitServers: []string{u.Host}}})
+	defer conf.Mock(nil)
+
+	if w := copyRepo(&protocol.RepoCopyRequest{Repo: repoName}); w.Code != http.StatusBadRequest {
+		t.Fatalf("expected bad request without a source address, got %d", w.Code)
+	}
+	if w := copyRepo(&protocol.RepoCopyRequest{Repo: repoName, SourceAddr: "example.com:80"}); w.Code != http.StatusBadRequest {
+		t.Fatalf("expected bad request for a source address which is not a gitserver instance, got %d", w.Code)
+	}
+
+	if w := copyRepo(&protocol.RepoCopyRequest{Repo: repoName, SourceAddr: u.Host}); w.Code != http.StatusOK {
+		t.Fatalf("unexpected status %d: %s", w.Code, w.Body.String())
+	}
+
+	dst := dest.dir(repoName)
+	if !repoCloned(dst) {
+		t.Fatal("expected repository to be cloned")
+	}
+	if gotCommit := runCmd(t, filepath.Dir(string(dst)), "git", "rev-parse", "HEAD"); gotCommit != wantCommit {
+		t.Fatalf("got commit %q, want %q", gotCommit, wantCommit)
+	}
+
+	// Copying again updates the existing clone from the source instance.
+	remoteCmd("git", "commit", "--allow-empty", "-m", "update")
+	wantCommit = remoteCmd("git", "rev-parse", "HEAD")
+	remoteURL, err := vcs.ParseURL(remote)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := (&GitRepoSyncer{}).Fetch(ctx, remoteURL, source.dir(repoName)); err != nil {
+		t.Fatal(err)
+	}
+	if w := copyRepo(&protocol.RepoCopyRequest{Repo: repoName, SourceAddr: u.Host}); w.Code != http.StatusOK {
+		t.Fatalf("unexpected status %d: %s", w.Code, w.Body.String())
+	}
+	if gotCommit := runCmd(t, filepath.Dir(string(dst)), "git", "rev-parse", "HEAD"); gotCommit != wantCommit {
+		t.Fatalf("got commit %q after update, want %q", gotCommit, wantCommit)
+	}
+}
+
+func TestAddrForHostname(t *testing.T) {
+	addrs := []string{"gitserver-1.gitserver:3178", "gitserver-10.gitserver:3178", "gitserver-2:3178"}
+
+	for hostname, want := range map[string]string{
+		"gitserver-1":  "gitserver-1.gitserver:3178",
+		"gitserver-10": "gitserver-10.gitserver:3178",
+		"gitserver-2":  "gitserver-2:3178",
+		"gitserver-3":  "",
+		"":             "",
+	} {
+		if got := addrForHostname(hostname, addrs); got != want {
+			t.Errorf("addrForHostname(%q) = %q, want %q", hostname, got, want)
+		}
+	}
+}
@@ -134,6 +134,12 @@ type Server struct {
 	// usually set to return a GitRepoSyncer.
 	GetVCSSyncer func(context.Context, api.RepoName) (VCSSyncer, error)
 
+	// DisableRebalancing turns off moving repositories between gitserver
+	// instances. RebalanceRepos does nothing, and repositories are always
+	// cloned from their code host rather than copied from the instance they
+	// were previously assigned to.
+	DisableRebalancing bool
+
 	// Hostname is how we identify this instance of gitserver. Generally it is the
 	// actual hostname but can also be overridden by the HOSTNAME environment variable.
 	Hostname string
@@ -167,6 +173,10 @@ type Server struct {
 
 	repoUpdateLocksMu sync.Mutex // protects the map below and also updates to locks.once
 	repoUpdateLocks   map[api.RepoName]*locks
+
+	// partialCloneCache caches the *partialCloneState of partial clones by
+	// api.RepoName. Use s.partialCloneState() instead of using it directly.
+	partialCloneCache sync.Map
 }
 
 type locks struct {
@@ -286,6 +296,7 @@ func (s *Server) Handler() http.Handler {
 	mux.HandleFunc("/repo-clone-progress", s.handleRepoCloneProgress)
 	mux.HandleFunc("/delete", s.handleRepoDelete)
 	mux.HandleFunc("/repo-update", s.handleRepoUpdate)
+	mux.HandleFunc("/repo-copy", s.handleRepoCopy)
 	mux.HandleFunc("/getGitolitePhabricatorMetadata", s.handleGetGitolitePhabricatorMetadata)
 	mux.HandleFunc("/create-commit-from-patch", s.handleCreateCommitFromPatch)
 	mux.HandleFunc("/ping", func(w http.ResponseWriter, _ *http.Request) {
@@ -331,18 +342,36 @@ func (s *Server) SyncRepoState(interval time.Duration, batchSize, perSecond int)
 // hostnameMatch checks whether the hostname matches the given address.
 // If we don't find an exact match, we look at the initial prefix.
 func (s *Server) hostnameMatch(addr string) bool {
-	if !strings.HasPrefix(addr, s.Hostname) {
+	return hostnameMatch(s.Hostname, addr)
+}
+
+func hostnameMatch(hostname, addr string) bool {
+	if !strings.HasPrefix(addr, hostname) {
 		return false
 	}
-	if addr == s.Hostname {
+	if addr == hostname {
 		return true
 	}
-	// We know that s.Hostname is shorter than addr so we can safely check the next
+	// We know that hostname is shorter than addr so we can safely check the next
 	// char
-	next := addr[len(s.Hostname)]
+	next := addr[len(hostname)]
 	return next == '.' || next == ':'
 }
 
+// addrForHostname returns the address in addrs of the gitserver instance
+// identified by hostname, or "" if there is none.
+func addrForHostname(hostname string, addrs []string) string {
+	if hostname == "" {
+		return ""
+	}
+	for _, addr := range addrs {
+		if hostnameMatch(hostname, addr) {
+			return addr
+		}
+	}
+	return ""
+}
+
 var (
 	repoSyncStateCounter = promauto.NewCounterVec(prometheus.CounterOpts{
 		Name: "src_repo_sync_state_counter",
@@ -446,6 +475,15 @@ func (s *Server) syncRepoState(addrs []string, batchSize, perSecond int, fullSyn
 		cloned := repoCloned(dir)
 		_, cloning := s.locker.Status(dir)
 
+		// The repo is still cloned on another instance, which is where it was
+		// assigned before the gitserver addresses changed. Leave the row
+		// alone until it has been copied to this instance.
+		if !cloned && !cloning && repo.GitserverRepo != nil && repo.CloneStatus == types.CloneStatusCloned &&
+			repo.ShardID != s.Hostname && addrForHostname(repo.ShardID, addrs) != "" {
+			repoSyncStateCounter.WithLabelValues("pending_copy").Inc()
+			return nil
+		}
+
 		var shouldUpdate bool
 		if repo.GitserverRepo == nil {
 			repo.GitserverRepo = &types.GitserverRepo{
@@ -754,9 +792,30 @@ func (s *Server) handleArchive(w http.ResponseWriter, r *http.Request) {
 	req.Args = append(req.Args, treeish, "--")
 	req.Args = append(req.Args, paths...)
 
+	if dir := s.dir(protocol.NormalizeRepo(req.Repo)); isPartialClone(dir) {
+		s.preparePartialCloneArchive(r.Context(), req, dir, treeish, paths)
+	}
+
 	s.exec(w, r, req)
 }
 
+// preparePartialCloneArchive leaves the excluded paths of the partial clone in
+// dir out of the archive requested by req, and fetches the blobs the archive
+// needs in bulk. Errors are logged rather than returned, since git archive
+// still fetches any missing blob it reads.
+func (s *Server) preparePartialCloneArchive(ctx context.Context, req *protocol.ExecRequest, dir GitDir, treeish string, paths []string) {
+	state, err := s.partialCloneState(ctx, req.Repo)
+	if err != nil {
+		log15.Warn("failed to get state of partial clone", "repo", req.Repo, "error", err)
+		return
+	}
+	req.Args = append(req.Args, excludePathspecs(state.excludePaths)...)
+
+	if err := prefetchBlobs(ctx, dir, state.remoteURL, treeish, paths, state.excludePaths); err != nil {
+		log15.Warn("failed to prefetch blobs of partial clone", "repo", req.Repo, "treeish", treeish, "error", err)
+	}
+}
+
 func (s *Server) handleExec(w http.ResponseWriter, r *http.Request) {
 	var req protocol.ExecRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -963,6 +1022,15 @@ func (s *Server) exec(w http.ResponseWriter, r *http.Request, req *protocol.Exec
 	cmd.Stdout = stdoutW
 	cmd.Stderr = stderrW
 
+	// Partial clones fetch the objects the command needs on demand.
+	if isPartialClone(dir) {
+		if state, err := s.partialCloneState(ctx, req.Repo); err != nil {
+			log15.Warn("failed to get state of partial clone", "repo", req.Repo, "error", err)
+		} else {
+			configurePromisorRemote(cmd, state.remoteURL)
+		}
+	}
+
 	exitStatus, execErr = runCommand(ctx, cmd)
 
 	status = strconv.Itoa(exitStatus)
@@ -1228,6 +1296,13 @@ type cloneOptions struct {
 
 	// Overwrite will overwrite the existing clone.
 	Overwrite bool
+
+	// SourceAddr is the address of another gitserver instance to copy the
+	// repository from. If unset, the repository is copied from the instance
+	// it was previously assigned to if that instance still has a clone, and
+	// cloned from its code host otherwise. Partial clones are always cloned
+	// from their code host.
+	SourceAddr string
 }
 
 // cloneRepo performs a clone operation for the given repository. It is
@@ -1249,8 +1324,29 @@ func (s *Server) cloneRepo(ctx context.Context, repo api.RepoName, opts *cloneOp
 	if err != nil {
 		return "", errors.Wrap(err, "get VCS syncer")
 	}
+	repoType := syncer.Type()
 
-	remoteURL, err := s.getRemoteURL(ctx, repo)
+	// Partial clones are cheap to clone from the code host, and can't be
+	// copied from other instances, see gitServiceHandler.
+	var sourceAddr string
+	if gs, ok := syncer.(*GitRepoSyncer); !ok || gs.PartialClone == nil {
+		if opts != nil && opts.SourceAddr != "" {
+			sourceAddr = opts.SourceAddr
+		} else if opts == nil || !opts.Overwrite {
+			sourceAddr = s.peerCloneAddr(ctx, repo)
+		}
+	}
+
+	var remoteURL *vcs.URL
+	if sourceAddr != "" {
+		// Copy the repository from the other gitserver instance's internal
+		// git service. This is always a git repository, regardless of the
+		// code host.
+		syncer = &GitRepoSyncer{}
+		remoteURL, err = peerRemoteURL(sourceAddr, repo)
+	} else {
+		remoteURL, err = s.getRemoteURL(ctx, repo)
+	}
 	if err != nil {
 		return "", err
 	}
@@ -1268,8 +1364,12 @@ func (s *Server) cloneRepo(ctx context.Context, repo api.RepoName, opts *cloneOp
 	}
 	defer cancel()
 
-	if err = s.rpsLimiter.Wait(ctx); err != nil {
-		return "", err
+	// Copies from other gitserver instances do not count towards the code
+	// host rate limit.
+	if sourceAddr == "" {
+		if err = s.rpsLimiter.Wait(ctx); err != nil {
+			return "", err
+		}
 	}
 
 	if err := syncer.IsCloneable(ctx, remoteURL); err != nil {
@@ -1303,8 +1403,10 @@ func (s *Server) cloneRepo(ctx context.Context, repo api.RepoName, opts *cloneOp
 		}
 		defer cancel1()
 
-		if err = s.rpsLimiter.Wait(ctx); err != nil {
-			return err
+		if sourceAddr == "" {
+			if err = s.rpsLimiter.Wait(ctx); err != nil {
+				return err
+			}
 		}
 
 		ctx, cancel2 := context.WithTimeout(ctx, longGitCommandTimeout)
@@ -1332,14 +1434,25 @@ func (s *Server) cloneRepo(ctx context.Context, repo api.RepoName, opts *cloneOp
 		tmpPath = filepath.Join(tmpPath, ".git")
 		tmp := GitDir(tmpPath)
 
-		// It may already be cloned
-		if !repoCloned(dir) {
-			s.setCloneStatusNonFatal(ctx, repo, types.CloneStatusCloning)
+		if sourceAddr != "" {
+			// The repository stays assigned to the source instance until the
+			// copy has succeeded, so that a failed copy can be retried.
+			defer func() {
+				if repoCloned(dir) {
+					// Use a background context to ensure we still update the DB even if we time out
+					s.setCloneStatusNonFatal(context.Background(), repo, types.CloneStatusCloned)
+				}
+			}()
+		} else {
+			// It may already be cloned
+			if !repoCloned(dir) {
+				s.setCloneStatusNonFatal(ctx, repo, types.CloneStatusCloning)
+			}
+			defer func() {
+				// Use a background context to ensure we still update the DB even if we time out
+				s.setCloneStatusNonFatal(context.Background(), repo, cloneStatus(repoCloned(dir), false))
+			}()
 		}
-		defer func() {
-			// Use a background context to ensure we still update the DB even if we time out
-			s.setCloneStatusNonFatal(context.Background(), repo, cloneStatus(repoCloned(dir), false))
-		}()
 
 		cmd, err := syncer.CloneCommand(ctx, remoteURL, tmpPath)
 		if err != nil {
@@ -1372,7 +1485,7 @@ func (s *Server) cloneRepo(ctx context.Context, repo api.RepoName, opts *cloneOp
 			return errors.Wrap(err, "failed to ensure HEAD exists")
 		}
 
-		if err := setRepositoryType(tmp, syncer.Type()); err != nil {
+		if err := setRepositoryType(tmp, repoType); err != nil {
 			return errors.Wrap(err, `git config set "sourcegraph.type"`)
 		}
 
@@ -1401,8 +1514,13 @@ func (s *Server) cloneRepo(ctx context.Context, repo api.RepoName, opts *cloneOp
 			return err
 		}
 
-		log15.Info("repo cloned", "repo", repo)
-		repoClonedCounter.Inc()
+		if sourceAddr != "" {
+			log15.Info("repo copied", "repo", repo, "source", sourceAddr)
+			repoCopiedCounter.Inc()
+		} else {
+			log15.Info("repo cloned", "repo", repo)
+			repoClonedCounter.Inc()
+		}
 
 		return nil
 	}
@@ -1430,6 +1548,54 @@ func (s *Server) cloneRepo(ctx context.Context, repo api.RepoName, opts *cloneOp
 	return "", nil
 }
 
+// peerCloneAddr returns the address of the gitserver instance which, according
+// to the database, has a clone of repo. It returns "" if there is no such
+// instance other than this one, or it is no longer a known gitserver address.
+func (s *Server) peerCloneAddr(ctx context.Context, name api.RepoName) string {
+	if s.DB == nil || s.DisableRebalancing {
+		return ""
+	}
+	// With a single instance there is no other instance to copy from, so we
+	// skip looking up where the repository was cloned.
+	addrs := conf.Get().ServiceConnections.GitServers
+	if len(addrs) < 2 {
+		return ""
+	}
+	repo, err := database.Repos(s.DB).GetByName(ctx, name)
+	if err != nil {
+		return ""
+	}
+	gr, err := database.GitserverRepos(s.DB).GetByID(ctx, repo.ID)
+	if err != nil {
+		return ""
+	}
+	if gr.CloneStatus != types.CloneStatusCloned || gr.ShardID == s.Hostname {
+		return ""
+	}
+	addr := addrForHostname(gr.ShardID, addrs)
+	if addr == "" {
+		return ""
+	}
+
+	// The database may be out of date, so check the clone is actually there
+	// before relying on it rather than the code host.
+	remoteURL, err := peerRemoteURL(addr, name)
+	if err != nil {
+		return ""
+	}
+	if err := (&GitRepoSyncer{}).IsCloneable(ctx, remoteURL); err != nil {
+		log15.Warn("repo not cloneable from previous gitserver instance", "repo", name, "addr", addr, "error", err)
+		return ""
+	}
+	return addr
+}
+
+// peerRemoteURL returns the URL of repo on the internal git service of the
+// gitserver instance at addr.
+func peerRemoteURL(addr string, repo api.RepoName) (*vcs.URL, error) {
+	return vcs.ParseURL("http://" + addr + "/git/" + string(protocol.NormalizeRepo(repo)))
+}
+
 // readCloneProgress scans the reader and saves the most recent line of output
 // as the lock status.
 func readCloneProgress(redactor *urlRedactor, lock *RepositoryLock, pr io.Reader) {
@@ -1544,6 +1710,10 @@ var (
 		Name: "src_gitserver_repo_cloned",
 		Help: "number of successful git clones run",
 	})
+	repoCopiedCounter = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "src_gitserver_repo_copied",
+		Help: "number of repositories successfully copied from another gitserver instance",
+	})
 )
 
 // Send 1 in 16 events to honeycomb. This is hardcoded since we only use this
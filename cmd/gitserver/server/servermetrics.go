@@ -11,6 +11,24 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/metrics"
 )
 
+var (
+	maintenanceTaskDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "src_gitserver_maintenance_task_duration_seconds",
+		Help:    "Duration of the maintenance tasks run on repositories by the janitor",
+		Buckets: []float64{0.1, 0.5, 1, 5, 10, 30, 60, 300, 900, 1800},
+	}, []string{"task", "success"})
+	maintenanceRepoPacks = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "src_gitserver_maintenance_repo_packs",
+		Help:    "Number of packfiles in a repository before maintenance",
+		Buckets: []float64{1, 2, 4, 8, 16, 32, 64, 128, 256, 512},
+	})
+	maintenanceRepoLooseObjects = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "src_gitserver_maintenance_repo_loose_objects",
+		Help:    "Estimated number of loose objects in a repository before maintenance",
+		Buckets: []float64{0, 256, 1024, 4096, 16384, 65536},
+	})
+)
+
 func (s *Server) RegisterMetrics() {
 	// test the latency of exec, which may increase under certain memory
 	// conditions
@@ -31,6 +49,8 @@ func (s *Server) RegisterMetrics() {
 		}
 	}()
 
+	prometheus.MustRegister(maintenanceTaskDuration, maintenanceRepoPacks, maintenanceRepoLooseObjects)
+
 	// report the size of the repos dir
 	if s.ReposDir == "" {
 		log15.Error("ReposDir is not set, cannot export disk_space_available metric.")
@@ -33,7 +33,11 @@ type VCSSyncer interface {
 }
 
 // GitRepoSyncer is a syncer for Git repositories.
-type GitRepoSyncer struct{}
+type GitRepoSyncer struct {
+	// PartialClone, if non-nil, makes the syncer clone the repository without
+	// the blobs excluded by its filter. Missing blobs are fetched on demand.
+	PartialClone *PartialCloneOptions
+}
 
 func (s *GitRepoSyncer) Type() string {
 	return "git"
@@ -78,11 +82,36 @@ func (s *GitRepoSyncer) CloneCommand(ctx context.Context, remoteURL *vcs.URL, tm
 		return nil, errors.Wrapf(err, "clone setup failed")
 	}
 
+	if s.PartialClone != nil {
+		if err := configurePartialClone(GitDir(tmpPath), s.PartialClone.Filter); err != nil {
+			return nil, errors.Wrap(err, "clone setup failed")
+		}
+		cmd = partialFetchCommand(ctx, remoteURL, "--filter="+s.PartialClone.Filter)
+		cmd.Dir = tmpPath
+		return cmd, nil
+	}
+
 	cmd, _ = s.fetchCommand(ctx, remoteURL)
 	cmd.Dir = tmpPath
 	return cmd, nil
 }
 
+// fetchRefspecs are the refs we fetch from Git remotes.
+var fetchRefspecs = []string{
+	// Normal git refs
+	"+refs/heads/*:refs/heads/*", "+refs/tags/*:refs/tags/*",
+	// GitHub pull requests
+	"+refs/pull/*:refs/pull/*",
+	// GitLab merge requests
+	"+refs/merge-requests/*:refs/merge-requests/*",
+	// Bitbucket pull requests
+	"+refs/pull-requests/*:refs/pull-requests/*",
+	// Gerrit changesets
+	"+refs/changes/*:refs/changes/*",
+	// Possibly deprecated refs for sourcegraph zap experiment?
+	"+refs/sourcegraph/*:refs/sourcegraph/*",
+}
+
 func (s *GitRepoSyncer) fetchCommand(ctx context.Context, remoteURL *vcs.URL) (cmd *exec.Cmd, configRemoteOpts bool) {
 	configRemoteOpts = true
 	if customCmd := customFetchCmd(ctx, remoteURL); customCmd != nil {
@@ -91,20 +120,8 @@ func (s *GitRepoSyncer) fetchCommand(ctx context.Context, remoteURL *vcs.URL) (c
 	} else if useRefspecOverrides() {
 		cmd = refspecOverridesFetchCmd(ctx, remoteURL)
 	} else {
-		cmd = exec.CommandContext(ctx, "git", "fetch",
-			"--progress", "--prune", remoteURL.String(),
-			// Normal git refs
-			"+refs/heads/*:refs/heads/*", "+refs/tags/*:refs/tags/*",
-			// GitHub pull requests
-			"+refs/pull/*:refs/pull/*",
-			// GitLab merge requests
-			"+refs/merge-requests/*:refs/merge-requests/*",
-			// Bitbucket pull requests
-			"+refs/pull-requests/*:refs/pull-requests/*",
-			// Gerrit changesets
-			"+refs/changes/*:refs/changes/*",
-			// Possibly deprecated refs for sourcegraph zap experiment?
-			"+refs/sourcegraph/*:refs/sourcegraph/*")
+		args := append([]string{"fetch", "--progress", "--prune", remoteURL.String()}, fetchRefspecs...)
+		cmd = exec.CommandContext(ctx, "git", args...)
 	}
 	return cmd, configRemoteOpts
 }
@@ -112,6 +129,13 @@ func (s *GitRepoSyncer) fetchCommand(ctx context.Context, remoteURL *vcs.URL) (c
 // Fetch tries to fetch updates of a Git repository.
 func (s *GitRepoSyncer) Fetch(ctx context.Context, remoteURL *vcs.URL, dir GitDir) error {
 	cmd, configRemoteOpts := s.fetchCommand(ctx, remoteURL)
+	// A partial clone must keep fetching from its promisor remote, which
+	// applies the filter it was cloned with. This is independent of the
+	// current configuration: a repository only changes between a partial and
+	// a full clone when it is re-cloned.
+	if isPartialClone(dir) {
+		cmd, configRemoteOpts = partialFetchCommand(ctx, remoteURL), true
+	}
 	dir.Set(cmd)
 	if output, err := runWith(ctx, cmd, configRemoteOpts, nil); err != nil {
 		return errors.Wrapf(err, "failed to update with output %q", newURLRedactor(remoteURL).redact(string(output)))
@@ -0,0 +1,145 @@
+package server
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+
+	"github.com/cockroachdb/errors"
+	"golang.org/x/mod/module"
+	modzip "golang.org/x/mod/zip"
+
+	"github.com/sourcegraph/sourcegraph/internal/conf/reposource"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gomodules/goproxy"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/vcs"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// GoModulesSyncer creates git repositories from the zip archives of Go module
+// versions served by a module proxy, with one git tag per version.
+type GoModulesSyncer struct {
+	Config *schema.GoModulesConnection
+	client *goproxy.Client
+}
+
+var _ VCSSyncer = &GoModulesSyncer{}
+
+// NewGoModulesSyncer returns a syncer for the given connection. If a nil doer
+// is provided, httpcli.ExternalDoer() will be used.
+func NewGoModulesSyncer(config *schema.GoModulesConnection, doer httpcli.Doer) *GoModulesSyncer {
+	return &GoModulesSyncer{
+		Config: config,
+		client: goproxy.NewClient(config, doer),
+	}
+}
+
+func (s *GoModulesSyncer) Type() string {
+	return "go_modules"
+}
+
+// IsCloneable checks to see if the VCS remote URL is cloneable. Any non-nil
+// error indicates there is a problem.
+func (s *GoModulesSyncer) IsCloneable(ctx context.Context, remoteURL *vcs.URL) error {
+	_, err := s.packageDependencies(ctx, remoteURL.Path)
+	return err
+}
+
+// CloneCommand returns the command to be executed for cloning from remote.
+// Like for JVM packages, the actual cloning happens inside this method and
+// the returned command is a no-op.
+func (s *GoModulesSyncer) CloneCommand(ctx context.Context, remoteURL *vcs.URL, bareGitDirectory string) (*exec.Cmd, error) {
+	return cloneWithPackageTags(ctx, bareGitDirectory, func(dir GitDir) error {
+		return s.Fetch(ctx, remoteURL, dir)
+	})
+}
+
+// Fetch adds git tags for newly added dependency versions and removes git tags
+// for deleted versions.
+func (s *GoModulesSyncer) Fetch(ctx context.Context, remoteURL *vcs.URL, dir GitDir) error {
+	dependencies, err := s.packageDependencies(ctx, remoteURL.Path)
+	if err != nil {
+		return err
+	}
+
+	packageDependencies := make([]packageDependency, 0, len(dependencies))
+	for i := range dependencies {
+		packageDependencies = append(packageDependencies, &dependencies[i])
+	}
+
+	return syncPackageTags(ctx, dir, packageDependencies, func(ctx context.Context, dependency packageDependency, workingDirectory string) error {
+		return s.unzipModule(ctx, *dependency.(*reposource.GoDependency), workingDirectory)
+	})
+}
+
+// RemoteShowCommand returns the command to be executed for showing remote.
+func (s *GoModulesSyncer) RemoteShowCommand(ctx context.Context, remoteURL *vcs.URL) (cmd *exec.Cmd, err error) {
+	return exec.CommandContext(ctx, "git", "remote", "show", "./"), nil
+}
+
+// packageDependencies returns the list of Go dependencies that belong to the
+// given URL path, sorted by semantic versioning.
+func (s *GoModulesSyncer) packageDependencies(ctx context.Context, repoUrlPath string) (dependencies []reposource.GoDependency, err error) {
+	mod, err := reposource.ParseGoModuleFromRepoURL(repoUrlPath)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, dependency := range s.Config.Dependencies {
+		if !mod.MatchesDependencyString(dependency) {
+			continue
+		}
+		dependency, err := reposource.ParseGoDependency(dependency)
+		if err != nil {
+			return nil, err
+		}
+
+		if s.client.Exists(ctx, dependency) {
+			dependencies = append(dependencies, dependency)
+		}
+		// Silently ignore non-existent dependencies because they are
+		// already logged out in the `GetRepo` method in
+		// internal/repos/go_modules.go.
+	}
+
+	if len(dependencies) == 0 {
+		return nil, errors.Errorf("no Go dependencies for URL path %s", repoUrlPath)
+	}
+
+	reposource.SortGoDependencies(dependencies)
+	return dependencies, nil
+}
+
+// unzipModule downloads the zip archive of the given dependency and extracts
+// it into the empty destination directory. The archive is validated with the
+// same rules the go command applies, which also rejects paths that escape the
+// destination directory.
+func (s *GoModulesSyncer) unzipModule(ctx context.Context, dependency reposource.GoDependency, destination string) error {
+	body, err := s.client.FetchZip(ctx, dependency)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	zipFile, err := ioutil.TempFile("", "gomodule-*.zip")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(zipFile.Name())
+
+	_, err = io.Copy(zipFile, body)
+	if err1 := zipFile.Close(); err == nil {
+		err = err1
+	}
+	if err != nil {
+		return err
+	}
+
+	version := module.Version{Path: dependency.Path, Version: dependency.Version}
+	if err := modzip.Unzip(destination, version, zipFile.Name()); err != nil {
+		return errors.Wrapf(err, "failed to unzip Go module %s", dependency.PackageManagerSyntax())
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+package server
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"os/exec"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/vcs"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+const (
+	exampleGoModulePath     = "example.com/mod"
+	exampleGoFilePath       = "mod.go"
+	exampleGoFileContents   = "package mod\n\nconst X = 1\n"
+	exampleGoFileContents2  = "package mod\n\nconst X = 2\n"
+	exampleGoDependency     = "example.com/mod@v1.0.0"
+	exampleGoDependency2    = "example.com/mod@v1.1.0"
+	exampleGoModuleURL      = "go/example.com/mod"
+	exampleGoModFileContent = "module example.com/mod\n"
+)
+
+func createPlaceholderModuleZip(t *testing.T, version, contents string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zipWriter := zip.NewWriter(&buf)
+	prefix := exampleGoModulePath + "@" + version + "/"
+	for name, body := range map[string]string{
+		"go.mod":          exampleGoModFileContent,
+		exampleGoFilePath: contents,
+	} {
+		w, err := zipWriter.Create(prefix + name)
+		assert.Nil(t, err)
+		_, err = w.Write([]byte(body))
+		assert.Nil(t, err)
+	}
+	assert.Nil(t, zipWriter.Close())
+	return buf.Bytes()
+}
+
+// goModuleProxy returns a stand-in for a Go module proxy that serves versions
+// v1.0.0 and v1.1.0 of the module example.com/mod.
+func goModuleProxy(t *testing.T) *httptest.Server {
+	zips := map[string][]byte{
+		"v1.0.0": createPlaceholderModuleZip(t, "v1.0.0", exampleGoFileContents),
+		"v1.1.0": createPlaceholderModuleZip(t, "v1.1.0", exampleGoFileContents2),
+	}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		file := strings.TrimPrefix(r.URL.Path, "/"+exampleGoModulePath+"/@v/")
+		ext := path.Ext(file)
+		zipContents, ok := zips[strings.TrimSuffix(file, ext)]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		switch ext {
+		case ".info":
+			fmt.Fprintf(w, `{"Version":%q,"Time":"2021-08-01T00:00:00Z"}`, strings.TrimSuffix(file, ext))
+		case ".zip":
+			w.Write(zipContents)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+}
+
+func TestGoModulesCloneCommand(t *testing.T) {
+	dir, err := os.MkdirTemp("", "")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	srv := goModuleProxy(t)
+	defer srv.Close()
+
+	// The first proxy doesn't serve any module, so every request falls back
+	// to the second proxy.
+	empty := httptest.NewServer(http.NotFoundHandler())
+	defer empty.Close()
+
+	s := NewGoModulesSyncer(&schema.GoModulesConnection{Urls: []string{empty.URL, srv.URL}}, http.DefaultClient)
+	bareGitDirectory := path.Join(dir, "git")
+
+	s.runCloneCommand(t, bareGitDirectory, []string{exampleGoDependency})
+	assertCommandOutput(t,
+		exec.Command("git", "tag", "--list"),
+		bareGitDirectory,
+		"v1.0.0\n",
+	)
+	assertCommandOutput(t,
+		exec.Command("git", "show", "v1.0.0:"+exampleGoFilePath),
+		bareGitDirectory,
+		exampleGoFileContents,
+	)
+	assertCommandOutput(t,
+		exec.Command("git", "show", "v1.0.0:go.mod"),
+		bareGitDirectory,
+		exampleGoModFileContent,
+	)
+
+	s.runCloneCommand(t, bareGitDirectory, []string{exampleGoDependency, exampleGoDependency2})
+	assertCommandOutput(t,
+		exec.Command("git", "tag", "--list"),
+		bareGitDirectory,
+		"v1.0.0\nv1.1.0\n", // verify that the v1.1.0 tag got added
+	)
+	assertCommandOutput(t,
+		exec.Command("git", "show", "latest:"+exampleGoFilePath),
+		bareGitDirectory,
+		exampleGoFileContents2,
+	)
+
+	s.runCloneCommand(t, bareGitDirectory, []string{exampleGoDependency})
+	assertCommandOutput(t,
+		exec.Command("git", "tag", "--list"),
+		bareGitDirectory,
+		"v1.0.0\n", // verify that the v1.1.0 tag has been removed.
+	)
+}
+
+func TestGoModulesIsCloneable(t *testing.T) {
+	srv := goModuleProxy(t)
+	defer srv.Close()
+
+	s := NewGoModulesSyncer(&schema.GoModulesConnection{
+		Urls:         []string{srv.URL},
+		Dependencies: []string{"example.com/mod@v2.0.0+incompatible"},
+	}, http.DefaultClient)
+	remoteURL := &vcs.URL{URL: url.URL{Path: exampleGoModuleURL}}
+	assert.NotNil(t, s.IsCloneable(context.Background(), remoteURL))
+
+	s.Config.Dependencies = []string{exampleGoDependency}
+	assert.Nil(t, s.IsCloneable(context.Background(), remoteURL))
+}
+
+func (s *GoModulesSyncer) runCloneCommand(t *testing.T, bareGitDirectory string, dependencies []string) {
+	t.Helper()
+	url := vcs.URL{
+		URL: url.URL{Path: exampleGoModuleURL},
+	}
+	s.Config.Dependencies = dependencies
+	cmd, err := s.CloneCommand(context.Background(), &url, bareGitDirectory)
+	require.NoError(t, err)
+	assert.Nil(t, cmd.Run())
+}
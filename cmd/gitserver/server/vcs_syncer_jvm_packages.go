@@ -7,7 +7,6 @@ import (
 	"encoding/binary"
 	"encoding/json"
 	"io"
-	"io/ioutil"
 	"os"
 	"os/exec"
 	"path"
@@ -16,7 +15,6 @@ import (
 	"strings"
 
 	"github.com/cockroachdb/errors"
-	"github.com/inconshreveable/log15"
 
 	"github.com/sourcegraph/sourcegraph/internal/conf/reposource"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/jvmpackages/coursier"
@@ -78,23 +76,9 @@ func (s *JVMPackagesSyncer) IsCloneable(ctx context.Context, remoteURL *vcs.URL)
 // helpful progress bar while cloning JVM package repositories, but that's an
 // acceptable tradeoff we're willing to make.
 func (s *JVMPackagesSyncer) CloneCommand(ctx context.Context, remoteURL *vcs.URL, bareGitDirectory string) (*exec.Cmd, error) {
-	err := os.MkdirAll(bareGitDirectory, 0755)
-	if err != nil {
-		return nil, err
-	}
-
-	cmd := exec.CommandContext(ctx, "git", "--bare", "init")
-	if _, err := runCommandInDirectory(ctx, cmd, bareGitDirectory); err != nil {
-		return nil, err
-	}
-
-	// The Fetch method is responsible for cleaning up temporary directories.
-	if err := s.Fetch(ctx, remoteURL, GitDir(bareGitDirectory)); err != nil {
-		return nil, err
-	}
-
-	// no-op command to satisfy VCSSyncer interface, see docstring for more details.
-	return exec.CommandContext(ctx, "git", "--version"), nil
+	return cloneWithPackageTags(ctx, bareGitDirectory, func(dir GitDir) error {
+		return s.Fetch(ctx, remoteURL, dir)
+	})
 }
 
 // Fetch adds git tags for newly added dependency versions and removes git tags
@@ -105,46 +89,14 @@ func (s *JVMPackagesSyncer) Fetch(ctx context.Context, remoteURL *vcs.URL, dir G
 		return err
 	}
 
-	tags := map[string]bool{}
-
-	out, err := runCommandInDirectory(ctx, exec.CommandContext(ctx, "git", "tag"), string(dir))
-	if err != nil {
-		return err
+	packageDependencies := make([]packageDependency, 0, len(dependencies))
+	for i := range dependencies {
+		packageDependencies = append(packageDependencies, &dependencies[i])
 	}
 
-	for _, line := range strings.Split(out, "\n") {
-		if len(line) == 0 {
-			continue
-		}
-		tags[line] = true
-	}
-
-	for i, dependency := range dependencies {
-		if tags[dependency.GitTagFromVersion()] {
-			continue
-		}
-		// the gitPushDependencyTag method is reponsible for cleaning up temporary directories.
-		if err := s.gitPushDependencyTag(ctx, string(dir), dependency, i == 0); err != nil {
-			return errors.Wrapf(err, "error pushing dependency %q", dependency)
-		}
-	}
-
-	dependencyTags := make(map[string]struct{}, len(dependencies))
-	for _, dependency := range dependencies {
-		dependencyTags[dependency.GitTagFromVersion()] = struct{}{}
-	}
-
-	for tag := range tags {
-		if _, isDependencyTag := dependencyTags[tag]; !isDependencyTag {
-			cmd := exec.CommandContext(ctx, "git", "tag", "-d", tag)
-			if _, err := runCommandInDirectory(ctx, cmd, string(dir)); err != nil {
-				log15.Error("Failed to delete git tag", "error", err, "tag", tag)
-				continue
-			}
-		}
-	}
-
-	return nil
+	return syncPackageTags(ctx, dir, packageDependencies, func(ctx context.Context, dependency packageDependency, workingDirectory string) error {
+		return s.writeJarSources(ctx, *dependency.(*reposource.MavenDependency), workingDirectory)
+	})
 }
 
 // RemoteShowCommand returns the command to be executed for showing remote.
@@ -185,18 +137,11 @@ func (s *JVMPackagesSyncer) packageDependencies(ctx context.Context, repoUrlPath
 	return dependencies, nil
 }
 
-// gitPushDependencyTag pushes a git tag to the given bareGitDirectory path. The
-// tag points to a commit that adds all sources of given dependency. When
-// isMainBranch is true, the main branch of the bare git directory will also be
-// updated to point to the same commit as the git tag.
-func (s *JVMPackagesSyncer) gitPushDependencyTag(ctx context.Context, bareGitDirectory string, dependency reposource.MavenDependency, isLatestVersion bool) error {
-	tmpDirectory, err := ioutil.TempDir("", "maven")
-	if err != nil {
-		return err
-	}
-	// Always clean up created temporary directories.
-	defer os.RemoveAll(tmpDirectory)
-
+// writeJarSources writes the file contents of the sources jar of the given
+// dependency into workingDirectory, along with an lsif-java.json file that
+// describes how to index them. A `*.jar` file works the same way as a `*.zip`
+// file, it can even be uncompressed with the `unzip` command-line tool.
+func (s *JVMPackagesSyncer) writeJarSources(ctx context.Context, dependency reposource.MavenDependency, workingDirectory string) error {
 	sourceCodePaths, err := coursier.FetchSources(ctx, s.Config, dependency)
 	if err != nil {
 		return err
@@ -206,46 +151,7 @@ func (s *JVMPackagesSyncer) gitPushDependencyTag(ctx context.Context, bareGitDir
 		return errors.Errorf("no sources for dependency %s", dependency)
 	}
 
-	sourceCodePath := sourceCodePaths[0]
-
-	cmd := exec.CommandContext(ctx, "git", "init")
-	if _, err := runCommandInDirectory(ctx, cmd, tmpDirectory); err != nil {
-		return err
-	}
-
-	err = s.commitJar(ctx, dependency, tmpDirectory, sourceCodePath, s.Config)
-	if err != nil {
-		return err
-	}
-
-	cmd = exec.CommandContext(ctx, "git", "remote", "add", "origin", bareGitDirectory)
-	if _, err := runCommandInDirectory(ctx, cmd, tmpDirectory); err != nil {
-		return err
-	}
-
-	cmd = exec.CommandContext(ctx, "git", "push", "--force", "origin", "--tags")
-	if _, err := runCommandInDirectory(ctx, cmd, tmpDirectory); err != nil {
-		return err
-	}
-
-	if isLatestVersion {
-		defaultBranch, err := runCommandInDirectory(ctx, exec.CommandContext(ctx, "git", "rev-parse", "--abbrev-ref", "HEAD"), tmpDirectory)
-		if err != nil {
-			return err
-		}
-		cmd = exec.CommandContext(ctx, "git", "push", "--force", "origin", strings.TrimSpace(defaultBranch)+":latest", dependency.GitTagFromVersion())
-		if _, err := runCommandInDirectory(ctx, cmd, tmpDirectory); err != nil {
-			return err
-		}
-	}
-
-	return nil
-}
-
-// commitJar creates a git commit in the given working directory that adds all the file contents of the given jar file.
-// A `*.jar` file works the same way as a `*.zip` file, it can even be uncompressed with the `unzip` command-line tool.
-func (s *JVMPackagesSyncer) commitJar(ctx context.Context, dependency reposource.MavenDependency,
-	workingDirectory, sourceCodeJarPath string, connection *schema.JVMPackagesConnection) error {
+	sourceCodeJarPath := sourceCodePaths[0]
 	if err := unzipJarFile(sourceCodeJarPath, workingDirectory); err != nil {
 		return errors.Wrapf(err, "failed to unzip jar file %v", sourceCodeJarPath)
 	}
@@ -256,7 +162,7 @@ func (s *JVMPackagesSyncer) commitJar(ctx context.Context, dependency reposource
 	}
 	defer file.Close()
 
-	jvmVersion, err := inferJVMVersionFromByteCode(ctx, connection, dependency)
+	jvmVersion, err := inferJVMVersionFromByteCode(ctx, s.Config, dependency)
 	if err != nil {
 		return err
 	}
@@ -271,26 +177,7 @@ func (s *JVMPackagesSyncer) commitJar(ctx context.Context, dependency reposource
 	}
 
 	_, err = file.Write(jsonContents)
-	if err != nil {
-		return err
-	}
-
-	cmd := exec.CommandContext(ctx, "git", "add", ".")
-	if _, err := runCommandInDirectory(ctx, cmd, workingDirectory); err != nil {
-		return err
-	}
-
-	cmd = exec.CommandContext(ctx, "git", "commit", "-m", dependency.CoursierSyntax(), "--date", stableGitCommitDate)
-	if _, err := runCommandInDirectory(ctx, cmd, workingDirectory); err != nil {
-		return err
-	}
-
-	cmd = exec.CommandContext(ctx, "git", "tag", "-m", dependency.CoursierSyntax(), dependency.GitTagFromVersion())
-	if _, err := runCommandInDirectory(ctx, cmd, workingDirectory); err != nil {
-		return err
-	}
-
-	return nil
+	return err
 }
 
 func unzipJarFile(jarPath, destination string) error {
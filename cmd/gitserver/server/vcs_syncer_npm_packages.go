@@ -0,0 +1,184 @@
+package server
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/internal/conf/reposource"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/npmpackages/npm"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/vcs"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// NpmPackagesSyncer creates git repositories from the tarballs of published
+// npm packages, with one git tag per version.
+type NpmPackagesSyncer struct {
+	Config *schema.NpmPackagesConnection
+	client *npm.Client
+}
+
+var _ VCSSyncer = &NpmPackagesSyncer{}
+
+// NewNpmPackagesSyncer returns a syncer for the given connection. If a nil
+// doer is provided, httpcli.ExternalDoer() will be used.
+func NewNpmPackagesSyncer(config *schema.NpmPackagesConnection, doer httpcli.Doer) *NpmPackagesSyncer {
+	return &NpmPackagesSyncer{
+		Config: config,
+		client: npm.NewClient(config, doer),
+	}
+}
+
+func (s *NpmPackagesSyncer) Type() string {
+	return "npm_packages"
+}
+
+// IsCloneable checks to see if the VCS remote URL is cloneable. Any non-nil
+// error indicates there is a problem.
+func (s *NpmPackagesSyncer) IsCloneable(ctx context.Context, remoteURL *vcs.URL) error {
+	_, err := s.packageDependencies(ctx, remoteURL.Path)
+	return err
+}
+
+// CloneCommand returns the command to be executed for cloning from remote.
+// Like for JVM packages, the actual cloning happens inside this method and
+// the returned command is a no-op.
+func (s *NpmPackagesSyncer) CloneCommand(ctx context.Context, remoteURL *vcs.URL, bareGitDirectory string) (*exec.Cmd, error) {
+	return cloneWithPackageTags(ctx, bareGitDirectory, func(dir GitDir) error {
+		return s.Fetch(ctx, remoteURL, dir)
+	})
+}
+
+// Fetch adds git tags for newly added dependency versions and removes git tags
+// for deleted versions.
+func (s *NpmPackagesSyncer) Fetch(ctx context.Context, remoteURL *vcs.URL, dir GitDir) error {
+	dependencies, err := s.packageDependencies(ctx, remoteURL.Path)
+	if err != nil {
+		return err
+	}
+
+	packageDependencies := make([]packageDependency, 0, len(dependencies))
+	for i := range dependencies {
+		packageDependencies = append(packageDependencies, &dependencies[i])
+	}
+
+	return syncPackageTags(ctx, dir, packageDependencies, func(ctx context.Context, dependency packageDependency, workingDirectory string) error {
+		tarball, err := s.client.FetchTarball(ctx, *dependency.(*reposource.NpmDependency))
+		if err != nil {
+			return err
+		}
+		defer tarball.Close()
+
+		if err := extractNpmTarball(tarball, workingDirectory); err != nil {
+			return errors.Wrapf(err, "failed to extract tarball of %s", dependency.PackageManagerSyntax())
+		}
+		return nil
+	})
+}
+
+// RemoteShowCommand returns the command to be executed for showing remote.
+func (s *NpmPackagesSyncer) RemoteShowCommand(ctx context.Context, remoteURL *vcs.URL) (cmd *exec.Cmd, err error) {
+	return exec.CommandContext(ctx, "git", "remote", "show", "./"), nil
+}
+
+// packageDependencies returns the list of npm dependencies that belong to the
+// given URL path, sorted by semantic versioning.
+func (s *NpmPackagesSyncer) packageDependencies(ctx context.Context, repoUrlPath string) (dependencies []reposource.NpmDependency, err error) {
+	pkg, err := reposource.ParseNpmPackageFromRepoURL(repoUrlPath)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, dependency := range s.Config.Dependencies {
+		if !pkg.MatchesDependencyString(dependency) {
+			continue
+		}
+		dependency, err := reposource.ParseNpmDependency(dependency)
+		if err != nil {
+			return nil, err
+		}
+
+		if s.client.Exists(ctx, dependency) {
+			dependencies = append(dependencies, dependency)
+		}
+		// Silently ignore non-existent dependencies because they are
+		// already logged out in the `GetRepo` method in
+		// internal/repos/npm_packages.go.
+	}
+
+	if len(dependencies) == 0 {
+		return nil, errors.Errorf("no npm dependencies for URL path %s", repoUrlPath)
+	}
+
+	reposource.SortNpmDependencies(dependencies)
+	return dependencies, nil
+}
+
+// extractNpmTarball extracts the regular files of the given gzipped tarball
+// into destination. npm tarballs wrap the package contents in a single
+// top-level directory, usually "package/", which is stripped.
+func extractNpmTarball(tarball io.Reader, destination string) error {
+	gzipReader, err := gzip.NewReader(tarball)
+	if err != nil {
+		return err
+	}
+	defer gzipReader.Close()
+
+	destinationDirectory := strings.TrimSuffix(destination, string(os.PathSeparator)) + string(os.PathSeparator)
+	tarReader := tar.NewReader(gzipReader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if header.Typeflag != tar.TypeReg {
+			// Skip directories, symlinks and other special files.
+			continue
+		}
+
+		name := strings.TrimPrefix(header.Name, "./")
+		i := strings.Index(name, "/")
+		if i < 0 {
+			continue
+		}
+		name = name[i+1:]
+		if name == ".git" || strings.HasPrefix(name, ".git/") {
+			// For security reasons, don't extract files under the
+			// `.git/` directory, see unzipJarFile.
+			continue
+		}
+		outputPath := path.Join(destination, name)
+		if !strings.HasPrefix(outputPath, destinationDirectory) {
+			// For security reasons, skip file if it's not a child
+			// of the target directory. See "Zip Slip Vulnerability".
+			continue
+		}
+
+		if err := os.MkdirAll(path.Dir(outputPath), 0700); err != nil {
+			return err
+		}
+		outputFile, err := os.OpenFile(outputPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(outputFile, tarReader)
+		if err1 := outputFile.Close(); err == nil {
+			err = err1
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
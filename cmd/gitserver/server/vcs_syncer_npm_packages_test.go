@@ -0,0 +1,165 @@
+package server
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"os/exec"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sourcegraph/sourcegraph/internal/vcs"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+const (
+	exampleNpmFilePath      = "index.js"
+	exampleNpmFileContents  = "module.exports = 1;\n"
+	exampleNpmFileContents2 = "module.exports = 2;\n"
+	exampleNpmDependency    = "@example/pkg@1.0.0"
+	exampleNpmDependency2   = "@example/pkg@2.0.0"
+	exampleNpmPackageURL    = "npm/example/pkg"
+)
+
+func createPlaceholderNpmTarball(t *testing.T, contents string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gzipWriter := gzip.NewWriter(&buf)
+	tarWriter := tar.NewWriter(gzipWriter)
+	for name, body := range map[string]string{
+		"package/" + exampleNpmFilePath: contents,
+		"package/.git/config":           "[core]\n",
+		"package/../../escaped.js":      "evil",
+	} {
+		assert.Nil(t, tarWriter.WriteHeader(&tar.Header{
+			Name:     name,
+			Typeflag: tar.TypeReg,
+			Mode:     0644,
+			Size:     int64(len(body)),
+		}))
+		_, err := tarWriter.Write([]byte(body))
+		assert.Nil(t, err)
+	}
+	assert.Nil(t, tarWriter.Close())
+	assert.Nil(t, gzipWriter.Close())
+	return buf.Bytes()
+}
+
+// npmRegistry returns a stand-in for the npm registry that serves versions
+// 1.0.0 and 2.0.0 of the package @example/pkg.
+func npmRegistry(t *testing.T) *httptest.Server {
+	tarballs := map[string][]byte{
+		"1.0.0": createPlaceholderNpmTarball(t, exampleNpmFileContents),
+		"2.0.0": createPlaceholderNpmTarball(t, exampleNpmFileContents2),
+	}
+	var srv *httptest.Server
+	mux := http.NewServeMux()
+	mux.HandleFunc("/@example/pkg/", func(w http.ResponseWriter, r *http.Request) {
+		version := path.Base(r.URL.Path)
+		if _, ok := tarballs[version]; !ok {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprintf(w, `{"name":"@example/pkg","version":%q,"dist":{"tarball":"%s/tarballs/pkg-%s.tgz"}}`, version, srv.URL, version)
+	})
+	mux.HandleFunc("/tarballs/", func(w http.ResponseWriter, r *http.Request) {
+		var version string
+		fmt.Sscanf(path.Base(r.URL.Path), "pkg-%5s.tgz", &version)
+		tarball, ok := tarballs[version]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(tarball)
+	})
+	srv = httptest.NewServer(mux)
+	return srv
+}
+
+func TestNpmCloneCommand(t *testing.T) {
+	dir, err := os.MkdirTemp("", "")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	srv := npmRegistry(t)
+	defer srv.Close()
+
+	s := NewNpmPackagesSyncer(&schema.NpmPackagesConnection{Registry: srv.URL}, http.DefaultClient)
+	bareGitDirectory := path.Join(dir, "git")
+
+	s.runCloneCommand(t, bareGitDirectory, []string{exampleNpmDependency})
+	assertCommandOutput(t,
+		exec.Command("git", "tag", "--list"),
+		bareGitDirectory,
+		"v1.0.0\n",
+	)
+	assertCommandOutput(t,
+		exec.Command("git", "show", "v1.0.0:"+exampleNpmFilePath),
+		bareGitDirectory,
+		exampleNpmFileContents,
+	)
+	// Files under .git/ and outside of the package directory are skipped.
+	assertCommandOutput(t,
+		exec.Command("git", "ls-tree", "-r", "--name-only", "v1.0.0"),
+		bareGitDirectory,
+		exampleNpmFilePath+"\n",
+	)
+
+	s.runCloneCommand(t, bareGitDirectory, []string{exampleNpmDependency, exampleNpmDependency2})
+	assertCommandOutput(t,
+		exec.Command("git", "tag", "--list"),
+		bareGitDirectory,
+		"v1.0.0\nv2.0.0\n", // verify that the v2.0.0 tag got added
+	)
+	assertCommandOutput(t,
+		exec.Command("git", "show", "v2.0.0:"+exampleNpmFilePath),
+		bareGitDirectory,
+		exampleNpmFileContents2,
+	)
+	assertCommandOutput(t,
+		exec.Command("git", "show", "latest:"+exampleNpmFilePath),
+		bareGitDirectory,
+		exampleNpmFileContents2,
+	)
+
+	s.runCloneCommand(t, bareGitDirectory, []string{exampleNpmDependency, "@example/pkg@3.0.0"})
+	assertCommandOutput(t,
+		exec.Command("git", "tag", "--list"),
+		bareGitDirectory,
+		"v1.0.0\n", // verify that the v2.0.0 tag has been removed and 3.0.0 ignored.
+	)
+}
+
+func TestNpmIsCloneable(t *testing.T) {
+	srv := npmRegistry(t)
+	defer srv.Close()
+
+	s := NewNpmPackagesSyncer(&schema.NpmPackagesConnection{
+		Registry:     srv.URL,
+		Dependencies: []string{"@example/pkg@3.0.0"},
+	}, http.DefaultClient)
+	remoteURL := &vcs.URL{URL: url.URL{Path: exampleNpmPackageURL}}
+	assert.NotNil(t, s.IsCloneable(context.Background(), remoteURL))
+
+	s.Config.Dependencies = []string{exampleNpmDependency}
+	assert.Nil(t, s.IsCloneable(context.Background(), remoteURL))
+}
+
+func (s *NpmPackagesSyncer) runCloneCommand(t *testing.T, bareGitDirectory string, dependencies []string) {
+	t.Helper()
+	url := vcs.URL{
+		URL: url.URL{Path: exampleNpmPackageURL},
+	}
+	s.Config.Dependencies = dependencies
+	cmd, err := s.CloneCommand(context.Background(), &url, bareGitDirectory)
+	assert.Nil(t, err)
+	assert.Nil(t, cmd.Run())
+}
@@ -0,0 +1,143 @@
+package server
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	"github.com/inconshreveable/log15"
+)
+
+// packageDependency is a single version of a package that is stored as a git
+// tag in a package repository.
+type packageDependency interface {
+	PackageManagerSyntax() string
+	GitTagFromVersion() string
+}
+
+// syncPackageTags adds git tags for newly added dependency versions and removes
+// git tags for deleted versions. The dependencies must be sorted by version in
+// descending order, the first dependency becomes the "latest" branch.
+// writeSources must write the source files of the given dependency into the
+// given empty directory.
+func syncPackageTags(ctx context.Context, dir GitDir, dependencies []packageDependency, writeSources func(ctx context.Context, dependency packageDependency, workingDirectory string) error) error {
+	tags := map[string]bool{}
+
+	out, err := runCommandInDirectory(ctx, exec.CommandContext(ctx, "git", "tag"), string(dir))
+	if err != nil {
+		return err
+	}
+
+	for _, line := range strings.Split(out, "\n") {
+		if len(line) == 0 {
+			continue
+		}
+		tags[line] = true
+	}
+
+	for i, dependency := range dependencies {
+		if tags[dependency.GitTagFromVersion()] {
+			continue
+		}
+		// the pushPackageTag function is reponsible for cleaning up temporary directories.
+		if err := pushPackageTag(ctx, string(dir), dependency, i == 0, writeSources); err != nil {
+			return errors.Wrapf(err, "error pushing dependency %q", dependency.PackageManagerSyntax())
+		}
+	}
+
+	dependencyTags := make(map[string]struct{}, len(dependencies))
+	for _, dependency := range dependencies {
+		dependencyTags[dependency.GitTagFromVersion()] = struct{}{}
+	}
+
+	for tag := range tags {
+		if _, isDependencyTag := dependencyTags[tag]; !isDependencyTag {
+			cmd := exec.CommandContext(ctx, "git", "tag", "-d", tag)
+			if _, err := runCommandInDirectory(ctx, cmd, string(dir)); err != nil {
+				log15.Error("Failed to delete git tag", "error", err, "tag", tag)
+				continue
+			}
+		}
+	}
+
+	return nil
+}
+
+// pushPackageTag pushes a git tag to the given bareGitDirectory path. The tag
+// points to a commit that adds all sources of given dependency. When
+// isLatestVersion is true, the "latest" branch of the bare git directory will
+// also be updated to point to the same commit as the git tag.
+func pushPackageTag(ctx context.Context, bareGitDirectory string, dependency packageDependency, isLatestVersion bool, writeSources func(ctx context.Context, dependency packageDependency, workingDirectory string) error) error {
+	tmpDirectory, err := ioutil.TempDir("", "package")
+	if err != nil {
+		return err
+	}
+	// Always clean up created temporary directories.
+	defer os.RemoveAll(tmpDirectory)
+
+	// Write the sources before initializing the repository so that
+	// writeSources gets an empty directory.
+	if err := writeSources(ctx, dependency, tmpDirectory); err != nil {
+		return err
+	}
+	// For security reasons, never let package contents provide a `.git/`
+	// directory, see unzipJarFile.
+	if _, err := os.Lstat(filepath.Join(tmpDirectory, ".git")); !os.IsNotExist(err) {
+		return errors.Errorf("sources of dependency %s contain a .git entry", dependency.PackageManagerSyntax())
+	}
+
+	for _, args := range [][]string{
+		{"init"},
+		{"add", "."},
+		{"commit", "--allow-empty", "-m", dependency.PackageManagerSyntax(), "--date", stableGitCommitDate},
+		{"tag", "-m", dependency.PackageManagerSyntax(), dependency.GitTagFromVersion()},
+		{"remote", "add", "origin", bareGitDirectory},
+		{"push", "--force", "origin", "--tags"},
+	} {
+		cmd := exec.CommandContext(ctx, "git", args...)
+		if _, err := runCommandInDirectory(ctx, cmd, tmpDirectory); err != nil {
+			return err
+		}
+	}
+
+	if isLatestVersion {
+		defaultBranch, err := runCommandInDirectory(ctx, exec.CommandContext(ctx, "git", "rev-parse", "--abbrev-ref", "HEAD"), tmpDirectory)
+		if err != nil {
+			return err
+		}
+		cmd := exec.CommandContext(ctx, "git", "push", "--force", "origin", strings.TrimSpace(defaultBranch)+":latest", dependency.GitTagFromVersion())
+		if _, err := runCommandInDirectory(ctx, cmd, tmpDirectory); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// cloneWithPackageTags initializes the bare git directory and calls fetch to
+// populate it. There is no external tool that performs all the steps for
+// creating a package repository so the actual cloning happens here and the
+// returned command is a no-op, see JVMPackagesSyncer.CloneCommand.
+func cloneWithPackageTags(ctx context.Context, bareGitDirectory string, fetch func(dir GitDir) error) (*exec.Cmd, error) {
+	err := os.MkdirAll(bareGitDirectory, 0755)
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := exec.CommandContext(ctx, "git", "--bare", "init")
+	if _, err := runCommandInDirectory(ctx, cmd, bareGitDirectory); err != nil {
+		return nil, err
+	}
+
+	// fetch is responsible for cleaning up temporary directories.
+	if err := fetch(GitDir(bareGitDirectory)); err != nil {
+		return nil, err
+	}
+
+	// no-op command to satisfy VCSSyncer interface.
+	return exec.CommandContext(ctx, "git", "--version"), nil
+}
@@ -42,6 +42,12 @@ type Server struct {
 	JVMPackagesSource interface {
 		GetRepo(ctx context.Context, artifactName string) (*types.Repo, error)
 	}
+	NpmPackagesSource interface {
+		GetRepo(ctx context.Context, repoName string) (*types.Repo, error)
+	}
+	GoModulesSource interface {
+		GetRepo(ctx context.Context, repoName string) (*types.Repo, error)
+	}
 	Scheduler interface {
 		UpdateOnce(id api.RepoID, name api.RepoName)
 		ScheduleInfo(id api.RepoID) *protocol.RepoUpdateSchedulerInfoResult
@@ -454,6 +460,44 @@ func (s *Server) remoteRepoSync(ctx context.Context, codehost *extsvc.CodeHost,
 				ErrorNotFound: true,
 			}, nil
 		}
+	case extsvc.NpmPackages:
+		if s.NpmPackagesSource != nil {
+			repo, err = s.NpmPackagesSource.GetRepo(ctx, remoteName)
+			if err != nil {
+				if errcode.IsNotFound(err) {
+					return &protocol.RepoLookupResult{
+						ErrorNotFound: true,
+					}, nil
+				}
+				return nil, err
+			}
+		} else {
+			log15.Error(
+				"NpmPackagesSource is nil: doing nothing. To fix this problem, make sure that cloud_default is true for the npm packages external service type.",
+				"remoteName", remoteName)
+			return &protocol.RepoLookupResult{
+				ErrorNotFound: true,
+			}, nil
+		}
+	case extsvc.GoModules:
+		if s.GoModulesSource != nil {
+			repo, err = s.GoModulesSource.GetRepo(ctx, remoteName)
+			if err != nil {
+				if errcode.IsNotFound(err) {
+					return &protocol.RepoLookupResult{
+						ErrorNotFound: true,
+					}, nil
+				}
+				return nil, err
+			}
+		} else {
+			log15.Error(
+				"GoModulesSource is nil: doing nothing. To fix this problem, make sure that cloud_default is true for the Go modules external service type.",
+				"remoteName", remoteName)
+			return &protocol.RepoLookupResult{
+				ErrorNotFound: true,
+			}, nil
+		}
 	}
 
 	if repo.Private {
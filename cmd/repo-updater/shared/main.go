@@ -199,6 +199,8 @@ func Main(enterpriseInit EnterpriseInit) {
 				extsvc.KindGitHub,
 				extsvc.KindGitLab,
 				extsvc.KindJVMPackages,
+				extsvc.KindNpmPackages,
+				extsvc.KindGoModules,
 			},
 		})
 		if err != nil {
@@ -222,6 +224,10 @@ func Main(enterpriseInit EnterpriseInit) {
 				}
 			case *schema.JVMPackagesConnection:
 				server.JVMPackagesSource, err = repos.NewJVMPackagesSource(e)
+			case *schema.NpmPackagesConnection:
+				server.NpmPackagesSource, err = repos.NewNpmPackagesSource(e)
+			case *schema.GoModulesConnection:
+				server.GoModulesSource, err = repos.NewGoModulesSource(e)
 			}
 
 			if err != nil {
@@ -123,10 +123,32 @@ type PatternInfo struct {
 	// file list in the frontend and passes it to searcher.
 	CombyRule string
 
+	// CombyRewrite is a rewrite template for structural search. When it is
+	// set, searcher returns the diff of rewriting each matched file instead
+	// of the matches. It only applies when IsStructuralPat is true.
+	CombyRewrite string
+
 	// Select is the value of the the select field in the query. It is not necessary to
 	// use it since selection is done after the query completes, but exposing it can enable
 	// optimizations.
 	Select string
+
+	// BlameAuthor is a regular expression that the author of the last change
+	// to a matched line, formatted as "Name <email>", must match. It is
+	// matched case insensitively.
+	BlameAuthor string
+
+	// BlameBefore and BlameAfter bound the date of the last change to a
+	// matched line. They are parsed by git, so they can be absolute, like
+	// "2021-06-01", or relative, like "3 weeks ago".
+	BlameBefore string
+	BlameAfter  string
+}
+
+// HasBlameFilters returns true if matched lines must be filtered by the
+// output of git blame.
+func (p *PatternInfo) HasBlameFilters() bool {
+	return p.BlameAuthor != "" || p.BlameBefore != "" || p.BlameAfter != ""
 }
 
 func (p *PatternInfo) String() string {
@@ -140,6 +162,9 @@ func (p *PatternInfo) String() string {
 		} else {
 			args = append(args, "comby")
 		}
+		if p.CombyRewrite != "" {
+			args = append(args, fmt.Sprintf("rewrite:%q", p.CombyRewrite))
+		}
 	}
 	if p.IsWordMatch {
 		args = append(args, "word")
@@ -162,6 +187,15 @@ func (p *PatternInfo) String() string {
 	if p.Select != "" {
 		args = append(args, fmt.Sprintf("select:%s", p.Select))
 	}
+	if p.BlameAuthor != "" {
+		args = append(args, fmt.Sprintf("blame.author:%q", p.BlameAuthor))
+	}
+	if p.BlameBefore != "" {
+		args = append(args, fmt.Sprintf("blame.before:%s", p.BlameBefore))
+	}
+	if p.BlameAfter != "" {
+		args = append(args, fmt.Sprintf("blame.after:%s", p.BlameAfter))
+	}
 
 	path := "glob"
 	if p.PathPatternsAreRegExps {
@@ -189,6 +223,10 @@ type Response struct {
 
 	// DeadlineHit is true if Matches may not include all FileMatches because a deadline was hit.
 	DeadlineHit bool
+
+	// BlameTruncated is true if Matches may not include all FileMatches
+	// because not every file could be checked against the blame filters.
+	BlameTruncated bool
 }
 
 // FileMatch is the struct used by vscode to receive search results
@@ -202,6 +240,11 @@ type FileMatch struct {
 
 	// LimitHit is true if LineMatches may not include all LineMatches.
 	LimitHit bool
+
+	// Diff is the unified diff of rewriting the file with CombyRewrite. It
+	// is only set for structural searches with a rewrite template, in which
+	// case LineMatches is empty.
+	Diff string
 }
 
 // LineMatch is the struct used by vscode to receive search results for a line.
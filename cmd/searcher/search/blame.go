@@ -0,0 +1,159 @@
+package search
+
+import (
+	"context"
+	"regexp"
+	"time"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/cmd/searcher/protocol"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+)
+
+// maxBlameFiles is the maximum number of files we run git blame on for a
+// single request. Files past this limit are dropped from the results and the
+// response is marked as truncated.
+const maxBlameFiles = 100
+
+// blameFile is git.BlameFile. It is a variable so tests can mock it.
+var blameFile = git.BlameFile
+
+// parseDate is git.ParseDate. It is a variable so tests can mock it.
+var parseDate = git.ParseDate
+
+// blameFilter keeps the line matches of a FileMatch whose last change
+// satisfies the blame filters of a request.
+type blameFilter struct {
+	author        *regexp.Regexp
+	before, after time.Time
+}
+
+// compileBlameAuthor compiles the BlameAuthor filter of a request.
+func compileBlameAuthor(author string) (*regexp.Regexp, error) {
+	re, err := regexp.Compile("(?i:" + author + ")")
+	if err != nil {
+		return nil, badRequestError{errors.Wrap(err, "invalid BlameAuthor").Error()}
+	}
+	return re, nil
+}
+
+func newBlameFilter(ctx context.Context, p *protocol.Request) (*blameFilter, error) {
+	f := &blameFilter{}
+	if p.BlameAuthor != "" {
+		re, err := compileBlameAuthor(p.BlameAuthor)
+		if err != nil {
+			return nil, err
+		}
+		f.author = re
+	}
+	for _, d := range []struct {
+		value string
+		dst   *time.Time
+	}{
+		{p.BlameBefore, &f.before},
+		{p.BlameAfter, &f.after},
+	} {
+		if d.value == "" {
+			continue
+		}
+		t, err := parseDate(ctx, p.Repo, d.value)
+		if err != nil {
+			return nil, errors.Wrap(err, "parsing blame date")
+		}
+		*d.dst = t
+	}
+	return f, nil
+}
+
+func (f *blameFilter) keep(h *git.Hunk) bool {
+	if f.author != nil && !f.author.MatchString(h.Author.Name+" <"+h.Author.Email+">") {
+		return false
+	}
+	if !f.before.IsZero() && !h.Author.Date.Before(f.before) {
+		return false
+	}
+	if !f.after.IsZero() && !h.Author.Date.After(f.after) {
+		return false
+	}
+	return true
+}
+
+// filterByBlame removes the line matches of matches that were not last
+// changed by a commit satisfying the blame filters in p. File matches without
+// any remaining line matches, including path-only matches, are dropped. At
+// most limit file matches are returned, and limitHit is true if matches has
+// more file matches that were not checked. truncated is true if some files
+// were dropped without being checked because of maxBlameFiles or the context
+// being done.
+func filterByBlame(ctx context.Context, p *protocol.Request, matches []protocol.FileMatch, limit int) (filtered []protocol.FileMatch, limitHit, truncated bool, err error) {
+	f, err := newBlameFilter(ctx, p)
+	if err != nil {
+		return nil, false, false, err
+	}
+
+	blamed := 0
+	for _, fm := range matches {
+		if len(fm.LineMatches) == 0 {
+			continue
+		}
+		if len(filtered) == limit {
+			limitHit = true
+			break
+		}
+		if blamed == maxBlameFiles || ctx.Err() != nil {
+			truncated = true
+			break
+		}
+		blamed++
+
+		// Only blame the range of lines that contain matches.
+		start, end := fm.LineMatches[0].LineNumber, fm.LineMatches[0].LineNumber
+		for _, lm := range fm.LineMatches {
+			if lm.LineNumber < start {
+				start = lm.LineNumber
+			}
+			if lm.LineNumber > end {
+				end = lm.LineNumber
+			}
+		}
+		hunks, err := blameFile(ctx, p.Repo, fm.Path, &git.BlameOptions{
+			NewestCommit: p.Commit,
+			StartLine:    start + 1,
+			EndLine:      end + 1,
+		})
+		if err != nil {
+			if ctx.Err() != nil {
+				truncated = true
+				break
+			}
+			return nil, false, false, errors.Wrapf(err, "blaming %s", fm.Path)
+		}
+
+		lineMatches := fm.LineMatches[:0:0]
+		for _, lm := range fm.LineMatches {
+			if h := hunkForLine(hunks, lm.LineNumber+1); h != nil && f.keep(h) {
+				lineMatches = append(lineMatches, lm)
+			}
+		}
+		if len(lineMatches) == 0 {
+			continue
+		}
+		if len(lineMatches) != len(fm.LineMatches) {
+			fm.MatchCount = len(lineMatches)
+		}
+		fm.LineMatches = lineMatches
+		filtered = append(filtered, fm)
+	}
+	return filtered, limitHit, truncated, nil
+}
+
+// hunkForLine returns the hunk containing the 1-based line, or nil.
+func hunkForLine(hunks []*git.Hunk, line int) *git.Hunk {
+	for _, h := range hunks {
+		if h.StartLine <= line && line < h.EndLine {
+			return h
+		}
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/cmd/searcher/protocol"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+)
+
+func TestFilterByBlame(t *testing.T) {
+	alice := git.Signature{Name: "Alice", Email: "alice@example.com", Date: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)}
+	bob := git.Signature{Name: "Bob", Email: "bob@example.com", Date: time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)}
+
+	// Lines 1-2 of every file were last changed by alice, lines 3-4 by bob.
+	blameFile = func(ctx context.Context, repo api.RepoName, path string, opt *git.BlameOptions) ([]*git.Hunk, error) {
+		if opt.StartLine != 1 || opt.EndLine != 3 {
+			return nil, fmt.Errorf("unexpected range %d-%d", opt.StartLine, opt.EndLine)
+		}
+		return []*git.Hunk{
+			{StartLine: 1, EndLine: 3, Author: alice},
+			{StartLine: 3, EndLine: 5, Author: bob},
+		}, nil
+	}
+	defer func() { blameFile = git.BlameFile }()
+
+	parseDate = func(ctx context.Context, repo api.RepoName, date string) (time.Time, error) {
+		return time.Parse("2006-01-02", date)
+	}
+	defer func() { parseDate = git.ParseDate }()
+
+	matches := []protocol.FileMatch{
+		{Path: "path-only"},
+		{
+			Path:       "a.go",
+			MatchCount: 2,
+			LineMatches: []protocol.LineMatch{
+				{Preview: "alice", LineNumber: 0},
+				{Preview: "bob", LineNumber: 2},
+			},
+		},
+	}
+
+	for _, tc := range []struct {
+		name string
+		p    protocol.PatternInfo
+		want []string
+	}{
+		{"author", protocol.PatternInfo{BlameAuthor: "^alice"}, []string{"alice"}},
+		{"author email", protocol.PatternInfo{BlameAuthor: "bob@example"}, []string{"bob"}},
+		{"before", protocol.PatternInfo{BlameBefore: "2021-03-01"}, []string{"alice"}},
+		{"after", protocol.PatternInfo{BlameAfter: "2021-03-01"}, []string{"bob"}},
+		{"none", protocol.PatternInfo{BlameAuthor: "carol"}, nil},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p := &protocol.Request{Repo: "foo", Commit: "deadbeef", PatternInfo: tc.p}
+			filtered, limitHit, truncated, err := filterByBlame(context.Background(), p, matches, 10)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if limitHit || truncated {
+				t.Errorf("unexpected limitHit=%v truncated=%v", limitHit, truncated)
+			}
+
+			var got []string
+			for _, fm := range filtered {
+				if fm.MatchCount != len(fm.LineMatches) {
+					t.Errorf("MatchCount = %d, want %d", fm.MatchCount, len(fm.LineMatches))
+				}
+				for _, lm := range fm.LineMatches {
+					got = append(got, lm.Preview)
+				}
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("unexpected lines (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	t.Run("truncated", func(t *testing.T) {
+		many := make([]protocol.FileMatch, maxBlameFiles+1)
+		for i := range many {
+			many[i] = matches[1]
+		}
+		p := &protocol.Request{PatternInfo: protocol.PatternInfo{BlameAuthor: "alice"}}
+		filtered, limitHit, truncated, err := filterByBlame(context.Background(), p, many, maxLimit)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if limitHit || !truncated {
+			t.Errorf("got limitHit=%v truncated=%v, want only truncation", limitHit, truncated)
+		}
+		if len(filtered) != maxBlameFiles {
+			t.Errorf("got %d files, want %d", len(filtered), maxBlameFiles)
+		}
+	})
+
+	t.Run("limit applies after filtering", func(t *testing.T) {
+		// Only the later files were changed by bob, so a limit applied before
+		// filtering would find none of them.
+		blameFile = func(ctx context.Context, repo api.RepoName, path string, opt *git.BlameOptions) ([]*git.Hunk, error) {
+			author := bob
+			if path == "a" || path == "b" {
+				author = alice
+			}
+			return []*git.Hunk{{StartLine: 1, EndLine: 2, Author: author}}, nil
+		}
+		var many []protocol.FileMatch
+		for _, path := range []string{"a", "b", "c", "d", "e"} {
+			many = append(many, protocol.FileMatch{Path: path, LineMatches: []protocol.LineMatch{{LineNumber: 0}}})
+		}
+		p := &protocol.Request{PatternInfo: protocol.PatternInfo{BlameAuthor: "bob"}}
+		filtered, limitHit, truncated, err := filterByBlame(context.Background(), p, many, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !limitHit || truncated {
+			t.Errorf("got limitHit=%v truncated=%v, want only limitHit", limitHit, truncated)
+		}
+		var got []string
+		for _, fm := range filtered {
+			got = append(got, fm.Path)
+		}
+		if diff := cmp.Diff([]string{"c", "d"}, got); diff != "" {
+			t.Errorf("unexpected files (-want +got):\n%s", diff)
+		}
+	})
+}
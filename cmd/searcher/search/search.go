@@ -102,6 +102,13 @@ func (s *Service) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		p.Limit = maxLimit
 	}
 
+	// Blame filters drop matches after searching, so the limit applies to the
+	// filtered matches rather than to the search.
+	limit := p.Limit
+	if p.HasBlameFilters() {
+		p.Limit = maxLimit
+	}
+
 	ctx, cancel, stream := newLimitedStreamCollector(ctx, p.Limit)
 	defer cancel()
 
@@ -119,11 +126,26 @@ func (s *Service) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	matches := stream.Collected()
+	limitHit := stream.LimitHit()
+	var blameTruncated bool
+	if p.HasBlameFilters() {
+		var blameLimitHit bool
+		matches, blameLimitHit, blameTruncated, err = filterByBlame(ctx, &p, matches, limit)
+		if err != nil {
+			log.Printf("internal error filtering by blame %#+v: %s", p, err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		limitHit = limitHit || blameLimitHit
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	resp := protocol.Response{
-		Matches:     stream.Collected(),
-		LimitHit:    stream.LimitHit(),
-		DeadlineHit: deadlineHit,
+		Matches:        matches,
+		LimitHit:       limitHit,
+		DeadlineHit:    deadlineHit,
+		BlameTruncated: blameTruncated,
 	}
 	// The only reasonable error is the client going away now since we know we
 	// can encode resp. This happens relatively often due to our
@@ -194,7 +216,6 @@ func (s *Service) search(ctx context.Context, p *protocol.Request, sender *limit
 
 	if p.IsStructuralPat && p.Indexed {
 		// Execute the new structural search path that directly calls Zoekt.
-		// TODO use limit in indexed structural search
 		return structuralSearchWithZoekt(ctx, p, sender)
 	}
 
@@ -262,6 +283,22 @@ func validateParams(p *protocol.Request) error {
 	if p.IsNegated && p.IsStructuralPat {
 		return errors.New("Negated patterns are not supported for structural searches")
 	}
+	if p.CombyRewrite != "" && !p.IsStructuralPat {
+		return errors.New("Rewrite templates are only supported for structural searches")
+	}
+	if p.HasBlameFilters() {
+		if p.IsNegated {
+			return errors.New("Negated patterns are not supported with blame filters")
+		}
+		if p.CombyRewrite != "" {
+			return errors.New("Rewrite templates are not supported with blame filters")
+		}
+		if p.BlameAuthor != "" {
+			if _, err := compileBlameAuthor(p.BlameAuthor); err != nil {
+				return err
+			}
+		}
+	}
 	return nil
 }
 
@@ -2,19 +2,21 @@ package search
 
 import (
 	"context"
-	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
-	"time"
 
+	"github.com/google/zoekt"
 	"github.com/inconshreveable/log15"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
+	"golang.org/x/sync/errgroup"
 
 	"github.com/sourcegraph/sourcegraph/cmd/searcher/protocol"
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/comby"
 	"github.com/sourcegraph/sourcegraph/internal/lazyregexp"
+	"github.com/sourcegraph/sourcegraph/internal/mutablelimiter"
 	"github.com/sourcegraph/sourcegraph/internal/search"
 	"github.com/sourcegraph/sourcegraph/internal/store"
 )
@@ -84,6 +86,26 @@ func toFileMatch(combyMatch comby.FileMatch) protocol.FileMatch {
 	}
 }
 
+// toRewriteFileMatch converts the diff of a file rewritten by comby. Comby
+// does not report the matches of a rewrite, so each hunk of the diff counts
+// as a match.
+func toRewriteFileMatch(combyDiff comby.FileDiff) protocol.FileMatch {
+	matchCount := 0
+	for _, line := range strings.Split(combyDiff.Diff, "\n") {
+		if strings.HasPrefix(line, "@@") {
+			matchCount++
+		}
+	}
+	if matchCount == 0 {
+		matchCount = 1
+	}
+	return protocol.FileMatch{
+		Path:       combyDiff.URI,
+		MatchCount: matchCount,
+		Diff:       combyDiff.Diff,
+	}
+}
+
 var isValidMatcher = lazyregexp.New(`\.(s|sh|bib|c|cs|css|dart|clj|elm|erl|ex|f|fsx|go|html|hs|java|js|json|jl|kt|tex|lisp|nim|md|ml|org|pas|php|py|re|rb|rs|rst|scala|sql|swift|tex|txt|ts)$`)
 
 func extensionToMatcher(extension string) string {
@@ -202,7 +224,7 @@ func filteredStructuralSearch(ctx context.Context, zipPath string, zipFile *stor
 		extensionHint = filepath.Ext(matchedPaths[0])
 	}
 
-	return structuralSearch(ctx, zipPath, Subset(matchedPaths), extensionHint, p.Pattern, p.CombyRule, p.Languages, repo, sender)
+	return structuralSearch(ctx, comby.ZipPath(zipPath), Subset(matchedPaths), extensionHint, p.Pattern, p.CombyRule, p.CombyRewrite, p.Languages, repo, sender)
 }
 
 // toMatcher returns the matcher that parameterizes structural search. It
@@ -240,9 +262,28 @@ type Subset []string
 
 var All UniversalSet = struct{}{}
 
-func structuralSearch(ctx context.Context, zipPath string, paths filePatterns, extensionHint, pattern, rule string, languages []string, repo api.RepoName, sender *limitedStreamCollector) error {
+// structuralSearchLimiter bounds the number of comby invocations running
+// concurrently in this searcher instance. Structural searches over many repos
+// otherwise fork an unbounded number of comby processes.
+var structuralSearchLimiter = mutablelimiter.New(runtime.GOMAXPROCS(0))
+
+func structuralSearch(ctx context.Context, input comby.Input, paths filePatterns, extensionHint, pattern, rule, rewrite string, languages []string, repo api.RepoName, sender *limitedStreamCollector) (err error) {
 	log15.Info("structural search", "repo", string(repo))
 
+	defer func() {
+		if err != nil && sender.LimitHit() {
+			// The sender cancels ctx once the limit is hit, which kills
+			// comby. This is not an error.
+			err = nil
+		}
+	}()
+
+	ctx, release, err := structuralSearchLimiter.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+
 	// Cap the number of forked processes to limit the size of zip contents being mapped to memory. Resolving #7133 could help to lift this restriction.
 	numWorkers := 4
 
@@ -254,7 +295,7 @@ func structuralSearch(ctx context.Context, zipPath string, paths filePatterns, e
 	}
 
 	args := comby.Args{
-		Input:         comby.ZipPath(zipPath),
+		Input:         input,
 		Matcher:       matcher,
 		MatchTemplate: pattern,
 		MatchOnly:     true,
@@ -263,20 +304,22 @@ func structuralSearch(ctx context.Context, zipPath string, paths filePatterns, e
 		NumWorkers:    numWorkers,
 	}
 
-	combyMatches, err := comby.Matches(ctx, args)
-	if err != nil {
-		return err
+	if rewrite != "" {
+		args.RewriteTemplate = rewrite
+		return comby.StreamRewrites(ctx, args, func(combyDiff comby.FileDiff) {
+			sender.Send(toRewriteFileMatch(combyDiff))
+		})
 	}
 
-	for _, combyMatch := range combyMatches {
-		if ctx.Err() != nil {
-			return nil
-		}
+	return comby.StreamMatches(ctx, args, func(combyMatch comby.FileMatch) {
 		sender.Send(toFileMatch(combyMatch))
-	}
-	return nil
+	})
 }
 
+// structuralSearchWithZoekt runs comby over the contents of candidate files
+// streamed from zoekt, without fetching an archive. Comby is started once the
+// first candidate arrives, so that its file extension can be used to infer a
+// matcher.
 func structuralSearchWithZoekt(ctx context.Context, p *protocol.Request, sender *limitedStreamCollector) (deadlineHit bool, err error) {
 	patternInfo := &search.TextPatternInfo{
 		Pattern:                      p.Pattern,
@@ -284,6 +327,7 @@ func structuralSearchWithZoekt(ctx context.Context, p *protocol.Request, sender
 		IsRegExp:                     p.IsRegExp,
 		IsStructuralPat:              p.IsStructuralPat,
 		CombyRule:                    p.CombyRule,
+		CombyRewrite:                 p.CombyRewrite,
 		IsWordMatch:                  p.IsWordMatch,
 		IsCaseSensitive:              p.IsCaseSensitive,
 		FileMatchLimit:               int32(p.Limit),
@@ -299,34 +343,44 @@ func structuralSearchWithZoekt(ctx context.Context, p *protocol.Request, sender
 		p.Branch = "HEAD"
 	}
 	repoBranches := map[string][]string{string(p.Repo): {p.Branch}}
-	useFullDeadline := false
-	zoektMatches, _, _, err := zoektSearch(ctx, patternInfo, repoBranches, time.Since, p.IndexerEndpoints, useFullDeadline, nil)
-	if err != nil {
-		return false, err
-	}
-
-	if len(zoektMatches) == 0 {
-		return false, nil
-	}
-
-	zipFile, err := os.CreateTemp("", "*.zip")
-	if err != nil {
-		return false, err
-	}
-	defer zipFile.Close()
-	defer os.Remove(zipFile.Name())
 
-	if err = writeZip(ctx, zipFile, zoektMatches); err != nil {
-		return false, err
+	g, ctx := errgroup.WithContext(ctx)
+
+	var tarInputEventC chan comby.TarInputEvent
+	send := func(file zoekt.FileMatch) {
+		if tarInputEventC == nil {
+			tarInputEventC = make(chan comby.TarInputEvent)
+			input := comby.Tar{TarInputEventC: tarInputEventC}
+			extensionHint := filepath.Ext(file.FileName)
+			g.Go(func() error {
+				return structuralSearch(ctx, input, All, extensionHint, p.Pattern, p.CombyRule, p.CombyRewrite, p.Languages, p.Repo, sender)
+			})
+		}
+		select {
+		case tarInputEventC <- comby.TarInputEvent{Path: file.FileName, Content: file.Content}:
+		case <-ctx.Done():
+		}
 	}
 
-	var extensionHint string
-	if len(zoektMatches) > 0 {
-		filename := zoektMatches[0].FileName
-		extensionHint = filepath.Ext(filename)
-	}
+	g.Go(func() error {
+		defer func() {
+			if tarInputEventC != nil {
+				close(tarInputEventC)
+			}
+		}()
+		limitHit, err := zoektStreamSearch(ctx, patternInfo, repoBranches, p.IndexerEndpoints, send)
+		if err != nil && sender.LimitHit() {
+			return nil
+		}
+		if limitHit {
+			// Zoekt skipped candidates, so comby may not have seen every
+			// match.
+			sender.SetLimitHit()
+		}
+		return err
+	})
 
-	return false, structuralSearch(ctx, zipFile.Name(), All, extensionHint, p.Pattern, p.CombyRule, p.Languages, p.Repo, sender)
+	return false, g.Wait()
 }
 
 var requestTotalStructuralSearch = promauto.NewCounterVec(prometheus.CounterOpts{
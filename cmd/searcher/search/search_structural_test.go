@@ -78,7 +78,7 @@ func foo(go string) {}
 
 				ctx, cancel, sender := newLimitedStreamCollector(context.Background(), 100000000)
 				defer cancel()
-				err := structuralSearch(ctx, zf, Subset(p.IncludePatterns), "", p.Pattern, p.CombyRule, p.Languages, "repo_foo", sender)
+				err := structuralSearch(ctx, comby.ZipPath(zf), Subset(p.IncludePatterns), "", p.Pattern, p.CombyRule, "", p.Languages, "repo_foo", sender)
 				if err != nil {
 					t.Fatal(err)
 				}
@@ -139,7 +139,7 @@ func foo(go.txt) {}
 		extensionHint := filepath.Ext(filename)
 		ctx, cancel, sender := newLimitedStreamCollector(context.Background(), 1000000000)
 		defer cancel()
-		err := structuralSearch(ctx, zf, All, extensionHint, "foo(:[args])", "", languages, "repo_foo", sender)
+		err := structuralSearch(ctx, comby.ZipPath(zf), All, extensionHint, "foo(:[args])", "", "", languages, "repo_foo", sender)
 		if err != nil {
 			return "ERROR: " + err.Error()
 		}
@@ -340,7 +340,7 @@ func TestIncludePatterns(t *testing.T) {
 	}
 	ctx, cancel, sender := newLimitedStreamCollector(context.Background(), 1000000000)
 	defer cancel()
-	err = structuralSearch(ctx, zf, Subset(p.IncludePatterns), "", p.Pattern, p.CombyRule, p.Languages, "foo", sender)
+	err = structuralSearch(ctx, comby.ZipPath(zf), Subset(p.IncludePatterns), "", p.Pattern, p.CombyRule, "", p.Languages, "foo", sender)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -384,7 +384,7 @@ func TestRule(t *testing.T) {
 
 	ctx, cancel, sender := newLimitedStreamCollector(context.Background(), 1000000000)
 	defer cancel()
-	err = structuralSearch(ctx, zf, Subset(p.IncludePatterns), "", p.Pattern, p.CombyRule, p.Languages, "repo", sender)
+	err = structuralSearch(ctx, comby.ZipPath(zf), Subset(p.IncludePatterns), "", p.Pattern, p.CombyRule, "", p.Languages, "repo", sender)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -410,6 +410,73 @@ func TestRule(t *testing.T) {
 	}
 }
 
+func TestRewrite(t *testing.T) {
+	// If we are not on CI skip the test.
+	if os.Getenv("CI") == "" {
+		t.Skip("Not on CI, skipping comby-dependent test")
+	}
+
+	input := map[string]string{
+		"file.go":  "func foo(a) {}\n",
+		"other.go": "func bar() {}\n",
+	}
+
+	zipData, err := testutil.CreateZip(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zf, cleanup, err := testutil.TempZipFileOnDisk(zipData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	p := &protocol.PatternInfo{
+		Pattern:         "func foo(:[args])",
+		IncludePatterns: []string{".go"},
+		CombyRewrite:    "func foo(ctx, :[args])",
+	}
+
+	ctx, cancel, sender := newLimitedStreamCollector(context.Background(), 1000000000)
+	defer cancel()
+	err = structuralSearch(ctx, comby.ZipPath(zf), Subset(p.IncludePatterns), "", p.Pattern, p.CombyRule, p.CombyRewrite, p.Languages, "repo", sender)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := sender.collected
+
+	if len(got) != 1 || got[0].Path != "file.go" {
+		t.Fatalf("got file matches %v, want a single match for file.go", got)
+	}
+	if len(got[0].LineMatches) != 0 {
+		t.Errorf("got line matches %v, want none", got[0].LineMatches)
+	}
+	if !strings.Contains(got[0].Diff, "+func foo(ctx, a) {}") {
+		t.Errorf("diff does not contain the rewritten line:\n%s", got[0].Diff)
+	}
+}
+
+func TestToRewriteFileMatch(t *testing.T) {
+	diff := `--- a.go
++++ a.go
+@@ -1,1 +1,1 @@
+-foo(a)
++bar(a)
+@@ -9,1 +9,1 @@
+-foo(b)
++bar(b)`
+
+	want := protocol.FileMatch{
+		Path:       "a.go",
+		MatchCount: 2,
+		Diff:       diff,
+	}
+	got := toRewriteFileMatch(comby.FileDiff{URI: "a.go", Diff: diff})
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+}
+
 func TestStructuralLimits(t *testing.T) {
 	// If we are not on CI skip the test.
 	if os.Getenv("CI") == "" {
@@ -456,7 +523,7 @@ func bar() {
 		return func(t *testing.T) {
 			ctx, cancel, sender := newLimitedStreamCollector(context.Background(), limit)
 			defer cancel()
-			err := structuralSearch(ctx, zf, Subset(p.IncludePatterns), "", p.Pattern, p.CombyRule, p.Languages, "repo_foo", sender)
+			err := structuralSearch(ctx, comby.ZipPath(zf), Subset(p.IncludePatterns), "", p.Pattern, p.CombyRule, "", p.Languages, "repo_foo", sender)
 			require.NoError(t, err)
 
 			require.Equal(t, wantCount, count(sender.collected))
@@ -599,7 +666,7 @@ func bar() {
 	t.Run("Strutural search match count", func(t *testing.T) {
 		ctx, cancel, sender := newLimitedStreamCollector(context.Background(), 1000000000)
 		defer cancel()
-		err := structuralSearch(ctx, zf, Subset(p.IncludePatterns), "", p.Pattern, p.CombyRule, p.Languages, "repo_foo", sender)
+		err := structuralSearch(ctx, comby.ZipPath(zf), Subset(p.IncludePatterns), "", p.Pattern, p.CombyRule, "", p.Languages, "repo_foo", sender)
 		if err != nil {
 			t.Fatal(err)
 		}
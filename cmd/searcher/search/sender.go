@@ -75,6 +75,14 @@ func (m *limitedStreamCollector) Remaining() int {
 	return m.remaining
 }
 
+// SetLimitHit marks the results as incomplete without canceling the search,
+// e.g. because a backend skipped candidates.
+func (m *limitedStreamCollector) SetLimitHit() {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	m.limitHit = true
+}
+
 func (m *limitedStreamCollector) LimitHit() bool {
 	m.mux.Lock()
 	defer m.mux.Unlock()
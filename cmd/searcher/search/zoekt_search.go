@@ -1,28 +1,21 @@
 package search
 
 import (
-	"archive/zip"
 	"context"
-	"io"
 	"regexp/syntax"
 	"sync"
 	"sync/atomic"
-	"time"
 
 	"github.com/cockroachdb/errors"
 	"github.com/google/zoekt"
 	zoektquery "github.com/google/zoekt/query"
 	zoektrpc "github.com/google/zoekt/rpc"
-	"github.com/opentracing/opentracing-go/log"
+	"github.com/google/zoekt/stream"
 
-	"github.com/sourcegraph/sourcegraph/internal/actor"
-	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/comby"
 	"github.com/sourcegraph/sourcegraph/internal/search"
 	"github.com/sourcegraph/sourcegraph/internal/search/backend"
 	zoektutil "github.com/sourcegraph/sourcegraph/internal/search/zoekt"
-	"github.com/sourcegraph/sourcegraph/internal/trace"
-	"github.com/sourcegraph/sourcegraph/internal/trace/ot"
 )
 
 var zoektOnce sync.Once
@@ -110,165 +103,47 @@ func buildQuery(args *search.TextPatternInfo, repoBranches map[string][]string,
 	), nil
 }
 
-type zoektSearchStreamEvent struct {
-	fm       []zoekt.FileMatch
-	limitHit bool
-	partial  map[api.RepoID]struct{}
-	err      error
-}
-
-const defaultMaxSearchResults = 30
-
-// zoektSearch searches repositories using zoekt, returning file contents for
-// files that match the given pattern.
+// zoektStreamSearch streams the files that are structural search candidates
+// for the given repository branches to send, as zoekt finds them. File
+// contents are included so that callers do not need to fetch an archive. It
+// returns limitHit if zoekt skipped files or shards, i.e. the candidates are
+// incomplete.
 //
-// Timeouts are reported through the context, and as a special case errNoResultsInTimeout
-// is returned if no results are found in the given timeout (instead of the more common
-// case of finding partial or full results in the given timeout).
-func zoektSearch(ctx context.Context, args *search.TextPatternInfo, repoBranches map[string][]string, since func(t time.Time) time.Duration, endpoints []string, useFullDeadline bool, c chan<- zoektSearchStreamEvent) (fm []zoekt.FileMatch, limitHit bool, partial map[api.RepoID]struct{}, err error) {
-	defer func() {
-		if c != nil {
-			c <- zoektSearchStreamEvent{
-				fm:       fm,
-				limitHit: limitHit,
-				partial:  partial,
-				err:      err,
-			}
-		}
-	}()
+// Unlike text search, we always use the complete (non-shortcircuit) query.
+// Comby starts processing the first candidates as soon as they are streamed,
+// so there is no benefit to a cheap approximate first pass.
+func zoektStreamSearch(ctx context.Context, args *search.TextPatternInfo, repoBranches map[string][]string, endpoints []string, send func(zoekt.FileMatch)) (limitHit bool, err error) {
 	if len(repoBranches) == 0 {
-		return nil, false, nil, nil
+		return false, nil
 	}
 
-	// Choose sensible values for k when we generalize this.
 	k := zoektutil.ResultCountFactor(len(repoBranches), args.FileMatchLimit, false)
 	searchOpts := zoektutil.SearchOpts(ctx, k, args.FileMatchLimit)
 	searchOpts.Whole = true
 
-	// TODO(@camdencheek) TODO(@rvantonder) handle "timeout:..." values in this context.
-	if useFullDeadline {
-		// If the user manually specified a timeout, allow zoekt to use all of the remaining timeout.
-		deadline, _ := ctx.Deadline()
-		searchOpts.MaxWallTime = time.Until(deadline)
-
-		// We don't want our context's deadline to cut off zoekt so that we can get the results
-		// found before the deadline.
-		//
-		// We'll create a new context that gets cancelled if the other context is cancelled for any
-		// reason other than the deadline being exceeded. This essentially means the deadline for the new context
-		// will be `deadline + time for zoekt to cancel + network latency`.
-		var cancel context.CancelFunc
-		ctx, cancel = contextWithoutDeadline(ctx)
-		defer cancel()
-	}
-
 	filePathPatterns, err := HandleFilePathPatterns(args)
 	if err != nil {
-		return nil, false, nil, err
+		return false, err
 	}
 
-	t0 := time.Now()
-	q, err := buildQuery(args, repoBranches, filePathPatterns, true)
+	q, err := buildQuery(args, repoBranches, filePathPatterns, false)
 	if err != nil {
-		return nil, false, nil, err
+		return false, err
 	}
 
 	client := getZoektClient(endpoints)
-	resp, err := client.Search(ctx, q, &searchOpts)
-	if err != nil {
-		return nil, false, nil, err
-	}
-	if since(t0) >= searchOpts.MaxWallTime {
-		return nil, false, nil, errNoResultsInTimeout
-	}
-
-	// We always return approximate results (limitHit true) unless we run the branch to perform a more complete search.
-	limitHit = true
-	// If the previous indexed search did not return a substantial number of matching file candidates or count was
-	// manually specified, run a more complete and expensive search.
-	if resp.FileCount < 10 || args.FileMatchLimit != defaultMaxSearchResults {
-		q, err = buildQuery(args, repoBranches, filePathPatterns, false)
-		if err != nil {
-			return nil, false, nil, err
-		}
-		resp, err = client.Search(ctx, q, &searchOpts)
-		if err != nil {
-			return nil, false, nil, err
-		}
-		if since(t0) >= searchOpts.MaxWallTime {
-			return nil, false, nil, errNoResultsInTimeout
+	err = client.StreamSearch(ctx, q, &searchOpts, stream.SenderFunc(func(sr *zoekt.SearchResult) {
+		if sr == nil {
+			return
 		}
-		// This is the only place limitHit can be set false, meaning we covered everything.
-		limitHit = resp.FilesSkipped+resp.ShardsSkipped > 0
-	}
-
-	if len(resp.Files) == 0 {
-		return nil, false, nil, nil
-	}
-
-	maxLineMatches := 25 + k
-	for _, file := range resp.Files {
-		if len(file.LineMatches) > maxLineMatches {
-			file.LineMatches = file.LineMatches[:maxLineMatches]
+		if sr.Stats.FilesSkipped+sr.Stats.ShardsSkipped > 0 {
 			limitHit = true
 		}
-	}
-
-	return resp.Files, limitHit, partial, nil
-}
-
-func writeZip(ctx context.Context, w io.Writer, fileMatches []zoekt.FileMatch) (err error) {
-	bytesWritten := 0
-	span, _ := ot.StartSpanFromContext(ctx, "WriteZip")
-	defer func() {
-		span.LogFields(log.Int("bytes_written", bytesWritten))
-		span.Finish()
-	}()
-
-	zw := zip.NewWriter(w)
-	defer zw.Close()
-
-	for _, match := range fileMatches {
-		mw, err := zw.Create(match.FileName)
-		if err != nil {
-			return err
+		for _, file := range sr.Files {
+			send(file)
 		}
-
-		n, err := mw.Write(match.Content)
-		if err != nil {
-			return err
-		}
-		bytesWritten += n
-	}
-
-	return nil
-}
-
-var errNoResultsInTimeout = errors.New("no results found in specified timeout")
-
-// contextWithoutDeadline returns a context which will cancel if the cOld is
-// canceled.
-func contextWithoutDeadline(cOld context.Context) (context.Context, context.CancelFunc) {
-	cNew, cancel := context.WithCancel(context.Background())
-
-	// Set trace context so we still get spans propagated
-	cNew = trace.CopyContext(cNew, cOld)
-
-	// Copy actor from cOld to cNew.
-	cNew = actor.WithActor(cNew, actor.FromContext(cOld))
-
-	go func() {
-		select {
-		case <-cOld.Done():
-			// cancel the new context if the old one is done for some reason other than the deadline passing.
-			if cOld.Err() != context.DeadlineExceeded {
-				cancel()
-			}
-		case <-cNew.Done():
-		}
-	}()
-
-	return cNew, cancel
+	}))
+	return limitHit, err
 }
 
 // atomicEndpoints allows us to update the endpoints used by our zoekt client.
@@ -35,6 +35,25 @@ type SearchArgs struct {
 	// need to match to get included in the result
 	ExcludePattern string
 
+	// IsExact if true will only match symbols whose name is equal to Query,
+	// instead of treating Query as a pattern.
+	IsExact bool
+
+	// Kinds is an optional list of symbol kinds as reported by ctags (e.g.
+	// "function", "interface"). When non-empty, a symbol's kind must be one of
+	// them. Kinds are matched case insensitively.
+	Kinds []string
+
+	// ParentPattern is an optional regex that the name of a symbol's parent
+	// (the symbol containing it, e.g. a class) needs to match to get included in
+	// the result.
+	ParentPattern string
+
+	// Languages is an optional list of languages as reported by ctags (e.g.
+	// "Go"). When non-empty, a symbol's language must be one of them. Languages
+	// are matched case insensitively.
+	Languages []string
+
 	// First indicates that only the first n symbols should be returned.
 	First int
 }
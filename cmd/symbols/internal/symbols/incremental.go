@@ -0,0 +1,212 @@
+package symbols
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+
+	"github.com/cockroachdb/errors"
+	"github.com/inconshreveable/log15"
+	"github.com/jmoiron/sqlx"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/diskcache"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+)
+
+// maxIncrementalPaths is the maximum number of added and modified paths between
+// an ancestor and a commit for which the database of the commit is derived from
+// the database of the ancestor. Beyond that it is cheaper to parse the whole
+// repository archive than to request the changed paths individually. The
+// paths are sent to gitserver as query parameters of the archive URL, so this
+// also keeps the request below common URL and header size limits.
+const maxIncrementalPaths = 1000
+
+// Changes are the paths that differ between two commits.
+type Changes struct {
+	Added    []string
+	Modified []string
+	Deleted  []string
+}
+
+// ParseGitDiffNameStatus parses the output of
+// `git diff -z --name-status --no-renames` into Changes.
+func ParseGitDiffNameStatus(output []byte) (Changes, error) {
+	fields := bytes.Split(output, []byte{0})
+	if n := len(fields); n > 0 && len(fields[n-1]) == 0 {
+		fields = fields[:n-1]
+	}
+	if len(fields)%2 != 0 {
+		return Changes{}, errors.Errorf("unexpected git diff output %q", output)
+	}
+
+	var changes Changes
+	for i := 0; i < len(fields); i += 2 {
+		status, path := string(fields[i]), string(fields[i+1])
+		if status == "" {
+			return Changes{}, errors.Errorf("missing status for path %q in git diff output", path)
+		}
+
+		switch status[0] {
+		case 'A':
+			changes.Added = append(changes.Added, path)
+		case 'M', 'T':
+			changes.Modified = append(changes.Modified, path)
+		case 'D':
+			changes.Deleted = append(changes.Deleted, path)
+		default:
+			return Changes{}, errors.Errorf("unrecognized git diff status %q for path %q", status, path)
+		}
+	}
+
+	return changes, nil
+}
+
+// writeSymbolsToNewDB writes the symbols of repo@commit to the blank database
+// file `dbFile`. When possible, the database is derived from the database of
+// the nearest already indexed ancestor of the commit. Otherwise all symbols of
+// the commit are parsed.
+func (s *Service) writeSymbolsToNewDB(ctx context.Context, dbFile string, repoName api.RepoName, commitID api.CommitID) error {
+	if s.GitDiff != nil && s.ListAncestors != nil {
+		ok, err := s.writeSymbolsFromAncestorDB(ctx, dbFile, repoName, commitID)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			log15.Warn("Failed to derive symbols database from an ancestor, parsing all symbols", "repo", repoName, "commit", commitID, "error", err)
+		}
+		if ok && err == nil {
+			return nil
+		}
+
+		// Start over with a blank database file.
+		if err := os.Truncate(dbFile, 0); err != nil {
+			return err
+		}
+	}
+
+	return s.writeAllSymbolsToNewDB(ctx, dbFile, repoName, commitID)
+}
+
+// writeSymbolsFromAncestorDB copies the database of the nearest already indexed
+// ancestor of repo@commit to `dbFile` and updates it by re-parsing the paths
+// that changed between the ancestor and the commit. It returns false if there
+// is no suitable ancestor database.
+//
+// The derived database is a standalone copy, so the ancestor database may be
+// evicted at any point afterwards. The ancestor database is read without
+// updating its modified time: a descendant makes for a better base of future
+// commits, so superseded ancestors are evicted before their descendants.
+func (s *Service) writeSymbolsFromAncestorDB(ctx context.Context, dbFile string, repoName api.RepoName, commitID api.CommitID) (bool, error) {
+	ancestorDBFile, ancestor, err := s.findAncestorDB(ctx, repoName, commitID)
+	if err != nil || ancestorDBFile == nil {
+		return false, err
+	}
+	defer ancestorDBFile.Close()
+
+	changes, err := s.GitDiff(ctx, repoName, ancestor, commitID)
+	if err != nil {
+		return false, err
+	}
+
+	parsePaths := make([]string, 0, len(changes.Added)+len(changes.Modified))
+	parsePaths = append(parsePaths, changes.Added...)
+	parsePaths = append(parsePaths, changes.Modified...)
+	if len(parsePaths) > maxIncrementalPaths {
+		return false, nil
+	}
+
+	if err := copyFile(dbFile, ancestorDBFile.File); err != nil {
+		return false, err
+	}
+
+	db, err := sqlx.Open("sqlite3_with_pcre", dbFile)
+	if err != nil {
+		return false, err
+	}
+	defer db.Close()
+
+	tx, err := db.Beginx()
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	deleteStatement, err := tx.Preparex(`DELETE FROM symbols WHERE path = ?`)
+	if err != nil {
+		return false, err
+	}
+	for _, paths := range [][]string{changes.Modified, changes.Deleted} {
+		for _, path := range paths {
+			if _, err := deleteStatement.Exec(path); err != nil {
+				return false, err
+			}
+		}
+	}
+
+	if len(parsePaths) > 0 {
+		insertStatement, err := prepareInsertSymbol(tx)
+		if err != nil {
+			return false, err
+		}
+
+		err = s.parseUncached(ctx, repoName, commitID, parsePaths, func(symbol result.Symbol) error {
+			symbolInDBValue := symbolToSymbolInDB(symbol)
+			_, err := insertStatement.Exec(&symbolInDBValue)
+			return err
+		})
+		if err != nil {
+			return false, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, err
+	}
+
+	derivedDatabases.Inc()
+	return true, nil
+}
+
+// findAncestorDB returns the cached database of the nearest ancestor of
+// repo@commit that has one, along with that ancestor. It returns a nil file if
+// none of the ancestors within MaxAncestorDistance have been indexed.
+func (s *Service) findAncestorDB(ctx context.Context, repoName api.RepoName, commitID api.CommitID) (*diskcache.File, api.CommitID, error) {
+	ancestors, err := s.ListAncestors(ctx, repoName, commitID, s.MaxAncestorDistance)
+	if err != nil {
+		return nil, "", err
+	}
+
+	for _, ancestor := range ancestors {
+		file, err := s.cache.Peek(symbolsDBKey(repoName, ancestor))
+		if err != nil {
+			return nil, "", err
+		}
+		if file != nil {
+			return file, ancestor, nil
+		}
+	}
+
+	return nil, "", nil
+}
+
+// copyFile overwrites the file at path with the contents of src.
+func copyFile(path string, src io.Reader) error {
+	dst, err := os.OpenFile(path, os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		dst.Close()
+		return err
+	}
+	return dst.Close()
+}
+
+var derivedDatabases = promauto.NewCounter(prometheus.CounterOpts{
+	Name: "symbols_store_derived_databases",
+	Help: "The total number of databases derived from the database of an ancestor commit.",
+})
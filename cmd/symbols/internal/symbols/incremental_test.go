@@ -0,0 +1,137 @@
+package symbols
+
+import (
+	"context"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sourcegraph/go-ctags"
+
+	"github.com/sourcegraph/sourcegraph/cmd/symbols/internal/protocol"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+)
+
+func TestParseGitDiffNameStatus(t *testing.T) {
+	output := "A\x00new.go\x00M\x00changed.go\x00D\x00removed.go\x00T\x00link.go\x00"
+
+	changes, err := ParseGitDiffNameStatus([]byte(output))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := Changes{
+		Added:    []string{"new.go"},
+		Modified: []string{"changed.go", "link.go"},
+		Deleted:  []string{"removed.go"},
+	}
+	if diff := cmp.Diff(want, changes); diff != "" {
+		t.Errorf("unexpected changes (-want +got):\n%s", diff)
+	}
+
+	for _, output := range []string{"A\x00", "X\x00unknown.go\x00"} {
+		if _, err := ParseGitDiffNameStatus([]byte(output)); err == nil {
+			t.Errorf("expected an error for %q", output)
+		}
+	}
+}
+
+func TestServiceIncremental(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { os.RemoveAll(tmpDir) }()
+
+	commits := map[api.CommitID]map[string]string{
+		"a": {"a.js": "x", "b.js": "y", "c.js": "z"},
+		"b": {"a.js": "x", "b.js": "w", "d.js": "v"},
+	}
+
+	var (
+		mu         sync.Mutex
+		fetchPaths [][]string
+	)
+	service := Service{
+		FetchTar: func(ctx context.Context, repo api.RepoName, commit api.CommitID, paths []string) (io.ReadCloser, error) {
+			mu.Lock()
+			fetchPaths = append(fetchPaths, paths)
+			mu.Unlock()
+
+			files := map[string]string{}
+			for path, body := range commits[commit] {
+				if len(paths) == 0 || contains(paths, path) {
+					files[path] = body
+				}
+			}
+			return createTar(files)
+		},
+		GitDiff: func(ctx context.Context, repo api.RepoName, commitA, commitB api.CommitID) (Changes, error) {
+			if commitA != "a" || commitB != "b" {
+				t.Fatalf("unexpected diff %s..%s", commitA, commitB)
+			}
+			return Changes{Added: []string{"d.js"}, Modified: []string{"b.js"}, Deleted: []string{"c.js"}}, nil
+		},
+		ListAncestors: func(ctx context.Context, repo api.RepoName, commit api.CommitID, n int) ([]api.CommitID, error) {
+			if commit == "b" {
+				return []api.CommitID{"a"}, nil
+			}
+			return nil, nil
+		},
+		NewParser: func() (ctags.Parser, error) {
+			return contentParser{}, nil
+		},
+		Path: tmpDir,
+	}
+	if err := service.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	search := func(commit api.CommitID) []string {
+		result, err := service.search(context.Background(), protocol.SearchArgs{Repo: "r", CommitID: commit, First: 10})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var symbols []string
+		for _, symbol := range *result {
+			symbols = append(symbols, symbol.Path+":"+symbol.Name)
+		}
+		sort.Strings(symbols)
+		return symbols
+	}
+
+	if diff := cmp.Diff([]string{"a.js:x", "b.js:y", "c.js:z"}, search("a")); diff != "" {
+		t.Errorf("unexpected symbols at a (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"a.js:x", "b.js:w", "d.js:v"}, search("b")); diff != "" {
+		t.Errorf("unexpected symbols at b (-want +got):\n%s", diff)
+	}
+
+	// The database of b is derived from the database of a, so only the added
+	// and modified paths are fetched.
+	if diff := cmp.Diff([][]string{nil, {"d.js", "b.js"}}, fetchPaths); diff != "" {
+		t.Errorf("unexpected fetched paths (-want +got):\n%s", diff)
+	}
+}
+
+// contentParser emits a single symbol per file, named after the file contents.
+type contentParser struct{}
+
+func (contentParser) Parse(name string, content []byte) ([]*ctags.Entry, error) {
+	return []*ctags.Entry{{Name: strings.TrimSpace(string(content)), Path: name}}, nil
+}
+
+func (contentParser) Close() {}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -39,7 +39,9 @@ func (s *Service) startParsers() error {
 	return nil
 }
 
-func (s *Service) parseUncached(ctx context.Context, repo api.RepoName, commitID api.CommitID, callback func(symbol result.Symbol) error) (err error) {
+// parseUncached parses the symbols of the given paths of repo@commit, or of all
+// files if paths is empty, and calls callback for each symbol.
+func (s *Service) parseUncached(ctx context.Context, repo api.RepoName, commitID api.CommitID, paths []string, callback func(symbol result.Symbol) error) (err error) {
 	span, ctx := ot.StartSpanFromContext(ctx, "parseUncached")
 	defer func() {
 		if err != nil {
@@ -50,9 +52,10 @@ func (s *Service) parseUncached(ctx context.Context, repo api.RepoName, commitID
 	}()
 	span.SetTag("repo", string(repo))
 	span.SetTag("commit", string(commitID))
+	span.SetTag("paths", len(paths))
 
 	tr := nettrace.New("parseUncached", string(repo))
-	tr.LazyPrintf("commitID: %s", commitID)
+	tr.LazyPrintf("commitID: %s paths: %d", commitID, len(paths))
 
 	totalSymbols := 0
 	defer func() {
@@ -65,7 +68,7 @@ func (s *Service) parseUncached(ctx context.Context, repo api.RepoName, commitID
 	}()
 
 	tr.LazyPrintf("fetch")
-	parseRequests, errChan, err := s.fetchRepositoryArchive(ctx, repo, commitID)
+	parseRequests, errChan, err := s.fetchRepositoryArchive(ctx, repo, commitID, paths)
 	tr.LazyPrintf("fetch (returned chans)")
 	if err != nil {
 		return err
@@ -99,8 +99,8 @@ func (s *Service) search(ctx context.Context, args protocol.SearchArgs) (*result
 // specified in `args`. If the database doesn't already exist in the disk cache,
 // it will create a new one and write all the symbols into it.
 func (s *Service) getDBFile(ctx context.Context, args protocol.SearchArgs) (string, error) {
-	diskcacheFile, err := s.cache.OpenWithPath(ctx, fmt.Sprintf("%d-%s@%s", symbolsDBVersion, args.Repo, args.CommitID), func(fetcherCtx context.Context, tempDBFile string) error {
-		err := s.writeAllSymbolsToNewDB(fetcherCtx, tempDBFile, args.Repo, args.CommitID)
+	diskcacheFile, err := s.cache.OpenWithPath(ctx, symbolsDBKey(args.Repo, args.CommitID), func(fetcherCtx context.Context, tempDBFile string) error {
+		err := s.writeSymbolsToNewDB(fetcherCtx, tempDBFile, args.Repo, args.CommitID)
 		if err != nil {
 			if err == context.Canceled {
 				log15.Error("Unable to parse repository symbols within the context", "repo", args.Repo, "commit", args.CommitID, "query", args.Query)
@@ -185,12 +185,39 @@ func filterSymbols(ctx context.Context, db *sqlx.DB, args protocol.SearchArgs) (
 		return newConditions
 	}
 
+	makeExactCondition := func(column string, value string) *sqlf.Query {
+		if args.IsCaseSensitive {
+			return sqlf.Sprintf(column+" = %s", value)
+		}
+		return sqlf.Sprintf(column+"lowercase = %s", strings.ToLower(value))
+	}
+
+	// makeInCondition matches any of the values case insensitively.
+	makeInCondition := func(column string, values []string) []*sqlf.Query {
+		if len(values) == 0 {
+			return nil
+		}
+
+		items := make([]*sqlf.Query, 0, len(values))
+		for _, value := range values {
+			items = append(items, sqlf.Sprintf("%s", strings.ToLower(value)))
+		}
+		return []*sqlf.Query{sqlf.Sprintf(column+"lowercase IN (%s)", sqlf.Join(items, ","))}
+	}
+
 	var conditions []*sqlf.Query
-	conditions = append(conditions, makeCondition("name", args.Query)...)
+	if args.IsExact && args.Query != "" {
+		conditions = append(conditions, makeExactCondition("name", args.Query))
+	} else {
+		conditions = append(conditions, makeCondition("name", args.Query)...)
+	}
 	for _, includePattern := range args.IncludePatterns {
 		conditions = append(conditions, makeCondition("path", includePattern)...)
 	}
 	conditions = append(conditions, negateAll(makeCondition("path", args.ExcludePattern))...)
+	conditions = append(conditions, makeCondition("parent", args.ParentPattern)...)
+	conditions = append(conditions, makeInCondition("kind", args.Kinds)...)
+	conditions = append(conditions, makeInCondition("language", args.Languages)...)
 
 	var sqlQuery *sqlf.Query
 	if len(conditions) == 0 {
@@ -217,40 +244,50 @@ func filterSymbols(ctx context.Context, db *sqlx.DB, args protocol.SearchArgs) (
 // filenames to prevent a newer version of the symbols service from attempting
 // to read from a database created by an older (and likely incompatible) symbols
 // service. Increment this when you change the database schema.
-const symbolsDBVersion = 3
+const symbolsDBVersion = 6
+
+// symbolsDBKey returns the disk cache key of the database for repo@commit.
+func symbolsDBKey(repo api.RepoName, commitID api.CommitID) string {
+	return fmt.Sprintf("%d-%s@%s", symbolsDBVersion, repo, commitID)
+}
 
-// symbolInDB is the same as `protocol.Symbol`, but with two additional columns:
-// namelowercase and pathlowercase, which enable indexed case insensitive
-// queries.
+// symbolInDB is the same as `protocol.Symbol`, but with additional lowercase
+// columns, which enable indexed case insensitive queries.
 type symbolInDB struct {
-	Name          string
-	NameLowercase string // derived from `Name`
-	Path          string
-	PathLowercase string // derived from `Path`
-	Line          int
-	Kind          string
-	Language      string
-	Parent        string
-	ParentKind    string
-	Signature     string
-	Pattern       string
+	Name              string
+	NameLowercase     string // derived from `Name`
+	Path              string
+	PathLowercase     string // derived from `Path`
+	Line              int
+	Kind              string
+	KindLowercase     string // derived from `Kind`
+	Language          string
+	LanguageLowercase string // derived from `Language`
+	Parent            string
+	ParentLowercase   string // derived from `Parent`
+	ParentKind        string
+	Signature         string
+	Pattern           string
 
 	FileLimited bool
 }
 
 func symbolToSymbolInDB(symbol result.Symbol) symbolInDB {
 	return symbolInDB{
-		Name:          symbol.Name,
-		NameLowercase: strings.ToLower(symbol.Name),
-		Path:          symbol.Path,
-		PathLowercase: strings.ToLower(symbol.Path),
-		Line:          symbol.Line,
-		Kind:          symbol.Kind,
-		Language:      symbol.Language,
-		Parent:        symbol.Parent,
-		ParentKind:    symbol.ParentKind,
-		Signature:     symbol.Signature,
-		Pattern:       symbol.Pattern,
+		Name:              symbol.Name,
+		NameLowercase:     strings.ToLower(symbol.Name),
+		Path:              symbol.Path,
+		PathLowercase:     strings.ToLower(symbol.Path),
+		Line:              symbol.Line,
+		Kind:              symbol.Kind,
+		KindLowercase:     strings.ToLower(symbol.Kind),
+		Language:          symbol.Language,
+		LanguageLowercase: strings.ToLower(symbol.Language),
+		Parent:            symbol.Parent,
+		ParentLowercase:   strings.ToLower(symbol.Parent),
+		ParentKind:        symbol.ParentKind,
+		Signature:         symbol.Signature,
+		Pattern:           symbol.Pattern,
 
 		FileLimited: symbol.FileLimited,
 	}
@@ -287,10 +324,38 @@ func (s *Service) writeAllSymbolsToNewDB(ctx context.Context, dbFile string, rep
 		return err
 	}
 
+	if err := createSymbolsTable(tx); err != nil {
+		return err
+	}
+
+	insertStatement, err := prepareInsertSymbol(tx)
+	if err != nil {
+		return err
+	}
+
+	err = s.parseUncached(ctx, repoName, commitID, nil, func(symbol result.Symbol) error {
+		symbolInDBValue := symbolToSymbolInDB(symbol)
+		_, err := insertStatement.Exec(&symbolInDBValue)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// createSymbolsTable creates the symbols table and its indexes.
+func createSymbolsTable(tx *sqlx.Tx) error {
 	// The column names are the lowercase version of fields in `symbolInDB`
 	// because sqlx lowercases struct fields by default. See
 	// http://jmoiron.github.io/sqlx/#query
-	_, err = tx.Exec(
+	_, err := tx.Exec(
 		`CREATE TABLE IF NOT EXISTS symbols (
 			name VARCHAR(256) NOT NULL,
 			namelowercase VARCHAR(256) NOT NULL,
@@ -298,8 +363,11 @@ func (s *Service) writeAllSymbolsToNewDB(ctx context.Context, dbFile string, rep
 			pathlowercase VARCHAR(4096) NOT NULL,
 			line INT NOT NULL,
 			kind VARCHAR(255) NOT NULL,
+			kindlowercase VARCHAR(255) NOT NULL,
 			language VARCHAR(255) NOT NULL,
+			languagelowercase VARCHAR(255) NOT NULL,
 			parent VARCHAR(255) NOT NULL,
+			parentlowercase VARCHAR(255) NOT NULL,
 			parentkind VARCHAR(255) NOT NULL,
 			signature VARCHAR(255) NOT NULL,
 			pattern VARCHAR(255) NOT NULL,
@@ -330,28 +398,32 @@ func (s *Service) writeAllSymbolsToNewDB(ctx context.Context, dbFile string, rep
 		return err
 	}
 
-	insertStatement, err := tx.PrepareNamed(
-		fmt.Sprintf(
-			"INSERT INTO symbols %s VALUES %s",
-			"( name,  namelowercase,  path,  pathlowercase,  line,  kind,  language,  parent,  parentkind,  signature,  pattern,  filelimited)",
-			"(:name, :namelowercase, :path, :pathlowercase, :line, :kind, :language, :parent, :parentkind, :signature, :pattern, :filelimited)"))
+	_, err = tx.Exec(`CREATE INDEX parentlowercase_index ON symbols(parentlowercase);`)
 	if err != nil {
 		return err
 	}
 
-	err = s.parseUncached(ctx, repoName, commitID, func(symbol result.Symbol) error {
-		symbolInDBValue := symbolToSymbolInDB(symbol)
-		_, err := insertStatement.Exec(&symbolInDBValue)
-		return err
-	})
+	// `kindlowercase_index` and `languagelowercase_index` enable indexed kind
+	// and language filters.
+	_, err = tx.Exec(`CREATE INDEX kindlowercase_index ON symbols(kindlowercase);`)
 	if err != nil {
 		return err
 	}
 
-	err = tx.Commit()
+	_, err = tx.Exec(`CREATE INDEX languagelowercase_index ON symbols(languagelowercase);`)
 	if err != nil {
 		return err
 	}
 
 	return nil
 }
+
+// prepareInsertSymbol prepares a statement that inserts a `symbolInDB` into
+// the symbols table.
+func prepareInsertSymbol(tx *sqlx.Tx) (*sqlx.NamedStmt, error) {
+	return tx.PrepareNamed(
+		fmt.Sprintf(
+			"INSERT INTO symbols %s VALUES %s",
+			"( name,  namelowercase,  path,  pathlowercase,  line,  kind,  kindlowercase,  language,  languagelowercase,  parent,  parentlowercase,  parentkind,  signature,  pattern,  filelimited)",
+			"(:name, :namelowercase, :path, :pathlowercase, :line, :kind, :kindlowercase, :language, :languagelowercase, :parent, :parentlowercase, :parentkind, :signature, :pattern, :filelimited)"))
+}
@@ -3,6 +3,7 @@ package symbols
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"path"
 	"testing"
@@ -10,17 +11,17 @@ import (
 	"github.com/inconshreveable/log15"
 
 	"github.com/sourcegraph/sourcegraph/cmd/symbols/internal/protocol"
-	"github.com/sourcegraph/sourcegraph/cmd/symbols/internal/sqliteutil"
+	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/testutil"
 )
 
 func BenchmarkSearch(b *testing.B) {
-	sqliteutil.MustRegisterSqlite3WithPcre()
-
 	log15.Root().SetHandler(log15.LvlFilterHandler(log15.LvlError, log15.Root().GetHandler()))
 
 	service := Service{
-		FetchTar:  testutil.FetchTarFromGithub,
+		FetchTar: func(ctx context.Context, repo api.RepoName, commit api.CommitID, paths []string) (io.ReadCloser, error) {
+			return testutil.FetchTarFromGithub(ctx, repo, commit)
+		},
 		NewParser: NewParser,
 		Path:      "/tmp/symbols-cache",
 	}
@@ -19,9 +19,23 @@ import (
 // Service is the symbols service.
 type Service struct {
 	// FetchTar returns an io.ReadCloser to a tar archive of a repository at the specified Git
-	// remote URL and commit ID. If the error implements "BadRequest() bool", it will be used to
-	// determine if the error is a bad request (eg invalid repo).
-	FetchTar func(context.Context, api.RepoName, api.CommitID) (io.ReadCloser, error)
+	// remote URL and commit ID. If paths is non-empty, the archive only contains those paths. If
+	// the error implements "BadRequest() bool", it will be used to determine if the error is a bad
+	// request (eg invalid repo).
+	FetchTar func(ctx context.Context, repo api.RepoName, commit api.CommitID, paths []string) (io.ReadCloser, error)
+
+	// GitDiff returns the paths that differ between two commits of a repository. When set along
+	// with ListAncestors, the database of a commit is derived from the database of its nearest
+	// already indexed ancestor by re-parsing only the changed paths.
+	GitDiff func(ctx context.Context, repo api.RepoName, commitA, commitB api.CommitID) (Changes, error)
+
+	// ListAncestors returns at most n ancestors of a commit, nearest first, excluding the commit
+	// itself.
+	ListAncestors func(ctx context.Context, repo api.RepoName, commit api.CommitID, n int) ([]api.CommitID, error)
+
+	// MaxAncestorDistance is the number of ancestors of a commit to consider when looking for a
+	// database to derive the commit's database from. It defaults to 100.
+	MaxAncestorDistance int
 
 	// MaxConcurrentFetchTar is the maximum number of concurrent calls allowed
 	// to FetchTar. It defaults to 15.
@@ -63,6 +77,10 @@ func (s *Service) Start() error {
 	}
 	s.fetchSem = make(chan int, s.MaxConcurrentFetchTar)
 
+	if s.MaxAncestorDistance == 0 {
+		s.MaxAncestorDistance = 100
+	}
+
 	s.cache = &diskcache.Store{
 		Dir:               s.Path,
 		Component:         "symbols",
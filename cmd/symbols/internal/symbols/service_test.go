@@ -8,10 +8,12 @@ import (
 	"net/http/httptest"
 	"os"
 	"reflect"
+	"sort"
 	"testing"
 
 	"github.com/sourcegraph/go-ctags"
 
+	"github.com/sourcegraph/sourcegraph/cmd/symbols/internal/protocol"
 	"github.com/sourcegraph/sourcegraph/cmd/symbols/internal/sqliteutil"
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/search"
@@ -21,6 +23,7 @@ import (
 
 func init() {
 	sqliteutil.SetLocalLibpath()
+	sqliteutil.MustRegisterSqlite3WithPcre()
 }
 
 func TestIsLiteralEquality(t *testing.T) {
@@ -56,8 +59,6 @@ func TestIsLiteralEquality(t *testing.T) {
 }
 
 func TestService(t *testing.T) {
-	sqliteutil.MustRegisterSqlite3WithPcre()
-
 	tmpDir, err := os.MkdirTemp("", "")
 	if err != nil {
 		t.Fatal(err)
@@ -66,7 +67,7 @@ func TestService(t *testing.T) {
 
 	files := map[string]string{"a.js": "var x = 1"}
 	service := Service{
-		FetchTar: func(ctx context.Context, repo api.RepoName, commit api.CommitID) (io.ReadCloser, error) {
+		FetchTar: func(ctx context.Context, repo api.RepoName, commit api.CommitID, paths []string) (io.ReadCloser, error) {
 			return createTar(files)
 		},
 		NewParser: func() (ctags.Parser, error) {
@@ -145,6 +146,80 @@ func TestService(t *testing.T) {
 	}
 }
 
+func TestServiceFilters(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { os.RemoveAll(tmpDir) }()
+
+	entries := []*ctags.Entry{
+		{Name: "Store", Path: "a.go", Kind: "interface", Language: "Go"},
+		{Name: "UploadStore", Path: "a.go", Kind: "interface", Language: "Go"},
+		{Name: "store", Path: "a.go", Kind: "struct", Language: "Go"},
+		{Name: "Get", Path: "a.go", Kind: "method", Language: "Go", Parent: "store", ParentKind: "struct"},
+		{Name: "Store", Path: "b.ts", Kind: "class", Language: "TypeScript"},
+	}
+	service := Service{
+		FetchTar: func(ctx context.Context, repo api.RepoName, commit api.CommitID, paths []string) (io.ReadCloser, error) {
+			return createTar(map[string]string{"a.go": "package a"})
+		},
+		NewParser: func() (ctags.Parser, error) {
+			return entriesParser(entries), nil
+		},
+		Path: tmpDir,
+	}
+	if err := service.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		args protocol.SearchArgs
+		want []string
+	}{
+		"kinds": {
+			args: protocol.SearchArgs{Query: "Store$", Kinds: []string{"INTERFACE"}},
+			want: []string{"Store", "UploadStore"},
+		},
+		"languages": {
+			args: protocol.SearchArgs{Query: "Store", Languages: []string{"typescript"}},
+			want: []string{"Store"},
+		},
+		"parent": {
+			args: protocol.SearchArgs{ParentPattern: "^store$", IsCaseSensitive: true},
+			want: []string{"Get"},
+		},
+		"exact": {
+			args: protocol.SearchArgs{Query: "Store", IsExact: true, IsCaseSensitive: true, Kinds: []string{"interface"}},
+			want: []string{"Store"},
+		},
+		"exact case insensitive": {
+			args: protocol.SearchArgs{Query: "STORE", IsExact: true, Languages: []string{"go"}},
+			want: []string{"Store", "store"},
+		},
+	}
+	for label, test := range tests {
+		t.Run(label, func(t *testing.T) {
+			test.args.Repo = "r"
+			test.args.CommitID = "c"
+			test.args.First = 10
+
+			result, err := service.search(context.Background(), test.args)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var names []string
+			for _, symbol := range *result {
+				names = append(names, symbol.Name)
+			}
+			sort.Strings(names)
+			if !reflect.DeepEqual(names, test.want) {
+				t.Errorf("got %v, want %v", names, test.want)
+			}
+		})
+	}
+}
+
 func createTar(files map[string]string) (io.ReadCloser, error) {
 	buf := new(bytes.Buffer)
 	w := tar.NewWriter(buf)
@@ -180,3 +255,12 @@ func (m mockParser) Parse(name string, content []byte) ([]*ctags.Entry, error) {
 }
 
 func (mockParser) Close() {}
+
+// entriesParser returns the same entries for every file.
+type entriesParser []*ctags.Entry
+
+func (p entriesParser) Parse(name string, content []byte) ([]*ctags.Entry, error) {
+	return p, nil
+}
+
+func (entriesParser) Close() {}
@@ -12,6 +12,7 @@ import (
 	"os/signal"
 	"runtime"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/inconshreveable/log15"
@@ -52,8 +53,30 @@ func main() {
 	go debugserver.NewServerRoutine(ready).Start()
 
 	service := symbols.Service{
-		FetchTar: func(ctx context.Context, repo api.RepoName, commit api.CommitID) (io.ReadCloser, error) {
-			return gitserver.DefaultClient.Archive(ctx, repo, gitserver.ArchiveOptions{Treeish: string(commit), Format: "tar"})
+		FetchTar: func(ctx context.Context, repo api.RepoName, commit api.CommitID, paths []string) (io.ReadCloser, error) {
+			return gitserver.DefaultClient.Archive(ctx, repo, gitserver.ArchiveOptions{Treeish: string(commit), Format: "tar", Paths: paths})
+		},
+		GitDiff: func(ctx context.Context, repo api.RepoName, commitA, commitB api.CommitID) (symbols.Changes, error) {
+			cmd := gitserver.DefaultClient.Command("git", "diff", "-z", "--name-status", "--no-renames", string(commitA), string(commitB))
+			cmd.Repo = repo
+			output, err := cmd.Output(ctx)
+			if err != nil {
+				return symbols.Changes{}, err
+			}
+			return symbols.ParseGitDiffNameStatus(output)
+		},
+		ListAncestors: func(ctx context.Context, repo api.RepoName, commit api.CommitID, n int) ([]api.CommitID, error) {
+			cmd := gitserver.DefaultClient.Command("git", "rev-list", "--first-parent", "--skip=1", "--max-count="+strconv.Itoa(n), string(commit))
+			cmd.Repo = repo
+			output, err := cmd.Output(ctx)
+			if err != nil {
+				return nil, err
+			}
+			var ancestors []api.CommitID
+			for _, line := range strings.Fields(string(output)) {
+				ancestors = append(ancestors, api.CommitID(line))
+			}
+			return ancestors, nil
 		},
 		NewParser: symbols.NewParser,
 		Path:      cacheDir,
@@ -62,8 +62,7 @@ func Init(ctx context.Context, db dbutil.DB, outOfBandMigrationRunner *oobmigrat
 			return nil
 		}
 
-		// We currently support three types of authz providers: GitHub, GitLab and Bitbucket Server.
-		authzTypes := make(map[string]struct{}, 3)
+		authzTypes := make(map[string]struct{}, len(providers))
 		for _, p := range providers {
 			authzTypes[p.ServiceType()] = struct{}{}
 		}
@@ -77,6 +76,8 @@ func Init(ctx context.Context, db dbutil.DB, outOfBandMigrationRunner *oobmigrat
 				authzNames = append(authzNames, "GitLab")
 			case extsvc.TypeBitbucketServer:
 				authzNames = append(authzNames, "Bitbucket Server")
+			case extsvc.TypeBitbucketCloud:
+				authzNames = append(authzNames, "Bitbucket Cloud")
 			default:
 				authzNames = append(authzNames, t)
 			}
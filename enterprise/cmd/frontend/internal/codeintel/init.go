@@ -2,6 +2,7 @@ package codeintel
 
 import (
 	"context"
+	"database/sql"
 	"net/http"
 
 	"github.com/cockroachdb/errors"
@@ -13,10 +14,13 @@ import (
 	gql "github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
 	codeintelresolvers "github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/codeintel/resolvers"
 	codeintelgqlresolvers "github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/codeintel/resolvers/graphql"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/stores/dbstore"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
 	"github.com/sourcegraph/sourcegraph/internal/oobmigration"
 	"github.com/sourcegraph/sourcegraph/internal/trace"
+	"github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker"
 )
 
 func Init(ctx context.Context, db dbutil.DB, outOfBandMigrationRunner *oobmigration.Runner, enterpriseServices *enterprise.Services) error {
@@ -34,6 +38,8 @@ func Init(ctx context.Context, db dbutil.DB, outOfBandMigrationRunner *oobmigrat
 		return err
 	}
 
+	registerQueues(db, observationContext)
+
 	resolver, err := newResolver(ctx, db, observationContext)
 	if err != nil {
 		return err
@@ -49,6 +55,13 @@ func Init(ctx context.Context, db dbutil.DB, outOfBandMigrationRunner *oobmigrat
 	return nil
 }
 
+// registerQueues exposes the failed dependency indexes to site admins. The index queue is registered
+// along with the executor queue that shares its store. The upload queue is not registered, as uploads
+// must be deleted through the codeintel API so that their data is cleaned up.
+func registerQueues(db dbutil.DB, observationContext *observation.Context) {
+	dbworker.RegisterQueue("codeintel_dependency_index", dbstore.WorkerutilDependencyIndexStore(basestore.NewWithDB(db, sql.TxOptions{}), observationContext))
+}
+
 func newResolver(ctx context.Context, db dbutil.DB, observationContext *observation.Context) (gql.CodeIntelResolver, error) {
 	hunkCache, err := codeintelresolvers.NewHunkCache(config.HunkCacheSize)
 	if err != nil {
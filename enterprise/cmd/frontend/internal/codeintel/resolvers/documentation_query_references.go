@@ -65,7 +65,7 @@ func (r *queryResolver) DocumentationReferences(ctx context.Context, pathID stri
 		for len(references) < limit {
 			var candidates []AdjustedLocation
 			r.path = location.Path
-			candidates, rawCursor, err = r.References(ctx, location.AdjustedRange.Start.Line, location.AdjustedRange.Start.Character, defaultReferencesPageSize, rawCursor)
+			candidates, rawCursor, err = r.References(ctx, location.AdjustedRange.Start.Line, location.AdjustedRange.Start.Character, defaultReferencesPageSize, 1, rawCursor)
 			if err != nil {
 				return nil, rawCursor, err
 			}
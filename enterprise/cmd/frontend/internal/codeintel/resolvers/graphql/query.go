@@ -18,6 +18,13 @@ const DefaultDiagnosticsPageSize = 100
 // ErrIllegalLimit occurs when the user requests less than one object per page.
 var ErrIllegalLimit = errors.New("illegal limit")
 
+// MaximumReferencesDepth is the maximum number of dependency hops that can be followed when
+// resolving references.
+const MaximumReferencesDepth = 5
+
+// ErrIllegalDepth occurs when the user requests a reference depth out of bounds.
+var ErrIllegalDepth = errors.Newf("illegal depth: must be between 1 and %d", MaximumReferencesDepth)
+
 // ErrIllegalBounds occurs when a negative or zero-width bound is supplied by the user.
 var ErrIllegalBounds = errors.New("illegal bounds")
 
@@ -73,12 +80,16 @@ func (r *QueryResolver) References(ctx context.Context, args *gql.LSIFPagedQuery
 	if limit <= 0 {
 		return nil, ErrIllegalLimit
 	}
+	maxDepth := derefInt32(args.MaxDepth, 1)
+	if maxDepth <= 0 || maxDepth > MaximumReferencesDepth {
+		return nil, ErrIllegalDepth
+	}
 	cursor, err := decodeCursor(args.After)
 	if err != nil {
 		return nil, err
 	}
 
-	locations, cursor, err := r.resolver.References(ctx, int(args.Line), int(args.Character), limit, cursor)
+	locations, cursor, err := r.resolver.References(ctx, int(args.Line), int(args.Character), limit, maxDepth, cursor)
 	if err != nil {
 		return nil, err
 	}
@@ -63,6 +63,7 @@ func TestReferences(t *testing.T) {
 	resolver := NewQueryResolver(mockResolver, NewCachedLocationResolver(db))
 
 	offset := int32(25)
+	maxDepth := int32(3)
 	cursor := base64.StdEncoding.EncodeToString([]byte("test-cursor"))
 
 	args := &gql.LSIFPagedQueryPositionArgs{
@@ -72,6 +73,7 @@ func TestReferences(t *testing.T) {
 		},
 		ConnectionArgs: graphqlutil.ConnectionArgs{First: &offset},
 		After:          &cursor,
+		MaxDepth:       &maxDepth,
 	}
 
 	if _, err := resolver.References(context.Background(), args); err != nil {
@@ -90,7 +92,10 @@ func TestReferences(t *testing.T) {
 	if val := mockResolver.ReferencesFunc.History()[0].Arg3; val != 25 {
 		t.Fatalf("unexpected character. want=%d have=%d", 25, val)
 	}
-	if val := mockResolver.ReferencesFunc.History()[0].Arg4; val != "test-cursor" {
+	if val := mockResolver.ReferencesFunc.History()[0].Arg4; val != 3 {
+		t.Fatalf("unexpected max depth. want=%d have=%d", 3, val)
+	}
+	if val := mockResolver.ReferencesFunc.History()[0].Arg5; val != "test-cursor" {
 		t.Fatalf("unexpected character. want=%s have=%s", "test-cursor", val)
 	}
 }
@@ -119,6 +124,9 @@ func TestReferencesDefaultLimit(t *testing.T) {
 	if val := mockResolver.ReferencesFunc.History()[0].Arg3; val != DefaultReferencesPageSize {
 		t.Fatalf("unexpected limit. want=%d have=%d", DefaultReferencesPageSize, val)
 	}
+	if val := mockResolver.ReferencesFunc.History()[0].Arg4; val != 1 {
+		t.Fatalf("unexpected max depth. want=%d have=%d", 1, val)
+	}
 }
 
 func TestReferencesDefaultIllegalLimit(t *testing.T) {
@@ -141,6 +149,28 @@ func TestReferencesDefaultIllegalLimit(t *testing.T) {
 	}
 }
 
+func TestReferencesIllegalDepth(t *testing.T) {
+	db := new(dbtesting.MockDB)
+
+	mockResolver := resolvermocks.NewMockQueryResolver()
+	resolver := NewQueryResolver(mockResolver, NewCachedLocationResolver(db))
+
+	for _, maxDepth := range []int32{0, MaximumReferencesDepth + 1} {
+		maxDepth := maxDepth
+		args := &gql.LSIFPagedQueryPositionArgs{
+			LSIFQueryPositionArgs: gql.LSIFQueryPositionArgs{
+				Line:      10,
+				Character: 15,
+			},
+			MaxDepth: &maxDepth,
+		}
+
+		if _, err := resolver.References(context.Background(), args); err != ErrIllegalDepth {
+			t.Fatalf("unexpected error. want=%q have=%q", ErrIllegalDepth, err)
+		}
+	}
+}
+
 func TestHover(t *testing.T) {
 	db := new(dbtesting.MockDB)
 
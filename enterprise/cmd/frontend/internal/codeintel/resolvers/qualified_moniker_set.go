@@ -20,12 +20,7 @@ func newQualifiedMonikerSet() *qualifiedMonikerSet {
 // add the given qualified moniker to the set if it is distinct from all elements
 // currently in the set.
 func (s *qualifiedMonikerSet) add(qualifiedMoniker semantic.QualifiedMonikerData) {
-	monikerHash := strings.Join([]string{
-		qualifiedMoniker.PackageInformationData.Name,
-		qualifiedMoniker.PackageInformationData.Version,
-		qualifiedMoniker.MonikerData.Scheme,
-		qualifiedMoniker.MonikerData.Identifier,
-	}, ":")
+	monikerHash := qualifiedMonikerHash(qualifiedMoniker)
 
 	if _, ok := s.monikerHashMap[monikerHash]; ok {
 		return
@@ -34,3 +29,18 @@ func (s *qualifiedMonikerSet) add(qualifiedMoniker semantic.QualifiedMonikerData
 	s.monikerHashMap[monikerHash] = struct{}{}
 	s.monikers = append(s.monikers, qualifiedMoniker)
 }
+
+// contains returns true if the set contains a moniker equivalent to the given qualified moniker.
+func (s *qualifiedMonikerSet) contains(qualifiedMoniker semantic.QualifiedMonikerData) bool {
+	_, ok := s.monikerHashMap[qualifiedMonikerHash(qualifiedMoniker)]
+	return ok
+}
+
+func qualifiedMonikerHash(qualifiedMoniker semantic.QualifiedMonikerData) string {
+	return strings.Join([]string{
+		qualifiedMoniker.PackageInformationData.Name,
+		qualifiedMoniker.PackageInformationData.Version,
+		qualifiedMoniker.MonikerData.Scheme,
+		qualifiedMoniker.MonikerData.Identifier,
+	}, ":")
+}
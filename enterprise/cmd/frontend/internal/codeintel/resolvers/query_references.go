@@ -20,8 +20,27 @@ import (
 
 const slowReferencesRequestThreshold = time.Second
 
+// transitiveReferencesTimeBudget is the amount of time a single request may spend looking for monikers
+// re-exported by dependents. The budget is tracked per request rather than in the cursor, which is
+// provided by the client. Once the budget is exhausted, the references of the hops discovered so far
+// are still returned but no further re-exports are collected during the request.
+const transitiveReferencesTimeBudget = 5 * time.Second
+
+// maxVisitedMonikers and maxReexportLocations bound the size of the state kept in the cursor while
+// following transitive dependents. maxVisitedMonikers allows monikerLimit monikers for each of the (at
+// most five) hops. Once either is reached, no further re-exports are collected.
+const (
+	maxVisitedMonikers   = 5 * monikerLimit
+	maxReexportLocations = 100
+)
+
 // References returns the list of source locations that reference the symbol at the given position.
-func (r *queryResolver) References(ctx context.Context, line, character, limit int, rawCursor string) (_ []AdjustedLocation, _ string, err error) {
+//
+// If maxDepth is greater than one, references are also resolved transitively through dependents:
+// when a reference in another index re-exports the symbol under its own moniker (e.g., a wrapper
+// package), the references of that moniker are returned as well, up to maxDepth hops away from the
+// original symbol.
+func (r *queryResolver) References(ctx context.Context, line, character, limit, maxDepth int, rawCursor string) (_ []AdjustedLocation, _ string, err error) {
 	ctx, traceLog, endObservation := observeResolver(ctx, &err, "References", r.operations.references, slowReferencesRequestThreshold, observation.Args{
 		LogFields: []log.Field{
 			log.Int("repositoryID", r.repositoryID),
@@ -31,6 +50,7 @@ func (r *queryResolver) References(ctx context.Context, line, character, limit i
 			log.String("uploads", uploadIDsToString(r.uploads)),
 			log.Int("line", line),
 			log.Int("character", character),
+			log.Int("maxDepth", maxDepth),
 		},
 	})
 	defer endObservation()
@@ -96,11 +116,14 @@ func (r *queryResolver) References(ctx context.Context, line, character, limit i
 	}
 
 	// Query a single page of location results
-	locations, hasMore, err := r.pageReferences(ctx, adjustedUploads, orderedMonikers, definitionUploadIDs, uploadsByID, &cursor, limit)
+	locations, hasMore, err := r.pageReferences(ctx, adjustedUploads, orderedMonikers, definitionUploadIDs, uploadsByID, &cursor, limit, maxDepth)
 	if err != nil {
 		return nil, "", err
 	}
-	traceLog(log.Int("numLocations", len(locations)))
+	traceLog(
+		log.Int("numLocations", len(locations)),
+		log.Int("depth", cursor.Depth),
+	)
 
 	// Adjust the locations back to the appropriate range in the target commits. This adjusts
 	// locations within the repository the user is browsing so that it appears all references
@@ -237,7 +260,7 @@ func (r *queryResolver) definitionUploadIDsFromCursor(ctx context.Context, adjus
 // pageReferences returns a slice of the result set denoted by the given cursor. The given cursor will be
 // adjusted to reflect the offsets required to resolve the next page of results. If there are no more pages
 // left in the result set, a false-valued flag is returned.
-func (r *queryResolver) pageReferences(ctx context.Context, adjustedUploads []adjustedUpload, orderedMonikers []semantic.QualifiedMonikerData, definitionUploadIDs []int, uploadsByID map[int]dbstore.Dump, cursor *referencesCursor, limit int) ([]lsifstore.Location, bool, error) {
+func (r *queryResolver) pageReferences(ctx context.Context, adjustedUploads []adjustedUpload, orderedMonikers []semantic.QualifiedMonikerData, definitionUploadIDs []int, uploadsByID map[int]dbstore.Dump, cursor *referencesCursor, limit, maxDepth int) ([]lsifstore.Location, bool, error) {
 	var locations []lsifstore.Location
 
 	// Phase 1: Gather all "local" locations via LSIF graph traversal. We'll continue to request additional
@@ -263,17 +286,42 @@ func (r *queryResolver) pageReferences(ctx context.Context, adjustedUploads []ad
 	// Phase 2: Gather all "remote" locations via moniker search. We only do this if there are no more local
 	// results. We'll continue to request additional locations until we fill an entire page or there are no
 	// more local results remaining, just as we did above.
+	//
+	// When following transitive dependents, each hop repeats this phase for the monikers re-exported by the
+	// remote locations of the previous hop.
 
 	if cursor.RemotePhase {
+		transitiveDeadline := time.Now().Add(transitiveReferencesTimeBudget)
+
 		for len(locations) < limit {
-			remoteLocations, hasMore, err := r.pageRemoteReferences(ctx, adjustedUploads, orderedMonikers, definitionUploadIDs, uploadsByID, cursor, limit-len(locations))
+			hopMonikers, hopDefinitionUploadIDs := orderedMonikers, definitionUploadIDs
+			if cursor.Depth > 0 {
+				hopMonikers, hopDefinitionUploadIDs = cursor.HopMonikers, cursor.HopDefinitionUploadIDs
+			}
+
+			remoteLocations, hasMore, err := r.pageRemoteReferences(ctx, adjustedUploads, hopMonikers, hopDefinitionUploadIDs, uploadsByID, cursor, limit-len(locations))
 			if err != nil {
 				return nil, false, err
 			}
+			if cursor.Depth > 0 {
+				remoteLocations = filterReexportLocations(remoteLocations, cursor.ReexportLocations)
+			}
 			locations = append(locations, remoteLocations...)
 
+			if cursor.Depth+1 < maxDepth {
+				if err := r.collectReexportedMonikers(ctx, remoteLocations, cursor, transitiveDeadline); err != nil {
+					return nil, false, err
+				}
+			}
+
 			if !hasMore {
-				return locations, false, nil
+				ok, err := r.nextReferencesHop(ctx, adjustedUploads, uploadsByID, cursor)
+				if err != nil {
+					return nil, false, err
+				}
+				if !ok {
+					return locations, false, nil
+				}
 			}
 		}
 	}
@@ -281,6 +329,146 @@ func (r *queryResolver) pageReferences(ctx context.Context, adjustedUploads []ad
 	return locations, true, nil
 }
 
+// collectReexportedMonikers adds the export monikers attached to the ranges of the given (remote) locations
+// to the set of monikers searched in the next hop. These are the monikers under which a dependent re-exports
+// the symbol. Monikers that have already been searched are ignored. Collection stops once the given deadline
+// passes, the next hop has monikerLimit monikers or the cursor reaches its size limits.
+func (r *queryResolver) collectReexportedMonikers(ctx context.Context, locations []lsifstore.Location, cursor *referencesCursor, deadline time.Time) error {
+	if !time.Now().Before(deadline) {
+		return nil
+	}
+
+	monikerSet := newQualifiedMonikerSet()
+	for _, monikers := range [][]semantic.QualifiedMonikerData{cursor.OrderedMonikers, cursor.VisitedMonikers, cursor.NextHopMonikers} {
+		for _, moniker := range monikers {
+			monikerSet.add(moniker)
+		}
+	}
+
+	for _, location := range locations {
+		if len(cursor.NextHopMonikers) >= monikerLimit ||
+			len(cursor.VisitedMonikers)+len(cursor.NextHopMonikers) >= maxVisitedMonikers ||
+			len(cursor.ReexportLocations) >= maxReexportLocations ||
+			!time.Now().Before(deadline) {
+			break
+		}
+
+		monikers, err := r.exportMonikersAtLocation(ctx, location)
+		if err != nil {
+			return err
+		}
+
+		reexported := false
+		for _, moniker := range monikers {
+			if monikerSet.contains(moniker) || len(cursor.NextHopMonikers) >= monikerLimit || len(cursor.VisitedMonikers)+len(cursor.NextHopMonikers) >= maxVisitedMonikers {
+				continue
+			}
+
+			monikerSet.add(moniker)
+			cursor.NextHopMonikers = append(cursor.NextHopMonikers, moniker)
+			reexported = true
+		}
+		if reexported {
+			cursor.ReexportLocations = append(cursor.ReexportLocations, location)
+		}
+	}
+
+	return nil
+}
+
+// exportMonikersAtLocation returns the export monikers attached to the ranges enclosing the start of the given
+// location.
+func (r *queryResolver) exportMonikersAtLocation(ctx context.Context, location lsifstore.Location) ([]semantic.QualifiedMonikerData, error) {
+	rangeMonikers, err := r.lsifStore.MonikersByPosition(ctx, location.DumpID, location.Path, location.Range.Start.Line, location.Range.Start.Character)
+	if err != nil {
+		return nil, errors.Wrap(err, "lsifStore.MonikersByPosition")
+	}
+
+	var qualifiedMonikers []semantic.QualifiedMonikerData
+	for _, monikers := range rangeMonikers {
+		for _, moniker := range monikers {
+			if moniker.PackageInformationID == "" || moniker.Kind != "export" {
+				continue
+			}
+
+			packageInformationData, _, err := r.lsifStore.PackageInformation(ctx, location.DumpID, location.Path, string(moniker.PackageInformationID))
+			if err != nil {
+				return nil, errors.Wrap(err, "lsifStore.PackageInformation")
+			}
+
+			qualifiedMonikers = append(qualifiedMonikers, semantic.QualifiedMonikerData{
+				MonikerData:            moniker,
+				PackageInformationData: packageInformationData,
+			})
+		}
+	}
+
+	return qualifiedMonikers, nil
+}
+
+// nextReferencesHop moves the cursor to the next hop if any re-exported monikers were collected during the
+// current hop. The uploads defining the re-exported monikers become the first batch of the next hop's remote
+// phase, mirroring definitionUploadIDsFromCursor. If there is no next hop, a false-valued flag is returned.
+func (r *queryResolver) nextReferencesHop(ctx context.Context, adjustedUploads []adjustedUpload, uploadsByID map[int]dbstore.Dump, cursor *referencesCursor) (bool, error) {
+	if len(cursor.NextHopMonikers) == 0 {
+		return false, nil
+	}
+
+	definitionUploads, err := r.definitionUploads(ctx, cursor.NextHopMonikers)
+	if err != nil {
+		return false, err
+	}
+
+	definitionUploadIDs := make([]int, 0, len(definitionUploads))
+	for i := range definitionUploads {
+		if !isAdjustedUpload(adjustedUploads, definitionUploads[i].ID) {
+			definitionUploadIDs = append(definitionUploadIDs, definitionUploads[i].ID)
+		}
+		uploadsByID[definitionUploads[i].ID] = definitionUploads[i]
+	}
+
+	cursor.Depth++
+	cursor.VisitedMonikers = append(cursor.VisitedMonikers, cursor.NextHopMonikers...)
+	cursor.HopMonikers = cursor.NextHopMonikers
+	cursor.NextHopMonikers = nil
+	cursor.HopDefinitionUploadIDs = definitionUploadIDs
+	cursor.BatchIDs = definitionUploadIDs
+	cursor.RemoteOffset = 0
+	cursor.RemoteBatchOffset = 0
+	return true, nil
+}
+
+// isAdjustedUpload returns true if the given upload identifier is one of the adjusted uploads.
+func isAdjustedUpload(adjustedUploads []adjustedUpload, id int) bool {
+	for i := range adjustedUploads {
+		if adjustedUploads[i].Upload.ID == id {
+			return true
+		}
+	}
+
+	return false
+}
+
+// filterReexportLocations removes the locations that re-export the symbol from the given slice. These
+// locations were already returned by the previous hop, and will be found again as they are also tagged
+// with the re-exported moniker.
+func filterReexportLocations(locations, reexportLocations []lsifstore.Location) []lsifstore.Location {
+	filtered := locations[:0]
+
+outer:
+	for _, location := range locations {
+		for _, reexportLocation := range reexportLocations {
+			if location == reexportLocation {
+				continue outer
+			}
+		}
+
+		filtered = append(filtered, location)
+	}
+
+	return filtered
+}
+
 // pageLocalReferences returns a slice of the (local) result set denoted by the given cursor fulfilled by
 // traversing the LSIF graph. The given cursor will be adjusted to reflect the offsets required to resolve
 // the next page of results. If there are no more pages left in the result set, a false-valued flag is
@@ -4,6 +4,8 @@ import (
 	"encoding/base64"
 	"encoding/json"
 
+	"github.com/cockroachdb/errors"
+
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/stores/lsifstore"
 	"github.com/sourcegraph/sourcegraph/lib/codeintel/semantic"
 )
@@ -21,6 +23,16 @@ type referencesCursor struct {
 	BatchIDs                  []int                           `json:"batchIDs"`
 	RemoteOffset              int                             `json:"remoteOffset"`
 	RemoteBatchOffset         int                             `json:"remoteBatchOffset"`
+
+	// Fields used to follow transitive dependents. Depth is zero while searching for references
+	// of the ordered monikers, and incremented each time we search for references of monikers
+	// re-exported by the previous hop.
+	Depth                  int                             `json:"depth,omitempty"`
+	HopMonikers            []semantic.QualifiedMonikerData `json:"hopMonikers,omitempty"`
+	HopDefinitionUploadIDs []int                           `json:"hopDefinitionUploadIDs,omitempty"`
+	NextHopMonikers        []semantic.QualifiedMonikerData `json:"nextHopMonikers,omitempty"`
+	VisitedMonikers        []semantic.QualifiedMonikerData `json:"visitedMonikers,omitempty"`
+	ReexportLocations      []lsifstore.Location            `json:"reexportLocations,omitempty"`
 }
 
 type cursorAdjustedUpload struct {
@@ -43,8 +55,20 @@ func decodeCursor(rawEncoded string) (referencesCursor, error) {
 	}
 
 	var cursor referencesCursor
-	err = json.Unmarshal(raw, &cursor)
-	return cursor, err
+	if err := json.Unmarshal(raw, &cursor); err != nil {
+		return referencesCursor{}, err
+	}
+
+	// The cursor is provided by the client, so make sure the transitive state does not exceed the
+	// limits enforced while collecting it.
+	if len(cursor.HopMonikers) > monikerLimit ||
+		len(cursor.NextHopMonikers) > monikerLimit ||
+		len(cursor.VisitedMonikers) > maxVisitedMonikers ||
+		len(cursor.ReexportLocations) > maxReexportLocations {
+		return referencesCursor{}, errors.New("cursor exceeds size limits")
+	}
+
+	return cursor, nil
 }
 
 // encodeCursor returns an encoding of the given cursor suitable for a URL or a GraphQL token.
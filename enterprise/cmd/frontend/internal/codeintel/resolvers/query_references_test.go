@@ -50,7 +50,7 @@ func TestReferences(t *testing.T) {
 		uploads,
 		newOperations(&observation.TestContext),
 	)
-	adjustedLocations, _, err := resolver.References(context.Background(), 10, 20, 50, "")
+	adjustedLocations, _, err := resolver.References(context.Background(), 10, 20, 50, 1, "")
 	if err != nil {
 		t.Fatalf("unexpected error querying references: %s", err)
 	}
@@ -171,7 +171,7 @@ func TestReferencesRemote(t *testing.T) {
 		uploads,
 		newOperations(&observation.TestContext),
 	)
-	adjustedLocations, _, err := resolver.References(context.Background(), 10, 20, 50, "")
+	adjustedLocations, _, err := resolver.References(context.Background(), 10, 20, 50, 1, "")
 	if err != nil {
 		t.Fatalf("unexpected error querying references: %s", err)
 	}
@@ -236,3 +236,147 @@ func TestReferencesRemote(t *testing.T) {
 		}
 	}
 }
+
+func TestReferencesTransitive(t *testing.T) {
+	mockDBStore := NewMockDBStore()
+	mockLSIFStore := NewMockLSIFStore()
+	mockGitserverClient := NewMockGitserverClient()
+	mockPositionAdjuster := noopPositionAdjuster()
+	mockGitserverClient.CommitExistsFunc.SetDefaultReturn(true, nil)
+
+	// leftpad.padLeft is referenced by upload #250, which re-exports it as wrapper.padLeft; that
+	// moniker is referenced in turn by upload #350.
+	monikers := []semantic.MonikerData{
+		{Kind: "export", Scheme: "tsc", Identifier: "padLeft", PackageInformationID: "51"},
+		{Kind: "import", Scheme: "tsc", Identifier: "padLeft", PackageInformationID: "51"},
+		{Kind: "export", Scheme: "tsc", Identifier: "wrappedPadLeft", PackageInformationID: "61"},
+	}
+	packageInformation1 := semantic.PackageInformationData{Name: "leftpad", Version: "0.1.0"}
+	packageInformation2 := semantic.PackageInformationData{Name: "wrapper", Version: "1.0.0"}
+
+	mockLSIFStore.MonikersByPositionFunc.PushReturn([][]semantic.MonikerData{{monikers[0]}}, nil)              // target position
+	mockLSIFStore.MonikersByPositionFunc.PushReturn([][]semantic.MonikerData{{monikers[1], monikers[2]}}, nil) // #250 a.go
+	mockLSIFStore.MonikersByPositionFunc.PushReturn(nil, nil)                                                  // #250 b.go
+	mockLSIFStore.PackageInformationFunc.PushReturn(packageInformation1, true, nil)
+	mockLSIFStore.PackageInformationFunc.PushReturn(packageInformation2, true, nil)
+
+	referenceUploads := []dbstore.Dump{
+		{ID: 250, Commit: "deadbeef2", Root: "sub2/"},
+		{ID: 350, Commit: "deadbeef3", Root: "sub3/"},
+	}
+	mockDBStore.DefinitionDumpsFunc.PushReturn(nil, nil)
+	mockDBStore.DefinitionDumpsFunc.PushReturn(referenceUploads[:1], nil)
+	mockDBStore.GetDumpsByIDsFunc.PushReturn(referenceUploads[:1], nil)
+	mockDBStore.GetDumpsByIDsFunc.PushReturn(nil, nil) // empty
+	mockDBStore.GetDumpsByIDsFunc.PushReturn(referenceUploads[1:], nil)
+
+	filter1, err := bloomfilter.CreateFilter([]string{"padLeft"})
+	if err != nil {
+		t.Fatalf("unexpected error encoding bloom filter: %s", err)
+	}
+	filter2, err := bloomfilter.CreateFilter([]string{"wrappedPadLeft"})
+	if err != nil {
+		t.Fatalf("unexpected error encoding bloom filter: %s", err)
+	}
+	scanner1 := dbstore.PackageReferenceScannerFromSlice(lsifstore.PackageReference{Package: lsifstore.Package{DumpID: 250}, Filter: filter1})
+	scanner2 := dbstore.PackageReferenceScannerFromSlice(lsifstore.PackageReference{Package: lsifstore.Package{DumpID: 350}, Filter: filter2})
+	mockDBStore.ReferenceIDsAndFiltersFunc.PushReturn(scanner1, 1, nil)
+	mockDBStore.ReferenceIDsAndFiltersFunc.PushReturn(scanner2, 1, nil)
+
+	monikerLocations := []lsifstore.Location{
+		{DumpID: 250, Path: "a.go", Range: testRange1},
+		{DumpID: 250, Path: "b.go", Range: testRange2},
+		{DumpID: 350, Path: "c.go", Range: testRange3},
+	}
+	mockLSIFStore.BulkMonikerResultsFunc.PushReturn(monikerLocations[0:2], 2, nil) // hop 0: refs
+	mockLSIFStore.BulkMonikerResultsFunc.PushReturn(monikerLocations[0:1], 1, nil) // hop 1: re-export
+	mockLSIFStore.BulkMonikerResultsFunc.PushReturn(monikerLocations[2:3], 1, nil) // hop 1: refs
+
+	uploads := []dbstore.Dump{
+		{ID: 50, Commit: "deadbeef", Root: "sub1/"},
+	}
+	resolver := newQueryResolver(
+		mockDBStore,
+		mockLSIFStore,
+		newCachedCommitChecker(mockGitserverClient),
+		mockPositionAdjuster,
+		42,
+		"deadbeef",
+		"s1/main.go",
+		uploads,
+		newOperations(&observation.TestContext),
+	)
+	adjustedLocations, cursor, err := resolver.References(context.Background(), 10, 20, 50, 2, "")
+	if err != nil {
+		t.Fatalf("unexpected error querying references: %s", err)
+	}
+	if cursor != "" {
+		t.Errorf("unexpected cursor: %q", cursor)
+	}
+
+	expectedLocations := []AdjustedLocation{
+		{Dump: referenceUploads[0], Path: "sub2/a.go", AdjustedCommit: "deadbeef2", AdjustedRange: testRange1},
+		{Dump: referenceUploads[0], Path: "sub2/b.go", AdjustedCommit: "deadbeef2", AdjustedRange: testRange2},
+		{Dump: referenceUploads[1], Path: "sub3/c.go", AdjustedCommit: "deadbeef3", AdjustedRange: testRange3},
+	}
+	if diff := cmp.Diff(expectedLocations, adjustedLocations); diff != "" {
+		t.Errorf("unexpected locations (-want +got):\n%s", diff)
+	}
+
+	if history := mockDBStore.DefinitionDumpsFunc.History(); len(history) != 2 {
+		t.Fatalf("unexpected call count for dbstore.DefinitionDump. want=%d have=%d", 2, len(history))
+	} else {
+		expectedMonikers := []semantic.QualifiedMonikerData{
+			{MonikerData: monikers[2], PackageInformationData: packageInformation2},
+		}
+		if diff := cmp.Diff(expectedMonikers, history[1].Arg1); diff != "" {
+			t.Errorf("unexpected monikers (-want +got):\n%s", diff)
+		}
+	}
+
+	if history := mockLSIFStore.BulkMonikerResultsFunc.History(); len(history) != 3 {
+		t.Fatalf("unexpected call count for lsifstore.BulkMonikerResults. want=%d have=%d", 3, len(history))
+	} else {
+		if diff := cmp.Diff([]int{250}, history[0].Arg2); diff != "" {
+			t.Errorf("unexpected ids (-want +got):\n%s", diff)
+		}
+		if diff := cmp.Diff([]semantic.MonikerData{monikers[0]}, history[0].Arg3); diff != "" {
+			t.Errorf("unexpected monikers (-want +got):\n%s", diff)
+		}
+
+		if diff := cmp.Diff([]int{250}, history[1].Arg2); diff != "" {
+			t.Errorf("unexpected ids (-want +got):\n%s", diff)
+		}
+		if diff := cmp.Diff([]int{350}, history[2].Arg2); diff != "" {
+			t.Errorf("unexpected ids (-want +got):\n%s", diff)
+		}
+		for i := 1; i < 3; i++ {
+			if diff := cmp.Diff([]semantic.MonikerData{monikers[2]}, history[i].Arg3); diff != "" {
+				t.Errorf("unexpected monikers (-want +got):\n%s", diff)
+			}
+		}
+	}
+}
+
+func TestDecodeCursorSizeLimits(t *testing.T) {
+	cursor, err := decodeCursor(encodeCursor(referencesCursor{
+		VisitedMonikers:   make([]semantic.QualifiedMonikerData, maxVisitedMonikers),
+		ReexportLocations: make([]lsifstore.Location, maxReexportLocations),
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error decoding cursor: %s", err)
+	}
+	if len(cursor.VisitedMonikers) != maxVisitedMonikers {
+		t.Errorf("unexpected number of visited monikers. want=%d have=%d", maxVisitedMonikers, len(cursor.VisitedMonikers))
+	}
+
+	for _, cursor := range []referencesCursor{
+		{VisitedMonikers: make([]semantic.QualifiedMonikerData, maxVisitedMonikers+1)},
+		{ReexportLocations: make([]lsifstore.Location, maxReexportLocations+1)},
+		{NextHopMonikers: make([]semantic.QualifiedMonikerData, monikerLimit+1)},
+	} {
+		if _, err := decodeCursor(encodeCursor(cursor)); err == nil {
+			t.Errorf("expected error decoding oversized cursor")
+		}
+	}
+}
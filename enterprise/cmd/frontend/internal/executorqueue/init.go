@@ -18,6 +18,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/observation"
 	"github.com/sourcegraph/sourcegraph/internal/oobmigration"
 	"github.com/sourcegraph/sourcegraph/internal/trace"
+	"github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker"
 )
 
 type configuration interface {
@@ -63,6 +64,11 @@ func Init(ctx context.Context, db dbutil.DB, outOfBandMigrationRunner *oobmigrat
 		"batches":   batches.QueueOptions(db, batchesConfig, observationContext),
 	}
 
+	// Expose the failed indexes to site admins. The store is shared with the executor queue so that
+	// its metrics are only registered once. Failed indexes are also the records users see for their
+	// repositories, so they can only be requeued, not deleted.
+	dbworker.RegisterDomainQueue("codeintel_index", queueOptions["codeintel"].Store)
+
 	handler, err := codeintel.NewCodeIntelUploadHandler(ctx, db, true)
 	if err != nil {
 		return err
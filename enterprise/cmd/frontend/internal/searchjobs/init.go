@@ -0,0 +1,17 @@
+package searchjobs
+
+import (
+	"context"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/enterprise"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/searchjobs"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/searchjobs/resolvers"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/oobmigration"
+)
+
+func Init(ctx context.Context, db dbutil.DB, outOfBandMigrationRunner *oobmigration.Runner, enterpriseServices *enterprise.Services) error {
+	enterpriseServices.SearchJobsResolver = resolvers.NewResolver(db)
+	enterpriseServices.SearchJobsResultsHandler = newResultsHandler(db, searchjobs.NewStore(db))
+	return nil
+}
@@ -0,0 +1,95 @@
+package searchjobs
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/searchjobs"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	streamhttp "github.com/sourcegraph/sourcegraph/internal/search/streaming/http"
+)
+
+// resultsHandler serves the results of a search job in the same formats as
+// search exports. Like an export, an interrupted download is resumed by
+// passing the Seq of the last row received as the "after" parameter.
+type resultsHandler struct {
+	db    dbutil.DB
+	store *searchjobs.Store
+
+	flushInterval time.Duration
+}
+
+func newResultsHandler(db dbutil.DB, store *searchjobs.Store) http.Handler {
+	return &resultsHandler{db: db, store: store, flushInterval: 100 * time.Millisecond}
+}
+
+func (h *resultsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		http.Error(w, "invalid search job id", http.StatusBadRequest)
+		return
+	}
+
+	format, err := streamhttp.ParseExportFormat(r.URL.Query().Get("format"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	after := 0
+	if s := r.URL.Query().Get("after"); s != "" {
+		if after, err = strconv.Atoi(s); err != nil || after < 0 {
+			http.Error(w, "after must be a non-negative integer", http.StatusBadRequest)
+			return
+		}
+	}
+
+	job, err := h.store.GetSearchJob(ctx, id)
+	if err == searchjobs.ErrSearchJobNotFound {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// 🚨 SECURITY: Only site admins and the user who created a search job can
+	// download its results.
+	if err := backend.CheckSiteAdminOrSameUser(ctx, h.db, job.InitiatorID); err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	exportWriter, err := streamhttp.NewExportWriter(w, format)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	seq := after
+	lastFlush := time.Now()
+	err = h.store.ForEachSearchJobResult(ctx, job.ID, after, func(row streamhttp.ExportRow) error {
+		seq++
+		row.Seq = seq
+		if err := exportWriter.Write(row); err != nil {
+			return err
+		}
+		if time.Since(lastFlush) >= h.flushInterval {
+			lastFlush = time.Now()
+			return exportWriter.Flush()
+		}
+		return nil
+	})
+	if flushErr := exportWriter.Flush(); flushErr != nil {
+		return
+	}
+	if err != nil {
+		exportWriter.Error(err)
+	}
+}
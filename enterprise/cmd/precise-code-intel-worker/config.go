@@ -14,6 +14,8 @@ type Config struct {
 	WorkerPollInterval time.Duration
 	WorkerConcurrency  int
 	WorkerBudget       int64
+
+	WorkerConcurrencyPerRepository int
 }
 
 func (c *Config) Load() {
@@ -23,5 +25,6 @@ func (c *Config) Load() {
 
 	c.WorkerPollInterval = c.GetInterval("PRECISE_CODE_INTEL_WORKER_POLL_INTERVAL", "1s", "Interval between queries to the upload queue.")
 	c.WorkerConcurrency = c.GetInt("PRECISE_CODE_INTEL_WORKER_CONCURRENCY", "1", "The maximum number of indexes that can be processed concurrently.")
+	c.WorkerConcurrencyPerRepository = c.GetInt("PRECISE_CODE_INTEL_WORKER_CONCURRENCY_PER_REPOSITORY", "0", "The maximum number of uploads of a single repository that can be processed concurrently. Zero disables the limit.")
 	c.WorkerBudget = int64(c.GetInt("PRECISE_CODE_INTEL_WORKER_BUDGET", "0", "The amount of compressed input data (in bytes) a worker can process concurrently. Zero acts as an infinite budget."))
 }
@@ -21,15 +21,24 @@ type MockWorkerStore struct {
 	// AddExecutionLogEntryFunc is an instance of a mock function object
 	// controlling the behavior of the method AddExecutionLogEntry.
 	AddExecutionLogEntryFunc *WorkerStoreAddExecutionLogEntryFunc
+	// DeleteFailedFunc is an instance of a mock function object controlling
+	// the behavior of the method DeleteFailed.
+	DeleteFailedFunc *WorkerStoreDeleteFailedFunc
 	// DequeueFunc is an instance of a mock function object controlling the
 	// behavior of the method Dequeue.
 	DequeueFunc *WorkerStoreDequeueFunc
+	// DequeueWithKeyFunc is an instance of a mock function object
+	// controlling the behavior of the method DequeueWithKey.
+	DequeueWithKeyFunc *WorkerStoreDequeueWithKeyFunc
 	// HandleFunc is an instance of a mock function object controlling the
 	// behavior of the method Handle.
 	HandleFunc *WorkerStoreHandleFunc
 	// HeartbeatFunc is an instance of a mock function object controlling
 	// the behavior of the method Heartbeat.
 	HeartbeatFunc *WorkerStoreHeartbeatFunc
+	// ListFailedFunc is an instance of a mock function object controlling
+	// the behavior of the method ListFailed.
+	ListFailedFunc *WorkerStoreListFailedFunc
 	// MarkCompleteFunc is an instance of a mock function object controlling
 	// the behavior of the method MarkComplete.
 	MarkCompleteFunc *WorkerStoreMarkCompleteFunc
@@ -45,6 +54,9 @@ type MockWorkerStore struct {
 	// RequeueFunc is an instance of a mock function object controlling the
 	// behavior of the method Requeue.
 	RequeueFunc *WorkerStoreRequeueFunc
+	// RequeueFailedFunc is an instance of a mock function object
+	// controlling the behavior of the method RequeueFailed.
+	RequeueFailedFunc *WorkerStoreRequeueFailedFunc
 	// ResetStalledFunc is an instance of a mock function object controlling
 	// the behavior of the method ResetStalled.
 	ResetStalledFunc *WorkerStoreResetStalledFunc
@@ -62,11 +74,21 @@ func NewMockWorkerStore() *MockWorkerStore {
 				return 0, nil
 			},
 		},
+		DeleteFailedFunc: &WorkerStoreDeleteFailedFunc{
+			defaultHook: func(context.Context, []int) (int, error) {
+				return 0, nil
+			},
+		},
 		DequeueFunc: &WorkerStoreDequeueFunc{
 			defaultHook: func(context.Context, string, []*sqlf.Query) (workerutil.Record, bool, error) {
 				return nil, false, nil
 			},
 		},
+		DequeueWithKeyFunc: &WorkerStoreDequeueWithKeyFunc{
+			defaultHook: func(context.Context, string, []*sqlf.Query, []string) (workerutil.Record, string, bool, error) {
+				return nil, "", false, nil
+			},
+		},
 		HandleFunc: &WorkerStoreHandleFunc{
 			defaultHook: func() *basestore.TransactableHandle {
 				return nil
@@ -77,6 +99,11 @@ func NewMockWorkerStore() *MockWorkerStore {
 				return nil, nil
 			},
 		},
+		ListFailedFunc: &WorkerStoreListFailedFunc{
+			defaultHook: func(context.Context, store.ListFailedOptions) ([]store.FailedRecord, int, error) {
+				return nil, 0, nil
+			},
+		},
 		MarkCompleteFunc: &WorkerStoreMarkCompleteFunc{
 			defaultHook: func(context.Context, int, store.MarkFinalOptions) (bool, error) {
 				return false, nil
@@ -102,6 +129,11 @@ func NewMockWorkerStore() *MockWorkerStore {
 				return nil
 			},
 		},
+		RequeueFailedFunc: &WorkerStoreRequeueFailedFunc{
+			defaultHook: func(context.Context, []int) (int, error) {
+				return 0, nil
+			},
+		},
 		ResetStalledFunc: &WorkerStoreResetStalledFunc{
 			defaultHook: func(context.Context) (map[int]time.Duration, map[int]time.Duration, error) {
 				return nil, nil, nil
@@ -123,15 +155,24 @@ func NewMockWorkerStoreFrom(i store.Store) *MockWorkerStore {
 		AddExecutionLogEntryFunc: &WorkerStoreAddExecutionLogEntryFunc{
 			defaultHook: i.AddExecutionLogEntry,
 		},
+		DeleteFailedFunc: &WorkerStoreDeleteFailedFunc{
+			defaultHook: i.DeleteFailed,
+		},
 		DequeueFunc: &WorkerStoreDequeueFunc{
 			defaultHook: i.Dequeue,
 		},
+		DequeueWithKeyFunc: &WorkerStoreDequeueWithKeyFunc{
+			defaultHook: i.DequeueWithKey,
+		},
 		HandleFunc: &WorkerStoreHandleFunc{
 			defaultHook: i.Handle,
 		},
 		HeartbeatFunc: &WorkerStoreHeartbeatFunc{
 			defaultHook: i.Heartbeat,
 		},
+		ListFailedFunc: &WorkerStoreListFailedFunc{
+			defaultHook: i.ListFailed,
+		},
 		MarkCompleteFunc: &WorkerStoreMarkCompleteFunc{
 			defaultHook: i.MarkComplete,
 		},
@@ -147,6 +188,9 @@ func NewMockWorkerStoreFrom(i store.Store) *MockWorkerStore {
 		RequeueFunc: &WorkerStoreRequeueFunc{
 			defaultHook: i.Requeue,
 		},
+		RequeueFailedFunc: &WorkerStoreRequeueFailedFunc{
+			defaultHook: i.RequeueFailed,
+		},
 		ResetStalledFunc: &WorkerStoreResetStalledFunc{
 			defaultHook: i.ResetStalled,
 		},
@@ -274,6 +318,115 @@ func (c WorkerStoreAddExecutionLogEntryFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
+// WorkerStoreDeleteFailedFunc describes the behavior when the DeleteFailed
+// method of the parent MockWorkerStore instance is invoked.
+type WorkerStoreDeleteFailedFunc struct {
+	defaultHook func(context.Context, []int) (int, error)
+	hooks       []func(context.Context, []int) (int, error)
+	history     []WorkerStoreDeleteFailedFuncCall
+	mutex       sync.Mutex
+}
+
+// DeleteFailed delegates to the next hook function in the queue and stores
+// the parameter and result values of this invocation.
+func (m *MockWorkerStore) DeleteFailed(v0 context.Context, v1 []int) (int, error) {
+	r0, r1 := m.DeleteFailedFunc.nextHook()(v0, v1)
+	m.DeleteFailedFunc.appendCall(WorkerStoreDeleteFailedFuncCall{v0, v1, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the DeleteFailed method
+// of the parent MockWorkerStore instance is invoked and the hook queue is
+// empty.
+func (f *WorkerStoreDeleteFailedFunc) SetDefaultHook(hook func(context.Context, []int) (int, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// DeleteFailed method of the parent MockWorkerStore instance invokes the
+// hook at the front of the queue and discards it. After the queue is empty,
+// the default hook function is invoked for any future action.
+func (f *WorkerStoreDeleteFailedFunc) PushHook(hook func(context.Context, []int) (int, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *WorkerStoreDeleteFailedFunc) SetDefaultReturn(r0 int, r1 error) {
+	f.SetDefaultHook(func(context.Context, []int) (int, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *WorkerStoreDeleteFailedFunc) PushReturn(r0 int, r1 error) {
+	f.PushHook(func(context.Context, []int) (int, error) {
+		return r0, r1
+	})
+}
+
+func (f *WorkerStoreDeleteFailedFunc) nextHook() func(context.Context, []int) (int, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *WorkerStoreDeleteFailedFunc) appendCall(r0 WorkerStoreDeleteFailedFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of WorkerStoreDeleteFailedFuncCall objects
+// describing the invocations of this function.
+func (f *WorkerStoreDeleteFailedFunc) History() []WorkerStoreDeleteFailedFuncCall {
+	f.mutex.Lock()
+	history := make([]WorkerStoreDeleteFailedFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// WorkerStoreDeleteFailedFuncCall is an object that describes an invocation
+// of method DeleteFailed on an instance of MockWorkerStore.
+type WorkerStoreDeleteFailedFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 []int
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 int
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c WorkerStoreDeleteFailedFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c WorkerStoreDeleteFailedFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // WorkerStoreDequeueFunc describes the behavior when the Dequeue method of
 // the parent MockWorkerStore instance is invoked.
 type WorkerStoreDequeueFunc struct {
@@ -389,6 +542,127 @@ func (c WorkerStoreDequeueFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1, c.Result2}
 }
 
+// WorkerStoreDequeueWithKeyFunc describes the behavior when the
+// DequeueWithKey method of the parent MockWorkerStore instance is invoked.
+type WorkerStoreDequeueWithKeyFunc struct {
+	defaultHook func(context.Context, string, []*sqlf.Query, []string) (workerutil.Record, string, bool, error)
+	hooks       []func(context.Context, string, []*sqlf.Query, []string) (workerutil.Record, string, bool, error)
+	history     []WorkerStoreDequeueWithKeyFuncCall
+	mutex       sync.Mutex
+}
+
+// DequeueWithKey delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockWorkerStore) DequeueWithKey(v0 context.Context, v1 string, v2 []*sqlf.Query, v3 []string) (workerutil.Record, string, bool, error) {
+	r0, r1, r2, r3 := m.DequeueWithKeyFunc.nextHook()(v0, v1, v2, v3)
+	m.DequeueWithKeyFunc.appendCall(WorkerStoreDequeueWithKeyFuncCall{v0, v1, v2, v3, r0, r1, r2, r3})
+	return r0, r1, r2, r3
+}
+
+// SetDefaultHook sets function that is called when the DequeueWithKey
+// method of the parent MockWorkerStore instance is invoked and the hook
+// queue is empty.
+func (f *WorkerStoreDequeueWithKeyFunc) SetDefaultHook(hook func(context.Context, string, []*sqlf.Query, []string) (workerutil.Record, string, bool, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// DequeueWithKey method of the parent MockWorkerStore instance invokes the
+// hook at the front of the queue and discards it. After the queue is empty,
+// the default hook function is invoked for any future action.
+func (f *WorkerStoreDequeueWithKeyFunc) PushHook(hook func(context.Context, string, []*sqlf.Query, []string) (workerutil.Record, string, bool, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *WorkerStoreDequeueWithKeyFunc) SetDefaultReturn(r0 workerutil.Record, r1 string, r2 bool, r3 error) {
+	f.SetDefaultHook(func(context.Context, string, []*sqlf.Query, []string) (workerutil.Record, string, bool, error) {
+		return r0, r1, r2, r3
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *WorkerStoreDequeueWithKeyFunc) PushReturn(r0 workerutil.Record, r1 string, r2 bool, r3 error) {
+	f.PushHook(func(context.Context, string, []*sqlf.Query, []string) (workerutil.Record, string, bool, error) {
+		return r0, r1, r2, r3
+	})
+}
+
+func (f *WorkerStoreDequeueWithKeyFunc) nextHook() func(context.Context, string, []*sqlf.Query, []string) (workerutil.Record, string, bool, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *WorkerStoreDequeueWithKeyFunc) appendCall(r0 WorkerStoreDequeueWithKeyFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of WorkerStoreDequeueWithKeyFuncCall objects
+// describing the invocations of this function.
+func (f *WorkerStoreDequeueWithKeyFunc) History() []WorkerStoreDequeueWithKeyFuncCall {
+	f.mutex.Lock()
+	history := make([]WorkerStoreDequeueWithKeyFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// WorkerStoreDequeueWithKeyFuncCall is an object that describes an
+// invocation of method DequeueWithKey on an instance of MockWorkerStore.
+type WorkerStoreDequeueWithKeyFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 string
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 []*sqlf.Query
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 []string
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 workerutil.Record
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 string
+	// Result2 is the value of the 3rd result returned from this method
+	// invocation.
+	Result2 bool
+	// Result3 is the value of the 4th result returned from this method
+	// invocation.
+	Result3 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c WorkerStoreDequeueWithKeyFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c WorkerStoreDequeueWithKeyFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1, c.Result2, c.Result3}
+}
+
 // WorkerStoreHandleFunc describes the behavior when the Handle method of
 // the parent MockWorkerStore instance is invoked.
 type WorkerStoreHandleFunc struct {
@@ -600,6 +874,118 @@ func (c WorkerStoreHeartbeatFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
+// WorkerStoreListFailedFunc describes the behavior when the ListFailed
+// method of the parent MockWorkerStore instance is invoked.
+type WorkerStoreListFailedFunc struct {
+	defaultHook func(context.Context, store.ListFailedOptions) ([]store.FailedRecord, int, error)
+	hooks       []func(context.Context, store.ListFailedOptions) ([]store.FailedRecord, int, error)
+	history     []WorkerStoreListFailedFuncCall
+	mutex       sync.Mutex
+}
+
+// ListFailed delegates to the next hook function in the queue and stores
+// the parameter and result values of this invocation.
+func (m *MockWorkerStore) ListFailed(v0 context.Context, v1 store.ListFailedOptions) ([]store.FailedRecord, int, error) {
+	r0, r1, r2 := m.ListFailedFunc.nextHook()(v0, v1)
+	m.ListFailedFunc.appendCall(WorkerStoreListFailedFuncCall{v0, v1, r0, r1, r2})
+	return r0, r1, r2
+}
+
+// SetDefaultHook sets function that is called when the ListFailed method of
+// the parent MockWorkerStore instance is invoked and the hook queue is
+// empty.
+func (f *WorkerStoreListFailedFunc) SetDefaultHook(hook func(context.Context, store.ListFailedOptions) ([]store.FailedRecord, int, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// ListFailed method of the parent MockWorkerStore instance invokes the hook
+// at the front of the queue and discards it. After the queue is empty, the
+// default hook function is invoked for any future action.
+func (f *WorkerStoreListFailedFunc) PushHook(hook func(context.Context, store.ListFailedOptions) ([]store.FailedRecord, int, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *WorkerStoreListFailedFunc) SetDefaultReturn(r0 []store.FailedRecord, r1 int, r2 error) {
+	f.SetDefaultHook(func(context.Context, store.ListFailedOptions) ([]store.FailedRecord, int, error) {
+		return r0, r1, r2
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *WorkerStoreListFailedFunc) PushReturn(r0 []store.FailedRecord, r1 int, r2 error) {
+	f.PushHook(func(context.Context, store.ListFailedOptions) ([]store.FailedRecord, int, error) {
+		return r0, r1, r2
+	})
+}
+
+func (f *WorkerStoreListFailedFunc) nextHook() func(context.Context, store.ListFailedOptions) ([]store.FailedRecord, int, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *WorkerStoreListFailedFunc) appendCall(r0 WorkerStoreListFailedFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of WorkerStoreListFailedFuncCall objects
+// describing the invocations of this function.
+func (f *WorkerStoreListFailedFunc) History() []WorkerStoreListFailedFuncCall {
+	f.mutex.Lock()
+	history := make([]WorkerStoreListFailedFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// WorkerStoreListFailedFuncCall is an object that describes an invocation
+// of method ListFailed on an instance of MockWorkerStore.
+type WorkerStoreListFailedFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 store.ListFailedOptions
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []store.FailedRecord
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 int
+	// Result2 is the value of the 3rd result returned from this method
+	// invocation.
+	Result2 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c WorkerStoreListFailedFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c WorkerStoreListFailedFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1, c.Result2}
+}
+
 // WorkerStoreMarkCompleteFunc describes the behavior when the MarkComplete
 // method of the parent MockWorkerStore instance is invoked.
 type WorkerStoreMarkCompleteFunc struct {
@@ -1160,6 +1546,115 @@ func (c WorkerStoreRequeueFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0}
 }
 
+// WorkerStoreRequeueFailedFunc describes the behavior when the
+// RequeueFailed method of the parent MockWorkerStore instance is invoked.
+type WorkerStoreRequeueFailedFunc struct {
+	defaultHook func(context.Context, []int) (int, error)
+	hooks       []func(context.Context, []int) (int, error)
+	history     []WorkerStoreRequeueFailedFuncCall
+	mutex       sync.Mutex
+}
+
+// RequeueFailed delegates to the next hook function in the queue and stores
+// the parameter and result values of this invocation.
+func (m *MockWorkerStore) RequeueFailed(v0 context.Context, v1 []int) (int, error) {
+	r0, r1 := m.RequeueFailedFunc.nextHook()(v0, v1)
+	m.RequeueFailedFunc.appendCall(WorkerStoreRequeueFailedFuncCall{v0, v1, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the RequeueFailed method
+// of the parent MockWorkerStore instance is invoked and the hook queue is
+// empty.
+func (f *WorkerStoreRequeueFailedFunc) SetDefaultHook(hook func(context.Context, []int) (int, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// RequeueFailed method of the parent MockWorkerStore instance invokes the
+// hook at the front of the queue and discards it. After the queue is empty,
+// the default hook function is invoked for any future action.
+func (f *WorkerStoreRequeueFailedFunc) PushHook(hook func(context.Context, []int) (int, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *WorkerStoreRequeueFailedFunc) SetDefaultReturn(r0 int, r1 error) {
+	f.SetDefaultHook(func(context.Context, []int) (int, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *WorkerStoreRequeueFailedFunc) PushReturn(r0 int, r1 error) {
+	f.PushHook(func(context.Context, []int) (int, error) {
+		return r0, r1
+	})
+}
+
+func (f *WorkerStoreRequeueFailedFunc) nextHook() func(context.Context, []int) (int, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *WorkerStoreRequeueFailedFunc) appendCall(r0 WorkerStoreRequeueFailedFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of WorkerStoreRequeueFailedFuncCall objects
+// describing the invocations of this function.
+func (f *WorkerStoreRequeueFailedFunc) History() []WorkerStoreRequeueFailedFuncCall {
+	f.mutex.Lock()
+	history := make([]WorkerStoreRequeueFailedFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// WorkerStoreRequeueFailedFuncCall is an object that describes an
+// invocation of method RequeueFailed on an instance of MockWorkerStore.
+type WorkerStoreRequeueFailedFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 []int
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 int
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c WorkerStoreRequeueFailedFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c WorkerStoreRequeueFailedFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // WorkerStoreResetStalledFunc describes the behavior when the ResetStalled
 // method of the parent MockWorkerStore instance is invoked.
 type WorkerStoreResetStalledFunc struct {
@@ -22,6 +22,7 @@ func NewWorker(
 	gitserverClient GitserverClient,
 	pollInterval time.Duration,
 	numProcessorRoutines int,
+	maxConcurrencyPerRepository int,
 	budgetMax int64,
 	workerMetrics workerutil.WorkerMetrics,
 ) *workerutil.Worker {
@@ -38,10 +39,11 @@ func NewWorker(
 	}
 
 	return dbworker.NewWorker(rootContext, workerStore, handler, workerutil.WorkerOptions{
-		Name:              "precise_code_intel_upload_worker",
-		NumHandlers:       numProcessorRoutines,
-		Interval:          pollInterval,
-		HeartbeatInterval: UploadHeartbeatInterval,
-		Metrics:           workerMetrics,
+		Name:                 "precise_code_intel_upload_worker",
+		NumHandlers:          numProcessorRoutines,
+		MaxConcurrencyPerKey: maxConcurrencyPerRepository,
+		Interval:             pollInterval,
+		HeartbeatInterval:    UploadHeartbeatInterval,
+		Metrics:              workerMetrics,
 	})
 }
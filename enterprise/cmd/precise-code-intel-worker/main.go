@@ -102,6 +102,7 @@ func main() {
 		gitserverClient,
 		config.WorkerPollInterval,
 		config.WorkerConcurrency,
+		config.WorkerConcurrencyPerRepository,
 		config.WorkerBudget,
 		makeWorkerMetrics(observationContext),
 	)
@@ -511,6 +511,13 @@ func (s *PermsSyncer) syncRepoPerms(ctx context.Context, repoID api.RepoID, noPe
 		return errors.Wrap(s.permsStore.TouchRepoPermissions(ctx, int32(repoID)), "touch repository permissions")
 	}
 
+	// Some code hosts cannot list the users who have access to a repository, in
+	// which case we rely solely on user-centric syncing.
+	if authz.IsUnimplemented(err) {
+		log15.Debug("PermsSyncer.syncRepoPerms.unimplemented", "repoID", repo.ID, "err", err)
+		return errors.Wrap(s.permsStore.TouchRepoPermissions(ctx, int32(repoID)), "touch repository permissions")
+	}
+
 	if err != nil {
 		// Process partial results if this is an initial fetch.
 		if !noPerms {
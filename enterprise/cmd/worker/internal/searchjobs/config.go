@@ -0,0 +1,17 @@
+package searchjobs
+
+import (
+	"github.com/sourcegraph/sourcegraph/internal/env"
+)
+
+type searchJobsConfig struct {
+	env.BaseConfig
+
+	NumRepoHandlers int
+}
+
+var searchJobsConfigInst = &searchJobsConfig{}
+
+func (c *searchJobsConfig) Load() {
+	c.NumRepoHandlers = c.GetInt("SEARCH_JOBS_REPO_CONCURRENCY", "4", "The maximum number of repositories searched concurrently by search jobs.")
+}
@@ -0,0 +1,31 @@
+package searchjobs
+
+import (
+	"context"
+
+	"github.com/sourcegraph/sourcegraph/cmd/worker/shared"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/searchjobs/background"
+	"github.com/sourcegraph/sourcegraph/internal/env"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+)
+
+type searchJobsJob struct{}
+
+// NewSearchJobsJob returns the job that searches the repositories of search
+// jobs and stores their results.
+func NewSearchJobsJob() shared.Job {
+	return &searchJobsJob{}
+}
+
+func (j *searchJobsJob) Config() []env.Config {
+	return []env.Config{searchJobsConfigInst}
+}
+
+func (j *searchJobsJob) Routines(ctx context.Context) ([]goroutine.BackgroundRoutine, error) {
+	db, err := shared.InitDatabase()
+	if err != nil {
+		return nil, err
+	}
+
+	return background.NewBackgroundJobs(context.Background(), db, searchJobsConfigInst.NumRepoHandlers), nil
+}
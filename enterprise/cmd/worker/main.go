@@ -11,6 +11,7 @@ import (
 
 	"github.com/sourcegraph/sourcegraph/cmd/worker/shared"
 	"github.com/sourcegraph/sourcegraph/enterprise/cmd/worker/internal/codeintel"
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/worker/internal/searchjobs"
 	eiauthz "github.com/sourcegraph/sourcegraph/enterprise/internal/authz"
 	"github.com/sourcegraph/sourcegraph/internal/authz"
 	"github.com/sourcegraph/sourcegraph/internal/conf"
@@ -32,6 +33,7 @@ func main() {
 		"codeintel-auto-indexing":  codeintel.NewIndexingJob(),
 		"codehost-version-syncing": versions.NewSyncingJob(),
 		"insights-job":             insights.NewInsightsJob(),
+		"search-jobs":              searchjobs.NewSearchJobsJob(),
 	})
 }
 
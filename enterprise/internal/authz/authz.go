@@ -10,7 +10,10 @@ import (
 	"github.com/inconshreveable/log15"
 
 	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/authz/bitbucketcloud"
 	"github.com/sourcegraph/sourcegraph/internal/authz/bitbucketserver"
+	"github.com/sourcegraph/sourcegraph/internal/authz/gerrit"
+	"github.com/sourcegraph/sourcegraph/internal/authz/gitea"
 	"github.com/sourcegraph/sourcegraph/internal/authz/github"
 	"github.com/sourcegraph/sourcegraph/internal/authz/gitlab"
 	"github.com/sourcegraph/sourcegraph/internal/authz/perforce"
@@ -52,7 +55,10 @@ func ProvidersFromConfig(
 			extsvc.KindGitHub,
 			extsvc.KindGitLab,
 			extsvc.KindBitbucketServer,
+			extsvc.KindBitbucketCloud,
 			extsvc.KindPerforce,
+			extsvc.KindGitea,
+			extsvc.KindGerrit,
 		},
 		LimitOffset: &database.LimitOffset{
 			Limit: 500, // The number is randomly chosen
@@ -63,7 +69,10 @@ func ProvidersFromConfig(
 		gitHubConns          []*types.GitHubConnection
 		gitLabConns          []*types.GitLabConnection
 		bitbucketServerConns []*types.BitbucketServerConnection
+		bitbucketCloudConns  []*types.BitbucketCloudConnection
 		perforceConns        []*types.PerforceConnection
+		giteaConns           []*types.GiteaConnection
+		gerritConns          []*types.GerritConnection
 	)
 	for {
 		svcs, err := store.List(ctx, opt)
@@ -99,11 +108,26 @@ func ProvidersFromConfig(
 					URN:                       svc.URN(),
 					BitbucketServerConnection: c,
 				})
+			case *schema.BitbucketCloudConnection:
+				bitbucketCloudConns = append(bitbucketCloudConns, &types.BitbucketCloudConnection{
+					URN:                      svc.URN(),
+					BitbucketCloudConnection: c,
+				})
 			case *schema.PerforceConnection:
 				perforceConns = append(perforceConns, &types.PerforceConnection{
 					URN:                svc.URN(),
 					PerforceConnection: c,
 				})
+			case *schema.GiteaConnection:
+				giteaConns = append(giteaConns, &types.GiteaConnection{
+					URN:             svc.URN(),
+					GiteaConnection: c,
+				})
+			case *schema.GerritConnection:
+				gerritConns = append(gerritConns, &types.GerritConnection{
+					URN:              svc.URN(),
+					GerritConnection: c,
+				})
 			default:
 				log15.Error("ProvidersFromConfig", "error", errors.Errorf("unexpected connection type: %T", cfg))
 				continue
@@ -137,6 +161,13 @@ func ProvidersFromConfig(
 		warnings = append(warnings, bbsWarnings...)
 	}
 
+	if len(bitbucketCloudConns) > 0 {
+		bbcProviders, bbcProblems, bbcWarnings := bitbucketcloud.NewAuthzProviders(bitbucketCloudConns)
+		providers = append(providers, bbcProviders...)
+		seriousProblems = append(seriousProblems, bbcProblems...)
+		warnings = append(warnings, bbcWarnings...)
+	}
+
 	if len(perforceConns) > 0 {
 		pfProviders, pfProblems, pfWarnings := perforce.NewAuthzProviders(perforceConns)
 		providers = append(providers, pfProviders...)
@@ -144,6 +175,20 @@ func ProvidersFromConfig(
 		warnings = append(warnings, pfWarnings...)
 	}
 
+	if len(giteaConns) > 0 {
+		gtProviders, gtProblems, gtWarnings := gitea.NewAuthzProviders(giteaConns)
+		providers = append(providers, gtProviders...)
+		seriousProblems = append(seriousProblems, gtProblems...)
+		warnings = append(warnings, gtWarnings...)
+	}
+
+	if len(gerritConns) > 0 {
+		grProviders, grProblems, grWarnings := gerrit.NewAuthzProviders(gerritConns)
+		providers = append(providers, grProviders...)
+		seriousProblems = append(seriousProblems, grProblems...)
+		warnings = append(warnings, grWarnings...)
+	}
+
 	// 🚨 SECURITY: Warn the admin when both code host authz provider and the permissions user mapping are configured.
 	if cfg.SiteConfiguration.PermissionsUserMapping != nil &&
 		cfg.SiteConfiguration.PermissionsUserMapping.Enabled {
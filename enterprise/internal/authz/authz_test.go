@@ -505,7 +505,10 @@ type fakeStore struct {
 	gitlabs          []*schema.GitLabConnection
 	githubs          []*schema.GitHubConnection
 	bitbucketServers []*schema.BitbucketServerConnection
+	bitbucketClouds  []*schema.BitbucketCloudConnection
 	perforces        []*schema.PerforceConnection
+	giteas           []*schema.GiteaConnection
+	gerrits          []*schema.GerritConnection
 }
 
 func (s fakeStore) List(ctx context.Context, opt database.ExternalServicesListOptions) ([]*types.ExternalService, error) {
@@ -541,6 +544,13 @@ func (s fakeStore) List(ctx context.Context, opt database.ExternalServicesListOp
 					Config: mustMarshalJSONString(bbs),
 				})
 			}
+		case extsvc.KindBitbucketCloud:
+			for _, bbc := range s.bitbucketClouds {
+				svcs = append(svcs, &types.ExternalService{
+					Kind:   kind,
+					Config: mustMarshalJSONString(bbc),
+				})
+			}
 		case extsvc.KindPerforce:
 			for _, p := range s.perforces {
 				svcs = append(svcs, &types.ExternalService{
@@ -548,6 +558,20 @@ func (s fakeStore) List(ctx context.Context, opt database.ExternalServicesListOp
 					Config: mustMarshalJSONString(p),
 				})
 			}
+		case extsvc.KindGitea:
+			for _, g := range s.giteas {
+				svcs = append(svcs, &types.ExternalService{
+					Kind:   kind,
+					Config: mustMarshalJSONString(g),
+				})
+			}
+		case extsvc.KindGerrit:
+			for _, g := range s.gerrits {
+				svcs = append(svcs, &types.ExternalService{
+					Kind:   kind,
+					Config: mustMarshalJSONString(g),
+				})
+			}
 		default:
 			return nil, errors.Errorf("unexpected kind: %s", kind)
 		}
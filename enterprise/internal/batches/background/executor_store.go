@@ -41,6 +41,9 @@ var executorWorkerStoreOptions = dbworkerstore.Options{
 	MaxNumResets:      executorMaximumNumResets,
 	// Explicitly disable retries.
 	MaxNumRetries: 0,
+	// Round-robin across users so that one user's large batch spec does not
+	// delay the executions of all other users.
+	FairnessKeyExpression: sqlf.Sprintf("batch_spec_executions.user_id"),
 }
 
 // NewExecutorStore creates a dbworker store that wraps the batch_spec_executions
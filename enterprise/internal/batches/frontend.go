@@ -34,6 +34,8 @@ func InitFrontend(ctx context.Context, db dbutil.DB, outOfBandMigrationRunner *o
 	enterpriseServices.GitHubWebhook = webhooks.NewGitHubWebhook(cstore)
 	enterpriseServices.BitbucketServerWebhook = webhooks.NewBitbucketServerWebhook(cstore)
 	enterpriseServices.GitLabWebhook = webhooks.NewGitLabWebhook(cstore)
+	enterpriseServices.BitbucketCloudWebhook = webhooks.NewBitbucketCloudWebhook(cstore)
+	enterpriseServices.AWSCodeCommitWebhook = webhooks.NewAWSCodeCommitWebhook(cstore)
 
 	return background.RegisterMigrations(cstore, outOfBandMigrationRunner)
 }
@@ -0,0 +1,253 @@
+package sources
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/aws-sdk-go-v2/config"
+	awscredentials "github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/cockroachdb/errors"
+	"golang.org/x/net/http2"
+
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/auth"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/awscodecommit"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/jsonc"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// AWSCodeCommitSource is a ChangesetSource for AWS CodeCommit. API requests
+// always use the access keys of the external service, since CodeCommit has
+// no per-user API tokens. The authenticator is only used to push commits with
+// HTTPS Git credentials.
+type AWSCodeCommitSource struct {
+	client *awscodecommit.Client
+	au     auth.Authenticator
+}
+
+// NewAWSCodeCommitSource returns a new AWSCodeCommitSource from the given external service.
+func NewAWSCodeCommitSource(svc *types.ExternalService, cf *httpcli.Factory) (*AWSCodeCommitSource, error) {
+	var c schema.AWSCodeCommitConnection
+	if err := jsonc.Unmarshal(svc.Config, &c); err != nil {
+		return nil, errors.Errorf("external service id=%d config error: %s", svc.ID, err)
+	}
+	return newAWSCodeCommitSource(&c, cf, nil)
+}
+
+func newAWSCodeCommitSource(c *schema.AWSCodeCommitConnection, cf *httpcli.Factory, au auth.Authenticator) (*AWSCodeCommitSource, error) {
+	if cf == nil {
+		cf = httpcli.NewExternalHTTPClientFactory()
+	}
+
+	cli, err := cf.Doer(func(c *http.Client) error {
+		tr := awshttp.NewBuildableClient().GetTransport()
+		if err := http2.ConfigureTransport(tr); err != nil {
+			return err
+		}
+		c.Transport = tr
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	awsConfig, err := config.LoadDefaultConfig(context.Background(),
+		config.WithRegion(c.Region),
+		config.WithCredentialsProvider(
+			awscredentials.StaticCredentialsProvider{
+				Value: aws.Credentials{
+					AccessKeyID:     c.AccessKeyID,
+					SecretAccessKey: c.SecretAccessKey,
+					Source:          "sourcegraph-site-configuration",
+				},
+			},
+		),
+		config.WithHTTPClient(cli),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if au == nil {
+		au = &auth.BasicAuth{Username: c.GitCredentials.Username, Password: c.GitCredentials.Password}
+	}
+
+	return &AWSCodeCommitSource{
+		client: awscodecommit.NewClient(awsConfig),
+		au:     au,
+	}, nil
+}
+
+func (s AWSCodeCommitSource) GitserverPushConfig(ctx context.Context, store *database.ExternalServiceStore, repo *types.Repo) (*protocol.PushConfig, error) {
+	return gitserverPushConfig(ctx, store, repo, s.au)
+}
+
+func (s AWSCodeCommitSource) WithAuthenticator(a auth.Authenticator) (ChangesetSource, error) {
+	switch a.(type) {
+	case *auth.BasicAuth,
+		*auth.BasicAuthWithSSH:
+		break
+
+	default:
+		return nil, newUnsupportedAuthenticatorError("AWSCodeCommitSource", a)
+	}
+
+	return &AWSCodeCommitSource{
+		client: s.client,
+		au:     a,
+	}, nil
+}
+
+// ValidateAuthenticator always succeeds: HTTPS Git credentials cannot be
+// checked through the AWS CodeCommit API, so invalid credentials only
+// surface when pushing.
+func (s AWSCodeCommitSource) ValidateAuthenticator(ctx context.Context) error {
+	return nil
+}
+
+// CreateChangeset creates the given *Changeset in the code host. If an open
+// pull request for the same branches already exists, it is used instead.
+func (s AWSCodeCommitSource) CreateChangeset(ctx context.Context, c *Changeset) (bool, error) {
+	repo := c.Repo.Metadata.(*awscodecommit.Repository)
+	source := git.EnsureRefPrefix(c.HeadRef)
+	destination := git.EnsureRefPrefix(c.BaseRef)
+
+	exists := true
+	pr, err := s.client.FindOpenPullRequest(ctx, repo.Name, source, destination)
+	if err != nil {
+		return false, errors.Wrap(err, "looking for existing pull request")
+	}
+	if pr == nil {
+		exists = false
+		pr, err = s.client.CreatePullRequest(ctx, &awscodecommit.CreatePullRequestInput{
+			RepositoryName:       repo.Name,
+			Title:                c.Title,
+			Description:          c.Body,
+			SourceReference:      source,
+			DestinationReference: destination,
+		})
+		if err != nil {
+			return false, err
+		}
+	}
+
+	if err = c.SetMetadata(pr); err != nil {
+		return false, errors.Wrap(err, "setting changeset metadata")
+	}
+
+	return exists, nil
+}
+
+// CloseChangeset closes the given *Changeset on the code host and updates the
+// Metadata column in the *batches.Changeset to the newly closed pull request.
+func (s AWSCodeCommitSource) CloseChangeset(ctx context.Context, c *Changeset) error {
+	pr, ok := c.Changeset.Metadata.(*awscodecommit.PullRequest)
+	if !ok {
+		return errors.New("Changeset is not an AWS CodeCommit pull request")
+	}
+
+	closed, err := s.client.ClosePullRequest(ctx, pr.ID)
+	if err != nil {
+		return err
+	}
+
+	return c.Changeset.SetMetadata(closed)
+}
+
+// LoadChangeset loads the latest state of the given Changeset from the codehost.
+func (s AWSCodeCommitSource) LoadChangeset(ctx context.Context, cs *Changeset) error {
+	pr, err := s.client.GetPullRequest(ctx, cs.ExternalID)
+	if err != nil {
+		if awscodecommit.IsPullRequestNotFound(err) {
+			return ChangesetNotFoundError{Changeset: cs}
+		}
+		return err
+	}
+
+	if err = cs.SetMetadata(pr); err != nil {
+		return errors.Wrap(err, "setting changeset metadata")
+	}
+
+	return nil
+}
+
+// UpdateChangeset updates the title and description of the pull request.
+// AWS CodeCommit does not allow changing the destination branch of a pull
+// request.
+func (s AWSCodeCommitSource) UpdateChangeset(ctx context.Context, c *Changeset) error {
+	pr, ok := c.Changeset.Metadata.(*awscodecommit.PullRequest)
+	if !ok {
+		return errors.New("Changeset is not an AWS CodeCommit pull request")
+	}
+
+	if git.EnsureRefPrefix(c.BaseRef) != git.EnsureRefPrefix(pr.DestinationReference) {
+		return errors.New("AWS CodeCommit does not support changing the base branch of a pull request")
+	}
+
+	updated, err := s.client.UpdatePullRequest(ctx, pr, c.Title, c.Body)
+	if err != nil {
+		return err
+	}
+
+	return c.Changeset.SetMetadata(updated)
+}
+
+// ReopenChangeset opens a new pull request for the branches of the closed
+// *Changeset, since AWS CodeCommit cannot reopen closed pull requests. The
+// Metadata column, and with it the external ID, of the *batches.Changeset is
+// updated to the new pull request.
+func (s AWSCodeCommitSource) ReopenChangeset(ctx context.Context, c *Changeset) error {
+	pr, ok := c.Changeset.Metadata.(*awscodecommit.PullRequest)
+	if !ok {
+		return errors.New("Changeset is not an AWS CodeCommit pull request")
+	}
+
+	if c.Title == "" {
+		c.Title = pr.Title
+		c.Body = pr.Description
+	}
+	if c.HeadRef == "" {
+		c.HeadRef = pr.SourceReference
+	}
+	if c.BaseRef == "" {
+		c.BaseRef = pr.DestinationReference
+	}
+
+	_, err := s.CreateChangeset(ctx, c)
+	return err
+}
+
+// CreateComment posts a comment on the Changeset.
+func (s AWSCodeCommitSource) CreateComment(ctx context.Context, c *Changeset, text string) error {
+	pr, ok := c.Changeset.Metadata.(*awscodecommit.PullRequest)
+	if !ok {
+		return errors.New("Changeset is not an AWS CodeCommit pull request")
+	}
+
+	return s.client.CreatePullRequestComment(ctx, pr, text)
+}
+
+// MergeChangeset merges a Changeset on the code host, if in a mergeable state.
+// If squash is false, a three-way merge is performed.
+func (s AWSCodeCommitSource) MergeChangeset(ctx context.Context, c *Changeset, squash bool) error {
+	pr, ok := c.Changeset.Metadata.(*awscodecommit.PullRequest)
+	if !ok {
+		return errors.New("Changeset is not an AWS CodeCommit pull request")
+	}
+
+	merged, err := s.client.MergePullRequest(ctx, pr, squash)
+	if err != nil {
+		if awscodecommit.IsNotMergeable(err) {
+			return &ChangesetNotMergeableError{ErrorMsg: err.Error()}
+		}
+		return err
+	}
+
+	return c.Changeset.SetMetadata(merged)
+}
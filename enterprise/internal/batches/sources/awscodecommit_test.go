@@ -0,0 +1,359 @@
+package sources
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/errors"
+
+	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/auth"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/awscodecommit"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// newAWSCodeCommitTestSource returns an AWSCodeCommitSource whose API requests
+// are sent to a fake CodeCommit API. handler is called with the name of the
+// requested operation, such as "GetPullRequest", and the decoded request
+// body.
+func newAWSCodeCommitTestSource(t *testing.T, handler func(w http.ResponseWriter, op string, input map[string]interface{})) *AWSCodeCommitSource {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var input map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			t.Error(err)
+		}
+		op := strings.TrimPrefix(r.Header.Get("X-Amz-Target"), "CodeCommit_20150413.")
+		w.Header().Set("Content-Type", "application/x-amz-json-1.1")
+		handler(w, op, input)
+	}))
+	t.Cleanup(srv.Close)
+
+	srvURL, err := url.Parse(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The SDK cannot add a custom CA bundle to a client wrapped in
+	// middleware, so ignore one configured in the environment.
+	if bundle, ok := os.LookupEnv("AWS_CA_BUNDLE"); ok {
+		os.Unsetenv("AWS_CA_BUNDLE")
+		t.Cleanup(func() { os.Setenv("AWS_CA_BUNDLE", bundle) })
+	}
+
+	// Redirect all requests from the AWS endpoint to the fake API.
+	cf := httpcli.NewFactory(func(cli httpcli.Doer) httpcli.Doer {
+		return httpcli.DoerFunc(func(req *http.Request) (*http.Response, error) {
+			req.URL.Scheme = srvURL.Scheme
+			req.URL.Host = srvURL.Host
+			return cli.Do(req)
+		})
+	})
+
+	src, err := newAWSCodeCommitSource(&schema.AWSCodeCommitConnection{
+		Region:          "us-west-1",
+		AccessKeyID:     "key",
+		SecretAccessKey: "secret",
+		GitCredentials:  schema.AWSCodeCommitGitCredentials{Username: "user", Password: "pass"},
+	}, cf, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return src
+}
+
+// awsPullRequest returns the API representation of an open pull request
+// created by the tests.
+func awsPullRequest(id string) map[string]interface{} {
+	return map[string]interface{}{
+		"pullRequestId":     id,
+		"title":             "Title",
+		"description":       "Body",
+		"pullRequestStatus": "OPEN",
+		"revisionId":        "rev-" + id,
+		"pullRequestTargets": []map[string]interface{}{{
+			"repositoryName":       "repo",
+			"sourceReference":      "refs/heads/batch",
+			"destinationReference": "refs/heads/main",
+		}},
+	}
+}
+
+// writeAWSPullRequestDetails answers the requests made to load the approvals
+// and events of a pull request. It reports whether op was one of them.
+func writeAWSPullRequestDetails(t *testing.T, w http.ResponseWriter, op string) bool {
+	switch op {
+	case "GetPullRequestApprovalStates":
+		writeJSON(t, w, map[string]interface{}{"approvals": []interface{}{}})
+	case "DescribePullRequestEvents":
+		writeJSON(t, w, map[string]interface{}{"pullRequestEvents": []interface{}{}})
+	default:
+		return false
+	}
+	return true
+}
+
+func TestAWSCodeCommitSource_CreateChangeset(t *testing.T) {
+	repo := &types.Repo{Metadata: &awscodecommit.Repository{Name: "repo"}}
+
+	for name, tc := range map[string]struct {
+		existing   []string
+		wantExists bool
+		wantID     string
+	}{
+		"new":      {wantExists: false, wantID: "2"},
+		"existing": {existing: []string{"1"}, wantExists: true, wantID: "1"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			var created bool
+			src := newAWSCodeCommitTestSource(t, func(w http.ResponseWriter, op string, input map[string]interface{}) {
+				if writeAWSPullRequestDetails(t, w, op) {
+					return
+				}
+
+				switch op {
+				case "ListPullRequests":
+					if input["repositoryName"] != "repo" || input["pullRequestStatus"] != "OPEN" {
+						t.Errorf("unexpected input %v", input)
+					}
+					writeJSON(t, w, map[string]interface{}{"pullRequestIds": tc.existing})
+				case "GetPullRequest":
+					writeJSON(t, w, map[string]interface{}{"pullRequest": awsPullRequest(input["pullRequestId"].(string))})
+				case "CreatePullRequest":
+					created = true
+					targets := input["targets"].([]interface{})
+					target := targets[0].(map[string]interface{})
+					if input["title"] != "Title" || input["description"] != "Body" ||
+						target["repositoryName"] != "repo" ||
+						target["sourceReference"] != "refs/heads/batch" ||
+						target["destinationReference"] != "refs/heads/main" {
+						t.Errorf("unexpected input %v", input)
+					}
+					writeJSON(t, w, map[string]interface{}{"pullRequest": awsPullRequest("2")})
+				default:
+					t.Errorf("unexpected operation %q", op)
+					w.WriteHeader(http.StatusBadRequest)
+				}
+			})
+
+			cs := &Changeset{
+				Title:     "Title",
+				Body:      "Body",
+				HeadRef:   "batch",
+				BaseRef:   "main",
+				Repo:      repo,
+				Changeset: &btypes.Changeset{},
+			}
+			exists, err := src.CreateChangeset(context.Background(), cs)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if exists != tc.wantExists {
+				t.Errorf("exists = %v, want %v", exists, tc.wantExists)
+			}
+			if created == tc.wantExists {
+				t.Errorf("created = %v, want %v", created, !tc.wantExists)
+			}
+			if cs.ExternalID != tc.wantID {
+				t.Errorf("ExternalID = %q, want %q", cs.ExternalID, tc.wantID)
+			}
+			if cs.ExternalServiceType != extsvc.TypeAWSCodeCommit {
+				t.Errorf("ExternalServiceType = %q", cs.ExternalServiceType)
+			}
+			if pr := cs.Changeset.Metadata.(*awscodecommit.PullRequest); pr.Region != "us-west-1" {
+				t.Errorf("unexpected region %q", pr.Region)
+			}
+		})
+	}
+}
+
+func TestAWSCodeCommitSource_LoadChangeset_NotFound(t *testing.T) {
+	src := newAWSCodeCommitTestSource(t, func(w http.ResponseWriter, op string, input map[string]interface{}) {
+		w.WriteHeader(http.StatusBadRequest)
+		writeJSON(t, w, map[string]interface{}{
+			"__type":  "PullRequestDoesNotExistException",
+			"message": "pull request does not exist",
+		})
+	})
+
+	cs := &Changeset{
+		Repo:      &types.Repo{Metadata: &awscodecommit.Repository{Name: "repo"}},
+		Changeset: &btypes.Changeset{ExternalID: "42"},
+	}
+	err := src.LoadChangeset(context.Background(), cs)
+	if !errors.HasType(err, ChangesetNotFoundError{}) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestAWSCodeCommitSource_UpdateChangeset(t *testing.T) {
+	t.Run("title and description", func(t *testing.T) {
+		var ops []string
+		src := newAWSCodeCommitTestSource(t, func(w http.ResponseWriter, op string, input map[string]interface{}) {
+			if writeAWSPullRequestDetails(t, w, op) {
+				return
+			}
+
+			ops = append(ops, op)
+			switch op {
+			case "UpdatePullRequestTitle":
+				if input["title"] != "New title" {
+					t.Errorf("unexpected input %v", input)
+				}
+				writeJSON(t, w, map[string]interface{}{"pullRequest": awsPullRequest("1")})
+			case "GetPullRequest":
+				writeJSON(t, w, map[string]interface{}{"pullRequest": awsPullRequest("1")})
+			default:
+				t.Errorf("unexpected operation %q", op)
+				w.WriteHeader(http.StatusBadRequest)
+			}
+		})
+
+		cs := &Changeset{
+			Title:   "New title",
+			Body:    "Body",
+			BaseRef: "main",
+			Changeset: &btypes.Changeset{Metadata: &awscodecommit.PullRequest{
+				ID:                   "1",
+				Title:                "Title",
+				Description:          "Body",
+				DestinationReference: "refs/heads/main",
+			}},
+		}
+		if err := src.UpdateChangeset(context.Background(), cs); err != nil {
+			t.Fatal(err)
+		}
+		if want := []string{"UpdatePullRequestTitle", "GetPullRequest"}; strings.Join(ops, ",") != strings.Join(want, ",") {
+			t.Errorf("operations = %v, want %v", ops, want)
+		}
+	})
+
+	t.Run("base branch changed", func(t *testing.T) {
+		src := newAWSCodeCommitTestSource(t, func(w http.ResponseWriter, op string, input map[string]interface{}) {
+			t.Errorf("unexpected operation %q", op)
+			w.WriteHeader(http.StatusBadRequest)
+		})
+
+		cs := &Changeset{
+			Title:   "Title",
+			BaseRef: "develop",
+			Changeset: &btypes.Changeset{Metadata: &awscodecommit.PullRequest{
+				ID:                   "1",
+				DestinationReference: "refs/heads/main",
+			}},
+		}
+		if err := src.UpdateChangeset(context.Background(), cs); err == nil {
+			t.Fatal("expected error changing the base branch")
+		}
+	})
+}
+
+func TestAWSCodeCommitSource_MergeChangeset(t *testing.T) {
+	for name, tc := range map[string]struct {
+		squash  bool
+		wantOp  string
+		errType string
+		wantErr bool
+	}{
+		"three-way":     {squash: false, wantOp: "MergePullRequestByThreeWay"},
+		"squash":        {squash: true, wantOp: "MergePullRequestBySquash"},
+		"not mergeable": {squash: true, wantOp: "MergePullRequestBySquash", errType: "ManualMergeRequiredException", wantErr: true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			src := newAWSCodeCommitTestSource(t, func(w http.ResponseWriter, op string, input map[string]interface{}) {
+				if writeAWSPullRequestDetails(t, w, op) {
+					return
+				}
+
+				if op != tc.wantOp {
+					t.Errorf("unexpected operation %q", op)
+				}
+				if input["sourceCommitId"] != "abc" {
+					t.Errorf("unexpected input %v", input)
+				}
+				if tc.errType != "" {
+					w.WriteHeader(http.StatusBadRequest)
+					writeJSON(t, w, map[string]interface{}{"__type": tc.errType, "message": "cannot merge"})
+					return
+				}
+				pr := awsPullRequest("1")
+				pr["pullRequestStatus"] = "CLOSED"
+				writeJSON(t, w, map[string]interface{}{"pullRequest": pr})
+			})
+
+			cs := &Changeset{
+				Changeset: &btypes.Changeset{Metadata: &awscodecommit.PullRequest{
+					ID:             "1",
+					RepositoryName: "repo",
+					SourceCommit:   "abc",
+					Status:         "OPEN",
+				}},
+			}
+			err := src.MergeChangeset(context.Background(), cs, tc.squash)
+			if tc.wantErr {
+				if !errors.HasType(err, &ChangesetNotMergeableError{}) {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if pr := cs.Changeset.Metadata.(*awscodecommit.PullRequest); pr.Status != "CLOSED" {
+				t.Errorf("unexpected status %q", pr.Status)
+			}
+		})
+	}
+}
+
+func TestAWSCodeCommitSource_WithAuthenticator(t *testing.T) {
+	src := newAWSCodeCommitTestSource(t, func(w http.ResponseWriter, op string, input map[string]interface{}) {})
+
+	t.Run("supported", func(t *testing.T) {
+		for name, tc := range map[string]auth.Authenticator{
+			"BasicAuth":        &auth.BasicAuth{Username: "a", Password: "b"},
+			"BasicAuthWithSSH": &auth.BasicAuthWithSSH{BasicAuth: auth.BasicAuth{Username: "a", Password: "b"}},
+		} {
+			t.Run(name, func(t *testing.T) {
+				have, err := src.WithAuthenticator(tc)
+				if err != nil {
+					t.Fatalf("unexpected non-nil error: %v", err)
+				}
+				as, ok := have.(*AWSCodeCommitSource)
+				if !ok {
+					t.Fatal("cannot coerce Source into AWSCodeCommitSource")
+				}
+				if as.au != tc {
+					t.Errorf("incorrect authenticator: have=%v want=%v", as.au, tc)
+				}
+			})
+		}
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		for name, tc := range map[string]auth.Authenticator{
+			"nil":              nil,
+			"OAuthBearerToken": &auth.OAuthBearerToken{},
+		} {
+			t.Run(name, func(t *testing.T) {
+				have, err := src.WithAuthenticator(tc)
+				if !errors.HasType(err, UnsupportedAuthenticatorError{}) {
+					t.Errorf("unexpected error of type %T: %v", err, err)
+				}
+				if have != nil {
+					t.Errorf("expected nil Source: %v", have)
+				}
+			})
+		}
+	})
+}
@@ -0,0 +1,269 @@
+package sources
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/auth"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketcloud"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/jsonc"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+type BitbucketCloudSource struct {
+	client *bitbucketcloud.Client
+	au     auth.Authenticator
+}
+
+// NewBitbucketCloudSource returns a new BitbucketCloudSource from the given external service.
+func NewBitbucketCloudSource(svc *types.ExternalService, cf *httpcli.Factory) (*BitbucketCloudSource, error) {
+	var c schema.BitbucketCloudConnection
+	if err := jsonc.Unmarshal(svc.Config, &c); err != nil {
+		return nil, errors.Errorf("external service id=%d config error: %s", svc.ID, err)
+	}
+	return newBitbucketCloudSource(&c, cf, nil)
+}
+
+func newBitbucketCloudSource(c *schema.BitbucketCloudConnection, cf *httpcli.Factory, au auth.Authenticator) (*BitbucketCloudSource, error) {
+	if c.ApiURL == "" {
+		c.ApiURL = "https://api.bitbucket.org"
+	}
+	apiURL, err := url.Parse(c.ApiURL)
+	if err != nil {
+		return nil, err
+	}
+	apiURL = extsvc.NormalizeBaseURL(apiURL)
+
+	if cf == nil {
+		cf = httpcli.NewExternalHTTPClientFactory()
+	}
+
+	cli, err := cf.Doer()
+	if err != nil {
+		return nil, err
+	}
+
+	if au == nil {
+		au = &auth.BasicAuth{Username: c.Username, Password: c.AppPassword}
+	}
+
+	s := &BitbucketCloudSource{client: bitbucketcloud.NewClient(apiURL, cli)}
+	return s.withAuthenticator(au)
+}
+
+func (s BitbucketCloudSource) GitserverPushConfig(ctx context.Context, store *database.ExternalServiceStore, repo *types.Repo) (*protocol.PushConfig, error) {
+	return gitserverPushConfig(ctx, store, repo, s.au)
+}
+
+func (s BitbucketCloudSource) WithAuthenticator(a auth.Authenticator) (ChangesetSource, error) {
+	src, err := s.withAuthenticator(a)
+	if err != nil {
+		return nil, err
+	}
+	return src, nil
+}
+
+func (s BitbucketCloudSource) withAuthenticator(a auth.Authenticator) (*BitbucketCloudSource, error) {
+	// Bitbucket Cloud only supports app passwords, which are sent using
+	// basic authentication.
+	switch a := a.(type) {
+	case *auth.BasicAuth:
+		return &BitbucketCloudSource{
+			client: s.client.WithCredentials(a.Username, a.Password),
+			au:     a,
+		}, nil
+	case *auth.BasicAuthWithSSH:
+		return &BitbucketCloudSource{
+			client: s.client.WithCredentials(a.Username, a.Password),
+			au:     a,
+		}, nil
+	default:
+		return nil, newUnsupportedAuthenticatorError("BitbucketCloudSource", a)
+	}
+}
+
+func (s BitbucketCloudSource) ValidateAuthenticator(ctx context.Context) error {
+	_, err := s.client.CurrentUser(ctx)
+	return err
+}
+
+// CreateChangeset creates the given *Changeset in the code host. If an open
+// pull request for the same branches already exists, it is used instead.
+func (s BitbucketCloudSource) CreateChangeset(ctx context.Context, c *Changeset) (bool, error) {
+	repo := c.Repo.Metadata.(*bitbucketcloud.Repo)
+	source := git.AbbreviateRef(c.HeadRef)
+	destination := git.AbbreviateRef(c.BaseRef)
+
+	exists := true
+	pr, err := s.client.FindOpenPullRequest(ctx, repo.FullName, source, destination)
+	if err != nil {
+		return false, errors.Wrap(err, "looking for existing pull request")
+	}
+	if pr == nil {
+		exists = false
+		pr, err = s.client.CreatePullRequest(ctx, repo.FullName, &bitbucketcloud.PullRequestInput{
+			Title:             c.Title,
+			Description:       c.Body,
+			SourceBranch:      source,
+			DestinationBranch: destination,
+		})
+		if err != nil {
+			return false, err
+		}
+	}
+
+	if err := s.loadPullRequestData(ctx, repo, pr); err != nil {
+		return false, errors.Wrap(err, "loading extra metadata")
+	}
+	if err = c.SetMetadata(pr); err != nil {
+		return false, errors.Wrap(err, "setting changeset metadata")
+	}
+
+	return exists, nil
+}
+
+// CloseChangeset declines the given *Changeset on the code host and updates
+// the Metadata column in the *batches.Changeset to the declined pull request.
+func (s BitbucketCloudSource) CloseChangeset(ctx context.Context, c *Changeset) error {
+	pr, ok := c.Changeset.Metadata.(*bitbucketcloud.PullRequest)
+	if !ok {
+		return errors.New("Changeset is not a Bitbucket Cloud pull request")
+	}
+	repo := c.Repo.Metadata.(*bitbucketcloud.Repo)
+
+	declined, err := s.client.DeclinePullRequest(ctx, repo.FullName, pr.ID)
+	if err != nil {
+		return err
+	}
+
+	return c.Changeset.SetMetadata(declined)
+}
+
+// LoadChangeset loads the latest state of the given Changeset from the codehost.
+func (s BitbucketCloudSource) LoadChangeset(ctx context.Context, cs *Changeset) error {
+	repo := cs.Repo.Metadata.(*bitbucketcloud.Repo)
+	id, err := strconv.ParseInt(cs.ExternalID, 10, 64)
+	if err != nil {
+		return err
+	}
+
+	pr, err := s.client.PullRequest(ctx, repo.FullName, id)
+	if err != nil {
+		if errcode.IsNotFound(err) {
+			return ChangesetNotFoundError{Changeset: cs}
+		}
+		return err
+	}
+
+	if err := s.loadPullRequestData(ctx, repo, pr); err != nil {
+		return errors.Wrap(err, "loading pull request data")
+	}
+	if err = cs.SetMetadata(pr); err != nil {
+		return errors.Wrap(err, "setting changeset metadata")
+	}
+
+	return nil
+}
+
+func (s BitbucketCloudSource) loadPullRequestData(ctx context.Context, repo *bitbucketcloud.Repo, pr *bitbucketcloud.PullRequest) error {
+	statuses, err := s.client.PullRequestStatuses(ctx, repo.FullName, pr.ID)
+	if err != nil {
+		return errors.Wrap(err, "loading pr build statuses")
+	}
+	pr.Statuses = statuses
+	return nil
+}
+
+func (s BitbucketCloudSource) UpdateChangeset(ctx context.Context, c *Changeset) error {
+	pr, ok := c.Changeset.Metadata.(*bitbucketcloud.PullRequest)
+	if !ok {
+		return errors.New("Changeset is not a Bitbucket Cloud pull request")
+	}
+	repo := c.Repo.Metadata.(*bitbucketcloud.Repo)
+
+	updated, err := s.client.UpdatePullRequest(ctx, repo.FullName, pr.ID, &bitbucketcloud.PullRequestInput{
+		Title:             c.Title,
+		Description:       c.Body,
+		SourceBranch:      pr.Source.Branch.Name,
+		DestinationBranch: git.AbbreviateRef(c.BaseRef),
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := s.loadPullRequestData(ctx, repo, updated); err != nil {
+		return errors.Wrap(err, "loading pull request data")
+	}
+	return c.Changeset.SetMetadata(updated)
+}
+
+// ReopenChangeset opens a new pull request for the branches of the declined
+// *Changeset, since Bitbucket Cloud cannot reopen declined pull requests. The
+// Metadata column, and with it the external ID, of the *batches.Changeset is
+// updated to the new pull request.
+func (s BitbucketCloudSource) ReopenChangeset(ctx context.Context, c *Changeset) error {
+	pr, ok := c.Changeset.Metadata.(*bitbucketcloud.PullRequest)
+	if !ok {
+		return errors.New("Changeset is not a Bitbucket Cloud pull request")
+	}
+
+	if c.Title == "" {
+		c.Title = pr.Title
+		c.Body = pr.Description
+	}
+	if c.HeadRef == "" {
+		c.HeadRef = pr.Source.Branch.Name
+	}
+	if c.BaseRef == "" {
+		c.BaseRef = pr.Destination.Branch.Name
+	}
+
+	_, err := s.CreateChangeset(ctx, c)
+	return err
+}
+
+// CreateComment posts a comment on the Changeset.
+func (s BitbucketCloudSource) CreateComment(ctx context.Context, c *Changeset, text string) error {
+	pr, ok := c.Changeset.Metadata.(*bitbucketcloud.PullRequest)
+	if !ok {
+		return errors.New("Changeset is not a Bitbucket Cloud pull request")
+	}
+	repo := c.Repo.Metadata.(*bitbucketcloud.Repo)
+
+	return s.client.CreatePullRequestComment(ctx, repo.FullName, pr.ID, text)
+}
+
+// MergeChangeset merges a Changeset on the code host, if in a mergeable state.
+// If squash is false, a merge commit is created.
+func (s BitbucketCloudSource) MergeChangeset(ctx context.Context, c *Changeset, squash bool) error {
+	pr, ok := c.Changeset.Metadata.(*bitbucketcloud.PullRequest)
+	if !ok {
+		return errors.New("Changeset is not a Bitbucket Cloud pull request")
+	}
+	repo := c.Repo.Metadata.(*bitbucketcloud.Repo)
+
+	strategy := bitbucketcloud.MergeStrategyMergeCommit
+	if squash {
+		strategy = bitbucketcloud.MergeStrategySquash
+	}
+
+	merged, err := s.client.MergePullRequest(ctx, repo.FullName, pr.ID, strategy)
+	if err != nil {
+		if bitbucketcloud.IsNotMergeable(err) {
+			return &ChangesetNotMergeableError{ErrorMsg: err.Error()}
+		}
+		return err
+	}
+
+	return c.Changeset.SetMetadata(merged)
+}
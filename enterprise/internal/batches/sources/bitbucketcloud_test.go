@@ -0,0 +1,232 @@
+package sources
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cockroachdb/errors"
+
+	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/auth"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketcloud"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// newBitbucketCloudTestSource returns a BitbucketCloudSource talking to a
+// fake API served by handler.
+func newBitbucketCloudTestSource(t *testing.T, handler http.HandlerFunc) *BitbucketCloudSource {
+	t.Helper()
+
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	src, err := NewBitbucketCloudSource(&types.ExternalService{
+		Kind: extsvc.KindBitbucketCloud,
+		Config: marshalJSON(t, &schema.BitbucketCloudConnection{
+			Url:         "https://bitbucket.org",
+			ApiURL:      srv.URL,
+			Username:    "user",
+			AppPassword: "secret",
+		}),
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return src
+}
+
+func writeJSON(t *testing.T, w http.ResponseWriter, v interface{}) {
+	t.Helper()
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBitbucketCloudSource_CreateChangeset(t *testing.T) {
+	repo := &types.Repo{Metadata: &bitbucketcloud.Repo{FullName: "sourcegraph/src-cli"}}
+	const prsPath = "/2.0/repositories/sourcegraph/src-cli/pullrequests"
+
+	for name, tc := range map[string]struct {
+		existing   []*bitbucketcloud.PullRequest
+		wantExists bool
+		wantID     string
+	}{
+		"new":      {wantExists: false, wantID: "2"},
+		"existing": {existing: []*bitbucketcloud.PullRequest{{ID: 1, State: "OPEN"}}, wantExists: true, wantID: "1"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			var created bool
+			src := newBitbucketCloudTestSource(t, func(w http.ResponseWriter, r *http.Request) {
+				if user, pass, _ := r.BasicAuth(); user != "user" || pass != "secret" {
+					t.Errorf("unexpected credentials %q:%q", user, pass)
+				}
+
+				switch {
+				case r.Method == "GET" && r.URL.Path == prsPath:
+					want := `state = "OPEN" AND source.branch.name = "batch" AND destination.branch.name = "main"`
+					if q := r.URL.Query().Get("q"); q != want {
+						t.Errorf("unexpected query %q", q)
+					}
+					writeJSON(t, w, map[string]interface{}{"values": tc.existing})
+				case r.Method == "POST" && r.URL.Path == prsPath:
+					created = true
+					body, _ := io.ReadAll(r.Body)
+					want := `{"title":"Title","description":"Body","source":{"branch":{"name":"batch"}},"destination":{"branch":{"name":"main"}}}`
+					if string(body) != want {
+						t.Errorf("unexpected body %s", body)
+					}
+					writeJSON(t, w, &bitbucketcloud.PullRequest{ID: 2, Title: "Title", State: "OPEN"})
+				case r.Method == "GET" && r.URL.Path == prsPath+"/"+tc.wantID+"/statuses":
+					writeJSON(t, w, map[string]interface{}{"values": []*bitbucketcloud.CommitStatus{{Key: "ci", State: "SUCCESSFUL"}}})
+				default:
+					t.Errorf("unexpected request %s %s", r.Method, r.URL)
+					w.WriteHeader(http.StatusNotFound)
+				}
+			})
+
+			cs := &Changeset{
+				Title:     "Title",
+				Body:      "Body",
+				HeadRef:   "refs/heads/batch",
+				BaseRef:   "refs/heads/main",
+				Repo:      repo,
+				Changeset: &btypes.Changeset{},
+			}
+			exists, err := src.CreateChangeset(context.Background(), cs)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if exists != tc.wantExists {
+				t.Errorf("exists = %v, want %v", exists, tc.wantExists)
+			}
+			if created == tc.wantExists {
+				t.Errorf("created = %v, want %v", created, !tc.wantExists)
+			}
+			if cs.ExternalID != tc.wantID {
+				t.Errorf("ExternalID = %q, want %q", cs.ExternalID, tc.wantID)
+			}
+			if cs.ExternalServiceType != extsvc.TypeBitbucketCloud {
+				t.Errorf("ExternalServiceType = %q", cs.ExternalServiceType)
+			}
+			if pr := cs.Changeset.Metadata.(*bitbucketcloud.PullRequest); len(pr.Statuses) != 1 {
+				t.Errorf("expected statuses to be loaded, got %v", pr.Statuses)
+			}
+		})
+	}
+}
+
+func TestBitbucketCloudSource_LoadChangeset_NotFound(t *testing.T) {
+	src := newBitbucketCloudTestSource(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	cs := &Changeset{
+		Repo:      &types.Repo{Metadata: &bitbucketcloud.Repo{FullName: "sourcegraph/src-cli"}},
+		Changeset: &btypes.Changeset{ExternalID: "42"},
+	}
+	err := src.LoadChangeset(context.Background(), cs)
+	if !errors.HasType(err, ChangesetNotFoundError{}) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestBitbucketCloudSource_MergeChangeset(t *testing.T) {
+	for name, tc := range map[string]struct {
+		squash       bool
+		status       int
+		wantStrategy string
+		wantErr      bool
+	}{
+		"merge commit":  {squash: false, status: 200, wantStrategy: "merge_commit"},
+		"squash":        {squash: true, status: 200, wantStrategy: "squash"},
+		"not mergeable": {squash: true, status: 400, wantStrategy: "squash", wantErr: true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			src := newBitbucketCloudTestSource(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/2.0/repositories/sourcegraph/src-cli/pullrequests/1/merge" {
+					t.Errorf("unexpected request %s %s", r.Method, r.URL)
+				}
+				var body struct {
+					MergeStrategy string `json:"merge_strategy"`
+				}
+				if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+					t.Error(err)
+				}
+				if body.MergeStrategy != tc.wantStrategy {
+					t.Errorf("merge strategy = %q, want %q", body.MergeStrategy, tc.wantStrategy)
+				}
+				w.WriteHeader(tc.status)
+				writeJSON(t, w, &bitbucketcloud.PullRequest{ID: 1, State: "MERGED"})
+			})
+
+			cs := &Changeset{
+				Repo:      &types.Repo{Metadata: &bitbucketcloud.Repo{FullName: "sourcegraph/src-cli"}},
+				Changeset: &btypes.Changeset{Metadata: &bitbucketcloud.PullRequest{ID: 1, State: "OPEN"}},
+			}
+			err := src.MergeChangeset(context.Background(), cs, tc.squash)
+			if tc.wantErr {
+				if !errors.HasType(err, &ChangesetNotMergeableError{}) {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if pr := cs.Changeset.Metadata.(*bitbucketcloud.PullRequest); pr.State != "MERGED" {
+				t.Errorf("unexpected state %q", pr.State)
+			}
+		})
+	}
+}
+
+func TestBitbucketCloudSource_WithAuthenticator(t *testing.T) {
+	src := newBitbucketCloudTestSource(t, func(w http.ResponseWriter, r *http.Request) {})
+
+	t.Run("supported", func(t *testing.T) {
+		for name, tc := range map[string]auth.Authenticator{
+			"BasicAuth":        &auth.BasicAuth{Username: "a", Password: "b"},
+			"BasicAuthWithSSH": &auth.BasicAuthWithSSH{BasicAuth: auth.BasicAuth{Username: "a", Password: "b"}},
+		} {
+			t.Run(name, func(t *testing.T) {
+				have, err := src.WithAuthenticator(tc)
+				if err != nil {
+					t.Fatalf("unexpected non-nil error: %v", err)
+				}
+				bs, ok := have.(*BitbucketCloudSource)
+				if !ok {
+					t.Fatal("cannot coerce Source into BitbucketCloudSource")
+				}
+				if bs.au != tc {
+					t.Errorf("incorrect authenticator: have=%v want=%v", bs.au, tc)
+				}
+				if bs.client.Username != "a" || bs.client.AppPassword != "b" {
+					t.Errorf("client credentials not updated")
+				}
+			})
+		}
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		for name, tc := range map[string]auth.Authenticator{
+			"nil":              nil,
+			"OAuthBearerToken": &auth.OAuthBearerToken{},
+		} {
+			t.Run(name, func(t *testing.T) {
+				have, err := src.WithAuthenticator(tc)
+				if !errors.HasType(err, UnsupportedAuthenticatorError{}) {
+					t.Errorf("unexpected error of type %T: %v", err, err)
+				}
+				if have != nil {
+					t.Errorf("expected nil Source: %v", have)
+				}
+			})
+		}
+	})
+}
@@ -67,6 +67,17 @@ var RequiredEventTypesForHistory = []btypes.ChangesetEventKind{
 	btypes.ChangesetEventKindBitbucketServerUnapproved,
 	btypes.ChangesetEventKindBitbucketServerDismissed,
 	btypes.ChangesetEventKindGitLabUnapproved,
+	btypes.ChangesetEventKindBitbucketCloudDeclined,
+	btypes.ChangesetEventKindBitbucketCloudMerged,
+	btypes.ChangesetEventKindBitbucketCloudApproved,
+	btypes.ChangesetEventKindBitbucketCloudChangesRequested,
+	btypes.ChangesetEventKindBitbucketCloudUnapproved,
+	btypes.ChangesetEventKindBitbucketCloudChangesRequestRemoved,
+	btypes.ChangesetEventKindAWSCodeCommitClosed,
+	btypes.ChangesetEventKindAWSCodeCommitMerged,
+	btypes.ChangesetEventKindAWSCodeCommitReopened,
+	btypes.ChangesetEventKindAWSCodeCommitApproved,
+	btypes.ChangesetEventKindAWSCodeCommitApprovalRevoked,
 }
 
 type changesetStatesAtTime struct {
@@ -121,7 +132,9 @@ func computeHistory(ch *btypes.Changeset, ce ChangesetEvents) (changesetHistory,
 		switch e.Kind {
 		case btypes.ChangesetEventKindGitHubClosed,
 			btypes.ChangesetEventKindBitbucketServerDeclined,
-			btypes.ChangesetEventKindGitLabClosed:
+			btypes.ChangesetEventKindGitLabClosed,
+			btypes.ChangesetEventKindBitbucketCloudDeclined,
+			btypes.ChangesetEventKindAWSCodeCommitClosed:
 			// Merged is a final state. We can ignore everything after.
 			if currentExtState != btypes.ChangesetExternalStateMerged {
 				currentExtState = btypes.ChangesetExternalStateClosed
@@ -130,7 +143,9 @@ func computeHistory(ch *btypes.Changeset, ce ChangesetEvents) (changesetHistory,
 
 		case btypes.ChangesetEventKindGitHubMerged,
 			btypes.ChangesetEventKindBitbucketServerMerged,
-			btypes.ChangesetEventKindGitLabMerged:
+			btypes.ChangesetEventKindGitLabMerged,
+			btypes.ChangesetEventKindBitbucketCloudMerged,
+			btypes.ChangesetEventKindAWSCodeCommitMerged:
 			currentExtState = btypes.ChangesetExternalStateMerged
 			pushStates(et)
 
@@ -161,7 +176,8 @@ func computeHistory(ch *btypes.Changeset, ce ChangesetEvents) (changesetHistory,
 
 		case btypes.ChangesetEventKindGitHubReopened,
 			btypes.ChangesetEventKindBitbucketServerReopened,
-			btypes.ChangesetEventKindGitLabReopened:
+			btypes.ChangesetEventKindGitLabReopened,
+			btypes.ChangesetEventKindAWSCodeCommitReopened:
 			// Merged is a final state. We can ignore everything after.
 			if currentExtState != btypes.ChangesetExternalStateMerged {
 				if isDraft {
@@ -175,7 +191,10 @@ func computeHistory(ch *btypes.Changeset, ce ChangesetEvents) (changesetHistory,
 		case btypes.ChangesetEventKindGitHubReviewed,
 			btypes.ChangesetEventKindBitbucketServerApproved,
 			btypes.ChangesetEventKindBitbucketServerReviewed,
-			btypes.ChangesetEventKindGitLabApproved:
+			btypes.ChangesetEventKindGitLabApproved,
+			btypes.ChangesetEventKindBitbucketCloudApproved,
+			btypes.ChangesetEventKindBitbucketCloudChangesRequested,
+			btypes.ChangesetEventKindAWSCodeCommitApproved:
 
 			s, err := e.ReviewState()
 			if err != nil {
@@ -217,7 +236,10 @@ func computeHistory(ch *btypes.Changeset, ce ChangesetEvents) (changesetHistory,
 
 		case btypes.ChangesetEventKindBitbucketServerUnapproved,
 			btypes.ChangesetEventKindBitbucketServerDismissed,
-			btypes.ChangesetEventKindGitLabUnapproved:
+			btypes.ChangesetEventKindGitLabUnapproved,
+			btypes.ChangesetEventKindBitbucketCloudUnapproved,
+			btypes.ChangesetEventKindBitbucketCloudChangesRequestRemoved,
+			btypes.ChangesetEventKindAWSCodeCommitApprovalRevoked:
 			author := e.ReviewAuthor()
 			// If the user has been deleted, skip their reviews, as they don't count towards the final state anymore.
 			if author == "" {
@@ -244,6 +266,21 @@ func computeHistory(ch *btypes.Changeset, ce ChangesetEvents) (changesetHistory,
 				}
 			}
 
+			// Bitbucket Cloud and AWS CodeCommit send separate events for
+			// withdrawing an approval and withdrawing a request for changes,
+			// so each must only remove the matching review.
+			switch e.Type() {
+			case btypes.ChangesetEventKindBitbucketCloudUnapproved,
+				btypes.ChangesetEventKindAWSCodeCommitApprovalRevoked:
+				if lastReviewByAuthor[author] != btypes.ChangesetReviewStateApproved {
+					continue
+				}
+			case btypes.ChangesetEventKindBitbucketCloudChangesRequestRemoved:
+				if lastReviewByAuthor[author] != btypes.ChangesetReviewStateChangesRequested {
+					continue
+				}
+			}
+
 			// Save current review state, then remove last approval and
 			// recompute overall review state
 			oldReviewState := currentReviewState
@@ -16,6 +16,8 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/awscodecommit"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketcloud"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketserver"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitlab"
@@ -105,6 +107,9 @@ func computeCheckState(c *btypes.Changeset, events ChangesetEvents) btypes.Chang
 
 	case *gitlab.MergeRequest:
 		return computeGitLabCheckState(c.UpdatedAt, m, events)
+
+	case *bitbucketcloud.PullRequest:
+		return computeBitbucketCloudBuildStatus(c.UpdatedAt, m, events)
 	}
 
 	return btypes.ChangesetCheckStateUnknown
@@ -184,9 +189,46 @@ func computeBitbucketBuildStatus(lastSynced time.Time, pr *bitbucketserver.PullR
 	return combineCheckStates(states)
 }
 
+func computeBitbucketCloudBuildStatus(lastSynced time.Time, pr *bitbucketcloud.PullRequest, events []*btypes.ChangesetEvent) btypes.ChangesetCheckState {
+	// Bitbucket Cloud returns abbreviated hashes in pull requests, but full
+	// hashes in commit statuses.
+	isHead := func(hash string) bool {
+		head := pr.Source.Commit.Hash
+		return head != "" && strings.HasPrefix(hash, head)
+	}
+
+	stateMap := make(map[string]btypes.ChangesetCheckState)
+
+	// States from last sync
+	for _, status := range pr.Statuses {
+		stateMap[status.Key] = parseBitbucketBuildState(status.State)
+	}
+
+	// Add any events we've received since our last sync
+	for _, e := range events {
+		switch m := e.Metadata.(type) {
+		case *bitbucketcloud.RepoCommitStatusEvent:
+			if !isHead(m.CommitStatus.CommitHash()) {
+				continue
+			}
+			if m.CommitStatus.UpdatedOn.Before(lastSynced) {
+				continue
+			}
+			stateMap[m.CommitStatus.Key] = parseBitbucketBuildState(m.CommitStatus.State)
+		}
+	}
+
+	states := make([]btypes.ChangesetCheckState, 0, len(stateMap))
+	for _, v := range stateMap {
+		states = append(states, v)
+	}
+
+	return combineCheckStates(states)
+}
+
 func parseBitbucketBuildState(s string) btypes.ChangesetCheckState {
 	switch s {
-	case "FAILED":
+	case "FAILED", "STOPPED":
 		return btypes.ChangesetCheckStateFailed
 	case "INPROGRESS":
 		return btypes.ChangesetCheckStatePending
@@ -450,6 +492,28 @@ func computeSingleChangesetExternalState(c *btypes.Changeset) (s btypes.Changese
 		default:
 			return "", errors.Errorf("unknown GitLab merge request state: %s", m.State)
 		}
+	case *bitbucketcloud.PullRequest:
+		switch m.State {
+		case bitbucketcloud.PullRequestStateOpen:
+			s = btypes.ChangesetExternalStateOpen
+		case bitbucketcloud.PullRequestStateMerged:
+			s = btypes.ChangesetExternalStateMerged
+		case bitbucketcloud.PullRequestStateDeclined, bitbucketcloud.PullRequestStateSuperseded:
+			s = btypes.ChangesetExternalStateClosed
+		default:
+			return "", errors.Errorf("unknown Bitbucket Cloud pull request state: %s", m.State)
+		}
+	case *awscodecommit.PullRequest:
+		switch {
+		case m.IsMerged:
+			s = btypes.ChangesetExternalStateMerged
+		case m.Status == awscodecommit.PullRequestStatusClosed:
+			s = btypes.ChangesetExternalStateClosed
+		case m.Status == awscodecommit.PullRequestStatusOpen:
+			s = btypes.ChangesetExternalStateOpen
+		default:
+			return "", errors.Errorf("unknown AWS CodeCommit pull request status: %s", m.Status)
+		}
 	default:
 		return "", errors.New("unknown changeset type")
 	}
@@ -507,6 +571,27 @@ func computeSingleChangesetReviewState(c *btypes.Changeset) (s btypes.ChangesetR
 		}
 		return btypes.ChangesetReviewStatePending, nil
 
+	case *bitbucketcloud.PullRequest:
+		for _, p := range m.Participants {
+			switch {
+			case p.State == bitbucketcloud.ParticipantStateChangesRequested:
+				states[btypes.ChangesetReviewStateChangesRequested] = true
+			case p.Approved || p.State == bitbucketcloud.ParticipantStateApproved:
+				states[btypes.ChangesetReviewStateApproved] = true
+			case p.Role == "REVIEWER":
+				states[btypes.ChangesetReviewStatePending] = true
+			}
+		}
+
+	case *awscodecommit.PullRequest:
+		// AWS CodeCommit has no concept of requesting changes, so a pull
+		// request is either approved or pending.
+		for _, a := range m.Approvals {
+			if a.State == awscodecommit.ApprovalStateApprove {
+				states[btypes.ChangesetReviewStateApproved] = true
+			}
+		}
+
 	default:
 		return "", errors.New("unknown changeset type")
 	}
@@ -9,6 +9,8 @@ import (
 
 	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/awscodecommit"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketcloud"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketserver"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitlab"
@@ -276,6 +278,86 @@ func TestComputeBitbucketBuildStatus(t *testing.T) {
 	}
 }
 
+func TestComputeBitbucketCloudBuildStatus(t *testing.T) {
+	t.Parallel()
+
+	now := timeutil.Now()
+	lastSynced := now.Add(-1 * time.Minute)
+
+	statusEvent := func(hash, key, state string, updatedOn time.Time) *btypes.ChangesetEvent {
+		e := &bitbucketcloud.RepoCommitStatusEvent{}
+		e.CommitStatus.Key = key
+		e.CommitStatus.State = state
+		e.CommitStatus.UpdatedOn = updatedOn
+		e.CommitStatus.Commit.Hash = hash
+		return &btypes.ChangesetEvent{
+			Kind:     btypes.ChangesetEventKindBitbucketCloudCommitStatus,
+			Metadata: e,
+		}
+	}
+
+	pr := &bitbucketcloud.PullRequest{
+		Statuses: []*bitbucketcloud.CommitStatus{{Key: "ctx1", State: "INPROGRESS"}},
+	}
+	pr.Source.Commit.Hash = "abcdef012345"
+
+	tests := []struct {
+		name   string
+		events []*btypes.ChangesetEvent
+		want   btypes.ChangesetCheckState
+	}{
+		{
+			name: "synced statuses only",
+			want: btypes.ChangesetCheckStatePending,
+		},
+		{
+			name: "newer event for head",
+			events: []*btypes.ChangesetEvent{
+				statusEvent("abcdef0123456789", "ctx1", "SUCCESSFUL", now),
+			},
+			want: btypes.ChangesetCheckStatePassed,
+		},
+		{
+			name: "event older than last sync",
+			events: []*btypes.ChangesetEvent{
+				statusEvent("abcdef0123456789", "ctx1", "SUCCESSFUL", lastSynced.Add(-1*time.Minute)),
+			},
+			want: btypes.ChangesetCheckStatePending,
+		},
+		{
+			name: "event for other commit",
+			events: []*btypes.ChangesetEvent{
+				statusEvent("0123456789abcdef", "ctx1", "FAILED", now),
+			},
+			want: btypes.ChangesetCheckStatePending,
+		},
+		{
+			name: "stopped build",
+			events: []*btypes.ChangesetEvent{
+				statusEvent("abcdef0123456789", "ctx2", "STOPPED", now),
+			},
+			want: btypes.ChangesetCheckStatePending,
+		},
+		{
+			name: "success + stopped",
+			events: []*btypes.ChangesetEvent{
+				statusEvent("abcdef0123456789", "ctx1", "SUCCESSFUL", now),
+				statusEvent("abcdef0123456789", "ctx2", "STOPPED", now),
+			},
+			want: btypes.ChangesetCheckStateFailed,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			have := computeBitbucketCloudBuildStatus(lastSynced, pr, tc.events)
+			if diff := cmp.Diff(tc.want, have); diff != "" {
+				t.Fatalf(diff)
+			}
+		})
+	}
+}
+
 func TestComputeGitLabCheckState(t *testing.T) {
 	t.Parallel()
 
@@ -535,6 +617,38 @@ func TestComputeReviewState(t *testing.T) {
 			},
 			want: btypes.ChangesetReviewStateChangesRequested,
 		},
+		{
+			name: "bitbucketcloud - no events, changes requested",
+			changeset: bitbucketCloudChangeset(daysAgo(0), bitbucketcloud.PullRequestStateOpen,
+				bitbucketcloud.Participant{Role: "REVIEWER", Approved: true},
+				bitbucketcloud.Participant{Role: "REVIEWER", State: bitbucketcloud.ParticipantStateChangesRequested},
+			),
+			history: []changesetStatesAtTime{},
+			want:    btypes.ChangesetReviewStateChangesRequested,
+		},
+		{
+			name: "bitbucketcloud - no events, approved",
+			changeset: bitbucketCloudChangeset(daysAgo(0), bitbucketcloud.PullRequestStateOpen,
+				bitbucketcloud.Participant{Role: "PARTICIPANT"},
+				bitbucketcloud.Participant{Role: "REVIEWER", Approved: true},
+			),
+			history: []changesetStatesAtTime{},
+			want:    btypes.ChangesetReviewStateApproved,
+		},
+		{
+			name:      "awscodecommit - no events, no approvals",
+			changeset: awsCodeCommitChangeset(daysAgo(0), awscodecommit.PullRequestStatusOpen, false),
+			history:   []changesetStatesAtTime{},
+			want:      btypes.ChangesetReviewStatePending,
+		},
+		{
+			name: "awscodecommit - no events, approved",
+			changeset: awsCodeCommitChangeset(daysAgo(0), awscodecommit.PullRequestStatusOpen, false,
+				&awscodecommit.Approval{State: awscodecommit.ApprovalStateApprove},
+			),
+			history: []changesetStatesAtTime{},
+			want:    btypes.ChangesetReviewStateApproved,
+		},
 	}
 
 	for i, tc := range tests {
@@ -653,6 +767,32 @@ func TestComputeExternalState(t *testing.T) {
 			},
 			want: btypes.ChangesetExternalStateDeleted,
 		},
+		{
+			name:      "bitbucketcloud - no events, superseded",
+			changeset: bitbucketCloudChangeset(daysAgo(0), bitbucketcloud.PullRequestStateSuperseded),
+			history:   []changesetStatesAtTime{},
+			want:      btypes.ChangesetExternalStateClosed,
+		},
+		{
+			name:      "bitbucketcloud - changeset older than events",
+			changeset: bitbucketCloudChangeset(daysAgo(10), bitbucketcloud.PullRequestStateOpen),
+			history: []changesetStatesAtTime{
+				{t: daysAgo(0), externalState: btypes.ChangesetExternalStateMerged},
+			},
+			want: btypes.ChangesetExternalStateMerged,
+		},
+		{
+			name:      "awscodecommit - no events, merged",
+			changeset: awsCodeCommitChangeset(daysAgo(0), awscodecommit.PullRequestStatusClosed, true),
+			history:   []changesetStatesAtTime{},
+			want:      btypes.ChangesetExternalStateMerged,
+		},
+		{
+			name:      "awscodecommit - no events, closed",
+			changeset: awsCodeCommitChangeset(daysAgo(0), awscodecommit.PullRequestStatusClosed, false),
+			history:   []changesetStatesAtTime{},
+			want:      btypes.ChangesetExternalStateClosed,
+		},
 		{
 			name:      "gitlab - no events, opened",
 			changeset: gitLabChangeset(daysAgo(0), gitlab.MergeRequestStateOpened, nil),
@@ -846,6 +986,29 @@ func bitbucketChangeset(updatedAt time.Time, state, reviewStatus string) *btypes
 	}
 }
 
+func bitbucketCloudChangeset(updatedAt time.Time, state bitbucketcloud.PullRequestState, participants ...bitbucketcloud.Participant) *btypes.Changeset {
+	return &btypes.Changeset{
+		ExternalServiceType: extsvc.TypeBitbucketCloud,
+		UpdatedAt:           updatedAt,
+		Metadata: &bitbucketcloud.PullRequest{
+			State:        state,
+			Participants: participants,
+		},
+	}
+}
+
+func awsCodeCommitChangeset(updatedAt time.Time, status string, merged bool, approvals ...*awscodecommit.Approval) *btypes.Changeset {
+	return &btypes.Changeset{
+		ExternalServiceType: extsvc.TypeAWSCodeCommit,
+		UpdatedAt:           updatedAt,
+		Metadata: &awscodecommit.PullRequest{
+			Status:    status,
+			IsMerged:  merged,
+			Approvals: approvals,
+		},
+	}
+}
+
 func githubChangeset(updatedAt time.Time, state string) *btypes.Changeset {
 	return &btypes.Changeset{
 		ExternalServiceType: extsvc.TypeGitHub,
@@ -11,6 +11,8 @@ import (
 
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/awscodecommit"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketcloud"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketserver"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitlab"
@@ -379,6 +381,18 @@ func (c *Changeset) SetMetadata(meta interface{}) error {
 		c.ExternalServiceType = extsvc.TypeGitLab
 		c.ExternalBranch = git.EnsureRefPrefix(pr.SourceBranch)
 		c.ExternalUpdatedAt = pr.UpdatedAt.Time
+	case *bitbucketcloud.PullRequest:
+		c.Metadata = pr
+		c.ExternalID = strconv.FormatInt(pr.ID, 10)
+		c.ExternalServiceType = extsvc.TypeBitbucketCloud
+		c.ExternalBranch = git.EnsureRefPrefix(pr.Source.Branch.Name)
+		c.ExternalUpdatedAt = pr.UpdatedOn
+	case *awscodecommit.PullRequest:
+		c.Metadata = pr
+		c.ExternalID = pr.ID
+		c.ExternalServiceType = extsvc.TypeAWSCodeCommit
+		c.ExternalBranch = git.EnsureRefPrefix(pr.SourceReference)
+		c.ExternalUpdatedAt = pr.LastActivityDate
 	default:
 		return errors.New("unknown changeset type")
 	}
@@ -404,6 +418,10 @@ func (c *Changeset) Title() (string, error) {
 		return m.Title, nil
 	case *gitlab.MergeRequest:
 		return m.Title, nil
+	case *bitbucketcloud.PullRequest:
+		return m.Title, nil
+	case *awscodecommit.PullRequest:
+		return m.Title, nil
 	default:
 		return "", errors.New("unknown changeset type")
 	}
@@ -421,6 +439,10 @@ func (c *Changeset) AuthorName() (string, error) {
 		return m.Author.User.Name, nil
 	case *gitlab.MergeRequest:
 		return m.Author.Username, nil
+	case *bitbucketcloud.PullRequest:
+		return m.Author.Nickname, nil
+	case *awscodecommit.PullRequest:
+		return m.AuthorARN, nil
 	default:
 		return "", errors.New("unknown changeset type")
 	}
@@ -444,6 +466,10 @@ func (c *Changeset) AuthorEmail() (string, error) {
 		return m.Author.User.EmailAddress, nil
 	case *gitlab.MergeRequest:
 		return m.Author.Email, nil
+	case *bitbucketcloud.PullRequest, *awscodecommit.PullRequest:
+		// Neither Bitbucket Cloud nor AWS CodeCommit expose the email
+		// address of the author.
+		return "", nil
 	default:
 		return "", errors.New("unknown changeset type")
 	}
@@ -460,6 +486,10 @@ func (c *Changeset) ExternalCreatedAt() time.Time {
 		return unixMilliToTime(int64(m.CreatedDate))
 	case *gitlab.MergeRequest:
 		return m.CreatedAt.Time
+	case *bitbucketcloud.PullRequest:
+		return m.CreatedOn
+	case *awscodecommit.PullRequest:
+		return m.CreationDate
 	default:
 		return time.Time{}
 	}
@@ -474,6 +504,10 @@ func (c *Changeset) Body() (string, error) {
 		return m.Description, nil
 	case *gitlab.MergeRequest:
 		return m.Description, nil
+	case *bitbucketcloud.PullRequest:
+		return m.Description, nil
+	case *awscodecommit.PullRequest:
+		return m.Description, nil
 	default:
 		return "", errors.New("unknown changeset type")
 	}
@@ -511,6 +545,10 @@ func (c *Changeset) URL() (s string, err error) {
 		return selfLink.Href, nil
 	case *gitlab.MergeRequest:
 		return m.WebURL, nil
+	case *bitbucketcloud.PullRequest:
+		return m.Links.HTML.Href, nil
+	case *awscodecommit.PullRequest:
+		return m.URL(), nil
 	default:
 		return "", errors.New("unknown changeset type")
 	}
@@ -643,6 +681,33 @@ func (c *Changeset) Events() (events []*ChangesetEvent, err error) {
 				Metadata:    pipeline,
 			})
 		}
+
+	case *bitbucketcloud.PullRequest:
+		events = make([]*ChangesetEvent, 0, len(m.Statuses))
+		for _, status := range m.Statuses {
+			e := &bitbucketcloud.RepoCommitStatusEvent{CommitStatus: *status}
+			appendEvent(&ChangesetEvent{
+				ChangesetID: c.ID,
+				Key:         e.Key(),
+				Kind:        ChangesetEventKindBitbucketCloudCommitStatus,
+				Metadata:    e,
+			})
+		}
+
+	case *awscodecommit.PullRequest:
+		events = make([]*ChangesetEvent, 0, len(m.Events))
+		var kind ChangesetEventKind
+		for _, e := range m.Events {
+			if kind, err = ChangesetEventKindFor(e); err != nil {
+				return
+			}
+			appendEvent(&ChangesetEvent{
+				ChangesetID: c.ID,
+				Key:         e.Key(),
+				Kind:        kind,
+				Metadata:    e,
+			})
+		}
 	}
 	return events, nil
 }
@@ -658,6 +723,11 @@ func (c *Changeset) HeadRefOid() (string, error) {
 		return "", nil
 	case *gitlab.MergeRequest:
 		return m.DiffRefs.HeadSHA, nil
+	case *bitbucketcloud.PullRequest:
+		// Bitbucket Cloud only returns abbreviated commit hashes.
+		return "", nil
+	case *awscodecommit.PullRequest:
+		return m.SourceCommit, nil
 	default:
 		return "", errors.New("unknown changeset type")
 	}
@@ -673,6 +743,10 @@ func (c *Changeset) HeadRef() (string, error) {
 		return m.FromRef.ID, nil
 	case *gitlab.MergeRequest:
 		return "refs/heads/" + m.SourceBranch, nil
+	case *bitbucketcloud.PullRequest:
+		return "refs/heads/" + m.Source.Branch.Name, nil
+	case *awscodecommit.PullRequest:
+		return git.EnsureRefPrefix(m.SourceReference), nil
 	default:
 		return "", errors.New("unknown changeset type")
 	}
@@ -689,6 +763,10 @@ func (c *Changeset) BaseRefOid() (string, error) {
 		return "", nil
 	case *gitlab.MergeRequest:
 		return m.DiffRefs.BaseSHA, nil
+	case *bitbucketcloud.PullRequest:
+		return "", nil
+	case *awscodecommit.PullRequest:
+		return m.DestinationCommit, nil
 	default:
 		return "", errors.New("unknown changeset type")
 	}
@@ -704,6 +782,10 @@ func (c *Changeset) BaseRef() (string, error) {
 		return m.ToRef.ID, nil
 	case *gitlab.MergeRequest:
 		return "refs/heads/" + m.TargetBranch, nil
+	case *bitbucketcloud.PullRequest:
+		return "refs/heads/" + m.Destination.Branch.Name, nil
+	case *awscodecommit.PullRequest:
+		return git.EnsureRefPrefix(m.DestinationReference), nil
 	default:
 		return "", errors.New("unknown changeset type")
 	}
@@ -981,6 +1063,44 @@ func ChangesetEventKindFor(e interface{}) (ChangesetEventKind, error) {
 		return ChangesetEventKindGitLabReopened, nil
 	case *gitlab.MergeRequestMergedEvent:
 		return ChangesetEventKindGitLabMerged, nil
+
+	case *bitbucketcloud.PullRequestApprovalEvent:
+		if e.EventKey == bitbucketcloud.EventKeyPullRequestUnapproved {
+			return ChangesetEventKindBitbucketCloudUnapproved, nil
+		}
+		return ChangesetEventKindBitbucketCloudApproved, nil
+	case *bitbucketcloud.PullRequestChangesRequestEvent:
+		if e.EventKey == bitbucketcloud.EventKeyPullRequestChangesRequestRemoved {
+			return ChangesetEventKindBitbucketCloudChangesRequestRemoved, nil
+		}
+		return ChangesetEventKindBitbucketCloudChangesRequested, nil
+	case *bitbucketcloud.PullRequestEvent:
+		switch e.EventKey {
+		case bitbucketcloud.EventKeyPullRequestFulfilled:
+			return ChangesetEventKindBitbucketCloudMerged, nil
+		case bitbucketcloud.EventKeyPullRequestRejected:
+			return ChangesetEventKindBitbucketCloudDeclined, nil
+		}
+	case *bitbucketcloud.RepoCommitStatusEvent:
+		return ChangesetEventKindBitbucketCloudCommitStatus, nil
+
+	case *awscodecommit.PullRequestEvent:
+		switch e.Type {
+		case awscodecommit.PullRequestEventStatusChanged:
+			if e.Status == awscodecommit.PullRequestStatusOpen {
+				return ChangesetEventKindAWSCodeCommitReopened, nil
+			}
+			return ChangesetEventKindAWSCodeCommitClosed, nil
+		case awscodecommit.PullRequestEventMergeStateChanged:
+			if e.IsMerged {
+				return ChangesetEventKindAWSCodeCommitMerged, nil
+			}
+		case awscodecommit.PullRequestEventApprovalStateChanged:
+			if e.ApprovalState == awscodecommit.ApprovalStateRevoke {
+				return ChangesetEventKindAWSCodeCommitApprovalRevoked, nil
+			}
+			return ChangesetEventKindAWSCodeCommitApproved, nil
+		}
 	}
 
 	return ChangesetEventKindInvalid, errors.Errorf("unknown changeset event kind for %T", e)
@@ -1042,6 +1162,32 @@ func NewChangesetEventMetadata(k ChangesetEventKind) (interface{}, error) {
 		case ChangesetEventKindCheckRun:
 			return new(github.CheckRun), nil
 		}
+	case strings.HasPrefix(string(k), "bitbucketcloud"):
+		switch k {
+		case ChangesetEventKindBitbucketCloudApproved:
+			return &bitbucketcloud.PullRequestApprovalEvent{PullRequestEvent: bitbucketcloud.PullRequestEvent{EventKey: bitbucketcloud.EventKeyPullRequestApproved}}, nil
+		case ChangesetEventKindBitbucketCloudUnapproved:
+			return &bitbucketcloud.PullRequestApprovalEvent{PullRequestEvent: bitbucketcloud.PullRequestEvent{EventKey: bitbucketcloud.EventKeyPullRequestUnapproved}}, nil
+		case ChangesetEventKindBitbucketCloudChangesRequested:
+			return &bitbucketcloud.PullRequestChangesRequestEvent{PullRequestEvent: bitbucketcloud.PullRequestEvent{EventKey: bitbucketcloud.EventKeyPullRequestChangesRequestCreated}}, nil
+		case ChangesetEventKindBitbucketCloudChangesRequestRemoved:
+			return &bitbucketcloud.PullRequestChangesRequestEvent{PullRequestEvent: bitbucketcloud.PullRequestEvent{EventKey: bitbucketcloud.EventKeyPullRequestChangesRequestRemoved}}, nil
+		case ChangesetEventKindBitbucketCloudMerged:
+			return &bitbucketcloud.PullRequestEvent{EventKey: bitbucketcloud.EventKeyPullRequestFulfilled}, nil
+		case ChangesetEventKindBitbucketCloudDeclined:
+			return &bitbucketcloud.PullRequestEvent{EventKey: bitbucketcloud.EventKeyPullRequestRejected}, nil
+		case ChangesetEventKindBitbucketCloudCommitStatus:
+			return new(bitbucketcloud.RepoCommitStatusEvent), nil
+		}
+	case strings.HasPrefix(string(k), "awscodecommit"):
+		switch k {
+		case ChangesetEventKindAWSCodeCommitClosed,
+			ChangesetEventKindAWSCodeCommitReopened,
+			ChangesetEventKindAWSCodeCommitMerged,
+			ChangesetEventKindAWSCodeCommitApproved,
+			ChangesetEventKindAWSCodeCommitApprovalRevoked:
+			return new(awscodecommit.PullRequestEvent), nil
+		}
 	case strings.HasPrefix(string(k), "gitlab"):
 		switch k {
 		case ChangesetEventKindGitLabApproved:
@@ -9,6 +9,8 @@ import (
 	"github.com/cockroachdb/errors"
 	"github.com/inconshreveable/log15"
 
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/awscodecommit"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketcloud"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketserver"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitlab"
@@ -78,6 +80,20 @@ const (
 	ChangesetEventKindGitLabMarkWorkInProgress   ChangesetEventKind = "gitlab:mark_wip"
 	ChangesetEventKindGitLabUnmarkWorkInProgress ChangesetEventKind = "gitlab:unmark_wip"
 
+	ChangesetEventKindBitbucketCloudApproved              ChangesetEventKind = "bitbucketcloud:approved"
+	ChangesetEventKindBitbucketCloudUnapproved            ChangesetEventKind = "bitbucketcloud:unapproved"
+	ChangesetEventKindBitbucketCloudChangesRequested      ChangesetEventKind = "bitbucketcloud:changes_requested"
+	ChangesetEventKindBitbucketCloudChangesRequestRemoved ChangesetEventKind = "bitbucketcloud:changes_request_removed"
+	ChangesetEventKindBitbucketCloudMerged                ChangesetEventKind = "bitbucketcloud:merged"
+	ChangesetEventKindBitbucketCloudDeclined              ChangesetEventKind = "bitbucketcloud:declined"
+	ChangesetEventKindBitbucketCloudCommitStatus          ChangesetEventKind = "bitbucketcloud:commit_status"
+
+	ChangesetEventKindAWSCodeCommitClosed          ChangesetEventKind = "awscodecommit:closed"
+	ChangesetEventKindAWSCodeCommitReopened        ChangesetEventKind = "awscodecommit:reopened"
+	ChangesetEventKindAWSCodeCommitMerged          ChangesetEventKind = "awscodecommit:merged"
+	ChangesetEventKindAWSCodeCommitApproved        ChangesetEventKind = "awscodecommit:approved"
+	ChangesetEventKindAWSCodeCommitApprovalRevoked ChangesetEventKind = "awscodecommit:approval_revoked"
+
 	ChangesetEventKindInvalid ChangesetEventKind = "invalid"
 )
 
@@ -121,6 +137,18 @@ func (e *ChangesetEvent) ReviewAuthor() string {
 	case *gitlab.ReviewUnapprovedEvent:
 		return meta.Author.Username
 
+	case *bitbucketcloud.PullRequestApprovalEvent:
+		return meta.Approval.User.Nickname
+
+	case *bitbucketcloud.PullRequestChangesRequestEvent:
+		return meta.ChangesRequest.User.Nickname
+
+	case *awscodecommit.PullRequestEvent:
+		if meta.Type == awscodecommit.PullRequestEventApprovalStateChanged {
+			return meta.ActorARN
+		}
+		return ""
+
 	default:
 		return ""
 	}
@@ -130,12 +158,15 @@ func (e *ChangesetEvent) ReviewAuthor() string {
 func (e *ChangesetEvent) ReviewState() (ChangesetReviewState, error) {
 	switch e.Kind {
 	case ChangesetEventKindBitbucketServerApproved,
-		ChangesetEventKindGitLabApproved:
+		ChangesetEventKindGitLabApproved,
+		ChangesetEventKindBitbucketCloudApproved,
+		ChangesetEventKindAWSCodeCommitApproved:
 		return ChangesetReviewStateApproved, nil
 
 	// BitbucketServer's "REVIEWED" activity is created when someone clicks
 	// the "Needs work" button in the UI, which is why we map it to "Changes Requested"
-	case ChangesetEventKindBitbucketServerReviewed:
+	case ChangesetEventKindBitbucketServerReviewed,
+		ChangesetEventKindBitbucketCloudChangesRequested:
 		return ChangesetReviewStateChangesRequested, nil
 
 	case ChangesetEventKindGitHubReviewed:
@@ -155,7 +186,10 @@ func (e *ChangesetEvent) ReviewState() (ChangesetReviewState, error) {
 	case ChangesetEventKindGitHubReviewDismissed,
 		ChangesetEventKindBitbucketServerUnapproved,
 		ChangesetEventKindBitbucketServerDismissed,
-		ChangesetEventKindGitLabUnapproved:
+		ChangesetEventKindGitLabUnapproved,
+		ChangesetEventKindBitbucketCloudUnapproved,
+		ChangesetEventKindBitbucketCloudChangesRequestRemoved,
+		ChangesetEventKindAWSCodeCommitApprovalRevoked:
 		return ChangesetReviewStateDismissed, nil
 
 	default:
@@ -240,6 +274,16 @@ func (e *ChangesetEvent) Timestamp() time.Time {
 		// fall back to the event record we created when we received the
 		// webhook.
 		t = e.CreatedAt
+	case *bitbucketcloud.PullRequestApprovalEvent:
+		t = ev.Approval.Date
+	case *bitbucketcloud.PullRequestChangesRequestEvent:
+		t = ev.ChangesRequest.Date
+	case *bitbucketcloud.PullRequestEvent:
+		t = ev.PullRequest.UpdatedOn
+	case *bitbucketcloud.RepoCommitStatusEvent:
+		t = ev.CommitStatus.UpdatedOn
+	case *awscodecommit.PullRequestEvent:
+		t = ev.Date
 	}
 
 	return t
@@ -722,6 +766,31 @@ func (e *ChangesetEvent) Update(o *ChangesetEvent) error {
 		// We always get the full event, so safe to replace it
 		*e = *o
 
+	case *bitbucketcloud.PullRequestApprovalEvent:
+		o := o.Metadata.(*bitbucketcloud.PullRequestApprovalEvent)
+		// We always get the full event, so safe to replace it
+		*e = *o
+
+	case *bitbucketcloud.PullRequestChangesRequestEvent:
+		o := o.Metadata.(*bitbucketcloud.PullRequestChangesRequestEvent)
+		// We always get the full event, so safe to replace it
+		*e = *o
+
+	case *bitbucketcloud.PullRequestEvent:
+		o := o.Metadata.(*bitbucketcloud.PullRequestEvent)
+		// We always get the full event, so safe to replace it
+		*e = *o
+
+	case *bitbucketcloud.RepoCommitStatusEvent:
+		o := o.Metadata.(*bitbucketcloud.RepoCommitStatusEvent)
+		// We always get the full event, so safe to replace it
+		*e = *o
+
+	case *awscodecommit.PullRequestEvent:
+		o := o.Metadata.(*awscodecommit.PullRequestEvent)
+		// We always get the full event, so safe to replace it
+		*e = *o
+
 	default:
 		return errors.Errorf("unknown changeset event metadata %T", e)
 	}
@@ -0,0 +1,140 @@
+package webhooks
+
+import (
+	"context"
+	"crypto/subtle"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/cockroachdb/errors"
+	"github.com/inconshreveable/log15"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/store"
+	"github.com/sourcegraph/sourcegraph/internal/conf/reposource"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/awscodecommit"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// AWSCodeCommitSecretHeader is the header the Amazon EventBridge API
+// destination must be configured to send the webhook secret in.
+const AWSCodeCommitSecretHeader = "X-Sourcegraph-Webhook-Secret"
+
+type AWSCodeCommitWebhook struct {
+	*Webhook
+}
+
+func NewAWSCodeCommitWebhook(store *store.Store) *AWSCodeCommitWebhook {
+	return &AWSCodeCommitWebhook{
+		Webhook: &Webhook{store, extsvc.TypeAWSCodeCommit},
+	}
+}
+
+// ServeHTTP implements the http.Handler interface.
+func (h *AWSCodeCommitWebhook) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	externalServiceID, err := strconv.ParseInt(r.FormValue(extsvc.IDParam), 10, 64)
+	if err != nil {
+		respond(w, http.StatusBadRequest, errors.Wrap(err, "invalid external service id"))
+		return
+	}
+
+	es, err := h.Store.ExternalServices().List(r.Context(), database.ExternalServicesListOptions{
+		IDs:   []int64{externalServiceID},
+		Kinds: []string{extsvc.KindAWSCodeCommit},
+	})
+	if err != nil {
+		respond(w, http.StatusInternalServerError, errors.Wrap(err, "getting external service"))
+		return
+	}
+	if len(es) != 1 {
+		respond(w, http.StatusUnauthorized, errExternalServiceNotFound)
+		return
+	}
+
+	c, err := es[0].Configuration()
+	if err != nil {
+		respond(w, http.StatusInternalServerError, errors.Wrap(err, "getting external service configuration"))
+		return
+	}
+	config, ok := c.(*schema.AWSCodeCommitConnection)
+	if !ok {
+		respond(w, http.StatusInternalServerError, errExternalServiceWrongKind)
+		return
+	}
+
+	// 🚨 SECURITY: Verify the shared secret against the webhooks configured in
+	// the external service. If none of them match, or the header is empty,
+	// then we return a 401 to the client.
+	if !validateAWSCodeCommitSecret(config, r.Header.Get(AWSCodeCommitSecretHeader)) {
+		respond(w, http.StatusUnauthorized, "shared secret is incorrect")
+		return
+	}
+
+	payload, err := io.ReadAll(r.Body)
+	if err != nil {
+		respond(w, http.StatusInternalServerError, errors.Wrap(err, "reading payload"))
+		return
+	}
+
+	e, err := awscodecommit.ParsePullRequestStateChangeEvent(payload)
+	if err != nil {
+		respond(w, http.StatusBadRequest, errors.Wrap(err, "parsing webhook"))
+		return
+	}
+
+	if err := h.handleEvent(r.Context(), config, e); err != nil {
+		respond(w, http.StatusInternalServerError, err)
+		return
+	}
+	respond(w, http.StatusNoContent, nil)
+}
+
+func (h *AWSCodeCommitWebhook) handleEvent(ctx context.Context, config *schema.AWSCodeCommitConnection, e *awscodecommit.PullRequestStateChangeEvent) error {
+	log15.Debug("AWS CodeCommit webhook received", "event", e.Detail.Event)
+
+	ev := e.PullRequestEvent()
+	if ev == nil {
+		// Other events, such as source branch updates, are picked up by the
+		// next sync.
+		return nil
+	}
+
+	id, err := strconv.ParseInt(e.Detail.PullRequestID, 10, 64)
+	if err != nil {
+		return errors.Wrap(err, "invalid pull request id")
+	}
+
+	// The event only contains the name of the repository, not its ID, so we
+	// look the repository up by the name we gave it.
+	name := reposource.AWSRepoName(config.RepositoryPathPattern, e.RepositoryName())
+	repo, err := h.Store.Repos().GetByName(ctx, name)
+	if err != nil {
+		if errcode.IsNotFound(err) {
+			log15.Debug("Webhook event could not be matched to repo", "name", name)
+			return nil
+		}
+		return errors.Wrap(err, "getting repo")
+	}
+
+	pr := PR{ID: id, RepoExternalID: repo.ExternalRepo.ID}
+	return errors.Wrapf(h.upsertChangesetEvent(ctx, e.ServiceID(), pr, ev), "upserting changeset event for pull request %d", id)
+}
+
+// validateAWSCodeCommitSecret validates that the given secret matches one of
+// the webhooks in the external service.
+func validateAWSCodeCommitSecret(config *schema.AWSCodeCommitConnection, secret string) bool {
+	// An empty secret never succeeds.
+	if secret == "" {
+		return false
+	}
+
+	for _, webhook := range config.Webhooks {
+		if subtle.ConstantTimeCompare([]byte(webhook.Secret), []byte(secret)) == 1 {
+			return true
+		}
+	}
+	return false
+}
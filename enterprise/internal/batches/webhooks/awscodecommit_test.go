@@ -0,0 +1,28 @@
+package webhooks
+
+import (
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestValidateAWSCodeCommitSecret(t *testing.T) {
+	config := &schema.AWSCodeCommitConnection{
+		Webhooks: []*schema.AWSCodeCommitWebhook{{Secret: "old"}, {Secret: "new"}},
+	}
+
+	for secret, want := range map[string]bool{
+		"":      false,
+		"old":   true,
+		"new":   true,
+		"other": false,
+	} {
+		if have := validateAWSCodeCommitSecret(config, secret); have != want {
+			t.Errorf("secret %q: have %v, want %v", secret, have, want)
+		}
+	}
+
+	if validateAWSCodeCommitSecret(&schema.AWSCodeCommitConnection{}, "") {
+		t.Error("empty secret must not match a service without webhooks")
+	}
+}
@@ -0,0 +1,217 @@
+package webhooks
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	gh "github.com/google/go-github/v28/github"
+	"github.com/hashicorp/go-multierror"
+	"github.com/inconshreveable/log15"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/store"
+	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketcloud"
+	"github.com/sourcegraph/sourcegraph/internal/repoupdater"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+type BitbucketCloudWebhook struct {
+	*Webhook
+}
+
+func NewBitbucketCloudWebhook(store *store.Store) *BitbucketCloudWebhook {
+	return &BitbucketCloudWebhook{
+		Webhook: &Webhook{store, extsvc.TypeBitbucketCloud},
+	}
+}
+
+// ServeHTTP implements the http.Handler interface.
+func (h *BitbucketCloudWebhook) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	e, extSvc, hErr := h.parseEvent(r)
+	if hErr != nil {
+		respond(w, hErr.code, hErr)
+		return
+	}
+
+	externalServiceID, err := extractExternalServiceID(extSvc)
+	if err != nil {
+		respond(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	if hErr := h.handleEvent(r.Context(), externalServiceID, e); hErr != nil {
+		respond(w, hErr.code, hErr)
+		return
+	}
+	respond(w, http.StatusNoContent, nil)
+}
+
+func (h *BitbucketCloudWebhook) parseEvent(r *http.Request) (interface{}, *types.ExternalService, *httpError) {
+	payload, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, nil, &httpError{http.StatusInternalServerError, err}
+	}
+
+	externalServiceID, err := strconv.ParseInt(r.FormValue(extsvc.IDParam), 10, 64)
+	if err != nil {
+		return nil, nil, &httpError{http.StatusBadRequest, errors.Wrap(err, "invalid external service id")}
+	}
+
+	es, err := h.Store.ExternalServices().List(r.Context(), database.ExternalServicesListOptions{
+		IDs:   []int64{externalServiceID},
+		Kinds: []string{extsvc.KindBitbucketCloud},
+	})
+	if err != nil {
+		return nil, nil, &httpError{http.StatusInternalServerError, err}
+	}
+	if len(es) != 1 {
+		return nil, nil, &httpError{http.StatusUnauthorized, errExternalServiceNotFound}
+	}
+	extSvc := es[0]
+
+	// 🚨 SECURITY: Verify the signature against the secrets of the webhooks
+	// configured in the external service. If none of them match, or the
+	// header is missing, then we return a 401 to the client.
+	if ok, err := validateBitbucketCloudSignature(extSvc, r.Header.Get("X-Hub-Signature"), payload); err != nil {
+		return nil, nil, &httpError{http.StatusInternalServerError, errors.Wrap(err, "validating the signature")}
+	} else if !ok {
+		return nil, nil, &httpError{http.StatusUnauthorized, errors.New("signature is incorrect")}
+	}
+
+	e, err := bitbucketcloud.ParseWebhookEvent(r.Header.Get(bitbucketcloud.EventKeyHeader), payload)
+	if err != nil {
+		return nil, nil, &httpError{http.StatusBadRequest, errors.Wrap(err, "parsing webhook")}
+	}
+	return e, extSvc, nil
+}
+
+func (h *BitbucketCloudWebhook) handleEvent(ctx context.Context, externalServiceID string, theirs interface{}) *httpError {
+	log15.Debug("Bitbucket Cloud webhook received", "type", fmt.Sprintf("%T", theirs))
+
+	var err error
+	switch e := theirs.(type) {
+	case *bitbucketcloud.PullRequestApprovalEvent:
+		err = h.upsertChangesetEvent(ctx, externalServiceID, bitbucketCloudToPR(&e.PullRequestEvent), e)
+	case *bitbucketcloud.PullRequestChangesRequestEvent:
+		err = h.upsertChangesetEvent(ctx, externalServiceID, bitbucketCloudToPR(&e.PullRequestEvent), e)
+	case *bitbucketcloud.PullRequestEvent:
+		switch e.EventKey {
+		case bitbucketcloud.EventKeyPullRequestFulfilled, bitbucketcloud.EventKeyPullRequestRejected:
+			err = h.upsertChangesetEvent(ctx, externalServiceID, bitbucketCloudToPR(e), e)
+		default:
+			// Created and updated events carry no information we can store
+			// as a changeset event, but the pull request may have changed in
+			// ways only a full sync picks up, such as a new head commit.
+			err = h.enqueueChangesetSync(ctx, externalServiceID, bitbucketCloudToPR(e))
+		}
+	case *bitbucketcloud.RepoCommitStatusEvent:
+		err = h.handleCommitStatusEvent(ctx, externalServiceID, e)
+	}
+
+	if err != nil {
+		return &httpError{http.StatusInternalServerError, err}
+	}
+	return nil
+}
+
+func (h *BitbucketCloudWebhook) enqueueChangesetSync(ctx context.Context, externalServiceID string, pr PR) error {
+	repo, err := h.getRepoForPR(ctx, h.Store, pr, externalServiceID)
+	if err != nil {
+		log15.Debug("Webhook event could not be matched to repo", "err", err)
+		return nil
+	}
+
+	cs, err := h.Store.GetChangeset(ctx, store.GetChangesetOpts{
+		RepoID:              repo.ID,
+		ExternalID:          strconv.FormatInt(pr.ID, 10),
+		ExternalServiceType: h.ServiceType,
+	})
+	if err != nil {
+		if err == store.ErrNoResults {
+			return nil
+		}
+		return errors.Wrap(err, "getting changeset")
+	}
+
+	return errors.Wrap(repoupdater.DefaultClient.EnqueueChangesetSync(ctx, []int64{cs.ID}), "enqueuing changeset sync")
+}
+
+// handleCommitStatusEvent attaches a commit status to all open changesets in
+// the repository whose head commit it refers to, since Bitbucket Cloud
+// doesn't tell us which pull requests are affected.
+func (h *BitbucketCloudWebhook) handleCommitStatusEvent(ctx context.Context, externalServiceID string, e *bitbucketcloud.RepoCommitStatusEvent) error {
+	hash := e.CommitStatus.CommitHash()
+	if hash == "" {
+		return nil
+	}
+
+	repo, err := h.getRepoForPR(ctx, h.Store, PR{RepoExternalID: e.Repository.UUID}, externalServiceID)
+	if err != nil {
+		log15.Debug("Webhook event could not be matched to repo", "err", err)
+		return nil
+	}
+
+	css, _, err := h.Store.ListChangesets(ctx, store.ListChangesetsOpts{
+		RepoID:         repo.ID,
+		ExternalStates: []btypes.ChangesetExternalState{btypes.ChangesetExternalStateOpen},
+	})
+	if err != nil {
+		return errors.Wrap(err, "listing changesets")
+	}
+
+	m := new(multierror.Error)
+	for _, cs := range css {
+		pr, ok := cs.Metadata.(*bitbucketcloud.PullRequest)
+		if !ok || pr.Source.Commit.Hash == "" || !strings.HasPrefix(hash, pr.Source.Commit.Hash) {
+			continue
+		}
+
+		if err := h.upsertChangesetEvent(ctx, externalServiceID, PR{ID: pr.ID, RepoExternalID: e.Repository.UUID}, e); err != nil {
+			m = multierror.Append(m, err)
+		}
+	}
+	return m.ErrorOrNil()
+}
+
+// bitbucketCloudToPR returns the PR referenced by the given event.
+func bitbucketCloudToPR(e *bitbucketcloud.PullRequestEvent) PR {
+	return PR{
+		ID:             e.PullRequest.ID,
+		RepoExternalID: e.Repository.UUID,
+	}
+}
+
+// validateBitbucketCloudSignature validates that the given signature of the
+// payload was created with the secret of one of the webhooks in the external
+// service.
+func validateBitbucketCloudSignature(extSvc *types.ExternalService, sig string, payload []byte) (bool, error) {
+	// An empty signature never succeeds.
+	if sig == "" {
+		return false, nil
+	}
+
+	c, err := extSvc.Configuration()
+	if err != nil {
+		return false, errors.Wrap(err, "getting external service configuration")
+	}
+
+	config, ok := c.(*schema.BitbucketCloudConnection)
+	if !ok {
+		return false, errExternalServiceWrongKind
+	}
+
+	for _, webhook := range config.Webhooks {
+		if gh.ValidateSignature(sig, payload, []byte(webhook.Secret)) == nil {
+			return true, nil
+		}
+	}
+	return false, nil
+}
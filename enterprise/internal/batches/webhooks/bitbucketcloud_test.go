@@ -0,0 +1,47 @@
+package webhooks
+
+import (
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func TestValidateBitbucketCloudSignature(t *testing.T) {
+	extSvc := &types.ExternalService{
+		Kind:   extsvc.KindBitbucketCloud,
+		Config: `{"url": "https://bitbucket.org", "username": "user", "appPassword": "secret", "webhooks": [{"secret": "old"}, {"secret": "new"}]}`,
+	}
+	payload := []byte(`{"pullrequest": {"id": 1}}`)
+
+	for name, tc := range map[string]struct {
+		sig  string
+		want bool
+	}{
+		"empty signature":  {sig: "", want: false},
+		"first secret":     {sig: sign(t, payload, []byte("old")), want: true},
+		"second secret":    {sig: sign(t, payload, []byte("new")), want: true},
+		"unknown secret":   {sig: sign(t, payload, []byte("other")), want: false},
+		"tampered payload": {sig: sign(t, []byte(`{}`), []byte("new")), want: false},
+	} {
+		t.Run(name, func(t *testing.T) {
+			have, err := validateBitbucketCloudSignature(extSvc, tc.sig, payload)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if have != tc.want {
+				t.Errorf("have %v, want %v", have, tc.want)
+			}
+		})
+	}
+
+	t.Run("wrong kind", func(t *testing.T) {
+		_, err := validateBitbucketCloudSignature(&types.ExternalService{
+			Kind:   extsvc.KindGitLab,
+			Config: `{"url": "https://gitlab.com", "token": "abc", "projectQuery": ["none"]}`,
+		}, sign(t, payload, []byte("new")), payload)
+		if err != errExternalServiceWrongKind {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+}
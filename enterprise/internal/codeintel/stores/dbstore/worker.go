@@ -30,6 +30,10 @@ var uploadWorkerStoreOptions = dbworkerstore.Options{
 	OrderByExpression: sqlf.Sprintf("u.uploaded_at, u.id"),
 	StalledMaxAge:     StalledUploadMaxAge,
 	MaxNumResets:      UploadMaxNumResets,
+
+	// Round-robin across repositories so that a repository with many uploads
+	// does not delay the processing of uploads for all other repositories.
+	FairnessKeyExpression: sqlf.Sprintf("u.repository_id"),
 }
 
 func WorkerutilUploadStore(s basestore.ShareableStore, observationContext *observation.Context) dbworkerstore.Store {
@@ -56,6 +60,10 @@ var indexWorkerStoreOptions = dbworkerstore.Options{
 	OrderByExpression: sqlf.Sprintf("u.queued_at, u.id"),
 	StalledMaxAge:     StalledIndexMaxAge,
 	MaxNumResets:      IndexMaxNumResets,
+
+	// Round-robin across repositories so that a repository with many index
+	// jobs does not delay the indexing of all other repositories.
+	FairnessKeyExpression: sqlf.Sprintf("u.repository_id"),
 }
 
 func WorkerutilIndexStore(s basestore.ShareableStore, observationContext *observation.Context) dbworkerstore.Store {
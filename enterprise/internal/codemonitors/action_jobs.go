@@ -3,6 +3,7 @@ package codemonitors
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"time"
 
 	"github.com/cockroachdb/errors"
@@ -14,8 +15,13 @@ import (
 )
 
 type ActionJob struct {
-	Id           int
-	Email        int64
+	Id int
+
+	// Exactly one of Email, SlackWebhook and Webhook is set.
+	Email        *int64
+	SlackWebhook *int64
+	Webhook      *int64
+
 	TriggerEvent int
 
 	// Fields demanded by any dbworker.
@@ -40,11 +46,17 @@ type ActionJobMetadata struct {
 
 	// The query with after: filter.
 	Query string
+
+	// The JSON encoded search results of the trigger event, if they were
+	// recorded.
+	Results json.RawMessage
 }
 
 var ActionJobsColumns = []*sqlf.Query{
 	sqlf.Sprintf("cm_action_jobs.id"),
 	sqlf.Sprintf("cm_action_jobs.email"),
+	sqlf.Sprintf("cm_action_jobs.slack_webhook"),
+	sqlf.Sprintf("cm_action_jobs.webhook"),
 	sqlf.Sprintf("cm_action_jobs.trigger_event"),
 	sqlf.Sprintf("cm_action_jobs.state"),
 	sqlf.Sprintf("cm_action_jobs.failure_message"),
@@ -56,8 +68,8 @@ var ActionJobsColumns = []*sqlf.Query{
 	sqlf.Sprintf("cm_action_jobs.log_contents"),
 }
 
-const readActionEmailEventsFmtStr = `
-SELECT id, email, trigger_event, state, failure_message, started_at, finished_at, process_after, num_resets, num_failures, log_contents
+const readActionEventsFmtStr = `
+SELECT %s
 FROM cm_action_jobs
 WHERE %s
 AND id > %s
@@ -65,19 +77,24 @@ ORDER BY id ASC
 LIMIT %s;
 `
 
-func (s *Store) ReadActionEmailEvents(ctx context.Context, emailID int64, triggerEventID *int, args *graphqlbackend.ListEventsArgs) (js []*ActionJob, err error) {
-	var where *sqlf.Query
-	if triggerEventID == nil {
-		where = sqlf.Sprintf("email = %s", emailID)
-	} else {
-		where = sqlf.Sprintf("email = %s AND trigger_event = %s", emailID, *triggerEventID)
-	}
-	var rows *sql.Rows
+func (s *Store) ReadActionEmailEvents(ctx context.Context, emailID int64, triggerEventID *int, args *graphqlbackend.ListEventsArgs) ([]*ActionJob, error) {
+	return s.readActionEvents(ctx, actionEventsWhere("email", emailID, triggerEventID), args)
+}
+
+func (s *Store) ReadActionSlackWebhookEvents(ctx context.Context, slackWebhookID int64, triggerEventID *int, args *graphqlbackend.ListEventsArgs) ([]*ActionJob, error) {
+	return s.readActionEvents(ctx, actionEventsWhere("slack_webhook", slackWebhookID, triggerEventID), args)
+}
+
+func (s *Store) ReadActionWebhookEvents(ctx context.Context, webhookID int64, triggerEventID *int, args *graphqlbackend.ListEventsArgs) ([]*ActionJob, error) {
+	return s.readActionEvents(ctx, actionEventsWhere("webhook", webhookID, triggerEventID), args)
+}
+
+func (s *Store) readActionEvents(ctx context.Context, where *sqlf.Query, args *graphqlbackend.ListEventsArgs) ([]*ActionJob, error) {
 	after, err := unmarshalAfter(args.After)
 	if err != nil {
 		return nil, err
 	}
-	rows, err = s.Query(ctx, sqlf.Sprintf(readActionEmailEventsFmtStr, where, after, args.First))
+	rows, err := s.Query(ctx, sqlf.Sprintf(readActionEventsFmtStr, sqlf.Join(ActionJobsColumns, ", "), where, after, args.First))
 	if err != nil {
 		return nil, err
 	}
@@ -85,26 +102,42 @@ func (s *Store) ReadActionEmailEvents(ctx context.Context, emailID int64, trigge
 	return scanActionJobs(rows, err)
 }
 
-const totalActionEmailEventsFmtStr = `
+const totalActionEventsFmtStr = `
 SELECT COUNT(*)
 FROM cm_action_jobs
 WHERE %s
 `
 
-func (s *Store) TotalActionEmailEvents(ctx context.Context, emailID int64, triggerEventID *int) (totalCount int32, err error) {
-	var where *sqlf.Query
-	if triggerEventID == nil {
-		where = sqlf.Sprintf("email = %s", emailID)
-	} else {
-		where = sqlf.Sprintf("email = %s AND trigger_event = %s", emailID, *triggerEventID)
-	}
-	err = s.QueryRow(ctx, sqlf.Sprintf(totalActionEmailEventsFmtStr, where)).Scan(&totalCount)
+func (s *Store) TotalActionEmailEvents(ctx context.Context, emailID int64, triggerEventID *int) (int32, error) {
+	return s.totalActionEvents(ctx, actionEventsWhere("email", emailID, triggerEventID))
+}
+
+func (s *Store) TotalActionSlackWebhookEvents(ctx context.Context, slackWebhookID int64, triggerEventID *int) (int32, error) {
+	return s.totalActionEvents(ctx, actionEventsWhere("slack_webhook", slackWebhookID, triggerEventID))
+}
+
+func (s *Store) TotalActionWebhookEvents(ctx context.Context, webhookID int64, triggerEventID *int) (int32, error) {
+	return s.totalActionEvents(ctx, actionEventsWhere("webhook", webhookID, triggerEventID))
+}
+
+func (s *Store) totalActionEvents(ctx context.Context, where *sqlf.Query) (totalCount int32, err error) {
+	err = s.QueryRow(ctx, sqlf.Sprintf(totalActionEventsFmtStr, where)).Scan(&totalCount)
 	if err != nil {
 		return -1, err
 	}
 	return totalCount, nil
 }
 
+// actionEventsWhere returns the condition selecting the action jobs of the
+// action with the given ID, optionally restricted to a single trigger event.
+// column is one of the action columns of cm_action_jobs.
+func actionEventsWhere(column string, actionID int64, triggerEventID *int) *sqlf.Query {
+	if triggerEventID == nil {
+		return sqlf.Sprintf(column+" = %s", actionID)
+	}
+	return sqlf.Sprintf(column+" = %s AND trigger_event = %s", actionID, *triggerEventID)
+}
+
 const enqueueActionEmailFmtStr = `
 WITH due AS (
 	SELECT e.id, e.monitor, e.enabled, e.priority, e.header, e.created_by, e.created_at, e.changed_by, e.changed_at
@@ -124,8 +157,60 @@ func (s *Store) EnqueueActionEmailsForQueryIDInt64(ctx context.Context, queryID
 	return s.Store.Exec(ctx, sqlf.Sprintf(enqueueActionEmailFmtStr, queryID, triggerEventID, triggerEventID))
 }
 
+const enqueueActionSlackWebhooksFmtStr = `
+WITH due AS (
+	SELECT w.id
+	FROM cm_slack_webhooks w INNER JOIN cm_queries q ON w.monitor = q.monitor
+	WHERE q.id = %s AND w.enabled = true
+),
+busy AS (
+    SELECT DISTINCT slack_webhook as id FROM cm_action_jobs
+    WHERE state = 'queued'
+    OR state = 'processing'
+)
+INSERT INTO cm_action_jobs (slack_webhook, trigger_event)
+SELECT id, %s::integer from due EXCEPT SELECT id, %s::integer from busy ORDER BY id
+`
+
+func (s *Store) EnqueueActionSlackWebhooksForQueryIDInt64(ctx context.Context, queryID int64, triggerEventID int) (err error) {
+	return s.Store.Exec(ctx, sqlf.Sprintf(enqueueActionSlackWebhooksFmtStr, queryID, triggerEventID, triggerEventID))
+}
+
+const enqueueActionWebhooksFmtStr = `
+WITH due AS (
+	SELECT w.id
+	FROM cm_webhooks w INNER JOIN cm_queries q ON w.monitor = q.monitor
+	WHERE q.id = %s AND w.enabled = true
+),
+busy AS (
+    SELECT DISTINCT webhook as id FROM cm_action_jobs
+    WHERE state = 'queued'
+    OR state = 'processing'
+)
+INSERT INTO cm_action_jobs (webhook, trigger_event)
+SELECT id, %s::integer from due EXCEPT SELECT id, %s::integer from busy ORDER BY id
+`
+
+func (s *Store) EnqueueActionWebhooksForQueryIDInt64(ctx context.Context, queryID int64, triggerEventID int) (err error) {
+	return s.Store.Exec(ctx, sqlf.Sprintf(enqueueActionWebhooksFmtStr, queryID, triggerEventID, triggerEventID))
+}
+
+// EnqueueActionJobsForQueryIDInt64 enqueues a job for every enabled action,
+// regardless of its kind, of the monitor the query belongs to.
+func (s *Store) EnqueueActionJobsForQueryIDInt64(ctx context.Context, queryID int64, triggerEventID int) (err error) {
+	err = s.EnqueueActionEmailsForQueryIDInt64(ctx, queryID, triggerEventID)
+	if err != nil {
+		return err
+	}
+	err = s.EnqueueActionSlackWebhooksForQueryIDInt64(ctx, queryID, triggerEventID)
+	if err != nil {
+		return err
+	}
+	return s.EnqueueActionWebhooksForQueryIDInt64(ctx, queryID, triggerEventID)
+}
+
 const getActionJobMetadataFmtStr = `
-select cm.description, ctj.query_string, cm.id as monitorID, ctj.num_results from
+select cm.description, ctj.query_string, cm.id as monitorID, ctj.num_results, ctj.search_results from
 cm_action_jobs caj
 inner join cm_trigger_jobs ctj on caj.trigger_event = ctj.id
 inner join cm_queries cq on cq.id = ctj.query
@@ -136,15 +221,17 @@ where caj.id = %s
 func (s *Store) GetActionJobMetadata(ctx context.Context, recordID int) (m *ActionJobMetadata, err error) {
 	row := s.Store.QueryRow(ctx, sqlf.Sprintf(getActionJobMetadataFmtStr, recordID))
 	m = &ActionJobMetadata{}
-	err = row.Scan(&m.Description, &m.Query, &m.MonitorID, &m.NumResults)
+	var results []byte
+	err = row.Scan(&m.Description, &m.Query, &m.MonitorID, &m.NumResults, &results)
 	if err != nil {
 		return nil, err
 	}
+	m.Results = results
 	return m, nil
 }
 
 const actionJobForIDFmtStr = `
-SELECT id, email, trigger_event, state, failure_message, started_at, finished_at, process_after, num_resets, num_failures, log_contents
+SELECT id, email, slack_webhook, webhook, trigger_event, state, failure_message, started_at, finished_at, process_after, num_resets, num_failures, log_contents
 FROM cm_action_jobs
 WHERE id = %s
 `
@@ -190,6 +277,8 @@ func scanActionJobs(rows *sql.Rows, err error) ([]*ActionJob, error) {
 		if err := rows.Scan(
 			&aj.Id,
 			&aj.Email,
+			&aj.SlackWebhook,
+			&aj.Webhook,
 			&aj.TriggerEvent,
 			&aj.State,
 			&aj.FailureMessage,
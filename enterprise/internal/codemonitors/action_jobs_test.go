@@ -35,9 +35,10 @@ func TestEnqueueActionEmailsForQueryIDInt64QueryByRecordID(t *testing.T) {
 		t.Fatal(err)
 	}
 
+	wantEmail := int64(1)
 	want := &ActionJob{
 		Id:             1,
-		Email:          1,
+		Email:          &wantEmail,
 		TriggerEvent:   1,
 		State:          "queued",
 		FailureMessage: nil,
@@ -0,0 +1,210 @@
+package codemonitors
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/keegancsmith/sqlf"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+)
+
+// WebhookTable is the table a kind of webhook action is stored in. All of
+// them have the same columns.
+type WebhookTable string
+
+const (
+	// SlackWebhooks are actions which post a message to a Slack incoming
+	// webhook whenever the trigger of their monitor fires.
+	SlackWebhooks WebhookTable = "cm_slack_webhooks"
+	// Webhooks are actions which post a JSON payload describing new search
+	// results to an arbitrary URL whenever the trigger of their monitor fires.
+	Webhooks WebhookTable = "cm_webhooks"
+)
+
+// MonitorWebhook is a webhook action, stored in one of the WebhookTables.
+type MonitorWebhook struct {
+	Id        int64
+	Monitor   int64
+	Enabled   bool
+	URL       string
+	CreatedBy int32
+	CreatedAt time.Time
+	ChangedBy int32
+	ChangedAt time.Time
+}
+
+const createActionWebhookFmtStr = `
+INSERT INTO %s
+(monitor, enabled, url, created_by, created_at, changed_by, changed_at)
+VALUES (%s,%s,%s,%s,%s,%s,%s)
+RETURNING %s;
+`
+
+func (s *Store) CreateActionWebhook(ctx context.Context, t WebhookTable, monitorID int64, enabled bool, url string) (*MonitorWebhook, error) {
+	now := s.Now()
+	a := actor.FromContext(ctx)
+	q := sqlf.Sprintf(
+		createActionWebhookFmtStr,
+		t.table(),
+		monitorID,
+		enabled,
+		url,
+		a.UID,
+		now,
+		a.UID,
+		now,
+		t.columns(),
+	)
+	return s.runWebhookQuery(ctx, q)
+}
+
+const updateActionWebhookFmtStr = `
+UPDATE %s
+SET enabled = %s,
+	url = %s,
+	changed_by = %s,
+	changed_at = %s
+WHERE id = %s
+AND monitor = %s
+RETURNING %s;
+`
+
+func (s *Store) UpdateActionWebhook(ctx context.Context, t WebhookTable, monitorID, actionID int64, enabled bool, url string) (*MonitorWebhook, error) {
+	now := s.Now()
+	a := actor.FromContext(ctx)
+	q := sqlf.Sprintf(
+		updateActionWebhookFmtStr,
+		t.table(),
+		enabled,
+		url,
+		a.UID,
+		now,
+		actionID,
+		monitorID,
+		t.columns(),
+	)
+	return s.runWebhookQuery(ctx, q)
+}
+
+const deleteActionWebhooksFmtStr = `DELETE FROM %s WHERE id in (%s) AND monitor = %s`
+
+func (s *Store) DeleteActionWebhooks(ctx context.Context, t WebhookTable, actionIDs []int64, monitorID int64) error {
+	if len(actionIDs) == 0 {
+		return nil
+	}
+	deleteIDs := make([]*sqlf.Query, 0, len(actionIDs))
+	for _, id := range actionIDs {
+		deleteIDs = append(deleteIDs, sqlf.Sprintf("%d", id))
+	}
+	return s.Exec(ctx, sqlf.Sprintf(deleteActionWebhooksFmtStr, t.table(), sqlf.Join(deleteIDs, ", "), monitorID))
+}
+
+const totalCountActionWebhooksFmtStr = `
+SELECT COUNT(*)
+FROM %s
+WHERE monitor = %s;
+`
+
+func (s *Store) TotalCountActionWebhooks(ctx context.Context, t WebhookTable, monitorID int64) (count int32, err error) {
+	err = s.QueryRow(ctx, sqlf.Sprintf(totalCountActionWebhooksFmtStr, t.table(), monitorID)).Scan(&count)
+	return count, err
+}
+
+const actionWebhookByIDFmtStr = `
+SELECT %s
+FROM %s
+WHERE id = %s
+`
+
+func (s *Store) ActionWebhookByIDInt64(ctx context.Context, t WebhookTable, id int64) (*MonitorWebhook, error) {
+	return s.runWebhookQuery(ctx, sqlf.Sprintf(actionWebhookByIDFmtStr, t.columns(), t.table(), id))
+}
+
+const listActionWebhooksFmtStr = `
+SELECT %s
+FROM %s
+WHERE monitor = %s
+AND id > %s
+ORDER BY id ASC
+LIMIT %s;
+`
+
+func (s *Store) ListActionWebhooks(ctx context.Context, t WebhookTable, monitorID int64, args *graphqlbackend.ListActionArgs) ([]*MonitorWebhook, error) {
+	after, err := unmarshalAfter(args.After)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.Query(ctx, sqlf.Sprintf(
+		listActionWebhooksFmtStr,
+		t.columns(),
+		t.table(),
+		monitorID,
+		after,
+		args.First,
+	))
+	return scanWebhooks(rows, err)
+}
+
+func (s *Store) runWebhookQuery(ctx context.Context, q *sqlf.Query) (*MonitorWebhook, error) {
+	rows, err := s.Query(ctx, q)
+	ws, err := scanWebhooks(rows, err)
+	if err != nil {
+		return nil, err
+	}
+	if len(ws) == 0 {
+		return nil, errors.Errorf("operation failed. Query should have returned 1 row")
+	}
+	return ws[0], nil
+}
+
+func (t WebhookTable) table() *sqlf.Query {
+	return sqlf.Sprintf(string(t))
+}
+
+var webhookColumns = []string{
+	"id",
+	"monitor",
+	"enabled",
+	"url",
+	"created_by",
+	"created_at",
+	"changed_by",
+	"changed_at",
+}
+
+func (t WebhookTable) columns() *sqlf.Query {
+	columns := make([]*sqlf.Query, 0, len(webhookColumns))
+	for _, c := range webhookColumns {
+		columns = append(columns, sqlf.Sprintf(string(t)+"."+c))
+	}
+	return sqlf.Join(columns, ", ")
+}
+
+func scanWebhooks(rows *sql.Rows, queryErr error) (ws []*MonitorWebhook, err error) {
+	if queryErr != nil {
+		return nil, queryErr
+	}
+	defer func() { err = basestore.CloseRows(rows, err) }()
+	for rows.Next() {
+		w := &MonitorWebhook{}
+		if err := rows.Scan(
+			&w.Id,
+			&w.Monitor,
+			&w.Enabled,
+			&w.URL,
+			&w.CreatedBy,
+			&w.CreatedAt,
+			&w.ChangedBy,
+			&w.ChangedAt,
+		); err != nil {
+			return nil, err
+		}
+		ws = append(ws, w)
+	}
+	return ws, nil
+}
@@ -0,0 +1,241 @@
+package background
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/cockroachdb/errors"
+
+	cm "github.com/sourcegraph/sourcegraph/enterprise/internal/codemonitors"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codemonitors/email"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+)
+
+const (
+	utmSourceSlack   = "code-monitoring-slack"
+	utmSourceWebhook = "code-monitoring-webhook"
+
+	// maxSlackResults is the number of results that are listed in a Slack
+	// message. The full list is available behind the search link.
+	maxSlackResults = 5
+)
+
+var (
+	webhookDoerOnce sync.Once
+	webhookDoer     httpcli.Doer
+)
+
+// getWebhookDoer returns the client webhook actions are posted with. Webhook
+// URLs are set by users, so it refuses to connect to addresses which are not
+// public, unless the site configuration allows them. This is checked when
+// connecting rather than when the URL is set, so that it also applies to
+// redirects and to hosts whose DNS records change.
+func getWebhookDoer() httpcli.Doer {
+	webhookDoerOnce.Do(func() {
+		var err error
+		webhookDoer, err = httpcli.NewExternalHTTPClientFactory().Doer(publicAddressesOnlyOpt)
+		if err != nil {
+			panic("codemonitors: failed to create the webhook client. This should not happen: " + err.Error())
+		}
+	})
+	return webhookDoer
+}
+
+// publicAddressesOnlyOpt is an httpcli.Opt which makes the client fail to
+// connect to addresses which the current cm.WebhookAllowlist does not allow.
+// It wraps the dialer of the client's transport, so that the proxy and TLS
+// settings of the transport still apply.
+func publicAddressesOnlyOpt(cli *http.Client) error {
+	if cli.Transport == nil {
+		cli.Transport = http.DefaultTransport
+	}
+	tr, ok := cli.Transport.(*http.Transport)
+	if !ok {
+		return errors.Errorf("webhook client transport is not an *http.Transport: %T", cli.Transport)
+	}
+	tr = tr.Clone()
+	dial := tr.DialContext
+	if dial == nil {
+		dial = (&net.Dialer{}).DialContext
+	}
+	tr.DialContext = dialAllowedAddresses(dial, cm.CurrentWebhookAllowlist)
+	cli.Transport = tr
+	return nil
+}
+
+type dialFunc func(ctx context.Context, network, address string) (net.Conn, error)
+
+var lookupIPAddr = net.DefaultResolver.LookupIPAddr
+
+// dialAllowedAddresses wraps dial so that it only connects to addresses that
+// allowlist allows. The host is resolved here and the allowed addresses are
+// dialed directly, so that the host can't resolve to another address between
+// the check and the connection.
+func dialAllowedAddresses(dial dialFunc, allowlist func() *cm.WebhookAllowlist) dialFunc {
+	return func(ctx context.Context, network, address string) (net.Conn, error) {
+		host, port, err := net.SplitHostPort(address)
+		if err != nil {
+			return nil, err
+		}
+		allow := allowlist()
+		if allow.AllowsHost(host) {
+			return dial(ctx, network, address)
+		}
+
+		addrs, err := lookupIPAddr(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		err = errors.Errorf("webhook address %s is not public", host)
+		for _, addr := range addrs {
+			if !allow.AllowsIP(addr.IP) {
+				continue
+			}
+			var conn net.Conn
+			if conn, err = dial(ctx, network, net.JoinHostPort(addr.String(), port)); err == nil {
+				return conn, nil
+			}
+		}
+		return nil, err
+	}
+}
+
+// webhookPayload is the JSON body posted to generic webhooks.
+type webhookPayload struct {
+	MonitorDescription string          `json:"monitorDescription"`
+	MonitorURL         string          `json:"monitorURL"`
+	Query              string          `json:"query"`
+	SearchURL          string          `json:"searchURL"`
+	NumResults         int             `json:"numResults"`
+	Results            json.RawMessage `json:"results"`
+}
+
+func newWebhookPayload(ctx context.Context, m *cm.ActionJobMetadata, utmSource string) (*webhookPayload, error) {
+	searchURL, err := email.GetSearchURL(ctx, m.Query, utmSource)
+	if err != nil {
+		return nil, err
+	}
+	monitorURL, err := email.GetCodeMonitorURL(ctx, m.MonitorID, utmSource)
+	if err != nil {
+		return nil, err
+	}
+	results := m.Results
+	if len(results) == 0 {
+		results = json.RawMessage("[]")
+	}
+	return &webhookPayload{
+		MonitorDescription: m.Description,
+		MonitorURL:         monitorURL,
+		Query:              m.Query,
+		SearchURL:          searchURL,
+		NumResults:         zeroOrVal(m.NumResults),
+		Results:            results,
+	}, nil
+}
+
+// sendWebhookNotification posts the search results of the action job
+// described by m as JSON to url.
+func sendWebhookNotification(ctx context.Context, doer httpcli.Doer, url string, m *cm.ActionJobMetadata) error {
+	payload, err := newWebhookPayload(ctx, m, utmSourceWebhook)
+	if err != nil {
+		return err
+	}
+	return postJSON(ctx, doer, url, payload)
+}
+
+// slackPayload is the body of a message posted to a Slack incoming webhook.
+// See https://api.slack.com/messaging/webhooks.
+type slackPayload struct {
+	Text string `json:"text"`
+}
+
+// sendSlackNotification posts a message summarizing the search results of the
+// action job described by m to the Slack incoming webhook url.
+func sendSlackNotification(ctx context.Context, doer httpcli.Doer, url string, m *cm.ActionJobMetadata) error {
+	payload, err := newWebhookPayload(ctx, m, utmSourceSlack)
+	if err != nil {
+		return err
+	}
+	return postJSON(ctx, doer, url, &slackPayload{Text: slackMessage(payload)})
+}
+
+func slackMessage(p *webhookPayload) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "*<%s|%s>*\n", p.MonitorURL, slackEscape(p.MonitorDescription))
+	if p.NumResults == 1 {
+		fmt.Fprintf(&b, "There was 1 new search result for your query.")
+	} else {
+		fmt.Fprintf(&b, "There were %d new search results for your query.", p.NumResults)
+	}
+	fmt.Fprintf(&b, " <%s|View results>\n", p.SearchURL)
+
+	var results []commitResult
+	// The results are only informational, a message without them is still
+	// useful.
+	_ = json.Unmarshal(p.Results, &results)
+	for i, r := range results {
+		if i == maxSlackResults {
+			fmt.Fprintf(&b, "…and %d more\n", len(results)-maxSlackResults)
+			break
+		}
+		if r.Typename != "CommitSearchResult" {
+			continue
+		}
+		subject := strings.SplitN(r.Commit.Message, "\n", 2)[0]
+		fmt.Fprintf(&b, "• `%s` `%s` %s\n", r.Commit.Repository.Name, r.Commit.AbbreviatedOID, slackEscape(subject))
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// commitResult is the subset of a CommitSearchResult returned by
+// gqlSearchQuery that is shown in Slack messages.
+type commitResult struct {
+	Typename string `json:"__typename"`
+	Commit   struct {
+		Repository struct {
+			Name string `json:"name"`
+		} `json:"repository"`
+		AbbreviatedOID string `json:"abbreviatedOID"`
+		Message        string `json:"message"`
+	} `json:"commit"`
+}
+
+// slackEscape escapes the characters Slack uses for control sequences. See
+// https://api.slack.com/reference/surfaces/formatting#escaping.
+func slackEscape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
+
+// postJSON posts v as JSON to url. A response with a non-2xx status code is
+// returned as an error, so that the action job is retried by the worker.
+func postJSON(ctx context.Context, doer httpcli.Doer, url string, v interface{}) error {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest("POST", url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := doer.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		b, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+		return errors.Errorf("webhook returned unexpected status code %d: %q", resp.StatusCode, string(b))
+	}
+	return nil
+}
@@ -0,0 +1,189 @@
+package background
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	cm "github.com/sourcegraph/sourcegraph/enterprise/internal/codemonitors"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codemonitors/email"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func mockExternalURL(t *testing.T) {
+	email.MockExternalURL = func() *url.URL {
+		u, _ := url.Parse("https://sourcegraph.example.com")
+		return u
+	}
+	t.Cleanup(func() { email.MockExternalURL = nil })
+}
+
+func testActionJobMetadata() *cm.ActionJobMetadata {
+	numResults := 2
+	return &cm.ActionJobMetadata{
+		Description: "TODO(security) <new>",
+		MonitorID:   1,
+		NumResults:  &numResults,
+		Query:       "TODO(security) type:diff",
+		Results: json.RawMessage(`[
+			{"__typename": "CommitSearchResult", "commit": {"repository": {"name": "github.com/foo/bar"}, "abbreviatedOID": "abc1234", "message": "Add auth\n\nLong description"}},
+			{"__typename": "CommitSearchResult", "commit": {"repository": {"name": "github.com/foo/baz"}, "abbreviatedOID": "def5678", "message": "Fix <script>"}}
+		]`),
+	}
+}
+
+func TestSendWebhookNotification(t *testing.T) {
+	mockExternalURL(t)
+
+	var got webhookPayload
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+			t.Errorf("unexpected content type %q", ct)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Error(err)
+		}
+	}))
+	defer ts.Close()
+
+	m := testActionJobMetadata()
+	if err := sendWebhookNotification(context.Background(), http.DefaultClient, ts.URL, m); err != nil {
+		t.Fatal(err)
+	}
+
+	if got.NumResults != 2 || got.Query != m.Query || got.MonitorDescription != m.Description {
+		t.Fatalf("unexpected payload: %+v", got)
+	}
+	if want := "https://sourcegraph.example.com/search?q=TODO%28security%29+type%3Adiff&utm_source=code-monitoring-webhook"; got.SearchURL != want {
+		t.Fatalf("unexpected search URL: want %q, have %q", want, got.SearchURL)
+	}
+	var results []commitResult
+	if err := json.Unmarshal(got.Results, &results); err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 || results[1].Commit.AbbreviatedOID != "def5678" {
+		t.Fatalf("unexpected results: %+v", results)
+	}
+}
+
+func TestSendSlackNotification(t *testing.T) {
+	mockExternalURL(t)
+
+	var got slackPayload
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Error(err)
+		}
+	}))
+	defer ts.Close()
+
+	if err := sendSlackNotification(context.Background(), http.DefaultClient, ts.URL, testActionJobMetadata()); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "*<https://sourcegraph.example.com/code-monitoring/Q29kZU1vbml0b3I6MQ==?utm_source=code-monitoring-slack|TODO(security) &lt;new&gt;>*\n" +
+		"There were 2 new search results for your query. <https://sourcegraph.example.com/search?q=TODO%28security%29+type%3Adiff&utm_source=code-monitoring-slack|View results>\n" +
+		"• `github.com/foo/bar` `abc1234` Add auth\n" +
+		"• `github.com/foo/baz` `def5678` Fix &lt;script&gt;"
+	if diff := cmp.Diff(want, got.Text); diff != "" {
+		t.Fatalf("unexpected message (-want +got):\n%s", diff)
+	}
+}
+
+func TestPostJSONErrorStatus(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = ioutil.ReadAll(r.Body)
+		http.Error(w, "invalid_token", http.StatusForbidden)
+	}))
+	defer ts.Close()
+
+	err := postJSON(context.Background(), http.DefaultClient, ts.URL, slackPayload{Text: "hi"})
+	if err == nil {
+		t.Fatal("expected an error for a non-2xx response")
+	}
+}
+
+func TestPublicAddressesOnlyOpt(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("unexpected request to loopback address")
+	}))
+	defer ts.Close()
+
+	var cli http.Client
+	if err := publicAddressesOnlyOpt(&cli); err != nil {
+		t.Fatal(err)
+	}
+	if err := postJSON(context.Background(), &cli, ts.URL, struct{}{}); err == nil {
+		t.Fatal("expected error for loopback address")
+	}
+}
+
+func TestPublicAddressesOnlyOptAllowlist(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ts.Close()
+
+	conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{
+		CodeMonitorsWebhookAllowedPrivateAddresses: []string{"127.0.0.0/8"},
+	}})
+	defer conf.Mock(nil)
+
+	var cli http.Client
+	if err := publicAddressesOnlyOpt(&cli); err != nil {
+		t.Fatal(err)
+	}
+	if err := postJSON(context.Background(), &cli, ts.URL, struct{}{}); err != nil {
+		t.Fatalf("unexpected error for allowed address: %s", err)
+	}
+}
+
+func TestPublicAddressesOnlyOptKeepsTransport(t *testing.T) {
+	proxy := func(*http.Request) (*url.URL, error) { return url.Parse("http://proxy.example.com:3128") }
+	cli := http.Client{Transport: &http.Transport{
+		Proxy:           proxy,
+		TLSClientConfig: &tls.Config{ServerName: "example.com"},
+	}}
+	if err := publicAddressesOnlyOpt(&cli); err != nil {
+		t.Fatal(err)
+	}
+
+	tr := cli.Transport.(*http.Transport)
+	if tr.Proxy == nil {
+		t.Error("expected the proxy of the transport to be kept")
+	}
+	if tr.TLSClientConfig == nil || tr.TLSClientConfig.ServerName != "example.com" {
+		t.Error("expected the TLS config of the transport to be kept")
+	}
+}
+
+func TestDialAllowedAddresses(t *testing.T) {
+	lookupIPAddr = func(_ context.Context, host string) ([]net.IPAddr, error) {
+		return []net.IPAddr{{IP: net.ParseIP("10.0.0.1")}, {IP: net.ParseIP("93.184.216.34")}}, nil
+	}
+	defer func() { lookupIPAddr = net.DefaultResolver.LookupIPAddr }()
+
+	var dialed []string
+	dial := dialAllowedAddresses(func(_ context.Context, _, address string) (net.Conn, error) {
+		dialed = append(dialed, address)
+		return nil, nil
+	}, func() *cm.WebhookAllowlist {
+		return cm.NewWebhookAllowlist([]string{"hooks.internal"})
+	})
+
+	for _, address := range []string{"example.com:443", "hooks.internal:80"} {
+		if _, err := dial(context.Background(), "tcp", address); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if diff := cmp.Diff([]string{"93.184.216.34:443", "hooks.internal:80"}, dialed); diff != "" {
+		t.Errorf("unexpected dialed addresses (-want +got):\n%s", diff)
+	}
+}
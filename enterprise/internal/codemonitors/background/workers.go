@@ -165,9 +165,13 @@ func (r *queryRunner) Handle(ctx context.Context, record workerutil.Record) (err
 		numResults = len(results.Data.Search.Results.Results)
 	}
 	if numResults > 0 {
-		err := s.EnqueueActionEmailsForQueryIDInt64(ctx, q.Id, record.RecordID())
+		err := s.LogSearchResults(ctx, results.Data.Search.Results.Results, record.RecordID())
 		if err != nil {
-			return errors.Errorf("store.EnqueueActionEmailsForQueryIDInt64: %w", err)
+			return errors.Errorf("store.LogSearchResults: %w", err)
+		}
+		err = s.EnqueueActionJobsForQueryIDInt64(ctx, q.Id, record.RecordID())
+		if err != nil {
+			return errors.Errorf("store.EnqueueActionJobsForQueryIDInt64: %w", err)
 		}
 	}
 	// Log next_run and latest_result to table cm_queries.
@@ -202,36 +206,48 @@ func (r *actionRunner) Handle(ctx context.Context, record workerutil.Record) (er
 	}
 	defer func() { err = s.Done(err) }()
 
-	var (
-		j    *cm.ActionJob
-		m    *cm.ActionJobMetadata
-		e    *cm.MonitorEmail
-		recs []*cm.Recipient
-		data *email.TemplateDataNewSearchResults
-	)
-
-	var ok bool
-	j, ok = record.(*cm.ActionJob)
+	j, ok := record.(*cm.ActionJob)
 	if !ok {
 		return errors.Errorf("type assertion failed")
 	}
 
-	m, err = s.GetActionJobMetadata(ctx, record.RecordID())
+	m, err := s.GetActionJobMetadata(ctx, record.RecordID())
 	if err != nil {
 		return errors.Errorf("store.GetActionJobMetadata: %w", err)
 	}
 
-	e, err = s.ActionEmailByIDInt64(ctx, j.Email)
+	switch {
+	case j.Email != nil:
+		return handleEmail(ctx, s, *j.Email, m)
+	case j.SlackWebhook != nil:
+		w, err := s.ActionWebhookByIDInt64(ctx, cm.SlackWebhooks, *j.SlackWebhook)
+		if err != nil {
+			return errors.Errorf("store.ActionWebhookByIDInt64: %w", err)
+		}
+		return sendSlackNotification(ctx, getWebhookDoer(), w.URL, m)
+	case j.Webhook != nil:
+		w, err := s.ActionWebhookByIDInt64(ctx, cm.Webhooks, *j.Webhook)
+		if err != nil {
+			return errors.Errorf("store.ActionWebhookByIDInt64: %w", err)
+		}
+		return sendWebhookNotification(ctx, getWebhookDoer(), w.URL, m)
+	default:
+		return errors.Errorf("action job %d has no action", j.Id)
+	}
+}
+
+func handleEmail(ctx context.Context, s *cm.Store, emailID int64, m *cm.ActionJobMetadata) error {
+	e, err := s.ActionEmailByIDInt64(ctx, emailID)
 	if err != nil {
 		return errors.Errorf("store.ActionEmailByIDInt64: %w", err)
 	}
 
-	recs, err = s.AllRecipientsForEmailIDInt64(ctx, j.Email)
+	recs, err := s.AllRecipientsForEmailIDInt64(ctx, emailID)
 	if err != nil {
 		return errors.Errorf("store.AllRecipientsForEmailIDInt64: %w", err)
 	}
 
-	data, err = email.NewTemplateDataForNewSearchResults(ctx, m.Description, m.Query, e, zeroOrVal(m.NumResults))
+	data, err := email.NewTemplateDataForNewSearchResults(ctx, m.Description, m.Query, e, zeroOrVal(m.NumResults))
 	if err != nil {
 		return errors.Errorf("email.NewTemplateDataForNewSearchResults: %w", err)
 	}
@@ -47,12 +47,12 @@ func NewTemplateDataForNewSearchResults(ctx context.Context, monitorDescription,
 		priority                  string
 		numberOfResultsWithDetail string
 	)
-	searchURL, err = getSearchURL(ctx, queryString, utmSourceEmail)
+	searchURL, err = GetSearchURL(ctx, queryString, utmSourceEmail)
 	if err != nil {
 		return nil, err
 	}
 
-	codeMonitorURL, err = getCodeMonitorURL(ctx, email.Monitor, utmSourceEmail)
+	codeMonitorURL, err = GetCodeMonitorURL(ctx, email.Monitor, utmSourceEmail)
 	if err != nil {
 		return nil, err
 	}
@@ -105,11 +105,14 @@ func sendEmail(ctx context.Context, userID int32, template txtypes.Templates, da
 	return nil
 }
 
-func getSearchURL(ctx context.Context, query, utmSource string) (string, error) {
+// GetSearchURL returns the URL of the search results page of query. utmSource
+// identifies the channel, such as email or Slack, the link was sent through.
+func GetSearchURL(ctx context.Context, query, utmSource string) (string, error) {
 	return sourcegraphURL(ctx, "search", query, utmSource)
 }
 
-func getCodeMonitorURL(ctx context.Context, monitorID int64, utmSource string) (string, error) {
+// GetCodeMonitorURL returns the URL of the code monitor with the given ID.
+func GetCodeMonitorURL(ctx context.Context, monitorID int64, utmSource string) (string, error) {
 	return sourcegraphURL(ctx, fmt.Sprintf("code-monitoring/%s", relay.MarshalID(MonitorKind, monitorID)), "", utmSource)
 }
 
@@ -3,6 +3,9 @@ package resolvers
 import (
 	"context"
 	"database/sql"
+	"net"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/cockroachdb/errors"
@@ -115,6 +118,11 @@ func (r *Resolver) CreateCodeMonitor(ctx context.Context, args *graphqlbackend.C
 	if err != nil {
 		return nil, err
 	}
+	for _, a := range args.Actions {
+		if err = validateCreateActionArgs(a); err != nil {
+			return nil, err
+		}
+	}
 	var mo *cm.Monitor
 	mo, err = r.store.CreateCodeMonitor(ctx, args)
 	if err != nil {
@@ -170,7 +178,11 @@ func (r *Resolver) UpdateCodeMonitor(ctx context.Context, args *graphqlbackend.U
 	}
 
 	toCreate, toDelete, err := splitActionIDs(ctx, args, actionIDs)
-	if len(toDelete) == len(actionIDs) {
+	if err != nil {
+		return nil, err
+	}
+	// After splitActionIDs, args.Actions only contains the actions to update.
+	if len(toCreate) == 0 && len(args.Actions) == 0 {
 		return nil, errors.Errorf("you tried to delete all actions, but every monitor must be connected to at least 1 action")
 	}
 
@@ -182,7 +194,7 @@ func (r *Resolver) UpdateCodeMonitor(ctx context.Context, args *graphqlbackend.U
 	}
 	defer func() { err = tx.store.Done(err) }()
 
-	err = tx.store.DeleteActionsInt64(ctx, toDelete, monitorID)
+	err = tx.deleteActions(ctx, toDelete, monitorID)
 	if err != nil {
 		return nil, err
 	}
@@ -278,9 +290,29 @@ func (r *Resolver) actionIDsForMonitorIDInt64(ctx context.Context, monitorID int
 		}
 		after = cur
 	}
+
+	// Webhooks are far less numerous than emails, so we don't bother paging.
+	slackWebhooks, err := r.store.ListActionWebhooks(ctx, cm.SlackWebhooks, monitorID, &graphqlbackend.ListActionArgs{First: maxWebhookActions})
+	if err != nil {
+		return nil, err
+	}
+	for _, w := range slackWebhooks {
+		ids = append(ids, (&monitorSlackWebhook{MonitorWebhook: w}).ID())
+	}
+	webhooks, err := r.store.ListActionWebhooks(ctx, cm.Webhooks, monitorID, &graphqlbackend.ListActionArgs{First: maxWebhookActions})
+	if err != nil {
+		return nil, err
+	}
+	for _, w := range webhooks {
+		ids = append(ids, (&monitorWebhook{MonitorWebhook: w}).ID())
+	}
 	return ids, nil
 }
 
+// maxWebhookActions bounds the number of webhook actions of each kind we read
+// for a single monitor.
+const maxWebhookActions = 1000
+
 func (r *Resolver) actionIDsForMonitorIDINT64SinglePage(ctx context.Context, q *sqlf.Query, limit int) (ids []graphql.ID, cursor *string, err error) {
 	var rows *sql.Rows
 	rows, err = r.store.Query(ctx, q)
@@ -309,35 +341,152 @@ func (r *Resolver) actionIDsForMonitorIDINT64SinglePage(ctx context.Context, q *
 
 // splitActionIDs splits actions into three buckets: create, delete and update.
 // Note: args is mutated. After splitActionIDs, args only contains actions to be updated.
-func splitActionIDs(ctx context.Context, args *graphqlbackend.UpdateCodeMonitorArgs, actionIDs []graphql.ID) (toCreate []*graphqlbackend.CreateActionArgs, toDelete []int64, err error) {
+func splitActionIDs(ctx context.Context, args *graphqlbackend.UpdateCodeMonitorArgs, actionIDs []graphql.ID) (toCreate []*graphqlbackend.CreateActionArgs, toDelete []graphql.ID, err error) {
 	aMap := make(map[graphql.ID]struct{}, len(actionIDs))
 	for _, id := range actionIDs {
 		aMap[id] = struct{}{}
 	}
 	var toUpdateActions []*graphqlbackend.EditActionArgs
 	for _, a := range args.Actions {
-		if a.Email.Id == nil {
-			toCreate = append(toCreate, &graphqlbackend.CreateActionArgs{Email: a.Email.Update})
+		id, create, err := editActionIDAndCreateArgs(a)
+		if err != nil {
+			return nil, nil, err
+		}
+		if id == nil {
+			toCreate = append(toCreate, create)
 			continue
 		}
-		if _, ok := aMap[*a.Email.Id]; !ok {
-			return nil, nil, errors.Errorf("unknown ID=%s for action", *a.Email.Id)
+		if _, ok := aMap[*id]; !ok {
+			return nil, nil, errors.Errorf("unknown ID=%s for action", *id)
 		}
 		toUpdateActions = append(toUpdateActions, a)
-		delete(aMap, *a.Email.Id)
+		delete(aMap, *id)
 	}
-	var actionID int64
 	for k := range aMap {
-		err = relay.UnmarshalSpec(k, &actionID)
-		if err != nil {
-			return nil, nil, err
-		}
-		toDelete = append(toDelete, actionID)
+		toDelete = append(toDelete, k)
 	}
 	args.Actions = toUpdateActions
 	return toCreate, toDelete, nil
 }
 
+// editActionIDAndCreateArgs returns the ID of the action to edit, which is nil
+// for new actions, and the arguments to create it from scratch.
+func editActionIDAndCreateArgs(a *graphqlbackend.EditActionArgs) (*graphql.ID, *graphqlbackend.CreateActionArgs, error) {
+	var (
+		id     *graphql.ID
+		create graphqlbackend.CreateActionArgs
+		n      int
+	)
+	if a.Email != nil {
+		id, create.Email, n = a.Email.Id, a.Email.Update, n+1
+	}
+	if a.SlackWebhook != nil {
+		id, create.SlackWebhook, n = a.SlackWebhook.Id, a.SlackWebhook.Update, n+1
+	}
+	if a.Webhook != nil {
+		id, create.Webhook, n = a.Webhook.Id, a.Webhook.Update, n+1
+	}
+	if n != 1 {
+		return nil, nil, errors.New("exactly one of email, slackWebhook and webhook must be set for an action")
+	}
+	if err := validateCreateActionArgs(&create); err != nil {
+		return nil, nil, err
+	}
+	return id, &create, nil
+}
+
+// validateCreateActionArgs checks that exactly one kind of action is set and
+// that webhook URLs are valid.
+func validateCreateActionArgs(a *graphqlbackend.CreateActionArgs) error {
+	n := 0
+	if a.Email != nil {
+		n++
+	}
+	if a.SlackWebhook != nil {
+		n++
+		if err := validateWebhookURL(a.SlackWebhook.URL); err != nil {
+			return err
+		}
+	}
+	if a.Webhook != nil {
+		n++
+		if err := validateWebhookURL(a.Webhook.URL); err != nil {
+			return err
+		}
+	}
+	if n != 1 {
+		return errors.New("exactly one of email, slackWebhook and webhook must be set for an action")
+	}
+	return nil
+}
+
+// validateWebhookURL returns an error if rawURL is not an absolute http or
+// https URL of a public host, or of a private host allowed by the site
+// configuration. Hosts which only resolve to internal addresses are rejected
+// when the webhook is posted to, see cm.WebhookAllowlist.
+func validateWebhookURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return errors.Wrap(err, "invalid webhook URL")
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.Errorf("invalid webhook URL %q: must be an absolute http or https URL", rawURL)
+	}
+	host := u.Hostname()
+	allowlist := cm.CurrentWebhookAllowlist()
+	if allowlist.AllowsHost(host) {
+		return nil
+	}
+	if ip := net.ParseIP(host); (ip != nil && !allowlist.AllowsIP(ip)) || strings.EqualFold(host, "localhost") {
+		return errors.Errorf("invalid webhook URL %q: must not point to a private address", rawURL)
+	}
+	return nil
+}
+
+// deleteActions deletes the actions with the given IDs, which may be of any
+// kind, from the monitor.
+func (r *Resolver) deleteActions(ctx context.Context, ids []graphql.ID, monitorID int64) error {
+	var emails, slackWebhooks, webhooks []int64
+	for _, id := range ids {
+		var actionID int64
+		if err := relay.UnmarshalSpec(id, &actionID); err != nil {
+			return err
+		}
+		switch kind := relay.UnmarshalKind(id); kind {
+		case monitorActionEmailKind:
+			emails = append(emails, actionID)
+		case monitorActionSlackWebhookKind:
+			slackWebhooks = append(slackWebhooks, actionID)
+		case monitorActionWebhookKind:
+			webhooks = append(webhooks, actionID)
+		default:
+			return errors.Errorf("unknown action kind %q", kind)
+		}
+	}
+	if err := r.store.DeleteActionsInt64(ctx, emails, monitorID); err != nil {
+		return err
+	}
+	if err := r.store.DeleteActionWebhooks(ctx, cm.SlackWebhooks, slackWebhooks, monitorID); err != nil {
+		return err
+	}
+	return r.store.DeleteActionWebhooks(ctx, cm.Webhooks, webhooks, monitorID)
+}
+
+// unmarshalActionID returns the database ID of the action with the given
+// GraphQL ID, which must be of the given kind. The ID of an action of one kind
+// must not be used to edit an action of another kind with the same database ID.
+func unmarshalActionID(id *graphql.ID, kind string) (int64, error) {
+	if id == nil {
+		return 0, errors.New("nil is not a valid action ID")
+	}
+	if got := relay.UnmarshalKind(*id); got != kind {
+		return 0, errors.Errorf("expected action ID of kind %q, got %q", kind, got)
+	}
+	var actionID int64
+	err := relay.UnmarshalSpec(*id, &actionID)
+	return actionID, err
+}
+
 func (r *Resolver) updateCodeMonitor(ctx context.Context, args *graphqlbackend.UpdateCodeMonitorArgs) (m graphqlbackend.MonitorResolver, err error) {
 	// Update monitor.
 	var mo *cm.Monitor
@@ -357,27 +506,49 @@ func (r *Resolver) updateCodeMonitor(ctx context.Context, args *graphqlbackend.U
 			Monitor:  mo,
 		}, nil
 	}
-	var emailID int64
-	var e *cm.MonitorEmail
 	for i, action := range args.Actions {
-		if action.Email == nil {
-			return nil, errors.Errorf("missing email object for action %d", i)
-		}
-		err = relay.UnmarshalSpec(*action.Email.Id, &emailID)
-		if err != nil {
-			return nil, err
-		}
-		err = r.store.DeleteRecipients(ctx, emailID)
-		if err != nil {
-			return nil, err
-		}
-		e, err = r.store.UpdateActionEmail(ctx, mo.ID, action)
-		if err != nil {
-			return nil, err
-		}
-		err = r.store.CreateRecipients(ctx, action.Email.Update.Recipients, e.Id)
-		if err != nil {
-			return nil, err
+		switch {
+		case action.Email != nil:
+			var emailID int64
+			emailID, err = unmarshalActionID(action.Email.Id, monitorActionEmailKind)
+			if err != nil {
+				return nil, err
+			}
+			err = r.store.DeleteRecipients(ctx, emailID)
+			if err != nil {
+				return nil, err
+			}
+			var e *cm.MonitorEmail
+			e, err = r.store.UpdateActionEmail(ctx, mo.ID, action)
+			if err != nil {
+				return nil, err
+			}
+			err = r.store.CreateRecipients(ctx, action.Email.Update.Recipients, e.Id)
+			if err != nil {
+				return nil, err
+			}
+		case action.SlackWebhook != nil:
+			var webhookID int64
+			webhookID, err = unmarshalActionID(action.SlackWebhook.Id, monitorActionSlackWebhookKind)
+			if err != nil {
+				return nil, err
+			}
+			_, err = r.store.UpdateActionWebhook(ctx, cm.SlackWebhooks, mo.ID, webhookID, action.SlackWebhook.Update.Enabled, action.SlackWebhook.Update.URL)
+			if err != nil {
+				return nil, err
+			}
+		case action.Webhook != nil:
+			var webhookID int64
+			webhookID, err = unmarshalActionID(action.Webhook.Id, monitorActionWebhookKind)
+			if err != nil {
+				return nil, err
+			}
+			_, err = r.store.UpdateActionWebhook(ctx, cm.Webhooks, mo.ID, webhookID, action.Webhook.Update.Enabled, action.Webhook.Update.URL)
+			if err != nil {
+				return nil, err
+			}
+		default:
+			return nil, errors.Errorf("missing action object for action %d", i)
 		}
 	}
 	return &monitor{
@@ -521,6 +692,8 @@ const (
 	monitorTriggerQueryKind         = "CodeMonitorTriggerQuery"
 	monitorTriggerEventKind         = "CodeMonitorTriggerEvent"
 	monitorActionEmailKind          = "CodeMonitorActionEmail"
+	monitorActionSlackWebhookKind   = "CodeMonitorActionSlackWebhook"
+	monitorActionWebhookKind        = "CodeMonitorActionWebhook"
 	monitorActionEventKind          = "CodeMonitorActionEmailEvent"
 	monitorActionEmailRecipientKind = "CodeMonitorActionEmailRecipient"
 )
@@ -566,37 +739,115 @@ func (m *monitor) Actions(ctx context.Context, args *graphqlbackend.ListActionAr
 	return m.actionConnectionResolverWithTriggerID(ctx, nil, m.Monitor.ID, args)
 }
 
+// actionKinds is the order in which actions of different kinds are listed.
+var actionKinds = []string{monitorActionEmailKind, monitorActionSlackWebhookKind, monitorActionWebhookKind}
+
+// actionConnectionResolverWithTriggerID lists the actions of a monitor. Actions
+// are ordered by kind, then by ID. Since the cursor is the relay ID of the last
+// action of the previous page, its kind tells us where to continue.
 func (r *Resolver) actionConnectionResolverWithTriggerID(ctx context.Context, triggerEventID *int, monitorID int64, args *graphqlbackend.ListActionArgs) (graphqlbackend.MonitorActionConnectionResolver, error) {
-	// For now, we only support emails as actions. Once we add other actions such as
-	// webhooks, we have to query those tables here too.
-	q, err := r.store.ReadActionEmailQuery(ctx, monitorID, args)
-	if err != nil {
-		return nil, err
+	afterKind := -1
+	if args.After != nil {
+		kind := relay.UnmarshalKind(graphql.ID(*args.After))
+		for i, k := range actionKinds {
+			if k == kind {
+				afterKind = i
+			}
+		}
+		if afterKind == -1 {
+			return nil, errors.Errorf("invalid cursor %q", *args.After)
+		}
 	}
-	rows, err := r.store.Query(ctx, q)
+
+	actions := make([]graphqlbackend.MonitorAction, 0, args.First)
+	for i, kind := range actionKinds {
+		remaining := int(args.First) - len(actions)
+		if remaining <= 0 {
+			break
+		}
+		if i < afterKind {
+			continue
+		}
+		kindArgs := &graphqlbackend.ListActionArgs{First: int32(remaining)}
+		if i == afterKind {
+			kindArgs.After = args.After
+		}
+
+		switch kind {
+		case monitorActionEmailKind:
+			q, err := r.store.ReadActionEmailQuery(ctx, monitorID, kindArgs)
+			if err != nil {
+				return nil, err
+			}
+			rows, err := r.store.Query(ctx, q)
+			if err != nil {
+				return nil, err
+			}
+			es, err := cm.ScanEmails(rows)
+			if err != nil {
+				return nil, err
+			}
+			for _, e := range es {
+				actions = append(actions, &action{
+					email: &monitorEmail{
+						Resolver:       r,
+						MonitorEmail:   e,
+						triggerEventID: triggerEventID,
+					},
+				})
+			}
+		case monitorActionSlackWebhookKind:
+			ws, err := r.store.ListActionWebhooks(ctx, cm.SlackWebhooks, monitorID, kindArgs)
+			if err != nil {
+				return nil, err
+			}
+			for _, w := range ws {
+				actions = append(actions, &action{
+					slackWebhook: &monitorSlackWebhook{
+						Resolver:       r,
+						MonitorWebhook: w,
+						triggerEventID: triggerEventID,
+					},
+				})
+			}
+		case monitorActionWebhookKind:
+			ws, err := r.store.ListActionWebhooks(ctx, cm.Webhooks, monitorID, kindArgs)
+			if err != nil {
+				return nil, err
+			}
+			for _, w := range ws {
+				actions = append(actions, &action{
+					webhook: &monitorWebhook{
+						Resolver:       r,
+						MonitorWebhook: w,
+						triggerEventID: triggerEventID,
+					},
+				})
+			}
+		}
+	}
+
+	totalCount, err := r.totalCountActions(ctx, monitorID)
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
-	es, err := cm.ScanEmails(rows)
+	return &monitorActionConnection{actions: actions, totalCount: totalCount}, nil
+}
+
+func (r *Resolver) totalCountActions(ctx context.Context, monitorID int64) (int32, error) {
+	emails, err := r.store.TotalCountActionEmails(ctx, monitorID)
 	if err != nil {
-		return nil, err
+		return 0, err
 	}
-	totalCount, err := r.store.TotalCountActionEmails(ctx, monitorID)
+	slackWebhooks, err := r.store.TotalCountActionWebhooks(ctx, cm.SlackWebhooks, monitorID)
 	if err != nil {
-		return nil, err
+		return 0, err
 	}
-	actions := make([]graphqlbackend.MonitorAction, 0, len(es))
-	for _, e := range es {
-		actions = append(actions, &action{
-			email: &monitorEmail{
-				Resolver:       r,
-				MonitorEmail:   e,
-				triggerEventID: triggerEventID,
-			},
-		})
+	webhooks, err := r.store.TotalCountActionWebhooks(ctx, cm.Webhooks, monitorID)
+	if err != nil {
+		return 0, err
 	}
-	return &monitorActionConnection{actions: actions, totalCount: totalCount}, nil
+	return emails + slackWebhooks + webhooks, nil
 }
 
 //
@@ -738,20 +989,36 @@ func (a *monitorActionConnection) PageInfo(ctx context.Context) (*graphqlutil.Pa
 	if email, ok := last.ToMonitorEmail(); ok {
 		return graphqlutil.NextPageCursor(string(email.ID())), nil
 	}
-	return nil, errors.Errorf("we only support email actions for now")
+	if slackWebhook, ok := last.ToMonitorSlackWebhook(); ok {
+		return graphqlutil.NextPageCursor(string(slackWebhook.ID())), nil
+	}
+	if webhook, ok := last.ToMonitorWebhook(); ok {
+		return graphqlutil.NextPageCursor(string(webhook.ID())), nil
+	}
+	return nil, errors.Errorf("unknown action type")
 }
 
 //
 // Action <<UNION>>
 //
 type action struct {
-	email graphqlbackend.MonitorEmailResolver
+	email        graphqlbackend.MonitorEmailResolver
+	slackWebhook graphqlbackend.MonitorSlackWebhookResolver
+	webhook      graphqlbackend.MonitorWebhookResolver
 }
 
 func (a *action) ToMonitorEmail() (graphqlbackend.MonitorEmailResolver, bool) {
 	return a.email, a.email != nil
 }
 
+func (a *action) ToMonitorSlackWebhook() (graphqlbackend.MonitorSlackWebhookResolver, bool) {
+	return a.slackWebhook, a.slackWebhook != nil
+}
+
+func (a *action) ToMonitorWebhook() (graphqlbackend.MonitorWebhookResolver, bool) {
+	return a.webhook, a.webhook != nil
+}
+
 //
 // Email
 //
@@ -833,6 +1100,88 @@ func (m *monitorEmail) Events(ctx context.Context, args *graphqlbackend.ListEven
 	return &monitorActionEventConnection{events: events, totalCount: totalCount}, nil
 }
 
+//
+// Slack webhook
+//
+type monitorSlackWebhook struct {
+	*Resolver
+	*cm.MonitorWebhook
+
+	// If triggerEventID == nil, all events of this action will be returned.
+	// Otherwise, only those events of this action which are related to the specified
+	// trigger event will be returned.
+	triggerEventID *int
+}
+
+func (m *monitorSlackWebhook) ID() graphql.ID {
+	return relay.MarshalID(monitorActionSlackWebhookKind, m.Id)
+}
+
+func (m *monitorSlackWebhook) Enabled() bool {
+	return m.MonitorWebhook.Enabled
+}
+
+func (m *monitorSlackWebhook) URL() string {
+	return m.MonitorWebhook.URL
+}
+
+func (m *monitorSlackWebhook) Events(ctx context.Context, args *graphqlbackend.ListEventsArgs) (graphqlbackend.MonitorActionEventConnectionResolver, error) {
+	ajs, err := m.store.ReadActionSlackWebhookEvents(ctx, m.Id, m.triggerEventID, args)
+	if err != nil {
+		return nil, err
+	}
+	totalCount, err := m.store.TotalActionSlackWebhookEvents(ctx, m.Id, m.triggerEventID)
+	if err != nil {
+		return nil, err
+	}
+	events := make([]graphqlbackend.MonitorActionEventResolver, len(ajs))
+	for i, aj := range ajs {
+		events[i] = &monitorActionEvent{Resolver: m.Resolver, ActionJob: aj}
+	}
+	return &monitorActionEventConnection{events: events, totalCount: totalCount}, nil
+}
+
+//
+// Webhook
+//
+type monitorWebhook struct {
+	*Resolver
+	*cm.MonitorWebhook
+
+	// If triggerEventID == nil, all events of this action will be returned.
+	// Otherwise, only those events of this action which are related to the specified
+	// trigger event will be returned.
+	triggerEventID *int
+}
+
+func (m *monitorWebhook) ID() graphql.ID {
+	return relay.MarshalID(monitorActionWebhookKind, m.Id)
+}
+
+func (m *monitorWebhook) Enabled() bool {
+	return m.MonitorWebhook.Enabled
+}
+
+func (m *monitorWebhook) URL() string {
+	return m.MonitorWebhook.URL
+}
+
+func (m *monitorWebhook) Events(ctx context.Context, args *graphqlbackend.ListEventsArgs) (graphqlbackend.MonitorActionEventConnectionResolver, error) {
+	ajs, err := m.store.ReadActionWebhookEvents(ctx, m.Id, m.triggerEventID, args)
+	if err != nil {
+		return nil, err
+	}
+	totalCount, err := m.store.TotalActionWebhookEvents(ctx, m.Id, m.triggerEventID)
+	if err != nil {
+		return nil, err
+	}
+	events := make([]graphqlbackend.MonitorActionEventResolver, len(ajs))
+	for i, aj := range ajs {
+		events[i] = &monitorActionEvent{Resolver: m.Resolver, ActionJob: aj}
+	}
+	return &monitorActionEventConnection{events: events, totalCount: totalCount}, nil
+}
+
 //
 // MonitorActionEmailRecipientConnection
 //
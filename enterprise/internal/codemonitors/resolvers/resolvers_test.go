@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"sort"
 	"testing"
 	"time"
 
@@ -19,8 +20,10 @@ import (
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/codemonitors/resolvers/apitest"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/codemonitors/storetest"
 	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbtesting"
+	"github.com/sourcegraph/sourcegraph/schema"
 )
 
 func init() {
@@ -386,7 +389,7 @@ func TestQueryMonitor(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	schema, err := graphqlbackend.NewSchema(db, nil, nil, nil, nil, r, nil, nil)
+	schema, err := graphqlbackend.NewSchema(db, nil, nil, nil, nil, r, nil, nil, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -622,7 +625,7 @@ func TestEditCodeMonitor(t *testing.T) {
 
 	// Update the code monitor.
 	// We update all fields, delete one action, and add a new action.
-	schema, err := graphqlbackend.NewSchema(db, nil, nil, nil, nil, r, nil, nil)
+	schema, err := graphqlbackend.NewSchema(db, nil, nil, nil, nil, r, nil, nil, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -1197,3 +1200,116 @@ func TestMonitorKindEqualsResolvers(t *testing.T) {
 		t.Fatal("email.MonitorKind should match resolvers.MonitorKind")
 	}
 }
+
+func TestSplitActionIDs(t *testing.T) {
+	emailID := relay.MarshalID(monitorActionEmailKind, 1)
+	slackID := relay.MarshalID(monitorActionSlackWebhookKind, 1)
+	webhookID := relay.MarshalID(monitorActionWebhookKind, 1)
+	deletedID := relay.MarshalID(monitorActionWebhookKind, 2)
+
+	args := &graphqlbackend.UpdateCodeMonitorArgs{
+		Actions: []*graphqlbackend.EditActionArgs{
+			{Email: &graphqlbackend.EditActionEmailArgs{Id: &emailID, Update: &graphqlbackend.CreateActionEmailArgs{}}},
+			{SlackWebhook: &graphqlbackend.EditActionSlackWebhookArgs{Id: &slackID, Update: &graphqlbackend.CreateActionSlackWebhookArgs{URL: "https://hooks.slack.com/services/x"}}},
+			{Webhook: &graphqlbackend.EditActionWebhookArgs{Update: &graphqlbackend.CreateActionWebhookArgs{URL: "https://example.com/hook"}}},
+		},
+	}
+
+	toCreate, toDelete, err := splitActionIDs(context.Background(), args, []graphql.ID{emailID, slackID, webhookID, deletedID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(toCreate) != 1 || toCreate[0].Webhook == nil || toCreate[0].Webhook.URL != "https://example.com/hook" {
+		t.Fatalf("unexpected actions to create: %+v", toCreate)
+	}
+	if len(args.Actions) != 2 {
+		t.Fatalf("expected 2 actions to update, got %d", len(args.Actions))
+	}
+	sortIDs := func(ids []graphql.ID) {
+		sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	}
+	wantDelete := []graphql.ID{webhookID, deletedID}
+	sortIDs(toDelete)
+	sortIDs(wantDelete)
+	if diff := cmp.Diff(wantDelete, toDelete); diff != "" {
+		t.Fatalf("unexpected actions to delete (-want +got):\n%s", diff)
+	}
+
+	t.Run("invalid URL", func(t *testing.T) {
+		args := &graphqlbackend.UpdateCodeMonitorArgs{
+			Actions: []*graphqlbackend.EditActionArgs{
+				{Webhook: &graphqlbackend.EditActionWebhookArgs{Update: &graphqlbackend.CreateActionWebhookArgs{URL: "file:///etc/passwd"}}},
+			},
+		}
+		if _, _, err := splitActionIDs(context.Background(), args, nil); err == nil {
+			t.Fatal("expected error for non-http URL")
+		}
+	})
+
+	t.Run("multiple kinds", func(t *testing.T) {
+		args := &graphqlbackend.UpdateCodeMonitorArgs{
+			Actions: []*graphqlbackend.EditActionArgs{{
+				Email:   &graphqlbackend.EditActionEmailArgs{Update: &graphqlbackend.CreateActionEmailArgs{}},
+				Webhook: &graphqlbackend.EditActionWebhookArgs{Update: &graphqlbackend.CreateActionWebhookArgs{URL: "https://example.com/hook"}},
+			}},
+		}
+		if _, _, err := splitActionIDs(context.Background(), args, nil); err == nil {
+			t.Fatal("expected error for action with multiple kinds")
+		}
+	})
+}
+
+func TestValidateWebhookURL(t *testing.T) {
+	for _, u := range []string{
+		"https://hooks.slack.com/services/x",
+		"http://93.184.216.34:8080/hook",
+	} {
+		if err := validateWebhookURL(u); err != nil {
+			t.Errorf("unexpected error for %q: %s", u, err)
+		}
+	}
+	for _, u := range []string{
+		"file:///etc/passwd",
+		"/relative",
+		"http://localhost:3080/hook",
+		"http://127.0.0.1/hook",
+		"http://10.0.0.1/hook",
+		"http://169.254.169.254/latest/meta-data/",
+		"http://[::1]/hook",
+		"http://[fd00::1]/hook",
+	} {
+		if err := validateWebhookURL(u); err == nil {
+			t.Errorf("expected error for %q", u)
+		}
+	}
+
+	conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{
+		CodeMonitorsWebhookAllowedPrivateAddresses: []string{"10.0.0.0/8", "localhost"},
+	}})
+	defer conf.Mock(nil)
+	for _, u := range []string{
+		"http://10.0.0.1/hook",
+		"http://localhost:3080/hook",
+	} {
+		if err := validateWebhookURL(u); err != nil {
+			t.Errorf("unexpected error for allowed %q: %s", u, err)
+		}
+	}
+	if err := validateWebhookURL("http://192.168.0.1/hook"); err == nil {
+		t.Error("expected error for address that is not allowed")
+	}
+}
+
+func TestUnmarshalActionID(t *testing.T) {
+	slackID := relay.MarshalID(monitorActionSlackWebhookKind, 1)
+	id, err := unmarshalActionID(&slackID, monitorActionSlackWebhookKind)
+	if err != nil || id != 1 {
+		t.Fatalf("got %d, %v, want 1", id, err)
+	}
+	if _, err := unmarshalActionID(&slackID, monitorActionWebhookKind); err == nil {
+		t.Fatal("expected error for ID of another action kind")
+	}
+	if _, err := unmarshalActionID(nil, monitorActionWebhookKind); err == nil {
+		t.Fatal("expected error for nil ID")
+	}
+}
@@ -0,0 +1,98 @@
+package codemonitors
+
+import (
+	"net"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+)
+
+// nonPublicNetworks are the networks, other than loopback, link-local and
+// multicast addresses, which are not reachable from the public internet.
+var nonPublicNetworks = mustParseCIDRs(
+	"0.0.0.0/8",
+	"10.0.0.0/8",
+	"100.64.0.0/10",
+	"172.16.0.0/12",
+	"192.168.0.0/16",
+	"fc00::/7",
+)
+
+// IsPublicIP returns true if ip is a public unicast address. Webhook actions
+// may only post to public addresses, so that code monitors can't be used to
+// reach services on the internal network, such as cloud metadata endpoints.
+func IsPublicIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() {
+		return false
+	}
+	for _, n := range nonPublicNetworks {
+		if n.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// WebhookAllowlist are the private networks and hosts that webhook actions may
+// post to anyway, as set by the site configuration setting
+// codeMonitors.webhookAllowedPrivateAddresses.
+type WebhookAllowlist struct {
+	nets  []*net.IPNet
+	hosts map[string]struct{}
+}
+
+// NewWebhookAllowlist parses entries, which are either IP addresses, networks
+// in CIDR notation, or host names.
+func NewWebhookAllowlist(entries []string) *WebhookAllowlist {
+	a := &WebhookAllowlist{hosts: map[string]struct{}{}}
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if _, n, err := net.ParseCIDR(entry); err == nil {
+			a.nets = append(a.nets, n)
+		} else if ip := net.ParseIP(entry); ip != nil {
+			a.nets = append(a.nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(len(ip)*8, len(ip)*8)})
+		} else if entry != "" {
+			a.hosts[strings.ToLower(entry)] = struct{}{}
+		}
+	}
+	return a
+}
+
+// CurrentWebhookAllowlist returns the allowlist of the current site
+// configuration.
+func CurrentWebhookAllowlist() *WebhookAllowlist {
+	return NewWebhookAllowlist(conf.Get().CodeMonitorsWebhookAllowedPrivateAddresses)
+}
+
+// AllowsHost returns true if host is allowed regardless of the addresses it
+// resolves to.
+func (a *WebhookAllowlist) AllowsHost(host string) bool {
+	_, ok := a.hosts[strings.ToLower(host)]
+	return ok
+}
+
+// AllowsIP returns true if ip is public or in one of the allowed networks.
+func (a *WebhookAllowlist) AllowsIP(ip net.IP) bool {
+	if IsPublicIP(ip) {
+		return true
+	}
+	for _, n := range a.nets {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func mustParseCIDRs(cidrs ...string) []*net.IPNet {
+	nets := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, n, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		nets = append(nets, n)
+	}
+	return nets
+}
@@ -0,0 +1,34 @@
+package codemonitors
+
+import (
+	"net"
+	"testing"
+)
+
+func TestWebhookAllowlist(t *testing.T) {
+	a := NewWebhookAllowlist([]string{"10.1.2.0/24", "192.168.0.7", "Hooks.Internal", " "})
+
+	for ip, want := range map[string]bool{
+		"93.184.216.34": true,
+		"10.1.2.3":      true,
+		"10.1.3.1":      false,
+		"192.168.0.7":   true,
+		"192.168.0.8":   false,
+		"127.0.0.1":     false,
+	} {
+		if got := a.AllowsIP(net.ParseIP(ip)); got != want {
+			t.Errorf("AllowsIP(%s) = %t, want %t", ip, got, want)
+		}
+	}
+
+	for host, want := range map[string]bool{
+		"hooks.internal": true,
+		"HOOKS.internal": true,
+		"example.com":    false,
+		"":               false,
+	} {
+		if got := a.AllowsHost(host); got != want {
+			t.Errorf("AllowsHost(%q) = %t, want %t", host, got, want)
+		}
+	}
+}
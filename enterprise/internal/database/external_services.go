@@ -3,7 +3,10 @@ package database
 import (
 	"database/sql"
 
+	"github.com/sourcegraph/sourcegraph/internal/authz/bitbucketcloud"
 	"github.com/sourcegraph/sourcegraph/internal/authz/bitbucketserver"
+	"github.com/sourcegraph/sourcegraph/internal/authz/gerrit"
+	"github.com/sourcegraph/sourcegraph/internal/authz/gitea"
 	"github.com/sourcegraph/sourcegraph/internal/authz/github"
 	"github.com/sourcegraph/sourcegraph/internal/authz/gitlab"
 	"github.com/sourcegraph/sourcegraph/internal/authz/perforce"
@@ -27,9 +30,18 @@ func NewExternalServicesStore(db dbutil.DB) *database.ExternalServiceStore {
 	es.BitbucketServerValidators = []func(*schema.BitbucketServerConnection) error{
 		bitbucketserver.ValidateAuthz,
 	}
+	es.BitbucketCloudValidators = []func(*schema.BitbucketCloudConnection) error{
+		bitbucketcloud.ValidateAuthz,
+	}
 	es.PerforceValidators = []func(connection *schema.PerforceConnection) error{
 		perforce.ValidateAuthz,
 	}
+	es.GiteaValidators = []func(*schema.GiteaConnection) error{
+		gitea.ValidateAuthz,
+	}
+	es.GerritValidators = []func(*schema.GerritConnection) error{
+		gerrit.ValidateAuthz,
+	}
 
 	return es
 }
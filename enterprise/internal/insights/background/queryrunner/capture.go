@@ -0,0 +1,181 @@
+package queryrunner
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/graph-gophers/graphql-go"
+	"github.com/hashicorp/go-multierror"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/store"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/search/query"
+)
+
+// This file contains the methods required to record series generated from capture groups. Instead
+// of recording a single match count per repository, these series record a match count per
+// repository for every distinct value of the first capture group of the search pattern. For
+// example, the pattern `github.com/foo/bar v(\d+\.\d+)` records one series per version.
+
+// captureGroupPattern returns the regular expression whose first capture group is used to
+// generate series from the given search query.
+func captureGroupPattern(q string) (*regexp.Regexp, error) {
+	// Parse the query without transformations: search joins space separated patterns into a
+	// single pattern wrapping each of them in a capture group, which would shift the capture
+	// group of the user's pattern.
+	nodes, err := query.Parse(q, query.SearchTypeRegex)
+	if err != nil {
+		return nil, errors.Wrap(err, "Parse")
+	}
+
+	var patterns []string
+	query.VisitPattern(nodes, func(value string, negated bool, _ query.Annotation) {
+		if !negated {
+			patterns = append(patterns, value)
+		}
+	})
+	if len(patterns) == 0 {
+		return nil, errors.Errorf("capture group series require a search pattern: %q", q)
+	}
+
+	// Join the patterns the same way search does, but without capturing.
+	pattern := "(?:" + strings.Join(patterns, ").*?(?:") + ")"
+	if len(patterns) == 1 {
+		pattern = patterns[0]
+	}
+	if !query.Q(nodes).IsCaseSensitive() {
+		pattern = "(?i:" + pattern + ")"
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, errors.Wrap(err, "compiling search pattern")
+	}
+	if re.NumSubexp() == 0 {
+		return nil, errors.Errorf("search pattern %q has no capture group", strings.Join(patterns, " "))
+	}
+	return re, nil
+}
+
+// captureValues returns the number of matches per value of the first capture group of re, found
+// in the line previews of the file match. Matches spanning multiple lines are not counted.
+func (r *fileMatch) captureValues(re *regexp.Regexp) map[string]int {
+	values := map[string]int{}
+	for _, lineMatch := range r.LineMatches {
+		for _, m := range re.FindAllStringSubmatch(lineMatch.Preview, -1) {
+			// An optional capture group that did not participate in the match has no value.
+			if m[1] == "" {
+				continue
+			}
+			values[m[1]]++
+		}
+	}
+	return values
+}
+
+// recordCaptureGroups records one data point per repository and capture group value found in the
+// search results.
+func (r *workHandler) recordCaptureGroups(ctx context.Context, job *Job, recordTime time.Time, results *gqlSearchResponse) (err error) {
+	re, err := captureGroupPattern(job.SearchQuery)
+	if err != nil {
+		return err
+	}
+
+	found := make(map[api.RepoID]map[string]int, len(results.Data.Search.Results.Results))
+	repoNames := make(map[api.RepoID]string, len(found))
+	for _, result := range results.Data.Search.Results.Results {
+		decoded, err := decodeResult(result)
+		if err != nil {
+			return errors.Wrap(err, fmt.Sprintf(`for query "%s"`, job.SearchQuery))
+		}
+		fm, ok := decoded.(*fileMatch)
+		if !ok {
+			continue
+		}
+		dbRepoID, idErr := graphqlbackend.UnmarshalRepositoryID(graphql.ID(fm.repoID()))
+		if idErr != nil {
+			err = multierror.Append(err, errors.Wrap(idErr, "UnmarshalRepositoryID"))
+			continue
+		}
+		if len(fm.repoName()) == 0 {
+			// this really should never happen, expect if for some reason the gql response is broken
+			err = multierror.Append(err, errors.Newf("MissingRepositoryName for repo_id: %v", string(dbRepoID)))
+			continue
+		}
+		repoNames[dbRepoID] = fm.repoName()
+		if found[dbRepoID] == nil {
+			found[dbRepoID] = map[string]int{}
+		}
+		for value, count := range fm.captureValues(re) {
+			found[dbRepoID][value] += count
+		}
+	}
+
+	recorded, recordedErr := r.insightsStore.SeriesRepoCaptures(ctx, job.SeriesID)
+	if recordedErr != nil {
+		return multierror.Append(err, errors.Wrap(recordedErr, "SeriesRepoCaptures"))
+	}
+
+	for _, point := range captureGroupPoints(found, repoNames, recorded) {
+		point := point
+		if recordErr := r.insightsStore.RecordSeriesPoint(ctx, store.RecordSeriesPointArgs{
+			SeriesID: job.SeriesID,
+			Point: store.SeriesPoint{
+				Time:  recordTime,
+				Value: float64(point.Count),
+			},
+			RepoName: &point.RepoName,
+			RepoID:   &point.RepoID,
+			Capture:  &point.Capture,
+		}); recordErr != nil {
+			err = multierror.Append(err, errors.Wrap(recordErr, "RecordSeriesPoint"))
+		}
+	}
+	return err
+}
+
+// capturePoint is the number of matches of a capture group value in a repository.
+type capturePoint struct {
+	store.RepoCapture
+	Count int
+}
+
+// captureGroupPoints returns the data points to record for the capture group values found per
+// repository. The last observation of every capture value is carried forward when querying the
+// series, so a value that disappeared from a repository would be counted forever. The recorded
+// values that are no longer found, including those of repositories without any match, are
+// therefore recorded as zero.
+func captureGroupPoints(found map[api.RepoID]map[string]int, repoNames map[api.RepoID]string, recorded []store.RepoCapture) []capturePoint {
+	var points []capturePoint
+	for repoID, values := range found {
+		for value, count := range values {
+			points = append(points, capturePoint{
+				RepoCapture: store.RepoCapture{RepoID: repoID, RepoName: repoNames[repoID], Capture: value},
+				Count:       count,
+			})
+		}
+	}
+
+	for _, c := range recorded {
+		if _, ok := found[c.RepoID][c.Capture]; ok {
+			continue
+		}
+		if name, ok := repoNames[c.RepoID]; ok {
+			c.RepoName = name
+		}
+		points = append(points, capturePoint{RepoCapture: c})
+	}
+
+	sort.Slice(points, func(i, j int) bool {
+		if points[i].RepoID != points[j].RepoID {
+			return points[i].RepoID < points[j].RepoID
+		}
+		return points[i].Capture < points[j].Capture
+	})
+	return points
+}
@@ -0,0 +1,100 @@
+package queryrunner
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/hexops/autogold"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/store"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+)
+
+func TestCaptureGroupPattern(t *testing.T) {
+	testCases := []struct {
+		query string
+		want  autogold.Value
+	}{
+		{
+			query: `github.com/foo/bar v(\d+\.\d+) file:go.mod`,
+			want: autogold.Want("space separated patterns", [2]string{
+				"(?i:(?:github.com/foo/bar).*?(?:v(\\d+\\.\\d+)))",
+				"<nil>",
+			}),
+		},
+		{
+			query: `github.com/foo/bar v(\d+\.\d+) case:yes`,
+			want: autogold.Want("case sensitive", [2]string{
+				"(?:github.com/foo/bar).*?(?:v(\\d+\\.\\d+))",
+				"<nil>",
+			}),
+		},
+		{
+			query: `"github.com/foo/bar v(\d+\.\d+)"`,
+			want: autogold.Want("quoted pattern", [2]string{
+				"(?i:github.com/foo/bar v(\\d+\\.\\d+))",
+				"<nil>",
+			}),
+		},
+		{
+			query: `github.com/foo/bar`,
+			want:  autogold.Want("no capture group", [2]string{"", `search pattern "github.com/foo/bar" has no capture group`}),
+		},
+		{
+			query: `repo:foo`,
+			want:  autogold.Want("no pattern", [2]string{"", `capture group series require a search pattern: "repo:foo"`}),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.want.Name(), func(t *testing.T) {
+			re, err := captureGroupPattern(tc.query)
+			got := ""
+			if re != nil {
+				got = re.String()
+			}
+			tc.want.Equal(t, [2]string{got, fmt.Sprint(err)})
+		})
+	}
+}
+
+func TestFileMatchCaptureValues(t *testing.T) {
+	re, err := captureGroupPattern(`github.com/foo/bar v(\d+\.\d+)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var fm fileMatch
+	if err := json.Unmarshal([]byte(`{
+		"repository": {"id": "UmVwb3NpdG9yeTox", "name": "github.com/a/b"},
+		"lineMatches": [
+			{"preview": "require github.com/foo/bar v1.2.3", "offsetAndLengths": [[8, 25]]},
+			{"preview": "replace github.com/foo/bar v1.2.0 => github.com/foo/bar v1.3.0", "offsetAndLengths": [[8, 25], [37, 25]]}
+		]
+	}`), &fm); err != nil {
+		t.Fatal(err)
+	}
+	autogold.Want("capture values", map[string]int{"1.2": 2, "1.3": 1}).Equal(t, fm.captureValues(re))
+}
+
+func TestCaptureGroupPoints(t *testing.T) {
+	found := map[api.RepoID]map[string]int{
+		1: {"1.3": 2},
+		2: {"1.2": 1},
+	}
+	repoNames := map[api.RepoID]string{1: "github.com/a/b", 2: "github.com/c/d"}
+	recorded := []store.RepoCapture{
+		// Repo 1 moved from 1.2 to 1.3.
+		{RepoID: 1, RepoName: "github.com/a/b", Capture: "1.2"},
+		{RepoID: 1, RepoName: "github.com/a/b", Capture: "1.3"},
+		// Repo 3 no longer matches at all.
+		{RepoID: 3, RepoName: "github.com/e/f", Capture: "1.1"},
+	}
+
+	autogold.Want("capture points", []capturePoint{
+		{RepoCapture: store.RepoCapture{RepoID: 1, RepoName: "github.com/a/b", Capture: "1.2"}},
+		{RepoCapture: store.RepoCapture{RepoID: 1, RepoName: "github.com/a/b", Capture: "1.3"}, Count: 2},
+		{RepoCapture: store.RepoCapture{RepoID: 2, RepoName: "github.com/c/d", Capture: "1.2"}, Count: 1},
+		{RepoCapture: store.RepoCapture{RepoID: 3, RepoName: "github.com/e/f", Capture: "1.1"}},
+	}).Equal(t, captureGroupPoints(found, repoNames, recorded))
+}
@@ -72,6 +72,40 @@ const gqlSearchQuery = `query Search(
 	}
 }`
 
+// gqlCaptureSearchQuery is the GraphQL query used for series generated from capture groups. The
+// search pattern is always interpreted as a regular expression, and the line previews are
+// requested so that the values of the capture group can be extracted.
+const gqlCaptureSearchQuery = `query Search(
+	$query: String!,
+) {
+	search(query: $query, version: V2, patternType:regexp) {
+		results {
+			limitHit
+			cloning { name }
+			missing { name }
+			timedout { name }
+			matchCount
+			results {
+				__typename
+				... on FileMatch {
+					repository {
+						id
+						name
+					}
+					lineMatches {
+						preview
+						offsetAndLengths
+					}
+				}
+			}
+			alert {
+				title
+				description
+			}
+		}
+	}
+}`
+
 type gqlSearchVars struct {
 	Query string `json:"query"`
 }
@@ -98,9 +132,19 @@ type gqlSearchResponse struct {
 
 // search executes the given search query.
 func search(ctx context.Context, query string) (*gqlSearchResponse, error) {
+	return doSearch(ctx, gqlSearchQuery, query)
+}
+
+// captureSearch executes the given regexp search query, returning file matches with their line
+// previews.
+func captureSearch(ctx context.Context, query string) (*gqlSearchResponse, error) {
+	return doSearch(ctx, gqlCaptureSearchQuery, query)
+}
+
+func doSearch(ctx context.Context, gqlQuery, query string) (*gqlSearchResponse, error) {
 	var buf bytes.Buffer
 	err := json.NewEncoder(&buf).Encode(graphQLQuery{
-		Query:     gqlSearchQuery,
+		Query:     gqlQuery,
 		Variables: gqlSearchVars{Query: query},
 	})
 	if err != nil {
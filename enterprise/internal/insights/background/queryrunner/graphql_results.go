@@ -114,6 +114,7 @@ type fileMatch struct {
 		Name string
 	}
 	LineMatches []struct {
+		Preview          string
 		OffsetAndLengths [][]int
 	}
 	Symbols []struct {
@@ -26,6 +26,7 @@ var _ workerutil.Handler = &workHandler{}
 type workHandler struct {
 	workerBaseStore *basestore.Store
 	insightsStore   *store.Store
+	metadataStore   store.DataSeriesStore
 	limiter         *rate.Limiter
 }
 
@@ -42,6 +43,13 @@ func (r *workHandler) Handle(ctx context.Context, record workerutil.Record) (err
 		return err
 	}
 
+	// Series generated from capture groups record one data point per capture group value, which
+	// requires a regexp search returning the matched lines.
+	captureGroups, err := r.generatedFromCaptureGroups(ctx, job.SeriesID)
+	if err != nil {
+		return err
+	}
+
 	err = r.limiter.Wait(ctx)
 	if err != nil {
 		return err
@@ -54,7 +62,11 @@ func (r *workHandler) Handle(ctx context.Context, record workerutil.Record) (err
 	// that a repository exists may or may not be fine, exposing individual results is definitely
 	// not, etc.)
 	var results *gqlSearchResponse
-	results, err = search(ctx, job.SearchQuery)
+	if captureGroups {
+		results, err = captureSearch(ctx, job.SearchQuery)
+	} else {
+		results, err = search(ctx, job.SearchQuery)
+	}
 	if err != nil {
 		return err
 	}
@@ -105,6 +117,10 @@ func (r *workHandler) Handle(ctx context.Context, record workerutil.Record) (err
 		recordTime = *job.RecordTime
 	}
 
+	if captureGroups {
+		return r.recordCaptureGroups(ctx, job, recordTime, results)
+	}
+
 	// Figure out how many matches we got for every unique repository returned in the search
 	// results.
 	matchesPerRepo := make(map[string]int, len(results.Data.Search.Results.Results)*4)
@@ -145,3 +161,13 @@ func (r *workHandler) Handle(ctx context.Context, record workerutil.Record) (err
 	}
 	return err
 }
+
+// generatedFromCaptureGroups returns true if the series with the given ID is generated from
+// capture groups. Series that no longer exist are treated as regular series.
+func (r *workHandler) generatedFromCaptureGroups(ctx context.Context, seriesID string) (bool, error) {
+	series, err := r.metadataStore.GetDataSeries(ctx, store.GetDataSeriesArgs{SeriesID: seriesID})
+	if err != nil {
+		return false, errors.Wrap(err, "GetDataSeries")
+	}
+	return len(series) > 0 && series[0].GeneratedFromCaptureGroups, nil
+}
@@ -33,7 +33,7 @@ import (
 
 // NewWorker returns a worker that will execute search queries and insert information about the
 // results into the code insights database.
-func NewWorker(ctx context.Context, workerBaseStore *basestore.Store, insightsStore *store.Store, metrics workerutil.WorkerMetrics) *workerutil.Worker {
+func NewWorker(ctx context.Context, workerBaseStore *basestore.Store, insightsStore *store.Store, metadataStore store.DataSeriesStore, metrics workerutil.WorkerMetrics) *workerutil.Worker {
 	workerStore := createDBWorkerStore(workerBaseStore)
 
 	numHandlers := conf.Get().InsightsQueryWorkerConcurrency
@@ -63,6 +63,7 @@ func NewWorker(ctx context.Context, workerBaseStore *basestore.Store, insightsSt
 	return dbworker.NewWorker(ctx, workerStore, &workHandler{
 		workerBaseStore: workerBaseStore,
 		insightsStore:   insightsStore,
+		metadataStore:   metadataStore,
 		limiter:         limiter,
 	}, options)
 }
@@ -94,6 +95,11 @@ func NewResetter(ctx context.Context, workerBaseStore *basestore.Store, metrics
 	return dbworker.NewResetter(workerStore, options)
 }
 
+// NewWorkerStore returns the dbworker store of the query runner jobs queue.
+func NewWorkerStore(s *basestore.Store) dbworkerstore.Store {
+	return createDBWorkerStore(s)
+}
+
 // createDBWorkerStore creates the dbworker store for the query runner worker.
 //
 // See internal/workerutil/dbworker for more information about dbworkers.
@@ -105,14 +111,21 @@ func createDBWorkerStore(s *basestore.Store) dbworkerstore.Store {
 		Scan:              scanJobs,
 
 		// We will let a search query or webhook run for up to 60s. After that, it times out and
-		// retries in 10s. If 3 timeouts occur, it is not retried.
+		// retries after 10s, 20s and 40s (give or take 10%, so that the jobs of an insight that
+		// failed together are spread out). If 3 timeouts occur, it is not retried.
 		//
 		// If you change this, be sure to adjust the interval that work is enqueued in
 		// enterprise/internal/insights/background:newInsightEnqueuer.
-		StalledMaxAge:     60 * time.Second,
-		RetryAfter:        10 * time.Second,
-		MaxNumRetries:     3,
-		OrderByExpression: sqlf.Sprintf("priority, id"),
+		StalledMaxAge:          60 * time.Second,
+		RetryAfter:             10 * time.Second,
+		RetryBackoffMultiplier: 2,
+		RetryJitter:            0.1,
+		MaxNumRetries:          3,
+		OrderByExpression:      sqlf.Sprintf("priority, id"),
+
+		// Round-robin across series so that backfilling the history of one insight does not delay
+		// the jobs of all other insights. Jobs of the same series are still dequeued by priority.
+		FairnessKeyExpression: sqlf.Sprintf("series_id"),
 	})
 }
 
@@ -79,8 +79,9 @@ func convertFromBackendInsight(backendInsights []*schema.Insight) []insights.Sea
 		temp.Description = backendInsight.Description
 		for _, series := range backendInsight.Series {
 			temp.Series = append(temp.Series, insights.TimeSeries{
-				Name:  series.Label,
-				Query: series.Search,
+				Name:                       series.Label,
+				Query:                      series.Search,
+				GeneratedFromCaptureGroups: series.GeneratedFromCaptureGroups,
 			})
 		}
 		temp.ID = backendInsight.Id
@@ -201,9 +202,10 @@ func migrateSeries(ctx context.Context, insightStore *store.InsightStore, from i
 
 	for i, timeSeries := range from.Series {
 		temp := types.InsightSeries{
-			SeriesID:              Encode(timeSeries),
-			Query:                 timeSeries.Query,
-			RecordingIntervalDays: 1,
+			SeriesID:                   Encode(timeSeries),
+			Query:                      timeSeries.Query,
+			RecordingIntervalDays:      1,
+			GeneratedFromCaptureGroups: timeSeries.GeneratedFromCaptureGroups,
 		}
 		result, err := tx.CreateSeries(ctx, temp)
 		if err != nil {
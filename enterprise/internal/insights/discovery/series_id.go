@@ -20,6 +20,8 @@ import (
 // data will not be queryable.
 func EncodeSeriesID(series *schema.InsightSeries) (string, error) {
 	switch {
+	case series.Search != "" && series.GeneratedFromCaptureGroups:
+		return fmt.Sprintf("c:%s", sha256String(series.Search)), nil
 	case series.Search != "":
 		return fmt.Sprintf("s:%s", sha256String(series.Search)), nil
 	case series.Webhook != "":
@@ -30,6 +32,9 @@ func EncodeSeriesID(series *schema.InsightSeries) (string, error) {
 }
 
 func Encode(series insights.TimeSeries) string {
+	if series.GeneratedFromCaptureGroups {
+		return fmt.Sprintf("c:%s", sha256String(series.Query))
+	}
 	return fmt.Sprintf("s:%s", sha256String(series.Query))
 }
 
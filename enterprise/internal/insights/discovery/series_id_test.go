@@ -21,6 +21,13 @@ func TestEncodeSeriesID(t *testing.T) {
 				"<nil>",
 			}),
 		},
+		{
+			input: &schema.InsightSeries{Search: `github.com/foo/bar v(\d+\.\d+)`, GeneratedFromCaptureGroups: true},
+			want: autogold.Want("capture_groups_search", [2]interface{}{
+				"c:BE665D44336BC5DD726107D0997869657B69F95521B881E7BD2ECD014A0CA61B",
+				"<nil>",
+			}),
+		},
 		{
 			input: &schema.InsightSeries{Webhook: "https://example.com/getData?foo=bar"},
 			want: autogold.Want("basic_webhook", [2]interface{}{
@@ -30,7 +37,7 @@ func TestEncodeSeriesID(t *testing.T) {
 		},
 		{
 			input: &schema.InsightSeries{},
-			want:  autogold.Want("invalid", [2]interface{}{"", "invalid series &{Label: RepositoriesList:[] Search: Webhook:}"}),
+			want:  autogold.Want("invalid", [2]interface{}{"", "invalid series &{GeneratedFromCaptureGroups:false Label: RepositoriesList:[] Search: Webhook:}"}),
 		},
 	}
 	for _, tc := range testCases {
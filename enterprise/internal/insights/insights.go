@@ -10,11 +10,14 @@ import (
 	"github.com/cockroachdb/errors"
 
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/enterprise"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/background/queryrunner"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/resolvers"
 	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbconn"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
 	"github.com/sourcegraph/sourcegraph/internal/oobmigration"
+	"github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker"
 )
 
 // IsEnabled tells if code insights are enabled or not.
@@ -50,6 +53,9 @@ func Init(ctx context.Context, postgres dbutil.DB, outOfBandMigrationRunner *oob
 		return err
 	}
 	enterpriseServices.InsightsResolver = resolvers.New(timescale, postgres)
+
+	// Expose the failed query runner jobs to site admins.
+	dbworker.RegisterQueue("insights_query_runner", queryrunner.NewWorkerStore(basestore.NewWithDB(postgres, sql.TxOptions{})))
 	return nil
 }
 
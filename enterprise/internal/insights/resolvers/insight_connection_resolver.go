@@ -37,15 +37,37 @@ func (r *insightConnectionResolver) Nodes(ctx context.Context) ([]graphqlbackend
 	}
 	resolvers := make([]graphqlbackend.InsightResolver, 0, len(nodes))
 	for _, insight := range nodes {
+		captures, err := seriesCaptures(ctx, r.insightsStore, insight.Series)
+		if err != nil {
+			return nil, err
+		}
 		resolvers = append(resolvers, &insightResolver{
 			insightsStore:   r.insightsStore,
 			workerBaseStore: r.workerBaseStore,
 			insight:         insight,
+			captures:        captures,
 		})
 	}
 	return resolvers, nil
 }
 
+// seriesCaptures returns the capture group values recorded so far for every series generated from
+// capture groups, keyed by series ID.
+func seriesCaptures(ctx context.Context, insightsStore store.Interface, series []types.InsightViewSeries) (map[string][]string, error) {
+	captures := map[string][]string{}
+	for _, s := range series {
+		if !s.GeneratedFromCaptureGroups {
+			continue
+		}
+		values, err := insightsStore.SeriesCaptures(ctx, s.SeriesID)
+		if err != nil {
+			return nil, err
+		}
+		captures[s.SeriesID] = values
+	}
+	return captures, nil
+}
+
 func (r *insightConnectionResolver) TotalCount(ctx context.Context) (int32, error) {
 	results, _, err := r.compute(ctx)
 	return int32(len(results)), err
@@ -82,6 +104,10 @@ type insightResolver struct {
 	insightsStore   store.Interface
 	workerBaseStore *basestore.Store
 	insight         types.Insight
+
+	// captures are the capture group values of series generated from capture groups, keyed by
+	// series ID.
+	captures map[string][]string
 }
 
 func (r *insightResolver) ID() string {
@@ -96,6 +122,19 @@ func (r *insightResolver) Series() []graphqlbackend.InsightSeriesResolver {
 	series := r.insight.Series
 	resolvers := make([]graphqlbackend.InsightSeriesResolver, 0, len(series))
 	for _, series := range series {
+		if series.GeneratedFromCaptureGroups {
+			// Every capture group value is presented as its own series.
+			for _, capture := range r.captures[series.SeriesID] {
+				capture := capture
+				resolvers = append(resolvers, &insightSeriesResolver{
+					insightsStore:   r.insightsStore,
+					workerBaseStore: r.workerBaseStore,
+					series:          series,
+					capture:         &capture,
+				})
+			}
+			continue
+		}
 		resolvers = append(resolvers, &insightSeriesResolver{
 			insightsStore:   r.insightsStore,
 			workerBaseStore: r.workerBaseStore,
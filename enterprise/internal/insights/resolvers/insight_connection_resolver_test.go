@@ -293,3 +293,57 @@ func resolvePoints(ctx context.Context, conn graphqlbackend.InsightConnectionRes
 	}
 	return results
 }
+
+// TestResolver_InsightCaptureGroupSeries tests that series generated from capture groups are
+// presented as one series per capture group value.
+func TestResolver_InsightCaptureGroupSeries(t *testing.T) {
+	ctx := context.Background()
+
+	insightMetadataStore := store.NewMockInsightMetadataStore()
+	insightMetadataStore.GetMappedFunc.SetDefaultReturn([]types.Insight{
+		{
+			UniqueID: "unique1",
+			Series: []types.InsightViewSeries{
+				{UniqueID: "unique1", SeriesID: "s:1", Label: "total"},
+				{UniqueID: "unique1", SeriesID: "c:2", Label: "versions", GeneratedFromCaptureGroups: true},
+			},
+		},
+	}, nil)
+
+	insightsStore := store.NewMockInterface()
+	insightsStore.SeriesCapturesFunc.SetDefaultHook(func(ctx context.Context, seriesID string) ([]string, error) {
+		if seriesID != "c:2" {
+			t.Errorf("unexpected series %q", seriesID)
+		}
+		return []string{"1.2", "1.3"}, nil
+	})
+
+	conn := &insightConnectionResolver{
+		insightsStore:        insightsStore,
+		insightMetadataStore: insightMetadataStore,
+	}
+	nodes, err := conn.Nodes(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(nodes) != 1 {
+		t.Fatal("incorrect length")
+	}
+
+	var labels []string
+	for _, series := range nodes[0].Series() {
+		labels = append(labels, series.Label())
+		if _, err := series.Points(ctx, &graphqlbackend.InsightsPointsArgs{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	autogold.Want("series labels", []string{"total", "1.2", "1.3"}).Equal(t, labels)
+
+	var captures []string
+	for _, call := range insightsStore.SeriesPointsFunc.History() {
+		if call.Arg1.Capture != nil {
+			captures = append(captures, *call.Arg1.Capture)
+		}
+	}
+	autogold.Want("point captures", []string{"1.2", "1.3"}).Equal(t, captures)
+}
@@ -18,9 +18,18 @@ type insightSeriesResolver struct {
 	insightsStore   store.Interface
 	workerBaseStore *basestore.Store
 	series          types.InsightViewSeries
+
+	// capture is the capture group value this resolver represents, if the series is generated
+	// from capture groups.
+	capture *string
 }
 
-func (r *insightSeriesResolver) Label() string { return r.series.Label }
+func (r *insightSeriesResolver) Label() string {
+	if r.capture != nil {
+		return *r.capture
+	}
+	return r.series.Label
+}
 
 func (r *insightSeriesResolver) Points(ctx context.Context, args *graphqlbackend.InsightsPointsArgs) ([]graphqlbackend.InsightsDataPointResolver, error) {
 	var opts store.SeriesPointsOpts
@@ -28,6 +37,7 @@ func (r *insightSeriesResolver) Points(ctx context.Context, args *graphqlbackend
 	// Query data points only for the series we are representing.
 	seriesID := r.series.SeriesID
 	opts.SeriesID = &seriesID
+	opts.Capture = r.capture
 
 	if args.From == nil {
 		// Default to last 6mo of data.
@@ -100,6 +100,8 @@ type GetDataSeriesArgs struct {
 	// NextRecordingBefore will filter for results for which the next_recording_after field falls before the specified time.
 	NextRecordingBefore time.Time
 	Deleted             bool
+	// SeriesID will filter for the series with this unique series ID, if non-empty.
+	SeriesID string
 }
 
 func (s *InsightStore) GetDataSeries(ctx context.Context, args GetDataSeriesArgs) ([]types.InsightSeries, error) {
@@ -113,6 +115,9 @@ func (s *InsightStore) GetDataSeries(ctx context.Context, args GetDataSeriesArgs
 	} else {
 		preds = append(preds, sqlf.Sprintf("deleted_at IS NULL"))
 	}
+	if args.SeriesID != "" {
+		preds = append(preds, sqlf.Sprintf("series_id = %s", args.SeriesID))
+	}
 	if len(preds) == 0 {
 		preds = append(preds, sqlf.Sprintf("%s", "TRUE"))
 	}
@@ -139,6 +144,7 @@ func scanDataSeries(rows *sql.Rows, queryErr error) (_ []types.InsightSeries, er
 			&temp.LastRecordedAt,
 			&temp.NextRecordingAfter,
 			&temp.RecordingIntervalDays,
+			&temp.GeneratedFromCaptureGroups,
 		); err != nil {
 			return []types.InsightSeries{}, err
 		}
@@ -170,6 +176,7 @@ func scanInsightViewSeries(rows *sql.Rows, queryErr error) (_ []types.InsightVie
 			&temp.LastRecordedAt,
 			&temp.NextRecordingAfter,
 			&temp.RecordingIntervalDays,
+			&temp.GeneratedFromCaptureGroups,
 		); err != nil {
 			return []types.InsightViewSeries{}, err
 		}
@@ -228,6 +235,7 @@ func (s *InsightStore) CreateSeries(ctx context.Context, series types.InsightSer
 		series.LastRecordedAt,
 		series.NextRecordingAfter,
 		series.RecordingIntervalDays,
+		series.GeneratedFromCaptureGroups,
 	))
 	var id int
 	err := row.Scan(&id)
@@ -282,15 +290,15 @@ returning id;`
 const createInsightSeriesSql = `
 -- source: enterprise/internal/insights/store/insight_store.go:CreateSeries
 INSERT INTO insight_series (series_id, query, created_at, oldest_historical_at, last_recorded_at,
-                            next_recording_after, recording_interval_days)
-VALUES (%s, %s, %s, %s, %s, %s, %s)
+                            next_recording_after, recording_interval_days, generated_from_capture_groups)
+VALUES (%s, %s, %s, %s, %s, %s, %s, %s)
 RETURNING id;`
 
 const getInsightByViewSql = `
 -- source: enterprise/internal/insights/store/insight_store.go:Get
 SELECT iv.unique_id, iv.title, iv.description, ivs.label, ivs.stroke,
 i.series_id, i.query, i.created_at, i.oldest_historical_at, i.last_recorded_at,
-i.next_recording_after, i.recording_interval_days
+i.next_recording_after, i.recording_interval_days, i.generated_from_capture_groups
 FROM insight_view iv
          JOIN insight_view_series ivs ON iv.id = ivs.insight_view_id
          JOIN insight_series i ON ivs.insight_series_id = i.id
@@ -300,6 +308,6 @@ ORDER BY iv.unique_id, i.series_id
 
 const getInsightDataSeriesSql = `
 -- source: enterprise/internal/insights/store/insight_store.go:GetDataSeries
-select id, series_id, query, created_at, oldest_historical_at, last_recorded_at, next_recording_after, recording_interval_days from insight_series
+select id, series_id, query, created_at, oldest_historical_at, last_recorded_at, next_recording_after, recording_interval_days, generated_from_capture_groups from insight_series
 WHERE %s
 `
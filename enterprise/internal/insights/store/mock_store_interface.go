@@ -18,9 +18,15 @@ type MockInterface struct {
 	// RecordSeriesPointFunc is an instance of a mock function object
 	// controlling the behavior of the method RecordSeriesPoint.
 	RecordSeriesPointFunc *InterfaceRecordSeriesPointFunc
+	// SeriesCapturesFunc is an instance of a mock function object
+	// controlling the behavior of the method SeriesCaptures.
+	SeriesCapturesFunc *InterfaceSeriesCapturesFunc
 	// SeriesPointsFunc is an instance of a mock function object controlling
 	// the behavior of the method SeriesPoints.
 	SeriesPointsFunc *InterfaceSeriesPointsFunc
+	// SeriesRepoCapturesFunc is an instance of a mock function object
+	// controlling the behavior of the method SeriesRepoCaptures.
+	SeriesRepoCapturesFunc *InterfaceSeriesRepoCapturesFunc
 }
 
 // NewMockInterface creates a new mock of the Interface interface. All
@@ -37,11 +43,21 @@ func NewMockInterface() *MockInterface {
 				return nil
 			},
 		},
+		SeriesCapturesFunc: &InterfaceSeriesCapturesFunc{
+			defaultHook: func(context.Context, string) ([]string, error) {
+				return nil, nil
+			},
+		},
 		SeriesPointsFunc: &InterfaceSeriesPointsFunc{
 			defaultHook: func(context.Context, SeriesPointsOpts) ([]SeriesPoint, error) {
 				return nil, nil
 			},
 		},
+		SeriesRepoCapturesFunc: &InterfaceSeriesRepoCapturesFunc{
+			defaultHook: func(context.Context, string) ([]RepoCapture, error) {
+				return nil, nil
+			},
+		},
 	}
 }
 
@@ -55,9 +71,15 @@ func NewMockInterfaceFrom(i Interface) *MockInterface {
 		RecordSeriesPointFunc: &InterfaceRecordSeriesPointFunc{
 			defaultHook: i.RecordSeriesPoint,
 		},
+		SeriesCapturesFunc: &InterfaceSeriesCapturesFunc{
+			defaultHook: i.SeriesCaptures,
+		},
 		SeriesPointsFunc: &InterfaceSeriesPointsFunc{
 			defaultHook: i.SeriesPoints,
 		},
+		SeriesRepoCapturesFunc: &InterfaceSeriesRepoCapturesFunc{
+			defaultHook: i.SeriesRepoCaptures,
+		},
 	}
 }
 
@@ -275,6 +297,115 @@ func (c InterfaceRecordSeriesPointFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0}
 }
 
+// InterfaceSeriesCapturesFunc describes the behavior when the
+// SeriesCaptures method of the parent MockInterface instance is invoked.
+type InterfaceSeriesCapturesFunc struct {
+	defaultHook func(context.Context, string) ([]string, error)
+	hooks       []func(context.Context, string) ([]string, error)
+	history     []InterfaceSeriesCapturesFuncCall
+	mutex       sync.Mutex
+}
+
+// SeriesCaptures delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockInterface) SeriesCaptures(v0 context.Context, v1 string) ([]string, error) {
+	r0, r1 := m.SeriesCapturesFunc.nextHook()(v0, v1)
+	m.SeriesCapturesFunc.appendCall(InterfaceSeriesCapturesFuncCall{v0, v1, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the SeriesCaptures
+// method of the parent MockInterface instance is invoked and the hook queue
+// is empty.
+func (f *InterfaceSeriesCapturesFunc) SetDefaultHook(hook func(context.Context, string) ([]string, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// SeriesCaptures method of the parent MockInterface instance invokes the
+// hook at the front of the queue and discards it. After the queue is empty,
+// the default hook function is invoked for any future action.
+func (f *InterfaceSeriesCapturesFunc) PushHook(hook func(context.Context, string) ([]string, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *InterfaceSeriesCapturesFunc) SetDefaultReturn(r0 []string, r1 error) {
+	f.SetDefaultHook(func(context.Context, string) ([]string, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *InterfaceSeriesCapturesFunc) PushReturn(r0 []string, r1 error) {
+	f.PushHook(func(context.Context, string) ([]string, error) {
+		return r0, r1
+	})
+}
+
+func (f *InterfaceSeriesCapturesFunc) nextHook() func(context.Context, string) ([]string, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *InterfaceSeriesCapturesFunc) appendCall(r0 InterfaceSeriesCapturesFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of InterfaceSeriesCapturesFuncCall objects
+// describing the invocations of this function.
+func (f *InterfaceSeriesCapturesFunc) History() []InterfaceSeriesCapturesFuncCall {
+	f.mutex.Lock()
+	history := make([]InterfaceSeriesCapturesFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// InterfaceSeriesCapturesFuncCall is an object that describes an invocation
+// of method SeriesCaptures on an instance of MockInterface.
+type InterfaceSeriesCapturesFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 string
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []string
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c InterfaceSeriesCapturesFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c InterfaceSeriesCapturesFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // InterfaceSeriesPointsFunc describes the behavior when the SeriesPoints
 // method of the parent MockInterface instance is invoked.
 type InterfaceSeriesPointsFunc struct {
@@ -383,3 +514,113 @@ func (c InterfaceSeriesPointsFuncCall) Args() []interface{} {
 func (c InterfaceSeriesPointsFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
+
+// InterfaceSeriesRepoCapturesFunc describes the behavior when the
+// SeriesRepoCaptures method of the parent MockInterface instance is
+// invoked.
+type InterfaceSeriesRepoCapturesFunc struct {
+	defaultHook func(context.Context, string) ([]RepoCapture, error)
+	hooks       []func(context.Context, string) ([]RepoCapture, error)
+	history     []InterfaceSeriesRepoCapturesFuncCall
+	mutex       sync.Mutex
+}
+
+// SeriesRepoCaptures delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockInterface) SeriesRepoCaptures(v0 context.Context, v1 string) ([]RepoCapture, error) {
+	r0, r1 := m.SeriesRepoCapturesFunc.nextHook()(v0, v1)
+	m.SeriesRepoCapturesFunc.appendCall(InterfaceSeriesRepoCapturesFuncCall{v0, v1, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the SeriesRepoCaptures
+// method of the parent MockInterface instance is invoked and the hook queue
+// is empty.
+func (f *InterfaceSeriesRepoCapturesFunc) SetDefaultHook(hook func(context.Context, string) ([]RepoCapture, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// SeriesRepoCaptures method of the parent MockInterface instance invokes the
+// hook at the front of the queue and discards it. After the queue is empty,
+// the default hook function is invoked for any future action.
+func (f *InterfaceSeriesRepoCapturesFunc) PushHook(hook func(context.Context, string) ([]RepoCapture, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *InterfaceSeriesRepoCapturesFunc) SetDefaultReturn(r0 []RepoCapture, r1 error) {
+	f.SetDefaultHook(func(context.Context, string) ([]RepoCapture, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *InterfaceSeriesRepoCapturesFunc) PushReturn(r0 []RepoCapture, r1 error) {
+	f.PushHook(func(context.Context, string) ([]RepoCapture, error) {
+		return r0, r1
+	})
+}
+
+func (f *InterfaceSeriesRepoCapturesFunc) nextHook() func(context.Context, string) ([]RepoCapture, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *InterfaceSeriesRepoCapturesFunc) appendCall(r0 InterfaceSeriesRepoCapturesFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of InterfaceSeriesRepoCapturesFuncCall objects
+// describing the invocations of this function.
+func (f *InterfaceSeriesRepoCapturesFunc) History() []InterfaceSeriesRepoCapturesFuncCall {
+	f.mutex.Lock()
+	history := make([]InterfaceSeriesRepoCapturesFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// InterfaceSeriesRepoCapturesFuncCall is an object that describes an invocation
+// of method SeriesRepoCaptures on an instance of MockInterface.
+type InterfaceSeriesRepoCapturesFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 string
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []RepoCapture
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c InterfaceSeriesRepoCapturesFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c InterfaceSeriesRepoCapturesFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
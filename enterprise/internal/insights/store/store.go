@@ -23,6 +23,8 @@ type Interface interface {
 	SeriesPoints(ctx context.Context, opts SeriesPointsOpts) ([]SeriesPoint, error)
 	RecordSeriesPoint(ctx context.Context, v RecordSeriesPointArgs) error
 	CountData(ctx context.Context, opts CountDataOpts) (int, error)
+	SeriesCaptures(ctx context.Context, seriesID string) ([]string, error)
+	SeriesRepoCaptures(ctx context.Context, seriesID string) ([]RepoCapture, error)
 }
 
 var _ Interface = &Store{}
@@ -72,9 +74,15 @@ type SeriesPoint struct {
 	Time     time.Time
 	Value    float64
 	Metadata []byte
+	// Capture is the value of the capture group this point was recorded for, if the series is
+	// generated from capture groups.
+	Capture *string
 }
 
 func (s *SeriesPoint) String() string {
+	if s.Capture != nil {
+		return fmt.Sprintf("SeriesPoint{Time: %q, Value: %v, Metadata: %s, Capture: %q}", s.Time, s.Value, s.Metadata, *s.Capture)
+	}
 	return fmt.Sprintf("SeriesPoint{Time: %q, Value: %v, Metadata: %s}", s.Time, s.Value, s.Metadata)
 }
 
@@ -86,6 +94,10 @@ type SeriesPointsOpts struct {
 	// RepoID, if non-nil, indicates to filter results to only points recorded with this repo ID.
 	RepoID *api.RepoID
 
+	// Capture, if non-nil, indicates to filter results to only points recorded for this capture
+	// group value.
+	Capture *string
+
 	Excluded []api.RepoID
 	Included []api.RepoID
 
@@ -131,6 +143,7 @@ func (s *Store) SeriesPoints(ctx context.Context, opts SeriesPointsOpts) ([]Seri
 			&point.Time,
 			&point.Value,
 			&point.Metadata,
+			&point.Capture,
 		)
 		if err != nil {
 			return err
@@ -143,14 +156,14 @@ func (s *Store) SeriesPoints(ctx context.Context, opts SeriesPointsOpts) ([]Seri
 
 // This query is a barebones implementation of per-repo per-series last-observation carried forward. Long term
 // this query is too expensive to run in real-time and should be moved to a materialized view.
-const lastObservationCarriedPointsSql = `select sub.series_id, sub.interval_time, sum(value) as value, null as metadata from (WITH target_times AS (SELECT *
+const lastObservationCarriedPointsSql = `select sub.series_id, sub.interval_time, sum(value) as value, null as metadata, %s as capture from (WITH target_times AS (SELECT *
 FROM GENERATE_SERIES(CURRENT_TIMESTAMP::date - INTERVAL '26 weeks', CURRENT_TIMESTAMP::date, '2 weeks') as interval_time)
-SELECT sub.series_id, sub.repo_id, sub.value, interval_time, repo_name_id
-FROM (select distinct repo_id, series_id from series_points) as r
+SELECT sub.series_id, sub.repo_id, sub.value, interval_time, repo_name_id, sub.capture
+FROM (select distinct repo_id, series_id, capture from series_points) as r
 cross join target_times tt
 join LATERAL (
     select sp.* from series_points as sp
-    where sp.repo_id = r.repo_id and sp.time <= tt.interval_time and sp.series_id = r.series_id
+    where sp.repo_id = r.repo_id and sp.time <= tt.interval_time and sp.series_id = r.series_id and sp.capture IS NOT DISTINCT FROM r.capture
     order by time DESC
     limit 1
     ) sub on sub.repo_id = r.repo_id and r.series_id = sub.series_id
@@ -189,6 +202,13 @@ func seriesPointsQuery(opts SeriesPointsOpts) *sqlf.Query {
 	if opts.RepoID != nil {
 		preds = append(preds, sqlf.Sprintf("repo_id = %d", int32(*opts.RepoID)))
 	}
+	// When filtering by capture all points share the same capture value, otherwise the values of
+	// all captures are summed up and the capture is omitted.
+	capture := sqlf.Sprintf("null")
+	if opts.Capture != nil {
+		preds = append(preds, sqlf.Sprintf("capture = %s", *opts.Capture))
+		capture = sqlf.Sprintf("%s::text", *opts.Capture)
+	}
 	if opts.From != nil {
 		preds = append(preds, sqlf.Sprintf("interval_time >= %s", *opts.From))
 	}
@@ -219,6 +239,7 @@ func seriesPointsQuery(opts SeriesPointsOpts) *sqlf.Query {
 	}
 	return sqlf.Sprintf(
 		lastObservationCarriedPointsSql+limitClause,
+		capture,
 		sqlf.Join(preds, "\n AND "),
 	)
 }
@@ -312,6 +333,11 @@ type RecordSeriesPointArgs struct {
 	// See the DB schema comments for intended use cases. This should generally be small,
 	// low-cardinality data to avoid inflating the table.
 	Metadata interface{}
+
+	// Capture is the value of the capture group this data point is recorded for, if the series
+	// is generated from capture groups. New capture values are recorded so that they can be
+	// listed with SeriesCaptures.
+	Capture *string
 }
 
 // RecordSeriesPoint records a data point for the specfied series ID (which is a unique ID for the
@@ -361,6 +387,12 @@ func (s *Store) RecordSeriesPoint(ctx context.Context, v RecordSeriesPointArgs)
 		metadataID = &metadataIDValue
 	}
 
+	if v.Capture != nil {
+		if err := txStore.Exec(ctx, sqlf.Sprintf(upsertSeriesCaptureFmtStr, v.SeriesID, *v.Capture, v.Point.Time.UTC())); err != nil {
+			return errors.Wrap(err, "upserting series capture")
+		}
+	}
+
 	// Insert the actual data point.
 	return txStore.Exec(ctx, sqlf.Sprintf(
 		recordSeriesPointFmtstr,
@@ -371,9 +403,71 @@ func (s *Store) RecordSeriesPoint(ctx context.Context, v RecordSeriesPointArgs)
 		v.RepoID,           // repo_id
 		repoNameID,         // repo_name_id
 		repoNameID,         // original_repo_name_id
+		v.Capture,          // capture
 	))
 }
 
+// SeriesCaptures returns the capture group values that have been recorded for the specified
+// series ID, in the order they were first seen.
+func (s *Store) SeriesCaptures(ctx context.Context, seriesID string) ([]string, error) {
+	return basestore.ScanStrings(s.Store.Query(ctx, sqlf.Sprintf(seriesCapturesFmtStr, seriesID)))
+}
+
+const seriesCapturesFmtStr = `
+-- source: enterprise/internal/insights/store/store.go:SeriesCaptures
+SELECT capture FROM insight_series_captures
+WHERE series_id = %s
+ORDER BY first_seen_at, capture
+`
+
+// RepoCapture is a capture group value recorded for a repository.
+type RepoCapture struct {
+	RepoID   api.RepoID
+	RepoName string
+	Capture  string
+}
+
+// SeriesRepoCaptures returns the (repository, capture group value) pairs of the specified series ID
+// whose most recently recorded data point is non-zero, ordered by repository ID and value.
+func (s *Store) SeriesRepoCaptures(ctx context.Context, seriesID string) (_ []RepoCapture, err error) {
+	rows, err := s.Store.Query(ctx, sqlf.Sprintf(seriesRepoCapturesFmtStr, seriesID))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { err = basestore.CloseRows(rows, err) }()
+
+	var captures []RepoCapture
+	for rows.Next() {
+		var c RepoCapture
+		if err := rows.Scan(&c.RepoID, &c.RepoName, &c.Capture); err != nil {
+			return nil, err
+		}
+		captures = append(captures, c)
+	}
+	return captures, nil
+}
+
+const seriesRepoCapturesFmtStr = `
+-- source: enterprise/internal/insights/store/store.go:SeriesRepoCaptures
+SELECT latest.repo_id, rn.name, latest.capture
+FROM (
+	SELECT DISTINCT ON (repo_id, capture) repo_id, repo_name_id, capture, value
+	FROM series_points
+	WHERE series_id = %s AND repo_id IS NOT NULL AND capture IS NOT NULL
+	ORDER BY repo_id, capture, time DESC
+) latest
+JOIN repo_names rn ON rn.id = latest.repo_name_id
+WHERE latest.value <> 0
+ORDER BY latest.repo_id, latest.capture
+`
+
+const upsertSeriesCaptureFmtStr = `
+-- source: enterprise/internal/insights/store/store.go:RecordSeriesPoint
+INSERT INTO insight_series_captures(series_id, capture, first_seen_at)
+VALUES (%s, %s, %s)
+ON CONFLICT (series_id, capture) DO UPDATE SET first_seen_at = LEAST(insight_series_captures.first_seen_at, EXCLUDED.first_seen_at);
+`
+
 const upsertRepoNameFmtStr = `
 -- source: enterprise/internal/insights/store/store.go:RecordSeriesPoint
 WITH e AS(
@@ -409,8 +503,9 @@ INSERT INTO series_points(
 	metadata_id,
 	repo_id,
 	repo_name_id,
-	original_repo_name_id)
-VALUES (%s, %s, %s, %s, %s, %s, %s);
+	original_repo_name_id,
+	capture)
+VALUES (%s, %s, %s, %s, %s, %s, %s, %s);
 `
 
 func (s *Store) query(ctx context.Context, q *sqlf.Query, sc scanFunc) error {
@@ -330,6 +330,102 @@ func TestRecordSeriesPoints(t *testing.T) {
 	// autogold.Want("forOriginalRepoNamePoints[0].String()", nil).Equal(t, forOriginalRepoNamePoints[0].String())
 }
 
+func TestRecordSeriesPointsCaptures(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx := context.Background()
+	clock := timeutil.Now
+	timescale, cleanup := insightsdbtesting.TimescaleDB(t)
+	defer cleanup()
+	postgres := dbtest.NewDB(t, "")
+	permStore := NewInsightPermissionStore(postgres)
+	store := NewWithClock(timescale, permStore, clock)
+
+	optionalString := func(v string) *string { return &v }
+	optionalRepoID := func(v api.RepoID) *api.RepoID { return &v }
+
+	current := time.Now().Truncate(24 * time.Hour)
+
+	// Repo 3 moves from 1.2 to 1.3, repo 4 stays on 1.2.
+	for _, record := range []RecordSeriesPointArgs{
+		{
+			SeriesID: "versions",
+			Point:    SeriesPoint{Time: current.Add(-time.Hour * 24 * 15), Value: 2},
+			RepoName: optionalString("repo1"),
+			RepoID:   optionalRepoID(3),
+			Capture:  optionalString("1.2"),
+		},
+		{
+			SeriesID: "versions",
+			Point:    SeriesPoint{Time: current.Add(-time.Hour * 24 * 15), Value: 1},
+			RepoName: optionalString("repo2"),
+			RepoID:   optionalRepoID(4),
+			Capture:  optionalString("1.2"),
+		},
+		{
+			SeriesID: "versions",
+			Point:    SeriesPoint{Time: current, Value: 0},
+			RepoName: optionalString("repo1"),
+			RepoID:   optionalRepoID(3),
+			Capture:  optionalString("1.2"),
+		},
+		{
+			SeriesID: "versions",
+			Point:    SeriesPoint{Time: current, Value: 3},
+			RepoName: optionalString("repo1"),
+			RepoID:   optionalRepoID(3),
+			Capture:  optionalString("1.3"),
+		},
+	} {
+		if err := store.RecordSeriesPoint(ctx, record); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	captures, err := store.SeriesCaptures(ctx, "versions")
+	if err != nil {
+		t.Fatal(err)
+	}
+	autogold.Want("captures", []string{"1.2", "1.3"}).Equal(t, captures)
+
+	// Repo 3 no longer matches 1.2, so only the values still counted are returned.
+	repoCaptures, err := store.SeriesRepoCaptures(ctx, "versions")
+	if err != nil {
+		t.Fatal(err)
+	}
+	autogold.Want("repo captures", []RepoCapture{
+		{RepoID: 3, RepoName: "repo1", Capture: "1.3"},
+		{RepoID: 4, RepoName: "repo2", Capture: "1.2"},
+	}).Equal(t, repoCaptures)
+
+	for _, tc := range []struct {
+		capture string
+		want    autogold.Value
+	}{
+		{"1.2", autogold.Want("1.2 points", []float64{1, 3})},
+		{"1.3", autogold.Want("1.3 points", []float64{3})},
+	} {
+		points, err := store.SeriesPoints(ctx, SeriesPointsOpts{
+			SeriesID: optionalString("versions"),
+			Capture:  optionalString(tc.capture),
+			Limit:    2,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var values []float64
+		for _, point := range points {
+			if point.Capture == nil || *point.Capture != tc.capture {
+				t.Errorf("unexpected capture for point %s", point.String())
+			}
+			values = append(values, point.Value)
+		}
+		tc.want.Equal(t, values)
+	}
+}
+
 func TestValues(t *testing.T) {
 	ids := []api.RepoID{1, 2, 3, 4, 5, 6}
 	got := values(ids)
@@ -18,6 +18,9 @@ type InsightViewSeries struct {
 	RecordingIntervalDays int
 	Label                 string
 	Stroke                string
+	// GeneratedFromCaptureGroups is true if the values of the first capture group of the query
+	// are recorded as separate series.
+	GeneratedFromCaptureGroups bool
 }
 
 type Insight struct {
@@ -52,4 +55,7 @@ type InsightSeries struct {
 	LastRecordedAt        time.Time
 	NextRecordingAfter    time.Time
 	RecordingIntervalDays int
+	// GeneratedFromCaptureGroups is true if the values of the first capture group of the query
+	// are recorded as separate series.
+	GeneratedFromCaptureGroups bool
 }
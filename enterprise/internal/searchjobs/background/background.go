@@ -0,0 +1,25 @@
+package background
+
+import (
+	"context"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/searchjobs"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+)
+
+// NewBackgroundJobs returns the routines which process search jobs. At most
+// numRepoHandlers repositories are searched concurrently.
+func NewBackgroundJobs(ctx context.Context, db dbutil.DB, numRepoHandlers int) []goroutine.BackgroundRoutine {
+	store := searchjobs.NewStore(db)
+
+	jobMetrics := newMetrics("search_jobs")
+	repoMetrics := newMetrics("search_job_repos")
+
+	return []goroutine.BackgroundRoutine{
+		newSearchJobWorker(ctx, db, store, jobMetrics),
+		newSearchJobResetter(store, jobMetrics),
+		newSearchJobRepoWorker(ctx, db, store, numRepoHandlers, repoMetrics),
+		newSearchJobRepoResetter(store, repoMetrics),
+	}
+}
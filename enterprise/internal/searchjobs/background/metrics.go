@@ -0,0 +1,53 @@
+package background
+
+import (
+	"github.com/inconshreveable/log15"
+	"github.com/opentracing/opentracing-go"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/trace"
+	"github.com/sourcegraph/sourcegraph/internal/workerutil"
+)
+
+type searchJobsMetrics struct {
+	workerMetrics workerutil.WorkerMetrics
+	resets        prometheus.Counter
+	resetFailures prometheus.Counter
+	errors        prometheus.Counter
+}
+
+// newMetrics returns the metrics of a worker processing search job records.
+// The name is one of "search_jobs" and "search_job_repos".
+func newMetrics(name string) searchJobsMetrics {
+	observationContext := &observation.Context{
+		Logger:     log15.Root(),
+		Tracer:     &trace.Tracer{Tracer: opentracing.GlobalTracer()},
+		Registerer: prometheus.DefaultRegisterer,
+	}
+
+	resetFailures := prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "src_" + name + "_reset_failures_total",
+		Help: "The number of reset failures.",
+	})
+	observationContext.Registerer.MustRegister(resetFailures)
+
+	resets := prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "src_" + name + "_resets_total",
+		Help: "The number of records reset.",
+	})
+	observationContext.Registerer.MustRegister(resets)
+
+	errors := prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "src_" + name + "_errors_total",
+		Help: "The number of errors that occur during job.",
+	})
+	observationContext.Registerer.MustRegister(errors)
+
+	return searchJobsMetrics{
+		workerMetrics: workerutil.NewMetrics(observationContext, name, nil),
+		resets:        resets,
+		resetFailures: resetFailures,
+		errors:        errors,
+	}
+}
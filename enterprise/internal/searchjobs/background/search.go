@@ -0,0 +1,86 @@
+package background
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/cockroachdb/errors"
+	"golang.org/x/net/context/ctxhttp"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	streamhttp "github.com/sourcegraph/sourcegraph/internal/search/streaming/http"
+)
+
+// searcher runs a search on behalf of the user with the given ID and calls
+// emit with every result.
+type searcher interface {
+	Search(ctx context.Context, userID int32, query, patternType string, emit func(streamhttp.ExportRow) error) error
+}
+
+// exportSearcher runs searches through the search export endpoint of the
+// internal frontend API.
+type exportSearcher struct {
+	baseURL string
+	client  *http.Client
+}
+
+func newExportSearcher() *exportSearcher {
+	return &exportSearcher{baseURL: api.InternalClient.URL}
+}
+
+func (s *exportSearcher) Search(ctx context.Context, userID int32, query, patternType string, emit func(streamhttp.ExportRow) error) error {
+	u, err := url.Parse(s.baseURL)
+	if err != nil {
+		return errors.Wrap(err, "constructing frontend URL")
+	}
+	u.Path = "/.internal/search/export"
+	u.RawQuery = url.Values{
+		"q":      []string{query},
+		"t":      []string{patternType},
+		"format": []string{string(streamhttp.ExportFormatJSONLines)},
+		// 🚨 SECURITY: The search only returns results from repositories
+		// this user has access to.
+		"actor": []string{strconv.FormatInt(int64(userID), 10)},
+	}.Encode()
+
+	resp, err := ctxhttp.Get(ctx, s.client, u.String())
+	if err != nil {
+		return errors.Wrap(err, "Get")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return errors.Errorf("search export: unexpected status %d: %s", resp.StatusCode, body)
+	}
+
+	const maxRowSize = 10 * 1024 * 1024 // 10mb
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 4096), maxRowSize)
+	for scanner.Scan() {
+		var row streamhttp.ExportRow
+		if err := json.Unmarshal(scanner.Bytes(), &row); err != nil {
+			return errors.Wrap(err, "decoding search result")
+		}
+		// The sequence number is assigned when the results of the search
+		// job are downloaded.
+		row.Seq = 0
+		if err := emit(row); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return errors.Wrap(err, "reading search results")
+	}
+
+	// The trailer is only available once the body has been read.
+	if msg := resp.Trailer.Get(streamhttp.ExportErrorTrailer); msg != "" {
+		return errors.Errorf("search failed: %s", msg)
+	}
+	return nil
+}
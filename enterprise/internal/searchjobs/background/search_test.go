@@ -0,0 +1,91 @@
+package background
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cockroachdb/errors"
+	"github.com/google/go-cmp/cmp"
+
+	streamhttp "github.com/sourcegraph/sourcegraph/internal/search/streaming/http"
+)
+
+func TestExportSearcher(t *testing.T) {
+	var gotQuery, gotPatternType, gotActor string
+	var failWith string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/.internal/search/export" {
+			http.Error(w, "not found", http.StatusNotFound)
+			return
+		}
+		gotQuery = r.URL.Query().Get("q")
+		gotPatternType = r.URL.Query().Get("t")
+		gotActor = r.URL.Query().Get("actor")
+
+		format, err := streamhttp.ParseExportFormat(r.URL.Query().Get("format"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		ew, err := streamhttp.NewExportWriter(w, format)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, row := range []streamhttp.ExportRow{
+			{Type: streamhttp.ContentMatchType, Repository: "github.com/acme/a", Path: "main.go", LineNumber: 3, Line: "secret := 1"},
+			{Type: streamhttp.PathMatchType, Repository: "github.com/acme/a", Path: "secret.txt"},
+		} {
+			row.Seq = i + 1
+			if err := ew.Write(row); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := ew.Flush(); err != nil {
+			t.Fatal(err)
+		}
+		if failWith != "" {
+			ew.Error(errors.New(failWith))
+		}
+	}))
+	defer ts.Close()
+
+	s := &exportSearcher{baseURL: ts.URL}
+
+	t.Run("success", func(t *testing.T) {
+		var rows []streamhttp.ExportRow
+		err := s.Search(context.Background(), 42, `repo:^github\.com/acme/a$ secret count:all`, "literal", func(row streamhttp.ExportRow) error {
+			rows = append(rows, row)
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := `repo:^github\.com/acme/a$ secret count:all`; gotQuery != want {
+			t.Errorf("got query %q, want %q", gotQuery, want)
+		}
+		if gotPatternType != "literal" {
+			t.Errorf("got pattern type %q, want literal", gotPatternType)
+		}
+		if gotActor != "42" {
+			t.Errorf("got actor %q, want 42", gotActor)
+		}
+		want := []streamhttp.ExportRow{
+			{Type: streamhttp.ContentMatchType, Repository: "github.com/acme/a", Path: "main.go", LineNumber: 3, Line: "secret := 1"},
+			{Type: streamhttp.PathMatchType, Repository: "github.com/acme/a", Path: "secret.txt"},
+		}
+		if diff := cmp.Diff(want, rows); diff != "" {
+			t.Errorf("unexpected rows (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("search error", func(t *testing.T) {
+		failWith = "repository is being cloned"
+		defer func() { failWith = "" }()
+
+		err := s.Search(context.Background(), 42, "secret", "literal", func(row streamhttp.ExportRow) error { return nil })
+		if err == nil || err.Error() != "search failed: repository is being cloned" {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+}
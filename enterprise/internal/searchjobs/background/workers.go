@@ -0,0 +1,153 @@
+package background
+
+import (
+	"context"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/searchjobs"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	streamhttp "github.com/sourcegraph/sourcegraph/internal/search/streaming/http"
+	"github.com/sourcegraph/sourcegraph/internal/workerutil"
+	"github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker"
+	dbworkerstore "github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store"
+)
+
+func newSearchJobWorker(ctx context.Context, db dbutil.DB, s *searchjobs.Store, metrics searchJobsMetrics) *workerutil.Worker {
+	options := workerutil.WorkerOptions{
+		Name:              "search_jobs_worker",
+		NumHandlers:       1,
+		Interval:          5 * time.Second,
+		HeartbeatInterval: 15 * time.Second,
+		Metrics:           metrics.workerMetrics,
+	}
+	return dbworker.NewWorker(ctx, createDBWorkerStoreForSearchJobs(s), &searchJobHandler{db: db, store: s}, options)
+}
+
+func newSearchJobResetter(s *searchjobs.Store, metrics searchJobsMetrics) *dbworker.Resetter {
+	options := dbworker.ResetterOptions{
+		Name:     "search_jobs_worker_resetter",
+		Interval: 1 * time.Minute,
+		Metrics: dbworker.ResetterMetrics{
+			Errors:              metrics.errors,
+			RecordResetFailures: metrics.resetFailures,
+			RecordResets:        metrics.resets,
+		},
+	}
+	return dbworker.NewResetter(createDBWorkerStoreForSearchJobs(s), options)
+}
+
+func newSearchJobRepoWorker(ctx context.Context, db dbutil.DB, s *searchjobs.Store, numHandlers int, metrics searchJobsMetrics) *workerutil.Worker {
+	options := workerutil.WorkerOptions{
+		Name:              "search_job_repos_worker",
+		NumHandlers:       numHandlers,
+		Interval:          1 * time.Second,
+		HeartbeatInterval: 15 * time.Second,
+		Metrics:           metrics.workerMetrics,
+	}
+	return dbworker.NewWorker(ctx, createDBWorkerStoreForSearchJobRepos(s), &searchJobRepoHandler{db: db, store: s, searcher: newExportSearcher()}, options)
+}
+
+func newSearchJobRepoResetter(s *searchjobs.Store, metrics searchJobsMetrics) *dbworker.Resetter {
+	options := dbworker.ResetterOptions{
+		Name:     "search_job_repos_worker_resetter",
+		Interval: 1 * time.Minute,
+		Metrics: dbworker.ResetterMetrics{
+			Errors:              metrics.errors,
+			RecordResetFailures: metrics.resetFailures,
+			RecordResets:        metrics.resets,
+		},
+	}
+	return dbworker.NewResetter(createDBWorkerStoreForSearchJobRepos(s), options)
+}
+
+func createDBWorkerStoreForSearchJobs(s *searchjobs.Store) dbworkerstore.Store {
+	return dbworkerstore.New(s.Handle(), dbworkerstore.Options{
+		Name:              "search_jobs_worker_store",
+		TableName:         "search_jobs",
+		ColumnExpressions: searchjobs.SearchJobColumns,
+		Scan:              searchjobs.ScanSearchJob,
+		StalledMaxAge:     60 * time.Second,
+		RetryAfter:        10 * time.Second,
+		MaxNumRetries:     3,
+		OrderByExpression: sqlf.Sprintf("id"),
+	})
+}
+
+func createDBWorkerStoreForSearchJobRepos(s *searchjobs.Store) dbworkerstore.Store {
+	return dbworkerstore.New(s.Handle(), dbworkerstore.Options{
+		Name:              "search_job_repos_worker_store",
+		TableName:         "search_job_repos",
+		ColumnExpressions: searchjobs.SearchJobRepoColumns,
+		Scan:              searchjobs.ScanSearchJobRepo,
+		StalledMaxAge:     5 * time.Minute,
+		RetryAfter:        1 * time.Minute,
+		MaxNumRetries:     3,
+		OrderByExpression: sqlf.Sprintf("id"),
+		// Search the repositories of concurrent search jobs in turns, so that
+		// a search job over all repositories doesn't hold up the others.
+		FairnessKeyExpression: sqlf.Sprintf("search_job_id"),
+	})
+}
+
+// searchJobHandler determines the repositories a search job needs to search
+// and queues their search.
+type searchJobHandler struct {
+	db    dbutil.DB
+	store *searchjobs.Store
+}
+
+func (h *searchJobHandler) Handle(ctx context.Context, record workerutil.Record) error {
+	job := record.(*searchjobs.SearchJob)
+	if job.CanceledAt != nil {
+		return nil
+	}
+
+	// 🚨 SECURITY: Only search the repositories the initiator of the search job
+	// has access to.
+	repoIDs, err := searchjobs.ResolveRepositories(actor.WithActor(ctx, actor.FromUser(job.InitiatorID)), h.db, job)
+	if err != nil {
+		return err
+	}
+	return h.store.CreateSearchJobRepos(ctx, job.ID, repoIDs)
+}
+
+// searchJobRepoHandler searches a single repository of a search job and
+// stores the results.
+type searchJobRepoHandler struct {
+	db       dbutil.DB
+	store    *searchjobs.Store
+	searcher searcher
+}
+
+func (h *searchJobRepoHandler) Handle(ctx context.Context, record workerutil.Record) error {
+	repo := record.(*searchjobs.SearchJobRepo)
+
+	job, err := h.store.GetSearchJob(ctx, repo.SearchJobID)
+	if err != nil {
+		return err
+	}
+	if job.CanceledAt != nil {
+		// The search job was canceled after this repository was dequeued.
+		return h.store.MarkSearchJobRepoCanceled(ctx, repo.ID)
+	}
+
+	// 🚨 SECURITY: The initiator may have lost access to the repository since
+	// the repositories of the search job were resolved. We don't search it in
+	// that case, and remove the results of previous attempts.
+	_, err = database.Repos(h.db).Get(actor.WithActor(ctx, actor.FromUser(job.InitiatorID)), repo.RepoID)
+	if errcode.IsNotFound(err) {
+		return h.store.ReplaceSearchJobRepoResults(ctx, repo, func(func(streamhttp.ExportRow) error) error { return nil })
+	} else if err != nil {
+		return err
+	}
+
+	q := searchjobs.RepoQuery(job.Query, repo.RepoName)
+	return h.store.ReplaceSearchJobRepoResults(ctx, repo, func(emit func(streamhttp.ExportRow) error) error {
+		return h.searcher.Search(ctx, job.InitiatorID, q, job.PatternType, emit)
+	})
+}
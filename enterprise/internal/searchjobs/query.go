@@ -0,0 +1,134 @@
+package searchjobs
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/query"
+)
+
+const maxQueryLength = 4096
+
+// searchType returns the query.SearchType of a pattern type, as accepted by
+// the GraphQL API.
+func searchType(patternType string) (query.SearchType, error) {
+	switch patternType {
+	case "literal":
+		return query.SearchTypeLiteral, nil
+	case "regexp":
+		return query.SearchTypeRegex, nil
+	case "structural":
+		return query.SearchTypeStructural, nil
+	default:
+		return -1, errors.Errorf("unrecognized pattern type %q", patternType)
+	}
+}
+
+// parseQuery parses and validates the query of a search job.
+func parseQuery(q, patternType string) (query.Basic, error) {
+	if len(q) > maxQueryLength {
+		return query.Basic{}, errors.Errorf("search job query exceeds maximum allowed length (%d)", maxQueryLength)
+	}
+
+	st, err := searchType(patternType)
+	if err != nil {
+		return query.Basic{}, err
+	}
+	plan, err := query.Pipeline(query.Init(q, st))
+	if err != nil {
+		return query.Basic{}, errors.Wrap(err, "invalid search job query")
+	}
+	if len(plan) != 1 {
+		return query.Basic{}, errors.New("search job query must not contain 'or' operators")
+	}
+	basic := plan[0]
+
+	for _, field := range []string{query.FieldCount, query.FieldTimeout} {
+		if basic.FindValue(field) != "" {
+			return query.Basic{}, errors.Errorf("search job query must not contain the %s: filter, search jobs always search exhaustively", field)
+		}
+	}
+	return basic, nil
+}
+
+// ValidateQuery returns an error if q can not be searched by a search job.
+func ValidateQuery(q, patternType string) error {
+	_, err := parseQuery(q, patternType)
+	return err
+}
+
+// repoListOptions returns the options to list the repositories that can
+// match basic. Filters which can't be evaluated against the database, such
+// as lang: or context:, are applied when each repository is searched.
+func repoListOptions(basic query.Basic) database.ReposListOptions {
+	var includePatterns, excludePatterns []string
+	fork, archived, visibility := query.No, query.No, query.Any
+	for _, p := range basic.Parameters {
+		switch p.Field {
+		case query.FieldRepo:
+			repo, _ := search.ParseRepositoryRevisions(p.Value)
+			if p.Negated {
+				excludePatterns = append(excludePatterns, repo)
+			} else {
+				includePatterns = append(includePatterns, repo)
+			}
+		case query.FieldFork:
+			fork = query.ParseYesNoOnly(p.Value)
+		case query.FieldArchived:
+			archived = query.ParseYesNoOnly(p.Value)
+		case query.FieldVisibility:
+			visibility = query.ParseVisibility(p.Value)
+		}
+	}
+
+	opts := database.ReposListOptions{
+		IncludePatterns: includePatterns,
+		NoForks:         fork == query.No,
+		OnlyForks:       fork == query.Only,
+		NoArchived:      archived == query.No,
+		OnlyArchived:    archived == query.Only,
+		NoPrivate:       visibility == query.Public,
+		OnlyPrivate:     visibility == query.Private,
+	}
+	if len(excludePatterns) > 0 {
+		opts.ExcludePattern = "(?:" + strings.Join(excludePatterns, ")|(?:") + ")"
+	}
+	return opts
+}
+
+// ResolveRepositories returns the IDs of the repositories that a search job
+// needs to search.
+//
+// 🚨 SECURITY: The repositories are filtered by the permissions of the actor
+// in ctx, which must be the initiator of the search job.
+func ResolveRepositories(ctx context.Context, db dbutil.DB, job *SearchJob) ([]api.RepoID, error) {
+	basic, err := parseQuery(job.Query, job.PatternType)
+	if err != nil {
+		return nil, err
+	}
+
+	repos, err := database.Repos(db).ListRepoNames(ctx, repoListOptions(basic))
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]api.RepoID, 0, len(repos))
+	for _, repo := range repos {
+		ids = append(ids, repo.ID)
+	}
+	return ids, nil
+}
+
+// RepoQuery returns the query that searches repo for the query of a search
+// job. count:all lifts the result limit and the timeout to the maximum
+// allowed by the site configuration.
+func RepoQuery(q string, repo api.RepoName) string {
+	return fmt.Sprintf("repo:^%s$ %s count:all", regexp.QuoteMeta(string(repo)), q)
+}
@@ -0,0 +1,67 @@
+package searchjobs
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestValidateQuery(t *testing.T) {
+	tests := []struct {
+		query       string
+		patternType string
+		wantErr     string
+	}{
+		{query: `repo:^github\.com/acme/ secret`, patternType: "literal"},
+		{query: `lang:go fmt\.Sprintf\(`, patternType: "regexp"},
+		{query: `foo(:[args])`, patternType: "structural"},
+		{query: `foo`, patternType: "fuzzy", wantErr: `unrecognized pattern type "fuzzy"`},
+		{query: `foo or bar`, patternType: "literal", wantErr: "search job query must not contain 'or' operators"},
+		{query: `foo count:100`, patternType: "literal", wantErr: "search job query must not contain the count: filter, search jobs always search exhaustively"},
+		{query: `foo timeout:1m`, patternType: "literal", wantErr: "search job query must not contain the timeout: filter, search jobs always search exhaustively"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			err := ValidateQuery(tt.query, tt.patternType)
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if tt.wantErr != "" && (err == nil || err.Error() != tt.wantErr) {
+				t.Fatalf("got error %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRepoListOptions(t *testing.T) {
+	basic, err := parseQuery(`repo:^github\.com/acme/ repo:foo@main -repo:archived -repo:old fork:yes visibility:private lang:go secret`, "literal")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	opts := repoListOptions(basic)
+	if diff := cmp.Diff([]string{`^github\.com/acme/`, "foo"}, opts.IncludePatterns); diff != "" {
+		t.Errorf("unexpected include patterns (-want +got):\n%s", diff)
+	}
+	if want := "(?:archived)|(?:old)"; opts.ExcludePattern != want {
+		t.Errorf("got exclude pattern %q, want %q", opts.ExcludePattern, want)
+	}
+	if opts.NoForks || opts.OnlyForks {
+		t.Error("expected forks to be included")
+	}
+	if !opts.NoArchived {
+		t.Error("expected archived repositories to be excluded by default")
+	}
+	if !opts.OnlyPrivate {
+		t.Error("expected only private repositories")
+	}
+}
+
+func TestRepoQuery(t *testing.T) {
+	got := RepoQuery(`lang:go secret`, "github.com/acme/a.b")
+	want := `repo:^github\.com/acme/a\.b$ lang:go secret count:all`
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,322 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/cockroachdb/errors"
+	"github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/searchjobs"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+)
+
+const searchJobKind = "SearchJob"
+
+// NewResolver returns a new Resolver that uses the given database.
+func NewResolver(db dbutil.DB) graphqlbackend.SearchJobsResolver {
+	return &Resolver{db: db, store: searchjobs.NewStore(db)}
+}
+
+type Resolver struct {
+	db    dbutil.DB
+	store *searchjobs.Store
+}
+
+func (r *Resolver) NodeResolvers() map[string]graphqlbackend.NodeByIDFunc {
+	return map[string]graphqlbackend.NodeByIDFunc{
+		searchJobKind: func(ctx context.Context, id graphql.ID) (graphqlbackend.Node, error) {
+			return r.searchJobByID(ctx, id)
+		},
+	}
+}
+
+func marshalSearchJobID(id int64) graphql.ID {
+	return relay.MarshalID(searchJobKind, id)
+}
+
+func unmarshalSearchJobID(id graphql.ID) (searchJobID int64, err error) {
+	if kind := relay.UnmarshalKind(id); kind != searchJobKind {
+		return 0, errors.Errorf("expected graphql ID to have kind %q; got %q", searchJobKind, kind)
+	}
+	err = relay.UnmarshalSpec(id, &searchJobID)
+	return
+}
+
+// searchJobByID returns the search job with the given ID.
+//
+// 🚨 SECURITY: Only site admins and the user who created a search job can
+// access it.
+func (r *Resolver) searchJobByID(ctx context.Context, id graphql.ID) (*searchJobResolver, error) {
+	searchJobID, err := unmarshalSearchJobID(id)
+	if err != nil {
+		return nil, err
+	}
+	job, err := r.store.GetSearchJob(ctx, searchJobID)
+	if err != nil {
+		return nil, err
+	}
+	if err := backend.CheckSiteAdminOrSameUser(ctx, r.db, job.InitiatorID); err != nil {
+		return nil, err
+	}
+	return &searchJobResolver{db: r.db, store: r.store, job: job}, nil
+}
+
+func (r *Resolver) SearchJobs(ctx context.Context, args *graphqlbackend.ListSearchJobsArgs) (graphqlbackend.SearchJobConnectionResolver, error) {
+	a := actor.FromContext(ctx)
+	if !a.IsAuthenticated() {
+		return nil, backend.ErrNotAuthenticated
+	}
+
+	opts := searchjobs.ListSearchJobsOpts{InitiatorID: a.UID, First: int(args.First)}
+	if args.UserID != nil {
+		userID, err := graphqlbackend.UnmarshalUserID(*args.UserID)
+		if err != nil {
+			return nil, err
+		}
+		// 🚨 SECURITY: Only site admins can list the search jobs of other users.
+		if err := backend.CheckSiteAdminOrSameUser(ctx, r.db, userID); err != nil {
+			return nil, err
+		}
+		opts.InitiatorID = userID
+	}
+	if args.After != nil {
+		after, err := unmarshalSearchJobID(graphql.ID(*args.After))
+		if err != nil {
+			return nil, err
+		}
+		opts.After = after
+	}
+
+	return &searchJobConnectionResolver{db: r.db, store: r.store, opts: opts}, nil
+}
+
+func (r *Resolver) CreateSearchJob(ctx context.Context, args *graphqlbackend.CreateSearchJobArgs) (graphqlbackend.SearchJobResolver, error) {
+	a := actor.FromContext(ctx)
+	if !a.IsAuthenticated() {
+		return nil, backend.ErrNotAuthenticated
+	}
+	if err := searchjobs.ValidateQuery(args.Query, args.PatternType); err != nil {
+		return nil, err
+	}
+
+	job, err := r.store.CreateSearchJob(ctx, args.Query, args.PatternType, a.UID)
+	if err != nil {
+		return nil, err
+	}
+	return &searchJobResolver{db: r.db, store: r.store, job: job}, nil
+}
+
+func (r *Resolver) CancelSearchJob(ctx context.Context, args *graphqlbackend.SearchJobIDArgs) (graphqlbackend.SearchJobResolver, error) {
+	job, err := r.searchJobByID(ctx, args.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.store.CancelSearchJob(ctx, job.job.ID); err != nil {
+		return nil, err
+	}
+	return r.searchJobByID(ctx, args.ID)
+}
+
+func (r *Resolver) DeleteSearchJob(ctx context.Context, args *graphqlbackend.SearchJobIDArgs) (*graphqlbackend.EmptyResponse, error) {
+	job, err := r.searchJobByID(ctx, args.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.store.DeleteSearchJob(ctx, job.job.ID); err != nil {
+		return nil, err
+	}
+	return &graphqlbackend.EmptyResponse{}, nil
+}
+
+type searchJobConnectionResolver struct {
+	db    dbutil.DB
+	store *searchjobs.Store
+	opts  searchjobs.ListSearchJobsOpts
+
+	once sync.Once
+	jobs []*searchjobs.SearchJob
+	next int64
+	err  error
+}
+
+func (r *searchJobConnectionResolver) compute(ctx context.Context) ([]*searchjobs.SearchJob, int64, error) {
+	r.once.Do(func() {
+		// Request one extra to determine if there are more pages.
+		opts := r.opts
+		opts.First++
+
+		r.jobs, r.err = r.store.ListSearchJobs(ctx, opts)
+		if r.err == nil && len(r.jobs) > r.opts.First {
+			r.jobs = r.jobs[:r.opts.First]
+			r.next = r.jobs[len(r.jobs)-1].ID
+		}
+	})
+	return r.jobs, r.next, r.err
+}
+
+func (r *searchJobConnectionResolver) Nodes(ctx context.Context) ([]graphqlbackend.SearchJobResolver, error) {
+	jobs, _, err := r.compute(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resolvers := make([]graphqlbackend.SearchJobResolver, 0, len(jobs))
+	for _, job := range jobs {
+		resolvers = append(resolvers, &searchJobResolver{db: r.db, store: r.store, job: job})
+	}
+	return resolvers, nil
+}
+
+func (r *searchJobConnectionResolver) TotalCount(ctx context.Context) (int32, error) {
+	return r.store.CountSearchJobs(ctx, r.opts)
+}
+
+func (r *searchJobConnectionResolver) PageInfo(ctx context.Context) (*graphqlutil.PageInfo, error) {
+	_, next, err := r.compute(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if next == 0 {
+		return graphqlutil.HasNextPage(false), nil
+	}
+	return graphqlutil.NextPageCursor(string(marshalSearchJobID(next))), nil
+}
+
+type searchJobResolver struct {
+	db    dbutil.DB
+	store *searchjobs.Store
+	job   *searchjobs.SearchJob
+
+	once  sync.Once
+	stats searchjobs.RepoStats
+	err   error
+}
+
+func (r *searchJobResolver) repoStats(ctx context.Context) (searchjobs.RepoStats, error) {
+	r.once.Do(func() {
+		r.stats, r.err = r.store.GetRepoStats(ctx, r.job.ID)
+	})
+	return r.stats, r.err
+}
+
+func (r *searchJobResolver) ID() graphql.ID {
+	return marshalSearchJobID(r.job.ID)
+}
+
+func (r *searchJobResolver) Query() string {
+	return r.job.Query
+}
+
+func (r *searchJobResolver) PatternType() string {
+	return r.job.PatternType
+}
+
+func (r *searchJobResolver) Creator(ctx context.Context) (*graphqlbackend.UserResolver, error) {
+	user, err := graphqlbackend.UserByIDInt32(ctx, r.db, r.job.InitiatorID)
+	if errcode.IsNotFound(err) {
+		return nil, nil
+	}
+	return user, err
+}
+
+func (r *searchJobResolver) State(ctx context.Context) (string, error) {
+	if r.job.CanceledAt != nil {
+		return "CANCELED", nil
+	}
+	switch r.job.State {
+	case "queued", "errored":
+		return "QUEUED", nil
+	case "processing":
+		return "PROCESSING", nil
+	case "failed":
+		return "FAILED", nil
+	case "completed":
+		stats, err := r.repoStats(ctx)
+		if err != nil {
+			return "", err
+		}
+		if !stats.Done() {
+			return "PROCESSING", nil
+		}
+		return "COMPLETED", nil
+	default:
+		return "", errors.Errorf("unknown search job state %q", r.job.State)
+	}
+}
+
+func (r *searchJobResolver) FailureMessage() *string {
+	return r.job.FailureMessage
+}
+
+func (r *searchJobResolver) CreatedAt() graphqlbackend.DateTime {
+	return graphqlbackend.DateTime{Time: r.job.CreatedAt}
+}
+
+func (r *searchJobResolver) StartedAt() *graphqlbackend.DateTime {
+	return graphqlbackend.DateTimeOrNil(r.job.StartedAt)
+}
+
+func (r *searchJobResolver) FinishedAt(ctx context.Context) (*graphqlbackend.DateTime, error) {
+	state, err := r.State(ctx)
+	if err != nil {
+		return nil, err
+	}
+	switch state {
+	case "FAILED":
+		return graphqlbackend.DateTimeOrNil(r.job.FinishedAt), nil
+	case "COMPLETED":
+		stats, err := r.repoStats(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if stats.LastFinishedAt == nil {
+			// There were no repositories to search.
+			return graphqlbackend.DateTimeOrNil(r.job.FinishedAt), nil
+		}
+		return graphqlbackend.DateTimeOrNil(stats.LastFinishedAt), nil
+	default:
+		return nil, nil
+	}
+}
+
+func (r *searchJobResolver) CanceledAt() *graphqlbackend.DateTime {
+	return graphqlbackend.DateTimeOrNil(r.job.CanceledAt)
+}
+
+func (r *searchJobResolver) RepoStats(ctx context.Context) (graphqlbackend.SearchJobRepoStatsResolver, error) {
+	stats, err := r.repoStats(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &repoStatsResolver{stats: stats}, nil
+}
+
+func (r *searchJobResolver) ResultCount(ctx context.Context) (int32, error) {
+	stats, err := r.repoStats(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return stats.NumResults, nil
+}
+
+func (r *searchJobResolver) ResultsURL() string {
+	return fmt.Sprintf("/.api/search/jobs/%d/results", r.job.ID)
+}
+
+type repoStatsResolver struct {
+	stats searchjobs.RepoStats
+}
+
+func (r *repoStatsResolver) Total() int32      { return r.stats.Total }
+func (r *repoStatsResolver) Queued() int32     { return r.stats.Queued }
+func (r *repoStatsResolver) Processing() int32 { return r.stats.Processing }
+func (r *repoStatsResolver) Completed() int32  { return r.stats.Completed }
+func (r *repoStatsResolver) Failed() int32     { return r.stats.Failed }
+func (r *repoStatsResolver) Canceled() int32   { return r.stats.Canceled }
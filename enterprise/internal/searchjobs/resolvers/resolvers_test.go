@@ -0,0 +1,60 @@
+package resolvers
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/resolvers/apitest"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func TestCreateSearchJobValidation(t *testing.T) {
+	database.Mocks.Users.GetByCurrentAuthUser = func(ctx context.Context) (*types.User, error) {
+		return &types.User{ID: actor.FromContext(ctx).UID}, nil
+	}
+	defer func() { database.Mocks.Users = database.MockUsers{} }()
+
+	schema, err := graphqlbackend.NewSchema(nil, nil, nil, nil, nil, nil, nil, nil, NewResolver(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mutation := `mutation CreateSearchJob($query: String!) { createSearchJob(query: $query) { id } }`
+
+	for name, tc := range map[string]struct {
+		ctx     context.Context
+		query   string
+		wantErr string
+	}{
+		"unauthenticated": {
+			ctx:     context.Background(),
+			query:   "secret",
+			wantErr: "not authenticated",
+		},
+		"or operator": {
+			ctx:     actor.WithActor(context.Background(), actor.FromUser(1)),
+			query:   "secret or password",
+			wantErr: "must not contain 'or' operators",
+		},
+		"count filter": {
+			ctx:     actor.WithActor(context.Background(), actor.FromUser(1)),
+			query:   "secret count:10",
+			wantErr: "must not contain the count: filter",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			var response struct{}
+			errs := apitest.Exec(tc.ctx, t, schema, map[string]interface{}{"query": tc.query}, &response, mutation)
+			if len(errs) != 1 {
+				t.Fatalf("expected one error, got %v", errs)
+			}
+			if !strings.Contains(errs[0].Message, tc.wantErr) {
+				t.Errorf("got error %q, want it to contain %q", errs[0].Message, tc.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,169 @@
+package searchjobs
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/workerutil"
+)
+
+// SearchJobRepo is the search of a single repository of a search job. Each
+// of them is processed by a worker, so a search job picks up where it left
+// off when the worker restarts.
+type SearchJobRepo struct {
+	ID          int64
+	SearchJobID int64
+	RepoID      api.RepoID
+	RepoName    api.RepoName
+	NumResults  int32
+
+	// Fields demanded for any dbworker.
+	State          string
+	FailureMessage *string
+	StartedAt      *time.Time
+	FinishedAt     *time.Time
+	ProcessAfter   *time.Time
+	NumResets      int32
+	NumFailures    int32
+}
+
+func (r *SearchJobRepo) RecordID() int {
+	return int(r.ID)
+}
+
+// SearchJobRepoColumns are the columns of a SearchJobRepo, in the order
+// expected by ScanSearchJobRepo.
+var SearchJobRepoColumns = []*sqlf.Query{
+	sqlf.Sprintf("search_job_repos.id"),
+	sqlf.Sprintf("search_job_repos.search_job_id"),
+	sqlf.Sprintf("search_job_repos.repo_id"),
+	sqlf.Sprintf("(SELECT name FROM repo WHERE repo.id = search_job_repos.repo_id)"),
+	sqlf.Sprintf("search_job_repos.num_results"),
+	sqlf.Sprintf("search_job_repos.state"),
+	sqlf.Sprintf("search_job_repos.failure_message"),
+	sqlf.Sprintf("search_job_repos.started_at"),
+	sqlf.Sprintf("search_job_repos.finished_at"),
+	sqlf.Sprintf("search_job_repos.process_after"),
+	sqlf.Sprintf("search_job_repos.num_resets"),
+	sqlf.Sprintf("search_job_repos.num_failures"),
+}
+
+const createSearchJobReposFmtStr = `
+INSERT INTO search_job_repos (search_job_id, repo_id)
+SELECT %s, unnest(%s::integer[])
+ON CONFLICT (search_job_id, repo_id) DO NOTHING
+`
+
+// CreateSearchJobRepos queues the search of the given repositories for a
+// search job. Repositories that were already queued are skipped.
+func (s *Store) CreateSearchJobRepos(ctx context.Context, searchJobID int64, repoIDs []api.RepoID) error {
+	ids := make([]int32, 0, len(repoIDs))
+	for _, id := range repoIDs {
+		ids = append(ids, int32(id))
+	}
+	return s.Store.Exec(ctx, sqlf.Sprintf(createSearchJobReposFmtStr, searchJobID, pq.Array(ids)))
+}
+
+const markSearchJobRepoCanceledFmtStr = `
+UPDATE search_job_repos
+SET state = 'canceled', finished_at = %s
+WHERE id = %s
+AND state = 'processing'
+`
+
+// MarkSearchJobRepoCanceled marks the search of a repository which is being
+// processed as canceled. Workers only mark processing records as completed, so
+// it stays canceled once its handler returns.
+func (s *Store) MarkSearchJobRepoCanceled(ctx context.Context, id int64) error {
+	return s.Store.Exec(ctx, sqlf.Sprintf(markSearchJobRepoCanceledFmtStr, s.Now(), id))
+}
+
+// RepoStats are the number of repositories of a search job, by the state of
+// their search.
+type RepoStats struct {
+	Total      int32
+	Queued     int32
+	Processing int32
+	Completed  int32
+	Failed     int32
+	Canceled   int32
+
+	// NumResults is the number of results found in all repositories.
+	NumResults int32
+
+	// LastFinishedAt is when the search of the last repository finished.
+	LastFinishedAt *time.Time
+}
+
+// Done returns true if no repository is left to search.
+func (s RepoStats) Done() bool {
+	return s.Queued == 0 && s.Processing == 0
+}
+
+const getRepoStatsFmtStr = `
+SELECT
+	COUNT(*),
+	COUNT(*) FILTER (WHERE state IN ('queued', 'errored')),
+	COUNT(*) FILTER (WHERE state = 'processing'),
+	COUNT(*) FILTER (WHERE state = 'completed'),
+	COUNT(*) FILTER (WHERE state = 'failed'),
+	COUNT(*) FILTER (WHERE state = 'canceled'),
+	COALESCE(SUM(num_results), 0),
+	MAX(finished_at)
+FROM search_job_repos
+WHERE search_job_id = %s
+`
+
+// GetRepoStats returns the progress of the search of the repositories of a
+// search job.
+func (s *Store) GetRepoStats(ctx context.Context, searchJobID int64) (RepoStats, error) {
+	var stats RepoStats
+	err := s.Store.QueryRow(ctx, sqlf.Sprintf(getRepoStatsFmtStr, searchJobID)).Scan(
+		&stats.Total,
+		&stats.Queued,
+		&stats.Processing,
+		&stats.Completed,
+		&stats.Failed,
+		&stats.Canceled,
+		&stats.NumResults,
+		&stats.LastFinishedAt,
+	)
+	return stats, err
+}
+
+// ScanSearchJobRepo is the dbworker RecordScanFn of search job repositories.
+func ScanSearchJobRepo(rows *sql.Rows, queryErr error) (_ workerutil.Record, _ bool, err error) {
+	if queryErr != nil {
+		return &SearchJobRepo{}, false, queryErr
+	}
+	defer func() { err = basestore.CloseRows(rows, err) }()
+
+	if !rows.Next() {
+		return &SearchJobRepo{}, false, nil
+	}
+
+	r := &SearchJobRepo{}
+	if err := rows.Scan(
+		&r.ID,
+		&r.SearchJobID,
+		&r.RepoID,
+		&r.RepoName,
+		&r.NumResults,
+		&r.State,
+		&r.FailureMessage,
+		&r.StartedAt,
+		&r.FinishedAt,
+		&r.ProcessAfter,
+		&r.NumResets,
+		&r.NumFailures,
+	); err != nil {
+		return &SearchJobRepo{}, false, err
+	}
+	return r, true, nil
+}
@@ -0,0 +1,92 @@
+package searchjobs
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/keegancsmith/sqlf"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/database/batch"
+	streamhttp "github.com/sourcegraph/sourcegraph/internal/search/streaming/http"
+)
+
+const deleteSearchJobRepoResultsFmtStr = `
+DELETE FROM search_job_results
+WHERE search_job_repo_id = %s
+`
+
+const setSearchJobRepoNumResultsFmtStr = `
+UPDATE search_job_repos
+SET num_results = %s
+WHERE id = %s
+`
+
+// ReplaceSearchJobRepoResults replaces the results of the search of a
+// repository with the rows passed to emit by search. Results of a previous,
+// failed attempt are removed in the same transaction, so that a repository
+// which is searched again never has duplicate results.
+func (s *Store) ReplaceSearchJobRepoResults(ctx context.Context, repo *SearchJobRepo, search func(emit func(streamhttp.ExportRow) error) error) (err error) {
+	tx, err := s.Transact(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { err = tx.Done(err) }()
+
+	if err := tx.Exec(ctx, sqlf.Sprintf(deleteSearchJobRepoResultsFmtStr, repo.ID)); err != nil {
+		return err
+	}
+
+	inserter := batch.NewInserter(ctx, tx.Handle().DB(), "search_job_results", "search_job_id", "search_job_repo_id", "result")
+	numResults := 0
+	err = search(func(row streamhttp.ExportRow) error {
+		b, err := json.Marshal(row)
+		if err != nil {
+			return err
+		}
+		numResults++
+		return inserter.Insert(ctx, repo.SearchJobID, repo.ID, b)
+	})
+	if err != nil {
+		return err
+	}
+	if err := inserter.Flush(ctx); err != nil {
+		return err
+	}
+
+	return tx.Exec(ctx, sqlf.Sprintf(setSearchJobRepoNumResultsFmtStr, numResults, repo.ID))
+}
+
+const listSearchJobResultsFmtStr = `
+SELECT result
+FROM search_job_results
+WHERE search_job_id = %s
+ORDER BY id
+OFFSET %s
+`
+
+// ForEachSearchJobResult calls fn with every result of a search job, in the
+// order they were stored, skipping the first offset results. The results are
+// read from a single cursor, so they don't need to fit into memory.
+func (s *Store) ForEachSearchJobResult(ctx context.Context, searchJobID int64, offset int, fn func(streamhttp.ExportRow) error) (err error) {
+	rows, err := s.Store.Query(ctx, sqlf.Sprintf(listSearchJobResultsFmtStr, searchJobID, offset))
+	if err != nil {
+		return err
+	}
+	defer func() { err = basestore.CloseRows(rows, err) }()
+
+	for rows.Next() {
+		var b []byte
+		if err := rows.Scan(&b); err != nil {
+			return err
+		}
+		var row streamhttp.ExportRow
+		if err := json.Unmarshal(b, &row); err != nil {
+			return err
+		}
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,255 @@
+package searchjobs
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/keegancsmith/sqlf"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/workerutil"
+)
+
+// SearchJob is a search query that is searched exhaustively, repository by
+// repository. The record itself is processed by a worker which determines
+// the repositories to search and creates a SearchJobRepo for each of them.
+type SearchJob struct {
+	ID          int64
+	Query       string
+	PatternType string
+	InitiatorID int32
+
+	// Fields demanded for any dbworker.
+	State          string
+	FailureMessage *string
+	StartedAt      *time.Time
+	FinishedAt     *time.Time
+	ProcessAfter   *time.Time
+	NumResets      int32
+	NumFailures    int32
+
+	CanceledAt *time.Time
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+func (j *SearchJob) RecordID() int {
+	return int(j.ID)
+}
+
+// ErrSearchJobNotFound is returned when a search job does not exist.
+var ErrSearchJobNotFound = errors.New("search job not found")
+
+// SearchJobColumns are the columns of a SearchJob, in the order expected by
+// ScanSearchJob.
+var SearchJobColumns = []*sqlf.Query{
+	sqlf.Sprintf("search_jobs.id"),
+	sqlf.Sprintf("search_jobs.query"),
+	sqlf.Sprintf("search_jobs.pattern_type"),
+	sqlf.Sprintf("search_jobs.initiator_id"),
+	sqlf.Sprintf("search_jobs.state"),
+	sqlf.Sprintf("search_jobs.failure_message"),
+	sqlf.Sprintf("search_jobs.started_at"),
+	sqlf.Sprintf("search_jobs.finished_at"),
+	sqlf.Sprintf("search_jobs.process_after"),
+	sqlf.Sprintf("search_jobs.num_resets"),
+	sqlf.Sprintf("search_jobs.num_failures"),
+	sqlf.Sprintf("search_jobs.canceled_at"),
+	sqlf.Sprintf("search_jobs.created_at"),
+	sqlf.Sprintf("search_jobs.updated_at"),
+}
+
+const createSearchJobFmtStr = `
+INSERT INTO search_jobs (query, pattern_type, initiator_id, created_at, updated_at)
+VALUES (%s, %s, %s, %s, %s)
+RETURNING %s
+`
+
+// CreateSearchJob queues a new search job.
+func (s *Store) CreateSearchJob(ctx context.Context, query, patternType string, initiatorID int32) (*SearchJob, error) {
+	now := s.Now()
+	q := sqlf.Sprintf(
+		createSearchJobFmtStr,
+		query,
+		patternType,
+		initiatorID,
+		now,
+		now,
+		sqlf.Join(SearchJobColumns, ", "),
+	)
+	return scanSearchJob(s.Store.QueryRow(ctx, q))
+}
+
+const getSearchJobFmtStr = `
+SELECT %s
+FROM search_jobs
+WHERE id = %s
+`
+
+// GetSearchJob returns the search job with the given ID, or
+// ErrSearchJobNotFound.
+func (s *Store) GetSearchJob(ctx context.Context, id int64) (*SearchJob, error) {
+	q := sqlf.Sprintf(getSearchJobFmtStr, sqlf.Join(SearchJobColumns, ", "), id)
+	return scanSearchJob(s.Store.QueryRow(ctx, q))
+}
+
+// ListSearchJobsOpts are the options for listing and counting search jobs.
+type ListSearchJobsOpts struct {
+	// InitiatorID, if non-zero, only returns the search jobs created by this
+	// user.
+	InitiatorID int32
+
+	// First is the maximum number of search jobs to return. It is ignored
+	// when counting.
+	First int
+
+	// After, if non-zero, only returns search jobs with a smaller ID. It is
+	// ignored when counting.
+	After int64
+}
+
+func (o ListSearchJobsOpts) conds() []*sqlf.Query {
+	conds := []*sqlf.Query{sqlf.Sprintf("TRUE")}
+	if o.InitiatorID != 0 {
+		conds = append(conds, sqlf.Sprintf("initiator_id = %s", o.InitiatorID))
+	}
+	return conds
+}
+
+const listSearchJobsFmtStr = `
+SELECT %s
+FROM search_jobs
+WHERE %s
+ORDER BY id DESC
+LIMIT %s
+`
+
+// ListSearchJobs returns search jobs, the most recent first.
+func (s *Store) ListSearchJobs(ctx context.Context, opts ListSearchJobsOpts) ([]*SearchJob, error) {
+	conds := opts.conds()
+	if opts.After != 0 {
+		conds = append(conds, sqlf.Sprintf("id < %s", opts.After))
+	}
+	q := sqlf.Sprintf(
+		listSearchJobsFmtStr,
+		sqlf.Join(SearchJobColumns, ", "),
+		sqlf.Join(conds, "AND"),
+		opts.First,
+	)
+	return scanSearchJobs(s.Store.Query(ctx, q))
+}
+
+const countSearchJobsFmtStr = `
+SELECT COUNT(*)
+FROM search_jobs
+WHERE %s
+`
+
+// CountSearchJobs returns the number of search jobs matching opts.
+func (s *Store) CountSearchJobs(ctx context.Context, opts ListSearchJobsOpts) (int32, error) {
+	count, _, err := basestore.ScanFirstInt(s.Store.Query(ctx, sqlf.Sprintf(countSearchJobsFmtStr, sqlf.Join(opts.conds(), "AND"))))
+	return int32(count), err
+}
+
+const cancelSearchJobFmtStr = `
+UPDATE search_jobs
+SET canceled_at = COALESCE(canceled_at, %s),
+    state = CASE WHEN state IN ('queued', 'errored') THEN 'canceled' ELSE state END,
+    updated_at = %s
+WHERE id = %s
+`
+
+const cancelSearchJobReposFmtStr = `
+UPDATE search_job_repos
+SET state = 'canceled'
+WHERE search_job_id = %s
+AND state IN ('queued', 'errored')
+`
+
+// CancelSearchJob cancels a search job. Repositories that are not being
+// searched yet are not searched anymore.
+func (s *Store) CancelSearchJob(ctx context.Context, id int64) (err error) {
+	tx, err := s.Transact(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { err = tx.Done(err) }()
+
+	now := s.Now()
+	if err := tx.Exec(ctx, sqlf.Sprintf(cancelSearchJobFmtStr, now, now, id)); err != nil {
+		return err
+	}
+	return tx.Exec(ctx, sqlf.Sprintf(cancelSearchJobReposFmtStr, id))
+}
+
+const deleteSearchJobFmtStr = `
+DELETE FROM search_jobs
+WHERE id = %s
+`
+
+// DeleteSearchJob deletes a search job along with its repositories and
+// results.
+func (s *Store) DeleteSearchJob(ctx context.Context, id int64) error {
+	return s.Store.Exec(ctx, sqlf.Sprintf(deleteSearchJobFmtStr, id))
+}
+
+// ScanSearchJob is the dbworker RecordScanFn of search jobs.
+func ScanSearchJob(rows *sql.Rows, err error) (workerutil.Record, bool, error) {
+	jobs, err := scanSearchJobs(rows, err)
+	if err != nil || len(jobs) == 0 {
+		return &SearchJob{}, false, err
+	}
+	return jobs[0], true, nil
+}
+
+type scanner interface {
+	Scan(dst ...interface{}) error
+}
+
+func scanSearchJobFields(sc scanner) (*SearchJob, error) {
+	j := &SearchJob{}
+	err := sc.Scan(
+		&j.ID,
+		&j.Query,
+		&j.PatternType,
+		&j.InitiatorID,
+		&j.State,
+		&j.FailureMessage,
+		&j.StartedAt,
+		&j.FinishedAt,
+		&j.ProcessAfter,
+		&j.NumResets,
+		&j.NumFailures,
+		&j.CanceledAt,
+		&j.CreatedAt,
+		&j.UpdatedAt,
+	)
+	return j, err
+}
+
+func scanSearchJob(row *sql.Row) (*SearchJob, error) {
+	j, err := scanSearchJobFields(row)
+	if err == sql.ErrNoRows {
+		return nil, ErrSearchJobNotFound
+	}
+	return j, err
+}
+
+func scanSearchJobs(rows *sql.Rows, queryErr error) (_ []*SearchJob, err error) {
+	if queryErr != nil {
+		return nil, queryErr
+	}
+	defer func() { err = basestore.CloseRows(rows, err) }()
+
+	var jobs []*SearchJob
+	for rows.Next() {
+		j, err := scanSearchJobFields(rows)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, j)
+	}
+	return jobs, nil
+}
@@ -0,0 +1,69 @@
+package bitbucketcloud
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketcloud"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// NewAuthzProviders returns the set of Bitbucket Cloud authz providers derived
+// from the connections. It also returns any validation problems with the
+// config, separating these into "serious problems" and "warnings". "Serious
+// problems" are those that should make Sourcegraph set
+// authz.allowAccessByDefault to false. "Warnings" are all other validation
+// problems.
+func NewAuthzProviders(conns []*types.BitbucketCloudConnection) (ps []authz.Provider, problems []string, warnings []string) {
+	for _, c := range conns {
+		p, err := newAuthzProvider(c)
+		if err != nil {
+			problems = append(problems, err.Error())
+		} else if p != nil {
+			ps = append(ps, p)
+		}
+	}
+
+	for _, p := range ps {
+		for _, problem := range p.Validate() {
+			warnings = append(warnings, fmt.Sprintf("Bitbucket Cloud config for %s was invalid: %s", p.ServiceID(), problem))
+		}
+	}
+
+	return ps, problems, warnings
+}
+
+func newAuthzProvider(c *types.BitbucketCloudConnection) (authz.Provider, error) {
+	if c.Authorization == nil {
+		return nil, nil
+	}
+
+	baseURL, err := url.Parse(c.Url)
+	if err != nil {
+		return nil, err
+	}
+	baseURL = extsvc.NormalizeBaseURL(baseURL)
+
+	apiURL := c.ApiURL
+	if apiURL == "" {
+		apiURL = "https://api.bitbucket.org"
+	}
+	u, err := url.Parse(apiURL)
+	if err != nil {
+		return nil, err
+	}
+	u = extsvc.NormalizeBaseURL(u)
+
+	cli := bitbucketcloud.NewClient(u, nil).WithCredentials(c.Username, c.AppPassword)
+	return NewProvider(c.URN, baseURL, cli, c.Teams), nil
+}
+
+// ValidateAuthz validates the authorization fields of the given Bitbucket
+// Cloud external service config.
+func ValidateAuthz(cfg *schema.BitbucketCloudConnection) error {
+	_, err := newAuthzProvider(&types.BitbucketCloudConnection{BitbucketCloudConnection: cfg})
+	return err
+}
@@ -0,0 +1,256 @@
+// Package bitbucketcloud contains an authorization provider for Bitbucket Cloud.
+package bitbucketcloud
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	otlog "github.com/opentracing/opentracing-go/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketcloud"
+	"github.com/sourcegraph/sourcegraph/internal/trace"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+// Provider is an implementation of authz.Provider that provides repository
+// permissions as determined from the workspace and permissions APIs of
+// Bitbucket Cloud.
+type Provider struct {
+	urn        string
+	client     *bitbucketcloud.Client
+	codeHost   *extsvc.CodeHost
+	workspaces []string
+}
+
+var _ authz.Provider = (*Provider)(nil)
+
+// NewProvider returns a new Bitbucket Cloud authorization provider that uses
+// the given bitbucketcloud.Client to fetch the permissions of the given
+// workspaces. The baseURL is the URL of the Bitbucket Cloud web interface, as
+// opposed to the API URL of the client. It assumes usernames of Sourcegraph
+// accounts match 1-1 with nicknames of Bitbucket Cloud users. The client's
+// user must be an administrator of the workspaces so that it can list the
+// permissions of other users.
+func NewProvider(urn string, baseURL *url.URL, cli *bitbucketcloud.Client, workspaces []string) *Provider {
+	return &Provider{
+		urn:        urn,
+		client:     cli,
+		codeHost:   extsvc.NewCodeHost(baseURL, extsvc.TypeBitbucketCloud),
+		workspaces: workspaces,
+	}
+}
+
+// Validate validates that the Provider has access to the Bitbucket Cloud API
+// with the credentials it was configured with, and that the user is an
+// administrator of every workspace.
+func (p *Provider) Validate() (problems []string) {
+	if len(p.workspaces) == 0 {
+		return []string{`at least one workspace must be listed in "teams" to sync permissions`}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	for _, workspace := range p.workspaces {
+		perm, err := p.client.CurrentUserWorkspacePermission(ctx, workspace)
+		if err != nil {
+			problems = append(problems, err.Error())
+			continue
+		}
+
+		if perm == nil || perm.Permission != "owner" {
+			problems = append(problems, fmt.Sprintf("the user must be an administrator of the workspace %q to sync permissions", workspace))
+		}
+	}
+
+	return problems
+}
+
+func (p *Provider) URN() string {
+	return p.urn
+}
+
+// ServiceID returns the absolute URL that identifies the Bitbucket Cloud
+// instance this provider is configured with.
+func (p *Provider) ServiceID() string { return p.codeHost.ServiceID }
+
+// ServiceType returns the type of this Provider, namely, "bitbucketCloud".
+func (p *Provider) ServiceType() string { return p.codeHost.ServiceType }
+
+// FetchAccount returns the Bitbucket Cloud account that is a member of one of
+// the workspaces and whose nickname matches the username of the given user.
+// It returns nil if there is no such account.
+func (p *Provider) FetchAccount(ctx context.Context, user *types.User, _ []*extsvc.Account, _ []string) (acct *extsvc.Account, err error) {
+	if user == nil {
+		return nil, nil
+	}
+
+	tr, ctx := trace.New(ctx, "bitbucketcloud.authz.provider.FetchAccount", "")
+	defer func() {
+		tr.LogFields(
+			otlog.String("user.name", user.Username),
+			otlog.Int32("user.id", user.ID),
+		)
+
+		if err != nil {
+			tr.SetError(err)
+		}
+
+		tr.Finish()
+	}()
+
+	bbUser, err := p.findMember(ctx, user.Username)
+	if err != nil || bbUser == nil {
+		return nil, err
+	}
+
+	accountData, err := json.Marshal(bbUser)
+	if err != nil {
+		return nil, err
+	}
+
+	return &extsvc.Account{
+		UserID: user.ID,
+		AccountSpec: extsvc.AccountSpec{
+			ServiceType: p.codeHost.ServiceType,
+			ServiceID:   p.codeHost.ServiceID,
+			AccountID:   bbUser.UUID,
+		},
+		AccountData: extsvc.AccountData{
+			Data: (*json.RawMessage)(&accountData),
+		},
+	}, nil
+}
+
+// findMember returns the member of the workspaces with the given nickname, or
+// nil if there is none.
+func (p *Provider) findMember(ctx context.Context, nickname string) (*bitbucketcloud.User, error) {
+	for _, workspace := range p.workspaces {
+		t := &bitbucketcloud.PageToken{}
+		for {
+			members, next, err := p.client.WorkspaceMembers(ctx, t, workspace)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, m := range members {
+				if m.User.Nickname == nickname {
+					return &m.User, nil
+				}
+			}
+
+			if !next.HasMore() {
+				break
+			}
+			t = next
+		}
+	}
+
+	return nil, nil
+}
+
+// FetchUserPerms returns a list of repository IDs (on code host) that the given
+// account has read access on the code host. The repository ID has the same
+// value as it would be used as api.ExternalRepoSpec.ID, i.e. the UUID of the
+// repository. Only repositories of the configured workspaces are returned.
+//
+// This method may return partial but valid results in case of error, and it is
+// up to callers to decide whether to discard.
+//
+// API docs: https://developer.atlassian.com/cloud/bitbucket/rest/api-group-workspaces/#api-workspaces-workspace-permissions-repositories-get
+func (p *Provider) FetchUserPerms(ctx context.Context, account *extsvc.Account) (*authz.ExternalUserPermissions, error) {
+	switch {
+	case account == nil:
+		return nil, errors.New("no account provided")
+	case account.Data == nil:
+		return nil, errors.New("no account data provided")
+	case !extsvc.IsHostOfAccount(p.codeHost, account):
+		return nil, errors.Errorf("not a code host of the account: want %q but have %q",
+			p.codeHost.ServiceID, account.AccountSpec.ServiceID)
+	}
+
+	var user bitbucketcloud.User
+	if err := json.Unmarshal(*account.Data, &user); err != nil {
+		return nil, errors.Wrap(err, "unmarshaling account data")
+	}
+
+	perms := &authz.ExternalUserPermissions{}
+	for _, workspace := range p.workspaces {
+		t := &bitbucketcloud.PageToken{}
+		for {
+			repoPerms, next, err := p.client.UserRepoPermissions(ctx, t, workspace, user.UUID)
+			if err != nil {
+				return perms, err
+			}
+
+			for _, rp := range repoPerms {
+				perms.Exacts = append(perms.Exacts, extsvc.RepoID(rp.Repo.UUID))
+			}
+
+			if !next.HasMore() {
+				break
+			}
+			t = next
+		}
+	}
+
+	return perms, nil
+}
+
+// FetchUserPermsByToken is currently only required for syncing permissions for
+// GitHub and GitLab on sourcegraph.com
+func (p *Provider) FetchUserPermsByToken(ctx context.Context, token string) (*authz.ExternalUserPermissions, error) {
+	return nil, errors.New("not implemented")
+}
+
+// FetchRepoPerms returns a list of user IDs (on code host) who have read access
+// to the given repo on the code host. The user ID has the same value as it
+// would be used as extsvc.Account.AccountID, i.e. the UUID of the user.
+//
+// This method may return partial but valid results in case of error, and it is
+// up to callers to decide whether to discard.
+//
+// API docs: https://developer.atlassian.com/cloud/bitbucket/rest/api-group-workspaces/#api-workspaces-workspace-permissions-repositories-repo-slug-get
+func (p *Provider) FetchRepoPerms(ctx context.Context, repo *extsvc.Repository) ([]extsvc.AccountID, error) {
+	switch {
+	case repo == nil:
+		return nil, errors.New("no repo provided")
+	case !extsvc.IsHostOfRepo(p.codeHost, &repo.ExternalRepoSpec):
+		return nil, errors.Errorf("not a code host of the repo: want %q but have %q",
+			p.codeHost.ServiceID, repo.ServiceID)
+	}
+
+	// The URI of a Bitbucket Cloud repository is "{host}/{workspace}/{slug}".
+	parts := strings.Split(repo.URI, "/")
+	if len(parts) != 3 {
+		return nil, errors.Errorf("unexpected repository URI %q", repo.URI)
+	}
+	workspace, slug := parts[1], parts[2]
+
+	var ids []extsvc.AccountID
+	t := &bitbucketcloud.PageToken{}
+	for {
+		repoPerms, next, err := p.client.RepoPermissions(ctx, t, workspace, slug)
+		if err != nil {
+			return ids, err
+		}
+
+		for _, rp := range repoPerms {
+			ids = append(ids, extsvc.AccountID(rp.User.UUID))
+		}
+
+		if !next.HasMore() {
+			break
+		}
+		t = next
+	}
+
+	return ids, nil
+}
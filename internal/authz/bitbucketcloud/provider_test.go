@@ -0,0 +1,135 @@
+package bitbucketcloud
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketcloud"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+var update = flag.Bool("update", false, "update testdata")
+
+// To update the recorded fixtures, set BITBUCKET_CLOUD_USERNAME and
+// BITBUCKET_CLOUD_APP_PASSWORD to the credentials of an administrator of the
+// "sglocal" workspace and run the tests with -update=true.
+
+var (
+	baseURL = &url.URL{Scheme: "https", Host: "bitbucket.org", Path: "/"}
+	apiURL  = &url.URL{Scheme: "https", Host: "api.bitbucket.org", Path: "/"}
+)
+
+const (
+	adminUUID = "{2b5e4d2c-1d2e-4f5a-9d1b-3c4b5a6d7e8f}"
+	aliceUUID = "{a1c3e5f7-0b2d-4e6f-8a1c-3e5f7a9b1d3f}"
+	muxUUID   = "{e1e75436-05e6-4c38-8543-9c36ec26fad1}"
+	langUUID  = "{421b93e9-1f00-4054-8156-4d821d4a768b}"
+)
+
+func newTestProvider(t *testing.T, name string) *Provider {
+	cli, save := bitbucketcloud.NewTestClient(t, name, *update, apiURL)
+	t.Cleanup(save)
+
+	return NewProvider("extsvc:bitbucketcloud:1", baseURL, cli, []string{"sglocal"})
+}
+
+func TestProvider_Validate(t *testing.T) {
+	t.Run("no problems when the user is an administrator", func(t *testing.T) {
+		p := newTestProvider(t, "Validate-owner")
+		if problems := p.Validate(); len(problems) > 0 {
+			t.Fatalf("unexpected problems: %v", problems)
+		}
+	})
+
+	t.Run("problems when the user is not an administrator", func(t *testing.T) {
+		p := newTestProvider(t, "Validate-member")
+		want := []string{`the user must be an administrator of the workspace "sglocal" to sync permissions`}
+		if diff := cmp.Diff(want, p.Validate()); diff != "" {
+			t.Fatalf("mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("problems without workspaces", func(t *testing.T) {
+		p := NewProvider("extsvc:bitbucketcloud:1", baseURL, bitbucketcloud.NewClient(apiURL, nil), nil)
+		if problems := p.Validate(); len(problems) != 1 {
+			t.Fatalf("unexpected problems: %v", problems)
+		}
+	})
+}
+
+func TestProvider_FetchAccount(t *testing.T) {
+	p := newTestProvider(t, "FetchAccount")
+	ctx := context.Background()
+
+	acct, err := p.FetchAccount(ctx, &types.User{ID: 42, Username: "alice"}, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if acct == nil || acct.AccountID != aliceUUID || acct.UserID != 42 || acct.ServiceID != "https://bitbucket.org/" {
+		t.Fatalf("unexpected account: %+v", acct)
+	}
+
+	acct, err = p.FetchAccount(ctx, &types.User{ID: 43, Username: "mallory"}, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if acct != nil {
+		t.Fatalf("expected no account, got %+v", acct)
+	}
+}
+
+func TestProvider_FetchUserPerms(t *testing.T) {
+	p := newTestProvider(t, "FetchUserPerms")
+
+	data, err := json.Marshal(bitbucketcloud.User{UUID: aliceUUID, Nickname: "alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	acct := &extsvc.Account{
+		AccountSpec: extsvc.AccountSpec{
+			ServiceType: extsvc.TypeBitbucketCloud,
+			ServiceID:   baseURL.String(),
+			AccountID:   aliceUUID,
+		},
+		AccountData: extsvc.AccountData{Data: (*json.RawMessage)(&data)},
+	}
+
+	perms, err := p.FetchUserPerms(context.Background(), acct)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := &authz.ExternalUserPermissions{Exacts: []extsvc.RepoID{muxUUID, langUUID}}
+	if diff := cmp.Diff(want, perms); diff != "" {
+		t.Fatalf("mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestProvider_FetchRepoPerms(t *testing.T) {
+	p := newTestProvider(t, "FetchRepoPerms")
+
+	ids, err := p.FetchRepoPerms(context.Background(), &extsvc.Repository{
+		URI: "bitbucket.org/sglocal/mux",
+		ExternalRepoSpec: api.ExternalRepoSpec{
+			ID:          muxUUID,
+			ServiceType: extsvc.TypeBitbucketCloud,
+			ServiceID:   baseURL.String(),
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []extsvc.AccountID{adminUUID, aliceUUID}
+	if diff := cmp.Diff(want, ids); diff != "" {
+		t.Fatalf("mismatch (-want +got):\n%s", diff)
+	}
+}
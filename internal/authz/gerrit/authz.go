@@ -0,0 +1,61 @@
+package gerrit
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gerrit"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// NewAuthzProviders returns the set of Gerrit authz providers derived from the
+// connections. It also returns any validation problems with the config,
+// separating these into "serious problems" and "warnings". "Serious problems"
+// are those that should make Sourcegraph set authz.allowAccessByDefault to
+// false. "Warnings" are all other validation problems.
+func NewAuthzProviders(conns []*types.GerritConnection) (ps []authz.Provider, problems []string, warnings []string) {
+	for _, c := range conns {
+		p, err := newAuthzProvider(c)
+		if err != nil {
+			problems = append(problems, err.Error())
+		} else if p != nil {
+			ps = append(ps, p)
+		}
+	}
+
+	for _, p := range ps {
+		for _, problem := range p.Validate() {
+			warnings = append(warnings, fmt.Sprintf("Gerrit config for %s was invalid: %s", p.ServiceID(), problem))
+		}
+	}
+
+	return ps, problems, warnings
+}
+
+func newAuthzProvider(c *types.GerritConnection) (authz.Provider, error) {
+	if c.Authorization == nil {
+		return nil, nil
+	}
+
+	baseURL, err := url.Parse(c.Url)
+	if err != nil {
+		return nil, err
+	}
+	baseURL = extsvc.NormalizeBaseURL(baseURL)
+
+	cli := gerrit.NewClient(baseURL, nil)
+	cli.Username = c.Username
+	cli.Password = c.Password
+
+	return NewProvider(c.URN, cli), nil
+}
+
+// ValidateAuthz validates the authorization fields of the given Gerrit
+// external service config.
+func ValidateAuthz(cfg *schema.GerritConnection) error {
+	_, err := newAuthzProvider(&types.GerritConnection{GerritConnection: cfg})
+	return err
+}
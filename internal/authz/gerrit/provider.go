@@ -0,0 +1,180 @@
+// Package gerrit contains an authorization provider for Gerrit.
+package gerrit
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	otlog "github.com/opentracing/opentracing-go/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gerrit"
+	"github.com/sourcegraph/sourcegraph/internal/trace"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+// Provider is an implementation of authz.Provider that provides project
+// permissions as determined from a Gerrit instance API.
+type Provider struct {
+	urn      string
+	client   *gerrit.Client
+	codeHost *extsvc.CodeHost
+}
+
+var _ authz.Provider = (*Provider)(nil)
+
+// NewProvider returns a new Gerrit authorization provider that uses the given
+// gerrit.Client to talk to a Gerrit API that is the source of truth for
+// permissions. It assumes usernames of Sourcegraph accounts match 1-1 with
+// usernames of Gerrit accounts. The client's account must have the "Run As"
+// global capability so that it can make requests on behalf of other users.
+func NewProvider(urn string, cli *gerrit.Client) *Provider {
+	return &Provider{
+		urn:      urn,
+		client:   cli,
+		codeHost: extsvc.NewCodeHost(cli.URL, extsvc.TypeGerrit),
+	}
+}
+
+// Validate validates that the Provider has access to the Gerrit API with the
+// credentials it was configured with, and that its account is allowed to make
+// requests on behalf of other users.
+func (p *Provider) Validate() []string {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	ok, err := p.client.HasCapability(ctx, "runAs")
+	if err != nil {
+		return []string{err.Error()}
+	}
+
+	if !ok {
+		return []string{`the account must have the "Run As" global capability to sync permissions`}
+	}
+
+	return nil
+}
+
+func (p *Provider) URN() string {
+	return p.urn
+}
+
+// ServiceID returns the absolute URL that identifies the Gerrit instance this
+// provider is configured with.
+func (p *Provider) ServiceID() string { return p.codeHost.ServiceID }
+
+// ServiceType returns the type of this Provider, namely, "gerrit".
+func (p *Provider) ServiceType() string { return p.codeHost.ServiceType }
+
+// FetchAccount returns the Gerrit account whose username matches the username
+// of the given user. It returns nil if there is no such account.
+func (p *Provider) FetchAccount(ctx context.Context, user *types.User, _ []*extsvc.Account, _ []string) (acct *extsvc.Account, err error) {
+	if user == nil {
+		return nil, nil
+	}
+
+	tr, ctx := trace.New(ctx, "gerrit.authz.provider.FetchAccount", "")
+	defer func() {
+		tr.LogFields(
+			otlog.String("user.name", user.Username),
+			otlog.Int32("user.id", user.ID),
+		)
+
+		if err != nil {
+			tr.SetError(err)
+		}
+
+		tr.Finish()
+	}()
+
+	gerritAccount, err := p.client.GetAccount(ctx, user.Username)
+	if errcode.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	accountData, err := json.Marshal(gerritAccount)
+	if err != nil {
+		return nil, err
+	}
+
+	return &extsvc.Account{
+		UserID: user.ID,
+		AccountSpec: extsvc.AccountSpec{
+			ServiceType: p.codeHost.ServiceType,
+			ServiceID:   p.codeHost.ServiceID,
+			AccountID:   strconv.FormatInt(gerritAccount.ID, 10),
+		},
+		AccountData: extsvc.AccountData{
+			Data: (*json.RawMessage)(&accountData),
+		},
+	}, nil
+}
+
+// FetchUserPerms returns a list of project IDs (on code host) that the given
+// account has read access on the code host. The project ID has the same value
+// as it would be used as api.ExternalRepoSpec.ID. Since all Gerrit projects are
+// treated as private, the returned list includes all projects visible to the
+// account.
+//
+// This method may return partial but valid results in case of error, and it is
+// up to callers to decide whether to discard.
+//
+// API docs: https://gerrit-review.googlesource.com/Documentation/rest-api-projects.html#query-projects
+func (p *Provider) FetchUserPerms(ctx context.Context, account *extsvc.Account) (*authz.ExternalUserPermissions, error) {
+	switch {
+	case account == nil:
+		return nil, errors.New("no account provided")
+	case account.Data == nil:
+		return nil, errors.New("no account data provided")
+	case !extsvc.IsHostOfAccount(p.codeHost, account):
+		return nil, errors.Errorf("not a code host of the account: want %q but have %q",
+			p.codeHost.ServiceID, account.AccountSpec.ServiceID)
+	}
+
+	var gerritAccount gerrit.Account
+	if err := json.Unmarshal(*account.Data, &gerritAccount); err != nil {
+		return nil, errors.Wrap(err, "unmarshaling account data")
+	}
+
+	c := p.client.RunAs(gerritAccount.Username)
+	perms := &authz.ExternalUserPermissions{}
+
+	t := &gerrit.PageToken{}
+	for {
+		projects, next, err := c.ListProjects(ctx, t)
+		if err != nil {
+			return perms, err
+		}
+
+		for _, project := range projects {
+			perms.Exacts = append(perms.Exacts, extsvc.RepoID(project.ID))
+		}
+
+		if !next.HasMore() {
+			break
+		}
+		t = next
+	}
+
+	return perms, nil
+}
+
+// FetchUserPermsByToken is currently only required for syncing permissions for
+// GitHub and GitLab on sourcegraph.com
+func (p *Provider) FetchUserPermsByToken(ctx context.Context, token string) (*authz.ExternalUserPermissions, error) {
+	return nil, errors.New("not implemented")
+}
+
+// FetchRepoPerms is unimplemented because Gerrit has no API to list the
+// accounts that have read access to a project. Project permissions are only
+// synced from the user side.
+func (p *Provider) FetchRepoPerms(ctx context.Context, repo *extsvc.Repository) ([]extsvc.AccountID, error) {
+	return nil, authz.ErrUnimplemented{Feature: "gerrit.FetchRepoPerms"}
+}
@@ -0,0 +1,111 @@
+package gerrit
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gerrit"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func TestProvider(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, _ := r.BasicAuth(); user != "admin" || pass != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		runAs := r.Header.Get("X-Gerrit-RunAs")
+		switch r.URL.Path {
+		case "/a/accounts/self/capabilities":
+			_, _ = w.Write([]byte(")]}'\n{\"runAs\": true}"))
+		case "/a/accounts/alice":
+			_, _ = w.Write([]byte(")]}'\n{\"_account_id\": 1000001, \"username\": \"alice\"}"))
+		case "/a/projects/":
+			if runAs != "alice" {
+				t.Errorf("unexpected X-Gerrit-RunAs header %q", runAs)
+			}
+			if r.URL.Query().Get("S") == "0" {
+				_, _ = w.Write([]byte(")]}'\n[{\"id\": \"platform%2Fbuild\", \"name\": \"platform/build\"}, {\"id\": \"tools%2Frepo\", \"name\": \"tools/repo\", \"_more_projects\": true}]"))
+				return
+			}
+			_, _ = w.Write([]byte(")]}'\n[{\"id\": \"archived%2Flegacy\", \"name\": \"archived/legacy\"}]"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	baseURL, _ := url.Parse(srv.URL)
+	baseURL = extsvc.NormalizeBaseURL(baseURL)
+	cli := gerrit.NewClient(baseURL, nil)
+	cli.Username = "admin"
+	cli.Password = "secret"
+	p := NewProvider("extsvc:gerrit:1", cli)
+
+	ctx := context.Background()
+
+	t.Run("Validate", func(t *testing.T) {
+		if problems := p.Validate(); len(problems) > 0 {
+			t.Fatalf("unexpected problems: %v", problems)
+		}
+	})
+
+	t.Run("FetchAccount", func(t *testing.T) {
+		acct, err := p.FetchAccount(ctx, &types.User{ID: 42, Username: "alice"}, nil, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if acct == nil || acct.AccountID != "1000001" || acct.UserID != 42 {
+			t.Fatalf("unexpected account: %+v", acct)
+		}
+
+		acct, err = p.FetchAccount(ctx, &types.User{ID: 43, Username: "mallory"}, nil, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if acct != nil {
+			t.Fatalf("expected no account, got %+v", acct)
+		}
+	})
+
+	t.Run("FetchUserPerms", func(t *testing.T) {
+		acct, err := p.FetchAccount(ctx, &types.User{ID: 42, Username: "alice"}, nil, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		perms, err := p.FetchUserPerms(ctx, acct)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := &authz.ExternalUserPermissions{
+			Exacts: []extsvc.RepoID{"platform%2Fbuild", "tools%2Frepo", "archived%2Flegacy"},
+		}
+		if diff := cmp.Diff(want, perms); diff != "" {
+			t.Fatalf("mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("FetchRepoPerms", func(t *testing.T) {
+		_, err := p.FetchRepoPerms(ctx, &extsvc.Repository{
+			ExternalRepoSpec: api.ExternalRepoSpec{
+				ID:          "platform%2Fbuild",
+				ServiceType: extsvc.TypeGerrit,
+				ServiceID:   baseURL.String(),
+			},
+		})
+		if !authz.IsUnimplemented(err) {
+			t.Fatalf("expected unimplemented error, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,260 @@
+// Package gitea contains an authorization provider for Gitea.
+package gitea
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	otlog "github.com/opentracing/opentracing-go/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitea"
+	"github.com/sourcegraph/sourcegraph/internal/trace"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+// Provider is an implementation of authz.Provider that provides repository
+// permissions as determined from a Gitea instance API.
+type Provider struct {
+	urn      string
+	client   *gitea.Client
+	codeHost *extsvc.CodeHost
+}
+
+var _ authz.Provider = (*Provider)(nil)
+
+// NewProvider returns a new Gitea authorization provider that uses the given
+// gitea.Client to talk to a Gitea API that is the source of truth for
+// permissions. It assumes usernames of Sourcegraph accounts match 1-1 with
+// usernames of Gitea users. The client's token must belong to a site admin so
+// that it can make requests on behalf of other users.
+func NewProvider(urn string, cli *gitea.Client) *Provider {
+	return &Provider{
+		urn:      urn,
+		client:   cli,
+		codeHost: extsvc.NewCodeHost(cli.URL, extsvc.TypeGitea),
+	}
+}
+
+// Validate validates that the Provider has access to the Gitea API with the
+// token it was configured with, and that the token belongs to a site admin.
+func (p *Provider) Validate() []string {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	u, err := p.client.GetAuthenticatedUser(ctx)
+	if err != nil {
+		return []string{err.Error()}
+	}
+
+	if !u.IsAdmin {
+		return []string{"the token must belong to a site admin to sync permissions"}
+	}
+
+	return nil
+}
+
+func (p *Provider) URN() string {
+	return p.urn
+}
+
+// ServiceID returns the absolute URL that identifies the Gitea instance this
+// provider is configured with.
+func (p *Provider) ServiceID() string { return p.codeHost.ServiceID }
+
+// ServiceType returns the type of this Provider, namely, "gitea".
+func (p *Provider) ServiceType() string { return p.codeHost.ServiceType }
+
+// FetchAccount returns the Gitea account whose username matches the username
+// of the given user. It returns nil if there is no such account.
+func (p *Provider) FetchAccount(ctx context.Context, user *types.User, _ []*extsvc.Account, _ []string) (acct *extsvc.Account, err error) {
+	if user == nil {
+		return nil, nil
+	}
+
+	tr, ctx := trace.New(ctx, "gitea.authz.provider.FetchAccount", "")
+	defer func() {
+		tr.LogFields(
+			otlog.String("user.name", user.Username),
+			otlog.Int32("user.id", user.ID),
+		)
+
+		if err != nil {
+			tr.SetError(err)
+		}
+
+		tr.Finish()
+	}()
+
+	giteaUser, err := p.client.GetUser(ctx, user.Username)
+	if errcode.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	accountData, err := json.Marshal(giteaUser)
+	if err != nil {
+		return nil, err
+	}
+
+	return &extsvc.Account{
+		UserID: user.ID,
+		AccountSpec: extsvc.AccountSpec{
+			ServiceType: p.codeHost.ServiceType,
+			ServiceID:   p.codeHost.ServiceID,
+			AccountID:   strconv.FormatInt(giteaUser.ID, 10),
+		},
+		AccountData: extsvc.AccountData{
+			Data: (*json.RawMessage)(&accountData),
+		},
+	}, nil
+}
+
+// FetchUserPerms returns a list of repository IDs (on code host) that the given
+// account has read access on the code host. The repository ID has the same
+// value as it would be used as api.ExternalRepoSpec.ID. The returned list only
+// includes private repository IDs.
+//
+// This method may return partial but valid results in case of error, and it is
+// up to callers to decide whether to discard.
+//
+// API docs: https://try.gitea.io/api/swagger#/repository/repoSearch
+func (p *Provider) FetchUserPerms(ctx context.Context, account *extsvc.Account) (*authz.ExternalUserPermissions, error) {
+	switch {
+	case account == nil:
+		return nil, errors.New("no account provided")
+	case account.Data == nil:
+		return nil, errors.New("no account data provided")
+	case !extsvc.IsHostOfAccount(p.codeHost, account):
+		return nil, errors.Errorf("not a code host of the account: want %q but have %q",
+			p.codeHost.ServiceID, account.AccountSpec.ServiceID)
+	}
+
+	var user gitea.User
+	if err := json.Unmarshal(*account.Data, &user); err != nil {
+		return nil, errors.Wrap(err, "unmarshaling account data")
+	}
+
+	c := p.client.Sudo(user.Login)
+	perms := &authz.ExternalUserPermissions{}
+
+	t := &gitea.PageToken{}
+	for {
+		repos, next, err := c.SearchRepos(ctx, t)
+		if err != nil {
+			return perms, err
+		}
+
+		for _, r := range repos {
+			if r.Private {
+				perms.Exacts = append(perms.Exacts, extsvc.RepoID(strconv.FormatInt(r.ID, 10)))
+			}
+		}
+
+		if !next.HasMore() {
+			break
+		}
+		t = next
+	}
+
+	return perms, nil
+}
+
+// FetchUserPermsByToken is currently only required for syncing permissions for
+// GitHub and GitLab on sourcegraph.com
+func (p *Provider) FetchUserPermsByToken(ctx context.Context, token string) (*authz.ExternalUserPermissions, error) {
+	return nil, errors.New("not implemented")
+}
+
+// FetchRepoPerms returns a list of user IDs (on code host) who have read access
+// to the given repo on the code host. The user ID has the same value as it
+// would be used as extsvc.Account.AccountID. The returned list includes the
+// owner and collaborators of the repository, as well as the members of the
+// organization teams that have access to it.
+//
+// This method may return partial but valid results in case of error, and it is
+// up to callers to decide whether to discard.
+func (p *Provider) FetchRepoPerms(ctx context.Context, repo *extsvc.Repository) ([]extsvc.AccountID, error) {
+	switch {
+	case repo == nil:
+		return nil, errors.New("no repo provided")
+	case !extsvc.IsHostOfRepo(p.codeHost, &repo.ExternalRepoSpec):
+		return nil, errors.Errorf("not a code host of the repo: want %q but have %q",
+			p.codeHost.ServiceID, repo.ServiceID)
+	}
+
+	id, err := strconv.ParseInt(repo.ID, 10, 64)
+	if err != nil {
+		return nil, errors.Wrap(err, "parse repository ID")
+	}
+
+	r, err := p.client.GetRepoByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []extsvc.AccountID
+	seen := make(map[int64]bool)
+	add := func(users ...*gitea.User) {
+		for _, u := range users {
+			if !seen[u.ID] {
+				ids = append(ids, extsvc.AccountID(strconv.FormatInt(u.ID, 10)))
+				seen[u.ID] = true
+			}
+		}
+	}
+
+	// Repositories owned by a user are accessible to the user, whereas
+	// repositories owned by an organization are accessible to the members of
+	// its teams that have been granted access.
+	_, err = p.client.GetOrg(ctx, r.Owner.Login)
+	switch {
+	case errcode.IsNotFound(err):
+		add(r.Owner)
+	case err != nil:
+		return ids, err
+	default:
+		teams, err := p.client.ListRepoTeams(ctx, r.Owner.Login, r.Name)
+		if err != nil {
+			return ids, err
+		}
+
+		for _, team := range teams {
+			t := &gitea.PageToken{}
+			for {
+				members, next, err := p.client.ListTeamMembers(ctx, team.ID, t)
+				if err != nil {
+					return ids, err
+				}
+				add(members...)
+
+				if !next.HasMore() {
+					break
+				}
+				t = next
+			}
+		}
+	}
+
+	t := &gitea.PageToken{}
+	for {
+		collaborators, next, err := p.client.ListCollaborators(ctx, r.Owner.Login, r.Name, t)
+		if err != nil {
+			return ids, err
+		}
+		add(collaborators...)
+
+		if !next.HasMore() {
+			break
+		}
+		t = next
+	}
+
+	return ids, nil
+}
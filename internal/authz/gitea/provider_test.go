@@ -0,0 +1,141 @@
+package gitea
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitea"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func TestProvider(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "token secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		sudo := r.Header.Get("Sudo")
+		switch r.URL.Path {
+		case "/api/v1/user":
+			writeJSON(w, gitea.User{ID: 1, Login: "admin", IsAdmin: true})
+		case "/api/v1/users/alice":
+			writeJSON(w, gitea.User{ID: 2, Login: "alice"})
+		case "/api/v1/repos/search":
+			if sudo != "alice" {
+				t.Errorf("unexpected Sudo header %q", sudo)
+			}
+			if r.URL.Query().Get("page") == "1" {
+				w.Header().Set("Link", `<`+r.URL.Path+`?page=2>; rel="next"`)
+				writeJSON(w, map[string]interface{}{"ok": true, "data": []gitea.Repo{{ID: 10, Private: true}, {ID: 11}}})
+				return
+			}
+			writeJSON(w, map[string]interface{}{"ok": true, "data": []gitea.Repo{{ID: 12, Private: true}}})
+		case "/api/v1/repositories/10":
+			writeJSON(w, gitea.Repo{ID: 10, Owner: &gitea.User{ID: 5, Login: "acme"}, Name: "secret", FullName: "acme/secret"})
+		case "/api/v1/repositories/12":
+			writeJSON(w, gitea.Repo{ID: 12, Owner: &gitea.User{ID: 2, Login: "alice"}, Name: "notes", FullName: "alice/notes"})
+		case "/api/v1/orgs/acme":
+			writeJSON(w, gitea.User{ID: 5, Login: "acme"})
+		case "/api/v1/repos/acme/secret/teams":
+			writeJSON(w, []gitea.Team{{ID: 7, Name: "Owners"}})
+		case "/api/v1/teams/7/members":
+			writeJSON(w, []gitea.User{{ID: 1, Login: "admin"}, {ID: 3, Login: "bob"}})
+		case "/api/v1/repos/acme/secret/collaborators":
+			writeJSON(w, []gitea.User{{ID: 3, Login: "bob"}, {ID: 4, Login: "carol"}})
+		case "/api/v1/repos/alice/notes/collaborators":
+			writeJSON(w, []gitea.User{})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	baseURL, _ := url.Parse(srv.URL)
+	baseURL = extsvc.NormalizeBaseURL(baseURL)
+	p := NewProvider("extsvc:gitea:1", gitea.NewClient(baseURL, "secret", nil))
+
+	ctx := context.Background()
+
+	t.Run("Validate", func(t *testing.T) {
+		if problems := p.Validate(); len(problems) > 0 {
+			t.Fatalf("unexpected problems: %v", problems)
+		}
+	})
+
+	t.Run("FetchAccount", func(t *testing.T) {
+		acct, err := p.FetchAccount(ctx, &types.User{ID: 42, Username: "alice"}, nil, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if acct == nil || acct.AccountID != "2" || acct.UserID != 42 {
+			t.Fatalf("unexpected account: %+v", acct)
+		}
+
+		acct, err = p.FetchAccount(ctx, &types.User{ID: 43, Username: "mallory"}, nil, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if acct != nil {
+			t.Fatalf("expected no account, got %+v", acct)
+		}
+	})
+
+	t.Run("FetchUserPerms", func(t *testing.T) {
+		acct, err := p.FetchAccount(ctx, &types.User{ID: 42, Username: "alice"}, nil, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		perms, err := p.FetchUserPerms(ctx, acct)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := &authz.ExternalUserPermissions{Exacts: []extsvc.RepoID{"10", "12"}}
+		if diff := cmp.Diff(want, perms); diff != "" {
+			t.Fatalf("mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("FetchRepoPerms", func(t *testing.T) {
+		for _, tc := range []struct {
+			name string
+			id   string
+			want []extsvc.AccountID
+		}{
+			{name: "org repo", id: "10", want: []extsvc.AccountID{"1", "3", "4"}},
+			{name: "user repo", id: "12", want: []extsvc.AccountID{"2"}},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				ids, err := p.FetchRepoPerms(ctx, &extsvc.Repository{
+					ExternalRepoSpec: api.ExternalRepoSpec{
+						ID:          tc.id,
+						ServiceType: extsvc.TypeGitea,
+						ServiceID:   baseURL.String(),
+					},
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+				if diff := cmp.Diff(tc.want, ids); diff != "" {
+					t.Fatalf("mismatch (-want +got):\n%s", diff)
+				}
+			})
+		}
+	})
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
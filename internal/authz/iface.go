@@ -5,6 +5,8 @@ package authz
 import (
 	"context"
 
+	"github.com/cockroachdb/errors"
+
 	"github.com/sourcegraph/sourcegraph/internal/extsvc"
 	"github.com/sourcegraph/sourcegraph/internal/types"
 )
@@ -88,3 +90,22 @@ type Provider interface {
 	// problems.
 	Validate() (problems []string)
 }
+
+// ErrUnimplemented is returned by a Provider when the code host does not
+// support an operation, e.g. Gerrit has no API to list the users who have read
+// access to a project.
+type ErrUnimplemented struct {
+	// Feature is the name of the unimplemented operation.
+	Feature string
+}
+
+func (e ErrUnimplemented) Error() string {
+	return e.Feature + " is unimplemented"
+}
+
+// IsUnimplemented reports whether err or one of its causes is an
+// ErrUnimplemented.
+func IsUnimplemented(err error) bool {
+	var e ErrUnimplemented
+	return errors.As(err, &e)
+}
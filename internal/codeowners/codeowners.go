@@ -0,0 +1,200 @@
+// Package codeowners parses CODEOWNERS files and answers ownership questions
+// about paths in a repository.
+package codeowners
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+)
+
+// Paths are the locations, relative to the repository root, that are checked
+// for a CODEOWNERS file. The first file found wins.
+var Paths = []string{
+	"CODEOWNERS",
+	".github/CODEOWNERS",
+	".gitlab/CODEOWNERS",
+	"docs/CODEOWNERS",
+}
+
+// maxFileSize is the largest CODEOWNERS file we are willing to read. GitHub
+// ignores CODEOWNERS files larger than 3MB, so we do too.
+const maxFileSize = 3 * 1024 * 1024
+
+// Rule is a single line of a CODEOWNERS file.
+type Rule struct {
+	Pattern string
+	Owners  []string
+
+	re *regexp.Regexp
+}
+
+// Match returns true if the rule's pattern matches path.
+func (r *Rule) Match(path string) bool {
+	return r.re.MatchString(strings.TrimPrefix(path, "/"))
+}
+
+// Ruleset is a parsed CODEOWNERS file. The zero value owns nothing.
+type Ruleset struct {
+	Rules []*Rule
+}
+
+// Parse parses the contents of a CODEOWNERS file. Blank lines and comments are
+// skipped. Lines without owners are kept, since they explicitly unassign
+// ownership of the matching paths.
+func Parse(r io.Reader) (*Ruleset, error) {
+	var rs Ruleset
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		re, err := compilePattern(fields[0])
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid CODEOWNERS pattern %q", fields[0])
+		}
+		rs.Rules = append(rs.Rules, &Rule{
+			Pattern: fields[0],
+			Owners:  fields[1:],
+			re:      re,
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return &rs, nil
+}
+
+// Owners returns the owners of path. As with GitHub, the last matching rule
+// takes precedence.
+func (rs *Ruleset) Owners(path string) []string {
+	if rs == nil {
+		return nil
+	}
+	for i := len(rs.Rules) - 1; i >= 0; i-- {
+		if rs.Rules[i].Match(path) {
+			return rs.Rules[i].Owners
+		}
+	}
+	return nil
+}
+
+// IsOwnedBy returns true if owner is one of the owners of path. Owners are
+// compared case-insensitively, and a leading @ is optional.
+func (rs *Ruleset) IsOwnedBy(path, owner string) bool {
+	owner = normalizeOwner(owner)
+	for _, o := range rs.Owners(path) {
+		if normalizeOwner(o) == owner {
+			return true
+		}
+	}
+	return false
+}
+
+// OwnedPatterns returns the regular expressions of the rules that list owner,
+// in the syntax of package regexp. Every path owned by owner matches one of
+// them, but a matching path is not owned if a later rule matches it too.
+func (rs *Ruleset) OwnedPatterns(owner string) []string {
+	if rs == nil {
+		return nil
+	}
+	owner = normalizeOwner(owner)
+	var patterns []string
+	for _, r := range rs.Rules {
+		for _, o := range r.Owners {
+			if normalizeOwner(o) == owner {
+				patterns = append(patterns, r.re.String())
+				break
+			}
+		}
+	}
+	return patterns
+}
+
+func normalizeOwner(owner string) string {
+	return strings.ToLower(strings.TrimPrefix(owner, "@"))
+}
+
+// Read finds and parses the CODEOWNERS file of repo at commit. If the
+// repository has no CODEOWNERS file, an empty ruleset is returned.
+func Read(ctx context.Context, repo api.RepoName, commit api.CommitID) (*Ruleset, error) {
+	for _, path := range Paths {
+		content, err := git.ReadFile(ctx, repo, commit, path, maxFileSize)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return Parse(strings.NewReader(string(content)))
+	}
+	return &Ruleset{}, nil
+}
+
+// compilePattern converts a CODEOWNERS pattern, which follows the gitignore
+// syntax, into a regular expression matching paths relative to the
+// repository root.
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	// A pattern is anchored to the root if it contains a slash anywhere but at
+	// the end. Otherwise it matches at any depth.
+	anchored := strings.Contains(strings.TrimSuffix(pattern, "/"), "/")
+	pattern = strings.TrimPrefix(pattern, "/")
+
+	// A trailing slash only matches directories, which for us means
+	// everything below it.
+	dirOnly := strings.HasSuffix(pattern, "/")
+	pattern = strings.TrimSuffix(pattern, "/")
+
+	var b strings.Builder
+	b.WriteString("^")
+	if !anchored {
+		b.WriteString("(?:.*/)?")
+	}
+
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch {
+		case c == '*' && strings.HasPrefix(pattern[i:], "**/"):
+			b.WriteString("(?:.*/)?")
+			i += 2
+		case c == '*' && strings.HasPrefix(pattern[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case c == '*':
+			b.WriteString("[^/]*")
+		case c == '?':
+			b.WriteString("[^/]")
+		case c == '\\' && i+1 < len(pattern):
+			i++
+			b.WriteString(regexp.QuoteMeta(string(pattern[i])))
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+
+	switch {
+	case dirOnly:
+		b.WriteString("/.*$")
+	case strings.HasSuffix(pattern, "/*"):
+		// GitHub documents that `docs/*` does not match nested files.
+		b.WriteString("$")
+	default:
+		// A pattern matching a directory also matches everything below it.
+		b.WriteString("(?:/.*)?$")
+	}
+	return regexp.Compile(b.String())
+}
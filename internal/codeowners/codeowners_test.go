@@ -0,0 +1,120 @@
+package codeowners
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+const testCodeowners = `
+# This is a comment.
+*                 @global-owner
+*.js              @js-owner # trailing comment
+/build/logs/      @doctocat
+docs/*            docs@example.com
+apps/             @octocat
+/scripts/**/*.sh  @org/infra
+/apps/github
+`
+
+func TestRuleset_Owners(t *testing.T) {
+	rs, err := Parse(strings.NewReader(testCodeowners))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path string
+		want []string
+	}{
+		{"README.md", []string{"@global-owner"}},
+		{"client/index.js", []string{"@js-owner"}},
+		{"build/logs/out.txt", []string{"@doctocat"}},
+		{"nested/build/logs/out.txt", []string{"@global-owner"}},
+		{"docs/getting-started.md", []string{"docs@example.com"}},
+		{"docs/build-app/troubleshooting.md", []string{"@global-owner"}},
+		{"sub/apps/main.go", []string{"@octocat"}},
+		{"scripts/deploy.sh", []string{"@org/infra"}},
+		{"scripts/ci/deep/deploy.sh", []string{"@org/infra"}},
+		{"apps/github/main.go", []string{}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.path, func(t *testing.T) {
+			if have := rs.Owners(tc.path); !reflect.DeepEqual(have, tc.want) {
+				t.Fatalf("unexpected owners: want %v, have %v", tc.want, have)
+			}
+		})
+	}
+}
+
+func TestRuleset_IsOwnedBy(t *testing.T) {
+	rs, err := Parse(strings.NewReader(testCodeowners))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path  string
+		owner string
+		want  bool
+	}{
+		{"scripts/deploy.sh", "@org/infra", true},
+		{"scripts/deploy.sh", "org/infra", true},
+		{"scripts/deploy.sh", "@ORG/Infra", true},
+		{"scripts/deploy.sh", "@global-owner", false},
+		{"docs/index.md", "docs@example.com", true},
+		{"apps/github/main.go", "@octocat", false},
+	}
+
+	for _, tc := range tests {
+		if have := rs.IsOwnedBy(tc.path, tc.owner); have != tc.want {
+			t.Errorf("IsOwnedBy(%q, %q): want %v, have %v", tc.path, tc.owner, tc.want, have)
+		}
+	}
+
+	var empty *Ruleset
+	if empty.IsOwnedBy("README.md", "@global-owner") {
+		t.Error("expected nil ruleset to own nothing")
+	}
+}
+
+func TestRuleset_OwnedPatterns(t *testing.T) {
+	rs, err := Parse(strings.NewReader(testCodeowners))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		owner   string
+		matches []string
+		misses  []string
+	}{
+		{"org/infra", []string{"scripts/deploy.sh", "scripts/ci/deploy.sh"}, []string{"deploy.sh", "scripts/README.md"}},
+		{"@octocat", []string{"apps/main.go", "sub/apps/main.go", "apps/github/main.go"}, []string{"main.go"}},
+		{"@nobody", nil, []string{"README.md"}},
+	}
+
+	for _, tc := range tests {
+		patterns := rs.OwnedPatterns(tc.owner)
+		matchesAny := func(path string) bool {
+			for _, p := range patterns {
+				if regexp.MustCompile(p).MatchString(path) {
+					return true
+				}
+			}
+			return false
+		}
+		for _, path := range tc.matches {
+			if !matchesAny(path) {
+				t.Errorf("OwnedPatterns(%q) = %v: expected a pattern to match %q", tc.owner, patterns, path)
+			}
+		}
+		for _, path := range tc.misses {
+			if matchesAny(path) {
+				t.Errorf("OwnedPatterns(%q) = %v: expected no pattern to match %q", tc.owner, patterns, path)
+			}
+		}
+	}
+}
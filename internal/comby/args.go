@@ -40,6 +40,8 @@ func (args Args) String() string {
 		s = append(s, "-zip", string(i))
 	case DirPath:
 		s = append(s, "-directory", string(i))
+	case Tar:
+		s = append(s, "-tar")
 	default:
 		s = append(s, fmt.Sprintf("~comby mccombyface is sad and can't handle type %T~", i))
 		log15.Error("unrecognized input type: %T", i)
@@ -1,6 +1,7 @@
 package comby
 
 import (
+	"archive/tar"
 	"bufio"
 	"bytes"
 	"context"
@@ -59,6 +60,8 @@ func rawArgs(args Args) (rawArgs []string) {
 		rawArgs = append(rawArgs, "-zip", string(i))
 	case DirPath:
 		rawArgs = append(rawArgs, "-directory", string(i))
+	case Tar:
+		rawArgs = append(rawArgs, "-tar")
 	default:
 		log15.Error("unrecognized input type", "type", i)
 		panic("unreachable")
@@ -109,6 +112,46 @@ func kill(pid int) {
 	_ = syscall.Kill(-pid, syscall.SIGKILL)
 }
 
+// writeTar writes the files received on c to w as a tar archive, closing w
+// when c is closed or ctx is done. If writing fails (e.g. because comby
+// exited early), the remaining files are discarded so that senders are not
+// blocked.
+func writeTar(ctx context.Context, w io.WriteCloser, c <-chan TarInputEvent) {
+	defer w.Close()
+
+	tw := tar.NewWriter(w)
+	var err error
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case e, ok := <-c:
+			if !ok {
+				if err == nil {
+					if err := tw.Close(); err != nil {
+						log15.Error("failed to close comby tar input", "error", err.Error())
+					}
+				}
+				return
+			}
+			if err != nil {
+				continue
+			}
+			hdr := &tar.Header{
+				Name: e.Path,
+				Mode: 0600,
+				Size: int64(len(e.Content)),
+			}
+			if err = tw.WriteHeader(hdr); err == nil {
+				_, err = tw.Write(e.Content)
+			}
+			if err != nil {
+				log15.Error("failed to write comby tar input", "error", err.Error())
+			}
+		}
+	}
+}
+
 func PipeTo(ctx context.Context, args Args, w io.Writer) (err error) {
 	if !exists() {
 		log15.Error("comby is not installed (it could not be found on the PATH)")
@@ -125,6 +168,14 @@ func PipeTo(ctx context.Context, args Args, w io.Writer) (err error) {
 	// Ensure forked child processes are killed
 	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
 
+	var stdin io.WriteCloser
+	if _, ok := args.Input.(Tar); ok {
+		stdin, err = cmd.StdinPipe()
+		if err != nil {
+			log15.Error("could not connect to comby command stdin", "error", err.Error())
+			return errors.Wrap(err, "failed to connect to comby command stdin")
+		}
+	}
 	stdout, err := cmd.StdoutPipe()
 	if err != nil {
 		log15.Error("could not connect to comby command stdout", "error", err.Error())
@@ -141,6 +192,10 @@ func PipeTo(ctx context.Context, args Args, w io.Writer) (err error) {
 		return errors.Wrap(err, "failed to start comby command")
 	}
 
+	if tarInput, ok := args.Input.(Tar); ok {
+		go writeTar(ctx, stdin, tarInput.TarInputEventC)
+	}
+
 	errorC := make(chan error, 1)
 	go func() {
 		errorC <- waitForCompletion(cmd, stdout, stderr, w)
@@ -148,8 +203,12 @@ func PipeTo(ctx context.Context, args Args, w io.Writer) (err error) {
 
 	select {
 	case <-ctx.Done():
-		log15.Error("comby context deadline reached")
+		log15.Error("comby context done", "error", ctx.Err())
 		kill(cmd.Process.Pid)
+		// Wait for the output to be drained so that nothing is written to w
+		// after we return.
+		<-errorC
+		return ctx.Err()
 	case err := <-errorC:
 		if err != nil {
 			err = errors.Wrap(err, "failed to wait for executing comby command")
@@ -161,42 +220,131 @@ func PipeTo(ctx context.Context, args Args, w io.Writer) (err error) {
 	return nil
 }
 
-// Matches returns all matches in all files for which comby finds matches.
-func Matches(ctx context.Context, args Args) (matches []FileMatch, err error) {
-	span, ctx := ot.StartSpanFromContext(ctx, "Comby.Matches")
+// StreamMatches runs comby and calls send for each file in which comby finds
+// matches, as soon as comby reports it. If ctx is done, the comby process is
+// killed and the context error is returned.
+func StreamMatches(ctx context.Context, args Args, send func(FileMatch)) error {
+	span, ctx := ot.StartSpanFromContext(ctx, "Comby.StreamMatches")
 	defer span.Finish()
 
-	b := new(bytes.Buffer)
-	w := bufio.NewWriter(b)
-
 	args.MatchOnly = true
 
-	err = PipeTo(ctx, args, w)
-	if err != nil {
-		return nil, err
-	}
+	pr, pw := io.Pipe()
+	errC := make(chan error, 1)
+	go func() {
+		err := PipeTo(ctx, args, pw)
+		pw.CloseWithError(err)
+		errC <- err
+	}()
 
-	scanner := bufio.NewScanner(b)
+	numMatches := 0
+	scanner := bufio.NewScanner(pr)
 	// increase the scanner buffer size for potentially long lines
 	scanner.Buffer(make([]byte, 100), 10*bufio.MaxScanTokenSize)
 	for scanner.Scan() {
-		b := scanner.Bytes()
-		if err := scanner.Err(); err != nil {
-			// warn on scanner errors and skip
-			log15.Warn("comby error: skipping scanner error line", "err", err.Error())
-			continue
-		}
 		var m *FileMatch
-		if err := json.Unmarshal(b, &m); err != nil {
+		if err := json.Unmarshal(scanner.Bytes(), &m); err != nil {
 			// warn on decode errors and skip
 			log15.Warn("comby error: skipping unmarshaling error", "err", err.Error())
 			continue
 		}
-		matches = append(matches, *m)
+		numMatches++
+		send(*m)
+	}
+	if err := scanner.Err(); err != nil {
+		// Unblock comby's output so that PipeTo can return.
+		pr.CloseWithError(err)
+		if pipeErr := <-errC; pipeErr != nil {
+			return pipeErr
+		}
+		return errors.Wrap(err, "failed to read comby output")
+	}
+
+	if numMatches > 0 {
+		log15.Info("comby invocation", "num_matches", strconv.Itoa(numMatches))
+	}
+	return <-errC
+}
+
+// StreamRewrites runs comby with args.RewriteTemplate and calls send with the
+// diff of each file comby rewrites, as soon as comby reports it. The files
+// themselves are not modified. If ctx is done, the comby process is killed and
+// the context error is returned.
+func StreamRewrites(ctx context.Context, args Args, send func(FileDiff)) error {
+	span, ctx := ot.StartSpanFromContext(ctx, "Comby.StreamRewrites")
+	defer span.Finish()
+
+	args.MatchOnly = false
+
+	pr, pw := io.Pipe()
+	errC := make(chan error, 1)
+	go func() {
+		err := PipeTo(ctx, args, pw)
+		pw.CloseWithError(err)
+		errC <- err
+	}()
+
+	r := bufio.NewReader(pr)
+	for {
+		line, skipped, err := readLine(r, maxDiffSize)
+		if skipped {
+			// A single huge diff should not abort the whole search.
+			log15.Warn("comby error: skipping diff larger than limit", "limit", maxDiffSize)
+		} else if len(bytes.TrimSpace(line)) > 0 {
+			var d *FileDiff
+			if err := json.Unmarshal(line, &d); err != nil {
+				// warn on decode errors and skip
+				log15.Warn("comby error: skipping unmarshaling error", "err", err.Error())
+			} else {
+				send(*d)
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			// Unblock comby's output so that PipeTo can return.
+			pr.CloseWithError(err)
+			if pipeErr := <-errC; pipeErr != nil {
+				return pipeErr
+			}
+			return errors.Wrap(err, "failed to read comby output")
+		}
 	}
+	return <-errC
+}
 
-	if len(matches) > 0 {
-		log15.Info("comby invocation", "num_matches", strconv.Itoa(len(matches)))
+// maxDiffSize is the largest line of comby output, i.e. the JSON encoded diff
+// of a single file, that StreamRewrites decodes. Diffs contain whole hunks of
+// context, so this is larger than the default token size of bufio.Scanner.
+const maxDiffSize = 10 * bufio.MaxScanTokenSize
+
+// readLine reads the next line from r. Lines longer than max bytes are
+// consumed but not returned, and skipped is true. err is io.EOF after the
+// last line.
+func readLine(r *bufio.Reader, max int) (line []byte, skipped bool, err error) {
+	for {
+		chunk, err := r.ReadSlice('\n')
+		if !skipped {
+			if len(line)+len(chunk) > max {
+				line, skipped = nil, true
+			} else {
+				line = append(line, chunk...)
+			}
+		}
+		if err != bufio.ErrBufferFull {
+			return line, skipped, err
+		}
+	}
+}
+
+// Matches returns all matches in all files for which comby finds matches.
+func Matches(ctx context.Context, args Args) (matches []FileMatch, err error) {
+	err = StreamMatches(ctx, args, func(m FileMatch) {
+		matches = append(matches, m)
+	})
+	if err != nil {
+		return nil, err
 	}
 	return matches, nil
 }
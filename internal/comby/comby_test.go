@@ -1,12 +1,17 @@
 package comby
 
 import (
+	"archive/tar"
 	"bufio"
 	"bytes"
 	"context"
+	"io"
 	"os"
+	"strings"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
+
 	"github.com/sourcegraph/sourcegraph/internal/testutil"
 )
 
@@ -64,6 +69,65 @@ func main() {
 	}
 }
 
+func TestRewritesInZip(t *testing.T) {
+	// If we are not on CI skip the test if comby is not installed.
+	if os.Getenv("CI") == "" && !exists() {
+		t.Skip("comby is not installed on the PATH. Try running 'bash <(curl -sL get.comby.dev)'.")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	files := map[string]string{
+		"main.go": `package main
+
+import "fmt"
+
+func main() {
+	fmt.Println("Hello foo")
+}
+`,
+		"other.go": `package main
+`,
+	}
+
+	zipPath, cleanup, err := testutil.TempZipFromFiles(files)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	args := Args{
+		Input:           ZipPath(zipPath),
+		MatchTemplate:   "fmt.Println(:[args])",
+		RewriteTemplate: "log.Println(:[args])",
+		FilePatterns:    []string{".go"},
+		Matcher:         ".go",
+	}
+
+	var diffs []FileDiff
+	err = StreamRewrites(ctx, args, func(d FileDiff) {
+		diffs = append(diffs, d)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(diffs) != 1 {
+		t.Fatalf("got %d diffs, want 1: %+v", len(diffs), diffs)
+	}
+	if diffs[0].URI != "main.go" {
+		t.Errorf("got URI %q, want main.go", diffs[0].URI)
+	}
+	for _, line := range []string{
+		`-	fmt.Println("Hello foo")`,
+		`+	log.Println("Hello foo")`,
+	} {
+		if !strings.Contains(diffs[0].Diff, line) {
+			t.Errorf("diff does not contain %q:\n%s", line, diffs[0].Diff)
+		}
+	}
+}
+
 func TestMatchesInZip(t *testing.T) {
 	// If we are not on CI skip the test if comby is not installed.
 	if os.Getenv("CI") == "" && !exists() {
@@ -124,3 +188,102 @@ func main() {
 		}
 	}
 }
+
+func TestWriteTar(t *testing.T) {
+	c := make(chan TarInputEvent, 2)
+	c <- TarInputEvent{Path: "main.go", Content: []byte("package main")}
+	c <- TarInputEvent{Path: "dir/README.md", Content: []byte("# Hello World")}
+	close(c)
+
+	pr, pw := io.Pipe()
+	go writeTar(context.Background(), pw, c)
+
+	got := map[string]string{}
+	tr := tar.NewReader(pr)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := io.ReadAll(tr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got[hdr.Name] = string(b)
+	}
+
+	want := map[string]string{
+		"main.go":       "package main",
+		"dir/README.md": "# Hello World",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected tar contents (-want +got):\n%s", diff)
+	}
+}
+
+func TestStreamMatchesTar(t *testing.T) {
+	// If we are not on CI skip the test if comby is not installed.
+	if os.Getenv("CI") == "" && !exists() {
+		t.Skip("comby is not installed on the PATH. Try running 'bash <(curl -sL get.comby.dev)'.")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	c := make(chan TarInputEvent, 2)
+	c <- TarInputEvent{Path: "README.md", Content: []byte("# Hello World")}
+	c <- TarInputEvent{Path: "main.go", Content: []byte("package main\n\nfunc main() {}\n")}
+	close(c)
+
+	args := Args{
+		Input:         Tar{TarInputEventC: c},
+		MatchTemplate: "func",
+		FilePatterns:  []string{".go"},
+		Matcher:       ".go",
+	}
+
+	var got []string
+	err := StreamMatches(ctx, args, func(m FileMatch) {
+		got = append(got, m.URI)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"main.go"}; !cmp.Equal(want, got) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestReadLine(t *testing.T) {
+	input := "short\n" + strings.Repeat("x", 100) + "\nlast"
+	// Use a buffer smaller than the long line so it is read in chunks.
+	r := bufio.NewReaderSize(strings.NewReader(input), 16)
+
+	type line struct {
+		Line    string
+		Skipped bool
+	}
+	var got []line
+	for {
+		l, skipped, err := readLine(r, 50)
+		got = append(got, line{Line: string(l), Skipped: skipped})
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want := []line{
+		{Line: "short\n"},
+		{Skipped: true},
+		{Line: "last"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected lines (-want +got):\n%s", diff)
+	}
+}
@@ -7,11 +7,27 @@ type Input interface {
 type ZipPath string
 type DirPath string
 
+// Tar is an input that streams files to comby as a tar archive on standard
+// input. Comby processes files as they arrive, until TarInputEventC is
+// closed. Senders must stop sending once the context passed to comby is
+// done.
+type Tar struct {
+	TarInputEventC chan TarInputEvent
+}
+
+// TarInputEvent is a single file written to the tar archive streamed to comby.
+type TarInputEvent struct {
+	Path    string
+	Content []byte
+}
+
 func (ZipPath) Value() {}
 func (DirPath) Value() {}
+func (Tar) Value()     {}
 
 type Args struct {
-	// An Input to process (either a path to a directory or zip file)
+	// An Input to process (either a path to a directory or zip file, or a
+	// stream of files)
 	Input
 
 	// A template pattern that expresses what to match
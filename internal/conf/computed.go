@@ -305,6 +305,12 @@ func EventLoggingEnabled() bool {
 	return val == "enabled"
 }
 
+// GitServerConsistentHashing reports whether repositories are assigned to
+// gitserver instances with consistent hashing.
+func GitServerConsistentHashing() bool {
+	return ExperimentalFeatures().GitServerConsistentHashing
+}
+
 func StructuralSearchEnabled() bool {
 	val := ExperimentalFeatures().StructuralSearch
 	if val == "" {
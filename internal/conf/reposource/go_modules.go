@@ -0,0 +1,91 @@
+package reposource
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+)
+
+// GoModule is a Go module, e.g. "github.com/sourcegraph/go-diff".
+type GoModule struct {
+	Path string
+}
+
+func (m *GoModule) MatchesDependencyString(dependency string) bool {
+	return strings.HasPrefix(dependency, m.Path+"@")
+}
+
+func (m *GoModule) RepoName() api.RepoName {
+	return api.RepoName("go/" + m.Path)
+}
+
+func (m *GoModule) CloneURL() string {
+	cloneURL := url.URL{Path: string(m.RepoName())}
+	return cloneURL.String()
+}
+
+// ParseGoModuleFromRepoURL returns a parsed Go module from the provided URL
+// path, without a leading `/`, e.g. "go/github.com/sourcegraph/go-diff".
+func ParseGoModuleFromRepoURL(urlPath string) (GoModule, error) {
+	if !strings.HasPrefix(urlPath, "go/") {
+		return GoModule{}, fmt.Errorf("failed to parse a Go module from the path %s", urlPath)
+	}
+	path := strings.TrimPrefix(urlPath, "go/")
+	if err := module.CheckPath(path); err != nil {
+		return GoModule{}, err
+	}
+	return GoModule{Path: path}, nil
+}
+
+type GoDependency struct {
+	GoModule
+	Version string
+}
+
+// PackageManagerSyntax returns the dependency in go get syntax, e.g.
+// "github.com/sourcegraph/go-diff@v0.6.1".
+func (d *GoDependency) PackageManagerSyntax() string {
+	return fmt.Sprintf("%s@%s", d.Path, d.Version)
+}
+
+// GitTagFromVersion returns the version as is, since Go module versions are
+// already prefixed with "v".
+func (d *GoDependency) GitTagFromVersion() string {
+	return d.Version
+}
+
+// ParseGoDependency parses a dependency in go get syntax, e.g.
+// "github.com/sourcegraph/go-diff@v0.6.1". The version must be a canonical
+// semantic version, including pseudo-versions.
+func ParseGoDependency(dependency string) (GoDependency, error) {
+	parts := strings.Split(dependency, "@")
+	if len(parts) != 2 {
+		return GoDependency{}, fmt.Errorf("dependency %q must be of the form module@version", dependency)
+	}
+	if err := module.Check(parts[0], parts[1]); err != nil {
+		return GoDependency{}, err
+	}
+	return GoDependency{
+		GoModule: GoModule{Path: parts[0]},
+		Version:  parts[1],
+	}, nil
+}
+
+// SortGoDependencies sorts the dependencies by the semantic version in
+// descending order. The latest version of a dependency becomes the first
+// element of the slice.
+func SortGoDependencies(dependencies []GoDependency) {
+	sort.Slice(dependencies, func(i, j int) bool {
+		a, b := dependencies[i], dependencies[j]
+		if a.Path != b.Path {
+			return a.Path > b.Path
+		}
+		return semver.Compare(a.Version, b.Version) > 0
+	})
+}
@@ -0,0 +1,74 @@
+package reposource
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+)
+
+func TestParseGoDependency(t *testing.T) {
+	tests := []struct {
+		dependency string
+		wantPath   string
+		wantErr    bool
+	}{
+		{dependency: "github.com/sourcegraph/go-diff@v0.6.1", wantPath: "github.com/sourcegraph/go-diff"},
+		{dependency: "golang.org/x/mod@v0.4.3-0.20210512182355-6088ed88cecd", wantPath: "golang.org/x/mod"},
+		{dependency: "github.com/sourcegraph/go-diff", wantErr: true},
+		{dependency: "github.com/sourcegraph/go-diff@0.6.1", wantErr: true},
+		{dependency: "github.com/sourcegraph/go-diff@v2.0.0", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.dependency, func(t *testing.T) {
+			dep, err := ParseGoDependency(tt.dependency)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantPath, dep.Path)
+			assert.Equal(t, tt.dependency, dep.PackageManagerSyntax())
+		})
+	}
+}
+
+func TestParseGoModuleFromRepoURL(t *testing.T) {
+	obtained, err := ParseGoModuleFromRepoURL("go/github.com/sourcegraph/go-diff")
+	assert.NoError(t, err)
+	assert.Equal(t, "github.com/sourcegraph/go-diff", obtained.Path)
+	assert.Equal(t, api.RepoName("go/github.com/sourcegraph/go-diff"), obtained.RepoName())
+
+	_, err = ParseGoModuleFromRepoURL("github.com/sourcegraph/go-diff")
+	assert.Error(t, err)
+}
+
+func parseGoDependencyOrPanic(t *testing.T, value string) GoDependency {
+	dependency, err := ParseGoDependency(value)
+	if err != nil {
+		t.Fatalf("error=%s", err)
+	}
+	return dependency
+}
+
+func TestSortGoDependencies(t *testing.T) {
+	dependencies := []GoDependency{
+		parseGoDependencyOrPanic(t, "c.com/m@v1.2.0"),
+		parseGoDependencyOrPanic(t, "a.com/m@v1.2.0"),
+		parseGoDependencyOrPanic(t, "b.com/m@v1.2.0"),
+		parseGoDependencyOrPanic(t, "b.com/m@v1.11.0"),
+		parseGoDependencyOrPanic(t, "b.com/m@v1.2.0-rc.1"),
+		parseGoDependencyOrPanic(t, "b.com/m@v1.1.0"),
+	}
+	expected := []GoDependency{
+		parseGoDependencyOrPanic(t, "c.com/m@v1.2.0"),
+		parseGoDependencyOrPanic(t, "b.com/m@v1.11.0"),
+		parseGoDependencyOrPanic(t, "b.com/m@v1.2.0"),
+		parseGoDependencyOrPanic(t, "b.com/m@v1.2.0-rc.1"),
+		parseGoDependencyOrPanic(t, "b.com/m@v1.1.0"),
+		parseGoDependencyOrPanic(t, "a.com/m@v1.2.0"),
+	}
+	SortGoDependencies(dependencies)
+	assert.Equal(t, expected, dependencies)
+}
@@ -72,6 +72,12 @@ func (d *MavenDependency) CoursierSyntax() string {
 	return fmt.Sprintf("%s:%s:%s", d.MavenModule.GroupID, d.MavenModule.ArtifactID, d.Version)
 }
 
+// PackageManagerSyntax returns the dependency in Coursier syntax, e.g.
+// "junit:junit:4.13.2".
+func (d *MavenDependency) PackageManagerSyntax() string {
+	return d.CoursierSyntax()
+}
+
 func (d *MavenDependency) GitTagFromVersion() string {
 	return "v" + d.Version
 }
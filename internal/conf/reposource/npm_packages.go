@@ -0,0 +1,137 @@
+package reposource
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/semver"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+)
+
+// npmPackageNameRegex matches the name of an npm package, optionally prefixed
+// with a scope. See https://docs.npmjs.com/cli/v7/configuring-npm/package-json#name.
+var npmPackageNameRegex = regexp.MustCompile(`^(?:@([a-z0-9-~][a-z0-9-._~]*)/)?([a-z0-9-~][a-z0-9-._~]*)$`)
+
+// NpmPackage is an npm package, e.g. "@types/node" or "react".
+type NpmPackage struct {
+	// Scope is the scope of the package without the leading "@", or the empty
+	// string for unscoped packages.
+	Scope string
+	Name  string
+}
+
+// PackageSyntax returns the name of the package as it is written in a
+// package.json file, e.g. "@types/node".
+func (p *NpmPackage) PackageSyntax() string {
+	if p.Scope == "" {
+		return p.Name
+	}
+	return fmt.Sprintf("@%s/%s", p.Scope, p.Name)
+}
+
+func (p *NpmPackage) MatchesDependencyString(dependency string) bool {
+	return strings.HasPrefix(dependency, p.PackageSyntax()+"@")
+}
+
+// RepoName returns the name of the Sourcegraph repository of the package. We
+// drop the "@" of the scope because it is used to separate the repository name
+// from the revision in Sourcegraph URLs.
+func (p *NpmPackage) RepoName() api.RepoName {
+	if p.Scope == "" {
+		return api.RepoName("npm/" + p.Name)
+	}
+	return api.RepoName(fmt.Sprintf("npm/%s/%s", p.Scope, p.Name))
+}
+
+func (p *NpmPackage) CloneURL() string {
+	cloneURL := url.URL{Path: string(p.RepoName())}
+	return cloneURL.String()
+}
+
+// ParseNpmPackage parses a package name in package.json syntax, e.g.
+// "@types/node".
+func ParseNpmPackage(name string) (NpmPackage, error) {
+	match := npmPackageNameRegex.FindStringSubmatch(name)
+	if match == nil {
+		return NpmPackage{}, fmt.Errorf("invalid npm package name %q", name)
+	}
+	return NpmPackage{Scope: match[1], Name: match[2]}, nil
+}
+
+// ParseNpmPackageFromRepoURL returns a parsed npm package from the provided URL
+// path, without a leading `/`, e.g. "npm/types/node".
+func ParseNpmPackageFromRepoURL(urlPath string) (NpmPackage, error) {
+	if !strings.HasPrefix(urlPath, "npm/") {
+		return NpmPackage{}, fmt.Errorf("failed to parse an npm package from the path %s", urlPath)
+	}
+	name := strings.TrimPrefix(urlPath, "npm/")
+	if strings.Contains(name, "/") {
+		// Unscoped package names can't contain slashes, so the first
+		// component must be the scope.
+		name = "@" + name
+	}
+	return ParseNpmPackage(name)
+}
+
+type NpmDependency struct {
+	NpmPackage
+	Version         string
+	SemanticVersion *semver.Version
+}
+
+// PackageManagerSyntax returns the dependency in npm install syntax, e.g.
+// "@types/node@16.4.13".
+func (d *NpmDependency) PackageManagerSyntax() string {
+	return fmt.Sprintf("%s@%s", d.PackageSyntax(), d.Version)
+}
+
+func (d *NpmDependency) GitTagFromVersion() string {
+	return "v" + d.Version
+}
+
+// ParseNpmDependency parses a dependency in npm install syntax, e.g.
+// "@types/node@16.4.13".
+func ParseNpmDependency(dependency string) (NpmDependency, error) {
+	// The version is separated by the last "@". Scoped package names also
+	// start with an "@", so we ignore the first character.
+	i := strings.LastIndex(dependency, "@")
+	if i <= 0 || i == len(dependency)-1 {
+		return NpmDependency{}, fmt.Errorf("dependency %q must be of the form (@scope/)?name@version", dependency)
+	}
+
+	pkg, err := ParseNpmPackage(dependency[:i])
+	if err != nil {
+		return NpmDependency{}, err
+	}
+	version := dependency[i+1:]
+
+	// Ignore the error for the same reason as in ParseMavenDependency: the
+	// semantic version is only used for sorting.
+	semanticVersion, _ := semver.NewVersion(version)
+
+	return NpmDependency{
+		NpmPackage:      pkg,
+		Version:         version,
+		SemanticVersion: semanticVersion,
+	}, nil
+}
+
+// SortNpmDependencies sorts the dependencies by the semantic version in
+// descending order. The latest version of a dependency becomes the first
+// element of the slice.
+func SortNpmDependencies(dependencies []NpmDependency) {
+	sort.Slice(dependencies, func(i, j int) bool {
+		a, b := dependencies[i], dependencies[j]
+		if a.NpmPackage != b.NpmPackage {
+			return a.PackageSyntax() > b.PackageSyntax()
+		}
+		if a.SemanticVersion == nil || b.SemanticVersion == nil {
+			return a.Version > b.Version
+		}
+		return a.SemanticVersion.GreaterThan(b.SemanticVersion)
+	})
+}
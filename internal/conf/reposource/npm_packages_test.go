@@ -0,0 +1,82 @@
+package reposource
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+)
+
+func TestParseNpmDependency(t *testing.T) {
+	tests := []struct {
+		dependency  string
+		wantPackage NpmPackage
+		wantVersion string
+		wantErr     bool
+	}{
+		{dependency: "react@17.0.2", wantPackage: NpmPackage{Name: "react"}, wantVersion: "17.0.2"},
+		{dependency: "@types/node@16.4.13", wantPackage: NpmPackage{Scope: "types", Name: "node"}, wantVersion: "16.4.13"},
+		{dependency: "@types/node", wantErr: true},
+		{dependency: "react@", wantErr: true},
+		{dependency: "React@17.0.2", wantErr: true},
+		{dependency: "a/b/c@1.0.0", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.dependency, func(t *testing.T) {
+			dep, err := ParseNpmDependency(tt.dependency)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantPackage, dep.NpmPackage)
+			assert.Equal(t, tt.wantVersion, dep.Version)
+			assert.Equal(t, tt.dependency, dep.PackageManagerSyntax())
+		})
+	}
+}
+
+func TestParseNpmPackageFromRepoURL(t *testing.T) {
+	obtained, err := ParseNpmPackageFromRepoURL("npm/types/node")
+	assert.NoError(t, err)
+	assert.Equal(t, "@types/node", obtained.PackageSyntax())
+	assert.Equal(t, api.RepoName("npm/types/node"), obtained.RepoName())
+
+	obtained, err = ParseNpmPackageFromRepoURL("npm/react")
+	assert.NoError(t, err)
+	assert.Equal(t, "react", obtained.PackageSyntax())
+	assert.Equal(t, api.RepoName("npm/react"), obtained.RepoName())
+
+	_, err = ParseNpmPackageFromRepoURL("maven/react")
+	assert.Error(t, err)
+}
+
+func parseNpmDependencyOrPanic(t *testing.T, value string) NpmDependency {
+	dependency, err := ParseNpmDependency(value)
+	if err != nil {
+		t.Fatalf("error=%s", err)
+	}
+	return dependency
+}
+
+func TestSortNpmDependencies(t *testing.T) {
+	dependencies := []NpmDependency{
+		parseNpmDependencyOrPanic(t, "c@1.2.0"),
+		parseNpmDependencyOrPanic(t, "@a/b@1.2.0"),
+		parseNpmDependencyOrPanic(t, "b@1.2.0"),
+		parseNpmDependencyOrPanic(t, "b@1.11.0"),
+		parseNpmDependencyOrPanic(t, "b@1.2.0-rc.1"),
+		parseNpmDependencyOrPanic(t, "b@1.1.0"),
+	}
+	expected := []NpmDependency{
+		parseNpmDependencyOrPanic(t, "c@1.2.0"),
+		parseNpmDependencyOrPanic(t, "b@1.11.0"),
+		parseNpmDependencyOrPanic(t, "b@1.2.0"),
+		parseNpmDependencyOrPanic(t, "b@1.2.0-rc.1"),
+		parseNpmDependencyOrPanic(t, "b@1.1.0"),
+		parseNpmDependencyOrPanic(t, "@a/b@1.2.0"),
+	}
+	SortNpmDependencies(dependencies)
+	assert.Equal(t, expected, dependencies)
+}
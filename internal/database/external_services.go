@@ -46,7 +46,10 @@ type ExternalServiceStore struct {
 	GitHubValidators          []func(*schema.GitHubConnection) error
 	GitLabValidators          []func(*schema.GitLabConnection, []schema.AuthProviders) error
 	BitbucketServerValidators []func(*schema.BitbucketServerConnection) error
+	BitbucketCloudValidators  []func(*schema.BitbucketCloudConnection) error
 	PerforceValidators        []func(*schema.PerforceConnection) error
+	GiteaValidators           []func(*schema.GiteaConnection) error
+	GerritValidators          []func(*schema.GerritConnection) error
 
 	key encryption.Key
 
@@ -60,7 +63,10 @@ func (e *ExternalServiceStore) copy() *ExternalServiceStore {
 		GitHubValidators:          e.GitHubValidators,
 		GitLabValidators:          e.GitLabValidators,
 		BitbucketServerValidators: e.BitbucketServerValidators,
+		BitbucketCloudValidators:  e.BitbucketCloudValidators,
 		PerforceValidators:        e.PerforceValidators,
+		GiteaValidators:           e.GiteaValidators,
+		GerritValidators:          e.GerritValidators,
 	}
 }
 
@@ -121,10 +127,14 @@ var ExternalServiceKinds = map[string]ExternalServiceKind{
 	extsvc.KindAWSCodeCommit:   {CodeHost: true, JSONSchema: schema.AWSCodeCommitSchemaJSON},
 	extsvc.KindBitbucketCloud:  {CodeHost: true, JSONSchema: schema.BitbucketCloudSchemaJSON},
 	extsvc.KindBitbucketServer: {CodeHost: true, JSONSchema: schema.BitbucketServerSchemaJSON},
+	extsvc.KindGerrit:          {CodeHost: true, JSONSchema: schema.GerritSchemaJSON},
 	extsvc.KindGitHub:          {CodeHost: true, JSONSchema: schema.GitHubSchemaJSON},
 	extsvc.KindGitLab:          {CodeHost: true, JSONSchema: schema.GitLabSchemaJSON},
+	extsvc.KindGitea:           {CodeHost: true, JSONSchema: schema.GiteaSchemaJSON},
 	extsvc.KindGitolite:        {CodeHost: true, JSONSchema: schema.GitoliteSchemaJSON},
+	extsvc.KindGoModules:       {CodeHost: true, JSONSchema: schema.GoModulesSchemaJSON},
 	extsvc.KindJVMPackages:     {CodeHost: true, JSONSchema: schema.JVMPackagesSchemaJSON},
+	extsvc.KindNpmPackages:     {CodeHost: true, JSONSchema: schema.NpmPackagesSchemaJSON},
 	extsvc.KindPerforce:        {CodeHost: true, JSONSchema: schema.PerforceSchemaJSON},
 	extsvc.KindPhabricator:     {CodeHost: true, JSONSchema: schema.PhabricatorSchemaJSON},
 	extsvc.KindOther:           {CodeHost: true, JSONSchema: schema.OtherExternalServiceSchemaJSON},
@@ -218,6 +228,10 @@ func (e *ExternalServiceStore) ValidateConfig(ctx context.Context, opt ValidateE
 	// serveExternalServiceConfigs to handle this case.
 
 	sl := gojsonschema.NewSchemaLoader()
+	// Code host connections reference the shared partial clone rules.
+	if err := sl.AddSchemas(gojsonschema.NewStringLoader(schema.PartialCloneSchemaJSON)); err != nil {
+		return nil, errors.Wrap(err, "unable to add partial clone schema")
+	}
 	sc, err := sl.Compile(gojsonschema.NewStringLoader(ext.JSONSchema))
 	if err != nil {
 		return nil, errors.Wrapf(err, "unable to compile schema for external service of kind %q", opt.Kind)
@@ -319,6 +333,20 @@ func (e *ExternalServiceStore) ValidateConfig(ctx context.Context, opt ValidateE
 		}
 		err = e.validatePerforceConnection(ctx, opt.ExternalServiceID, &c)
 
+	case extsvc.KindGitea:
+		var c schema.GiteaConnection
+		if err = jsoniter.Unmarshal(normalized, &c); err != nil {
+			return nil, err
+		}
+		err = e.validateGiteaConnection(ctx, opt.ExternalServiceID, &c)
+
+	case extsvc.KindGerrit:
+		var c schema.GerritConnection
+		if err = jsoniter.Unmarshal(normalized, &c); err != nil {
+			return nil, err
+		}
+		err = e.validateGerritConnection(ctx, opt.ExternalServiceID, &c)
+
 	case extsvc.KindOther:
 		var c schema.OtherExternalServiceConnection
 		if err = jsoniter.Unmarshal(normalized, &c); err != nil {
@@ -401,7 +429,14 @@ func (e *ExternalServiceStore) validateBitbucketServerConnection(ctx context.Con
 }
 
 func (e *ExternalServiceStore) validateBitbucketCloudConnection(ctx context.Context, id int64, c *schema.BitbucketCloudConnection) error {
-	return e.validateDuplicateRateLimits(ctx, id, extsvc.KindBitbucketCloud, c)
+	err := new(multierror.Error)
+	for _, validate := range e.BitbucketCloudValidators {
+		err = multierror.Append(err, validate(c))
+	}
+
+	err = multierror.Append(err, e.validateDuplicateRateLimits(ctx, id, extsvc.KindBitbucketCloud, c))
+
+	return err.ErrorOrNil()
 }
 
 func (e *ExternalServiceStore) validatePerforceConnection(ctx context.Context, id int64, c *schema.PerforceConnection) error {
@@ -419,6 +454,28 @@ func (e *ExternalServiceStore) validatePerforceConnection(ctx context.Context, i
 	return err.ErrorOrNil()
 }
 
+func (e *ExternalServiceStore) validateGiteaConnection(ctx context.Context, id int64, c *schema.GiteaConnection) error {
+	err := new(multierror.Error)
+	for _, validate := range e.GiteaValidators {
+		err = multierror.Append(err, validate(c))
+	}
+
+	err = multierror.Append(err, e.validateDuplicateRateLimits(ctx, id, extsvc.KindGitea, c))
+
+	return err.ErrorOrNil()
+}
+
+func (e *ExternalServiceStore) validateGerritConnection(ctx context.Context, id int64, c *schema.GerritConnection) error {
+	err := new(multierror.Error)
+	for _, validate := range e.GerritValidators {
+		err = multierror.Append(err, validate(c))
+	}
+
+	err = multierror.Append(err, e.validateDuplicateRateLimits(ctx, id, extsvc.KindGerrit, c))
+
+	return err.ErrorOrNil()
+}
+
 // validateDuplicateRateLimits returns an error if given config has duplicated non-default rate limit
 // with another external service for the same code host.
 func (e *ExternalServiceStore) validateDuplicateRateLimits(ctx context.Context, id int64, kind string, parsedConfig interface{}) error {
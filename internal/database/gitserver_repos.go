@@ -70,6 +70,8 @@ INSERT INTO
 type IterateRepoGitserverStatusOptions struct {
 	// If set, will only iterate over repos that have not been assigned to a shard
 	OnlyWithoutShard bool
+	// If set, will only iterate over repos that have been assigned to this shard
+	ShardID string
 }
 
 // IterateRepoGitserverStatus iterates over the status of all repos by joining
@@ -94,11 +96,16 @@ FROM repo
     LEFT JOIN gitserver_repos gr ON gr.repo_id = repo.id
     WHERE repo.deleted_at IS NULL
 `
+	var args []interface{}
 	if options.OnlyWithoutShard {
 		q = q + "AND (gr.shard_id = '' OR gr IS NULL)"
 	}
+	if options.ShardID != "" {
+		q = q + " AND gr.shard_id = %s"
+		args = append(args, options.ShardID)
+	}
 
-	rows, err := s.Query(ctx, sqlf.Sprintf(q))
+	rows, err := s.Query(ctx, sqlf.Sprintf(q, args...))
 	if err != nil {
 		return errors.Wrap(err, "fetching gitserver status")
 	}
@@ -179,9 +186,9 @@ WHERE repo_id = %s
 	return &gr, nil
 }
 
-// SetCloneStatus will attempt to update ONLY the clone status of a
+// SetCloneStatus will attempt to update ONLY the clone status and shard of a
 // GitServerRepo. If a matching row does not yet exist a new one will be created.
-// If the status value hasn't changed, the row will not be updated.
+// If neither the status nor the shard has changed, the row will not be updated.
 func (s *GitserverRepoStore) SetCloneStatus(ctx context.Context, id api.RepoID, status types.CloneStatus, shardID string) error {
 	err := s.Exec(ctx, sqlf.Sprintf(`
 -- source: internal/database/gitserver_repos.go:GitserverRepoStore.SetCloneStatus
@@ -191,6 +198,7 @@ ON CONFLICT (repo_id) DO UPDATE
 SET (clone_status, shard_id, updated_at) =
     (EXCLUDED.clone_status, EXCLUDED.shard_id, now())
     WHERE gitserver_repos.clone_status IS DISTINCT FROM EXCLUDED.clone_status
+       OR gitserver_repos.shard_id IS DISTINCT FROM EXCLUDED.shard_id
 `, id, status, shardID))
 
 	return errors.Wrap(err, "setting clone status")
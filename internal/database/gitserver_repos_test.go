@@ -96,6 +96,20 @@ func TestIterateRepoGitserverStatus(t *testing.T) {
 	if noShardCount != wantNoShardCount {
 		t.Fatalf("Want %d, got %d", wantNoShardCount, noShardCount)
 	}
+
+	var shardRepos []api.RepoName
+	// Iterate again against repos assigned to a shard
+	err = GitserverRepos(db).IterateRepoGitserverStatus(ctx, IterateRepoGitserverStatusOptions{ShardID: "gitserver1"}, func(repo types.RepoGitserverStatus) error {
+		shardRepos = append(shardRepos, repo.Name)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := cmp.Diff([]api.RepoName{repo1.Name}, shardRepos); diff != "" {
+		t.Fatal(diff)
+	}
 }
 
 func TestGitserverReposGetByID(t *testing.T) {
@@ -246,6 +260,18 @@ func TestSetCloneStatus(t *testing.T) {
 	if diff := cmp.Diff(fromDB, after); diff != "" {
 		t.Fatal(diff)
 	}
+
+	// Setting the same status from another shard should move the repo
+	if err := GitserverRepos(db).SetCloneStatus(ctx, repo2.ID, types.CloneStatusCloned, "other"); err != nil {
+		t.Fatal(err)
+	}
+	after, err = GitserverRepos(db).GetByID(ctx, repo2.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if after.ShardID != "other" {
+		t.Fatalf("Want shard %q, got %q", "other", after.ShardID)
+	}
 }
 
 func TestSetLastError(t *testing.T) {
@@ -28,10 +28,14 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/awscodecommit"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketcloud"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketserver"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gerrit"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitea"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitlab"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitolite"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gomodules"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/jvmpackages"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/npmpackages"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/perforce"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/phabricator"
 	"github.com/sourcegraph/sourcegraph/internal/trace"
@@ -401,6 +405,10 @@ func scanRepo(rows *sql.Rows, r *types.Repo) (err error) {
 		r.Metadata = new(bitbucketserver.Repo)
 	case extsvc.TypeBitbucketCloud:
 		r.Metadata = new(bitbucketcloud.Repo)
+	case extsvc.TypeGitea:
+		r.Metadata = new(gitea.Repo)
+	case extsvc.TypeGerrit:
+		r.Metadata = new(gerrit.Project)
 	case extsvc.TypeAWSCodeCommit:
 		r.Metadata = new(awscodecommit.Repository)
 	case extsvc.TypeGitolite:
@@ -413,6 +421,10 @@ func scanRepo(rows *sql.Rows, r *types.Repo) (err error) {
 		r.Metadata = new(extsvc.OtherRepoMetadata)
 	case extsvc.TypeJVMPackages:
 		r.Metadata = new(jvmpackages.Metadata)
+	case extsvc.TypeNpmPackages:
+		r.Metadata = new(npmpackages.Metadata)
+	case extsvc.TypeGoModules:
+		r.Metadata = new(gomodules.Metadata)
 	default:
 		log15.Warn("scanRepo - unknown service type", "typ", typ)
 		return nil
@@ -444,6 +456,10 @@ type ReposListOptions struct {
 	// returned in the list.
 	ExcludePattern string
 
+	// DescriptionPatterns is a list of regular expressions, all of which must
+	// match the description of all repositories returned in the list.
+	DescriptionPatterns []string
+
 	// Names is a list of repository names used to limit the results to that
 	// set of repositories.
 	// Note: This is currently used for version contexts. In future iterations,
@@ -746,6 +762,10 @@ func (s *RepoStore) listSQL(ctx context.Context, opt ReposListOptions) (*sqlf.Qu
 		where = append(where, sqlf.Sprintf("lower(name) !~* %s", opt.ExcludePattern))
 	}
 
+	for _, descriptionPattern := range opt.DescriptionPatterns {
+		where = append(where, sqlf.Sprintf("description ~* %s", descriptionPattern))
+	}
+
 	if opt.PatternQuery != nil {
 		cond, err := query.Eval(opt.PatternQuery, func(q query.Q) (*sqlf.Query, error) {
 			pattern, ok := q.(string)
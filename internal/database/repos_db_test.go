@@ -881,6 +881,54 @@ func TestRepos_List_patterns(t *testing.T) {
 	}
 }
 
+func TestRepos_List_descriptionPatterns(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	t.Parallel()
+	db := dbtest.NewDB(t, "")
+	ctx := actor.WithInternalActor(context.Background())
+
+	createdRepos := []*types.Repo{
+		{Name: "a/b", Description: "A Go library for parsing"},
+		{Name: "c/d", Description: "A Rust library"},
+		{Name: "e/f", Description: "Go service"},
+		{Name: "g/h"},
+	}
+	for _, repo := range createdRepos {
+		createRepo(ctx, t, db, repo)
+	}
+	tests := []struct {
+		descriptionPatterns []string
+		want                []api.RepoName
+	}{
+		{
+			descriptionPatterns: []string{"library"},
+			want:                []api.RepoName{"a/b", "c/d"},
+		},
+		{
+			descriptionPatterns: []string{"^go"},
+			want:                []api.RepoName{"e/f"},
+		},
+		{
+			descriptionPatterns: []string{"library", "go"},
+			want:                []api.RepoName{"a/b"},
+		},
+	}
+	for _, test := range tests {
+		repos, err := Repos(db).List(ctx, ReposListOptions{
+			DescriptionPatterns: test.descriptionPatterns,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := repoNames(repos); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("description %q: got repos %q, want %q", test.descriptionPatterns, got, test.want)
+		}
+	}
+}
+
 // TestRepos_List_patterns tests the behavior of Repos.List when called with
 // a QueryPattern.
 func TestRepos_List_queryPattern(t *testing.T) {
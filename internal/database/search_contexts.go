@@ -64,7 +64,7 @@ func searchContextsPermissionsCondition(ctx context.Context, db dbutil.DB) (*sql
 }
 
 const listSearchContextsFmtStr = `
-SELECT sc.id, sc.name, sc.description, sc.public, sc.namespace_user_id, sc.namespace_org_id, sc.updated_at, u.username, o.name
+SELECT sc.id, sc.name, sc.description, sc.public, sc.namespace_user_id, sc.namespace_org_id, sc.updated_at, sc.query, sc.query_repos_updated_at, u.username, o.name
 FROM search_contexts sc
 LEFT JOIN users u on sc.namespace_user_id = u.id
 LEFT JOIN orgs o on sc.namespace_org_id = o.id
@@ -113,6 +113,8 @@ type ListSearchContextsOptions struct {
 	NamespaceOrgIDs []int32
 	// NoNamespace matches search contexts without a namespace ("instance-level contexts").
 	NoNamespace bool
+	// OnlyQueryDefined matches only search contexts whose repositories are defined by a query.
+	OnlyQueryDefined bool
 	// OrderBy specifies the ordering option for search contexts. Search contexts are ordered using SearchContextsOrderByID by default.
 	// SearchContextsOrderBySpec option sorts contexts by coallesced namespace names first
 	// (user name and org name) and then by context name. SearchContextsOrderByUpdatedAt option sorts
@@ -186,6 +188,10 @@ func getSearchContextsQueryConditions(opts ListSearchContextsOptions) ([]*sqlf.Q
 		conds = append(conds, sqlf.Sprintf("COALESCE(u.username, o.name, '') ILIKE %s", "%"+opts.NamespaceName+"%"))
 	}
 
+	if opts.OnlyQueryDefined {
+		conds = append(conds, sqlf.Sprintf("sc.query IS NOT NULL"))
+	}
+
 	if len(conds) == 0 {
 		// If no conditions are present, append a catch-all condition to avoid a SQL syntax error
 		conds = append(conds, sqlf.Sprintf("1 = 1"))
@@ -302,8 +308,8 @@ func (s *SearchContextsStore) DeleteSearchContext(ctx context.Context, searchCon
 
 const insertSearchContextFmtStr = `
 INSERT INTO search_contexts
-(name, description, public, namespace_user_id, namespace_org_id)
-VALUES (%s, %s, %s, %s, %s)
+(name, description, public, namespace_user_id, namespace_org_id, query)
+VALUES (%s, %s, %s, %s, %s, %s)
 `
 
 // 🚨 SECURITY: The caller must ensure that the actor is a site admin or has permission to create the search context.
@@ -332,6 +338,7 @@ SET
 	name = %s,
 	description = %s,
 	public = %s,
+	query = %s,
 	updated_at = now()
 WHERE id = %d AND deleted_at IS NULL
 `
@@ -388,6 +395,46 @@ func (s *SearchContextsStore) SetSearchContextRepositoryRevisions(ctx context.Co
 	))
 }
 
+const setQuerySearchContextReposUpdatedAtFmtStr = `
+UPDATE search_contexts
+SET query_repos_updated_at = now()
+WHERE id = %d AND deleted_at IS NULL
+`
+
+// SetQuerySearchContextRepositories replaces the cached repositories of a search context defined by a query
+// with repos. The default branch of each repository is searched.
+//
+// 🚨 SECURITY: The caller must ensure that repos were resolved by an internal actor, so that the search context
+// contains all repositories matching its query. Repositories are filtered by permissions when searching.
+func (s *SearchContextsStore) SetQuerySearchContextRepositories(ctx context.Context, searchContextID int64, repos []types.RepoName) (err error) {
+	tx, err := s.Transact(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { err = tx.Done(err) }()
+
+	err = tx.Exec(ctx, sqlf.Sprintf("DELETE FROM search_context_repos WHERE search_context_id = %d", searchContextID))
+	if err != nil {
+		return err
+	}
+
+	if len(repos) > 0 {
+		values := make([]*sqlf.Query, 0, len(repos))
+		for _, repo := range repos {
+			values = append(values, sqlf.Sprintf("(%s, %s, %s)", searchContextID, repo.ID, "HEAD"))
+		}
+		err = tx.Exec(ctx, sqlf.Sprintf(
+			"INSERT INTO search_context_repos (search_context_id, repo_id, revision) VALUES %s",
+			sqlf.Join(values, ","),
+		))
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Exec(ctx, sqlf.Sprintf(setQuerySearchContextReposUpdatedAtFmtStr, searchContextID))
+}
+
 func (s *SearchContextsStore) createSearchContext(ctx context.Context, searchContext *types.SearchContext) (*types.SearchContext, error) {
 	err := s.Exec(ctx, sqlf.Sprintf(
 		insertSearchContextFmtStr,
@@ -396,6 +443,7 @@ func (s *SearchContextsStore) createSearchContext(ctx context.Context, searchCon
 		searchContext.Public,
 		nullInt32Column(searchContext.NamespaceUserID),
 		nullInt32Column(searchContext.NamespaceOrgID),
+		nullStringColumn(searchContext.Query),
 	))
 	if err != nil {
 		return nil, err
@@ -413,6 +461,7 @@ func (s *SearchContextsStore) updateSearchContext(ctx context.Context, searchCon
 		searchContext.Name,
 		searchContext.Description,
 		searchContext.Public,
+		nullStringColumn(searchContext.Query),
 		searchContext.ID,
 	))
 	if err != nil {
@@ -448,6 +497,8 @@ func scanSearchContexts(rows *sql.Rows) ([]*types.SearchContext, error) {
 			&dbutil.NullInt32{N: &sc.NamespaceUserID},
 			&dbutil.NullInt32{N: &sc.NamespaceOrgID},
 			&sc.UpdatedAt,
+			&dbutil.NullString{S: &sc.Query},
+			&dbutil.NullTime{Time: &sc.QueryReposUpdatedAt},
 			&dbutil.NullString{S: &sc.NamespaceUserName},
 			&dbutil.NullString{S: &sc.NamespaceOrgName},
 		)
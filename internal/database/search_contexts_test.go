@@ -418,6 +418,69 @@ func TestSearchContexts_CreateAndSetRepositoryRevisions(t *testing.T) {
 	}
 }
 
+func TestSearchContexts_SetQuerySearchContextRepositories(t *testing.T) {
+	db := dbtest.NewDB(t, "")
+	t.Parallel()
+	ctx := actor.WithInternalActor(context.Background())
+	sc := SearchContexts(db)
+	r := Repos(db)
+
+	err := r.Create(ctx, &types.Repo{Name: "testA", URI: "https://example.com/a"}, &types.Repo{Name: "testB", URI: "https://example.com/b"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	repos, err := r.ListRepoNames(ctx, ReposListOptions{OrderBy: RepoListOrderBy{{Field: RepoListID}}})
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+
+	searchContext, err := sc.CreateSearchContextWithRepositoryRevisions(
+		ctx,
+		&types.SearchContext{Name: "query", Description: "query", Public: true, Query: "repo:^test"},
+		nil,
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	if searchContext.Query != "repo:^test" || !searchContext.QueryReposUpdatedAt.IsZero() {
+		t.Fatalf("unexpected search context %+v", searchContext)
+	}
+
+	got, err := sc.ListSearchContexts(ctx, ListSearchContextsPageOptions{First: 10}, ListSearchContextsOptions{OnlyQueryDefined: true})
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	if len(got) != 1 || got[0].ID != searchContext.ID {
+		t.Fatalf("wanted only the query search context, got %+v", got)
+	}
+
+	for _, want := range [][]types.RepoName{repos, repos[1:], nil} {
+		err = sc.SetQuerySearchContextRepositories(ctx, searchContext.ID, want)
+		if err != nil {
+			t.Fatalf("Expected no error, got %s", err)
+		}
+		gotRepositoryRevisions, err := sc.GetSearchContextRepositoryRevisions(ctx, searchContext.ID)
+		if err != nil {
+			t.Fatalf("Expected no error, got %s", err)
+		}
+		wantRepositoryRevisions := []*types.SearchContextRepositoryRevisions{}
+		for _, repo := range want {
+			wantRepositoryRevisions = append(wantRepositoryRevisions, &types.SearchContextRepositoryRevisions{Repo: repo, Revisions: []string{"HEAD"}})
+		}
+		if !reflect.DeepEqual(wantRepositoryRevisions, gotRepositoryRevisions) {
+			t.Fatalf("wanted %v repository revisions, got %v", wantRepositoryRevisions, gotRepositoryRevisions)
+		}
+	}
+
+	updated, err := sc.GetSearchContext(ctx, GetSearchContextOptions{Name: "query"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	if updated.QueryReposUpdatedAt.IsZero() {
+		t.Fatal("expected query_repos_updated_at to be set")
+	}
+}
+
 func TestSearchContexts_Permissions(t *testing.T) {
 	db := dbtest.NewDB(t, "")
 	t.Parallel()
@@ -139,6 +139,26 @@ func (s *Store) OpenWithPath(ctx context.Context, key string, fetcher FetcherWit
 	}
 }
 
+// Peek opens the file for key if it is already in the cache, without fetching
+// it when missing. Unlike Open, Peek does not update the modified time of the
+// file, so peeking at an item does not protect it from eviction. It returns a
+// nil file if key is not in the cache.
+func (s *Store) Peek(key string) (*File, error) {
+	if s.Dir == "" {
+		return nil, errors.New("diskcache.Store.Dir must be set")
+	}
+
+	path := s.path(key)
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &File{File: f, Path: path}, nil
+}
+
 // path returns the path for key.
 func (s *Store) path(key string) string {
 	// path uses a sha256 hash of the key since we want to use it for the
@@ -60,3 +60,49 @@ func TestOpen(t *testing.T) {
 		t.Fatal("Item was not properly evicted")
 	}
 }
+
+func TestPeek(t *testing.T) {
+	dir, err := os.MkdirTemp("", "diskcache_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	store := &Store{
+		Dir:       dir,
+		Component: "test",
+	}
+
+	f, err := store.Peek("key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f != nil {
+		t.Fatal("Expected no file on empty cache")
+	}
+
+	f, err = store.Open(context.Background(), "key", func(ctx context.Context) (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader([]byte("foobar"))), nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	f, err = store.Peek("key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f == nil {
+		t.Fatal("Expected file after it was fetched")
+	}
+	defer f.Close()
+
+	got, err := io.ReadAll(f.File)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "foobar" {
+		t.Fatalf("unexpected contents. got %q, want %q", string(got), "foobar")
+	}
+}
@@ -0,0 +1,366 @@
+package awscodecommit
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/codecommit"
+	codecommittypes "github.com/aws/aws-sdk-go-v2/service/codecommit/types"
+	"github.com/cockroachdb/errors"
+)
+
+// Pull request statuses. A pull request that was merged is CLOSED and has
+// IsMerged set.
+const (
+	PullRequestStatusOpen   = string(codecommittypes.PullRequestStatusEnumOpen)
+	PullRequestStatusClosed = string(codecommittypes.PullRequestStatusEnumClosed)
+)
+
+// PullRequest is an AWS CodeCommit pull request with a single target.
+type PullRequest struct {
+	ID               string
+	Title            string
+	Description      string
+	Status           string
+	IsMerged         bool
+	AuthorARN        string
+	CreationDate     time.Time
+	LastActivityDate time.Time
+	RevisionID       string
+	Region           string
+
+	RepositoryName       string
+	SourceReference      string // the full ref name, e.g. refs/heads/my-branch
+	DestinationReference string
+	SourceCommit         string
+	DestinationCommit    string
+
+	// Approvals are the approval states of the current revision.
+	Approvals []*Approval
+	// Events are the status, merge and approval events of the pull request,
+	// oldest first.
+	Events []*PullRequestEvent
+}
+
+// URL returns the URL of the pull request in the AWS console.
+func (pr *PullRequest) URL() string {
+	return fmt.Sprintf(
+		"https://%s.console.aws.amazon.com/codesuite/codecommit/repositories/%s/pull-requests/%s/details?region=%s",
+		pr.Region, url.PathEscape(pr.RepositoryName), url.PathEscape(pr.ID), url.QueryEscape(pr.Region),
+	)
+}
+
+// Approval is the approval state of a single user.
+type Approval struct {
+	UserARN string
+	State   string // APPROVE or REVOKE
+}
+
+// Approval states.
+const (
+	ApprovalStateApprove = string(codecommittypes.ApprovalStateApprove)
+	ApprovalStateRevoke  = string(codecommittypes.ApprovalStateRevoke)
+)
+
+// Pull request event types that are loaded into PullRequest.Events.
+const (
+	PullRequestEventStatusChanged        = string(codecommittypes.PullRequestEventTypePullRequestStatusChanged)
+	PullRequestEventMergeStateChanged    = string(codecommittypes.PullRequestEventTypePullRequestMergeStateChanged)
+	PullRequestEventApprovalStateChanged = string(codecommittypes.PullRequestEventTypePullRequestApprovalStateChanged)
+)
+
+// PullRequestEvent is an event in the timeline of a pull request.
+type PullRequestEvent struct {
+	Type     string
+	Date     time.Time
+	ActorARN string
+
+	// Status is set for PULL_REQUEST_STATUS_CHANGED events.
+	Status string
+	// IsMerged is set for PULL_REQUEST_MERGE_STATE_CHANGED events, which
+	// are only kept once the pull request was merged.
+	IsMerged bool
+	// ApprovalState and RevisionID are set for
+	// PULL_REQUEST_APPROVAL_STATE_CHANGED events.
+	ApprovalState string
+	RevisionID    string
+}
+
+// Key is a unique key identifying this event in the context of its pull
+// request.
+func (e *PullRequestEvent) Key() string {
+	return e.Type + ":" + e.ActorARN + ":" + e.Date.UTC().String()
+}
+
+// CreatePullRequestInput is the input of CreatePullRequest.
+type CreatePullRequestInput struct {
+	RepositoryName       string
+	Title                string
+	Description          string
+	SourceReference      string
+	DestinationReference string
+}
+
+// CreatePullRequest opens a pull request.
+func (c *Client) CreatePullRequest(ctx context.Context, input *CreatePullRequestInput) (*PullRequest, error) {
+	svc := codecommit.NewFromConfig(c.aws)
+	out, err := svc.CreatePullRequest(ctx, &codecommit.CreatePullRequestInput{
+		Title:       aws.String(input.Title),
+		Description: aws.String(input.Description),
+		Targets: []codecommittypes.Target{{
+			RepositoryName:       aws.String(input.RepositoryName),
+			SourceReference:      aws.String(input.SourceReference),
+			DestinationReference: aws.String(input.DestinationReference),
+		}},
+	})
+	if err != nil {
+		return nil, &wrappedError{err: err}
+	}
+	return c.loadPullRequest(ctx, svc, out.PullRequest)
+}
+
+// FindOpenPullRequest returns the open pull request from sourceRef into
+// destinationRef, or nil if there is none.
+func (c *Client) FindOpenPullRequest(ctx context.Context, repositoryName, sourceRef, destinationRef string) (*PullRequest, error) {
+	svc := codecommit.NewFromConfig(c.aws)
+
+	input := codecommit.ListPullRequestsInput{
+		RepositoryName:    aws.String(repositoryName),
+		PullRequestStatus: codecommittypes.PullRequestStatusEnumOpen,
+	}
+	for {
+		out, err := svc.ListPullRequests(ctx, &input)
+		if err != nil {
+			return nil, &wrappedError{err: err}
+		}
+
+		for _, id := range out.PullRequestIds {
+			res, err := svc.GetPullRequest(ctx, &codecommit.GetPullRequestInput{PullRequestId: aws.String(id)})
+			if err != nil {
+				return nil, &wrappedError{err: err}
+			}
+			pr := fromPullRequest(res.PullRequest)
+			if pr.RepositoryName == repositoryName && pr.SourceReference == sourceRef && pr.DestinationReference == destinationRef {
+				return c.loadPullRequest(ctx, svc, res.PullRequest)
+			}
+		}
+
+		if out.NextToken == nil {
+			return nil, nil
+		}
+		input.NextToken = out.NextToken
+	}
+}
+
+// GetPullRequest fetches a pull request together with its approvals and
+// events.
+func (c *Client) GetPullRequest(ctx context.Context, id string) (*PullRequest, error) {
+	svc := codecommit.NewFromConfig(c.aws)
+	out, err := svc.GetPullRequest(ctx, &codecommit.GetPullRequestInput{PullRequestId: aws.String(id)})
+	if err != nil {
+		return nil, &wrappedError{err: err}
+	}
+	return c.loadPullRequest(ctx, svc, out.PullRequest)
+}
+
+// UpdatePullRequest sets the title and description of a pull request. Fields
+// that did not change are not updated.
+func (c *Client) UpdatePullRequest(ctx context.Context, pr *PullRequest, title, description string) (*PullRequest, error) {
+	svc := codecommit.NewFromConfig(c.aws)
+	if title != pr.Title {
+		if _, err := svc.UpdatePullRequestTitle(ctx, &codecommit.UpdatePullRequestTitleInput{
+			PullRequestId: aws.String(pr.ID),
+			Title:         aws.String(title),
+		}); err != nil {
+			return nil, &wrappedError{err: err}
+		}
+	}
+	if description != pr.Description {
+		if _, err := svc.UpdatePullRequestDescription(ctx, &codecommit.UpdatePullRequestDescriptionInput{
+			PullRequestId: aws.String(pr.ID),
+			Description:   aws.String(description),
+		}); err != nil {
+			return nil, &wrappedError{err: err}
+		}
+	}
+	return c.GetPullRequest(ctx, pr.ID)
+}
+
+// ClosePullRequest closes a pull request. AWS CodeCommit does not allow
+// closed pull requests to be reopened.
+func (c *Client) ClosePullRequest(ctx context.Context, id string) (*PullRequest, error) {
+	svc := codecommit.NewFromConfig(c.aws)
+	out, err := svc.UpdatePullRequestStatus(ctx, &codecommit.UpdatePullRequestStatusInput{
+		PullRequestId:     aws.String(id),
+		PullRequestStatus: codecommittypes.PullRequestStatusEnumClosed,
+	})
+	if err != nil {
+		return nil, &wrappedError{err: err}
+	}
+	return c.loadPullRequest(ctx, svc, out.PullRequest)
+}
+
+// MergePullRequest merges the current source commit of a pull request, either
+// with a three-way merge or by squashing it into a single commit.
+func (c *Client) MergePullRequest(ctx context.Context, pr *PullRequest, squash bool) (*PullRequest, error) {
+	svc := codecommit.NewFromConfig(c.aws)
+
+	var merged *codecommittypes.PullRequest
+	if squash {
+		out, err := svc.MergePullRequestBySquash(ctx, &codecommit.MergePullRequestBySquashInput{
+			PullRequestId:  aws.String(pr.ID),
+			RepositoryName: aws.String(pr.RepositoryName),
+			SourceCommitId: aws.String(pr.SourceCommit),
+		})
+		if err != nil {
+			return nil, &wrappedError{err: err}
+		}
+		merged = out.PullRequest
+	} else {
+		out, err := svc.MergePullRequestByThreeWay(ctx, &codecommit.MergePullRequestByThreeWayInput{
+			PullRequestId:  aws.String(pr.ID),
+			RepositoryName: aws.String(pr.RepositoryName),
+			SourceCommitId: aws.String(pr.SourceCommit),
+		})
+		if err != nil {
+			return nil, &wrappedError{err: err}
+		}
+		merged = out.PullRequest
+	}
+	return c.loadPullRequest(ctx, svc, merged)
+}
+
+// CreatePullRequestComment posts a comment on the current revision of a pull
+// request.
+func (c *Client) CreatePullRequestComment(ctx context.Context, pr *PullRequest, text string) error {
+	svc := codecommit.NewFromConfig(c.aws)
+	_, err := svc.PostCommentForPullRequest(ctx, &codecommit.PostCommentForPullRequestInput{
+		PullRequestId:  aws.String(pr.ID),
+		RepositoryName: aws.String(pr.RepositoryName),
+		BeforeCommitId: aws.String(pr.DestinationCommit),
+		AfterCommitId:  aws.String(pr.SourceCommit),
+		Content:        aws.String(text),
+	})
+	if err != nil {
+		return &wrappedError{err: err}
+	}
+	return nil
+}
+
+// IsNotMergeable reports whether err was returned because a pull request
+// cannot be merged without manual intervention.
+func IsNotMergeable(err error) bool {
+	return errors.HasType(err, &codecommittypes.ManualMergeRequiredException{}) ||
+		errors.HasType(err, &codecommittypes.PullRequestApprovalRulesNotSatisfiedException{}) ||
+		errors.HasType(err, &codecommittypes.TipOfSourceReferenceIsDifferentException{})
+}
+
+// IsPullRequestNotFound reports whether err was returned because a pull
+// request does not exist.
+func IsPullRequestNotFound(err error) bool {
+	return errors.HasType(err, &codecommittypes.PullRequestDoesNotExistException{})
+}
+
+// loadPullRequest converts p and loads its approvals and events.
+func (c *Client) loadPullRequest(ctx context.Context, svc *codecommit.Client, p *codecommittypes.PullRequest) (*PullRequest, error) {
+	pr := fromPullRequest(p)
+	pr.Region = c.aws.Region
+
+	approvals, err := svc.GetPullRequestApprovalStates(ctx, &codecommit.GetPullRequestApprovalStatesInput{
+		PullRequestId: aws.String(pr.ID),
+		RevisionId:    aws.String(pr.RevisionID),
+	})
+	if err != nil {
+		return nil, &wrappedError{err: err}
+	}
+	for _, a := range approvals.Approvals {
+		pr.Approvals = append(pr.Approvals, &Approval{
+			UserARN: aws.ToString(a.UserArn),
+			State:   string(a.ApprovalState),
+		})
+	}
+
+	input := codecommit.DescribePullRequestEventsInput{PullRequestId: aws.String(pr.ID)}
+	for {
+		out, err := svc.DescribePullRequestEvents(ctx, &input)
+		if err != nil {
+			return nil, &wrappedError{err: err}
+		}
+		for i := range out.PullRequestEvents {
+			if e := fromPullRequestEvent(&out.PullRequestEvents[i]); e != nil {
+				pr.Events = append(pr.Events, e)
+			}
+		}
+		if out.NextToken == nil {
+			break
+		}
+		input.NextToken = out.NextToken
+	}
+
+	// The API returns the newest events first.
+	for i, j := 0, len(pr.Events)-1; i < j; i, j = i+1, j-1 {
+		pr.Events[i], pr.Events[j] = pr.Events[j], pr.Events[i]
+	}
+
+	return pr, nil
+}
+
+func fromPullRequest(p *codecommittypes.PullRequest) *PullRequest {
+	pr := PullRequest{
+		ID:               aws.ToString(p.PullRequestId),
+		Title:            aws.ToString(p.Title),
+		Description:      aws.ToString(p.Description),
+		Status:           string(p.PullRequestStatus),
+		AuthorARN:        aws.ToString(p.AuthorArn),
+		CreationDate:     aws.ToTime(p.CreationDate),
+		LastActivityDate: aws.ToTime(p.LastActivityDate),
+		RevisionID:       aws.ToString(p.RevisionId),
+	}
+	// Pull requests created by us always have exactly one target.
+	if len(p.PullRequestTargets) > 0 {
+		t := p.PullRequestTargets[0]
+		pr.RepositoryName = aws.ToString(t.RepositoryName)
+		pr.SourceReference = aws.ToString(t.SourceReference)
+		pr.DestinationReference = aws.ToString(t.DestinationReference)
+		pr.SourceCommit = aws.ToString(t.SourceCommit)
+		pr.DestinationCommit = aws.ToString(t.DestinationCommit)
+		if t.MergeMetadata != nil {
+			pr.IsMerged = t.MergeMetadata.IsMerged
+		}
+	}
+	return &pr
+}
+
+// fromPullRequestEvent converts e, returning nil for event types we don't
+// track.
+func fromPullRequestEvent(e *codecommittypes.PullRequestEvent) *PullRequestEvent {
+	ev := PullRequestEvent{
+		Type:     string(e.PullRequestEventType),
+		Date:     aws.ToTime(e.EventDate),
+		ActorARN: aws.ToString(e.ActorArn),
+	}
+	switch e.PullRequestEventType {
+	case codecommittypes.PullRequestEventTypePullRequestStatusChanged:
+		if m := e.PullRequestStatusChangedEventMetadata; m != nil {
+			ev.Status = string(m.PullRequestStatus)
+		}
+	case codecommittypes.PullRequestEventTypePullRequestMergeStateChanged:
+		if m := e.PullRequestMergedStateChangedEventMetadata; m != nil && m.MergeMetadata != nil {
+			ev.IsMerged = m.MergeMetadata.IsMerged
+		}
+		if !ev.IsMerged {
+			return nil
+		}
+	case codecommittypes.PullRequestEventTypePullRequestApprovalStateChanged:
+		if m := e.ApprovalStateChangedEventMetadata; m != nil {
+			ev.ApprovalState = string(m.ApprovalStatus)
+			ev.RevisionID = aws.ToString(m.RevisionId)
+		}
+	default:
+		return nil
+	}
+	return &ev
+}
@@ -0,0 +1,97 @@
+package awscodecommit
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+)
+
+// PullRequestStateChangeDetailType is the EventBridge detail type of pull
+// request events sent by AWS CodeCommit.
+const PullRequestStateChangeDetailType = "CodeCommit Pull Request State Change"
+
+// PullRequestStateChangeEvent is an EventBridge event sent by AWS CodeCommit
+// when a pull request changes, forwarded to us by an EventBridge API
+// destination.
+type PullRequestStateChangeEvent struct {
+	DetailType string    `json:"detail-type"`
+	Source     string    `json:"source"`
+	Account    string    `json:"account"`
+	Region     string    `json:"region"`
+	Time       time.Time `json:"time"`
+	Resources  []string  `json:"resources"`
+	Detail     struct {
+		Event             string   `json:"event"`
+		PullRequestID     string   `json:"pullRequestId"`
+		PullRequestStatus string   `json:"pullRequestStatus"`
+		IsMerged          string   `json:"isMerged"`
+		RepositoryNames   []string `json:"repositoryNames"`
+		CallerUserARN     string   `json:"callerUserArn"`
+		RevisionID        string   `json:"revisionId"`
+		ApprovalStatus    string   `json:"approvalStatus"`
+	} `json:"detail"`
+}
+
+// ParsePullRequestStateChangeEvent parses an EventBridge event payload. It
+// returns an error if the event is not a pull request state change.
+func ParsePullRequestStateChangeEvent(payload []byte) (*PullRequestStateChangeEvent, error) {
+	var e PullRequestStateChangeEvent
+	if err := json.Unmarshal(payload, &e); err != nil {
+		return nil, err
+	}
+	if e.Source != "aws.codecommit" || e.DetailType != PullRequestStateChangeDetailType {
+		return nil, errors.Errorf("unexpected event %q from %q", e.DetailType, e.Source)
+	}
+	return &e, nil
+}
+
+// RepositoryName returns the name of the repository of the pull request.
+func (e *PullRequestStateChangeEvent) RepositoryName() string {
+	if len(e.Detail.RepositoryNames) == 0 {
+		return ""
+	}
+	return e.Detail.RepositoryNames[0]
+}
+
+// ServiceID returns the external service ID of the repository the event was
+// sent for: the prefix of its ARN up to the repository name, of the form
+// arn:<partition>:codecommit:<region>:<account ID>:.
+func (e *PullRequestStateChangeEvent) ServiceID() string {
+	// The resources are repository ARNs, which start with the service ID and
+	// end with the repository name.
+	for _, arn := range e.Resources {
+		if i := strings.LastIndex(arn, ":"); i >= 0 {
+			return arn[:i+1]
+		}
+	}
+	return ""
+}
+
+// PullRequestEvent converts e to the PullRequestEvent it describes, or
+// returns nil if it describes a change we don't track.
+func (e *PullRequestStateChangeEvent) PullRequestEvent() *PullRequestEvent {
+	ev := PullRequestEvent{
+		Date:     e.Time,
+		ActorARN: e.Detail.CallerUserARN,
+	}
+	switch e.Detail.Event {
+	case "pullRequestStatusChanged":
+		ev.Type = PullRequestEventStatusChanged
+		ev.Status = strings.ToUpper(e.Detail.PullRequestStatus)
+	case "pullRequestMergeStatusUpdated":
+		ev.Type = PullRequestEventMergeStateChanged
+		ev.IsMerged = strings.EqualFold(e.Detail.IsMerged, "true")
+		if !ev.IsMerged {
+			return nil
+		}
+	case "pullRequestApprovalStateChanged":
+		ev.Type = PullRequestEventApprovalStateChanged
+		ev.ApprovalState = e.Detail.ApprovalStatus
+		ev.RevisionID = e.Detail.RevisionID
+	default:
+		return nil
+	}
+	return &ev
+}
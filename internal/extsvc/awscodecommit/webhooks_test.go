@@ -0,0 +1,57 @@
+package awscodecommit
+
+import (
+	"testing"
+)
+
+func TestParsePullRequestStateChangeEvent(t *testing.T) {
+	const payload = `{
+		"detail-type": "CodeCommit Pull Request State Change",
+		"source": "aws.codecommit",
+		"account": "123456789012",
+		"time": "2021-09-01T10:00:00Z",
+		"region": "us-east-1",
+		"resources": ["arn:aws:codecommit:us-east-1:123456789012:my-repo"],
+		"detail": {
+			"event": "pullRequestMergeStatusUpdated",
+			"pullRequestId": "42",
+			"pullRequestStatus": "Closed",
+			"isMerged": "True",
+			"repositoryNames": ["my-repo"],
+			"callerUserArn": "arn:aws:iam::123456789012:user/alice"
+		}
+	}`
+
+	e, err := ParsePullRequestStateChangeEvent([]byte(payload))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if have, want := e.RepositoryName(), "my-repo"; have != want {
+		t.Errorf("RepositoryName() = %q, want %q", have, want)
+	}
+	if have, want := e.ServiceID(), ServiceID("aws", "us-east-1", "123456789012"); have != want {
+		t.Errorf("ServiceID() = %q, want %q", have, want)
+	}
+
+	ev := e.PullRequestEvent()
+	if ev == nil {
+		t.Fatal("expected merge event")
+	}
+	if ev.Type != PullRequestEventMergeStateChanged || !ev.IsMerged || ev.ActorARN != "arn:aws:iam::123456789012:user/alice" {
+		t.Errorf("unexpected event %+v", ev)
+	}
+
+	t.Run("unmerged merge status update", func(t *testing.T) {
+		e.Detail.IsMerged = "False"
+		if ev := e.PullRequestEvent(); ev != nil {
+			t.Errorf("expected no event, got %+v", ev)
+		}
+	})
+
+	t.Run("other source", func(t *testing.T) {
+		if _, err := ParsePullRequestStateChangeEvent([]byte(`{"source":"aws.s3","detail-type":"Object Created"}`)); err == nil {
+			t.Error("expected error for event from other source")
+		}
+	})
+}
@@ -86,6 +86,15 @@ func NewClient(apiURL *url.URL, httpClient httpcli.Doer) *Client {
 	}
 }
 
+// WithCredentials returns a copy of the client that authenticates with the
+// given username and app password.
+func (c *Client) WithCredentials(username, appPassword string) *Client {
+	cc := *c
+	cc.Username = username
+	cc.AppPassword = appPassword
+	return &cc
+}
+
 // Repos returns a list of repositories that are fetched and populated based on given account
 // name and pagination criteria. If the account requested is a team, results will be filtered
 // down to the ones that the app password's user has access to.
@@ -0,0 +1,103 @@
+package bitbucketcloud
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+// Workspace is the subset of workspace fields returned by the permissions
+// APIs.
+type Workspace struct {
+	Slug string `json:"slug"`
+	Name string `json:"name"`
+	UUID string `json:"uuid"`
+}
+
+// WorkspacePermission is the permission a user has in a workspace: "owner",
+// "collaborator" or "member".
+type WorkspacePermission struct {
+	Permission string    `json:"permission"`
+	User       User      `json:"user"`
+	Workspace  Workspace `json:"workspace"`
+}
+
+// WorkspaceMembership is the membership of a user in a workspace.
+type WorkspaceMembership struct {
+	User      User      `json:"user"`
+	Workspace Workspace `json:"workspace"`
+}
+
+// RepoPermission is the effective permission a user has on a repository:
+// "admin", "write" or "read". Permissions granted through groups are
+// included.
+type RepoPermission struct {
+	Permission string `json:"permission"`
+	User       User   `json:"user"`
+	Repo       Repo   `json:"repository"`
+}
+
+// CurrentUserWorkspacePermission returns the permission of the authenticated
+// user in the given workspace, or nil if the user is not a member of the
+// workspace.
+func (c *Client) CurrentUserWorkspacePermission(ctx context.Context, workspace string) (*WorkspacePermission, error) {
+	qry := url.Values{"q": []string{fmt.Sprintf("workspace.slug=%q", workspace)}}
+
+	var perms []*WorkspacePermission
+	if _, err := c.page(ctx, "/2.0/user/permissions/workspaces", qry, nil, &perms); err != nil {
+		return nil, err
+	}
+	if len(perms) == 0 {
+		return nil, nil
+	}
+	return perms[0], nil
+}
+
+// WorkspaceMembers returns a page of the members of the given workspace.
+// If the argument pageToken.Next is not empty, it will be used directly as
+// the URL to make the request.
+func (c *Client) WorkspaceMembers(ctx context.Context, pageToken *PageToken, workspace string) ([]*WorkspaceMembership, *PageToken, error) {
+	var members []*WorkspaceMembership
+	var next *PageToken
+	var err error
+	if pageToken.HasMore() {
+		next, err = c.reqPage(ctx, pageToken.Next, &members)
+	} else {
+		next, err = c.page(ctx, fmt.Sprintf("/2.0/workspaces/%s/members", workspace), nil, pageToken, &members)
+	}
+	return members, next, err
+}
+
+// UserRepoPermissions returns a page of the repository permissions of the
+// user with the given UUID in the given workspace. Only workspace
+// administrators may list the permissions of other users. If the argument
+// pageToken.Next is not empty, it will be used directly as the URL to make
+// the request.
+func (c *Client) UserRepoPermissions(ctx context.Context, pageToken *PageToken, workspace, userUUID string) ([]*RepoPermission, *PageToken, error) {
+	var perms []*RepoPermission
+	var next *PageToken
+	var err error
+	if pageToken.HasMore() {
+		next, err = c.reqPage(ctx, pageToken.Next, &perms)
+	} else {
+		qry := url.Values{"q": []string{fmt.Sprintf("user.uuid=%q", userUUID)}}
+		next, err = c.page(ctx, fmt.Sprintf("/2.0/workspaces/%s/permissions/repositories", workspace), qry, pageToken, &perms)
+	}
+	return perms, next, err
+}
+
+// RepoPermissions returns a page of the user permissions on the repository
+// with the given slug in the given workspace. Only workspace administrators
+// may list repository permissions. If the argument pageToken.Next is not
+// empty, it will be used directly as the URL to make the request.
+func (c *Client) RepoPermissions(ctx context.Context, pageToken *PageToken, workspace, slug string) ([]*RepoPermission, *PageToken, error) {
+	var perms []*RepoPermission
+	var next *PageToken
+	var err error
+	if pageToken.HasMore() {
+		next, err = c.reqPage(ctx, pageToken.Next, &perms)
+	} else {
+		next, err = c.page(ctx, fmt.Sprintf("/2.0/workspaces/%s/permissions/repositories/%s", workspace, slug), nil, pageToken, &perms)
+	}
+	return perms, next, err
+}
@@ -0,0 +1,339 @@
+package bitbucketcloud
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+)
+
+// PullRequestState is the state of a Bitbucket Cloud pull request.
+type PullRequestState string
+
+const (
+	PullRequestStateOpen       PullRequestState = "OPEN"
+	PullRequestStateMerged     PullRequestState = "MERGED"
+	PullRequestStateDeclined   PullRequestState = "DECLINED"
+	PullRequestStateSuperseded PullRequestState = "SUPERSEDED"
+)
+
+// PullRequest is a Bitbucket Cloud pull request.
+type PullRequest struct {
+	ID           int64               `json:"id"`
+	Title        string              `json:"title"`
+	Description  string              `json:"description"`
+	State        PullRequestState    `json:"state"`
+	Author       User                `json:"author"`
+	Source       PullRequestEndpoint `json:"source"`
+	Destination  PullRequestEndpoint `json:"destination"`
+	Participants []Participant       `json:"participants"`
+	CreatedOn    time.Time           `json:"created_on"`
+	UpdatedOn    time.Time           `json:"updated_on"`
+	Links        PullRequestLinks    `json:"links"`
+
+	// Statuses are the build statuses of the source commit. They are not
+	// part of the pull request API response and are loaded separately with
+	// PullRequestStatuses.
+	Statuses []*CommitStatus `json:"statuses,omitempty"`
+}
+
+// PullRequestEndpoint is the source or destination of a pull request.
+type PullRequestEndpoint struct {
+	Repo   PullRequestRepo `json:"repository"`
+	Branch struct {
+		Name string `json:"name"`
+	} `json:"branch"`
+	Commit struct {
+		Hash string `json:"hash"`
+	} `json:"commit"`
+}
+
+// PullRequestRepo is the subset of repository fields returned for the
+// endpoints of a pull request.
+type PullRequestRepo struct {
+	FullName string `json:"full_name"`
+	UUID     string `json:"uuid"`
+}
+
+type PullRequestLinks struct {
+	HTML Link `json:"html"`
+}
+
+// Participant is a user taking part in a pull request, either as reviewer or
+// because they commented on it.
+type Participant struct {
+	User     User   `json:"user"`
+	Role     string `json:"role"`
+	Approved bool   `json:"approved"`
+	// State is one of "approved", "changes_requested" or empty.
+	State string `json:"state"`
+}
+
+const (
+	ParticipantStateApproved         = "approved"
+	ParticipantStateChangesRequested = "changes_requested"
+)
+
+// User is a Bitbucket Cloud account.
+type User struct {
+	AccountID   string `json:"account_id"`
+	DisplayName string `json:"display_name"`
+	Nickname    string `json:"nickname"`
+	UUID        string `json:"uuid"`
+}
+
+// CommitStatus is a build status reported for a commit.
+type CommitStatus struct {
+	// Key identifies the status in the context of its commit. Reporting a
+	// status with the same key again replaces the previous one.
+	Key   string `json:"key"`
+	Name  string `json:"name"`
+	URL   string `json:"url"`
+	State string `json:"state"`
+
+	CreatedOn time.Time `json:"created_on"`
+	UpdatedOn time.Time `json:"updated_on"`
+
+	Commit struct {
+		Hash string `json:"hash"`
+	} `json:"commit"`
+	Links struct {
+		Commit Link `json:"commit"`
+	} `json:"links"`
+}
+
+// CommitHash returns the hash of the commit the status was reported for.
+// Webhook payloads only link to the commit, so the hash is taken from the
+// link if it is not set.
+func (s *CommitStatus) CommitHash() string {
+	if s.Commit.Hash != "" {
+		return s.Commit.Hash
+	}
+	href := s.Links.Commit.Href
+	if i := strings.LastIndex(href, "/"); i >= 0 {
+		return href[i+1:]
+	}
+	return ""
+}
+
+// PullRequestInput is the set of fields used to create or update a pull
+// request.
+type PullRequestInput struct {
+	Title        string
+	Description  string
+	SourceBranch string
+	// DestinationBranch defaults to the main branch of the repository when
+	// empty.
+	DestinationBranch string
+}
+
+func (input *PullRequestInput) MarshalJSON() ([]byte, error) {
+	type branch struct {
+		Name string `json:"name"`
+	}
+	type endpoint struct {
+		Branch branch `json:"branch"`
+	}
+	body := struct {
+		Title       string    `json:"title"`
+		Description string    `json:"description"`
+		Source      endpoint  `json:"source"`
+		Destination *endpoint `json:"destination,omitempty"`
+	}{
+		Title:       input.Title,
+		Description: input.Description,
+		Source:      endpoint{Branch: branch{Name: input.SourceBranch}},
+	}
+	if input.DestinationBranch != "" {
+		body.Destination = &endpoint{Branch: branch{Name: input.DestinationBranch}}
+	}
+	return json.Marshal(body)
+}
+
+// MergeStrategy is the strategy used to merge a pull request.
+type MergeStrategy string
+
+const (
+	MergeStrategyMergeCommit MergeStrategy = "merge_commit"
+	MergeStrategySquash      MergeStrategy = "squash"
+	MergeStrategyFastForward MergeStrategy = "fast_forward"
+)
+
+// CurrentUser returns the user the client is authenticated as.
+func (c *Client) CurrentUser(ctx context.Context) (*User, error) {
+	req, err := http.NewRequest("GET", "/2.0/user", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var user User
+	if err := c.do(ctx, req, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// CreatePullRequest opens a pull request in the repository with the given
+// full name ("workspace/slug").
+func (c *Client) CreatePullRequest(ctx context.Context, repo string, input *PullRequestInput) (*PullRequest, error) {
+	req, err := newJSONRequest("POST", pullRequestsPath(repo), input)
+	if err != nil {
+		return nil, err
+	}
+
+	var pr PullRequest
+	if err := c.do(ctx, req, &pr); err != nil {
+		return nil, err
+	}
+	return &pr, nil
+}
+
+// FindOpenPullRequest returns the open pull request from sourceBranch into
+// destinationBranch, or nil if there is none.
+func (c *Client) FindOpenPullRequest(ctx context.Context, repo, sourceBranch, destinationBranch string) (*PullRequest, error) {
+	q := fmt.Sprintf(
+		"state = %q AND source.branch.name = %q AND destination.branch.name = %q",
+		PullRequestStateOpen, sourceBranch, destinationBranch,
+	)
+
+	var prs []*PullRequest
+	if _, err := c.page(ctx, pullRequestsPath(repo), url.Values{"q": []string{q}}, nil, &prs); err != nil {
+		return nil, err
+	}
+	if len(prs) == 0 {
+		return nil, nil
+	}
+	return prs[0], nil
+}
+
+// PullRequest fetches a single pull request.
+func (c *Client) PullRequest(ctx context.Context, repo string, id int64) (*PullRequest, error) {
+	req, err := http.NewRequest("GET", pullRequestPath(repo, id, ""), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var pr PullRequest
+	if err := c.do(ctx, req, &pr); err != nil {
+		return nil, err
+	}
+	return &pr, nil
+}
+
+// UpdatePullRequest updates the title, description and destination branch of
+// a pull request.
+func (c *Client) UpdatePullRequest(ctx context.Context, repo string, id int64, input *PullRequestInput) (*PullRequest, error) {
+	req, err := newJSONRequest("PUT", pullRequestPath(repo, id, ""), input)
+	if err != nil {
+		return nil, err
+	}
+
+	var pr PullRequest
+	if err := c.do(ctx, req, &pr); err != nil {
+		return nil, err
+	}
+	return &pr, nil
+}
+
+// DeclinePullRequest declines (closes) a pull request. Declined pull requests
+// cannot be reopened on Bitbucket Cloud.
+func (c *Client) DeclinePullRequest(ctx context.Context, repo string, id int64) (*PullRequest, error) {
+	req, err := http.NewRequest("POST", pullRequestPath(repo, id, "decline"), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var pr PullRequest
+	if err := c.do(ctx, req, &pr); err != nil {
+		return nil, err
+	}
+	return &pr, nil
+}
+
+// MergePullRequest merges a pull request using the given strategy. An empty
+// strategy uses the repository default.
+func (c *Client) MergePullRequest(ctx context.Context, repo string, id int64, strategy MergeStrategy) (*PullRequest, error) {
+	body := struct {
+		MergeStrategy MergeStrategy `json:"merge_strategy,omitempty"`
+	}{strategy}
+	req, err := newJSONRequest("POST", pullRequestPath(repo, id, "merge"), body)
+	if err != nil {
+		return nil, err
+	}
+
+	var pr PullRequest
+	if err := c.do(ctx, req, &pr); err != nil {
+		return nil, err
+	}
+	return &pr, nil
+}
+
+// CreatePullRequestComment posts a comment on a pull request.
+func (c *Client) CreatePullRequestComment(ctx context.Context, repo string, id int64, text string) error {
+	body := struct {
+		Content struct {
+			Raw string `json:"raw"`
+		} `json:"content"`
+	}{}
+	body.Content.Raw = text
+
+	req, err := newJSONRequest("POST", pullRequestPath(repo, id, "comments"), body)
+	if err != nil {
+		return err
+	}
+	return c.do(ctx, req, nil)
+}
+
+// PullRequestStatuses returns all build statuses of the source commit of a
+// pull request.
+func (c *Client) PullRequestStatuses(ctx context.Context, repo string, id int64) ([]*CommitStatus, error) {
+	var all []*CommitStatus
+
+	var statuses []*CommitStatus
+	next, err := c.page(ctx, pullRequestPath(repo, id, "statuses"), nil, nil, &statuses)
+	for {
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, statuses...)
+		if !next.HasMore() {
+			return all, nil
+		}
+		statuses = nil
+		next, err = c.reqPage(ctx, next.Next, &statuses)
+	}
+}
+
+// IsNotMergeable reports whether err was returned because a pull request
+// could not be merged, for example because of conflicts or unmet merge checks.
+func IsNotMergeable(err error) bool {
+	var e *httpError
+	return errors.As(err, &e) && (e.StatusCode == http.StatusBadRequest || e.StatusCode == http.StatusConflict || e.StatusCode == http.StatusUnprocessableEntity)
+}
+
+func pullRequestsPath(repo string) string {
+	return "/2.0/repositories/" + strings.Trim(repo, "/") + "/pullrequests"
+}
+
+func pullRequestPath(repo string, id int64, action string) string {
+	p := pullRequestsPath(repo) + "/" + strconv.FormatInt(id, 10)
+	if action != "" {
+		p += "/" + action
+	}
+	return p
+}
+
+func newJSONRequest(method, path string, body interface{}) (*http.Request, error) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	return http.NewRequest(method, path, bytes.NewReader(data))
+}
@@ -0,0 +1,121 @@
+package bitbucketcloud
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/cockroachdb/errors"
+)
+
+// EventKeyHeader is the HTTP header Bitbucket Cloud uses to send the type of
+// a webhook event.
+const EventKeyHeader = "X-Event-Key"
+
+// PullRequestEvent is sent when a pull request is merged ("fulfilled") or
+// declined ("rejected"), and for the base of all other pull request events.
+type PullRequestEvent struct {
+	// EventKey is the X-Event-Key the event was sent with.
+	EventKey    string          `json:"event_key,omitempty"`
+	Actor       User            `json:"actor"`
+	PullRequest PullRequest     `json:"pullrequest"`
+	Repository  PullRequestRepo `json:"repository"`
+}
+
+// Key is a unique key identifying this event in the context of its pull
+// request.
+func (e *PullRequestEvent) Key() string {
+	return e.EventKey + ":" + e.PullRequest.UpdatedOn.UTC().String()
+}
+
+// PullRequestApprovalEvent is sent when a pull request is approved or an
+// approval is withdrawn.
+type PullRequestApprovalEvent struct {
+	PullRequestEvent
+	Approval Approval `json:"approval"`
+}
+
+// PullRequestChangesRequestEvent is sent when a participant requests changes
+// on a pull request or withdraws that request.
+type PullRequestChangesRequestEvent struct {
+	PullRequestEvent
+	ChangesRequest Approval `json:"changes_request"`
+}
+
+// Approval is the approval or changes request of a single participant.
+type Approval struct {
+	Date time.Time `json:"date"`
+	User User      `json:"user"`
+}
+
+func (a *Approval) key(eventKey string) string {
+	return eventKey + ":" + a.User.UUID + ":" + a.Date.UTC().String()
+}
+
+// Key is a unique key identifying this event in the context of its pull
+// request.
+func (e *PullRequestApprovalEvent) Key() string {
+	return e.Approval.key(e.EventKey)
+}
+
+// Key is a unique key identifying this event in the context of its pull
+// request.
+func (e *PullRequestChangesRequestEvent) Key() string {
+	return e.ChangesRequest.key(e.EventKey)
+}
+
+// RepoCommitStatusEvent is sent when a build status is created or updated for
+// a commit. It is not tied to a pull request.
+type RepoCommitStatusEvent struct {
+	// EventKey is the X-Event-Key the event was sent with.
+	EventKey     string          `json:"event_key,omitempty"`
+	Actor        User            `json:"actor"`
+	Repository   PullRequestRepo `json:"repository"`
+	CommitStatus CommitStatus    `json:"commit_status"`
+}
+
+// Key is a unique key identifying this event in the context of its pull
+// request. Later statuses with the same key replace earlier ones, like they
+// do on Bitbucket Cloud.
+func (e *RepoCommitStatusEvent) Key() string {
+	return e.CommitStatus.CommitHash() + ":" + e.CommitStatus.Key
+}
+
+// Webhook event keys sent in the EventKeyHeader.
+const (
+	EventKeyPullRequestApproved              = "pullrequest:approved"
+	EventKeyPullRequestUnapproved            = "pullrequest:unapproved"
+	EventKeyPullRequestChangesRequestCreated = "pullrequest:changes_request_created"
+	EventKeyPullRequestChangesRequestRemoved = "pullrequest:changes_request_removed"
+	EventKeyPullRequestFulfilled             = "pullrequest:fulfilled"
+	EventKeyPullRequestRejected              = "pullrequest:rejected"
+	EventKeyPullRequestCreated               = "pullrequest:created"
+	EventKeyPullRequestUpdated               = "pullrequest:updated"
+	EventKeyRepoCommitStatusCreated          = "repo:commit_status_created"
+	EventKeyRepoCommitStatusUpdated          = "repo:commit_status_updated"
+)
+
+// ParseWebhookEvent parses the payload of a webhook event with the given
+// event key. It returns one of *PullRequestEvent, *PullRequestApprovalEvent,
+// *PullRequestChangesRequestEvent or *RepoCommitStatusEvent.
+func ParseWebhookEvent(eventKey string, payload []byte) (interface{}, error) {
+	var e interface{}
+	switch eventKey {
+	case EventKeyPullRequestApproved, EventKeyPullRequestUnapproved:
+		e = &PullRequestApprovalEvent{PullRequestEvent: PullRequestEvent{EventKey: eventKey}}
+	case EventKeyPullRequestChangesRequestCreated, EventKeyPullRequestChangesRequestRemoved:
+		e = &PullRequestChangesRequestEvent{PullRequestEvent: PullRequestEvent{EventKey: eventKey}}
+	case EventKeyPullRequestFulfilled, EventKeyPullRequestRejected,
+		EventKeyPullRequestCreated, EventKeyPullRequestUpdated:
+		e = &PullRequestEvent{EventKey: eventKey}
+	case EventKeyRepoCommitStatusCreated, EventKeyRepoCommitStatusUpdated:
+		e = &RepoCommitStatusEvent{EventKey: eventKey}
+	default:
+		return nil, errors.Errorf("unknown webhook event key %s", strconv.Quote(eventKey))
+	}
+
+	if err := json.Unmarshal(payload, e); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
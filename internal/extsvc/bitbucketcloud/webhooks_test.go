@@ -0,0 +1,60 @@
+package bitbucketcloud
+
+import (
+	"testing"
+)
+
+func TestParseWebhookEvent(t *testing.T) {
+	t.Run("approval", func(t *testing.T) {
+		payload := `{
+			"actor": {"nickname": "alice", "uuid": "{a}"},
+			"pullrequest": {"id": 7, "state": "OPEN"},
+			"repository": {"full_name": "sourcegraph/src-cli", "uuid": "{r}"},
+			"approval": {"date": "2021-09-01T10:00:00Z", "user": {"nickname": "alice", "uuid": "{a}"}}
+		}`
+
+		e, err := ParseWebhookEvent(EventKeyPullRequestApproved, []byte(payload))
+		if err != nil {
+			t.Fatal(err)
+		}
+		have, ok := e.(*PullRequestApprovalEvent)
+		if !ok {
+			t.Fatalf("unexpected event type %T", e)
+		}
+		if have.PullRequest.ID != 7 || have.Repository.UUID != "{r}" {
+			t.Errorf("unexpected event %+v", have)
+		}
+		if want := "pullrequest:approved:{a}:2021-09-01 10:00:00 +0000 UTC"; have.Key() != want {
+			t.Errorf("Key() = %q, want %q", have.Key(), want)
+		}
+	})
+
+	t.Run("commit status", func(t *testing.T) {
+		payload := `{
+			"repository": {"full_name": "sourcegraph/src-cli", "uuid": "{r}"},
+			"commit_status": {
+				"key": "ci",
+				"state": "SUCCESSFUL",
+				"links": {"commit": {"href": "https://api.bitbucket.org/2.0/repositories/sourcegraph/src-cli/commit/abcdef0123456789"}}
+			}
+		}`
+
+		e, err := ParseWebhookEvent(EventKeyRepoCommitStatusCreated, []byte(payload))
+		if err != nil {
+			t.Fatal(err)
+		}
+		have, ok := e.(*RepoCommitStatusEvent)
+		if !ok {
+			t.Fatalf("unexpected event type %T", e)
+		}
+		if want := "abcdef0123456789:ci"; have.Key() != want {
+			t.Errorf("Key() = %q, want %q", have.Key(), want)
+		}
+	})
+
+	t.Run("unknown event key", func(t *testing.T) {
+		if _, err := ParseWebhookEvent("repo:push", []byte(`{}`)); err == nil {
+			t.Error("expected error for unknown event key")
+		}
+	})
+}
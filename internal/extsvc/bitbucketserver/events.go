@@ -30,6 +30,15 @@ func ParseWebhookEvent(eventType string, payload []byte) (e interface{}, err err
 	case "pr:participant:status":
 		e = &PullRequestParticipantStatusEvent{}
 		return e, json.Unmarshal(payload, e)
+	case "repo:refs_changed":
+		e = &RefsChangedEvent{}
+		return e, json.Unmarshal(payload, e)
+	case "repo:modified":
+		e = &RepoModifiedEvent{}
+		return e, json.Unmarshal(payload, e)
+	case "repo:forked":
+		e = &RepoForkedEvent{}
+		return e, json.Unmarshal(payload, e)
 	default:
 		return nil, errors.Errorf("unknown webhook event type: %q", eventType)
 	}
@@ -65,3 +74,31 @@ type BuildStatusEvent struct {
 	Status       BuildStatus   `json:"status"`
 	PullRequests []PullRequest `json:"pullRequests"`
 }
+
+// RefsChangedEvent is sent when refs of a repository are pushed to.
+type RefsChangedEvent struct {
+	Actor      User        `json:"actor"`
+	Repository Repo        `json:"repository"`
+	Changes    []RefChange `json:"changes"`
+}
+
+type RefChange struct {
+	RefID    string `json:"refId"`
+	FromHash string `json:"fromHash"`
+	ToHash   string `json:"toHash"`
+	Type     string `json:"type"`
+}
+
+// RepoModifiedEvent is sent when a repository is renamed or moved.
+type RepoModifiedEvent struct {
+	Actor User `json:"actor"`
+	Old   Repo `json:"old"`
+	New   Repo `json:"new"`
+}
+
+// RepoForkedEvent is sent when a repository is forked. Repository is the
+// newly created fork.
+type RepoForkedEvent struct {
+	Actor      User `json:"actor"`
+	Repository Repo `json:"repository"`
+}
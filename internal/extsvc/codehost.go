@@ -24,10 +24,18 @@ var (
 	MavenURL    = &url.URL{Host: "maven"}
 	JVMPackages = NewCodeHost(MavenURL, TypeJVMPackages)
 
+	NpmURL      = &url.URL{Host: "npm"}
+	NpmPackages = NewCodeHost(NpmURL, TypeNpmPackages)
+
+	GoURL     = &url.URL{Host: "go"}
+	GoModules = NewCodeHost(GoURL, TypeGoModules)
+
 	PublicCodeHosts = []*CodeHost{
 		GitHubDotCom,
 		GitLabDotCom,
 		JVMPackages,
+		NpmPackages,
+		GoModules,
 	}
 )
 
@@ -65,8 +73,11 @@ func NormalizeBaseURL(baseURL *url.URL) *url.URL {
 // determined by a common prefix between the repo name and the
 // code hosts' URL hostname component.
 func CodeHostOf(name api.RepoName, codehosts ...*CodeHost) *CodeHost {
+	lower := strings.ToLower(string(name))
 	for _, c := range codehosts {
-		if strings.HasPrefix(strings.ToLower(string(name)), c.BaseURL.Hostname()) {
+		// Match whole path components so that e.g. "golang.org/x/mod" is
+		// not attributed to the "go" code host.
+		if host := c.BaseURL.Hostname(); lower == host || strings.HasPrefix(lower, host+"/") {
 			return c
 		}
 	}
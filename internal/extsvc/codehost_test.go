@@ -32,6 +32,21 @@ func TestCodeHostOf(t *testing.T) {
 		repo:      "GITHUB.COM/foo/bar",
 		codehosts: PublicCodeHosts,
 		want:      GitHubDotCom,
+	}, {
+		name:      "npm",
+		repo:      "npm/types/node",
+		codehosts: PublicCodeHosts,
+		want:      NpmPackages,
+	}, {
+		name:      "go",
+		repo:      "go/golang.org/x/mod",
+		codehosts: PublicCodeHosts,
+		want:      GoModules,
+	}, {
+		name:      "host prefix",
+		repo:      "golang.org/x/mod",
+		codehosts: PublicCodeHosts,
+		want:      nil,
 	}} {
 		t.Run(tc.name, func(t *testing.T) {
 			have := CodeHostOf(tc.repo, tc.codehosts...)
@@ -0,0 +1,284 @@
+// Package gerrit implements a Gerrit API client.
+package gerrit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/inconshreveable/log15"
+	"github.com/opentracing-contrib/go-stdlib/nethttp"
+	"golang.org/x/time/rate"
+
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/metrics"
+	"github.com/sourcegraph/sourcegraph/internal/ratelimit"
+	"github.com/sourcegraph/sourcegraph/internal/trace/ot"
+)
+
+var requestCounter = metrics.NewRequestMeter("gerrit_requests_count", "Total number of requests sent to the Gerrit API.")
+
+// These fields define the self-imposed Gerrit rate limit, since Gerrit does
+// not report rate limits in HTTP response headers.
+//
+// See https://godoc.org/golang.org/x/time/rate#Limiter for an explanation of these fields.
+const (
+	rateLimitRequestsPerSecond = 2 // 120/min or 7200/hr
+	RateLimitMaxBurstRequests  = 500
+)
+
+// DefaultPageLimit is the number of projects requested per page.
+const DefaultPageLimit = 100
+
+// xssiPrefix is prepended by Gerrit to all JSON responses to prevent XSSI.
+// See https://gerrit-review.googlesource.com/Documentation/rest-api.html#output.
+var xssiPrefix = []byte(")]}'")
+
+// Client access a Gerrit instance via its REST API.
+type Client struct {
+	// HTTP Client used to communicate with the API
+	httpClient httpcli.Doer
+
+	// URL is the base URL of the Gerrit instance.
+	URL *url.URL
+
+	// The username and HTTP password credentials for accessing the server.
+	Username, Password string
+
+	// runAs is the username of the user that requests are made on behalf
+	// of. It requires the "Run As" global capability.
+	runAs string
+
+	// RateLimit is the self-imposed rate limiter.
+	RateLimit *rate.Limiter
+}
+
+// NewClient creates a new Gerrit API client with the given base URL. If a nil
+// httpClient is provided, httpcli.ExternalDoer() will be used. Both Username and
+// Password fields are required to be set before calling any APIs.
+func NewClient(baseURL *url.URL, httpClient httpcli.Doer) *Client {
+	if httpClient == nil {
+		httpClient = httpcli.ExternalDoer()
+	}
+
+	httpClient = requestCounter.Doer(httpClient, func(u *url.URL) string {
+		// The component after /a/ mostly maps to the type of API request
+		// we are making.
+		var category string
+		if parts := strings.SplitN(strings.TrimPrefix(u.Path, "/a/"), "/", 2); len(parts) > 0 {
+			category = parts[0]
+		}
+		return category
+	})
+
+	defaultLimiter := rate.NewLimiter(rateLimitRequestsPerSecond, RateLimitMaxBurstRequests)
+	l := ratelimit.DefaultRegistry.GetOrSet(baseURL.String(), defaultLimiter)
+
+	return &Client{
+		httpClient: httpClient,
+		URL:        baseURL,
+		RateLimit:  l,
+	}
+}
+
+// RunAs returns a copy of the Client that makes requests on behalf of the
+// given user. The client's account must have the "Run As" global capability.
+func (c *Client) RunAs(username string) *Client {
+	rc := *c
+	rc.runAs = username
+	return &rc
+}
+
+// PageToken describes a page of projects. Paginated methods return the token
+// of the page following the one they requested. Callers should stop
+// requesting pages once HasMore returns false.
+type PageToken struct {
+	// Start is the number of projects to skip.
+	Start int
+	// Limit is the maximum number of projects per page.
+	Limit int
+	// next is set on returned tokens if Gerrit indicated that there are more
+	// projects.
+	next bool
+}
+
+// HasMore returns true if t is the token of a page that follows the last
+// returned page.
+func (t *PageToken) HasMore() bool {
+	return t != nil && t.next
+}
+
+func (t *PageToken) limit() int {
+	if t == nil || t.Limit < 1 {
+		return DefaultPageLimit
+	}
+	return t.Limit
+}
+
+func (t *PageToken) start() int {
+	if t == nil {
+		return 0
+	}
+	return t.Start
+}
+
+// ListProjects returns a page of the active and read-only projects visible
+// to the authenticated user.
+//
+// API docs: https://gerrit-review.googlesource.com/Documentation/rest-api-projects.html#query-projects
+func (c *Client) ListProjects(ctx context.Context, token *PageToken) ([]*Project, *PageToken, error) {
+	qry := url.Values{}
+	qry.Set("query", "state:active OR state:read-only")
+	qry.Set("n", strconv.Itoa(token.limit()))
+	qry.Set("S", strconv.Itoa(token.start()))
+
+	var projects []*Project
+	if err := c.get(ctx, "projects/", qry, &projects); err != nil {
+		return nil, nil, err
+	}
+
+	next := &PageToken{Start: token.start() + len(projects), Limit: token.limit()}
+	if n := len(projects); n > 0 {
+		next.next = projects[n-1].MoreProjects
+	}
+	return projects, next, nil
+}
+
+// GetProject returns the project with the given name.
+//
+// API docs: https://gerrit-review.googlesource.com/Documentation/rest-api-projects.html#get-project
+func (c *Client) GetProject(ctx context.Context, name string) (*Project, error) {
+	var project Project
+	err := c.get(ctx, "projects/"+url.PathEscape(name), nil, &project)
+	return &project, err
+}
+
+// GetAccount returns the account with the given username.
+//
+// API docs: https://gerrit-review.googlesource.com/Documentation/rest-api-accounts.html#get-account
+func (c *Client) GetAccount(ctx context.Context, username string) (*Account, error) {
+	var account Account
+	err := c.get(ctx, "accounts/"+url.PathEscape(username), nil, &account)
+	return &account, err
+}
+
+// HasCapability reports whether the authenticated user has the given global
+// capability, e.g. "runAs".
+//
+// API docs: https://gerrit-review.googlesource.com/Documentation/rest-api-accounts.html#list-account-capabilities
+func (c *Client) HasCapability(ctx context.Context, capability string) (bool, error) {
+	var capabilities map[string]interface{}
+	if err := c.get(ctx, "accounts/self/capabilities", url.Values{"q": []string{capability}}, &capabilities); err != nil {
+		return false, err
+	}
+	_, ok := capabilities[capability]
+	return ok, nil
+}
+
+func (c *Client) get(ctx context.Context, path string, qry url.Values, result interface{}) error {
+	// Requests to endpoints prefixed with /a/ are authenticated. path may
+	// contain escaped path segments, so we parse it rather than setting it
+	// as the URL's Path.
+	u, err := url.Parse("a/" + path)
+	if err != nil {
+		return err
+	}
+	u.RawQuery = qry.Encode()
+
+	req, err := http.NewRequest("GET", c.URL.ResolveReference(u).String(), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(c.Username, c.Password)
+	if c.runAs != "" {
+		req.Header.Set("X-Gerrit-RunAs", c.runAs)
+	}
+
+	req, ht := nethttp.TraceRequest(ot.GetTracer(ctx),
+		req.WithContext(ctx),
+		nethttp.OperationName("Gerrit"),
+		nethttp.ClientTrace(false))
+	defer ht.Finish()
+
+	startWait := time.Now()
+	if err := c.RateLimit.Wait(ctx); err != nil {
+		return err
+	}
+
+	if d := time.Since(startWait); d > 200*time.Millisecond {
+		log15.Warn("Gerrit self-enforced API rate limit: request delayed longer than expected due to rate limit", "delay", d)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	bs, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 400 {
+		return errors.WithStack(&httpError{
+			URL:        req.URL,
+			StatusCode: resp.StatusCode,
+			Body:       bs,
+		})
+	}
+
+	if result != nil {
+		return json.Unmarshal(bytes.TrimPrefix(bs, xssiPrefix), result)
+	}
+	return nil
+}
+
+// Project is a Gerrit project.
+type Project struct {
+	// ID is the URL-encoded name of the project.
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Parent      string `json:"parent,omitempty"`
+	Description string `json:"description,omitempty"`
+	State       string `json:"state,omitempty"`
+
+	// MoreProjects is set on the last project of a page if there are more
+	// projects.
+	MoreProjects bool `json:"_more_projects,omitempty"`
+}
+
+// Account is a Gerrit account.
+type Account struct {
+	ID       int64  `json:"_account_id"`
+	Name     string `json:"name,omitempty"`
+	Email    string `json:"email,omitempty"`
+	Username string `json:"username,omitempty"`
+}
+
+type httpError struct {
+	StatusCode int
+	URL        *url.URL
+	Body       []byte
+}
+
+func (e *httpError) Error() string {
+	return fmt.Sprintf("Gerrit API HTTP error: code=%d url=%q body=%q", e.StatusCode, e.URL, e.Body)
+}
+
+func (e *httpError) Unauthorized() bool {
+	return e.StatusCode == http.StatusUnauthorized
+}
+
+func (e *httpError) NotFound() bool {
+	return e.StatusCode == http.StatusNotFound
+}
@@ -0,0 +1,376 @@
+// Package gitea implements a Gitea API client.
+package gitea
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/inconshreveable/log15"
+	"github.com/opentracing-contrib/go-stdlib/nethttp"
+	"golang.org/x/time/rate"
+
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/metrics"
+	"github.com/sourcegraph/sourcegraph/internal/ratelimit"
+	"github.com/sourcegraph/sourcegraph/internal/trace/ot"
+)
+
+var requestCounter = metrics.NewRequestMeter("gitea_requests_count", "Total number of requests sent to the Gitea API.")
+
+// These fields define the self-imposed Gitea rate limit, since Gitea does not
+// report rate limits in HTTP response headers.
+//
+// See https://godoc.org/golang.org/x/time/rate#Limiter for an explanation of these fields.
+const (
+	rateLimitRequestsPerSecond = 2 // 120/min or 7200/hr
+	RateLimitMaxBurstRequests  = 500
+)
+
+// DefaultPageLimit is the number of items requested per page. Gitea caps it
+// at its MAX_RESPONSE_ITEMS setting, which defaults to 50.
+const DefaultPageLimit = 50
+
+// Client access a Gitea instance via its REST API v1.
+type Client struct {
+	// HTTP Client used to communicate with the API
+	httpClient httpcli.Doer
+
+	// URL is the base URL of the Gitea instance.
+	URL *url.URL
+
+	// Token is the access token used to authenticate requests.
+	Token string
+
+	// sudo is the username of the user that requests are made on behalf of.
+	// It is only honored by Gitea if Token belongs to a site admin.
+	sudo string
+
+	// RateLimit is the self-imposed rate limiter.
+	RateLimit *rate.Limiter
+}
+
+// NewClient creates a new Gitea API client with the given base URL. If a nil
+// httpClient is provided, httpcli.ExternalDoer() will be used.
+func NewClient(baseURL *url.URL, token string, httpClient httpcli.Doer) *Client {
+	if httpClient == nil {
+		httpClient = httpcli.ExternalDoer()
+	}
+
+	httpClient = requestCounter.Doer(httpClient, func(u *url.URL) string {
+		// The component after /api/v1/ mostly maps to the type of API
+		// request we are making.
+		var category string
+		if parts := strings.SplitN(strings.TrimPrefix(u.Path, "/api/v1/"), "/", 2); len(parts) > 0 {
+			category = parts[0]
+		}
+		return category
+	})
+
+	defaultLimiter := rate.NewLimiter(rateLimitRequestsPerSecond, RateLimitMaxBurstRequests)
+	l := ratelimit.DefaultRegistry.GetOrSet(baseURL.String(), defaultLimiter)
+
+	return &Client{
+		httpClient: httpClient,
+		URL:        baseURL,
+		Token:      token,
+		RateLimit:  l,
+	}
+}
+
+// Sudo returns a copy of the Client that makes requests on behalf of the given
+// user. The client's token must belong to a site admin.
+func (c *Client) Sudo(username string) *Client {
+	sc := *c
+	sc.sudo = username
+	return &sc
+}
+
+// PageToken describes a page of results of a paginated Gitea API endpoint.
+// Paginated methods return the token of the page following the one they
+// requested. Callers should stop requesting pages once HasMore returns false.
+type PageToken struct {
+	// Page is the 1-based number of the page to request.
+	Page int
+	// Limit is the maximum number of results per page.
+	Limit int
+	// next is set on returned tokens if the Link header of the response
+	// pointed to a next page.
+	next bool
+}
+
+// HasMore returns true if t is the token of a page that follows the last
+// returned page.
+func (t *PageToken) HasMore() bool {
+	return t != nil && t.next
+}
+
+func (t *PageToken) page() int {
+	if t == nil || t.Page < 1 {
+		return 1
+	}
+	return t.Page
+}
+
+func (t *PageToken) limit() int {
+	if t == nil || t.Limit < 1 {
+		return DefaultPageLimit
+	}
+	return t.Limit
+}
+
+// ListOrgRepos returns a page of the repositories of the given organization.
+//
+// API docs: https://try.gitea.io/api/swagger#/organization/orgListRepos
+func (c *Client) ListOrgRepos(ctx context.Context, org string, token *PageToken) ([]*Repo, *PageToken, error) {
+	var repos []*Repo
+	next, err := c.page(ctx, "orgs/"+url.PathEscape(org)+"/repos", nil, token, &repos)
+	return repos, next, err
+}
+
+// SearchRepos returns a page of all repositories accessible to the
+// authenticated user, including private repositories.
+//
+// API docs: https://try.gitea.io/api/swagger#/repository/repoSearch
+func (c *Client) SearchRepos(ctx context.Context, token *PageToken) ([]*Repo, *PageToken, error) {
+	var result struct {
+		OK   bool    `json:"ok"`
+		Data []*Repo `json:"data"`
+	}
+	qry := url.Values{"private": []string{"true"}}
+	next, err := c.page(ctx, "repos/search", qry, token, &result)
+	if err == nil && !result.OK {
+		err = errors.New("Gitea repository search was not successful")
+	}
+	return result.Data, next, err
+}
+
+// GetRepo returns the repository with the given owner and name.
+//
+// API docs: https://try.gitea.io/api/swagger#/repository/repoGet
+func (c *Client) GetRepo(ctx context.Context, owner, name string) (*Repo, error) {
+	var repo Repo
+	err := c.get(ctx, "repos/"+url.PathEscape(owner)+"/"+url.PathEscape(name), nil, &repo)
+	return &repo, err
+}
+
+// GetRepoByID returns the repository with the given ID.
+//
+// API docs: https://try.gitea.io/api/swagger#/repository/repoGetByID
+func (c *Client) GetRepoByID(ctx context.Context, id int64) (*Repo, error) {
+	var repo Repo
+	err := c.get(ctx, "repositories/"+strconv.FormatInt(id, 10), nil, &repo)
+	return &repo, err
+}
+
+// GetUser returns the user with the given username.
+//
+// API docs: https://try.gitea.io/api/swagger#/user/userGet
+func (c *Client) GetUser(ctx context.Context, username string) (*User, error) {
+	var user User
+	err := c.get(ctx, "users/"+url.PathEscape(username), nil, &user)
+	return &user, err
+}
+
+// GetAuthenticatedUser returns the user the client's token belongs to.
+//
+// API docs: https://try.gitea.io/api/swagger#/user/userGetCurrent
+func (c *Client) GetAuthenticatedUser(ctx context.Context) (*User, error) {
+	var user User
+	err := c.get(ctx, "user", nil, &user)
+	return &user, err
+}
+
+// GetOrg returns the organization with the given name. It returns a not
+// found error if name is the name of a user.
+//
+// API docs: https://try.gitea.io/api/swagger#/organization/orgGet
+func (c *Client) GetOrg(ctx context.Context, name string) (*User, error) {
+	var org User
+	err := c.get(ctx, "orgs/"+url.PathEscape(name), nil, &org)
+	return &org, err
+}
+
+// ListCollaborators returns a page of the collaborators of the given
+// repository.
+//
+// API docs: https://try.gitea.io/api/swagger#/repository/repoListCollaborators
+func (c *Client) ListCollaborators(ctx context.Context, owner, name string, token *PageToken) ([]*User, *PageToken, error) {
+	var users []*User
+	next, err := c.page(ctx, "repos/"+url.PathEscape(owner)+"/"+url.PathEscape(name)+"/collaborators", nil, token, &users)
+	return users, next, err
+}
+
+// ListRepoTeams returns the organization teams that have access to the given
+// repository.
+//
+// API docs: https://try.gitea.io/api/swagger#/repository/repoListTeams
+func (c *Client) ListRepoTeams(ctx context.Context, owner, name string) ([]*Team, error) {
+	var teams []*Team
+	err := c.get(ctx, "repos/"+url.PathEscape(owner)+"/"+url.PathEscape(name)+"/teams", nil, &teams)
+	return teams, err
+}
+
+// ListTeamMembers returns a page of the members of the given team.
+//
+// API docs: https://try.gitea.io/api/swagger#/organization/orgListTeamMembers
+func (c *Client) ListTeamMembers(ctx context.Context, teamID int64, token *PageToken) ([]*User, *PageToken, error) {
+	var users []*User
+	next, err := c.page(ctx, "teams/"+strconv.FormatInt(teamID, 10)+"/members", nil, token, &users)
+	return users, next, err
+}
+
+func (c *Client) page(ctx context.Context, path string, qry url.Values, token *PageToken, result interface{}) (*PageToken, error) {
+	if qry == nil {
+		qry = make(url.Values)
+	}
+	qry.Set("page", strconv.Itoa(token.page()))
+	qry.Set("limit", strconv.Itoa(token.limit()))
+
+	header, err := c.do(ctx, path, qry, result)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PageToken{
+		Page:  token.page() + 1,
+		Limit: token.limit(),
+		next:  hasNextLink(header.Get("Link")),
+	}, nil
+}
+
+func (c *Client) get(ctx context.Context, path string, qry url.Values, result interface{}) error {
+	_, err := c.do(ctx, path, qry, result)
+	return err
+}
+
+func (c *Client) do(ctx context.Context, path string, qry url.Values, result interface{}) (http.Header, error) {
+	// path may contain escaped path segments, so we parse it rather than
+	// setting it as the URL's Path.
+	u, err := url.Parse("api/v1/" + path)
+	if err != nil {
+		return nil, err
+	}
+	u.RawQuery = qry.Encode()
+
+	req, err := http.NewRequest("GET", c.URL.ResolveReference(u).String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.Token != "" {
+		req.Header.Set("Authorization", "token "+c.Token)
+	}
+	if c.sudo != "" {
+		req.Header.Set("Sudo", c.sudo)
+	}
+
+	req, ht := nethttp.TraceRequest(ot.GetTracer(ctx),
+		req.WithContext(ctx),
+		nethttp.OperationName("Gitea"),
+		nethttp.ClientTrace(false))
+	defer ht.Finish()
+
+	startWait := time.Now()
+	if err := c.RateLimit.Wait(ctx); err != nil {
+		return nil, err
+	}
+
+	if d := time.Since(startWait); d > 200*time.Millisecond {
+		log15.Warn("Gitea self-enforced API rate limit: request delayed longer than expected due to rate limit", "delay", d)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	bs, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 400 {
+		return nil, errors.WithStack(&httpError{
+			URL:        req.URL,
+			StatusCode: resp.StatusCode,
+			Body:       bs,
+		})
+	}
+
+	if result != nil {
+		return resp.Header, json.Unmarshal(bs, result)
+	}
+	return resp.Header, nil
+}
+
+// hasNextLink reports whether the given Link header contains a link to the
+// next page.
+func hasNextLink(link string) bool {
+	for _, l := range strings.Split(link, ",") {
+		if strings.Contains(l, `rel="next"`) {
+			return true
+		}
+	}
+	return false
+}
+
+// Repo is a Gitea repository.
+type Repo struct {
+	ID            int64  `json:"id"`
+	Owner         *User  `json:"owner"`
+	Name          string `json:"name"`
+	FullName      string `json:"full_name"`
+	Description   string `json:"description"`
+	Empty         bool   `json:"empty"`
+	Private       bool   `json:"private"`
+	Fork          bool   `json:"fork"`
+	Mirror        bool   `json:"mirror"`
+	Archived      bool   `json:"archived"`
+	HTMLURL       string `json:"html_url"`
+	SSHURL        string `json:"ssh_url"`
+	CloneURL      string `json:"clone_url"`
+	DefaultBranch string `json:"default_branch"`
+}
+
+// User is a Gitea user or organization.
+type User struct {
+	ID       int64  `json:"id"`
+	Login    string `json:"login"`
+	FullName string `json:"full_name"`
+	Email    string `json:"email"`
+	IsAdmin  bool   `json:"is_admin"`
+}
+
+// Team is a team of a Gitea organization.
+type Team struct {
+	ID         int64  `json:"id"`
+	Name       string `json:"name"`
+	Permission string `json:"permission"`
+}
+
+type httpError struct {
+	StatusCode int
+	URL        *url.URL
+	Body       []byte
+}
+
+func (e *httpError) Error() string {
+	return fmt.Sprintf("Gitea API HTTP error: code=%d url=%q body=%q", e.StatusCode, e.URL, e.Body)
+}
+
+func (e *httpError) Unauthorized() bool {
+	return e.StatusCode == http.StatusUnauthorized
+}
+
+func (e *httpError) NotFound() bool {
+	return e.StatusCode == http.StatusNotFound
+}
@@ -25,6 +25,16 @@ type PipelineEvent struct {
 	MergeRequest *gitlab.MergeRequest `json:"merge_request"`
 }
 
+// PushEvent is sent when commits or tags are pushed to a project. It is used
+// for both the "push" and "tag_push" object kinds.
+type PushEvent struct {
+	EventCommon
+
+	Before string `json:"before"`
+	After  string `json:"after"`
+	Ref    string `json:"ref"`
+}
+
 var ErrObjectKindUnknown = errors.New("unknown object kind")
 
 type downcaster interface {
@@ -32,7 +42,7 @@ type downcaster interface {
 }
 
 // UnmarshalEvent unmarshals the given JSON into an event type. Possible return
-// types are *MergeRequestEvent.
+// types are *MergeRequestEvent, *PipelineEvent and *PushEvent.
 //
 // Errors caused by a valid payload being of an unknown type may be
 // distinguished from other errors by checking for ErrObjectKindUnknown in the
@@ -60,6 +70,8 @@ func UnmarshalEvent(data []byte) (interface{}, error) {
 		typedEvent = &mergeRequestEvent{}
 	case "pipeline":
 		typedEvent = &PipelineEvent{}
+	case "push", "tag_push":
+		typedEvent = &PushEvent{}
 	default:
 		return nil, errors.Wrapf(ErrObjectKindUnknown, "kind: %s", event.ObjectKind)
 	}
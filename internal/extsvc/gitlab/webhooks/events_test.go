@@ -62,6 +62,26 @@ func TestUnmarshalEvent(t *testing.T) {
 		}
 	})
 
+	t.Run("valid push", func(t *testing.T) {
+		event, err := UnmarshalEvent([]byte(`
+			{
+				"object_kind": "tag_push",
+				"ref": "refs/tags/v1.0.0",
+				"project": {"id": 42}
+			}
+		`))
+		if err != nil {
+			t.Fatalf("unexpected error: %+v", err)
+		}
+		push, ok := event.(*PushEvent)
+		if !ok {
+			t.Fatalf("unexpected event type: %T", event)
+		}
+		if push.Project.ID != 42 || push.Ref != "refs/tags/v1.0.0" {
+			t.Errorf("unexpected event: %+v", push)
+		}
+	})
+
 	t.Run("valid merge request", func(t *testing.T) {
 		event, err := UnmarshalEvent([]byte(`
 			{
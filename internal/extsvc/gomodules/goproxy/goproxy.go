@@ -0,0 +1,148 @@
+// Package goproxy implements a client for the Go module proxy protocol.
+//
+// Protocol docs: https://golang.org/ref/mod#goproxy-protocol
+package goproxy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"golang.org/x/mod/module"
+
+	"github.com/sourcegraph/sourcegraph/internal/conf/reposource"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/ratelimit"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// DefaultURL is the URL of the public Go module mirror.
+const DefaultURL = "https://proxy.golang.org"
+
+// Client fetches module metadata and zips from a list of module proxies.
+type Client struct {
+	urls []string
+	doer httpcli.Doer
+}
+
+// NewClient returns a client for the proxies of the given connection. If a
+// nil doer is provided, httpcli.ExternalDoer() will be used.
+func NewClient(config *schema.GoModulesConnection, doer httpcli.Doer) *Client {
+	if doer == nil {
+		doer = httpcli.ExternalDoer()
+	}
+	urls := make([]string, 0, len(config.Urls))
+	for _, u := range config.Urls {
+		urls = append(urls, strings.TrimSuffix(u, "/"))
+	}
+	if len(urls) == 0 {
+		urls = []string{DefaultURL}
+	}
+	return &Client{urls: urls, doer: doer}
+}
+
+// Info is the metadata of a module version.
+type Info struct {
+	Version string
+	Time    time.Time
+}
+
+// GetInfo returns the metadata of the given dependency.
+func (c *Client) GetInfo(ctx context.Context, dependency reposource.GoDependency) (*Info, error) {
+	body, err := c.get(ctx, dependency, ".info")
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	var info Info
+	if err := json.NewDecoder(body).Decode(&info); err != nil {
+		return nil, errors.Wrapf(err, "decoding info of Go module %s", dependency.PackageManagerSyntax())
+	}
+	return &info, nil
+}
+
+// Exists returns true if one of the proxies serves the given dependency.
+func (c *Client) Exists(ctx context.Context, dependency reposource.GoDependency) bool {
+	_, err := c.GetInfo(ctx, dependency)
+	return err == nil
+}
+
+// FetchZip returns the zip archive of the given dependency. The caller must
+// close the returned reader.
+func (c *Client) FetchZip(ctx context.Context, dependency reposource.GoDependency) (io.ReadCloser, error) {
+	return c.get(ctx, dependency, ".zip")
+}
+
+// get requests the given file of the dependency from each proxy in order,
+// falling back to the next proxy only if the module is not found, like the
+// go command does for comma-separated GOPROXY lists.
+func (c *Client) get(ctx context.Context, dependency reposource.GoDependency, suffix string) (io.ReadCloser, error) {
+	escapedPath, err := module.EscapePath(dependency.Path)
+	if err != nil {
+		return nil, err
+	}
+	escapedVersion, err := module.EscapeVersion(dependency.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	var lastErr error
+	for _, baseURL := range c.urls {
+		u := fmt.Sprintf("%s/%s/@v/%s%s", baseURL, escapedPath, escapedVersion, suffix)
+		body, err := c.getURL(ctx, u)
+		if err == nil {
+			return body, nil
+		}
+		lastErr = err
+		var e *Error
+		if !errors.As(err, &e) || !e.NotFound() {
+			return nil, err
+		}
+	}
+	return nil, lastErr
+}
+
+func (c *Client) getURL(ctx context.Context, u string) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ratelimit.DefaultRegistry.Get("go").Wait(ctx); err != nil {
+		return nil, err
+	}
+
+	resp, err := c.doer.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		bs, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, &Error{URL: u, StatusCode: resp.StatusCode, Body: bs}
+	}
+	return resp.Body, nil
+}
+
+// Error is returned for unsuccessful responses from a proxy.
+type Error struct {
+	URL        string
+	StatusCode int
+	Body       []byte
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("Go module proxy HTTP error: code=%d url=%q body=%q", e.StatusCode, e.URL, e.Body)
+}
+
+// NotFound returns true for the status codes that tell the go command to try
+// the next proxy.
+func (e *Error) NotFound() bool {
+	return e.StatusCode == http.StatusNotFound || e.StatusCode == http.StatusGone
+}
@@ -0,0 +1,137 @@
+// Package npm implements a client for the npm registry API.
+package npm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/internal/conf/reposource"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/ratelimit"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// DefaultRegistryURL is the URL of the public npm registry.
+const DefaultRegistryURL = "https://registry.npmjs.org"
+
+// Client fetches package metadata and tarballs from an npm registry.
+type Client struct {
+	registryURL string
+	credentials string
+	doer        httpcli.Doer
+}
+
+// NewClient returns a client for the registry of the given connection. If a
+// nil doer is provided, httpcli.ExternalDoer() will be used.
+func NewClient(config *schema.NpmPackagesConnection, doer httpcli.Doer) *Client {
+	if doer == nil {
+		doer = httpcli.ExternalDoer()
+	}
+	registryURL := config.Registry
+	if registryURL == "" {
+		registryURL = DefaultRegistryURL
+	}
+	return &Client{
+		registryURL: strings.TrimSuffix(registryURL, "/"),
+		credentials: config.Credentials,
+		doer:        doer,
+	}
+}
+
+// PackageVersion is the metadata of a single published version of a package.
+//
+// API docs: https://github.com/npm/registry/blob/master/docs/REGISTRY-API.md#getpackageversion
+type PackageVersion struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Dist    struct {
+		Tarball string `json:"tarball"`
+		Shasum  string `json:"shasum"`
+	} `json:"dist"`
+}
+
+// GetPackageVersion returns the metadata of the given dependency.
+func (c *Client) GetPackageVersion(ctx context.Context, dependency reposource.NpmDependency) (*PackageVersion, error) {
+	// Scoped package names contain a slash, which the registry accepts
+	// unescaped.
+	u := fmt.Sprintf("%s/%s/%s", c.registryURL, dependency.PackageSyntax(), url.PathEscape(dependency.Version))
+	body, err := c.get(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	var version PackageVersion
+	if err := json.NewDecoder(body).Decode(&version); err != nil {
+		return nil, errors.Wrapf(err, "decoding metadata of npm package %s", dependency.PackageManagerSyntax())
+	}
+	if version.Dist.Tarball == "" {
+		return nil, errors.Errorf("npm package %s has no tarball", dependency.PackageManagerSyntax())
+	}
+	return &version, nil
+}
+
+// Exists returns true if the given dependency is published in the registry.
+func (c *Client) Exists(ctx context.Context, dependency reposource.NpmDependency) bool {
+	_, err := c.GetPackageVersion(ctx, dependency)
+	return err == nil
+}
+
+// FetchTarball returns the gzipped tarball of the given dependency. The
+// caller must close the returned reader.
+func (c *Client) FetchTarball(ctx context.Context, dependency reposource.NpmDependency) (io.ReadCloser, error) {
+	version, err := c.GetPackageVersion(ctx, dependency)
+	if err != nil {
+		return nil, err
+	}
+	return c.get(ctx, version.Dist.Tarball)
+}
+
+func (c *Client) get(ctx context.Context, u string) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", u, nil)
+	if err != nil {
+		return nil, err
+	}
+	// Only send credentials to the configured registry, tarballs may be
+	// hosted elsewhere.
+	if c.credentials != "" && strings.HasPrefix(u, c.registryURL+"/") {
+		req.Header.Set("Authorization", "Bearer "+c.credentials)
+	}
+
+	if err := ratelimit.DefaultRegistry.Get("npm").Wait(ctx); err != nil {
+		return nil, err
+	}
+
+	resp, err := c.doer.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		bs, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, &Error{URL: u, StatusCode: resp.StatusCode, Body: bs}
+	}
+	return resp.Body, nil
+}
+
+// Error is returned for unsuccessful responses from the registry.
+type Error struct {
+	URL        string
+	StatusCode int
+	Body       []byte
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("npm registry HTTP error: code=%d url=%q body=%q", e.StatusCode, e.URL, e.Body)
+}
+
+func (e *Error) NotFound() bool {
+	return e.StatusCode == http.StatusNotFound
+}
@@ -77,12 +77,16 @@ const (
 	KindAWSCodeCommit   = "AWSCODECOMMIT"
 	KindBitbucketServer = "BITBUCKETSERVER"
 	KindBitbucketCloud  = "BITBUCKETCLOUD"
+	KindGerrit          = "GERRIT"
 	KindGitHub          = "GITHUB"
 	KindGitLab          = "GITLAB"
+	KindGitea           = "GITEA"
 	KindGitolite        = "GITOLITE"
 	KindPerforce        = "PERFORCE"
 	KindPhabricator     = "PHABRICATOR"
 	KindJVMPackages     = "JVMPACKAGES"
+	KindNpmPackages     = "NPMPACKAGES"
+	KindGoModules       = "GOMODULES"
 	KindOther           = "OTHER"
 )
 
@@ -102,6 +106,10 @@ const (
 	// ServiceID value is the base URL to the Bitbucket Cloud.
 	TypeBitbucketCloud = "bitbucketCloud"
 
+	// TypeGerrit is the (api.ExternalRepoSpec).ServiceType value for Gerrit projects. The ServiceID
+	// value is the base URL to the Gerrit instance.
+	TypeGerrit = "gerrit"
+
 	// TypeGitHub is the (api.ExternalRepoSpec).ServiceType value for GitHub repositories. The ServiceID value
 	// is the base URL to the GitHub instance (https://github.com or the GitHub Enterprise URL).
 	TypeGitHub = "github"
@@ -110,6 +118,10 @@ const (
 	// value is the base URL to the GitLab instance (https://gitlab.com or self-hosted GitLab URL).
 	TypeGitLab = "gitlab"
 
+	// TypeGitea is the (api.ExternalRepoSpec).ServiceType value for Gitea repositories. The ServiceID
+	// value is the base URL to the Gitea instance.
+	TypeGitea = "gitea"
+
 	// TypeGitolite is the (api.ExternalRepoSpec).ServiceType value for Gitolite projects.
 	TypeGitolite = "gitolite"
 
@@ -122,6 +134,12 @@ const (
 	// TypeJVMPackages is the (api.ExternalRepoSpec).ServiceType value for Maven packages (Java/JVM ecosystem libraries).
 	TypeJVMPackages = "jvmPackages"
 
+	// TypeNpmPackages is the (api.ExternalRepoSpec).ServiceType value for npm packages (JavaScript/TypeScript ecosystem libraries).
+	TypeNpmPackages = "npmPackages"
+
+	// TypeGoModules is the (api.ExternalRepoSpec).ServiceType value for Go modules.
+	TypeGoModules = "goModules"
+
 	// TypeOther is the (api.ExternalRepoSpec).ServiceType value for other projects.
 	TypeOther = "other"
 
@@ -138,10 +156,14 @@ func KindToType(kind string) string {
 		return TypeBitbucketServer
 	case KindBitbucketCloud:
 		return TypeBitbucketCloud
+	case KindGerrit:
+		return TypeGerrit
 	case KindGitHub:
 		return TypeGitHub
 	case KindGitLab:
 		return TypeGitLab
+	case KindGitea:
+		return TypeGitea
 	case KindGitolite:
 		return TypeGitolite
 	case KindPhabricator:
@@ -150,6 +172,10 @@ func KindToType(kind string) string {
 		return TypePerforce
 	case KindJVMPackages:
 		return TypeJVMPackages
+	case KindNpmPackages:
+		return TypeNpmPackages
+	case KindGoModules:
+		return TypeGoModules
 	case KindOther:
 		return TypeOther
 	default:
@@ -167,10 +193,14 @@ func TypeToKind(t string) string {
 		return KindBitbucketServer
 	case TypeBitbucketCloud:
 		return KindBitbucketCloud
+	case TypeGerrit:
+		return KindGerrit
 	case TypeGitHub:
 		return KindGitHub
 	case TypeGitLab:
 		return KindGitLab
+	case TypeGitea:
+		return KindGitea
 	case TypeGitolite:
 		return KindGitolite
 	case TypePerforce:
@@ -179,6 +209,10 @@ func TypeToKind(t string) string {
 		return KindPhabricator
 	case TypeJVMPackages:
 		return KindJVMPackages
+	case TypeNpmPackages:
+		return KindNpmPackages
+	case TypeGoModules:
+		return KindGoModules
 	case TypeOther:
 		return KindOther
 	default:
@@ -191,6 +225,8 @@ var (
 	bbsLower = strings.ToLower(TypeBitbucketServer)
 	bbcLower = strings.ToLower(TypeBitbucketCloud)
 	jvmLower = strings.ToLower(TypeJVMPackages)
+	npmLower = strings.ToLower(TypeNpmPackages)
+	goLower  = strings.ToLower(TypeGoModules)
 )
 
 // ParseServiceType will return a ServiceType constant after doing a case insensitive match on s.
@@ -203,10 +239,14 @@ func ParseServiceType(s string) (string, bool) {
 		return TypeBitbucketServer, true
 	case bbcLower:
 		return TypeBitbucketCloud, true
+	case TypeGerrit:
+		return TypeGerrit, true
 	case TypeGitHub:
 		return TypeGitHub, true
 	case TypeGitLab:
 		return TypeGitLab, true
+	case TypeGitea:
+		return TypeGitea, true
 	case TypeGitolite:
 		return TypeGitolite, true
 	case TypePerforce:
@@ -215,6 +255,10 @@ func ParseServiceType(s string) (string, bool) {
 		return TypePhabricator, true
 	case jvmLower:
 		return TypeJVMPackages, true
+	case npmLower:
+		return TypeNpmPackages, true
+	case goLower:
+		return TypeGoModules, true
 	case TypeOther:
 		return TypeOther, true
 	default:
@@ -232,10 +276,14 @@ func ParseServiceKind(s string) (string, bool) {
 		return KindBitbucketServer, true
 	case KindBitbucketCloud:
 		return KindBitbucketCloud, true
+	case KindGerrit:
+		return KindGerrit, true
 	case KindGitHub:
 		return KindGitHub, true
 	case KindGitLab:
 		return KindGitLab, true
+	case KindGitea:
+		return KindGitea, true
 	case KindGitolite:
 		return KindGitolite, true
 	case KindPerforce:
@@ -244,6 +292,10 @@ func ParseServiceKind(s string) (string, bool) {
 		return KindPhabricator, true
 	case KindJVMPackages:
 		return KindJVMPackages, true
+	case KindNpmPackages:
+		return KindNpmPackages, true
+	case KindGoModules:
+		return KindGoModules, true
 	case KindOther:
 		return KindOther, true
 	default:
@@ -273,10 +325,14 @@ func ParseConfig(kind, config string) (cfg interface{}, _ error) {
 		cfg = &schema.BitbucketServerConnection{}
 	case KindBitbucketCloud:
 		cfg = &schema.BitbucketCloudConnection{}
+	case KindGerrit:
+		cfg = &schema.GerritConnection{}
 	case KindGitHub:
 		cfg = &schema.GitHubConnection{}
 	case KindGitLab:
 		cfg = &schema.GitLabConnection{}
+	case KindGitea:
+		cfg = &schema.GiteaConnection{}
 	case KindGitolite:
 		cfg = &schema.GitoliteConnection{}
 	case KindPerforce:
@@ -285,6 +341,10 @@ func ParseConfig(kind, config string) (cfg interface{}, _ error) {
 		cfg = &schema.PhabricatorConnection{}
 	case KindJVMPackages:
 		cfg = &schema.JVMPackagesConnection{}
+	case KindNpmPackages:
+		cfg = &schema.NpmPackagesConnection{}
+	case KindGoModules:
+		cfg = &schema.GoModulesConnection{}
 	case KindOther:
 		cfg = &schema.OtherExternalServiceConnection{}
 	default:
@@ -304,6 +364,10 @@ func WebhookURL(kind string, externalServiceID int64, externalURL string) string
 		path = "bitbucket-server-webhooks"
 	case KindGitLab:
 		path = "gitlab-webhooks"
+	case KindBitbucketCloud:
+		path = "bitbucket-cloud-webhooks"
+	case KindAWSCodeCommit:
+		path = "aws-codecommit-webhooks"
 	default:
 		return ""
 	}
@@ -324,6 +388,8 @@ func ExtractToken(config string, kind string) (string, error) {
 		return c.Token, nil
 	case *schema.BitbucketServerConnection:
 		return c.Token, nil
+	case *schema.GiteaConnection:
+		return c.Token, nil
 	case *schema.PhabricatorConnection:
 		return c.Token, nil
 	default:
@@ -396,6 +462,20 @@ func GetLimitFromConfig(kind string, config interface{}) (rlc RateLimitConfig, e
 			rlc.IsDefault = false
 		}
 		rlc.BaseURL = c.Url
+	case *schema.GiteaConnection:
+		rlc.Limit = defaultRateLimit
+		if c != nil && c.RateLimit != nil {
+			rlc.Limit = limitOrInf(c.RateLimit.Enabled, c.RateLimit.RequestsPerHour)
+			rlc.IsDefault = false
+		}
+		rlc.BaseURL = c.Url
+	case *schema.GerritConnection:
+		rlc.Limit = defaultRateLimit
+		if c != nil && c.RateLimit != nil {
+			rlc.Limit = limitOrInf(c.RateLimit.Enabled, c.RateLimit.RequestsPerHour)
+			rlc.IsDefault = false
+		}
+		rlc.BaseURL = c.Url
 	case *schema.PerforceConnection:
 		rlc.Limit = rate.Limit(5000.0 / 3600.0)
 		if c != nil && c.RateLimit != nil {
@@ -410,6 +490,20 @@ func GetLimitFromConfig(kind string, config interface{}) (rlc RateLimitConfig, e
 			rlc.IsDefault = false
 		}
 		rlc.BaseURL = "maven"
+	case *schema.NpmPackagesConnection:
+		rlc.Limit = rate.Limit(3000.0 / 3600.0)
+		if c != nil && c.RateLimit != nil {
+			rlc.Limit = limitOrInf(c.RateLimit.Enabled, c.RateLimit.RequestsPerHour)
+			rlc.IsDefault = false
+		}
+		rlc.BaseURL = "npm"
+	case *schema.GoModulesConnection:
+		rlc.Limit = rate.Limit(57600.0 / 3600.0)
+		if c != nil && c.RateLimit != nil {
+			rlc.Limit = limitOrInf(c.RateLimit.Enabled, c.RateLimit.RequestsPerHour)
+			rlc.IsDefault = false
+		}
+		rlc.BaseURL = "go"
 	default:
 		return rlc, ErrRateLimitUnsupported{codehostKind: kind}
 	}
@@ -492,6 +586,10 @@ func UniqueCodeHostIdentifier(kind, config string) (string, error) {
 		rawURL = c.Url
 	case *schema.BitbucketCloudConnection:
 		rawURL = c.Url
+	case *schema.GiteaConnection:
+		rawURL = c.Url
+	case *schema.GerritConnection:
+		rawURL = c.Url
 	case *schema.PhabricatorConnection:
 		rawURL = c.Url
 	case *schema.OtherExternalServiceConnection:
@@ -507,6 +605,10 @@ func UniqueCodeHostIdentifier(kind, config string) (string, error) {
 		return c.P4Port, nil
 	case *schema.JVMPackagesConnection:
 		return KindJVMPackages, nil
+	case *schema.NpmPackagesConnection:
+		return KindNpmPackages, nil
+	case *schema.GoModulesConnection:
+		return KindGoModules, nil
 	default:
 		return "", errors.Errorf("unknown external service kind: %s", kind)
 	}
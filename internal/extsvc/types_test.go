@@ -27,6 +27,11 @@ func TestExtractToken(t *testing.T) {
 			kind:   KindBitbucketServer,
 			want:   "deadbeef",
 		},
+		{
+			config: `{"token": "deadbeef"}`,
+			kind:   KindGitea,
+			want:   "deadbeef",
+		},
 		{
 			config: `{"token": "deadbeef"}`,
 			kind:   KindPhabricator,
@@ -108,6 +113,30 @@ func TestExtractRateLimitConfig(t *testing.T) {
 				IsDefault:   true,
 			},
 		},
+		{
+			name:        "Gitea default",
+			config:      `{"url": "https://example.com/"}`,
+			kind:        KindGitea,
+			displayName: "Gitea 1",
+			want: RateLimitConfig{
+				BaseURL:     "https://example.com/",
+				DisplayName: "Gitea 1",
+				Limit:       2.0,
+				IsDefault:   true,
+			},
+		},
+		{
+			name:        "Gerrit non-default",
+			config:      `{"url": "https://example.com/", "rateLimit": {"enabled": true, "requestsPerHour": 3600}}`,
+			kind:        KindGerrit,
+			displayName: "Gerrit 1",
+			want: RateLimitConfig{
+				BaseURL:     "https://example.com/",
+				DisplayName: "Gerrit 1",
+				Limit:       1.0,
+				IsDefault:   false,
+			},
+		},
 		{
 			name:        "GitLab non-default",
 			config:      `{"url": "https://example.com/", "rateLimit": {"enabled": true, "requestsPerHour": 3600}}`,
@@ -118,11 +118,33 @@ func AddrForRepo(repo api.RepoName, addrs []string) string {
 // addrForKey returns the gitserver address to use for the given string key,
 // which is hashed for sharding purposes.
 func addrForKey(key string, addrs []string) string {
+	if conf.GitServerConsistentHashing() {
+		return rendezvousAddrForKey(key, addrs)
+	}
 	sum := md5.Sum([]byte(key))
 	serverIndex := binary.BigEndian.Uint64(sum[:]) % uint64(len(addrs))
 	return addrs[serverIndex]
 }
 
+// rendezvousAddrForKey returns the gitserver address to use for the given
+// string key using rendezvous (highest random weight) hashing: each address is
+// scored by hashing it together with the key, and the address with the highest
+// score wins. Unlike hashing modulo len(addrs), adding or removing an address
+// only moves the keys assigned to that address.
+func rendezvousAddrForKey(key string, addrs []string) string {
+	var (
+		best      string
+		bestScore uint64
+	)
+	for _, addr := range addrs {
+		sum := md5.Sum([]byte(addr + "\x00" + key))
+		if score := binary.BigEndian.Uint64(sum[:]); best == "" || score > bestScore {
+			best, bestScore = addr, score
+		}
+	}
+	return best
+}
+
 // ArchiveOptions contains options for the Archive func.
 type ArchiveOptions struct {
 	Treeish string   // the tree or commit to produce an archive for
@@ -619,6 +641,27 @@ func (c *Client) RequestRepoUpdate(ctx context.Context, repo api.RepoName, since
 	return info, err
 }
 
+// RequestRepoCopy asks the gitserver instance that repo is assigned to, to
+// copy its clone from the gitserver instance at sourceAddr. It blocks until
+// the copy has finished.
+func (c *Client) RequestRepoCopy(ctx context.Context, repo api.RepoName, sourceAddr string) error {
+	req := &protocol.RepoCopyRequest{
+		Repo:       repo,
+		SourceAddr: sourceAddr,
+	}
+	resp, err := c.httpPost(ctx, repo, "repo-copy", req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		// best-effort inclusion of body in error message
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 200))
+		return &url.Error{URL: resp.Request.URL.String(), Op: "RepoCopy", Err: errors.Errorf("RepoCopy: http status %d: %s", resp.StatusCode, string(body))}
+	}
+	return nil
+}
+
 // MockIsRepoCloneable mocks (*Client).IsRepoCloneable for tests.
 var MockIsRepoCloneable func(api.RepoName) error
 
@@ -22,8 +22,10 @@ import (
 
 	"github.com/sourcegraph/sourcegraph/cmd/gitserver/server"
 	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
 	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/schema"
 )
 
 func TestClient_ListCloned(t *testing.T) {
@@ -287,6 +289,36 @@ func TestAddrForRepo(t *testing.T) {
 	}
 }
 
+func TestAddrForRepo_ConsistentHashing(t *testing.T) {
+	conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{
+		ExperimentalFeatures: &schema.ExperimentalFeatures{GitServerConsistentHashing: true},
+	}})
+	defer conf.Mock(nil)
+
+	addrs := []string{"gitserver-1", "gitserver-2", "gitserver-3"}
+	scaledUp := append(addrs[:len(addrs):len(addrs)], "gitserver-4")
+
+	moved := 0
+	for i := 0; i < 1000; i++ {
+		repo := api.RepoName(fmt.Sprintf("github.com/foo/repo%d", i))
+		before := gitserver.AddrForRepo(repo, addrs)
+		after := gitserver.AddrForRepo(repo, scaledUp)
+		if before == after {
+			continue
+		}
+		// Adding an instance must only move repositories onto it.
+		if after != "gitserver-4" {
+			t.Fatalf("%s moved from %q to %q", repo, before, after)
+		}
+		moved++
+	}
+
+	// Roughly a quarter of repositories should move to the new instance.
+	if moved < 150 || moved > 350 {
+		t.Fatalf("unexpected number of moved repositories: %d", moved)
+	}
+}
+
 func TestClient_P4Exec(t *testing.T) {
 	root, err := os.MkdirTemp("", t.Name())
 	if err != nil {
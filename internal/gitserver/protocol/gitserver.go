@@ -105,6 +105,16 @@ type RepoDeleteRequest struct {
 	Repo api.RepoName
 }
 
+// RepoCopyRequest is a request to copy a repository clone from another
+// gitserver instance, rather than cloning it from its code host.
+type RepoCopyRequest struct {
+	// Repo is the repository to copy.
+	Repo api.RepoName
+	// SourceAddr is the address of the gitserver instance to copy the
+	// repository from.
+	SourceAddr string
+}
+
 // RepoInfoRequest is a request for information about multiple repositories on gitserver.
 type RepoInfoRequest struct {
 	// Repos are the repositories to get information about.
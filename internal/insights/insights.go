@@ -222,6 +222,9 @@ type TimeSeries struct {
 	Name   string
 	Stroke string
 	Query  string
+	// GeneratedFromCaptureGroups is true if one series is recorded per value of the first
+	// capture group of the query.
+	GeneratedFromCaptureGroups bool
 }
 
 type Interval struct {
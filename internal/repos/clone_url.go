@@ -11,10 +11,14 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/awscodecommit"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketcloud"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketserver"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gerrit"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitea"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitlab"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitolite"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gomodules"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/jvmpackages"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/npmpackages"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/perforce"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/phabricator"
 	"github.com/sourcegraph/sourcegraph/internal/types"
@@ -43,6 +47,14 @@ func CloneURL(kind, config string, repo *types.Repo) (string, error) {
 		if r, ok := repo.Metadata.(*bitbucketcloud.Repo); ok {
 			return bitbucketCloudCloneURL(r, t), nil
 		}
+	case *schema.GerritConnection:
+		if r, ok := repo.Metadata.(*gerrit.Project); ok {
+			return gerritCloneURL(r, t)
+		}
+	case *schema.GiteaConnection:
+		if r, ok := repo.Metadata.(*gitea.Repo); ok {
+			return giteaCloneURL(r, t), nil
+		}
 	case *schema.GitHubConnection:
 		if r, ok := repo.Metadata.(*github.Repository); ok {
 			return githubCloneURL(r, t)
@@ -71,6 +83,14 @@ func CloneURL(kind, config string, repo *types.Repo) (string, error) {
 		if r, ok := repo.Metadata.(*jvmpackages.Metadata); ok {
 			return r.Module.CloneURL(), nil
 		}
+	case *schema.NpmPackagesConnection:
+		if r, ok := repo.Metadata.(*npmpackages.Metadata); ok {
+			return r.Package.CloneURL(), nil
+		}
+	case *schema.GoModulesConnection:
+		if r, ok := repo.Metadata.(*gomodules.Metadata); ok {
+			return r.Module.CloneURL(), nil
+		}
 	default:
 		return "", errors.Errorf("unknown external service kind %q for repo %d", kind, repo.ID)
 	}
@@ -142,6 +162,53 @@ func bitbucketCloudCloneURL(repo *bitbucketcloud.Repo, cfg *schema.BitbucketClou
 	return u.String()
 }
 
+// gerritCloneURL returns the Gerrit project's Git remote URL. HTTP URLs point
+// at the authenticated /a/ endpoint and contain the configured username and
+// HTTP password in the URL userinfo.
+func gerritCloneURL(project *gerrit.Project, cfg *schema.GerritConnection) (string, error) {
+	baseURL, err := url.Parse(cfg.Url)
+	if err != nil {
+		return "", err
+	}
+	baseURL = extsvc.NormalizeBaseURL(baseURL)
+
+	if cfg.GitURLType == "ssh" {
+		port := cfg.SshPort
+		if port == 0 {
+			port = 29418
+		}
+		u := url.URL{
+			Scheme: "ssh",
+			User:   url.User(cfg.Username),
+			Host:   fmt.Sprintf("%s:%d", baseURL.Hostname(), port),
+			Path:   "/" + project.Name,
+		}
+		return u.String(), nil // SSH authentication must be provided out-of-band
+	}
+
+	u := baseURL.ResolveReference(&url.URL{Path: "a/" + project.Name})
+	u.User = url.UserPassword(cfg.Username, cfg.Password)
+	return u.String(), nil
+}
+
+// giteaCloneURL returns the Gitea repository's Git remote URL with the
+// configured Gitea access token inserted in the URL userinfo.
+func giteaCloneURL(repo *gitea.Repo, cfg *schema.GiteaConnection) string {
+	if cfg.GitURLType == "ssh" {
+		return repo.SSHURL // SSH authentication must be provided out-of-band
+	}
+	if cfg.Token == "" {
+		return repo.CloneURL
+	}
+	u, err := url.Parse(repo.CloneURL)
+	if err != nil {
+		log15.Warn("Error adding authentication to Gitea repository Git remote URL.", "url", repo.CloneURL, "error", err)
+		return repo.CloneURL
+	}
+	u.User = url.User(cfg.Token)
+	return u.String()
+}
+
 func githubCloneURL(repo *github.Repository, cfg *schema.GitHubConnection) (string, error) {
 	if cfg.GitURLType == "ssh" {
 		baseURL, err := url.Parse(cfg.Url)
@@ -0,0 +1,196 @@
+package repos
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf/reposource"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gerrit"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/jsonc"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// A GerritSource yields repositories from a single Gerrit connection configured
+// in Sourcegraph via the external services configuration.
+type GerritSource struct {
+	svc     *types.ExternalService
+	config  *schema.GerritConnection
+	exclude excludeFunc
+	baseURL *url.URL // normalized base URL of the Gerrit instance
+	client  *gerrit.Client
+}
+
+// NewGerritSource returns a new GerritSource from the given external service.
+func NewGerritSource(svc *types.ExternalService, cf *httpcli.Factory) (*GerritSource, error) {
+	var c schema.GerritConnection
+	if err := jsonc.Unmarshal(svc.Config, &c); err != nil {
+		return nil, errors.Errorf("external service id=%d config error: %s", svc.ID, err)
+	}
+	return newGerritSource(svc, &c, cf)
+}
+
+func newGerritSource(svc *types.ExternalService, c *schema.GerritConnection, cf *httpcli.Factory) (*GerritSource, error) {
+	baseURL, err := url.Parse(c.Url)
+	if err != nil {
+		return nil, err
+	}
+	baseURL = extsvc.NormalizeBaseURL(baseURL)
+
+	if cf == nil {
+		cf = httpcli.NewExternalHTTPClientFactory()
+	}
+
+	cli, err := cf.Doer()
+	if err != nil {
+		return nil, err
+	}
+
+	var eb excludeBuilder
+	for _, r := range c.Exclude {
+		eb.Exact(r.Name)
+		eb.Pattern(r.Pattern)
+	}
+	exclude, err := eb.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	client := gerrit.NewClient(baseURL, cli)
+	client.Username = c.Username
+	client.Password = c.Password
+
+	return &GerritSource{
+		svc:     svc,
+		config:  c,
+		exclude: exclude,
+		baseURL: baseURL,
+		client:  client,
+	}, nil
+}
+
+// ListRepos returns all Gerrit projects accessible to all connections configured
+// in Sourcegraph via the external services configuration.
+func (s GerritSource) ListRepos(ctx context.Context, results chan SourceResult) {
+	s.listAllProjects(ctx, results)
+}
+
+// GetRepo returns the Gerrit project with the given name.
+func (s GerritSource) GetRepo(ctx context.Context, name string) (*types.Repo, error) {
+	p, err := s.client.GetProject(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return s.makeRepo(p), nil
+}
+
+// ExternalServices returns a singleton slice containing the external service.
+func (s GerritSource) ExternalServices() types.ExternalServices {
+	return types.ExternalServices{s.svc}
+}
+
+func (s GerritSource) makeRepo(p *gerrit.Project) *types.Repo {
+	urn := s.svc.URN()
+	return &types.Repo{
+		Name: reposource.GerritRepoName(
+			s.config.RepositoryPathPattern,
+			s.baseURL.Hostname(),
+			p.Name,
+		),
+		URI: string(reposource.GerritRepoName(
+			"",
+			s.baseURL.Hostname(),
+			p.Name,
+		)),
+		ExternalRepo: api.ExternalRepoSpec{
+			ID:          p.ID,
+			ServiceType: extsvc.TypeGerrit,
+			ServiceID:   s.baseURL.String(),
+		},
+		Description: p.Description,
+		Archived:    p.State == "READ_ONLY",
+		// Read access to Gerrit projects is governed by access rules rather
+		// than a visibility setting, so we treat all projects as private.
+		Private: true,
+		Sources: map[string]*types.SourceInfo{
+			urn: {
+				ID:       urn,
+				CloneURL: s.remoteURL(p),
+			},
+		},
+		Metadata: p,
+	}
+}
+
+// remoteURL returns the project's Git remote URL
+//
+// note: this used to contain credentials but that is no longer the case
+// if you need to get an authenticated clone url use repos.CloneURL
+func (s *GerritSource) remoteURL(p *gerrit.Project) string {
+	if s.config.GitURLType == "ssh" {
+		// SSH URLs don't contain credentials.
+		if u, err := gerritCloneURL(p, s.config); err == nil {
+			return u
+		}
+	}
+	return s.baseURL.ResolveReference(&url.URL{Path: p.Name}).String()
+}
+
+func (s *GerritSource) excludes(p *gerrit.Project) bool {
+	return s.exclude(p.Name)
+}
+
+// gerritMetaProjects are the projects Gerrit uses to store its own
+// configuration. They are skipped unless explicitly listed in "projects".
+var gerritMetaProjects = map[string]bool{
+	"All-Projects": true,
+	"All-Users":    true,
+}
+
+func (s *GerritSource) listAllProjects(ctx context.Context, results chan SourceResult) {
+	seen := make(map[string]bool)
+	yield := func(projects ...*gerrit.Project) {
+		for _, p := range projects {
+			if !seen[p.ID] && !s.excludes(p) {
+				results <- SourceResult{Source: s, Repo: s.makeRepo(p)}
+				seen[p.ID] = true
+			}
+		}
+	}
+
+	if len(s.config.Projects) > 0 {
+		for _, name := range s.config.Projects {
+			p, err := s.client.GetProject(ctx, name)
+			if err != nil {
+				results <- SourceResult{Source: s, Err: errors.Wrapf(err, "gerrit.projects: item=%q", name)}
+				continue
+			}
+			yield(p)
+		}
+		return
+	}
+
+	t := &gerrit.PageToken{}
+	for {
+		projects, next, err := s.client.ListProjects(ctx, t)
+		if err != nil {
+			results <- SourceResult{Source: s, Err: errors.Wrapf(err, "gerrit.projects: start=%d", t.Start)}
+			break
+		}
+		for _, p := range projects {
+			if !gerritMetaProjects[p.Name] {
+				yield(p)
+			}
+		}
+
+		if !next.HasMore() {
+			break
+		}
+		t = next
+	}
+}
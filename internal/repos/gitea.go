@@ -0,0 +1,226 @@
+package repos
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf/reposource"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitea"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/jsonc"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// A GiteaSource yields repositories from a single Gitea connection configured
+// in Sourcegraph via the external services configuration.
+type GiteaSource struct {
+	svc     *types.ExternalService
+	config  *schema.GiteaConnection
+	exclude excludeFunc
+	baseURL *url.URL // normalized base URL of the Gitea instance
+	client  *gitea.Client
+}
+
+// NewGiteaSource returns a new GiteaSource from the given external service.
+func NewGiteaSource(svc *types.ExternalService, cf *httpcli.Factory) (*GiteaSource, error) {
+	var c schema.GiteaConnection
+	if err := jsonc.Unmarshal(svc.Config, &c); err != nil {
+		return nil, errors.Errorf("external service id=%d config error: %s", svc.ID, err)
+	}
+	return newGiteaSource(svc, &c, cf)
+}
+
+func newGiteaSource(svc *types.ExternalService, c *schema.GiteaConnection, cf *httpcli.Factory) (*GiteaSource, error) {
+	baseURL, err := url.Parse(c.Url)
+	if err != nil {
+		return nil, err
+	}
+	baseURL = extsvc.NormalizeBaseURL(baseURL)
+
+	if cf == nil {
+		cf = httpcli.NewExternalHTTPClientFactory()
+	}
+
+	cli, err := cf.Doer()
+	if err != nil {
+		return nil, err
+	}
+
+	var eb excludeBuilder
+	for _, r := range c.Exclude {
+		eb.Exact(r.Name)
+		if r.Id != 0 {
+			eb.Exact(strconv.Itoa(r.Id))
+		}
+		eb.Pattern(r.Pattern)
+	}
+	exclude, err := eb.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	return &GiteaSource{
+		svc:     svc,
+		config:  c,
+		exclude: exclude,
+		baseURL: baseURL,
+		client:  gitea.NewClient(baseURL, c.Token, cli),
+	}, nil
+}
+
+// ListRepos returns all Gitea repositories accessible to all connections configured
+// in Sourcegraph via the external services configuration.
+func (s GiteaSource) ListRepos(ctx context.Context, results chan SourceResult) {
+	s.listAllRepos(ctx, results)
+}
+
+// GetRepo returns the Gitea repository with the given "owner/name".
+func (s GiteaSource) GetRepo(ctx context.Context, nameWithOwner string) (*types.Repo, error) {
+	owner, name, err := splitGiteaNameWithOwner(nameWithOwner)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := s.client.GetRepo(ctx, owner, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.makeRepo(r), nil
+}
+
+// ExternalServices returns a singleton slice containing the external service.
+func (s GiteaSource) ExternalServices() types.ExternalServices {
+	return types.ExternalServices{s.svc}
+}
+
+func (s GiteaSource) makeRepo(r *gitea.Repo) *types.Repo {
+	urn := s.svc.URN()
+	return &types.Repo{
+		Name: reposource.GiteaRepoName(
+			s.config.RepositoryPathPattern,
+			s.baseURL.Hostname(),
+			r.FullName,
+		),
+		URI: string(reposource.GiteaRepoName(
+			"",
+			s.baseURL.Hostname(),
+			r.FullName,
+		)),
+		ExternalRepo: api.ExternalRepoSpec{
+			ID:          strconv.FormatInt(r.ID, 10),
+			ServiceType: extsvc.TypeGitea,
+			ServiceID:   s.baseURL.String(),
+		},
+		Description: r.Description,
+		Fork:        r.Fork,
+		Archived:    r.Archived,
+		Private:     r.Private,
+		Sources: map[string]*types.SourceInfo{
+			urn: {
+				ID:       urn,
+				CloneURL: s.remoteURL(r),
+			},
+		},
+		Metadata: r,
+	}
+}
+
+// remoteURL returns the repository's Git remote URL
+//
+// note: this used to contain credentials but that is no longer the case
+// if you need to get an authenticated clone url use repos.CloneURL
+func (s *GiteaSource) remoteURL(r *gitea.Repo) string {
+	if s.config.GitURLType == "ssh" {
+		return r.SSHURL // SSH authentication must be provided out-of-band
+	}
+	return r.CloneURL
+}
+
+func (s *GiteaSource) excludes(r *gitea.Repo) bool {
+	return s.exclude(r.FullName) || s.exclude(strconv.FormatInt(r.ID, 10))
+}
+
+func (s *GiteaSource) listAllRepos(ctx context.Context, results chan SourceResult) {
+	seen := make(map[int64]bool)
+	yield := func(repos ...*gitea.Repo) {
+		for _, r := range repos {
+			// Empty repositories can't be cloned.
+			if r.Empty {
+				continue
+			}
+			if !seen[r.ID] && !s.excludes(r) {
+				results <- SourceResult{Source: s, Repo: s.makeRepo(r)}
+				seen[r.ID] = true
+			}
+		}
+	}
+
+	for _, org := range s.config.Orgs {
+		t := &gitea.PageToken{}
+		for {
+			repos, next, err := s.client.ListOrgRepos(ctx, org, t)
+			if err != nil {
+				results <- SourceResult{Source: s, Err: errors.Wrapf(err, "gitea.orgs: item=%q, page=%d", org, t.Page)}
+				break
+			}
+			yield(repos...)
+
+			if !next.HasMore() {
+				break
+			}
+			t = next
+		}
+	}
+
+	for _, nameWithOwner := range s.config.Repos {
+		owner, name, err := splitGiteaNameWithOwner(nameWithOwner)
+		if err != nil {
+			results <- SourceResult{Source: s, Err: err}
+			continue
+		}
+
+		r, err := s.client.GetRepo(ctx, owner, name)
+		if err != nil {
+			results <- SourceResult{Source: s, Err: errors.Wrapf(err, "gitea.repos: item=%q", nameWithOwner)}
+			continue
+		}
+		yield(r)
+	}
+
+	if len(s.config.Orgs) > 0 || len(s.config.Repos) > 0 {
+		return
+	}
+
+	// Neither orgs nor repos are configured, so we mirror all repositories
+	// the token has access to.
+	t := &gitea.PageToken{}
+	for {
+		repos, next, err := s.client.SearchRepos(ctx, t)
+		if err != nil {
+			results <- SourceResult{Source: s, Err: errors.Wrapf(err, "gitea.search: page=%d", t.Page)}
+			break
+		}
+		yield(repos...)
+
+		if !next.HasMore() {
+			break
+		}
+		t = next
+	}
+}
+
+func splitGiteaNameWithOwner(nameWithOwner string) (owner, name string, err error) {
+	parts := strings.SplitN(nameWithOwner, "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", errors.Errorf("invalid Gitea repository name %q, must be of the form owner/name", nameWithOwner)
+	}
+	return parts[0], parts[1], nil
+}
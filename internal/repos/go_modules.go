@@ -0,0 +1,164 @@
+package repos
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/inconshreveable/log15"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf/reposource"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gomodules"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gomodules/goproxy"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/jsonc"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// A GoModulesSource creates git repositories from the zip archives of Go
+// module versions served by a module proxy.
+type GoModulesSource struct {
+	svc    *types.ExternalService
+	config *schema.GoModulesConnection
+	client *goproxy.Client
+}
+
+// NewGoModulesSource returns a new GoModulesSource from the given external
+// service.
+func NewGoModulesSource(svc *types.ExternalService) (*GoModulesSource, error) {
+	var c schema.GoModulesConnection
+	if err := jsonc.Unmarshal(svc.Config, &c); err != nil {
+		return nil, fmt.Errorf("external service id=%d config error: %s", svc.ID, err)
+	}
+	return newGoModulesSource(svc, &c, nil)
+}
+
+func newGoModulesSource(svc *types.ExternalService, c *schema.GoModulesConnection, doer httpcli.Doer) (*GoModulesSource, error) {
+	return &GoModulesSource{
+		svc:    svc,
+		config: c,
+		client: goproxy.NewClient(c, doer),
+	}, nil
+}
+
+// ListRepos returns all Go modules configured in the external service.
+func (s *GoModulesSource) ListRepos(ctx context.Context, results chan SourceResult) {
+	modules, err := GoModules(*s.config)
+	if err != nil {
+		results <- SourceResult{Err: err}
+		return
+	}
+	for _, mod := range modules {
+		results <- SourceResult{
+			Source: s,
+			Repo:   s.makeRepo(mod),
+		}
+	}
+}
+
+func (s *GoModulesSource) GetRepo(ctx context.Context, repoName string) (*types.Repo, error) {
+	mod, err := reposource.ParseGoModuleFromRepoURL(repoName)
+	if err != nil {
+		return nil, err
+	}
+
+	dependencies, err := GoDependencies(*s.config)
+	if err != nil {
+		return nil, err
+	}
+
+	nonExistentDependencies := make([]reposource.GoDependency, 0)
+	hasAtLeastOneValidDependency := false
+	for _, dep := range dependencies {
+		if dep.GoModule == mod {
+			if s.client.Exists(ctx, dep) {
+				hasAtLeastOneValidDependency = true
+			} else {
+				nonExistentDependencies = append(nonExistentDependencies, dep)
+			}
+		}
+	}
+
+	if !hasAtLeastOneValidDependency {
+		return nil, &goDependencyNotFound{
+			dependencies: nonExistentDependencies,
+		}
+	}
+
+	for _, nonExistentDependency := range nonExistentDependencies {
+		// Like for JVM packages, a single version that fails to resolve
+		// doesn't reject the whole module.
+		log15.Warn("Skipping non-existing Go module", "nonExistentDependency", nonExistentDependency.PackageManagerSyntax())
+	}
+
+	return s.makeRepo(mod), nil
+}
+
+type goDependencyNotFound struct {
+	dependencies []reposource.GoDependency
+}
+
+func (e *goDependencyNotFound) Error() string {
+	return fmt.Sprintf("not found: go dependency '%v'", e.dependencies)
+}
+
+func (e *goDependencyNotFound) NotFound() bool {
+	return true
+}
+
+func (s *GoModulesSource) makeRepo(mod reposource.GoModule) *types.Repo {
+	urn := s.svc.URN()
+	return &types.Repo{
+		Name: mod.RepoName(),
+		URI:  string(mod.RepoName()),
+		ExternalRepo: api.ExternalRepoSpec{
+			ID:          string(mod.RepoName()),
+			ServiceID:   extsvc.TypeGoModules,
+			ServiceType: extsvc.TypeGoModules,
+		},
+		Private: false,
+		Sources: map[string]*types.SourceInfo{
+			urn: {
+				ID:       urn,
+				CloneURL: mod.CloneURL(),
+			},
+		},
+		Metadata: &gomodules.Metadata{
+			Module: mod,
+		},
+	}
+}
+
+// ExternalServices returns a singleton slice containing the external service.
+func (s *GoModulesSource) ExternalServices() types.ExternalServices {
+	return types.ExternalServices{s.svc}
+}
+
+func GoDependencies(connection schema.GoModulesConnection) (dependencies []reposource.GoDependency, err error) {
+	for _, dep := range connection.Dependencies {
+		dependency, err := reposource.ParseGoDependency(dep)
+		if err != nil {
+			return nil, err
+		}
+		dependencies = append(dependencies, dependency)
+	}
+	return dependencies, nil
+}
+
+func GoModules(connection schema.GoModulesConnection) ([]reposource.GoModule, error) {
+	isAdded := make(map[reposource.GoModule]bool)
+	modules := []reposource.GoModule{}
+	dependencies, err := GoDependencies(connection)
+	if err != nil {
+		return nil, err
+	}
+	for _, dep := range dependencies {
+		if !isAdded[dep.GoModule] {
+			modules = append(modules, dep.GoModule)
+		}
+		isAdded[dep.GoModule] = true
+	}
+	return modules, nil
+}
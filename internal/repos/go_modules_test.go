@@ -0,0 +1,52 @@
+package repos
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestGoModulesSource_GetRepo(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Module paths are case-encoded by the proxy protocol.
+		if r.URL.Path != "/github.com/!burnt!sushi/toml/@v/v0.4.1.info" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{"Version":"v0.4.1","Time":"2021-08-05T08:00:00Z"}`))
+	}))
+	defer srv.Close()
+
+	svc := &types.ExternalService{ID: 1, Kind: extsvc.KindGoModules}
+	src, err := newGoModulesSource(svc, &schema.GoModulesConnection{
+		Urls:         []string{srv.URL},
+		Dependencies: []string{"github.com/BurntSushi/toml@v0.4.1", "golang.org/x/mod@v0.4.2"},
+	}, http.DefaultClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	repo, err := src.GetRepo(ctx, "go/github.com/BurntSushi/toml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if have, want := repo.Name, api.RepoName("go/github.com/BurntSushi/toml"); have != want {
+		t.Errorf("wrong repo name: have %q, want %q", have, want)
+	}
+	if have, want := repo.ExternalRepo.ServiceType, extsvc.TypeGoModules; have != want {
+		t.Errorf("wrong service type: have %q, want %q", have, want)
+	}
+
+	_, err = src.GetRepo(ctx, "go/golang.org/x/mod")
+	if !errcode.IsNotFound(err) {
+		t.Errorf("expected not found error, got %v", err)
+	}
+}
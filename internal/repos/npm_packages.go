@@ -0,0 +1,164 @@
+package repos
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/inconshreveable/log15"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf/reposource"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/npmpackages"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/npmpackages/npm"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/jsonc"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// A NpmPackagesSource creates git repositories from the tarballs of published
+// npm packages.
+type NpmPackagesSource struct {
+	svc    *types.ExternalService
+	config *schema.NpmPackagesConnection
+	client *npm.Client
+}
+
+// NewNpmPackagesSource returns a new NpmPackagesSource from the given external
+// service.
+func NewNpmPackagesSource(svc *types.ExternalService) (*NpmPackagesSource, error) {
+	var c schema.NpmPackagesConnection
+	if err := jsonc.Unmarshal(svc.Config, &c); err != nil {
+		return nil, fmt.Errorf("external service id=%d config error: %s", svc.ID, err)
+	}
+	return newNpmPackagesSource(svc, &c, nil)
+}
+
+func newNpmPackagesSource(svc *types.ExternalService, c *schema.NpmPackagesConnection, doer httpcli.Doer) (*NpmPackagesSource, error) {
+	return &NpmPackagesSource{
+		svc:    svc,
+		config: c,
+		client: npm.NewClient(c, doer),
+	}, nil
+}
+
+// ListRepos returns all npm packages configured in the external service.
+func (s *NpmPackagesSource) ListRepos(ctx context.Context, results chan SourceResult) {
+	packages, err := NpmPackages(*s.config)
+	if err != nil {
+		results <- SourceResult{Err: err}
+		return
+	}
+	for _, pkg := range packages {
+		results <- SourceResult{
+			Source: s,
+			Repo:   s.makeRepo(pkg),
+		}
+	}
+}
+
+func (s *NpmPackagesSource) GetRepo(ctx context.Context, repoName string) (*types.Repo, error) {
+	pkg, err := reposource.ParseNpmPackageFromRepoURL(repoName)
+	if err != nil {
+		return nil, err
+	}
+
+	dependencies, err := NpmDependencies(*s.config)
+	if err != nil {
+		return nil, err
+	}
+
+	nonExistentDependencies := make([]reposource.NpmDependency, 0)
+	hasAtLeastOneValidDependency := false
+	for _, dep := range dependencies {
+		if dep.NpmPackage == pkg {
+			if s.client.Exists(ctx, dep) {
+				hasAtLeastOneValidDependency = true
+			} else {
+				nonExistentDependencies = append(nonExistentDependencies, dep)
+			}
+		}
+	}
+
+	if !hasAtLeastOneValidDependency {
+		return nil, &npmDependencyNotFound{
+			dependencies: nonExistentDependencies,
+		}
+	}
+
+	for _, nonExistentDependency := range nonExistentDependencies {
+		// Like for JVM packages, a single version that fails to resolve
+		// doesn't reject the whole package.
+		log15.Warn("Skipping non-existing npm package", "nonExistentDependency", nonExistentDependency.PackageManagerSyntax())
+	}
+
+	return s.makeRepo(pkg), nil
+}
+
+type npmDependencyNotFound struct {
+	dependencies []reposource.NpmDependency
+}
+
+func (e *npmDependencyNotFound) Error() string {
+	return fmt.Sprintf("not found: npm dependency '%v'", e.dependencies)
+}
+
+func (e *npmDependencyNotFound) NotFound() bool {
+	return true
+}
+
+func (s *NpmPackagesSource) makeRepo(pkg reposource.NpmPackage) *types.Repo {
+	urn := s.svc.URN()
+	return &types.Repo{
+		Name: pkg.RepoName(),
+		URI:  string(pkg.RepoName()),
+		ExternalRepo: api.ExternalRepoSpec{
+			ID:          string(pkg.RepoName()),
+			ServiceID:   extsvc.TypeNpmPackages,
+			ServiceType: extsvc.TypeNpmPackages,
+		},
+		Private: false,
+		Sources: map[string]*types.SourceInfo{
+			urn: {
+				ID:       urn,
+				CloneURL: pkg.CloneURL(),
+			},
+		},
+		Metadata: &npmpackages.Metadata{
+			Package: pkg,
+		},
+	}
+}
+
+// ExternalServices returns a singleton slice containing the external service.
+func (s *NpmPackagesSource) ExternalServices() types.ExternalServices {
+	return types.ExternalServices{s.svc}
+}
+
+func NpmDependencies(connection schema.NpmPackagesConnection) (dependencies []reposource.NpmDependency, err error) {
+	for _, dep := range connection.Dependencies {
+		dependency, err := reposource.ParseNpmDependency(dep)
+		if err != nil {
+			return nil, err
+		}
+		dependencies = append(dependencies, dependency)
+	}
+	return dependencies, nil
+}
+
+func NpmPackages(connection schema.NpmPackagesConnection) ([]reposource.NpmPackage, error) {
+	isAdded := make(map[reposource.NpmPackage]bool)
+	packages := []reposource.NpmPackage{}
+	dependencies, err := NpmDependencies(connection)
+	if err != nil {
+		return nil, err
+	}
+	for _, dep := range dependencies {
+		if !isAdded[dep.NpmPackage] {
+			packages = append(packages, dep.NpmPackage)
+		}
+		isAdded[dep.NpmPackage] = true
+	}
+	return packages, nil
+}
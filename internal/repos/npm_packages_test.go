@@ -0,0 +1,69 @@
+package repos
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestNpmPackagesSource_GetRepo(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/@types/node/16.4.13" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{"name":"@types/node","version":"16.4.13","dist":{"tarball":"https://example.com/node.tgz"}}`))
+	}))
+	defer srv.Close()
+
+	svc := &types.ExternalService{ID: 1, Kind: extsvc.KindNpmPackages}
+	src, err := newNpmPackagesSource(svc, &schema.NpmPackagesConnection{
+		Registry:     srv.URL,
+		Dependencies: []string{"@types/node@16.4.13", "@types/node@1.0.0", "react@17.0.2"},
+	}, http.DefaultClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	repo, err := src.GetRepo(ctx, "npm/types/node")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if have, want := repo.Name, api.RepoName("npm/types/node"); have != want {
+		t.Errorf("wrong repo name: have %q, want %q", have, want)
+	}
+	if have, want := repo.ExternalRepo.ServiceType, extsvc.TypeNpmPackages; have != want {
+		t.Errorf("wrong service type: have %q, want %q", have, want)
+	}
+
+	// None of the configured versions of react exist in the registry.
+	_, err = src.GetRepo(ctx, "npm/react")
+	if !errcode.IsNotFound(err) {
+		t.Errorf("expected not found error, got %v", err)
+	}
+}
+
+func TestNpmPackages(t *testing.T) {
+	packages, err := NpmPackages(schema.NpmPackagesConnection{
+		Dependencies: []string{"react@17.0.2", "@types/node@16.4.13", "react@16.0.0"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var have []api.RepoName
+	for _, pkg := range packages {
+		have = append(have, pkg.RepoName())
+	}
+	want := []api.RepoName{"npm/react", "npm/types/node"}
+	if len(have) != len(want) || have[0] != want[0] || have[1] != want[1] {
+		t.Errorf("wrong packages: have %v, want %v", have, want)
+	}
+}
@@ -47,6 +47,36 @@ func NewSyncWorker(ctx context.Context, db dbutil.DB, handler workerutil.Handler
 		Isolation: sql.LevelReadCommitted,
 	})
 
+	store := newSyncWorkerStore(dbHandle)
+
+	worker := dbworker.NewWorker(ctx, store, handler, workerutil.WorkerOptions{
+		Name:              "repo_sync_worker",
+		NumHandlers:       opts.NumHandlers,
+		Interval:          opts.WorkerInterval,
+		HeartbeatInterval: 15 * time.Second,
+		Metrics:           newWorkerMetrics(opts.PrometheusRegisterer),
+	})
+
+	resetter := dbworker.NewResetter(store, dbworker.ResetterOptions{
+		Name:     "repo_sync_worker_resetter",
+		Interval: 5 * time.Minute,
+		Metrics:  newResetterMetrics(opts.PrometheusRegisterer),
+	})
+
+	if opts.CleanupOldJobs {
+		go runJobCleaner(ctx, db, opts.CleanupOldJobsInterval)
+	}
+
+	return worker, resetter
+}
+
+// NewSyncWorkerStore creates the dbworker store of the external service sync jobs
+// queue over the given database handle.
+func NewSyncWorkerStore(db dbutil.DB) store.Store {
+	return newSyncWorkerStore(basestore.NewHandleWithDB(db, sql.TxOptions{}))
+}
+
+func newSyncWorkerStore(handle *basestore.TransactableHandle) store.Store {
 	syncJobColumns := []*sqlf.Query{
 		sqlf.Sprintf("id"),
 		sqlf.Sprintf("state"),
@@ -61,7 +91,7 @@ func NewSyncWorker(ctx context.Context, db dbutil.DB, handler workerutil.Handler
 		sqlf.Sprintf("next_sync_at"),
 	}
 
-	store := store.New(dbHandle, store.Options{
+	return store.New(handle, store.Options{
 		Name:              "repo_sync_worker_store",
 		TableName:         "external_service_sync_jobs",
 		ViewName:          "external_service_sync_jobs_with_next_sync_at",
@@ -72,26 +102,6 @@ func NewSyncWorker(ctx context.Context, db dbutil.DB, handler workerutil.Handler
 		MaxNumResets:      5,
 		MaxNumRetries:     0,
 	})
-
-	worker := dbworker.NewWorker(ctx, store, handler, workerutil.WorkerOptions{
-		Name:              "repo_sync_worker",
-		NumHandlers:       opts.NumHandlers,
-		Interval:          opts.WorkerInterval,
-		HeartbeatInterval: 15 * time.Second,
-		Metrics:           newWorkerMetrics(opts.PrometheusRegisterer),
-	})
-
-	resetter := dbworker.NewResetter(store, dbworker.ResetterOptions{
-		Name:     "repo_sync_worker_resetter",
-		Interval: 5 * time.Minute,
-		Metrics:  newResetterMetrics(opts.PrometheusRegisterer),
-	})
-
-	if opts.CleanupOldJobs {
-		go runJobCleaner(ctx, db, opts.CleanupOldJobsInterval)
-	}
-
-	return worker, resetter
 }
 
 func newWorkerMetrics(r prometheus.Registerer) workerutil.WorkerMetrics {
@@ -0,0 +1,243 @@
+// Package fuzzy implements the matching and ranking used by fuzzy search
+// (patterntype:fuzzy). A pattern matches a candidate if its characters
+// appear in the candidate in order, like in fuzzy file finders. Candidates
+// are ranked by how well the matched characters line up with the start of
+// words in the candidate.
+package fuzzy
+
+import (
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/sourcegraph/sourcegraph/internal/search/query"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+)
+
+// Scoring constants. A matched character scores scoreMatch, plus a bonus if
+// it starts a word or directly follows the previous matched character.
+// Skipping characters between two matched characters is penalized.
+const (
+	scoreMatch       = 16
+	bonusBoundary    = 16
+	bonusConsecutive = 8
+	penaltyGapStart  = 3
+	penaltyGapExtend = 1
+
+	// bonusBaseName is added if the pattern matches the last path
+	// component, so that "usrsvc" ranks user_service.go above
+	// user/service/main.go.
+	bonusBaseName = 32
+)
+
+// MaxCandidates is the number of candidates backends are asked for in a
+// fuzzy search. Candidates are ranked once they are all found, so the search
+// backends may not truncate them to the number of results shown.
+const MaxCandidates = 10000
+
+// Regexp returns a regular expression which matches every string pattern is
+// a fuzzy match for. It is used to find candidates with the regexp search
+// backends, which are then ranked with Score. Whitespace in pattern is
+// ignored.
+func Regexp(pattern string) string {
+	var parts []string
+	for _, r := range pattern {
+		if unicode.IsSpace(r) {
+			continue
+		}
+		parts = append(parts, regexp.QuoteMeta(string(r)))
+	}
+	return strings.Join(parts, ".*?")
+}
+
+// QueryPattern returns the fuzzy pattern of q, which is used to rank its
+// results. Negated patterns are ignored.
+func QueryPattern(q query.Q) string {
+	var patterns []string
+	query.VisitPattern(q, func(value string, negated bool, annotation query.Annotation) {
+		if !negated && annotation.Labels.IsSet(query.Fuzzy) {
+			patterns = append(patterns, value)
+		}
+	})
+	return strings.Join(patterns, " ")
+}
+
+// Score returns how well pattern matches candidate, ignoring case. ok is
+// false if the characters of pattern do not appear in candidate in order.
+// Higher scores are better matches.
+func Score(pattern, candidate string) (score int, ok bool) {
+	p := []rune(strings.ToLower(strings.Join(strings.Fields(pattern), "")))
+	c := []rune(candidate)
+	if len(p) == 0 {
+		return 0, true
+	}
+	if len(p) > len(c) {
+		return 0, false
+	}
+
+	lower := make([]rune, len(c))
+	bonus := make([]int, len(c))
+	for j, r := range c {
+		lower[j] = unicode.ToLower(r)
+		if j == 0 || isBoundary(c[j-1], r) {
+			bonus[j] = bonusBoundary
+		}
+	}
+
+	// prev[j] is the best score of matching p[:i] with p[i-1] matched at
+	// c[j]. noMatch marks impossible alignments.
+	const noMatch = -1 << 30
+	prev := make([]int, len(c))
+	cur := make([]int, len(c))
+	for j := range c {
+		prev[j] = noMatch
+		if lower[j] == p[0] {
+			prev[j] = scoreMatch + bonus[j]
+		}
+	}
+
+	for i := 1; i < len(p); i++ {
+		// gap is the best score of an alignment of p[:i] which ends
+		// before c[j-1], including the penalty for the gap up to c[j].
+		gap := noMatch
+		for j := range c {
+			cur[j] = noMatch
+			if j >= 2 && prev[j-2] != noMatch {
+				gap = max(gap-penaltyGapExtend, prev[j-2]-penaltyGapStart)
+			} else if gap != noMatch {
+				gap -= penaltyGapExtend
+			}
+			if lower[j] != p[i] || j == 0 {
+				continue
+			}
+			best := gap
+			if prev[j-1] != noMatch {
+				best = max(best, prev[j-1]+bonusConsecutive)
+			}
+			if best != noMatch {
+				cur[j] = best + scoreMatch + bonus[j]
+			}
+		}
+		prev, cur = cur, prev
+	}
+
+	score = noMatch
+	for _, s := range prev {
+		score = max(score, s)
+	}
+	if score == noMatch {
+		return 0, false
+	}
+	return score, true
+}
+
+// isBoundary returns true if r starts a new word after prev, as in the
+// "S" of "userService", the "s" of "user_service" or the "2" of "v2".
+func isBoundary(prev, r rune) bool {
+	switch {
+	case !unicode.IsLetter(prev) && !unicode.IsDigit(prev):
+		return unicode.IsLetter(r) || unicode.IsDigit(r)
+	case unicode.IsLower(prev) && unicode.IsUpper(r):
+		return true
+	case unicode.IsLetter(prev) && unicode.IsDigit(r):
+		return true
+	}
+	return false
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// PathScore is Score for a file path. A match of the last path component is
+// preferred over a match spread across directories.
+func PathScore(pattern, p string) (score int, ok bool) {
+	if score, ok := Score(pattern, path.Base(p)); ok {
+		return score + bonusBaseName, true
+	}
+	return Score(pattern, p)
+}
+
+// MatchScore returns the score of the best match of pattern in m. Symbol
+// results are scored by symbol name, file results by path and repository
+// results by name.
+func MatchScore(pattern string, m result.Match) (score int, ok bool) {
+	score, _, ok = bestMatch(pattern, m)
+	return score, ok
+}
+
+// bestMatch is MatchScore, but also returns the string which was matched.
+func bestMatch(pattern string, m result.Match) (score int, matched string, ok bool) {
+	switch v := m.(type) {
+	case *result.FileMatch:
+		if len(v.Symbols) == 0 {
+			score, ok = PathScore(pattern, v.Path)
+			return score, v.Path, ok
+		}
+		for _, sym := range v.Symbols {
+			s, symOK := Score(pattern, sym.Symbol.Name)
+			if symOK && (!ok || s > score || (s == score && len(sym.Symbol.Name) < len(matched))) {
+				score, matched, ok = s, sym.Symbol.Name, true
+			}
+		}
+		return score, matched, ok
+	case *result.RepoMatch:
+		score, ok = Score(pattern, string(v.Name))
+		return score, string(v.Name), ok
+	}
+	return 0, "", false
+}
+
+// Sort orders matches by descending MatchScore for pattern. Matches which
+// do not match pattern are placed last. Ties are broken by the length of the
+// matched path or symbol name, so that the closest match comes first. The
+// symbols of each file match are sorted in the same way.
+func Sort(pattern string, matches []result.Match) {
+	type scored struct {
+		match   result.Match
+		score   int
+		matched string
+		ok      bool
+	}
+
+	s := make([]scored, len(matches))
+	for i, m := range matches {
+		if fm, ok := m.(*result.FileMatch); ok && len(fm.Symbols) > 1 {
+			sortSymbols(pattern, fm.Symbols)
+		}
+		score, matched, ok := bestMatch(pattern, m)
+		s[i] = scored{match: m, score: score, matched: matched, ok: ok}
+	}
+
+	sort.SliceStable(s, func(i, j int) bool {
+		if s[i].ok != s[j].ok {
+			return s[i].ok
+		}
+		if s[i].score != s[j].score {
+			return s[i].score > s[j].score
+		}
+		return len(s[i].matched) < len(s[j].matched)
+	})
+
+	for i := range s {
+		matches[i] = s[i].match
+	}
+}
+
+func sortSymbols(pattern string, symbols []*result.SymbolMatch) {
+	scores := make(map[*result.SymbolMatch]int, len(symbols))
+	for _, sym := range symbols {
+		scores[sym], _ = Score(pattern, sym.Symbol.Name)
+	}
+	sort.SliceStable(symbols, func(i, j int) bool {
+		if scores[symbols[i]] != scores[symbols[j]] {
+			return scores[symbols[i]] > scores[symbols[j]]
+		}
+		return len(symbols[i].Symbol.Name) < len(symbols[j].Symbol.Name)
+	})
+}
@@ -0,0 +1,110 @@
+package fuzzy
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/search/query"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+)
+
+func TestRegexp(t *testing.T) {
+	re := regexp.MustCompile("(?i)" + Regexp("UsrSvc.Ctrl"))
+	for _, s := range []string{
+		"cmd/user_service.controller.go",
+		"UserService.Controller",
+	} {
+		if !re.MatchString(s) {
+			t.Errorf("expected %q to match %s", s, re)
+		}
+	}
+	if re.MatchString("user_service_controller.go") {
+		t.Errorf("expected . to be matched literally by %s", re)
+	}
+
+	if got, want := Regexp("a b"), "a.*?b"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestQueryPattern(t *testing.T) {
+	q, err := query.ParseSearchType("usr svc type:path", query.SearchTypeFuzzy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := QueryPattern(q), "usr svc"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestScore(t *testing.T) {
+	for _, c := range []struct {
+		pattern, candidate string
+		ok                 bool
+	}{
+		{"UsrSvcCtrl", "user_service_controller.go", true},
+		{"usrsvcctrl", "UserServiceController", true},
+		{"usr svc", "user_service.go", true},
+		{"", "anything", true},
+		{"ctrlsvc", "user_service_controller.go", false},
+		{"abc", "ab", false},
+	} {
+		if _, ok := Score(c.pattern, c.candidate); ok != c.ok {
+			t.Errorf("Score(%q, %q) got ok=%v, want %v", c.pattern, c.candidate, ok, c.ok)
+		}
+	}
+}
+
+func TestSort(t *testing.T) {
+	fileMatch := func(path string) *result.FileMatch {
+		return &result.FileMatch{File: result.File{Path: path}}
+	}
+
+	matches := []result.Match{
+		fileMatch("README.md"),
+		fileMatch("user/service/controller/main.go"),
+		fileMatch("internal/user_service_controller_test.go"),
+		fileMatch("internal/user_service_controller.go"),
+	}
+	Sort("UsrSvcCtrl", matches)
+
+	var got []string
+	for _, m := range matches {
+		got = append(got, m.(*result.FileMatch).Path)
+	}
+	want := []string{
+		"internal/user_service_controller.go",
+		"internal/user_service_controller_test.go",
+		"user/service/controller/main.go",
+		"README.md",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected order (-want +got):\n%s", diff)
+	}
+}
+
+func TestSortSymbols(t *testing.T) {
+	file := &result.File{Path: "main.go"}
+	symbol := func(name string) *result.SymbolMatch {
+		return &result.SymbolMatch{Symbol: result.Symbol{Name: name}, File: file}
+	}
+
+	matches := []result.Match{
+		&result.FileMatch{File: *file, Symbols: []*result.SymbolMatch{symbol("newUserSession"), symbol("NewUserService")}},
+		&result.FileMatch{File: *file, Symbols: []*result.SymbolMatch{symbol("UserService")}},
+	}
+	Sort("usrsvc", matches)
+
+	var got []string
+	for _, m := range matches {
+		for _, sym := range m.(*result.FileMatch).Symbols {
+			got = append(got, sym.Symbol.Name)
+		}
+	}
+	want := []string{"UserService", "NewUserService", "newUserSession"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected order (-want +got):\n%s", diff)
+	}
+}
@@ -15,6 +15,8 @@ const (
 	FieldType               = "type"
 	FieldRepoHasFile        = "repohasfile"
 	FieldRepoHasCommitAfter = "repohascommitafter"
+	FieldRepoHasDescription = "repohasdescription"
+	FieldFileHasOwner       = "filehasowner"
 	FieldPatternType        = "patterntype"
 	FieldContent            = "content"
 	FieldVisibility         = "visibility"
@@ -28,12 +30,21 @@ const (
 	FieldCommitter = "committer"
 	FieldMessage   = "message"
 
+	// For text search only, filtering matched lines by `git blame`:
+	FieldBlameAuthor = "blame.author"
+	FieldBlameBefore = "blame.before"
+	FieldBlameAfter  = "blame.after"
+
+	// For symbol search only:
+	FieldSymbolParent = "symbol.parent"
+
 	// Temporary experimental fields:
-	FieldIndex     = "index"
-	FieldCount     = "count" // Searches that specify `count:` will fetch at least that number of results, or the full result set
-	FieldTimeout   = "timeout"
-	FieldCombyRule = "rule"
-	FieldSelect    = "select"
+	FieldIndex        = "index"
+	FieldCount        = "count" // Searches that specify `count:` will fetch at least that number of results, or the full result set
+	FieldTimeout      = "timeout"
+	FieldCombyRule    = "rule"
+	FieldCombyRewrite = "rewrite"
+	FieldSelect       = "select"
 )
 
 var allFields = map[string]struct{}{
@@ -56,6 +67,8 @@ var allFields = map[string]struct{}{
 	FieldVisibility:         empty,
 	FieldRepoHasFile:        empty,
 	FieldRepoHasCommitAfter: empty,
+	FieldRepoHasDescription: empty,
+	FieldFileHasOwner:       empty,
 	FieldBefore:             empty,
 	"until":                 empty,
 	FieldAfter:              empty,
@@ -65,10 +78,15 @@ var allFields = map[string]struct{}{
 	FieldMessage:            empty,
 	"m":                     empty,
 	"msg":                   empty,
+	FieldBlameAuthor:        empty,
+	FieldBlameBefore:        empty,
+	FieldBlameAfter:         empty,
+	FieldSymbolParent:       empty,
 	FieldIndex:              empty,
 	FieldCount:              empty,
 	FieldTimeout:            empty,
 	FieldCombyRule:          empty,
+	FieldCombyRewrite:       empty,
 	FieldRev:                empty,
 	"revision":              empty,
 	FieldSelect:             empty,
@@ -15,6 +15,7 @@ const (
 	HeuristicHoisted
 	Structural
 	IsPredicate
+	Fuzzy
 )
 
 var allLabels = map[labels]string{
@@ -27,6 +28,7 @@ var allLabels = map[labels]string{
 	HeuristicHoisted:          "HeuristicHoisted",
 	Structural:                "Structural",
 	IsPredicate:               "IsPredicate",
+	Fuzzy:                     "Fuzzy",
 }
 
 func (l *labels) IsSet(label labels) bool {
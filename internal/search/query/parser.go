@@ -605,6 +605,11 @@ func ScanField(buf []byte) (string, bool, int) {
 			result = append(result, r)
 			continue
 		}
+		if r == '.' && len(buf) > 0 && strings.ContainsRune(allowed, rune(buf[0])) {
+			// Dots separate namespaced fields like `blame.author:`.
+			result = append(result, r)
+			continue
+		}
 		if r == ':' {
 			// Invariant: len(result) > 0. If len(result) == 1,
 			// check that it is not just a '-'. If len(result) > 1, it is valid.
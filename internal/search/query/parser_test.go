@@ -205,6 +205,16 @@ func TestScanPredicate(t *testing.T) {
 		ResultLabels: "IsPredicate",
 	}).Equal(t, test(`repo:contains.commit.after(last thursday)`))
 
+	autogold.Want("Repo has description predicate", value{
+		Result:       `{"field":"repo","value":"has.description(go.*library)","negated":false}`,
+		ResultLabels: "IsPredicate",
+	}).Equal(t, test(`repo:has.description(go.*library)`))
+
+	autogold.Want("File has owner predicate", value{
+		Result:       `{"field":"file","value":"has.owner(@org/infra)","negated":false}`,
+		ResultLabels: "IsPredicate",
+	}).Equal(t, test(`file:has.owner(@org/infra)`))
+
 	autogold.Want("Repo contains commit before predicate does not exist", value{
 		Result:       `{"field":"repo","value":"contains.commit.before(yesterday)","negated":false}`,
 		ResultLabels: "None",
@@ -262,6 +272,10 @@ func TestScanField(t *testing.T) {
 	autogold.Want("-repo", `{"Field":"","Negated":false,"Advance":0}`).Equal(t, test("-repo"))
 	autogold.Want("--repo:", `{"Field":"","Negated":false,"Advance":0}`).Equal(t, test("--repo:"))
 	autogold.Want(":foo", `{"Field":"","Negated":false,"Advance":0}`).Equal(t, test(":foo"))
+	autogold.Want("blame.author:foo", `{"Field":"blame.author","Negated":false,"Advance":13}`).Equal(t, test("blame.author:foo"))
+	autogold.Want("-blame.after:", `{"Field":"blame.after","Negated":true,"Advance":13}`).Equal(t, test("-blame.after:"))
+	autogold.Want("blame.:foo", `{"Field":"","Negated":false,"Advance":0}`).Equal(t, test("blame.:foo"))
+	autogold.Want("blame.nope:foo", `{"Field":"","Negated":false,"Advance":0}`).Equal(t, test("blame.nope:foo"))
 }
 
 func parseAndOrGrammar(in string) ([]Node, error) {
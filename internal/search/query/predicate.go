@@ -2,6 +2,7 @@ package query
 
 import (
 	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/cockroachdb/errors"
@@ -31,10 +32,12 @@ var DefaultPredicateRegistry = predicateRegistry{
 		"contains.file":         func() Predicate { return &RepoContainsFilePredicate{} },
 		"contains.content":      func() Predicate { return &RepoContainsContentPredicate{} },
 		"contains.commit.after": func() Predicate { return &RepoContainsCommitAfterPredicate{} },
+		"has.description":       func() Predicate { return &RepoHasDescriptionPredicate{} },
 	},
 	FieldFile: {
 		"contains.content": func() Predicate { return &FileContainsContentPredicate{} },
 		"contains":         func() Predicate { return &FileContainsContentPredicate{} },
+		"has.owner":        func() Predicate { return &FileHasOwnerPredicate{} },
 	},
 }
 
@@ -243,6 +246,41 @@ func (f *RepoContainsCommitAfterPredicate) Plan(parent Basic) (Plan, error) {
 	return ToPlan(Dnf(nodes))
 }
 
+/* repo:has.description(pattern) */
+
+type RepoHasDescriptionPredicate struct {
+	Pattern string
+}
+
+func (f *RepoHasDescriptionPredicate) ParseParams(params string) error {
+	if _, err := regexp.Compile(params); err != nil {
+		return errors.Errorf("repo:has.description argument: %w", err)
+	}
+	if params == "" {
+		return errors.Errorf("repo:has.description argument should not be empty")
+	}
+	f.Pattern = params
+	return nil
+}
+
+func (f *RepoHasDescriptionPredicate) Field() string { return FieldRepo }
+func (f *RepoHasDescriptionPredicate) Name() string  { return "has.description" }
+func (f *RepoHasDescriptionPredicate) Plan(parent Basic) (Plan, error) {
+	nodes := make([]Node, 0, 3)
+	nodes = append(nodes, Parameter{
+		Field: FieldCount,
+		Value: "99999",
+	}, Parameter{
+		Field: FieldRepoHasDescription,
+		Value: f.Pattern,
+	})
+
+	nodes = append(nodes, nonPredicateRepos(parent)...)
+	return ToPlan(Dnf(nodes))
+}
+
+/* file:contains.content(pattern) */
+
 type FileContainsContentPredicate struct {
 	Pattern string
 }
@@ -278,6 +316,119 @@ func (f *FileContainsContentPredicate) Plan(parent Basic) (Plan, error) {
 	return ToPlan(Dnf(nodes))
 }
 
+/* file:has.owner(owner) */
+
+// FileHasOwnerPredicate represents the `file:has.owner()` predicate, which
+// filters to files owned by an owner according to the repository's
+// CODEOWNERS file.
+type FileHasOwnerPredicate struct {
+	Owner string
+}
+
+func (f *FileHasOwnerPredicate) ParseParams(params string) error {
+	params = strings.TrimSpace(params)
+	if params == "" {
+		return errors.Errorf("file:has.owner argument should not be empty")
+	}
+	if strings.ContainsAny(params, " \t") {
+		return errors.Errorf("file:has.owner argument should be a single owner")
+	}
+	f.Owner = params
+	return nil
+}
+
+func (f *FileHasOwnerPredicate) Field() string { return FieldFile }
+func (f *FileHasOwnerPredicate) Name() string  { return "has.owner" }
+
+// fileHasOwnerMaxPaths bounds the number of paths file:has.owner() considers
+// in each repository, and the number of CODEOWNERS files it looks at. Every
+// owned path becomes a file: filter of the expanded query.
+const fileHasOwnerMaxPaths = 10000
+
+// Plan returns the query for the CODEOWNERS files of the repositories the
+// parent query searches. The caller reads the rules of each of them and
+// expands the predicate with OwnedPathsPlan.
+func (f *FileHasOwnerPredicate) Plan(parent Basic) (Plan, error) {
+	nodes := make([]Node, 0, 3)
+	nodes = append(nodes, Parameter{
+		Field: FieldCount,
+		Value: strconv.Itoa(fileHasOwnerMaxPaths),
+	}, Parameter{
+		Field: FieldType,
+		Value: "path",
+	}, Parameter{
+		// Only the repository and commit of a match are used, the
+		// CODEOWNERS file itself is looked up in its usual locations.
+		Field: FieldFile,
+		Value: "(^|/)CODEOWNERS$",
+	})
+
+	nodes = append(nodes, nonPredicateRepos(parent)...)
+	return ToPlan(Dnf(nodes))
+}
+
+// OwnedPathsPlan returns the query for the paths of repo that match one of
+// patterns, the regular expressions of the CODEOWNERS rules that list the
+// owner. A later rule can take ownership away from a matching path, so the
+// results are filtered on the CODEOWNERS file again by the filehasowner:
+// parameter.
+func (f *FileHasOwnerPredicate) OwnedPathsPlan(parent Basic, repo string, patterns []string) (Plan, error) {
+	nodes := make([]Node, 0, 5)
+	nodes = append(nodes, Parameter{
+		Field: FieldCount,
+		Value: strconv.Itoa(fileHasOwnerMaxPaths),
+	}, Parameter{
+		Field: FieldType,
+		Value: "path",
+	}, Parameter{
+		Field: FieldRepo,
+		Value: "^" + regexp.QuoteMeta(repo) + "$",
+	}, Parameter{
+		Field: FieldFile,
+		Value: unionPatterns(patterns),
+	}, Parameter{
+		Field: FieldFileHasOwner,
+		Value: f.Owner,
+	})
+
+	// Only consider paths that the parent query can match.
+	nodes = append(nodes, nonPredicateFiles(parent)...)
+	nodes = append(nodes, nonPredicateRepos(parent)...)
+	return ToPlan(Dnf(nodes))
+}
+
+func unionPatterns(patterns []string) string {
+	if len(patterns) == 1 {
+		return patterns[0]
+	}
+	groups := make([]string, len(patterns))
+	for i, p := range patterns {
+		groups[i] = "(?:" + p + ")"
+	}
+	return strings.Join(groups, "|")
+}
+
+// nonPredicateFiles returns the file and language filters in a query that
+// aren't predicates.
+func nonPredicateFiles(q Basic) []Node {
+	var res []Node
+	VisitParameter(q.ToParseTree(), func(field, value string, negated bool, ann Annotation) {
+		if ann.Labels.IsSet(IsPredicate) {
+			return
+		}
+		switch field {
+		case FieldFile, FieldLang:
+			res = append(res, Parameter{
+				Field:      field,
+				Value:      value,
+				Negated:    negated,
+				Annotation: ann,
+			})
+		}
+	})
+	return res
+}
+
 // nonPredicateRepos returns the repo nodes in a query that aren't predicates,
 // respecting parameters that determine repo results.
 func nonPredicateRepos(q Basic) []Node {
@@ -55,6 +55,97 @@ func TestRepoContainsPredicate(t *testing.T) {
 	})
 }
 
+func TestRepoHasDescriptionPredicate(t *testing.T) {
+	t.Run("ParseParams", func(t *testing.T) {
+		p := &RepoHasDescriptionPredicate{}
+		if err := p.ParseParams(`go.*(library|lib)`); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if want := (&RepoHasDescriptionPredicate{Pattern: `go.*(library|lib)`}); !reflect.DeepEqual(want, p) {
+			t.Fatalf("expected %#v, got %#v", want, p)
+		}
+
+		for _, params := range []string{``, `(`} {
+			if err := (&RepoHasDescriptionPredicate{}).ParseParams(params); err == nil {
+				t.Fatalf("expected error for %q but got none", params)
+			}
+		}
+	})
+
+	t.Run("Plan", func(t *testing.T) {
+		parent, err := ParseLiteral(`repo:^github\.com/sourcegraph/ repo:has.description(go) foo`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		plan, err := (&RepoHasDescriptionPredicate{Pattern: "go"}).Plan(mustPlan(t, parent)[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := `count:99999 repohasdescription:go repo:^github\.com/sourcegraph/`
+		if got := StringHuman(plan.ToParseTree()); got != want {
+			t.Fatalf("expected %s, got %s", want, got)
+		}
+	})
+}
+
+func TestFileHasOwnerPredicate(t *testing.T) {
+	t.Run("ParseParams", func(t *testing.T) {
+		valid := []string{`@infra`, `@org/infra`, `docs@example.com`, ` @infra `}
+		for _, params := range valid {
+			p := &FileHasOwnerPredicate{}
+			if err := p.ParseParams(params); err != nil {
+				t.Fatalf("unexpected error for %q: %s", params, err)
+			}
+		}
+
+		invalid := []string{``, `   `, `@infra @search`}
+		for _, params := range invalid {
+			if err := (&FileHasOwnerPredicate{}).ParseParams(params); err == nil {
+				t.Fatalf("expected error for %q but got none", params)
+			}
+		}
+	})
+
+	t.Run("Plan", func(t *testing.T) {
+		parent, err := ParseLiteral(`repo:sourcegraph file:has.owner(@infra) -file:_test lang:go foo`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		plan, err := (&FileHasOwnerPredicate{Owner: "@infra"}).Plan(mustPlan(t, parent)[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := `count:10000 type:path file:(^|/)CODEOWNERS$ repo:sourcegraph`
+		if got := StringHuman(plan.ToParseTree()); got != want {
+			t.Fatalf("expected %s, got %s", want, got)
+		}
+	})
+
+	t.Run("OwnedPathsPlan", func(t *testing.T) {
+		parent, err := ParseLiteral(`repo:sourcegraph file:has.owner(@infra) -file:_test lang:go foo`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		plan, err := (&FileHasOwnerPredicate{Owner: "@infra"}).OwnedPathsPlan(mustPlan(t, parent)[0], "github.com/sourcegraph/sourcegraph", []string{`^infra/.*$`, `^(?:.*/)?Dockerfile$`})
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := `count:10000 type:path repo:^github\.com/sourcegraph/sourcegraph$ file:(?:^infra/.*$)|(?:^(?:.*/)?Dockerfile$) filehasowner:@infra -file:_test lang:go repo:sourcegraph`
+		if got := StringHuman(plan.ToParseTree()); got != want {
+			t.Fatalf("expected %s, got %s", want, got)
+		}
+	})
+}
+
+func mustPlan(t *testing.T, nodes []Node) Plan {
+	t.Helper()
+	p, err := ToPlan(Dnf(nodes))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
 func TestParseAsPredicate(t *testing.T) {
 	tests := []struct {
 		input  string
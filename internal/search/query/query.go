@@ -58,6 +58,8 @@ func For(searchType SearchType) step {
 		processType = succeeds(escapeParensHeuristic, substituteConcat(fuzzyRegexp))
 	case SearchTypeStructural:
 		processType = succeeds(labelStructural, ellipsesForHoles, substituteConcat(space))
+	case SearchTypeFuzzy:
+		processType = succeeds(labelFuzzy, substituteConcat(space))
 	}
 	normalize := succeeds(LowercaseFieldNames, SubstituteAliases(searchType), SubstituteCountAll)
 	return sequence(normalize, processType)
@@ -87,6 +89,11 @@ func InitStructural(in string) step {
 	return Init(in, SearchTypeStructural)
 }
 
+// InitFuzzy is Init where SearchType is Fuzzy.
+func InitFuzzy(in string) step {
+	return Init(in, SearchTypeFuzzy)
+}
+
 func Run(step step) ([]Node, error) {
 	return step(nil)
 }
@@ -686,6 +686,21 @@ func labelStructural(nodes []Node) []Node {
 	})
 }
 
+// labelFuzzy labels patterns of a fuzzy search. They are parsed like
+// literal patterns, but are matched as a subsequence of file names and
+// symbols.
+func labelFuzzy(nodes []Node) []Node {
+	return MapPattern(nodes, func(value string, negated bool, annotation Annotation) Node {
+		annotation.Labels.unset(Literal)
+		annotation.Labels.set(Fuzzy)
+		return Pattern{
+			Value:      value,
+			Negated:    negated,
+			Annotation: annotation,
+		}
+	})
+}
+
 // ellipsesForHoles substitutes ellipses ... for :[_] holes in structural search queries.
 func ellipsesForHoles(nodes []Node) []Node {
 	return MapPattern(nodes, func(value string, negated bool, annotation Annotation) Node {
@@ -325,6 +325,23 @@ func TestEllipsesForHoles(t *testing.T) {
 	})
 }
 
+func TestLabelFuzzy(t *testing.T) {
+	query, err := Run(InitFuzzy("usr svc(ctrl type:symbol"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := ToBasicQuery(query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !b.IsFuzzy() || b.IsLiteral() {
+		t.Fatalf("expected pattern to be labeled fuzzy, got %s", toString(query))
+	}
+	if want, got := "usr svc(ctrl", b.Pattern.(Pattern).Value; got != want {
+		t.Fatalf("got pattern %q, want %q", got, want)
+	}
+}
+
 func TestConvertEmptyGroupsToLiteral(t *testing.T) {
 	cases := []struct {
 		input      string
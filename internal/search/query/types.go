@@ -29,6 +29,7 @@ const (
 	SearchTypeRegex SearchType = iota
 	SearchTypeLiteral
 	SearchTypeStructural
+	SearchTypeFuzzy
 )
 
 func (s SearchType) String() string {
@@ -39,6 +40,8 @@ func (s SearchType) String() string {
 		return "literal"
 	case SearchTypeStructural:
 		return "structural"
+	case SearchTypeFuzzy:
+		return "fuzzy"
 	default:
 		return fmt.Sprintf("unknown{%d}", s)
 	}
@@ -173,6 +176,10 @@ func (b Basic) IsStructural() bool {
 	return b.HasPatternLabel(Structural)
 }
 
+func (b Basic) IsFuzzy() bool {
+	return b.HasPatternLabel(Fuzzy)
+}
+
 // FindParameter calls f on parameters matching field in b.
 func (b Basic) FindParameter(field string, f func(value string, negated bool, annotation Annotation)) {
 	for _, p := range b.Parameters {
@@ -393,11 +400,14 @@ func (q Q) valueToTypedValue(field, value string, label labels) []*Value {
 		FieldContent:
 		return []*Value{{String: &value}}
 
-	case FieldRepoHasFile:
+	case
+		FieldRepoHasFile,
+		FieldRepoHasDescription:
 		return []*Value{{Regexp: parseRegexpOrPanic(field, value)}}
 
 	case
 		FieldRepoHasCommitAfter,
+		FieldFileHasOwner,
 		FieldBefore, "until",
 		FieldAfter, "since":
 		return []*Value{{String: &value}}
@@ -405,14 +415,22 @@ func (q Q) valueToTypedValue(field, value string, label labels) []*Value {
 	case
 		FieldAuthor,
 		FieldCommitter,
-		FieldMessage, "m", "msg":
+		FieldMessage, "m", "msg",
+		FieldBlameAuthor,
+		FieldSymbolParent:
 		return []*Value{{Regexp: parseRegexpOrPanic(field, value)}}
 
+	case
+		FieldBlameBefore,
+		FieldBlameAfter:
+		return []*Value{{String: &value}}
+
 	case
 		FieldIndex,
 		FieldCount,
 		FieldTimeout,
-		FieldCombyRule:
+		FieldCombyRule,
+		FieldCombyRewrite:
 		return []*Value{{String: &value}}
 	}
 	return []*Value{{String: &value}}
@@ -308,6 +308,12 @@ func validateField(field, value string, negated bool, seen map[string]struct{})
 	case
 		FieldRepoHasCommitAfter:
 		return satisfies(isSingular, isNotNegated)
+	case
+		FieldRepoHasDescription:
+		return satisfies(isValidRegexp, isNotNegated)
+	case
+		FieldFileHasOwner:
+		return satisfies(isNotNegated)
 	case
 		FieldBefore,
 		FieldAfter:
@@ -317,6 +323,14 @@ func validateField(field, value string, negated bool, seen map[string]struct{})
 		FieldCommitter,
 		FieldMessage:
 		return satisfies(isValidRegexp)
+	case
+		FieldBlameAuthor,
+		FieldSymbolParent:
+		return satisfies(isSingular, isValidRegexp, isNotNegated)
+	case
+		FieldBlameBefore,
+		FieldBlameAfter:
+		return satisfies(isSingular, isNotNegated)
 	case
 		FieldIndex,
 		FieldFork,
@@ -326,7 +340,8 @@ func validateField(field, value string, negated bool, seen map[string]struct{})
 		FieldCount:
 		return satisfies(isSingular, isNumber, isNotNegated)
 	case
-		FieldCombyRule:
+		FieldCombyRule,
+		FieldCombyRewrite:
 		return satisfies(isSingular, isNotNegated)
 	case
 		FieldTimeout:
@@ -421,6 +436,47 @@ func validateTypeStructural(nodes []Node) error {
 	return nil
 }
 
+// validateTypeFuzzy validates that a fuzzy search only searches file names
+// and symbols, since fuzzy patterns are only ranked for those.
+func validateTypeFuzzy(nodes []Node) error {
+	var seenFuzzy bool
+	var invalidType string
+	VisitPattern(nodes, func(_ string, _ bool, annotation Annotation) {
+		if annotation.Labels.IsSet(Fuzzy) {
+			seenFuzzy = true
+		}
+	})
+	VisitField(nodes, FieldType, func(value string, _ bool, _ Annotation) {
+		if value != "path" && value != "symbol" {
+			invalidType = value
+		}
+	})
+	if seenFuzzy && invalidType != "" {
+		return errors.Errorf("this fuzzy search query specifies `type:%s` and is not supported. Fuzzy search only applies to file names (type:path) and symbols (type:symbol)", invalidType)
+	}
+	return nil
+}
+
+// validateCombyRewrite validates that a rewrite template is only given for a
+// structural search, since only comby can apply it.
+func validateCombyRewrite(nodes []Node) error {
+	var seenRewrite, seenStructural bool
+	VisitParameter(nodes, func(field, _ string, _ bool, _ Annotation) {
+		if field == FieldCombyRewrite {
+			seenRewrite = true
+		}
+	})
+	VisitPattern(nodes, func(_ string, _ bool, annotation Annotation) {
+		if annotation.Labels.IsSet(Structural) {
+			seenStructural = true
+		}
+	})
+	if seenRewrite && !seenStructural {
+		return errors.New("the rewrite: parameter requires a structural search pattern. Add patterntype:structural to the query")
+	}
+	return nil
+}
+
 // validatePredicates validates predicate parameters with respect to their validation logic.
 func validatePredicates(nodes []Node) error {
 	var err error
@@ -532,6 +588,8 @@ func validate(nodes []Node) error {
 		validateCommitParameters,
 		validatePredicates,
 		validateTypeStructural,
+		validateTypeFuzzy,
+		validateCombyRewrite,
 	)
 }
 
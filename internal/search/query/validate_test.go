@@ -99,6 +99,27 @@ func TestAndOrQuery_Validation(t *testing.T) {
 			input: "type:symbol select:symbol.timelime",
 			want:  `invalid field "timelime" on select path "symbol.timelime"`,
 		},
+		{
+			input: "-blame.author:rob",
+			want:  `field "blame.author" does not support negation`,
+		},
+		{
+			input: `foo blame.after:2021-01-01 blame.after:"1 week ago"`,
+			want:  `field "blame.after" may not be used more than once`,
+		},
+		{
+			input: "type:symbol -symbol.parent:Server",
+			want:  `field "symbol.parent" does not support negation`,
+		},
+		{
+			input: "foo(:[x]) rewrite:bar(:[x])",
+			want:  "the rewrite: parameter requires a structural search pattern. Add patterntype:structural to the query",
+		},
+		{
+			input:      "foo(:[x]) -rewrite:bar(:[x])",
+			want:       `field "rewrite" does not support negation`,
+			searchType: SearchTypeStructural,
+		},
 		{
 			input:      "nice try type:repo",
 			want:       "this structural search query specifies `type:` and is not supported. Structural search syntax only applies to searching file contents",
@@ -109,6 +130,11 @@ func TestAndOrQuery_Validation(t *testing.T) {
 			want:       "this structural search query specifies `type:` and is not supported. Structural search syntax only applies to searching file contents and is not currently supported for diff searches",
 			searchType: SearchTypeStructural,
 		},
+		{
+			input:      "UsrSvcCtrl type:file",
+			want:       "this fuzzy search query specifies `type:file` and is not supported. Fuzzy search only applies to file names (type:path) and symbols (type:symbol)",
+			searchType: SearchTypeFuzzy,
+		},
 	}
 	for _, c := range cases {
 		t.Run("validate and/or query", func(t *testing.T) {
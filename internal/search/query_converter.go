@@ -10,6 +10,7 @@ import (
 	"github.com/go-enry/go-enry/v2"
 	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/search/filter"
+	"github.com/sourcegraph/sourcegraph/internal/search/fuzzy"
 	"github.com/sourcegraph/sourcegraph/internal/search/query"
 
 	zoekt "github.com/google/zoekt/query"
@@ -34,9 +35,9 @@ func unionRegexp(values []string) string {
 	return "(" + strings.Join(values, ")|(") + ")"
 }
 
-// langToFileRegexp converts a lang: parameter to its corresponding file
+// LangToFileRegexp converts a lang: parameter to its corresponding file
 // patterns for file filters. The lang value must be valid, cf. validate.go
-func langToFileRegexp(lang string) string {
+func LangToFileRegexp(lang string) string {
 	lang, _ = enry.GetLanguageByAlias(lang) // Invariant: lang is valid.
 	extensions := enry.GetLanguageExtensions(lang)
 	patterns := make([]string, len(extensions))
@@ -67,6 +68,12 @@ func IncludeExcludeValues(q query.Basic, field string) (include, exclude []strin
 }
 
 func count(q query.Basic, p Protocol) int {
+	if q.IsFuzzy() {
+		// Fuzzy search results are ranked after they are found, so count:
+		// only limits the number of ranked results returned.
+		return fuzzy.MaxCandidates
+	}
+
 	if count := q.GetCount(); count != "" {
 		v, _ := strconv.Atoi(count) // Invariant: count is validated.
 		return v
@@ -102,8 +109,8 @@ func ToTextPatternInfo(q query.Basic, p Protocol, transform query.BasicPass) *Te
 	filesInclude, filesExclude := IncludeExcludeValues(q, query.FieldFile)
 	// Handle lang: and -lang: filters.
 	langInclude, langExclude := IncludeExcludeValues(q, query.FieldLang)
-	filesInclude = append(filesInclude, mapSlice(langInclude, langToFileRegexp)...)
-	filesExclude = append(filesExclude, mapSlice(langExclude, langToFileRegexp)...)
+	filesInclude = append(filesInclude, mapSlice(langInclude, LangToFileRegexp)...)
+	filesExclude = append(filesExclude, mapSlice(langExclude, LangToFileRegexp)...)
 	filesReposMustInclude, filesReposMustExclude := IncludeExcludeValues(q, query.FieldRepoHasFile)
 	selector, _ := filter.SelectPathFromString(q.FindValue(query.FieldSelect)) // Invariant: select is validated
 	count := count(q, p)
@@ -111,13 +118,18 @@ func ToTextPatternInfo(q query.Basic, p Protocol, transform query.BasicPass) *Te
 	// Ugly assumption: for a literal search, the IsRegexp member of
 	// TextPatternInfo must be set true. The logic assumes that a literal
 	// pattern is an escaped regular expression.
-	isRegexp := q.IsLiteral() || q.IsRegexp()
+	isRegexp := q.IsLiteral() || q.IsRegexp() || q.IsFuzzy()
 
 	var pattern string
 	if p, ok := q.Pattern.(query.Pattern); ok {
 		if q.IsLiteral() {
 			// Escape regexp meta characters if this pattern should be treated literally.
 			pattern = regexp.QuoteMeta(p.Value)
+		} else if q.IsFuzzy() {
+			// Backends find up to fuzzy.MaxCandidates file names or
+			// symbols containing the pattern as a subsequence. The
+			// results are ranked by fuzzy.Sort.
+			pattern = fuzzy.Regexp(p.Value)
 		} else {
 			pattern = p.Value
 		}
@@ -134,6 +146,18 @@ func ToTextPatternInfo(q query.Basic, p Protocol, transform query.BasicPass) *Te
 		negated = p.Negated
 	}
 
+	// Blame filters are applied by searcher after matching, and symbol
+	// parents only by the symbols service, so queries using them are never
+	// sent to zoekt.
+	index := q.Index()
+	blameAuthor := q.FindValue(query.FieldBlameAuthor)
+	blameBefore := q.FindValue(query.FieldBlameBefore)
+	blameAfter := q.FindValue(query.FieldBlameAfter)
+	symbolParent := q.FindValue(query.FieldSymbolParent)
+	if blameAuthor != "" || blameBefore != "" || blameAfter != "" || symbolParent != "" {
+		index = query.No
+	}
+
 	return &TextPatternInfo{
 		// Values dependent on pattern atom.
 		IsRegExp:        isRegexp,
@@ -151,8 +175,13 @@ func ToTextPatternInfo(q query.Basic, p Protocol, transform query.BasicPass) *Te
 		Languages:                    langInclude,
 		PathPatternsAreCaseSensitive: q.IsCaseSensitive(),
 		CombyRule:                    q.FindValue(query.FieldCombyRule),
-		Index:                        q.Index(),
+		CombyRewrite:                 q.FindValue(query.FieldCombyRewrite),
+		Index:                        index,
 		Select:                       selector,
+		BlameAuthor:                  blameAuthor,
+		BlameBefore:                  blameBefore,
+		BlameAfter:                   blameAfter,
+		SymbolParent:                 symbolParent,
 	}
 }
 
@@ -12,10 +12,11 @@ import (
 type RepoStatus uint8
 
 const (
-	RepoStatusCloning  RepoStatus = 1 << iota // could not be searched because they were still being cloned
-	RepoStatusMissing                         // could not be searched because they do not exist
-	RepoStatusLimitHit                        // searched, but have results that were not returned due to exceeded limits
-	RepoStatusTimedout                        // repos that were not searched due to timeout
+	RepoStatusCloning        RepoStatus = 1 << iota // could not be searched because they were still being cloned
+	RepoStatusMissing                               // could not be searched because they do not exist
+	RepoStatusLimitHit                              // searched, but have results that were not returned due to exceeded limits
+	RepoStatusTimedout                              // repos that were not searched due to timeout
+	RepoStatusBlameTruncated                        // searched, but not all matches could be checked against blame filters
 )
 
 var repoStatusName = []struct {
@@ -26,6 +27,7 @@ var repoStatusName = []struct {
 	{RepoStatusMissing, "missing"},
 	{RepoStatusLimitHit, "limithit"},
 	{RepoStatusTimedout, "timedout"},
+	{RepoStatusBlameTruncated, "blametruncated"},
 }
 
 func (s RepoStatus) String() string {
@@ -123,7 +123,7 @@ func (r *Resolver) Resolve(ctx context.Context, op search.RepoOptions) (Resolved
 
 	var searchableRepos []types.RepoName
 
-	if envvar.SourcegraphDotComMode() && len(includePatterns) == 0 && !query.HasTypeRepo(op.Query) && searchcontexts.IsGlobalSearchContext(searchContext) {
+	if envvar.SourcegraphDotComMode() && len(includePatterns) == 0 && len(op.DescriptionPatterns) == 0 && !query.HasTypeRepo(op.Query) && searchcontexts.IsGlobalSearchContext(searchContext) {
 		start := time.Now()
 		searchableRepos, err = searchableRepositories(ctx, r.SearchableReposFunc, r.Zoekt, excludePatterns)
 		if err != nil {
@@ -148,9 +148,10 @@ func (r *Resolver) Resolve(ctx context.Context, op search.RepoOptions) (Resolved
 		tr.LazyPrintf("Repos.List - start")
 
 		options := database.ReposListOptions{
-			IncludePatterns: includePatterns,
-			Names:           versionContextRepositories,
-			ExcludePattern:  UnionRegExps(excludePatterns),
+			IncludePatterns:     includePatterns,
+			Names:               versionContextRepositories,
+			ExcludePattern:      UnionRegExps(excludePatterns),
+			DescriptionPatterns: op.DescriptionPatterns,
 			// List N+1 repos so we can see if there are repos omitted due to our repo limit.
 			LimitOffset:  &database.LimitOffset{Limit: limit + 1},
 			NoForks:      op.NoForks,
@@ -47,6 +47,11 @@ type FileMatch struct {
 	Symbols     []*SymbolMatch `json:"-"`
 
 	LimitHit bool
+
+	// RewriteDiff is the unified diff of rewriting the file with the
+	// rewrite: template of a structural search. It is only set for such
+	// searches, in which case LineMatches is empty.
+	RewriteDiff string `json:",omitempty"`
 }
 
 func (fm *FileMatch) RepoName() types.RepoName {
@@ -76,6 +81,7 @@ func (fm *FileMatch) Select(selectPath filter.SelectPath) Match {
 	case filter.File:
 		fm.LineMatches = nil
 		fm.Symbols = nil
+		fm.RewriteDiff = ""
 		if len(selectPath) > 1 && selectPath[1] == "directory" {
 			fm.Path = path.Clean(path.Dir(fm.Path)) + "/" // Add trailing slash for clarity.
 		}
@@ -94,8 +100,8 @@ func (fm *FileMatch) Select(selectPath filter.SelectPath) Match {
 		}
 		return nil
 	case filter.Content:
-		// Only return file match if line matches exist
-		if len(fm.LineMatches) > 0 {
+		// Only return file match if line matches or a rewrite exist
+		if len(fm.LineMatches) > 0 || fm.RewriteDiff != "" {
 			fm.Symbols = nil
 			return fm
 		}
@@ -3,6 +3,7 @@ package result
 import (
 	"fmt"
 	"net/url"
+	"sort"
 	"strconv"
 	"strings"
 	"unicode/utf8"
@@ -252,6 +253,19 @@ func pick(symbols []*SymbolMatch, satisfy func(*SymbolMatch) bool) []*SymbolMatc
 	return result
 }
 
+// SelectSymbolKinds returns the ctags kinds of the symbols that
+// select:symbol.<field> selects, in lexicographic order.
+func SelectSymbolKinds(field string) []string {
+	var kinds []string
+	for kind, selectKind := range toSelectKind {
+		if selectKind == field {
+			kinds = append(kinds, kind)
+		}
+	}
+	sort.Strings(kinds)
+	return kinds
+}
+
 func SelectSymbolKind(symbols []*SymbolMatch, field string) []*SymbolMatch {
 	return pick(symbols, func(s *SymbolMatch) bool {
 		return field == toSelectKind[strings.ToLower(s.Symbol.Kind)]
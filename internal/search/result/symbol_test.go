@@ -67,3 +67,19 @@ func TestSymbolURL(t *testing.T) {
 		})
 	}
 }
+
+func TestSelectSymbolKinds(t *testing.T) {
+	want := []string{"interface"}
+	if diff := cmp.Diff(want, SelectSymbolKinds("interface")); diff != "" {
+		t.Fatal(diff)
+	}
+
+	want = []string{"anonmember", "field", "member", "recordfield"}
+	if diff := cmp.Diff(want, SelectSymbolKinds("field")); diff != "" {
+		t.Fatal(diff)
+	}
+
+	if kinds := SelectSymbolKinds("unknown"); kinds != nil {
+		t.Fatalf("expected no kinds, got %v", kinds)
+	}
+}
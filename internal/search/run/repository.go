@@ -57,6 +57,7 @@ func SearchRepositories(ctx context.Context, args *search.TextParameters, limit
 		query.FieldCase:               {},
 		query.FieldRepoHasFile:        {},
 		query.FieldRepoHasCommitAfter: {},
+		query.FieldRepoHasDescription: {},
 		query.FieldPatternType:        {},
 		query.FieldSelect:             {},
 	}
@@ -0,0 +1,218 @@
+package searchcontexts
+
+import (
+	"context"
+	"regexp/syntax"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/google/zoekt"
+	zoektquery "github.com/google/zoekt/query"
+	"github.com/inconshreveable/log15"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/backend"
+	"github.com/sourcegraph/sourcegraph/internal/search/query"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+const maxSearchContextQueryLength = 1024
+
+// searchContextQueryFields are the fields allowed in the query of a search
+// context. Each of them narrows down the set of repositories.
+var searchContextQueryFields = map[string]bool{
+	query.FieldRepo:       true,
+	query.FieldFork:       true,
+	query.FieldArchived:   true,
+	query.FieldVisibility: true,
+	query.FieldLang:       true,
+}
+
+// searchContextQuery is a parsed search context query.
+type searchContextQuery struct {
+	includePatterns []string
+	excludePatterns []string
+	fork            query.YesNoOnly
+	archived        query.YesNoOnly
+	visibility      string
+	langs           []string
+}
+
+// parseSearchContextQuery parses and validates the query of a search context,
+// e.g. `repo:^github\.com/acme/ lang:go -repo:archived`.
+func parseSearchContextQuery(q string) (*searchContextQuery, error) {
+	if len(q) > maxSearchContextQueryLength {
+		return nil, errors.Errorf("search context query exceeds maximum allowed length (%d)", maxSearchContextQueryLength)
+	}
+
+	plan, err := query.Pipeline(query.Init(q, query.SearchTypeRegex))
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid search context query")
+	}
+	if len(plan) != 1 {
+		return nil, errors.New("search context query must not contain 'or' operators")
+	}
+	basic := plan[0]
+	if basic.Pattern != nil {
+		return nil, errors.New("search context query must only contain filters, such as repo: and lang:")
+	}
+
+	parsed := &searchContextQuery{fork: query.No, archived: query.No, visibility: string(query.Any)}
+	for _, p := range basic.Parameters {
+		if !searchContextQueryFields[p.Field] {
+			return nil, errors.Errorf("search context query does not support the %s: filter", p.Field)
+		}
+		switch p.Field {
+		case query.FieldRepo:
+			repo, revs := search.ParseRepositoryRevisions(p.Value)
+			if len(revs) > 0 {
+				return nil, errors.New("search context query must not contain revisions, the default branch of each repository is searched")
+			}
+			if p.Negated {
+				parsed.excludePatterns = append(parsed.excludePatterns, repo)
+			} else {
+				parsed.includePatterns = append(parsed.includePatterns, repo)
+			}
+		case query.FieldFork:
+			parsed.fork = query.ParseYesNoOnly(p.Value)
+		case query.FieldArchived:
+			parsed.archived = query.ParseYesNoOnly(p.Value)
+		case query.FieldVisibility:
+			parsed.visibility = string(query.ParseVisibility(p.Value))
+		case query.FieldLang:
+			if p.Negated {
+				return nil, errors.New("search context query does not support negated lang: filters")
+			}
+			parsed.langs = append(parsed.langs, p.Value)
+		}
+	}
+	return parsed, nil
+}
+
+// ValidateSearchContextQuery returns an error if q can not be used to define
+// the repositories of a search context.
+func ValidateSearchContextQuery(q string) error {
+	_, err := parseSearchContextQuery(q)
+	return err
+}
+
+// ResolveSearchContextQuery returns the repositories matching the search
+// context query q. Repository filters are evaluated against the database.
+// lang: filters are evaluated against the search index, so only indexed
+// repositories can match them.
+//
+// 🚨 SECURITY: The returned repositories are only filtered by the permissions
+// of the actor in ctx.
+func ResolveSearchContextQuery(ctx context.Context, db dbutil.DB, zoektClient *backend.Zoekt, q string) ([]types.RepoName, error) {
+	parsed, err := parseSearchContextQuery(q)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := database.ReposListOptions{
+		IncludePatterns: parsed.includePatterns,
+		NoForks:         parsed.fork == query.No,
+		OnlyForks:       parsed.fork == query.Only,
+		NoArchived:      parsed.archived == query.No,
+		OnlyArchived:    parsed.archived == query.Only,
+		NoPrivate:       parsed.visibility == string(query.Public),
+		OnlyPrivate:     parsed.visibility == string(query.Private),
+	}
+	if len(parsed.excludePatterns) > 0 {
+		opts.ExcludePattern = "(?:" + strings.Join(parsed.excludePatterns, ")|(?:") + ")"
+	}
+	repos, err := database.Repos(db).ListRepoNames(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	if len(parsed.langs) == 0 || len(repos) == 0 {
+		return repos, nil
+	}
+
+	if zoektClient == nil || !zoektClient.Enabled() {
+		return nil, errors.New("search context queries with lang: filters require indexed search")
+	}
+
+	names := make([]string, 0, len(repos))
+	for _, repo := range repos {
+		names = append(names, string(repo.Name))
+	}
+	and := []zoektquery.Q{zoektquery.NewRepoSet(names...)}
+	for _, lang := range parsed.langs {
+		re, err := syntax.Parse(search.LangToFileRegexp(lang), syntax.Perl)
+		if err != nil {
+			return nil, err
+		}
+		and = append(and, &zoektquery.Regexp{Regexp: re, FileName: true})
+	}
+	list, err := zoektClient.Client.List(ctx, zoektquery.NewAnd(and...), &zoekt.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "listing indexed repositories")
+	}
+	matching := make(map[string]bool, len(list.Repos))
+	for _, entry := range list.Repos {
+		matching[entry.Repository.Name] = true
+	}
+
+	filtered := repos[:0]
+	for _, repo := range repos {
+		if matching[string(repo.Name)] {
+			filtered = append(filtered, repo)
+		}
+	}
+	return filtered, nil
+}
+
+// resolveQueryRepositories returns all repositories matching the query of a
+// search context, for caching them in the search context.
+func resolveQueryRepositories(ctx context.Context, db dbutil.DB, zoektClient *backend.Zoekt, q string) ([]types.RepoName, error) {
+	// 🚨 SECURITY: The search context must contain every repository matching
+	// its query. Repositories the searching user can't access are filtered out
+	// when searching.
+	return ResolveSearchContextQuery(actor.WithInternalActor(ctx), db, zoektClient, q)
+}
+
+// refreshQuerySearchContext resolves the repositories matching the query of
+// searchContext and caches them.
+func refreshQuerySearchContext(ctx context.Context, db dbutil.DB, zoektClient *backend.Zoekt, searchContext *types.SearchContext) error {
+	repos, err := resolveQueryRepositories(ctx, db, zoektClient, searchContext.Query)
+	if err != nil {
+		return err
+	}
+	return database.SearchContexts(db).SetQuerySearchContextRepositories(ctx, searchContext.ID, repos)
+}
+
+// RefreshQuerySearchContexts re-resolves the repositories of search contexts
+// defined by a query, whose repositories were resolved longer than maxAge
+// ago, so that new and renamed repositories are picked up.
+func RefreshQuerySearchContexts(ctx context.Context, db dbutil.DB, zoektClient *backend.Zoekt, maxAge time.Duration) error {
+	ctx = actor.WithInternalActor(ctx)
+	store := database.SearchContexts(db)
+
+	const pageSize = 100
+	var searchContexts []*types.SearchContext
+	for offset := int32(0); ; offset += pageSize {
+		page, err := store.ListSearchContexts(ctx, database.ListSearchContextsPageOptions{First: pageSize, After: offset}, database.ListSearchContextsOptions{OnlyQueryDefined: true})
+		if err != nil {
+			return err
+		}
+		searchContexts = append(searchContexts, page...)
+		if len(page) < pageSize {
+			break
+		}
+	}
+
+	for _, searchContext := range searchContexts {
+		if time.Since(searchContext.QueryReposUpdatedAt) < maxAge {
+			continue
+		}
+		if err := refreshQuerySearchContext(ctx, db, zoektClient, searchContext); err != nil {
+			log15.Warn("failed to resolve repositories of search context query", "searchContext", GetSearchContextSpec(searchContext), "error", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package searchcontexts
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/zoekt"
+	zoektquery "github.com/google/zoekt/query"
+
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtesting"
+	"github.com/sourcegraph/sourcegraph/internal/search/backend"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func TestValidateSearchContextQuery(t *testing.T) {
+	tests := []struct {
+		query   string
+		wantErr string
+	}{
+		{query: `repo:^github\.com/acme/ lang:go -repo:archived`},
+		{query: `repo:foo fork:yes archived:only visibility:private`},
+		{query: `repo:foo bar`, wantErr: "search context query must only contain filters, such as repo: and lang:"},
+		{query: `repo:foo file:bar`, wantErr: "search context query does not support the file: filter"},
+		{query: `repo:foo@main`, wantErr: "search context query must not contain revisions, the default branch of each repository is searched"},
+		{query: `repo:foo -lang:go`, wantErr: "search context query does not support negated lang: filters"},
+		{query: `repo:foo or repo:bar`, wantErr: "search context query must not contain 'or' operators"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			err := ValidateSearchContextQuery(tt.query)
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if tt.wantErr != "" && (err == nil || err.Error() != tt.wantErr) {
+				t.Fatalf("got error %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+type fakeZoektLister struct {
+	zoekt.Searcher
+	repos []string
+	query zoektquery.Q
+}
+
+func (f *fakeZoektLister) List(ctx context.Context, q zoektquery.Q, opts *zoekt.ListOptions) (*zoekt.RepoList, error) {
+	f.query = q
+	list := &zoekt.RepoList{}
+	for _, name := range f.repos {
+		list.Repos = append(list.Repos, &zoekt.RepoListEntry{Repository: zoekt.Repository{Name: name}})
+	}
+	return list, nil
+}
+
+func TestResolveSearchContextQuery(t *testing.T) {
+	db := new(dbtesting.MockDB)
+
+	var gotOpts database.ReposListOptions
+	database.Mocks.Repos.ListRepoNames = func(ctx context.Context, opts database.ReposListOptions) ([]types.RepoName, error) {
+		gotOpts = opts
+		return []types.RepoName{{ID: 1, Name: "github.com/acme/a"}, {ID: 2, Name: "github.com/acme/b"}}, nil
+	}
+	defer func() { database.Mocks.Repos.ListRepoNames = nil }()
+
+	lister := &fakeZoektLister{repos: []string{"github.com/acme/b"}}
+	zoektClient := &backend.Zoekt{Client: &backend.StreamSearchAdapter{Searcher: lister}}
+
+	t.Run("repo filters", func(t *testing.T) {
+		repos, err := ResolveSearchContextQuery(context.Background(), db, zoektClient, `repo:^github\.com/acme/ -repo:archived -repo:old visibility:public`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		wantOpts := database.ReposListOptions{
+			IncludePatterns: []string{`^github\.com/acme/`},
+			ExcludePattern:  "(?:archived)|(?:old)",
+			NoForks:         true,
+			NoArchived:      true,
+			NoPrivate:       true,
+		}
+		if diff := cmp.Diff(wantOpts, gotOpts); diff != "" {
+			t.Errorf("unexpected list options (-want +got):\n%s", diff)
+		}
+		if len(repos) != 2 {
+			t.Errorf("got %d repos, want 2", len(repos))
+		}
+		if lister.query != nil {
+			t.Errorf("unexpected zoekt query %s", lister.query)
+		}
+	})
+
+	t.Run("lang filter", func(t *testing.T) {
+		repos, err := ResolveSearchContextQuery(context.Background(), db, zoektClient, `repo:^github\.com/acme/ lang:go`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := []types.RepoName{{ID: 2, Name: "github.com/acme/b"}}
+		if diff := cmp.Diff(want, repos); diff != "" {
+			t.Errorf("unexpected repos (-want +got):\n%s", diff)
+		}
+		wantQuery := `(and (reposet github.com/acme/a github.com/acme/b) file_regex:"(?-m:\\.go$)")`
+		if lister.query == nil || lister.query.String() != wantQuery {
+			t.Errorf("got zoekt query %v, want %s", lister.query, wantQuery)
+		}
+	})
+}
@@ -193,10 +193,22 @@ func CreateSearchContextWithRepositoryRevisions(ctx context.Context, db dbutil.D
 		return nil, err
 	}
 
+	queryRepos, err := resolveSearchContextQueryRepositories(ctx, db, searchContext, repositoryRevisions)
+	if err != nil {
+		return nil, err
+	}
+
 	searchContext, err = database.SearchContexts(db).CreateSearchContextWithRepositoryRevisions(ctx, searchContext, repositoryRevisions)
 	if err != nil {
 		return nil, err
 	}
+
+	if searchContext.Query != "" {
+		err = database.SearchContexts(db).SetQuerySearchContextRepositories(ctx, searchContext.ID, queryRepos)
+		if err != nil {
+			return nil, err
+		}
+	}
 	return searchContext, nil
 }
 
@@ -225,13 +237,37 @@ func UpdateSearchContextWithRepositoryRevisions(ctx context.Context, db dbutil.D
 		return nil, err
 	}
 
+	queryRepos, err := resolveSearchContextQueryRepositories(ctx, db, searchContext, repositoryRevisions)
+	if err != nil {
+		return nil, err
+	}
+
 	searchContext, err = database.SearchContexts(db).UpdateSearchContextWithRepositoryRevisions(ctx, searchContext, repositoryRevisions)
 	if err != nil {
 		return nil, err
 	}
+
+	if searchContext.Query != "" {
+		err = database.SearchContexts(db).SetQuerySearchContextRepositories(ctx, searchContext.ID, queryRepos)
+		if err != nil {
+			return nil, err
+		}
+	}
 	return searchContext, nil
 }
 
+// resolveSearchContextQueryRepositories validates the query of searchContext, if any, and returns the
+// repositories matching it.
+func resolveSearchContextQueryRepositories(ctx context.Context, db dbutil.DB, searchContext *types.SearchContext, repositoryRevisions []*types.SearchContextRepositoryRevisions) ([]types.RepoName, error) {
+	if searchContext.Query == "" {
+		return nil, nil
+	}
+	if len(repositoryRevisions) > 0 {
+		return nil, errors.New("search context query and repositories are mutually exclusive")
+	}
+	return resolveQueryRepositories(ctx, db, search.Indexed(), searchContext.Query)
+}
+
 func DeleteSearchContext(ctx context.Context, db dbutil.DB, searchContext *types.SearchContext) error {
 	if IsAutoDefinedSearchContext(searchContext) {
 		return errors.New("cannot delete auto-defined search context")
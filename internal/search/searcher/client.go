@@ -41,10 +41,11 @@ var (
 	}
 )
 
-var MockSearch func(ctx context.Context, repo api.RepoName, commit api.CommitID, p *search.TextPatternInfo, fetchTimeout time.Duration) (matches []*protocol.FileMatch, limitHit bool, err error)
+var MockSearch func(ctx context.Context, repo api.RepoName, commit api.CommitID, p *search.TextPatternInfo, fetchTimeout time.Duration) (matches []*protocol.FileMatch, limitHit, blameTruncated bool, err error)
 
-// Search searches repo@commit with p.
-func Search(ctx context.Context, searcherURLs *endpoint.Map, repo api.RepoName, branch string, commit api.CommitID, indexed bool, p *search.TextPatternInfo, fetchTimeout time.Duration, indexerEndpoints []string) (matches []*protocol.FileMatch, limitHit bool, err error) {
+// Search searches repo@commit with p. blameTruncated is true if searcher could
+// not check all matching files against the blame filters of p.
+func Search(ctx context.Context, searcherURLs *endpoint.Map, repo api.RepoName, branch string, commit api.CommitID, indexed bool, p *search.TextPatternInfo, fetchTimeout time.Duration, indexerEndpoints []string) (matches []*protocol.FileMatch, limitHit, blameTruncated bool, err error) {
 	if MockSearch != nil {
 		return MockSearch(ctx, repo, commit, p, fetchTimeout)
 	}
@@ -73,7 +74,7 @@ func Search(ctx context.Context, searcherURLs *endpoint.Map, repo api.RepoName,
 	if deadline, ok := ctx.Deadline(); ok {
 		t, err := deadline.MarshalText()
 		if err != nil {
-			return nil, false, err
+			return nil, false, false, err
 		}
 		q.Set("Deadline", string(t))
 	}
@@ -103,6 +104,18 @@ func Search(ctx context.Context, searcherURLs *endpoint.Map, repo api.RepoName,
 	// these fields from old frontends that do not (and provide a default in the latter case).
 	q.Set("PatternMatchesContent", strconv.FormatBool(p.PatternMatchesContent))
 	q.Set("PatternMatchesPath", strconv.FormatBool(p.PatternMatchesPath))
+	if p.CombyRewrite != "" {
+		q.Set("CombyRewrite", p.CombyRewrite)
+	}
+	if p.BlameAuthor != "" {
+		q.Set("BlameAuthor", p.BlameAuthor)
+	}
+	if p.BlameBefore != "" {
+		q.Set("BlameBefore", p.BlameBefore)
+	}
+	if p.BlameAfter != "" {
+		q.Set("BlameAfter", p.BlameAfter)
+	}
 	rawQuery := q.Encode()
 
 	// Searcher caches the file contents for repo@commit since it is
@@ -122,7 +135,7 @@ func Search(ctx context.Context, searcherURLs *endpoint.Map, repo api.RepoName,
 
 		searcherURL, err := searcherURLs.Get(consistentHashKey, excludedSearchURLs)
 		if err != nil {
-			return nil, false, err
+			return nil, false, false, err
 		}
 
 		// Fallback to a bad host if nothing is left
@@ -130,25 +143,25 @@ func Search(ctx context.Context, searcherURLs *endpoint.Map, repo api.RepoName,
 			tr.LazyPrintf("failed to find endpoint, trying again without excludes")
 			searcherURL, err = searcherURLs.Get(consistentHashKey, nil)
 			if err != nil {
-				return nil, false, err
+				return nil, false, false, err
 			}
 		}
 
 		url := searcherURL + "?" + rawQuery
 		tr.LazyPrintf("attempt %d: %s", attempt, url)
-		matches, limitHit, err = textSearchURL(ctx, url)
+		matches, limitHit, blameTruncated, err = textSearchURL(ctx, url)
 		if err == nil || errcode.IsTimeout(err) {
-			return matches, limitHit, err
+			return matches, limitHit, blameTruncated, err
 		}
 
 		// If we are canceled, return that error.
 		if err := ctx.Err(); err != nil {
-			return nil, false, err
+			return nil, false, false, err
 		}
 
 		// If not temporary or our last attempt then don't try again.
 		if !errcode.IsTemporary(err) || attempt == maxAttempts {
-			return nil, false, err
+			return nil, false, false, err
 		}
 
 		tr.LazyPrintf("transient error %s", err.Error())
@@ -157,10 +170,10 @@ func Search(ctx context.Context, searcherURLs *endpoint.Map, repo api.RepoName,
 	}
 }
 
-func textSearchURL(ctx context.Context, url string) ([]*protocol.FileMatch, bool, error) {
+func textSearchURL(ctx context.Context, url string) ([]*protocol.FileMatch, bool, bool, error) {
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
-		return nil, false, err
+		return nil, false, false, err
 	}
 	req = req.WithContext(ctx)
 
@@ -179,30 +192,31 @@ func textSearchURL(ctx context.Context, url string) ([]*protocol.FileMatch, bool
 		if ctx.Err() != nil {
 			err = ctx.Err()
 		}
-		return nil, false, errors.Wrap(err, "searcher request failed")
+		return nil, false, false, errors.Wrap(err, "searcher request failed")
 	}
 	defer resp.Body.Close()
 	if resp.StatusCode != 200 {
 		body, err := io.ReadAll(resp.Body)
 		if err != nil {
-			return nil, false, err
+			return nil, false, false, err
 		}
-		return nil, false, errors.WithStack(&searcherError{StatusCode: resp.StatusCode, Message: string(body)})
+		return nil, false, false, errors.WithStack(&searcherError{StatusCode: resp.StatusCode, Message: string(body)})
 	}
 
 	r := struct {
-		Matches     []*protocol.FileMatch
-		LimitHit    bool
-		DeadlineHit bool
+		Matches        []*protocol.FileMatch
+		LimitHit       bool
+		DeadlineHit    bool
+		BlameTruncated bool
 	}{}
 	err = json.NewDecoder(resp.Body).Decode(&r)
 	if err != nil {
-		return nil, false, errors.Wrap(err, "searcher response invalid")
+		return nil, false, false, errors.Wrap(err, "searcher response invalid")
 	}
 	if r.DeadlineHit {
 		err = context.DeadlineExceeded
 	}
-	return r.Matches, r.LimitHit, err
+	return r.Matches, r.LimitHit, r.BlameTruncated, err
 }
 
 type searcherError struct {
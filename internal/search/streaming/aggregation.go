@@ -0,0 +1,171 @@
+package streaming
+
+import (
+	"regexp"
+	"sort"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+)
+
+// AggregationMode is the dimension results are grouped by in an
+// Aggregation.
+type AggregationMode string
+
+const (
+	AggregationModeRepo         AggregationMode = "repo"
+	AggregationModePath         AggregationMode = "path"
+	AggregationModeAuthor       AggregationMode = "author"
+	AggregationModeCaptureGroup AggregationMode = "capture_group"
+)
+
+// ParseAggregationMode returns the AggregationMode for s. It defaults to
+// AggregationModeRepo if s is empty.
+func ParseAggregationMode(s string) (AggregationMode, error) {
+	switch m := AggregationMode(s); m {
+	case "":
+		return AggregationModeRepo, nil
+	case AggregationModeRepo, AggregationModePath, AggregationModeAuthor, AggregationModeCaptureGroup:
+		return m, nil
+	default:
+		return "", errors.Errorf("unknown aggregation mode %q, expected one of repo, path, author or capture_group", s)
+	}
+}
+
+// AggregationGroup is the number of results which share a value of the
+// aggregated dimension.
+type AggregationGroup struct {
+	// Label is the value results were grouped by: a repository name, a
+	// file path, a commit author or the text of a capture group.
+	Label string
+
+	// Repository is the repository a path group belongs to. It is empty for
+	// the other modes.
+	Repository string
+
+	// Count is the sum of ResultCount of the results in the group. For
+	// AggregationModeCaptureGroup it is the number of times the capture
+	// group matched.
+	Count int
+}
+
+// Aggregation counts the results of a search grouped by Mode. Unlike
+// SearchFilters it keeps every group, so the counts are exact for the
+// results it has seen.
+type Aggregation struct {
+	Mode AggregationMode
+
+	// Pattern is the regular expression whose first capture group is used
+	// as the label in AggregationModeCaptureGroup. It is matched against
+	// the preview of each line match.
+	Pattern *regexp.Regexp
+
+	// ResultCount is the sum of ResultCount of every result seen, including
+	// results which did not contribute to a group.
+	ResultCount int
+
+	stats    Stats
+	limitHit bool
+	groups   map[groupKey]*AggregationGroup
+}
+
+type groupKey struct {
+	repo  string
+	label string
+}
+
+// Update adds the results and stats of event to the aggregation.
+func (a *Aggregation) Update(event SearchEvent) {
+	if a.groups == nil {
+		a.groups = make(map[groupKey]*AggregationGroup)
+	}
+
+	a.stats.Update(&event.Stats)
+
+	for _, match := range event.Results {
+		a.ResultCount += match.ResultCount()
+
+		switch a.Mode {
+		case AggregationModeRepo:
+			a.add("", string(match.RepoName().Name), match.ResultCount())
+
+		case AggregationModePath:
+			if fm, ok := match.(*result.FileMatch); ok {
+				a.add(string(fm.Repo.Name), fm.Path, fm.ResultCount())
+			}
+
+		case AggregationModeAuthor:
+			if cm, ok := match.(*result.CommitMatch); ok {
+				a.add("", cm.Commit.Author.Name, cm.ResultCount())
+			}
+
+		case AggregationModeCaptureGroup:
+			fm, ok := match.(*result.FileMatch)
+			if !ok || a.Pattern == nil {
+				continue
+			}
+			for _, lm := range fm.LineMatches {
+				for _, submatches := range a.Pattern.FindAllStringSubmatch(lm.Preview, -1) {
+					if len(submatches) > 1 {
+						a.add("", submatches[1], 1)
+					}
+				}
+			}
+		}
+
+		if fm, ok := match.(*result.FileMatch); ok && fm.LimitHit {
+			a.limitHit = true
+		}
+	}
+}
+
+func (a *Aggregation) add(repo, label string, count int) {
+	k := groupKey{repo: repo, label: label}
+	g, ok := a.groups[k]
+	if !ok {
+		g = &AggregationGroup{Label: label, Repository: repo}
+		a.groups[k] = g
+	}
+	g.Count += count
+}
+
+// Exhaustive returns true if the counts include every result matching the
+// query. It is false if a limit was hit or a repository could not be
+// searched completely.
+func (a *Aggregation) Exhaustive() bool {
+	return !(a.limitHit ||
+		a.stats.IsLimitHit ||
+		a.stats.IsIndexUnavailable ||
+		a.stats.Status.Any(search.RepoStatusCloning|search.RepoStatusMissing|search.RepoStatusLimitHit|search.RepoStatusTimedout|search.RepoStatusBlameTruncated))
+}
+
+// GroupCount returns the number of distinct groups seen.
+func (a *Aggregation) GroupCount() int {
+	return len(a.groups)
+}
+
+// Compute returns up to limit groups ordered by descending count, then by
+// label. If limit is not positive every group is returned.
+func (a *Aggregation) Compute(limit int) []AggregationGroup {
+	groups := make([]AggregationGroup, 0, len(a.groups))
+	for _, g := range a.groups {
+		groups = append(groups, *g)
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i].Count != groups[j].Count {
+			return groups[i].Count > groups[j].Count
+		}
+		if groups[i].Label != groups[j].Label {
+			return groups[i].Label < groups[j].Label
+		}
+		return groups[i].Repository < groups[j].Repository
+	})
+
+	if limit > 0 && len(groups) > limit {
+		groups = groups[:limit]
+	}
+	return groups
+}
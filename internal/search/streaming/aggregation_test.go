@@ -0,0 +1,135 @@
+package streaming
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+)
+
+func TestAggregation(t *testing.T) {
+	fileMatch := func(repo, path string, lines ...string) *result.FileMatch {
+		fm := &result.FileMatch{
+			File: result.File{
+				Repo: types.RepoName{ID: 1, Name: api.RepoName(repo)},
+				Path: path,
+			},
+		}
+		for _, l := range lines {
+			fm.LineMatches = append(fm.LineMatches, &result.LineMatch{
+				Preview:          l,
+				OffsetAndLengths: [][2]int32{{0, int32(len(l))}},
+			})
+		}
+		return fm
+	}
+	commitMatch := func(repo, author string) *result.CommitMatch {
+		return &result.CommitMatch{
+			Repo:   types.RepoName{ID: 2, Name: api.RepoName(repo)},
+			Commit: git.Commit{Author: git.Signature{Name: author}},
+		}
+	}
+
+	event := SearchEvent{
+		Results: []result.Match{
+			fileMatch("a", "main.go", `import "github.com/inconshreveable/log15"`, `log15.New()`),
+			fileMatch("b", "main.go", `import "github.com/inconshreveable/log15"`),
+			fileMatch("b", "util.go", `import "github.com/sirupsen/logrus"`),
+			commitMatch("a", "alice"),
+			commitMatch("b", "alice"),
+			commitMatch("b", "bob"),
+		},
+	}
+
+	cases := []struct {
+		mode    AggregationMode
+		pattern string
+		want    []AggregationGroup
+	}{{
+		mode: AggregationModeRepo,
+		want: []AggregationGroup{
+			{Label: "b", Count: 4},
+			{Label: "a", Count: 3},
+		},
+	}, {
+		mode: AggregationModePath,
+		want: []AggregationGroup{
+			{Label: "main.go", Repository: "a", Count: 2},
+			{Label: "main.go", Repository: "b", Count: 1},
+			{Label: "util.go", Repository: "b", Count: 1},
+		},
+	}, {
+		mode: AggregationModeAuthor,
+		want: []AggregationGroup{
+			{Label: "alice", Count: 2},
+			{Label: "bob", Count: 1},
+		},
+	}, {
+		mode:    AggregationModeCaptureGroup,
+		pattern: `github\.com/(\w+)/`,
+		want: []AggregationGroup{
+			{Label: "inconshreveable", Count: 2},
+			{Label: "sirupsen", Count: 1},
+		},
+	}}
+
+	for _, c := range cases {
+		t.Run(string(c.mode), func(t *testing.T) {
+			a := Aggregation{Mode: c.mode}
+			if c.pattern != "" {
+				a.Pattern = regexp.MustCompile(c.pattern)
+			}
+			a.Update(event)
+
+			if diff := cmp.Diff(c.want, a.Compute(0)); diff != "" {
+				t.Errorf("unexpected groups (-want +got):\n%s", diff)
+			}
+			if got, want := a.ResultCount, 7; got != want {
+				t.Errorf("got result count %d, want %d", got, want)
+			}
+			if !a.Exhaustive() {
+				t.Error("expected aggregation to be exhaustive")
+			}
+		})
+	}
+
+	t.Run("limit", func(t *testing.T) {
+		a := Aggregation{Mode: AggregationModeRepo}
+		a.Update(event)
+		if got := a.Compute(1); len(got) != 1 || got[0].Label != "b" {
+			t.Errorf("unexpected groups %v", got)
+		}
+		if got := a.GroupCount(); got != 2 {
+			t.Errorf("got group count %d, want 2", got)
+		}
+	})
+
+	t.Run("not exhaustive", func(t *testing.T) {
+		for name, stats := range map[string]Stats{
+			"limit hit": {IsLimitHit: true},
+			"timed out": {Status: search.RepoStatusSingleton(1, search.RepoStatusTimedout)},
+		} {
+			a := Aggregation{Mode: AggregationModeRepo}
+			a.Update(event)
+			a.Update(SearchEvent{Stats: stats})
+			if a.Exhaustive() {
+				t.Errorf("%s: expected aggregation to not be exhaustive", name)
+			}
+		}
+	})
+}
+
+func TestParseAggregationMode(t *testing.T) {
+	if m, err := ParseAggregationMode(""); err != nil || m != AggregationModeRepo {
+		t.Errorf("expected default mode repo, got %q %v", m, err)
+	}
+	if _, err := ParseAggregationMode("language"); err == nil {
+		t.Error("expected error for unknown mode")
+	}
+}
@@ -36,9 +36,10 @@ type ProgressStats struct {
 	ExcludedArchived    int
 	ExcludedForks       int
 
-	Timedout []Namer
-	Missing  []Namer
-	Cloning  []Namer
+	Timedout       []Namer
+	Missing        []Namer
+	Cloning        []Namer
+	BlameTruncated []Namer
 
 	LimitHit bool
 
@@ -105,6 +106,13 @@ func shardTimeoutHandler(resultsResolver ProgressStats) (Skipped, bool) {
 	})
 }
 
+func blameTruncatedHandler(resultsResolver ProgressStats) (Skipped, bool) {
+	return skippedReposHandler(resultsResolver.BlameTruncated, "blame truncated", "had too many matches to check all of them against `blame.` filters", Skipped{
+		Reason:   BlameTruncated,
+		Severity: SeverityInfo,
+	})
+}
+
 func displayLimitHandler(resultsResolver ProgressStats) (Skipped, bool) {
 	if resultsResolver.DisplayLimit >= resultsResolver.MatchCount {
 		return Skipped{}, false
@@ -193,6 +201,7 @@ var skippedHandlers = []func(stats ProgressStats) (Skipped, bool){
 	shardMatchLimitHandler,
 	// repositoryLimitHandler,
 	shardTimeoutHandler,
+	blameTruncatedHandler,
 	excludedForkHandler,
 	excludedArchiveHandler,
 	displayLimitHandler,
@@ -47,6 +47,12 @@ func TestSearchProgress(t *testing.T) {
 			SuggestedLimit:      1000,
 			DisplayLimit:        math.MaxInt32,
 		},
+		"blametruncated": {
+			MatchCount:        10,
+			RepositoriesCount: intPtr(2),
+			BlameTruncated:    []Namer{repo{"blame-1"}, repo{"blame-2"}},
+			DisplayLimit:      math.MaxInt32,
+		},
 		"traced": {
 			Trace: "abcd",
 		},
@@ -80,6 +80,9 @@ const (
 	// ExcludedArchive is when we did not search a repository because it is
 	// archived.
 	ExcludedArchive SkippedReason = "excluded-archive"
+	// BlameTruncated is when we did not check all matches in a repository
+	// against the blame filters of a query, so we dropped the rest.
+	BlameTruncated SkippedReason = "blame-truncated"
 )
 
 // SkippedSeverity is an enum for Skipped.Severity.
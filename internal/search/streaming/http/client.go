@@ -171,6 +171,8 @@ func (r *eventMatchUnmarshaller) UnmarshalJSON(b []byte) error {
 		r.EventMatch = &EventSymbolMatch{}
 	case CommitMatchType:
 		r.EventMatch = &EventCommitMatch{}
+	case RewriteMatchType:
+		r.EventMatch = &EventRewriteMatch{}
 	default:
 		return errors.Errorf("unknown MatchType %v", typeU.Type)
 	}
@@ -48,6 +48,11 @@ func TestDecoder(t *testing.T) {
 				Type:   CommitMatchType,
 				Detail: "test",
 			},
+			&EventRewriteMatch{
+				Type: RewriteMatchType,
+				Path: "test",
+				Diff: "test",
+			},
 		},
 	}, {
 		Name: "filters",
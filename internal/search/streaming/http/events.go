@@ -46,6 +46,25 @@ type EventPathMatch struct {
 
 func (e *EventPathMatch) eventMatch() {}
 
+// EventRewriteMatch is a file rewritten by the rewrite: template of a
+// structural search. The file itself is not modified, Diff previews the
+// change.
+type EventRewriteMatch struct {
+	// Type is always RewriteMatchType. Included here for marshalling.
+	Type MatchType `json:"type"`
+
+	Path       string   `json:"name"`
+	Repository string   `json:"repository"`
+	RepoStars  int      `json:"repoStars,omitempty"`
+	Branches   []string `json:"branches,omitempty"`
+	Version    string   `json:"version,omitempty"`
+
+	// Diff is a unified diff of the rewrite.
+	Diff string `json:"diff"`
+}
+
+func (e *EventRewriteMatch) eventMatch() {}
+
 // EventLineMatch is a subset of zoekt.LineMatch for our Event API.
 type EventLineMatch struct {
 	Line             string     `json:"line"`
@@ -149,22 +168,35 @@ const (
 	SymbolMatchType
 	CommitMatchType
 	PathMatchType
+	RewriteMatchType
 )
 
 func (t MatchType) MarshalJSON() ([]byte, error) {
+	s := t.String()
+	if s == "" {
+		return nil, errors.Errorf("unknown MatchType: %d", t)
+	}
+	return []byte(`"` + s + `"`), nil
+}
+
+// String returns the name used for t in the wire format. It returns the empty
+// string for unknown match types.
+func (t MatchType) String() string {
 	switch t {
 	case ContentMatchType:
-		return []byte(`"content"`), nil
+		return "content"
 	case RepoMatchType:
-		return []byte(`"repo"`), nil
+		return "repo"
 	case SymbolMatchType:
-		return []byte(`"symbol"`), nil
+		return "symbol"
 	case CommitMatchType:
-		return []byte(`"commit"`), nil
+		return "commit"
 	case PathMatchType:
-		return []byte(`"path"`), nil
+		return "path"
+	case RewriteMatchType:
+		return "rewrite"
 	default:
-		return nil, errors.Errorf("unknown MatchType: %d", t)
+		return ""
 	}
 }
 
@@ -179,6 +211,8 @@ func (t *MatchType) UnmarshalJSON(b []byte) error {
 		*t = CommitMatchType
 	} else if bytes.Equal(b, []byte(`"path"`)) {
 		*t = PathMatchType
+	} else if bytes.Equal(b, []byte(`"rewrite"`)) {
+		*t = RewriteMatchType
 	} else {
 		return errors.Errorf("unknown MatchType: %s", b)
 	}
@@ -0,0 +1,378 @@
+package http
+
+import (
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/cockroachdb/errors"
+)
+
+// ExportFormat is the encoding used by ExportWriter.
+type ExportFormat string
+
+const (
+	// ExportFormatJSONLines writes one JSON object per line.
+	ExportFormatJSONLines ExportFormat = "jsonl"
+	// ExportFormatCSV writes a header row followed by one record per line.
+	ExportFormatCSV ExportFormat = "csv"
+)
+
+// ParseExportFormat returns the ExportFormat named by s. The empty string
+// defaults to ExportFormatJSONLines.
+func ParseExportFormat(s string) (ExportFormat, error) {
+	switch ExportFormat(s) {
+	case "", ExportFormatJSONLines:
+		return ExportFormatJSONLines, nil
+	case ExportFormatCSV:
+		return ExportFormatCSV, nil
+	default:
+		return "", errors.Errorf("unsupported export format %q, expected %q or %q", s, ExportFormatJSONLines, ExportFormatCSV)
+	}
+}
+
+// ExportRow is a single flattened search result. A content match produces one
+// row per matching line and a symbol match one row per symbol, so that every
+// occurrence ends up on its own line in the export.
+type ExportRow struct {
+	// Seq is the 1-based position of the row in the response.
+	Seq int `json:"seq"`
+
+	// Cursor identifies the position of the row in the order of SortExportRows.
+	// Clients resume an interrupted export by requesting the rows after the
+	// Cursor of the last row they received. It is set by ExportWriter.
+	Cursor string `json:"cursor"`
+
+	Type       MatchType `json:"type"`
+	Repository string    `json:"repository"`
+	Path       string    `json:"path,omitempty"`
+	Branch     string    `json:"branch,omitempty"`
+	Version    string    `json:"version,omitempty"`
+
+	// LineNumber is 1-based, unlike EventLineMatch.LineNumber. It is 0 for
+	// rows which do not refer to a line.
+	LineNumber int32  `json:"lineNumber,omitempty"`
+	Line       string `json:"line,omitempty"`
+
+	SymbolName      string `json:"symbolName,omitempty"`
+	SymbolContainer string `json:"symbolContainer,omitempty"`
+	SymbolKind      string `json:"symbolKind,omitempty"`
+
+	Label  string `json:"label,omitempty"`
+	Detail string `json:"detail,omitempty"`
+	URL    string `json:"url,omitempty"`
+}
+
+// exportCSVHeader is the header row of a CSV export. It must be kept in sync
+// with ExportRow.csvRecord.
+var exportCSVHeader = []string{
+	"seq",
+	"cursor",
+	"type",
+	"repository",
+	"path",
+	"branch",
+	"version",
+	"lineNumber",
+	"line",
+	"symbolName",
+	"symbolContainer",
+	"symbolKind",
+	"label",
+	"detail",
+	"url",
+}
+
+func (r *ExportRow) csvRecord() []string {
+	lineNumber := ""
+	if r.LineNumber > 0 {
+		lineNumber = strconv.Itoa(int(r.LineNumber))
+	}
+	return []string{
+		strconv.Itoa(r.Seq),
+		r.Cursor,
+		r.Type.String(),
+		r.Repository,
+		r.Path,
+		r.Branch,
+		r.Version,
+		lineNumber,
+		r.Line,
+		r.SymbolName,
+		r.SymbolContainer,
+		r.SymbolKind,
+		r.Label,
+		r.Detail,
+		r.URL,
+	}
+}
+
+// exportKey are the fields of an ExportRow that determine its position in an
+// export. Repository, Path and LineNumber come first so that rows are grouped
+// by file in line order.
+type exportKey struct {
+	Repository      string `json:"r"`
+	Path            string `json:"p,omitempty"`
+	LineNumber      int32  `json:"l,omitempty"`
+	Type            int    `json:"t"`
+	Version         string `json:"v,omitempty"`
+	SymbolName      string `json:"s,omitempty"`
+	SymbolContainer string `json:"c,omitempty"`
+	Label           string `json:"n,omitempty"`
+	URL             string `json:"u,omitempty"`
+}
+
+func (r *ExportRow) key() exportKey {
+	return exportKey{
+		Repository:      r.Repository,
+		Path:            r.Path,
+		LineNumber:      r.LineNumber,
+		Type:            int(r.Type),
+		Version:         r.Version,
+		SymbolName:      r.SymbolName,
+		SymbolContainer: r.SymbolContainer,
+		Label:           r.Label,
+		URL:             r.URL,
+	}
+}
+
+func (k exportKey) less(o exportKey) bool {
+	switch {
+	case k.Repository != o.Repository:
+		return k.Repository < o.Repository
+	case k.Path != o.Path:
+		return k.Path < o.Path
+	case k.LineNumber != o.LineNumber:
+		return k.LineNumber < o.LineNumber
+	case k.Type != o.Type:
+		return k.Type < o.Type
+	case k.Version != o.Version:
+		return k.Version < o.Version
+	case k.SymbolName != o.SymbolName:
+		return k.SymbolName < o.SymbolName
+	case k.SymbolContainer != o.SymbolContainer:
+		return k.SymbolContainer < o.SymbolContainer
+	case k.Label != o.Label:
+		return k.Label < o.Label
+	default:
+		return k.URL < o.URL
+	}
+}
+
+// SortExportRows sorts rows by repository, path and line number. Unlike the
+// order in which search backends find results, this order is the same every
+// time a search is run, which makes cursors stable.
+func SortExportRows(rows []ExportRow) {
+	sort.SliceStable(rows, func(i, j int) bool {
+		return rows[i].key().less(rows[j].key())
+	})
+}
+
+// EncodeCursor returns the cursor of r, which is written as its Cursor field.
+func (r *ExportRow) EncodeCursor() string {
+	b, _ := json.Marshal(r.key())
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// ExportCursor is a position in the order of SortExportRows.
+type ExportCursor struct {
+	key exportKey
+}
+
+// DecodeExportCursor parses the Cursor of an ExportRow.
+func DecodeExportCursor(s string) (*ExportCursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, errors.Errorf("invalid export cursor %q", s)
+	}
+	var c ExportCursor
+	if err := json.Unmarshal(b, &c.key); err != nil {
+		return nil, errors.Errorf("invalid export cursor %q", s)
+	}
+	return &c, nil
+}
+
+// Before reports whether row comes after the cursor, i.e. whether it still
+// needs to be sent when resuming from the cursor.
+func (c *ExportCursor) Before(row *ExportRow) bool {
+	return c.key.less(row.key())
+}
+
+// ExportRows flattens match into the rows written by ExportWriter. Seq is
+// left unset and is assigned by the caller.
+func ExportRows(match EventMatch) []ExportRow {
+	firstBranch := func(branches []string) string {
+		if len(branches) == 0 {
+			return ""
+		}
+		return branches[0]
+	}
+
+	switch m := match.(type) {
+	case *EventContentMatch:
+		rows := make([]ExportRow, 0, len(m.LineMatches))
+		for _, lm := range m.LineMatches {
+			rows = append(rows, ExportRow{
+				Type:       m.Type,
+				Repository: m.Repository,
+				Path:       m.Path,
+				Branch:     firstBranch(m.Branches),
+				Version:    m.Version,
+				LineNumber: lm.LineNumber + 1,
+				Line:       lm.Line,
+			})
+		}
+		return rows
+
+	case *EventSymbolMatch:
+		rows := make([]ExportRow, 0, len(m.Symbols))
+		for _, sym := range m.Symbols {
+			rows = append(rows, ExportRow{
+				Type:            m.Type,
+				Repository:      m.Repository,
+				Path:            m.Path,
+				Branch:          firstBranch(m.Branches),
+				Version:         m.Version,
+				SymbolName:      sym.Name,
+				SymbolContainer: sym.ContainerName,
+				SymbolKind:      sym.Kind,
+				URL:             sym.URL,
+			})
+		}
+		return rows
+
+	case *EventPathMatch:
+		return []ExportRow{{
+			Type:       m.Type,
+			Repository: m.Repository,
+			Path:       m.Path,
+			Branch:     firstBranch(m.Branches),
+			Version:    m.Version,
+		}}
+
+	case *EventRewriteMatch:
+		return []ExportRow{{
+			Type:       m.Type,
+			Repository: m.Repository,
+			Path:       m.Path,
+			Branch:     firstBranch(m.Branches),
+			Version:    m.Version,
+			Detail:     m.Diff,
+		}}
+
+	case *EventRepoMatch:
+		return []ExportRow{{
+			Type:       m.Type,
+			Repository: m.Repository,
+			Branch:     firstBranch(m.Branches),
+			Detail:     m.Description,
+		}}
+
+	case *EventCommitMatch:
+		return []ExportRow{{
+			Type:       m.Type,
+			Repository: m.Repository,
+			Label:      m.Label,
+			Detail:     m.Detail,
+			URL:        m.URL,
+		}}
+
+	default:
+		return nil
+	}
+}
+
+// ExportErrorTrailer is the HTTP trailer set when an export ends because the
+// search failed. Since the rows have already been sent by then, the status
+// code cannot reflect the failure.
+const ExportErrorTrailer = "X-Sourcegraph-Export-Error"
+
+// ExportWriter writes ExportRows to an HTTP response as either JSON lines or
+// CSV.
+type ExportWriter struct {
+	w      io.Writer
+	flush  func()
+	header http.Header
+
+	csv *csv.Writer
+}
+
+// NewExportWriter sets the response headers for format and returns a writer
+// for it. For CSV the header row is written immediately.
+func NewExportWriter(w http.ResponseWriter, format ExportFormat) (*ExportWriter, error) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		return nil, errors.New("http flushing not supported")
+	}
+
+	filename := "search-results." + string(format)
+	switch format {
+	case ExportFormatJSONLines:
+		w.Header().Set("Content-Type", "application/x-ndjson")
+	case ExportFormatCSV:
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	default:
+		return nil, errors.Errorf("unsupported export format %q", format)
+	}
+	w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Transfer-Encoding", "chunked")
+
+	// See NewWriter.
+	w.Header().Set("X-Accel-Buffering", "no")
+
+	w.Header().Set("Trailer", ExportErrorTrailer)
+
+	e := &ExportWriter{
+		w:      w,
+		flush:  flusher.Flush,
+		header: w.Header(),
+	}
+
+	if format == ExportFormatCSV {
+		e.csv = csv.NewWriter(w)
+		if err := e.csv.Write(exportCSVHeader); err != nil {
+			return nil, err
+		}
+	}
+
+	return e, nil
+}
+
+// Write writes row with its Cursor set. Rows are buffered until the next call
+// to Flush.
+func (e *ExportWriter) Write(row ExportRow) error {
+	row.Cursor = row.EncodeCursor()
+	if e.csv != nil {
+		return e.csv.Write(row.csvRecord())
+	}
+
+	b, err := json.Marshal(row)
+	if err != nil {
+		return err
+	}
+	_, err = e.w.Write(append(b, '\n'))
+	return err
+}
+
+// Flush sends everything written so far to the client.
+func (e *ExportWriter) Flush() error {
+	if e.csv != nil {
+		e.csv.Flush()
+		if err := e.csv.Error(); err != nil {
+			return err
+		}
+	}
+	e.flush()
+	return nil
+}
+
+// Error reports err to the client in the ExportErrorTrailer trailer. It must
+// be called after the last call to Flush.
+func (e *ExportWriter) Error(err error) {
+	e.header.Set(ExportErrorTrailer, err.Error())
+}
@@ -0,0 +1,188 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestExportRows(t *testing.T) {
+	cases := []struct {
+		name  string
+		match EventMatch
+		want  []ExportRow
+	}{{
+		name: "content",
+		match: &EventContentMatch{
+			Type:       ContentMatchType,
+			Path:       "a.go",
+			Repository: "repo",
+			Branches:   []string{"main"},
+			Version:    "deadbeef",
+			LineMatches: []EventLineMatch{
+				{Line: "foo()", LineNumber: 0},
+				{Line: "bar()", LineNumber: 9},
+			},
+		},
+		want: []ExportRow{
+			{Type: ContentMatchType, Repository: "repo", Path: "a.go", Branch: "main", Version: "deadbeef", LineNumber: 1, Line: "foo()"},
+			{Type: ContentMatchType, Repository: "repo", Path: "a.go", Branch: "main", Version: "deadbeef", LineNumber: 10, Line: "bar()"},
+		},
+	}, {
+		name: "symbol",
+		match: &EventSymbolMatch{
+			Type:       SymbolMatchType,
+			Path:       "a.go",
+			Repository: "repo",
+			Symbols: []Symbol{{
+				URL:           "/repo/-/blob/a.go#L1",
+				Name:          "Foo",
+				ContainerName: "pkg",
+				Kind:          "FUNCTION",
+			}},
+		},
+		want: []ExportRow{
+			{Type: SymbolMatchType, Repository: "repo", Path: "a.go", SymbolName: "Foo", SymbolContainer: "pkg", SymbolKind: "FUNCTION", URL: "/repo/-/blob/a.go#L1"},
+		},
+	}, {
+		name: "rewrite",
+		match: &EventRewriteMatch{
+			Type:       RewriteMatchType,
+			Path:       "a.go",
+			Repository: "repo",
+			Version:    "deadbeef",
+			Diff:       "--- a.go\n+++ a.go\n",
+		},
+		want: []ExportRow{
+			{Type: RewriteMatchType, Repository: "repo", Path: "a.go", Version: "deadbeef", Detail: "--- a.go\n+++ a.go\n"},
+		},
+	}, {
+		name: "commit",
+		match: &EventCommitMatch{
+			Type:       CommitMatchType,
+			Label:      "fix foo",
+			URL:        "/repo/-/commit/deadbeef",
+			Detail:     "1 day ago",
+			Repository: "repo",
+			Content:    "ignored",
+		},
+		want: []ExportRow{
+			{Type: CommitMatchType, Repository: "repo", Label: "fix foo", Detail: "1 day ago", URL: "/repo/-/commit/deadbeef"},
+		},
+	}}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if diff := cmp.Diff(c.want, ExportRows(c.match)); diff != "" {
+				t.Errorf("unexpected rows (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestExportWriter(t *testing.T) {
+	row := ExportRow{
+		Seq:        1,
+		Type:       ContentMatchType,
+		Repository: "repo",
+		Path:       "a.go",
+		LineNumber: 3,
+		Line:       `say("hi, there")`,
+	}
+
+	cases := []struct {
+		format ExportFormat
+		want   string
+	}{{
+		format: ExportFormatJSONLines,
+		want: fmt.Sprintf(`{"seq":1,"cursor":%q,"type":"content","repository":"repo","path":"a.go","lineNumber":3,"line":"say(\"hi, there\")"}
+`, row.EncodeCursor()),
+	}, {
+		format: ExportFormatCSV,
+		want: fmt.Sprintf(`seq,cursor,type,repository,path,branch,version,lineNumber,line,symbolName,symbolContainer,symbolKind,label,detail,url
+1,%s,content,repo,a.go,,,3,"say(""hi, there"")",,,,,,
+`, row.EncodeCursor()),
+	}}
+
+	for _, c := range cases {
+		t.Run(string(c.format), func(t *testing.T) {
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				ew, err := NewExportWriter(w, c.format)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				if err := ew.Write(row); err != nil {
+					t.Error(err)
+				}
+				if err := ew.Flush(); err != nil {
+					t.Error(err)
+				}
+				ew.Error(errors.New("boom"))
+			}))
+			defer ts.Close()
+
+			res, err := http.Get(ts.URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, err := io.ReadAll(res.Body)
+			res.Body.Close()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if diff := cmp.Diff(c.want, string(b)); diff != "" {
+				t.Errorf("unexpected body (-want +got):\n%s", diff)
+			}
+			if got := res.Trailer.Get(ExportErrorTrailer); got != "boom" {
+				t.Errorf("got error trailer %q, want %q", got, "boom")
+			}
+		})
+	}
+}
+
+func TestExportCursor(t *testing.T) {
+	rows := []ExportRow{
+		{Type: ContentMatchType, Repository: "b", Path: "a.go", LineNumber: 1},
+		{Type: ContentMatchType, Repository: "a", Path: "b.go", LineNumber: 10},
+		{Type: RepoMatchType, Repository: "b"},
+		{Type: ContentMatchType, Repository: "a", Path: "b.go", LineNumber: 2},
+		{Type: PathMatchType, Repository: "a", Path: "a.go"},
+	}
+	SortExportRows(rows)
+
+	want := []ExportRow{
+		{Type: PathMatchType, Repository: "a", Path: "a.go"},
+		{Type: ContentMatchType, Repository: "a", Path: "b.go", LineNumber: 2},
+		{Type: ContentMatchType, Repository: "a", Path: "b.go", LineNumber: 10},
+		{Type: RepoMatchType, Repository: "b"},
+		{Type: ContentMatchType, Repository: "b", Path: "a.go", LineNumber: 1},
+	}
+	if diff := cmp.Diff(want, rows); diff != "" {
+		t.Fatalf("unexpected order (-want +got):\n%s", diff)
+	}
+
+	for i := range rows {
+		c, err := DecodeExportCursor(rows[i].EncodeCursor())
+		if err != nil {
+			t.Fatal(err)
+		}
+		for j := range rows {
+			if got, want := c.Before(&rows[j]), j > i; got != want {
+				t.Errorf("cursor of row %d: Before(row %d) = %t, want %t", i, j, got, want)
+			}
+		}
+	}
+
+	for _, s := range []string{"x", "-1", "e30x"} {
+		if _, err := DecodeExportCursor(s); err == nil {
+			t.Errorf("expected error for cursor %q", s)
+		}
+	}
+}
@@ -6,9 +6,11 @@ import (
 	"regexp"
 	"regexp/syntax"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/cockroachdb/errors"
+	"github.com/go-enry/go-enry/v2"
 	"github.com/google/zoekt"
 	zoektquery "github.com/google/zoekt/query"
 	"github.com/neelance/parallel"
@@ -20,6 +22,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/goroutine"
 	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/filter"
 	searchrepos "github.com/sourcegraph/sourcegraph/internal/search/repos"
 	"github.com/sourcegraph/sourcegraph/internal/search/result"
 	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
@@ -139,14 +142,25 @@ func searchInRepo(ctx context.Context, repoRevs *search.RepositoryRevisions, pat
 	}
 	span.SetTag("commit", string(commitID))
 
+	query, isExact := patternInfo.Pattern, false
+	if name, ok := exactSymbolName(patternInfo); ok {
+		query, isExact = name, true
+	}
+
 	symbols, err := backend.Symbols.ListTags(ctx, search.SymbolsParameters{
 		Repo:            repoRevs.Repo.Name,
 		CommitID:        commitID,
-		Query:           patternInfo.Pattern,
+		Query:           query,
+		IsExact:         isExact,
 		IsCaseSensitive: patternInfo.IsCaseSensitive,
 		IsRegExp:        patternInfo.IsRegExp,
 		IncludePatterns: patternInfo.IncludePatterns,
 		ExcludePattern:  patternInfo.ExcludePattern,
+		// Filter by kind, parent and language in the symbols service so
+		// that other symbols do not count towards the limit.
+		Kinds:         selectSymbolKinds(patternInfo.Select),
+		ParentPattern: patternInfo.SymbolParent,
+		Languages:     ctagsLanguages(patternInfo.Languages),
 		// Ask for limit + 1 so we can detect whether there are more results than the limit.
 		First: limit + 1,
 	})
@@ -188,6 +202,73 @@ func searchInRepo(ctx context.Context, repoRevs *search.RepositoryRevisions, pat
 	return matches, err
 }
 
+// selectSymbolKinds returns the ctags kinds selected by select:symbol.<kind>,
+// or nil if the selector does not restrict the kind of symbols.
+func selectSymbolKinds(selectPath filter.SelectPath) []string {
+	if selectPath.Root() != filter.Symbol || len(selectPath) < 2 {
+		return nil
+	}
+	return result.SelectSymbolKinds(selectPath[1])
+}
+
+// exactSymbolName returns the name in a pattern of the form ^name$, which
+// the symbols service can look up by equality instead of as a regexp.
+func exactSymbolName(patternInfo *search.TextPatternInfo) (string, bool) {
+	if !patternInfo.IsRegExp {
+		return "", false
+	}
+	re, err := syntax.Parse(patternInfo.Pattern, syntax.Perl)
+	if err != nil {
+		return "", false
+	}
+	if re.Op != syntax.OpConcat || len(re.Sub) != 3 {
+		return "", false
+	}
+	begin, lit, end := re.Sub[0], re.Sub[1], re.Sub[2]
+	if begin.Op != syntax.OpBeginText || lit.Op != syntax.OpLiteral || end.Op != syntax.OpEndText {
+		return "", false
+	}
+	if lit.Flags&syntax.FoldCase != 0 {
+		if patternInfo.IsCaseSensitive {
+			// (?i) in a case sensitive search.
+			return "", false
+		}
+		// The parser stores case folded literals in upper case.
+		return strings.ToLower(string(lit.Rune)), true
+	}
+	return string(lit.Rune), true
+}
+
+// ctagsLanguageNames maps the names of languages, as returned by
+// enry.GetLanguageByAlias for lang: values, to the names ctags reports when
+// they differ. The symbols service compares languages case insensitively.
+var ctagsLanguageNames = map[string]string{
+	"Shell":           "Sh",
+	"Objective-C":     "ObjectiveC",
+	"Emacs Lisp":      "EmacsLisp",
+	"Protocol Buffer": "Protobuf",
+	"Makefile":        "Make",
+	"Vim script":      "Vim",
+}
+
+// ctagsLanguages converts lang: values to the language names reported by ctags.
+func ctagsLanguages(langs []string) []string {
+	if len(langs) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(langs))
+	for _, lang := range langs {
+		if name, ok := enry.GetLanguageByAlias(lang); ok {
+			lang = name
+		}
+		if name, ok := ctagsLanguageNames[lang]; ok {
+			lang = name
+		}
+		names = append(names, lang)
+	}
+	return names
+}
+
 // indexedSymbols checks to see if Zoekt has indexed symbols information for a
 // repository at a specific commit. If it has it returns the branch name (for
 // use when querying zoekt). Otherwise an empty string is returned.
@@ -0,0 +1,44 @@
+package symbol
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/internal/search"
+)
+
+func TestExactSymbolName(t *testing.T) {
+	cases := []struct {
+		pattern         string
+		isRegExp        bool
+		isCaseSensitive bool
+		want            string
+		wantOK          bool
+	}{
+		{pattern: "^Store$", isRegExp: true, want: "Store", wantOK: true},
+		{pattern: `^foo\.bar$`, isRegExp: true, want: "foo.bar", wantOK: true},
+		{pattern: "(?i)^store$", isRegExp: true, want: "store", wantOK: true},
+		{pattern: "(?i)^store$", isRegExp: true, isCaseSensitive: true},
+		{pattern: "^Store", isRegExp: true},
+		{pattern: "^St.re$", isRegExp: true},
+		{pattern: "^Store$"},
+	}
+	for _, c := range cases {
+		got, ok := exactSymbolName(&search.TextPatternInfo{
+			Pattern:         c.pattern,
+			IsRegExp:        c.isRegExp,
+			IsCaseSensitive: c.isCaseSensitive,
+		})
+		if got != c.want || ok != c.wantOK {
+			t.Errorf("exactSymbolName(%q) = %q, %t, want %q, %t", c.pattern, got, ok, c.want, c.wantOK)
+		}
+	}
+}
+
+func TestCtagsLanguages(t *testing.T) {
+	got := ctagsLanguages([]string{"go", "shell", "objective-c", "unknown"})
+	want := []string{"Go", "Sh", "ObjectiveC", "unknown"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
@@ -84,6 +84,25 @@ type SymbolsParameters struct {
 	// need to match to get included in the result
 	ExcludePattern string
 
+	// IsExact if true will only match symbols whose name is equal to Query,
+	// instead of treating Query as a pattern.
+	IsExact bool
+
+	// Kinds is an optional list of symbol kinds as reported by ctags (e.g.
+	// "function", "interface"). When non-empty, a symbol's kind must be one of
+	// them. Kinds are matched case insensitively.
+	Kinds []string
+
+	// ParentPattern is an optional regex that the name of a symbol's parent
+	// (the symbol containing it, e.g. a class) needs to match to get included in
+	// the result.
+	ParentPattern string
+
+	// Languages is an optional list of languages as reported by ctags (e.g.
+	// "Go"). When non-empty, a symbol's language must be one of them. Languages
+	// are matched case insensitively.
+	Languages []string
+
 	// First indicates that only the first n symbols should be returned.
 	First int
 }
@@ -180,6 +199,7 @@ type TextPatternInfo struct {
 	IsRegExp        bool
 	IsStructuralPat bool
 	CombyRule       string
+	CombyRewrite    string `json:",omitempty"`
 	IsWordMatch     bool
 	IsCaseSensitive bool
 	FileMatchLimit  int32
@@ -200,6 +220,24 @@ type TextPatternInfo struct {
 	PatternMatchesPath    bool
 
 	Languages []string
+
+	// BlameAuthor, BlameBefore and BlameAfter restrict matched lines to those
+	// last changed by an author matching the regexp BlameAuthor, in the given
+	// time range. The dates are parsed by git, like the dates of before: and
+	// after:. Unset values are not applied.
+	BlameAuthor string `json:",omitempty"`
+	BlameBefore string `json:",omitempty"`
+	BlameAfter  string `json:",omitempty"`
+
+	// SymbolParent is a regexp that the name of the parent of a matched
+	// symbol (e.g. its class) must match. It only applies to symbol search.
+	SymbolParent string `json:",omitempty"`
+}
+
+// HasBlameFilters returns true if matched lines must be filtered by the
+// output of git blame.
+func (p *TextPatternInfo) HasBlameFilters() bool {
+	return p.BlameAuthor != "" || p.BlameBefore != "" || p.BlameAfter != ""
 }
 
 func (p *TextPatternInfo) String() string {
@@ -213,6 +251,9 @@ func (p *TextPatternInfo) String() string {
 		} else {
 			args = append(args, "comby")
 		}
+		if p.CombyRewrite != "" {
+			args = append(args, fmt.Sprintf("rewrite:%q", p.CombyRewrite))
+		}
 	}
 	if p.IsWordMatch {
 		args = append(args, "word")
@@ -232,6 +273,18 @@ func (p *TextPatternInfo) String() string {
 	for _, lang := range p.Languages {
 		args = append(args, fmt.Sprintf("lang:%s", lang))
 	}
+	if p.BlameAuthor != "" {
+		args = append(args, fmt.Sprintf("blame.author:%q", p.BlameAuthor))
+	}
+	if p.BlameBefore != "" {
+		args = append(args, fmt.Sprintf("blame.before:%q", p.BlameBefore))
+	}
+	if p.BlameAfter != "" {
+		args = append(args, fmt.Sprintf("blame.after:%q", p.BlameAfter))
+	}
+	if p.SymbolParent != "" {
+		args = append(args, fmt.Sprintf("symbol.parent:%q", p.SymbolParent))
+	}
 
 	for _, inc := range p.FilePatternsReposMustInclude {
 		args = append(args, fmt.Sprintf("repositoryPathPattern:%s", inc))
@@ -255,23 +308,24 @@ func (p *TextPatternInfo) String() string {
 }
 
 type RepoOptions struct {
-	RepoFilters        []string
-	MinusRepoFilters   []string
-	RepoGroupFilters   []string
-	SearchContextSpec  string
-	VersionContextName string
-	UserSettings       *schema.Settings
-	NoForks            bool
-	OnlyForks          bool
-	NoArchived         bool
-	OnlyArchived       bool
-	CommitAfter        string
-	OnlyPrivate        bool
-	OnlyPublic         bool
-	Ranked             bool // Return results ordered by rank
-	Limit              int
-	CacheLookup        bool
-	Query              query.Q
+	RepoFilters         []string
+	MinusRepoFilters    []string
+	RepoGroupFilters    []string
+	SearchContextSpec   string
+	VersionContextName  string
+	UserSettings        *schema.Settings
+	NoForks             bool
+	OnlyForks           bool
+	NoArchived          bool
+	OnlyArchived        bool
+	CommitAfter         string
+	DescriptionPatterns []string
+	OnlyPrivate         bool
+	OnlyPublic          bool
+	Ranked              bool // Return results ordered by rank
+	Limit               int
+	CacheLookup         bool
+	Query               query.Q
 }
 
 func (op *RepoOptions) String() string {
@@ -298,6 +352,9 @@ func (op *RepoOptions) String() string {
 	if op.CommitAfter != "" {
 		_, _ = fmt.Fprintf(&b, " CommitAfter=%q", op.CommitAfter)
 	}
+	if len(op.DescriptionPatterns) > 0 {
+		_, _ = fmt.Fprintf(&b, " DescriptionPatterns=%q", op.DescriptionPatterns)
+	}
 
 	if op.NoForks {
 		b.WriteString(" NoForks")
@@ -33,7 +33,7 @@ import (
 )
 
 func TestSearchFilesInRepos(t *testing.T) {
-	mockSearchFilesInRepo = func(ctx context.Context, repo types.RepoName, gitserverRepo api.RepoName, rev string, info *search.TextPatternInfo, fetchTimeout time.Duration) (matches []result.Match, limitHit bool, err error) {
+	mockSearchFilesInRepo = func(ctx context.Context, repo types.RepoName, gitserverRepo api.RepoName, rev string, info *search.TextPatternInfo, fetchTimeout time.Duration) (matches []result.Match, limitHit, blameTruncated bool, err error) {
 		repoName := repo.Name
 		switch repoName {
 		case "foo/one":
@@ -43,7 +43,7 @@ func TestSearchFilesInRepos(t *testing.T) {
 					InputRev: &rev,
 					Path:     "main.go",
 				},
-			}}, false, nil
+			}}, false, false, nil
 		case "foo/two":
 			return []result.Match{&result.FileMatch{
 				File: result.File{
@@ -51,24 +51,24 @@ func TestSearchFilesInRepos(t *testing.T) {
 					InputRev: &rev,
 					Path:     "main.go",
 				},
-			}}, false, nil
+			}}, false, false, nil
 		case "foo/empty":
-			return nil, false, nil
+			return nil, false, false, nil
 		case "foo/cloning":
-			return nil, false, &vcs.RepoNotExistError{Repo: repoName, CloneInProgress: true}
+			return nil, false, false, &vcs.RepoNotExistError{Repo: repoName, CloneInProgress: true}
 		case "foo/missing":
-			return nil, false, &vcs.RepoNotExistError{Repo: repoName}
+			return nil, false, false, &vcs.RepoNotExistError{Repo: repoName}
 		case "foo/missing-database":
-			return nil, false, &errcode.Mock{Message: "repo not found: foo/missing-database", IsNotFound: true}
+			return nil, false, false, &errcode.Mock{Message: "repo not found: foo/missing-database", IsNotFound: true}
 		case "foo/timedout":
-			return nil, false, context.DeadlineExceeded
+			return nil, false, false, context.DeadlineExceeded
 		case "foo/no-rev":
 			// TODO we do not specify a rev when searching "foo/no-rev", so it
 			// is treated as an empty repository. We need to test the fatal
 			// case of trying to search a revision which doesn't exist.
-			return nil, false, &gitserver.RevisionNotFoundError{Repo: repoName, Spec: "missing"}
+			return nil, false, false, &gitserver.RevisionNotFoundError{Repo: repoName, Spec: "missing"}
 		default:
-			return nil, false, errors.New("Unexpected repo")
+			return nil, false, false, errors.New("Unexpected repo")
 		}
 	}
 	defer func() { mockSearchFilesInRepo = nil }()
@@ -128,7 +128,7 @@ func TestSearchFilesInRepos(t *testing.T) {
 }
 
 func TestSearchFilesInReposStream(t *testing.T) {
-	mockSearchFilesInRepo = func(ctx context.Context, repo types.RepoName, gitserverRepo api.RepoName, rev string, info *search.TextPatternInfo, fetchTimeout time.Duration) (matches []result.Match, limitHit bool, err error) {
+	mockSearchFilesInRepo = func(ctx context.Context, repo types.RepoName, gitserverRepo api.RepoName, rev string, info *search.TextPatternInfo, fetchTimeout time.Duration) (matches []result.Match, limitHit, blameTruncated bool, err error) {
 		repoName := repo.Name
 		switch repoName {
 		case "foo/one":
@@ -138,7 +138,7 @@ func TestSearchFilesInReposStream(t *testing.T) {
 					InputRev: &rev,
 					Path:     "main.go",
 				},
-			}}, false, nil
+			}}, false, false, nil
 		case "foo/two":
 			return []result.Match{&result.FileMatch{
 				File: result.File{
@@ -146,7 +146,7 @@ func TestSearchFilesInReposStream(t *testing.T) {
 					InputRev: &rev,
 					Path:     "main.go",
 				},
-			}}, false, nil
+			}}, false, false, nil
 		case "foo/three":
 			return []result.Match{&result.FileMatch{
 				File: result.File{
@@ -154,9 +154,9 @@ func TestSearchFilesInReposStream(t *testing.T) {
 					InputRev: &rev,
 					Path:     "main.go",
 				},
-			}}, false, nil
+			}}, false, false, nil
 		default:
-			return nil, false, errors.New("Unexpected repo")
+			return nil, false, false, errors.New("Unexpected repo")
 		}
 	}
 	defer func() { mockSearchFilesInRepo = nil }()
@@ -204,7 +204,7 @@ func assertReposStatus(t *testing.T, repoNames map[api.RepoID]string, got search
 }
 
 func TestSearchFilesInRepos_multipleRevsPerRepo(t *testing.T) {
-	mockSearchFilesInRepo = func(ctx context.Context, repo types.RepoName, gitserverRepo api.RepoName, rev string, info *search.TextPatternInfo, fetchTimeout time.Duration) (matches []result.Match, limitHit bool, err error) {
+	mockSearchFilesInRepo = func(ctx context.Context, repo types.RepoName, gitserverRepo api.RepoName, rev string, info *search.TextPatternInfo, fetchTimeout time.Duration) (matches []result.Match, limitHit, blameTruncated bool, err error) {
 		repoName := repo.Name
 		switch repoName {
 		case "foo":
@@ -214,7 +214,7 @@ func TestSearchFilesInRepos_multipleRevsPerRepo(t *testing.T) {
 					CommitID: api.CommitID(rev),
 					Path:     "main.go",
 				},
-			}}, false, nil
+			}}, false, false, nil
 		default:
 			panic("unexpected repo")
 		}
@@ -270,15 +270,15 @@ func TestSearchFilesInRepos_multipleRevsPerRepo(t *testing.T) {
 }
 
 func TestRepoShouldBeSearched(t *testing.T) {
-	searcher.MockSearch = func(ctx context.Context, repo api.RepoName, commit api.CommitID, p *search.TextPatternInfo, fetchTimeout time.Duration) (matches []*protocol.FileMatch, limitHit bool, err error) {
+	searcher.MockSearch = func(ctx context.Context, repo api.RepoName, commit api.CommitID, p *search.TextPatternInfo, fetchTimeout time.Duration) (matches []*protocol.FileMatch, limitHit, blameTruncated bool, err error) {
 		repoName := repo
 		switch repoName {
 		case "foo/one":
-			return []*protocol.FileMatch{{Path: "main.go"}}, false, nil
+			return []*protocol.FileMatch{{Path: "main.go"}}, false, false, nil
 		case "foo/no-filematch":
-			return []*protocol.FileMatch{}, false, nil
+			return []*protocol.FileMatch{}, false, false, nil
 		default:
-			return nil, false, errors.New("Unexpected repo")
+			return nil, false, false, errors.New("Unexpected repo")
 		}
 	}
 	defer func() { searcher.MockSearch = nil }()
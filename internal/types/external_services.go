@@ -10,6 +10,12 @@ type BitbucketServerConnection struct {
 	*schema.BitbucketServerConnection
 }
 
+type BitbucketCloudConnection struct {
+	// The unique resource identifier of the external service.
+	URN string
+	*schema.BitbucketCloudConnection
+}
+
 type GitHubConnection struct {
 	// The unique resource identifier of the external service.
 	URN string
@@ -27,3 +33,15 @@ type PerforceConnection struct {
 	URN string
 	*schema.PerforceConnection
 }
+
+type GerritConnection struct {
+	// The unique resource identifier of the external service.
+	URN string
+	*schema.GerritConnection
+}
+
+type GiteaConnection struct {
+	// The unique resource identifier of the external service.
+	URN string
+	*schema.GiteaConnection
+}
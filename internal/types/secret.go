@@ -57,6 +57,10 @@ func (e *ExternalService) RedactConfigSecrets() error {
 		newCfg, err = redactField(e.Config, fields...)
 	case *schema.BitbucketCloudConnection:
 		newCfg, err = redactField(e.Config, "appPassword")
+	case *schema.GiteaConnection:
+		newCfg, err = redactField(e.Config, "token")
+	case *schema.GerritConnection:
+		newCfg, err = redactField(e.Config, "password")
 	case *schema.AWSCodeCommitConnection:
 		newCfg, err = redactField(e.Config, "secretAccessKey")
 	case *schema.PhabricatorConnection:
@@ -70,6 +74,10 @@ func (e *ExternalService) RedactConfigSecrets() error {
 		newCfg, err = redactField(e.Config, "url")
 	case *schema.JVMPackagesConnection:
 		newCfg, err = e.Config, nil
+	case *schema.NpmPackagesConnection:
+		newCfg, err = redactField(e.Config, "credentials")
+	case *schema.GoModulesConnection:
+		newCfg, err = e.Config, nil
 	default:
 		// return an error here, it's safer to fail than to incorrectly return unsafe data.
 		err = errors.Errorf("RedactExternalServiceConfig: kind %q not implemented", e.Kind)
@@ -134,6 +142,10 @@ func (e *ExternalService) UnredactConfig(old *ExternalService) error {
 		unredacted, err = unredactField(old.Config, e.Config, &cfg, fields...)
 	case *schema.BitbucketCloudConnection:
 		unredacted, err = unredactField(old.Config, e.Config, &cfg, jsonStringField{"appPassword", &cfg.AppPassword})
+	case *schema.GiteaConnection:
+		unredacted, err = unredactField(old.Config, e.Config, &cfg, jsonStringField{"token", &cfg.Token})
+	case *schema.GerritConnection:
+		unredacted, err = unredactField(old.Config, e.Config, &cfg, jsonStringField{"password", &cfg.Password})
 	case *schema.AWSCodeCommitConnection:
 		unredacted, err = unredactField(old.Config, e.Config, &cfg, jsonStringField{"secretAccessKey", &cfg.SecretAccessKey})
 	case *schema.PhabricatorConnection:
@@ -147,6 +159,10 @@ func (e *ExternalService) UnredactConfig(old *ExternalService) error {
 		unredacted, err = unredactField(old.Config, e.Config, &cfg, jsonStringField{"url", &cfg.Url})
 	case *schema.JVMPackagesConnection:
 		unredacted, err = e.Config, nil
+	case *schema.NpmPackagesConnection:
+		unredacted, err = unredactField(old.Config, e.Config, &cfg, jsonStringField{"credentials", &cfg.Credentials})
+	case *schema.GoModulesConnection:
+		unredacted, err = e.Config, nil
 	default:
 		// return an error here, it's safer to fail than to incorrectly return unsafe data.
 		err = errors.Errorf("UnRedactExternalServiceConfig: kind %q not implemented", e.Kind)
@@ -32,6 +32,15 @@ func TestRoundTripRedactExternalServiceConfig(t *testing.T) {
 		Token: someSecret,
 		Url:   "https://bitbucket.com",
 	}
+	giteaConfig := schema.GiteaConnection{
+		Token: someSecret,
+		Url:   "https://gitea.io",
+	}
+	gerritConfig := schema.GerritConnection{
+		Username: "admin",
+		Password: someSecret,
+		Url:      "https://gerrit.io",
+	}
 	awsCodeCommitConfig := schema.AWSCodeCommitConnection{
 		SecretAccessKey: someSecret,
 		Region:          "us-east-9000z",
@@ -47,6 +56,10 @@ func TestRoundTripRedactExternalServiceConfig(t *testing.T) {
 		P4Passwd: someSecret,
 		P4User:   "admin",
 	}
+	npmPackagesConfig := schema.NpmPackagesConnection{
+		Credentials: someSecret,
+		Registry:    "https://registry.npmjs.org",
+	}
 	otherConfig := schema.OtherExternalServiceConnection{
 		Url:                   someSecret,
 		RepositoryPathPattern: "foo",
@@ -88,6 +101,18 @@ func TestRoundTripRedactExternalServiceConfig(t *testing.T) {
 			editField:   &bitbucketServerConfigWithToken.Url,
 			secretField: &bitbucketServerConfigWithToken.Token,
 		},
+		{
+			kind:        extsvc.KindGitea,
+			config:      &giteaConfig,
+			editField:   &giteaConfig.Url,
+			secretField: &giteaConfig.Token,
+		},
+		{
+			kind:        extsvc.KindGerrit,
+			config:      &gerritConfig,
+			editField:   &gerritConfig.Url,
+			secretField: &gerritConfig.Password,
+		},
 		{
 			kind:        extsvc.KindAWSCodeCommit,
 			config:      &awsCodeCommitConfig,
@@ -111,6 +136,12 @@ func TestRoundTripRedactExternalServiceConfig(t *testing.T) {
 			editField:   &perforceConfig.P4User,
 			secretField: &perforceConfig.P4Passwd,
 		},
+		{
+			kind:        extsvc.KindNpmPackages,
+			config:      &npmPackagesConfig,
+			editField:   &npmPackagesConfig.Registry,
+			secretField: &npmPackagesConfig.Credentials,
+		},
 		{
 			kind:        extsvc.KindOther,
 			config:      &otherConfig,
@@ -1169,6 +1169,15 @@ type SearchContext struct {
 	NamespaceOrgID  int32 // if non-zero, the owner is this organization. NamespaceUserID/NamespaceOrgID are mutually exclusive.
 	UpdatedAt       time.Time
 
+	// Query, if non-empty, defines the repositories of the search context, e.g.
+	// "repo:^github\.com/acme/ lang:go". The repositories matching it are
+	// resolved periodically and cached as the repository revisions of the
+	// search context.
+	Query string
+	// QueryReposUpdatedAt is when the repositories matching Query were last
+	// resolved.
+	QueryReposUpdatedAt time.Time
+
 	// We cache namespace names to avoid separate database lookups when constructing the search context spec
 
 	// NamespaceUserName is the name of the user if NamespaceUserID is present.
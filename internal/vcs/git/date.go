@@ -0,0 +1,38 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/trace/ot"
+)
+
+// ParseDate parses date the way git parses the --since option of git log,
+// which also backs the before: and after: search filters. It accepts absolute
+// dates such as "2021-06-01" as well as relative dates such as "3 weeks ago".
+func ParseDate(ctx context.Context, repo api.RepoName, date string) (time.Time, error) {
+	span, ctx := ot.StartSpanFromContext(ctx, "Git: ParseDate")
+	span.SetTag("date", date)
+	defer span.Finish()
+	return parseDateCmd(ctx, gitserverCmdFunc(repo), date)
+}
+
+func parseDateCmd(ctx context.Context, command cmdFunc, date string) (time.Time, error) {
+	// git rev-parse translates --since=<date> into --max-age=<unix timestamp>.
+	cmd := command([]string{"rev-parse", "--since=" + date})
+	out, err := cmd.Output(ctx)
+	if err != nil {
+		return time.Time{}, errors.WithMessage(err, "git rev-parse --since")
+	}
+	out = bytes.TrimPrefix(bytes.TrimSpace(out), []byte("--max-age="))
+	seconds, err := strconv.ParseInt(string(out), 10, 64)
+	if err != nil {
+		return time.Time{}, errors.Errorf("invalid date %q", date)
+	}
+	return time.Unix(seconds, 0).UTC(), nil
+}
@@ -0,0 +1,29 @@
+package git
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestParseDate(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	repo := MakeGitRepository(t)
+
+	got, err := ParseDate(ctx, repo, "2021-06-01T12:00:00Z")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("got %s, want %s", got, want)
+	}
+
+	got, err = ParseDate(ctx, repo, "1 week ago")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Now().AddDate(0, 0, -7); got.Sub(want) > time.Minute || want.Sub(got) > time.Minute {
+		t.Errorf("got %s, want about %s", got, want)
+	}
+}
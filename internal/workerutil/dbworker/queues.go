@@ -0,0 +1,80 @@
+package dbworker
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store"
+)
+
+var (
+	queuesMu sync.RWMutex
+	queues   = map[string]registeredQueue{}
+)
+
+type registeredQueue struct {
+	store       store.Store
+	allowDelete bool
+}
+
+// RegisterQueue registers the store of a database-backed queue under the given name so that its
+// failed records can be inspected, requeued, and deleted by site admins. This function panics if
+// a queue with the same name is already registered.
+//
+// Deleting a failed record removes its row from the queue's table. Queues whose table doubles as
+// a domain table (such as lsif_indexes or changesets) must be registered with RegisterDomainQueue
+// instead.
+func RegisterQueue(name string, s store.Store) {
+	registerQueue(name, registeredQueue{store: s, allowDelete: true})
+}
+
+// RegisterDomainQueue registers the store of a database-backed queue whose table doubles as a
+// domain table. Its failed records can be inspected and requeued by site admins, but not deleted.
+// This function panics if a queue with the same name is already registered.
+func RegisterDomainQueue(name string, s store.Store) {
+	registerQueue(name, registeredQueue{store: s})
+}
+
+func registerQueue(name string, q registeredQueue) {
+	queuesMu.Lock()
+	defer queuesMu.Unlock()
+
+	if _, ok := queues[name]; ok {
+		panic(fmt.Sprintf("dbworker: queue %q registered twice", name))
+	}
+	queues[name] = q
+}
+
+// QueueNames returns the names of all registered queues in lexicographic order.
+func QueueNames() []string {
+	queuesMu.RLock()
+	defer queuesMu.RUnlock()
+
+	names := make([]string, 0, len(queues))
+	for name := range queues {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// QueueStore returns the store of the registered queue with the given name. This function returns
+// false if no such queue is registered.
+func QueueStore(name string) (store.Store, bool) {
+	queuesMu.RLock()
+	defer queuesMu.RUnlock()
+
+	q, ok := queues[name]
+	return q.store, ok
+}
+
+// QueueAllowsDelete returns true if the failed records of the registered queue with the given
+// name may be deleted, i.e. if it was registered with RegisterQueue.
+func QueueAllowsDelete(name string) bool {
+	queuesMu.RLock()
+	defer queuesMu.RUnlock()
+
+	return queues[name].allowDelete
+}
@@ -0,0 +1,34 @@
+package dbworker
+
+import (
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store/mocks"
+)
+
+func TestRegisterQueue(t *testing.T) {
+	queueStore := mocks.NewMockStore()
+	RegisterQueue("dbworker_test", queueStore)
+	RegisterDomainQueue("dbworker_test_domain", queueStore)
+
+	for name, allowDelete := range map[string]bool{
+		"dbworker_test":        true,
+		"dbworker_test_domain": false,
+		"dbworker_test_none":   false,
+	} {
+		if got := QueueAllowsDelete(name); got != allowDelete {
+			t.Errorf("unexpected QueueAllowsDelete(%q). want=%v have=%v", name, allowDelete, got)
+		}
+	}
+
+	if _, ok := QueueStore("dbworker_test_domain"); !ok {
+		t.Errorf("expected domain queue to be registered")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected registering a queue twice to panic")
+		}
+	}()
+	RegisterDomainQueue("dbworker_test", queueStore)
+}
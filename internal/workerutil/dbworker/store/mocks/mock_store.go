@@ -21,15 +21,24 @@ type MockStore struct {
 	// AddExecutionLogEntryFunc is an instance of a mock function object
 	// controlling the behavior of the method AddExecutionLogEntry.
 	AddExecutionLogEntryFunc *StoreAddExecutionLogEntryFunc
+	// DeleteFailedFunc is an instance of a mock function object controlling
+	// the behavior of the method DeleteFailed.
+	DeleteFailedFunc *StoreDeleteFailedFunc
 	// DequeueFunc is an instance of a mock function object controlling the
 	// behavior of the method Dequeue.
 	DequeueFunc *StoreDequeueFunc
+	// DequeueWithKeyFunc is an instance of a mock function object
+	// controlling the behavior of the method DequeueWithKey.
+	DequeueWithKeyFunc *StoreDequeueWithKeyFunc
 	// HandleFunc is an instance of a mock function object controlling the
 	// behavior of the method Handle.
 	HandleFunc *StoreHandleFunc
 	// HeartbeatFunc is an instance of a mock function object controlling
 	// the behavior of the method Heartbeat.
 	HeartbeatFunc *StoreHeartbeatFunc
+	// ListFailedFunc is an instance of a mock function object controlling
+	// the behavior of the method ListFailed.
+	ListFailedFunc *StoreListFailedFunc
 	// MarkCompleteFunc is an instance of a mock function object controlling
 	// the behavior of the method MarkComplete.
 	MarkCompleteFunc *StoreMarkCompleteFunc
@@ -45,6 +54,9 @@ type MockStore struct {
 	// RequeueFunc is an instance of a mock function object controlling the
 	// behavior of the method Requeue.
 	RequeueFunc *StoreRequeueFunc
+	// RequeueFailedFunc is an instance of a mock function object
+	// controlling the behavior of the method RequeueFailed.
+	RequeueFailedFunc *StoreRequeueFailedFunc
 	// ResetStalledFunc is an instance of a mock function object controlling
 	// the behavior of the method ResetStalled.
 	ResetStalledFunc *StoreResetStalledFunc
@@ -62,11 +74,21 @@ func NewMockStore() *MockStore {
 				return 0, nil
 			},
 		},
+		DeleteFailedFunc: &StoreDeleteFailedFunc{
+			defaultHook: func(context.Context, []int) (int, error) {
+				return 0, nil
+			},
+		},
 		DequeueFunc: &StoreDequeueFunc{
 			defaultHook: func(context.Context, string, []*sqlf.Query) (workerutil.Record, bool, error) {
 				return nil, false, nil
 			},
 		},
+		DequeueWithKeyFunc: &StoreDequeueWithKeyFunc{
+			defaultHook: func(context.Context, string, []*sqlf.Query, []string) (workerutil.Record, string, bool, error) {
+				return nil, "", false, nil
+			},
+		},
 		HandleFunc: &StoreHandleFunc{
 			defaultHook: func() *basestore.TransactableHandle {
 				return nil
@@ -77,6 +99,11 @@ func NewMockStore() *MockStore {
 				return nil, nil
 			},
 		},
+		ListFailedFunc: &StoreListFailedFunc{
+			defaultHook: func(context.Context, store.ListFailedOptions) ([]store.FailedRecord, int, error) {
+				return nil, 0, nil
+			},
+		},
 		MarkCompleteFunc: &StoreMarkCompleteFunc{
 			defaultHook: func(context.Context, int, store.MarkFinalOptions) (bool, error) {
 				return false, nil
@@ -102,6 +129,11 @@ func NewMockStore() *MockStore {
 				return nil
 			},
 		},
+		RequeueFailedFunc: &StoreRequeueFailedFunc{
+			defaultHook: func(context.Context, []int) (int, error) {
+				return 0, nil
+			},
+		},
 		ResetStalledFunc: &StoreResetStalledFunc{
 			defaultHook: func(context.Context) (map[int]time.Duration, map[int]time.Duration, error) {
 				return nil, nil, nil
@@ -122,15 +154,24 @@ func NewMockStoreFrom(i store.Store) *MockStore {
 		AddExecutionLogEntryFunc: &StoreAddExecutionLogEntryFunc{
 			defaultHook: i.AddExecutionLogEntry,
 		},
+		DeleteFailedFunc: &StoreDeleteFailedFunc{
+			defaultHook: i.DeleteFailed,
+		},
 		DequeueFunc: &StoreDequeueFunc{
 			defaultHook: i.Dequeue,
 		},
+		DequeueWithKeyFunc: &StoreDequeueWithKeyFunc{
+			defaultHook: i.DequeueWithKey,
+		},
 		HandleFunc: &StoreHandleFunc{
 			defaultHook: i.Handle,
 		},
 		HeartbeatFunc: &StoreHeartbeatFunc{
 			defaultHook: i.Heartbeat,
 		},
+		ListFailedFunc: &StoreListFailedFunc{
+			defaultHook: i.ListFailed,
+		},
 		MarkCompleteFunc: &StoreMarkCompleteFunc{
 			defaultHook: i.MarkComplete,
 		},
@@ -146,6 +187,9 @@ func NewMockStoreFrom(i store.Store) *MockStore {
 		RequeueFunc: &StoreRequeueFunc{
 			defaultHook: i.Requeue,
 		},
+		RequeueFailedFunc: &StoreRequeueFailedFunc{
+			defaultHook: i.RequeueFailed,
+		},
 		ResetStalledFunc: &StoreResetStalledFunc{
 			defaultHook: i.ResetStalled,
 		},
@@ -270,6 +314,114 @@ func (c StoreAddExecutionLogEntryFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
+// StoreDeleteFailedFunc describes the behavior when the DeleteFailed method
+// of the parent MockStore instance is invoked.
+type StoreDeleteFailedFunc struct {
+	defaultHook func(context.Context, []int) (int, error)
+	hooks       []func(context.Context, []int) (int, error)
+	history     []StoreDeleteFailedFuncCall
+	mutex       sync.Mutex
+}
+
+// DeleteFailed delegates to the next hook function in the queue and stores
+// the parameter and result values of this invocation.
+func (m *MockStore) DeleteFailed(v0 context.Context, v1 []int) (int, error) {
+	r0, r1 := m.DeleteFailedFunc.nextHook()(v0, v1)
+	m.DeleteFailedFunc.appendCall(StoreDeleteFailedFuncCall{v0, v1, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the DeleteFailed method
+// of the parent MockStore instance is invoked and the hook queue is empty.
+func (f *StoreDeleteFailedFunc) SetDefaultHook(hook func(context.Context, []int) (int, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// DeleteFailed method of the parent MockStore instance invokes the hook at
+// the front of the queue and discards it. After the queue is empty, the
+// default hook function is invoked for any future action.
+func (f *StoreDeleteFailedFunc) PushHook(hook func(context.Context, []int) (int, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *StoreDeleteFailedFunc) SetDefaultReturn(r0 int, r1 error) {
+	f.SetDefaultHook(func(context.Context, []int) (int, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *StoreDeleteFailedFunc) PushReturn(r0 int, r1 error) {
+	f.PushHook(func(context.Context, []int) (int, error) {
+		return r0, r1
+	})
+}
+
+func (f *StoreDeleteFailedFunc) nextHook() func(context.Context, []int) (int, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *StoreDeleteFailedFunc) appendCall(r0 StoreDeleteFailedFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of StoreDeleteFailedFuncCall objects
+// describing the invocations of this function.
+func (f *StoreDeleteFailedFunc) History() []StoreDeleteFailedFuncCall {
+	f.mutex.Lock()
+	history := make([]StoreDeleteFailedFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// StoreDeleteFailedFuncCall is an object that describes an invocation of
+// method DeleteFailed on an instance of MockStore.
+type StoreDeleteFailedFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 []int
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 int
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c StoreDeleteFailedFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c StoreDeleteFailedFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // StoreDequeueFunc describes the behavior when the Dequeue method of the
 // parent MockStore instance is invoked.
 type StoreDequeueFunc struct {
@@ -384,6 +536,127 @@ func (c StoreDequeueFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1, c.Result2}
 }
 
+// StoreDequeueWithKeyFunc describes the behavior when the DequeueWithKey
+// method of the parent MockStore instance is invoked.
+type StoreDequeueWithKeyFunc struct {
+	defaultHook func(context.Context, string, []*sqlf.Query, []string) (workerutil.Record, string, bool, error)
+	hooks       []func(context.Context, string, []*sqlf.Query, []string) (workerutil.Record, string, bool, error)
+	history     []StoreDequeueWithKeyFuncCall
+	mutex       sync.Mutex
+}
+
+// DequeueWithKey delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockStore) DequeueWithKey(v0 context.Context, v1 string, v2 []*sqlf.Query, v3 []string) (workerutil.Record, string, bool, error) {
+	r0, r1, r2, r3 := m.DequeueWithKeyFunc.nextHook()(v0, v1, v2, v3)
+	m.DequeueWithKeyFunc.appendCall(StoreDequeueWithKeyFuncCall{v0, v1, v2, v3, r0, r1, r2, r3})
+	return r0, r1, r2, r3
+}
+
+// SetDefaultHook sets function that is called when the DequeueWithKey
+// method of the parent MockStore instance is invoked and the hook queue is
+// empty.
+func (f *StoreDequeueWithKeyFunc) SetDefaultHook(hook func(context.Context, string, []*sqlf.Query, []string) (workerutil.Record, string, bool, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// DequeueWithKey method of the parent MockStore instance invokes the hook
+// at the front of the queue and discards it. After the queue is empty, the
+// default hook function is invoked for any future action.
+func (f *StoreDequeueWithKeyFunc) PushHook(hook func(context.Context, string, []*sqlf.Query, []string) (workerutil.Record, string, bool, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *StoreDequeueWithKeyFunc) SetDefaultReturn(r0 workerutil.Record, r1 string, r2 bool, r3 error) {
+	f.SetDefaultHook(func(context.Context, string, []*sqlf.Query, []string) (workerutil.Record, string, bool, error) {
+		return r0, r1, r2, r3
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *StoreDequeueWithKeyFunc) PushReturn(r0 workerutil.Record, r1 string, r2 bool, r3 error) {
+	f.PushHook(func(context.Context, string, []*sqlf.Query, []string) (workerutil.Record, string, bool, error) {
+		return r0, r1, r2, r3
+	})
+}
+
+func (f *StoreDequeueWithKeyFunc) nextHook() func(context.Context, string, []*sqlf.Query, []string) (workerutil.Record, string, bool, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *StoreDequeueWithKeyFunc) appendCall(r0 StoreDequeueWithKeyFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of StoreDequeueWithKeyFuncCall objects
+// describing the invocations of this function.
+func (f *StoreDequeueWithKeyFunc) History() []StoreDequeueWithKeyFuncCall {
+	f.mutex.Lock()
+	history := make([]StoreDequeueWithKeyFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// StoreDequeueWithKeyFuncCall is an object that describes an invocation of
+// method DequeueWithKey on an instance of MockStore.
+type StoreDequeueWithKeyFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 string
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 []*sqlf.Query
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 []string
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 workerutil.Record
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 string
+	// Result2 is the value of the 3rd result returned from this method
+	// invocation.
+	Result2 bool
+	// Result3 is the value of the 4th result returned from this method
+	// invocation.
+	Result3 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c StoreDequeueWithKeyFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c StoreDequeueWithKeyFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1, c.Result2, c.Result3}
+}
+
 // StoreHandleFunc describes the behavior when the Handle method of the
 // parent MockStore instance is invoked.
 type StoreHandleFunc struct {
@@ -594,6 +867,117 @@ func (c StoreHeartbeatFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
+// StoreListFailedFunc describes the behavior when the ListFailed method of
+// the parent MockStore instance is invoked.
+type StoreListFailedFunc struct {
+	defaultHook func(context.Context, store.ListFailedOptions) ([]store.FailedRecord, int, error)
+	hooks       []func(context.Context, store.ListFailedOptions) ([]store.FailedRecord, int, error)
+	history     []StoreListFailedFuncCall
+	mutex       sync.Mutex
+}
+
+// ListFailed delegates to the next hook function in the queue and stores
+// the parameter and result values of this invocation.
+func (m *MockStore) ListFailed(v0 context.Context, v1 store.ListFailedOptions) ([]store.FailedRecord, int, error) {
+	r0, r1, r2 := m.ListFailedFunc.nextHook()(v0, v1)
+	m.ListFailedFunc.appendCall(StoreListFailedFuncCall{v0, v1, r0, r1, r2})
+	return r0, r1, r2
+}
+
+// SetDefaultHook sets function that is called when the ListFailed method of
+// the parent MockStore instance is invoked and the hook queue is empty.
+func (f *StoreListFailedFunc) SetDefaultHook(hook func(context.Context, store.ListFailedOptions) ([]store.FailedRecord, int, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// ListFailed method of the parent MockStore instance invokes the hook at
+// the front of the queue and discards it. After the queue is empty, the
+// default hook function is invoked for any future action.
+func (f *StoreListFailedFunc) PushHook(hook func(context.Context, store.ListFailedOptions) ([]store.FailedRecord, int, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *StoreListFailedFunc) SetDefaultReturn(r0 []store.FailedRecord, r1 int, r2 error) {
+	f.SetDefaultHook(func(context.Context, store.ListFailedOptions) ([]store.FailedRecord, int, error) {
+		return r0, r1, r2
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *StoreListFailedFunc) PushReturn(r0 []store.FailedRecord, r1 int, r2 error) {
+	f.PushHook(func(context.Context, store.ListFailedOptions) ([]store.FailedRecord, int, error) {
+		return r0, r1, r2
+	})
+}
+
+func (f *StoreListFailedFunc) nextHook() func(context.Context, store.ListFailedOptions) ([]store.FailedRecord, int, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *StoreListFailedFunc) appendCall(r0 StoreListFailedFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of StoreListFailedFuncCall objects describing
+// the invocations of this function.
+func (f *StoreListFailedFunc) History() []StoreListFailedFuncCall {
+	f.mutex.Lock()
+	history := make([]StoreListFailedFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// StoreListFailedFuncCall is an object that describes an invocation of
+// method ListFailed on an instance of MockStore.
+type StoreListFailedFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 store.ListFailedOptions
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []store.FailedRecord
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 int
+	// Result2 is the value of the 3rd result returned from this method
+	// invocation.
+	Result2 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c StoreListFailedFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c StoreListFailedFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1, c.Result2}
+}
+
 // StoreMarkCompleteFunc describes the behavior when the MarkComplete method
 // of the parent MockStore instance is invoked.
 type StoreMarkCompleteFunc struct {
@@ -1149,6 +1533,114 @@ func (c StoreRequeueFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0}
 }
 
+// StoreRequeueFailedFunc describes the behavior when the RequeueFailed
+// method of the parent MockStore instance is invoked.
+type StoreRequeueFailedFunc struct {
+	defaultHook func(context.Context, []int) (int, error)
+	hooks       []func(context.Context, []int) (int, error)
+	history     []StoreRequeueFailedFuncCall
+	mutex       sync.Mutex
+}
+
+// RequeueFailed delegates to the next hook function in the queue and stores
+// the parameter and result values of this invocation.
+func (m *MockStore) RequeueFailed(v0 context.Context, v1 []int) (int, error) {
+	r0, r1 := m.RequeueFailedFunc.nextHook()(v0, v1)
+	m.RequeueFailedFunc.appendCall(StoreRequeueFailedFuncCall{v0, v1, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the RequeueFailed method
+// of the parent MockStore instance is invoked and the hook queue is empty.
+func (f *StoreRequeueFailedFunc) SetDefaultHook(hook func(context.Context, []int) (int, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// RequeueFailed method of the parent MockStore instance invokes the hook at
+// the front of the queue and discards it. After the queue is empty, the
+// default hook function is invoked for any future action.
+func (f *StoreRequeueFailedFunc) PushHook(hook func(context.Context, []int) (int, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *StoreRequeueFailedFunc) SetDefaultReturn(r0 int, r1 error) {
+	f.SetDefaultHook(func(context.Context, []int) (int, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *StoreRequeueFailedFunc) PushReturn(r0 int, r1 error) {
+	f.PushHook(func(context.Context, []int) (int, error) {
+		return r0, r1
+	})
+}
+
+func (f *StoreRequeueFailedFunc) nextHook() func(context.Context, []int) (int, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *StoreRequeueFailedFunc) appendCall(r0 StoreRequeueFailedFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of StoreRequeueFailedFuncCall objects
+// describing the invocations of this function.
+func (f *StoreRequeueFailedFunc) History() []StoreRequeueFailedFuncCall {
+	f.mutex.Lock()
+	history := make([]StoreRequeueFailedFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// StoreRequeueFailedFuncCall is an object that describes an invocation of
+// method RequeueFailed on an instance of MockStore.
+type StoreRequeueFailedFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 []int
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 int
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c StoreRequeueFailedFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c StoreRequeueFailedFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // StoreResetStalledFunc describes the behavior when the ResetStalled method
 // of the parent MockStore instance is invoked.
 type StoreResetStalledFunc struct {
@@ -18,6 +18,9 @@ type operations struct {
 	markFailed              *observation.Operation
 	resetStalled            *observation.Operation
 	heartbeat               *observation.Operation
+	listFailed              *observation.Operation
+	requeueFailed           *observation.Operation
+	deleteFailed            *observation.Operation
 }
 
 func newOperations(storeName string, observationContext *observation.Context) *operations {
@@ -47,5 +50,8 @@ func newOperations(storeName string, observationContext *observation.Context) *o
 		markFailed:              op("MarkFailed"),
 		resetStalled:            op("ResetStalled"),
 		heartbeat:               op("Heartbeat"),
+		listFailed:              op("ListFailed"),
+		requeueFailed:           op("RequeueFailed"),
+		deleteFailed:            op("DeleteFailed"),
 	}
 }
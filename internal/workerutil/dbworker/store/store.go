@@ -12,6 +12,7 @@ import (
 	"github.com/cockroachdb/errors"
 	"github.com/derision-test/glock"
 	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
 	"github.com/opentracing/opentracing-go/log"
 
 	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
@@ -82,6 +83,11 @@ type Store interface {
 	// The supplied conditions may use the alias provided in `ViewName`, if one was supplied.
 	Dequeue(ctx context.Context, workerHostname string, conditions []*sqlf.Query) (workerutil.Record, bool, error)
 
+	// DequeueWithKey behaves like Dequeue, but skips records whose fairness key is one of the given excluded keys.
+	// The fairness key of the dequeued record is returned along with the record. If no `FairnessKeyExpression` was
+	// supplied, every record has the empty key.
+	DequeueWithKey(ctx context.Context, workerHostname string, conditions []*sqlf.Query, excludedKeys []string) (workerutil.Record, string, bool, error)
+
 	// Heartbeat marks the given record as currently being processed.
 	Heartbeat(ctx context.Context, ids []int, options HeartbeatOptions) (knownIDs []int, err error)
 
@@ -119,6 +125,39 @@ type Store interface {
 	// identifiers the age of the record's last heartbeat timestamp for each record reset to queued and failed states,
 	// respectively.
 	ResetStalled(ctx context.Context) (resetLastHeartbeatsByIDs, failedLastHeartbeatsByIDs map[int]time.Duration, err error)
+
+	// ListFailed returns a page of the records in the failed state, most recently failed first, along with the total
+	// number of failed records. These records have exhausted their retries and will not be processed again unless
+	// they are explicitly requeued.
+	ListFailed(ctx context.Context, opts ListFailedOptions) (records []FailedRecord, totalCount int, err error)
+
+	// RequeueFailed moves the failed records with the given identifiers back to the queued state and resets their
+	// failure and reset counters. If no identifiers are given, all failed records are requeued. This method returns
+	// the number of requeued records.
+	RequeueFailed(ctx context.Context, ids []int) (int, error)
+
+	// DeleteFailed deletes the failed records with the given identifiers. If no identifiers are given, all failed
+	// records are deleted. This method returns the number of deleted records.
+	DeleteFailed(ctx context.Context, ids []int) (int, error)
+}
+
+// ListFailedOptions configure the page of failed records returned by ListFailed.
+type ListFailedOptions struct {
+	// Limit, if positive, is the maximum number of records to return.
+	Limit int
+	// Cursor, if positive, restricts the results to records with a smaller identifier.
+	Cursor int
+}
+
+// FailedRecord is the queue-independent view of a record in the failed state.
+type FailedRecord struct {
+	ID             int
+	FailureMessage *string
+	StartedAt      *time.Time
+	FinishedAt     *time.Time
+	NumResets      int
+	NumFailures    int
+	ExecutionLogs  []workerutil.ExecutionLogEntry
 }
 
 type ExecutionLogEntry workerutil.ExecutionLogEntry
@@ -215,6 +254,16 @@ type Options struct {
 	// expressions may use the alias provided in `ViewName`, if one was supplied.
 	ColumnExpressions []*sqlf.Query
 
+	// FairnessKeyExpression is an optional SQL expression that groups records by tenant, such as the
+	// user, repository, or namespace a record belongs to. When supplied, `Dequeue` round-robins across
+	// the distinct values of this expression by preferring the key that was dequeued least recently,
+	// and only uses `OrderByExpression` to order records with the same key. This expression may use the
+	// alias provided in `ViewName`, if one was supplied.
+	//
+	// The time each key was last dequeued is tracked in the workerutil_fairness_dequeues table under the
+	// store's `Name`, so stores sharing a name also share a schedule.
+	FairnessKeyExpression *sqlf.Query
+
 	// StalledMaxAge is the maximum allowed duration between heartbeat updates of a job's last_heartbeat_at
 	// field. An unmodified row that is marked as processing likely indicates that the worker that dequeued
 	// the record has died.
@@ -240,6 +289,18 @@ type Options struct {
 	// Setting this value to zero will disable retries entirely.
 	MaxNumRetries int
 
+	// RetryBackoffMultiplier, if greater than one, increases the delay before each retry exponentially. The
+	// n-th retry of a record is delayed by RetryAfter * RetryBackoffMultiplier^(n-1). If this value or RetryJitter
+	// is set, the time of the next attempt of an errored record is stored in its process_after column.
+	RetryBackoffMultiplier float64
+
+	// MaxRetryAfter, if non-zero, is the maximum delay before a retry when RetryBackoffMultiplier is set.
+	MaxRetryAfter time.Duration
+
+	// RetryJitter is the fraction (between zero and one) by which each retry delay is randomly increased or
+	// decreased so that records that failed at the same time are not all retried at the same time.
+	RetryJitter float64
+
 	// clock is used to mock out the wall clock used for heartbeat updates.
 	clock glock.Clock
 }
@@ -356,39 +417,48 @@ SELECT COUNT(*) FROM %s WHERE (
 // Most often, this will be when the handler moves the record into a terminal state.
 //
 // The supplied conditions may use the alias provided in `ViewName`, if one was supplied.
-func (s *store) Dequeue(ctx context.Context, workerHostname string, conditions []*sqlf.Query) (_ workerutil.Record, _ bool, err error) {
-	ctx, traceLog, endObservation := s.operations.dequeue.WithAndLogger(ctx, &err, observation.Args{})
+func (s *store) Dequeue(ctx context.Context, workerHostname string, conditions []*sqlf.Query) (workerutil.Record, bool, error) {
+	record, _, exists, err := s.DequeueWithKey(ctx, workerHostname, conditions, nil)
+	return record, exists, err
+}
+
+// DequeueWithKey behaves like Dequeue, but skips records whose fairness key is one of the given excluded keys.
+// The fairness key of the dequeued record is returned along with the record. If no `FairnessKeyExpression` was
+// supplied, every record has the empty key.
+func (s *store) DequeueWithKey(ctx context.Context, workerHostname string, conditions []*sqlf.Query, excludedKeys []string) (_ workerutil.Record, _ string, _ bool, err error) {
+	ctx, traceLog, endObservation := s.operations.dequeue.WithAndLogger(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.Int("numExcludedKeys", len(excludedKeys)),
+	}})
 	defer endObservation(1, observation.Args{})
 
 	if s.InTransaction() {
-		return nil, false, ErrDequeueTransaction
+		return nil, "", false, ErrDequeueTransaction
 	}
 
-	now := s.now()
+	var (
+		id     int
+		key    string
+		exists bool
+	)
+	if s.options.FairnessKeyExpression == nil {
+		for _, excludedKey := range excludedKeys {
+			if excludedKey == "" {
+				// Every record has the empty key
+				return nil, "", false, nil
+			}
+		}
 
-	// Select and "lock" candidate record
-	id, exists, err := basestore.ScanFirstInt(s.Query(ctx, s.formatQuery(
-		selectCandidateQuery,
-		quote(s.options.ViewName),
-		now,
-		int(s.options.RetryAfter/time.Second),
-		now,
-		int(s.options.RetryAfter/time.Second),
-		s.options.MaxNumRetries,
-		makeConditionSuffix(conditions),
-		s.options.OrderByExpression,
-		quote(s.options.TableName),
-		now,
-		now,
-		workerHostname,
-	)))
+		id, exists, err = s.selectCandidate(ctx, workerHostname, conditions)
+	} else {
+		id, key, exists, err = s.selectFairCandidate(ctx, workerHostname, conditions, excludedKeys)
+	}
 	if err != nil {
-		return nil, false, err
+		return nil, "", false, err
 	}
 	if !exists {
-		return nil, false, nil
+		return nil, "", false, nil
 	}
-	traceLog(log.Int("id", id))
+	traceLog(log.Int("id", id), log.String("key", key))
 
 	// Scan the actual record after updating its state
 	record, exists, err := s.options.Scan(s.Query(ctx, s.formatQuery(
@@ -398,13 +468,35 @@ func (s *store) Dequeue(ctx context.Context, workerHostname string, conditions [
 		id,
 	)))
 	if err != nil {
-		return nil, false, err
+		return nil, "", false, err
 	}
 	if !exists {
-		return nil, false, nil
+		return nil, "", false, nil
 	}
 
-	return record, true, nil
+	return record, key, true, nil
+}
+
+// selectCandidate selects and "locks" the first candidate record in the order of `OrderByExpression`.
+func (s *store) selectCandidate(ctx context.Context, workerHostname string, conditions []*sqlf.Query) (int, bool, error) {
+	now := s.now()
+
+	return basestore.ScanFirstInt(s.Query(ctx, s.formatQuery(
+		selectCandidateQuery,
+		quote(s.options.ViewName),
+		now,
+		int(s.options.RetryAfter/time.Second),
+		now,
+		s.minRetryAfterSeconds(),
+		s.retryBackoffCondition(now),
+		s.options.MaxNumRetries,
+		makeConditionSuffix(conditions),
+		s.options.OrderByExpression,
+		quote(s.options.TableName),
+		now,
+		now,
+		workerHostname,
+	)))
 }
 
 const selectCandidateQuery = `
@@ -420,6 +512,7 @@ WITH candidate AS (
 				%s > 0 AND
 				{state} = 'errored' AND
 				%s - {finished_at} > (%s * '1 second'::interval) AND
+				%s AND
 				{num_failures} < %s
 			)
 		)
@@ -441,6 +534,111 @@ WHERE {id} IN (SELECT {id} FROM candidate)
 RETURNING {id}
 `
 
+// selectFairCandidate selects and "locks" the first candidate record whose fairness key is not excluded,
+// preferring the key that was dequeued least recently. The dequeue times are joined on the primary key of
+// workerutil_fairness_dequeues rather than looked up for each candidate. The dequeue time of the selected
+// key is recorded in the same query.
+func (s *store) selectFairCandidate(ctx context.Context, workerHostname string, conditions []*sqlf.Query, excludedKeys []string) (id int, key string, exists bool, err error) {
+	now := s.now()
+	keyExpression := sqlf.Sprintf("COALESCE((%s)::text, '')", s.options.FairnessKeyExpression)
+
+	if len(excludedKeys) > 0 {
+		conditions = append(conditions, sqlf.Sprintf("NOT (%s = ANY(%s))", keyExpression, pq.Array(excludedKeys)))
+	}
+
+	rows, err := s.Query(ctx, s.formatQuery(
+		selectFairCandidateQuery,
+		keyExpression,
+		quote(s.options.ViewName),
+		s.options.Name,
+		keyExpression,
+		now,
+		int(s.options.RetryAfter/time.Second),
+		now,
+		s.minRetryAfterSeconds(),
+		s.retryBackoffCondition(now),
+		s.options.MaxNumRetries,
+		makeConditionSuffix(conditions),
+		s.options.OrderByExpression,
+		quote(viewAlias(s.options.ViewName)),
+		quote(s.options.TableName),
+		now,
+		now,
+		workerHostname,
+		s.options.Name,
+		now,
+	))
+	if err != nil {
+		return 0, "", false, err
+	}
+	defer func() { err = basestore.CloseRows(rows, err) }()
+
+	if rows.Next() {
+		if err := rows.Scan(&id, &key); err != nil {
+			return 0, "", false, err
+		}
+
+		return id, key, true, nil
+	}
+
+	return 0, "", false, nil
+}
+
+// viewAlias returns the name by which the given view (or table), which may be followed by an alias, is
+// referenced in a query.
+func viewAlias(viewName string) string {
+	fields := strings.Fields(viewName)
+	return fields[len(fields)-1]
+}
+
+const selectFairCandidateQuery = `
+-- source: internal/workerutil/store.go:DequeueWithKey
+WITH candidate AS (
+	SELECT {id}, %s AS fairness_key FROM %s
+	LEFT JOIN workerutil_fairness_dequeues fd ON fd.queue_name = %s AND fd.fairness_key = %s
+	WHERE
+		(
+			(
+				{state} = 'queued' AND
+				({process_after} IS NULL OR {process_after} <= %s)
+			) OR (
+				%s > 0 AND
+				{state} = 'errored' AND
+				%s - {finished_at} > (%s * '1 second'::interval) AND
+				%s AND
+				{num_failures} < %s
+			)
+		)
+		%s
+	ORDER BY fd.last_dequeued_at ASC NULLS FIRST, %s
+	FOR UPDATE OF %s SKIP LOCKED
+	LIMIT 1
+),
+updated AS (
+	UPDATE %s
+	SET
+		{state} = 'processing',
+		{started_at} = %s,
+		{last_heartbeat_at} = %s,
+		{finished_at} = NULL,
+		{failure_message} = NULL,
+		{execution_logs} = NULL,
+		{worker_hostname} = %s
+	WHERE {id} IN (SELECT {id} FROM candidate)
+	RETURNING {id}
+),
+dequeued AS (
+	SELECT c.{id}, c.fairness_key FROM candidate c
+	WHERE c.{id} IN (SELECT {id} FROM updated)
+),
+fairness AS (
+	INSERT INTO workerutil_fairness_dequeues (queue_name, fairness_key, last_dequeued_at)
+	SELECT %s, fairness_key, %s FROM dequeued
+	ON CONFLICT (queue_name, fairness_key) DO UPDATE SET last_dequeued_at = EXCLUDED.last_dequeued_at
+)
+SELECT {id}, fairness_key FROM dequeued
+`
+
 const selectRecordQuery = `
 -- source: internal/workerutil/store.go:Dequeue
 SELECT %s FROM %s WHERE {id} = %s
@@ -640,7 +838,7 @@ func (s *store) MarkErrored(ctx context.Context, id int, failureMessage string,
 	}
 	conds = append(conds, options.ToSQLConds(s.formatQuery)...)
 
-	q := s.formatQuery(markErroredQuery, quote(s.options.TableName), s.options.MaxNumRetries, failureMessage, sqlf.Join(conds, "AND"))
+	q := s.formatQuery(markErroredQuery, quote(s.options.TableName), s.options.MaxNumRetries, s.retryProcessAfterExpression(), failureMessage, sqlf.Join(conds, "AND"))
 	_, ok, err := basestore.ScanFirstInt(s.Query(ctx, q))
 	return ok, err
 }
@@ -650,12 +848,65 @@ const markErroredQuery = `
 UPDATE %s
 SET {state} = CASE WHEN {num_failures} + 1 = %d THEN 'failed' ELSE 'errored' END,
 	{finished_at} = clock_timestamp(),
+	{process_after} = %s,
 	{failure_message} = %s,
 	{num_failures} = {num_failures} + 1
 WHERE %s
 RETURNING {id}
 `
 
+// hasRetryBackoff returns true if the delay before a retry depends on the record, in which case the
+// time of the next attempt is stored in the process_after column of errored records. Otherwise, errored
+// records are retried RetryAfter after they finished and their process_after column is left untouched.
+func (s *store) hasRetryBackoff() bool {
+	return s.options.RetryBackoffMultiplier > 1 || s.options.RetryJitter > 0
+}
+
+// retryBackoffCondition returns the condition an errored record must satisfy to be retried in addition
+// to the RetryAfter delay.
+func (s *store) retryBackoffCondition(now time.Time) *sqlf.Query {
+	if !s.hasRetryBackoff() {
+		return sqlf.Sprintf("TRUE")
+	}
+
+	return s.formatQuery("({process_after} IS NULL OR {process_after} <= %s)", now)
+}
+
+// retryProcessAfterExpression returns the new value of the process_after column of a record being
+// marked as errored.
+func (s *store) retryProcessAfterExpression() *sqlf.Query {
+	if !s.hasRetryBackoff() {
+		return s.formatQuery("{process_after}")
+	}
+
+	return sqlf.Sprintf("clock_timestamp() + (%s * '1 second'::interval)", s.retryDelayExpression())
+}
+
+// retryDelayExpression returns an expression evaluating to the number of seconds to wait before retrying a
+// record, based on the number of times the record failed before its current failure.
+func (s *store) retryDelayExpression() *sqlf.Query {
+	multiplier := s.options.RetryBackoffMultiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+
+	delay := s.formatQuery("%s::float8 * POWER(%s::float8, {num_failures})", s.options.RetryAfter.Seconds(), multiplier)
+	if s.options.MaxRetryAfter > 0 {
+		delay = sqlf.Sprintf("LEAST(%s, %s::float8)", delay, s.options.MaxRetryAfter.Seconds())
+	}
+	if s.options.RetryJitter > 0 {
+		delay = sqlf.Sprintf("%s * (1 + %s::float8 * (2 * random() - 1))", delay, s.options.RetryJitter)
+	}
+
+	return delay
+}
+
+// minRetryAfterSeconds returns the smallest delay in seconds before an errored record may be retried,
+// taking a negative jitter into account.
+func (s *store) minRetryAfterSeconds() int {
+	return int(s.options.RetryAfter.Seconds() * (1 - s.options.RetryJitter))
+}
+
 // MarkFailed attempts to update the state of the record to failed. This method will only have an effect
 // if the current state of the record is processing or completed. A requeued record or a record already marked
 // with an error will not be updated. This method returns a boolean flag indicating if the record was updated.
@@ -787,6 +1038,174 @@ WHERE {id} IN (SELECT {id} FROM stalled)
 RETURNING {id}, {last_heartbeat_at}
 `
 
+// ListFailed returns a page of the records in the failed state, most recently failed first, along with the total
+// number of failed records.
+func (s *store) ListFailed(ctx context.Context, opts ListFailedOptions) (records []FailedRecord, totalCount int, err error) {
+	ctx, endObservation := s.operations.listFailed.With(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.Int("limit", opts.Limit),
+		log.Int("cursor", opts.Cursor),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	totalCount, _, err = basestore.ScanFirstInt(s.Query(ctx, s.formatQuery(
+		countFailedQuery,
+		quote(s.options.TableName),
+	)))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	conds := []*sqlf.Query{s.formatQuery("{state} = 'failed'")}
+	if opts.Cursor > 0 {
+		conds = append(conds, s.formatQuery("{id} < %s", opts.Cursor))
+	}
+	limit := sqlf.Sprintf("")
+	if opts.Limit > 0 {
+		limit = sqlf.Sprintf("LIMIT %s", opts.Limit)
+	}
+
+	records, err = scanFailedRecords(s.Query(ctx, s.formatQuery(
+		listFailedQuery,
+		quote(s.options.TableName),
+		sqlf.Join(conds, "AND"),
+		limit,
+	)))
+	return records, totalCount, err
+}
+
+const countFailedQuery = `
+-- source: internal/workerutil/store.go:ListFailed
+SELECT COUNT(*) FROM %s WHERE {state} = 'failed'
+`
+
+const listFailedQuery = `
+-- source: internal/workerutil/store.go:ListFailed
+SELECT
+	{id},
+	{failure_message},
+	{started_at},
+	{finished_at},
+	{num_resets},
+	{num_failures},
+	{execution_logs}
+FROM %s
+WHERE %s
+ORDER BY {id} DESC
+%s
+`
+
+func scanFailedRecords(rows *sql.Rows, queryErr error) (_ []FailedRecord, err error) {
+	if queryErr != nil {
+		return nil, queryErr
+	}
+	defer func() { err = basestore.CloseRows(rows, err) }()
+
+	var records []FailedRecord
+	for rows.Next() {
+		var record FailedRecord
+		var executionLogs []ExecutionLogEntry
+		if err := rows.Scan(
+			&record.ID,
+			&record.FailureMessage,
+			&record.StartedAt,
+			&record.FinishedAt,
+			&record.NumResets,
+			&record.NumFailures,
+			pq.Array(&executionLogs),
+		); err != nil {
+			return nil, err
+		}
+
+		for _, entry := range executionLogs {
+			record.ExecutionLogs = append(record.ExecutionLogs, workerutil.ExecutionLogEntry(entry))
+		}
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+// RequeueFailed moves the failed records with the given identifiers back to the queued state and resets their
+// failure and reset counters. If no identifiers are given, all failed records are requeued.
+func (s *store) RequeueFailed(ctx context.Context, ids []int) (_ int, err error) {
+	ctx, endObservation := s.operations.requeueFailed.With(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.Int("numIDs", len(ids)),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	return s.updateFailed(ctx, requeueFailedQuery, ids)
+}
+
+const requeueFailedQuery = `
+-- source: internal/workerutil/store.go:RequeueFailed
+WITH candidates AS (
+	SELECT {id} FROM %s
+	WHERE %s
+	FOR UPDATE SKIP LOCKED
+),
+updated AS (
+	UPDATE %s
+	SET
+		{state} = 'queued',
+		{process_after} = NULL,
+		{failure_message} = NULL,
+		{started_at} = NULL,
+		{finished_at} = NULL,
+		{num_resets} = 0,
+		{num_failures} = 0
+	WHERE {id} IN (SELECT {id} FROM candidates)
+	RETURNING 1
+)
+SELECT COUNT(*) FROM updated
+`
+
+// DeleteFailed deletes the failed records with the given identifiers. If no identifiers are given, all failed
+// records are deleted.
+func (s *store) DeleteFailed(ctx context.Context, ids []int) (_ int, err error) {
+	ctx, endObservation := s.operations.deleteFailed.With(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.Int("numIDs", len(ids)),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	return s.updateFailed(ctx, deleteFailedQuery, ids)
+}
+
+const deleteFailedQuery = `
+-- source: internal/workerutil/store.go:DeleteFailed
+WITH candidates AS (
+	SELECT {id} FROM %s
+	WHERE %s
+	FOR UPDATE SKIP LOCKED
+),
+deleted AS (
+	DELETE FROM %s
+	WHERE {id} IN (SELECT {id} FROM candidates)
+	RETURNING 1
+)
+SELECT COUNT(*) FROM deleted
+`
+
+// updateFailed runs the given query over the failed records with the given identifiers, or over all
+// failed records if no identifiers are given, and returns the number of affected records.
+func (s *store) updateFailed(ctx context.Context, query string, ids []int) (int, error) {
+	conds := []*sqlf.Query{s.formatQuery("{state} = 'failed'")}
+	if len(ids) > 0 {
+		sqlIDs := make([]*sqlf.Query, 0, len(ids))
+		for _, id := range ids {
+			sqlIDs = append(sqlIDs, sqlf.Sprintf("%s", id))
+		}
+		conds = append(conds, s.formatQuery("{id} IN (%s)", sqlf.Join(sqlIDs, ",")))
+	}
+
+	count, _, err := basestore.ScanFirstInt(s.Query(ctx, s.formatQuery(
+		query,
+		quote(s.options.TableName),
+		sqlf.Join(conds, "AND"),
+		quote(s.options.TableName),
+	)))
+	return count, err
+}
+
 func (s *store) formatQuery(query string, args ...interface{}) *sqlf.Query {
 	return sqlf.Sprintf(s.columnReplacer.Replace(query), args...)
 }
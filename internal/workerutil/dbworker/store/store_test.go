@@ -149,6 +149,61 @@ func TestStoreDequeueConditions(t *testing.T) {
 	assertDequeueRecordResult(t, 3, record, ok, err)
 }
 
+func TestStoreDequeueFairness(t *testing.T) {
+	db := setupStoreTest(t)
+
+	if _, err := db.ExecContext(context.Background(), `
+		INSERT INTO workerutil_test (id, state, uploaded_at)
+		VALUES
+			(1, 'queued', NOW() - '5 minute'::interval),
+			(2, 'queued', NOW() - '4 minute'::interval),
+			(3, 'queued', NOW() - '3 minute'::interval),
+			(10, 'queued', NOW() - '2 minute'::interval),
+			(11, 'queued', NOW() - '1 minute'::interval)
+	`); err != nil {
+		t.Fatalf("unexpected error inserting records: %s", err)
+	}
+
+	options := defaultTestStoreOptions(nil)
+	options.FairnessKeyExpression = sqlf.Sprintf("CASE WHEN w.id < 10 THEN 'a' ELSE 'b' END")
+	store := testStore(db, options)
+
+	for _, expectedID := range []int{1, 10, 2, 11, 3} {
+		record, ok, err := store.Dequeue(context.Background(), "test", nil)
+		assertDequeueRecordResult(t, expectedID, record, ok, err)
+	}
+}
+
+func TestStoreDequeueWithKeyExcludedKeys(t *testing.T) {
+	db := setupStoreTest(t)
+
+	if _, err := db.ExecContext(context.Background(), `
+		INSERT INTO workerutil_test (id, state, uploaded_at)
+		VALUES
+			(1, 'queued', NOW() - '3 minute'::interval),
+			(2, 'queued', NOW() - '2 minute'::interval),
+			(10, 'queued', NOW() - '1 minute'::interval)
+	`); err != nil {
+		t.Fatalf("unexpected error inserting records: %s", err)
+	}
+
+	options := defaultTestStoreOptions(nil)
+	options.FairnessKeyExpression = sqlf.Sprintf("CASE WHEN w.id < 10 THEN 'a' ELSE 'b' END")
+	store := testStore(db, options)
+
+	record, key, ok, err := store.DequeueWithKey(context.Background(), "test", nil, []string{"a"})
+	assertDequeueRecordResult(t, 10, record, ok, err)
+	if key != "b" {
+		t.Errorf("unexpected key. want=%q have=%q", "b", key)
+	}
+
+	if _, _, ok, err := store.DequeueWithKey(context.Background(), "test", nil, []string{"a", "b"}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if ok {
+		t.Fatalf("did not expect a dequeueable record")
+	}
+}
+
 func TestStoreDequeueResetExecutionLogs(t *testing.T) {
 	db := setupStoreTest(t)
 
@@ -338,6 +393,215 @@ func TestStoreDequeueRetryAfterDisabled(t *testing.T) {
 	}
 }
 
+func TestStoreMarkErroredBackoff(t *testing.T) {
+	db := setupStoreTest(t)
+
+	if _, err := db.ExecContext(context.Background(), `
+		INSERT INTO workerutil_test (id, state, num_failures)
+		VALUES
+			(1, 'processing', 0),
+			(2, 'processing', 2),
+			(3, 'processing', 8)
+	`); err != nil {
+		t.Fatalf("unexpected error inserting records: %s", err)
+	}
+
+	options := defaultTestStoreOptions(nil)
+	options.MaxNumRetries = 10
+	options.RetryAfter = time.Minute
+	options.RetryBackoffMultiplier = 2
+	options.MaxRetryAfter = time.Hour
+	store := testStore(db, options)
+
+	for _, id := range []int{1, 2, 3} {
+		if _, err := store.MarkErrored(context.Background(), id, "new message", MarkFinalOptions{}); err != nil {
+			t.Fatalf("unexpected error marking record as errored: %s", err)
+		}
+	}
+
+	delays, err := basestore.ScanInts(db.QueryContext(context.Background(), `
+		SELECT ROUND(EXTRACT(EPOCH FROM process_after - finished_at))::integer FROM workerutil_test ORDER BY id
+	`))
+	if err != nil {
+		t.Fatalf("unexpected error querying records: %s", err)
+	}
+
+	// 1 minute, 4 minutes, and 256 minutes capped at 1 hour
+	if diff := cmp.Diff([]int{60, 240, 3600}, delays); diff != "" {
+		t.Errorf("unexpected retry delays (-want +got):\n%s", diff)
+	}
+}
+
+func TestStoreMarkErroredWithoutBackoff(t *testing.T) {
+	db := setupStoreTest(t)
+
+	if _, err := db.ExecContext(context.Background(), `
+		INSERT INTO workerutil_test (id, state, process_after)
+		VALUES (1, 'processing', '2021-01-01 00:00:00+00')
+	`); err != nil {
+		t.Fatalf("unexpected error inserting records: %s", err)
+	}
+
+	options := defaultTestStoreOptions(nil)
+	options.MaxNumRetries = 10
+	options.RetryAfter = time.Minute
+	store := testStore(db, options)
+
+	if _, err := store.MarkErrored(context.Background(), 1, "new message", MarkFinalOptions{}); err != nil {
+		t.Fatalf("unexpected error marking record as errored: %s", err)
+	}
+
+	processAfter, _, err := basestore.ScanFirstTime(db.QueryContext(context.Background(), `SELECT process_after FROM workerutil_test WHERE id = 1`))
+	if err != nil {
+		t.Fatalf("unexpected error querying record: %s", err)
+	}
+	if want := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC); !processAfter.Equal(want) {
+		t.Errorf("unexpected process_after. want=%s have=%s", want, processAfter)
+	}
+}
+
+func TestStoreDequeueRetryBackoff(t *testing.T) {
+	db := setupStoreTest(t)
+
+	if _, err := db.ExecContext(context.Background(), `
+		INSERT INTO workerutil_test (id, state, finished_at, process_after, failure_message, num_failures, uploaded_at)
+		VALUES
+			(1, 'errored', NOW() - '6 minute'::interval, NOW() + '2 minute'::interval, 'error', 2, NOW() - '2 minutes'::interval),
+			(2, 'errored', NOW() - '6 minute'::interval, NOW() - '2 minute'::interval, 'error', 1, NOW() - '1 minutes'::interval)
+	`); err != nil {
+		t.Fatalf("unexpected error inserting records: %s", err)
+	}
+
+	options := defaultTestStoreOptions(nil)
+	options.MaxNumRetries = 5
+	options.RetryAfter = 5 * time.Minute
+	options.RetryBackoffMultiplier = 2
+	store := testStore(db, options)
+
+	record, ok, err := store.Dequeue(context.Background(), "test", nil)
+	assertDequeueRecordResult(t, 2, record, ok, err)
+
+	if _, ok, _ := store.Dequeue(context.Background(), "test", nil); ok {
+		t.Fatalf("did not expect a second dequeueable record")
+	}
+}
+
+func TestStoreListFailed(t *testing.T) {
+	db := setupStoreTest(t)
+
+	if _, err := db.ExecContext(context.Background(), `
+		INSERT INTO workerutil_test (id, state, failure_message, num_failures, execution_logs)
+		VALUES
+			(1, 'failed', 'oops 1', 3, E'{"{\\"key\\": \\"test\\"}"}'),
+			(2, 'completed', NULL, 0, NULL),
+			(3, 'failed', 'oops 3', 1, NULL),
+			(4, 'errored', 'oops 4', 1, NULL),
+			(5, 'failed', 'oops 5', 2, NULL)
+	`); err != nil {
+		t.Fatalf("unexpected error inserting records: %s", err)
+	}
+
+	store := testStore(db, defaultTestStoreOptions(nil))
+
+	records, totalCount, err := store.ListFailed(context.Background(), ListFailedOptions{Limit: 2})
+	if err != nil {
+		t.Fatalf("unexpected error listing failed records: %s", err)
+	}
+	if totalCount != 3 {
+		t.Errorf("unexpected total count. want=%d have=%d", 3, totalCount)
+	}
+	if ids := failedRecordIDs(records); !cmp.Equal([]int{5, 3}, ids) {
+		t.Errorf("unexpected ids. want=%v have=%v", []int{5, 3}, ids)
+	}
+
+	records, _, err = store.ListFailed(context.Background(), ListFailedOptions{Limit: 2, Cursor: 3})
+	if err != nil {
+		t.Fatalf("unexpected error listing failed records: %s", err)
+	}
+	if ids := failedRecordIDs(records); !cmp.Equal([]int{1}, ids) {
+		t.Errorf("unexpected ids. want=%v have=%v", []int{1}, ids)
+	}
+	if len(records[0].ExecutionLogs) != 1 || records[0].ExecutionLogs[0].Key != "test" {
+		t.Errorf("unexpected execution logs: %v", records[0].ExecutionLogs)
+	}
+}
+
+func failedRecordIDs(records []FailedRecord) []int {
+	ids := make([]int, 0, len(records))
+	for _, record := range records {
+		ids = append(ids, record.ID)
+	}
+
+	return ids
+}
+
+func TestStoreRequeueFailed(t *testing.T) {
+	db := setupStoreTest(t)
+
+	if _, err := db.ExecContext(context.Background(), `
+		INSERT INTO workerutil_test (id, state, failure_message, num_failures, num_resets)
+		VALUES
+			(1, 'failed', 'oops', 3, 1),
+			(2, 'failed', 'oops', 3, 0),
+			(3, 'completed', NULL, 0, 0)
+	`); err != nil {
+		t.Fatalf("unexpected error inserting records: %s", err)
+	}
+
+	store := testStore(db, defaultTestStoreOptions(nil))
+
+	count, err := store.RequeueFailed(context.Background(), []int{1, 3})
+	if err != nil {
+		t.Fatalf("unexpected error requeueing failed records: %s", err)
+	}
+	if count != 1 {
+		t.Errorf("unexpected count. want=%d have=%d", 1, count)
+	}
+
+	record, ok, err := store.Dequeue(context.Background(), "test", nil)
+	assertDequeueRecordResult(t, 1, record, ok, err)
+
+	count, err = store.RequeueFailed(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("unexpected error requeueing failed records: %s", err)
+	}
+	if count != 1 {
+		t.Errorf("unexpected count. want=%d have=%d", 1, count)
+	}
+}
+
+func TestStoreDeleteFailed(t *testing.T) {
+	db := setupStoreTest(t)
+
+	if _, err := db.ExecContext(context.Background(), `
+		INSERT INTO workerutil_test (id, state)
+		VALUES
+			(1, 'failed'),
+			(2, 'failed'),
+			(3, 'queued')
+	`); err != nil {
+		t.Fatalf("unexpected error inserting records: %s", err)
+	}
+
+	store := testStore(db, defaultTestStoreOptions(nil))
+
+	count, err := store.DeleteFailed(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("unexpected error deleting failed records: %s", err)
+	}
+	if count != 2 {
+		t.Errorf("unexpected count. want=%d have=%d", 2, count)
+	}
+
+	ids, err := basestore.ScanInts(db.QueryContext(context.Background(), `SELECT id FROM workerutil_test`))
+	if err != nil {
+		t.Fatalf("unexpected error querying records: %s", err)
+	}
+	if !cmp.Equal([]int{3}, ids) {
+		t.Errorf("unexpected remaining ids. want=%v have=%v", []int{3}, ids)
+	}
+}
+
 func TestStoreRequeue(t *testing.T) {
 	db := setupStoreTest(t)
 
@@ -15,7 +15,7 @@ type storeShim struct {
 	store.Store
 }
 
-var _ workerutil.Store = &storeShim{}
+var _ workerutil.FairStore = &storeShim{}
 
 // newStoreShim wraps the given store in a shim.
 func newStoreShim(store store.Store) workerutil.Store {
@@ -46,6 +46,16 @@ func (s *storeShim) Dequeue(ctx context.Context, workerHostname string, extraArg
 	return s.Store.Dequeue(ctx, workerHostname, conditions)
 }
 
+// DequeueWithKey calls into the inner store.
+func (s *storeShim) DequeueWithKey(ctx context.Context, workerHostname string, extraArguments interface{}, excludedKeys []string) (workerutil.Record, string, bool, error) {
+	conditions, err := convertArguments(extraArguments)
+	if err != nil {
+		return nil, "", false, err
+	}
+
+	return s.Store.DequeueWithKey(ctx, workerHostname, conditions, excludedKeys)
+}
+
 func (s *storeShim) Heartbeat(ctx context.Context, ids []int) (knownIDs []int, err error) {
 	return s.Store.Heartbeat(ctx, ids, store.HeartbeatOptions{})
 }
@@ -48,6 +48,17 @@ type Store interface {
 	MarkFailed(ctx context.Context, id int, failureMessage string) (bool, error)
 }
 
+// FairStore is an optional extension of Store implemented by stores that group records by a fairness
+// key, such as the user, repository, or namespace a record belongs to.
+type FairStore interface {
+	Store
+
+	// DequeueWithKey selects a record for processing whose fairness key is not one of the given excluded keys.
+	// Any extra arguments supplied will be used in the same way as in Dequeue. This method returns the fairness
+	// key of the selected record and a boolean flag indicating the existence of a processable record.
+	DequeueWithKey(ctx context.Context, workerHostname string, extraArguments interface{}, excludedKeys []string) (Record, string, bool, error)
+}
+
 // ExecutionLogEntry represents a command run by the executor.
 type ExecutionLogEntry struct {
 	Key        string    `json:"key"`
@@ -2,6 +2,7 @@ package workerutil
 
 import (
 	"context"
+	"sort"
 	"sync"
 	"time"
 
@@ -29,6 +30,8 @@ type Worker struct {
 	wg               sync.WaitGroup  // tracks active handler routines
 	finished         chan struct{}   // signals that Start has finished
 	runningIDSet     *IDSet          // tracks the running job IDs to heartbeat
+	runningKeysMu    sync.Mutex      // protects runningKeys
+	runningKeys      map[string]int  // tracks the number of running jobs per fairness key
 }
 
 type WorkerOptions struct {
@@ -47,6 +50,13 @@ type WorkerOptions struct {
 	// number of handlers exceeds this value.
 	NumHandlers int
 
+	// MaxConcurrencyPerKey is the maximum number of handlers that can be invoked
+	// concurrently for records with the same fairness key. Records whose key has
+	// reached this limit are skipped until one of its handlers exits. This option
+	// only has an effect if the underlying store implements FairStore. A value of
+	// zero disables the limit.
+	MaxConcurrencyPerKey int
+
 	// Interval is the frequency to poll the underlying store for new work.
 	Interval time.Duration
 
@@ -89,6 +99,7 @@ func newWorker(ctx context.Context, store Store, handler Handler, options Worker
 		cancel:           cancel,
 		finished:         make(chan struct{}),
 		runningIDSet:     newIDSet(),
+		runningKeys:      map[string]int{},
 	}
 }
 
@@ -195,7 +206,7 @@ func (w *Worker) dequeueAndHandle() (dequeued bool, err error) {
 	}
 
 	// Select a queued record to process and the transaction that holds it
-	record, dequeued, err := w.store.Dequeue(w.ctx, w.options.WorkerHostname, extraDequeueArguments)
+	record, key, dequeued, err := w.dequeue(extraDequeueArguments)
 	if err != nil {
 		return false, errors.Wrap(err, "store.Dequeue")
 	}
@@ -209,6 +220,7 @@ func (w *Worker) dequeueAndHandle() (dequeued bool, err error) {
 	if !w.runningIDSet.Add(record.RecordID(), cancel) {
 		return false, ErrJobAlreadyExists
 	}
+	w.addRunningKey(key)
 
 	w.options.Metrics.numJobs.Inc()
 	log15.Debug("Dequeued record for processing", "name", w.options.Name, "id", record.RecordID())
@@ -230,6 +242,7 @@ func (w *Worker) dequeueAndHandle() (dequeued bool, err error) {
 			// Remove the record from the set of running jobs, so it is not included
 			// in heartbeat updates anymore.
 			defer w.runningIDSet.Remove(record.RecordID())
+			w.removeRunningKey(key)
 			w.options.Metrics.numJobs.Dec()
 			w.handlerSemaphore <- struct{}{}
 			w.wg.Done()
@@ -243,6 +256,48 @@ func (w *Worker) dequeueAndHandle() (dequeued bool, err error) {
 	return true, nil
 }
 
+// dequeue selects a queued record to process. If the store groups records by fairness key and a
+// per-key concurrency limit is configured, records whose key has reached the limit are skipped.
+func (w *Worker) dequeue(extraDequeueArguments interface{}) (Record, string, bool, error) {
+	if fairStore, ok := w.store.(FairStore); ok && w.options.MaxConcurrencyPerKey > 0 {
+		return fairStore.DequeueWithKey(w.ctx, w.options.WorkerHostname, extraDequeueArguments, w.saturatedKeys())
+	}
+
+	record, dequeued, err := w.store.Dequeue(w.ctx, w.options.WorkerHostname, extraDequeueArguments)
+	return record, "", dequeued, err
+}
+
+// saturatedKeys returns the fairness keys whose number of running jobs has reached the
+// configured per-key concurrency limit.
+func (w *Worker) saturatedKeys() []string {
+	w.runningKeysMu.Lock()
+	defer w.runningKeysMu.Unlock()
+
+	var keys []string
+	for key, count := range w.runningKeys {
+		if count >= w.options.MaxConcurrencyPerKey {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+func (w *Worker) addRunningKey(key string) {
+	w.runningKeysMu.Lock()
+	w.runningKeys[key]++
+	w.runningKeysMu.Unlock()
+}
+
+func (w *Worker) removeRunningKey(key string) {
+	w.runningKeysMu.Lock()
+	if w.runningKeys[key]--; w.runningKeys[key] <= 0 {
+		delete(w.runningKeys, key)
+	}
+	w.runningKeysMu.Unlock()
+}
+
 // handle processes the given record. This method returns an error only if there is an issue updating
 // the record to a terminal state - no handler errors will bubble up.
 func (w *Worker) handle(ctx context.Context, record Record) (err error) {
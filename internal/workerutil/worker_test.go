@@ -9,6 +9,7 @@ import (
 
 	"github.com/cockroachdb/errors"
 	"github.com/derision-test/glock"
+	"github.com/google/go-cmp/cmp"
 
 	"github.com/sourcegraph/sourcegraph/internal/observation"
 )
@@ -304,6 +305,80 @@ func TestWorkerConditionalPreDequeueHook(t *testing.T) {
 	}
 }
 
+func TestWorkerMaxConcurrencyPerKey(t *testing.T) {
+	store := NewMockFairStore()
+	handler := NewMockHandler()
+	clock := glock.NewMockClock()
+	options := WorkerOptions{
+		Name:                 "test",
+		WorkerHostname:       "test",
+		NumHandlers:          3,
+		MaxConcurrencyPerKey: 1,
+		Interval:             time.Second,
+		Metrics:              NewMetrics(&observation.TestContext, "", nil),
+	}
+
+	store.DequeueWithKeyFunc.PushReturn(TestRecord{ID: 42}, "a", true, nil)
+	store.DequeueWithKeyFunc.PushReturn(TestRecord{ID: 43}, "b", true, nil)
+	store.DequeueWithKeyFunc.SetDefaultReturn(nil, "", false, nil)
+
+	// Keep handlers running so that their keys stay saturated
+	unblock := make(chan struct{})
+	handler.HandleFunc.SetDefaultHook(func(context.Context, Record) error {
+		<-unblock
+		return nil
+	})
+
+	worker := newWorker(context.Background(), store, handler, options, clock)
+	go func() { worker.Start() }()
+	clock.BlockingAdvance(time.Second)
+	clock.BlockingAdvance(time.Second)
+	clock.BlockingAdvance(time.Second)
+	close(unblock)
+	worker.Stop()
+
+	if callCount := len(store.DequeueFunc.History()); callCount != 0 {
+		t.Errorf("unexpected dequeue call count. want=%d have=%d", 0, callCount)
+	}
+
+	history := store.DequeueWithKeyFunc.History()
+	if len(history) < 3 {
+		t.Fatalf("unexpected dequeue with key call count. want>=%d have=%d", 3, len(history))
+	}
+	for i, expected := range [][]string{nil, {"a"}, {"a", "b"}} {
+		if diff := cmp.Diff(expected, history[i].Arg3); diff != "" {
+			t.Errorf("unexpected excluded keys for dequeue call %d (-want +got):\n%s", i, diff)
+		}
+	}
+}
+
+func TestWorkerMaxConcurrencyPerKeyUnsupportedStore(t *testing.T) {
+	store := NewMockStore()
+	handler := NewMockHandler()
+	clock := glock.NewMockClock()
+	options := WorkerOptions{
+		Name:                 "test",
+		WorkerHostname:       "test",
+		NumHandlers:          1,
+		MaxConcurrencyPerKey: 1,
+		Interval:             time.Second,
+		Metrics:              NewMetrics(&observation.TestContext, "", nil),
+	}
+
+	store.DequeueFunc.PushReturn(TestRecord{ID: 42}, true, nil)
+	store.DequeueFunc.SetDefaultReturn(nil, false, nil)
+	store.MarkCompleteFunc.SetDefaultReturn(true, nil)
+
+	worker := newWorker(context.Background(), store, handler, options, clock)
+	go func() { worker.Start() }()
+	clock.BlockingAdvance(time.Second)
+	worker.Stop()
+
+	if callCount := len(handler.HandleFunc.History()); callCount != 1 {
+		t.Errorf("unexpected handle call count. want=%d have=%d", 1, callCount)
+	}
+}
+
 type MockHandlerWithPreDequeue struct {
 	*MockHandler
 	*MockWithPreDequeue
@@ -0,0 +1,88 @@
+package inference
+
+import (
+	"path/filepath"
+	"regexp"
+
+	"github.com/sourcegraph/sourcegraph/lib/codeintel/autoindex/config"
+)
+
+func ClangPatterns() []*regexp.Regexp {
+	return []*regexp.Regexp{
+		pathPattern(rawPattern("compile_commands.json")),
+		pathPattern(rawPattern("CMakeLists.txt")),
+	}
+}
+
+func CanIndexClangRepo(gitclient GitClient, paths []string) bool {
+	for _, path := range paths {
+		if isCompilationDatabasePath(path) || isCMakeListsPath(path) {
+			return true
+		}
+	}
+
+	return false
+}
+
+const lsifClangImage = "sourcegraph/lsif-clang:latest"
+
+func InferClangIndexJobs(gitclient GitClient, paths []string) (indexes []config.IndexJob) {
+	for _, path := range paths {
+		if !isCompilationDatabasePath(path) {
+			continue
+		}
+
+		indexes = append(indexes, config.IndexJob{
+			Steps:       nil,
+			Root:        dirWithoutDot(path),
+			Indexer:     lsifClangImage,
+			IndexerArgs: []string{"lsif-clang", "compile_commands.json"},
+			Outfile:     "dump.lsif",
+		})
+	}
+	if len(indexes) > 0 {
+		return indexes
+	}
+
+	// Without a checked-in compilation database, generate one with CMake.
+	// Nested CMakeLists.txt files are usually included from their parent
+	// project via add_subdirectory, so only the outermost ones are roots.
+	var roots []string
+	for _, path := range paths {
+		if isCMakeListsPath(path) {
+			roots = append(roots, dirWithoutDot(path))
+		}
+	}
+
+	for _, root := range roots {
+		if hasAncestorRoot(root, roots) {
+			continue
+		}
+
+		indexes = append(indexes, config.IndexJob{
+			Steps: []config.DockerStep{
+				{
+					Root:     root,
+					Image:    lsifClangImage,
+					Commands: []string{"cmake -B build -DCMAKE_EXPORT_COMPILE_COMMANDS=ON"},
+				},
+			},
+			Root:        root,
+			Indexer:     lsifClangImage,
+			IndexerArgs: []string{"lsif-clang", "--project-root=.", "build/compile_commands.json"},
+			Outfile:     "dump.lsif",
+		})
+	}
+
+	return indexes
+}
+
+var clangSegmentBlockList = append([]string{"third_party", "vendor"}, segmentBlockList...)
+
+func isCompilationDatabasePath(path string) bool {
+	return filepath.Base(path) == "compile_commands.json" && containsNoSegments(path, clangSegmentBlockList...)
+}
+
+func isCMakeListsPath(path string) bool {
+	return filepath.Base(path) == "CMakeLists.txt" && containsNoSegments(path, clangSegmentBlockList...)
+}
@@ -0,0 +1,122 @@
+package inference
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/lib/codeintel/autoindex/config"
+)
+
+func TestClangPatterns(t *testing.T) {
+	testCases := []struct {
+		path     string
+		expected bool
+	}{
+		{"compile_commands.json", true},
+		{"build/compile_commands.json", true},
+		{"CMakeLists.txt", true},
+		{"src/CMakeLists.txt", true},
+		{"CMakeLists.txt/subdir", false},
+		{"main.cpp", false},
+	}
+
+	for _, testCase := range testCases {
+		match := false
+		for _, pattern := range ClangPatterns() {
+			if pattern.MatchString(testCase.path) {
+				match = true
+				break
+			}
+		}
+
+		if match {
+			if !testCase.expected {
+				t.Error(fmt.Sprintf("did not expect match: %s", testCase.path))
+			}
+
+		} else if testCase.expected {
+			t.Error(fmt.Sprintf("expected match: %s", testCase.path))
+		}
+	}
+}
+
+func TestCanIndexClangRepo(t *testing.T) {
+	testCases := []struct {
+		paths    []string
+		expected bool
+	}{
+		{paths: []string{"compile_commands.json"}, expected: true},
+		{paths: []string{"a/CMakeLists.txt"}, expected: true},
+		{paths: []string{"Makefile"}, expected: false},
+		{paths: []string{"third_party/foo/CMakeLists.txt"}, expected: false},
+		{paths: []string{"examples/foo/compile_commands.json"}, expected: false},
+	}
+
+	for _, testCase := range testCases {
+		name := strings.Join(testCase.paths, ", ")
+
+		t.Run(name, func(t *testing.T) {
+			if value := CanIndexClangRepo(NewMockGitClient(), testCase.paths); value != testCase.expected {
+				t.Errorf("unexpected result from CanIndex. want=%v have=%v", testCase.expected, value)
+			}
+		})
+	}
+}
+
+func TestInferClangIndexJobsCompilationDatabase(t *testing.T) {
+	paths := []string{
+		"CMakeLists.txt",
+		"compile_commands.json",
+		"lib/compile_commands.json",
+	}
+
+	expectedIndexJobs := []config.IndexJob{
+		{
+			Steps:       nil,
+			Root:        "",
+			Indexer:     lsifClangImage,
+			IndexerArgs: []string{"lsif-clang", "compile_commands.json"},
+			Outfile:     "dump.lsif",
+		},
+		{
+			Steps:       nil,
+			Root:        "lib",
+			Indexer:     lsifClangImage,
+			IndexerArgs: []string{"lsif-clang", "compile_commands.json"},
+			Outfile:     "dump.lsif",
+		},
+	}
+	if diff := cmp.Diff(expectedIndexJobs, InferClangIndexJobs(NewMockGitClient(), paths)); diff != "" {
+		t.Errorf("unexpected index jobs (-want +got):\n%s", diff)
+	}
+}
+
+func TestInferClangIndexJobsCMake(t *testing.T) {
+	paths := []string{
+		"CMakeLists.txt",
+		"src/CMakeLists.txt",
+		"third_party/zlib/CMakeLists.txt",
+	}
+
+	expectedIndexJobs := []config.IndexJob{
+		{
+			Steps: []config.DockerStep{
+				{
+					Root:     "",
+					Image:    lsifClangImage,
+					Commands: []string{"cmake -B build -DCMAKE_EXPORT_COMPILE_COMMANDS=ON"},
+				},
+			},
+			Root:        "",
+			Indexer:     lsifClangImage,
+			IndexerArgs: []string{"lsif-clang", "--project-root=.", "build/compile_commands.json"},
+			Outfile:     "dump.lsif",
+		},
+	}
+	if diff := cmp.Diff(expectedIndexJobs, InferClangIndexJobs(NewMockGitClient(), paths)); diff != "" {
+		t.Errorf("unexpected index jobs (-want +got):\n%s", diff)
+	}
+}
@@ -25,6 +25,20 @@ func ancestorDirs(path string) (ancestors []string) {
 	return ancestors
 }
 
+// hasAncestorRoot returns true if any strict ancestor of the given directory
+// is one of the given roots.
+func hasAncestorRoot(root string, roots []string) bool {
+	if root == "" {
+		return false
+	}
+	for _, dir := range ancestorDirs(root) {
+		if contains(roots, dir) {
+			return true
+		}
+	}
+	return false
+}
+
 // containsSegment returns true if the given path contains the given segment.
 func containsSegment(path, segment string) bool {
 	if path == "" {
@@ -37,6 +37,26 @@ func TestAncestorDirs(t *testing.T) {
 	}
 }
 
+func TestHasAncestorRoot(t *testing.T) {
+	testCases := []struct {
+		root     string
+		roots    []string
+		expected bool
+	}{
+		{"", []string{""}, false},
+		{"foo", []string{""}, true},
+		{"foo/bar", []string{"foo"}, true},
+		{"foo/bar", []string{"foo/bar"}, false},
+		{"foo/bar", []string{"baz"}, false},
+	}
+
+	for _, testCase := range testCases {
+		if value := hasAncestorRoot(testCase.root, testCase.roots); value != testCase.expected {
+			t.Errorf("unexpected result for %q in %v: want=%v have=%v", testCase.root, testCase.roots, testCase.expected, value)
+		}
+	}
+}
+
 func TestContainsSegment(t *testing.T) {
 	testCases := []struct {
 		path     string
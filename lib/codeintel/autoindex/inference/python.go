@@ -0,0 +1,74 @@
+package inference
+
+import (
+	"path/filepath"
+	"regexp"
+
+	"github.com/sourcegraph/sourcegraph/lib/codeintel/autoindex/config"
+)
+
+func PythonPatterns() []*regexp.Regexp {
+	return []*regexp.Regexp{
+		pathPattern(rawPattern("setup.py")),
+		pathPattern(rawPattern("pyproject.toml")),
+		pathPattern(rawPattern("requirements.txt")),
+	}
+}
+
+func CanIndexPythonRepo(gitclient GitClient, paths []string) bool {
+	for _, path := range paths {
+		if isPythonProjectPath(path) {
+			return true
+		}
+	}
+
+	return false
+}
+
+const lsifPyImage = "sourcegraph/lsif-py:latest"
+
+func InferPythonIndexJobs(gitclient GitClient, paths []string) (indexes []config.IndexJob) {
+	seen := map[string]struct{}{}
+	for _, path := range paths {
+		if !isPythonProjectPath(path) {
+			continue
+		}
+
+		// A project may declare itself with both a setup.py and a
+		// pyproject.toml file, which must result in a single index job.
+		root := dirWithoutDot(path)
+		if _, ok := seen[root]; ok {
+			continue
+		}
+		seen[root] = struct{}{}
+
+		var commands []string
+		if contains(paths, filepath.Join(root, "requirements.txt")) {
+			commands = append(commands, "pip install -r requirements.txt")
+		}
+		commands = append(commands, "pip install .")
+
+		indexes = append(indexes, config.IndexJob{
+			Steps: []config.DockerStep{
+				{
+					Root:     root,
+					Image:    lsifPyImage,
+					Commands: commands,
+				},
+			},
+			Root:        root,
+			Indexer:     lsifPyImage,
+			IndexerArgs: []string{"lsif-py", ".", "--file", "dump.lsif"},
+			Outfile:     "dump.lsif",
+		})
+	}
+
+	return indexes
+}
+
+var pythonSegmentBlockList = append([]string{"venv", ".venv", "site-packages"}, segmentBlockList...)
+
+func isPythonProjectPath(path string) bool {
+	base := filepath.Base(path)
+	return (base == "setup.py" || base == "pyproject.toml") && containsNoSegments(path, pythonSegmentBlockList...)
+}
@@ -0,0 +1,83 @@
+package inference
+
+import (
+	"context"
+	"path/filepath"
+	"regexp"
+
+	"github.com/sourcegraph/sourcegraph/lib/codeintel/autoindex/config"
+)
+
+func RustPatterns() []*regexp.Regexp {
+	return []*regexp.Regexp{
+		pathPattern(rawPattern("Cargo.toml")),
+	}
+}
+
+func CanIndexRustRepo(gitclient GitClient, paths []string) bool {
+	for _, path := range paths {
+		if isCargoManifestPath(path) {
+			return true
+		}
+	}
+
+	return false
+}
+
+const lsifRustImage = "sourcegraph/lsif-rust:latest"
+
+func InferRustIndexJobs(gitclient GitClient, paths []string) (indexes []config.IndexJob) {
+	var workspaceRoots []string
+	for _, path := range paths {
+		if isCargoManifestPath(path) && isCargoWorkspace(gitclient, path) {
+			workspaceRoots = append(workspaceRoots, dirWithoutDot(path))
+		}
+	}
+
+	for _, path := range paths {
+		if !isCargoManifestPath(path) {
+			continue
+		}
+
+		// Crates nested in a workspace are indexed as part of the
+		// workspace, as cargo resolves them from the workspace root.
+		root := dirWithoutDot(path)
+		if hasAncestorRoot(root, workspaceRoots) {
+			continue
+		}
+
+		indexes = append(indexes, config.IndexJob{
+			Steps: []config.DockerStep{
+				{
+					Root:     root,
+					Image:    lsifRustImage,
+					Commands: []string{"cargo fetch"},
+				},
+			},
+			Root:        root,
+			Indexer:     lsifRustImage,
+			IndexerArgs: []string{"lsif-rust", "index"},
+			Outfile:     "dump.lsif",
+		})
+	}
+
+	return indexes
+}
+
+// cargoWorkspacePattern matches the header of the workspace table of a Cargo
+// manifest. See https://doc.rust-lang.org/cargo/reference/workspaces.html.
+var cargoWorkspacePattern = regexp.MustCompile(`(?m)^\s*\[workspace\]\s*(#.*)?$`)
+
+func isCargoWorkspace(gitclient GitClient, path string) bool {
+	b, err := gitclient.RawContents(context.TODO(), path)
+	if err != nil {
+		return false
+	}
+	return cargoWorkspacePattern.Match(b)
+}
+
+var rustSegmentBlockList = append([]string{"target", "vendor"}, segmentBlockList...)
+
+func isCargoManifestPath(path string) bool {
+	return filepath.Base(path) == "Cargo.toml" && containsNoSegments(path, rustSegmentBlockList...)
+}
@@ -0,0 +1,145 @@
+package inference
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/lib/codeintel/autoindex/config"
+)
+
+func TestRustPatterns(t *testing.T) {
+	testCases := []struct {
+		path     string
+		expected bool
+	}{
+		{"Cargo.toml", true},
+		{"subdir/Cargo.toml", true},
+		{"Cargo.lock", false},
+		{"Cargo.toml/subdir", false},
+		{"main.rs", false},
+	}
+
+	for _, testCase := range testCases {
+		match := false
+		for _, pattern := range RustPatterns() {
+			if pattern.MatchString(testCase.path) {
+				match = true
+				break
+			}
+		}
+
+		if match {
+			if !testCase.expected {
+				t.Error(fmt.Sprintf("did not expect match: %s", testCase.path))
+			}
+
+		} else if testCase.expected {
+			t.Error(fmt.Sprintf("expected match: %s", testCase.path))
+		}
+	}
+}
+
+func TestCanIndexRustRepo(t *testing.T) {
+	testCases := []struct {
+		paths    []string
+		expected bool
+	}{
+		{paths: []string{"Cargo.toml"}, expected: true},
+		{paths: []string{"a/Cargo.toml"}, expected: true},
+		{paths: []string{"go.mod"}, expected: false},
+		{paths: []string{"vendor/foo/Cargo.toml"}, expected: false},
+		{paths: []string{"target/package/foo/Cargo.toml"}, expected: false},
+	}
+
+	for _, testCase := range testCases {
+		name := strings.Join(testCase.paths, ", ")
+
+		t.Run(name, func(t *testing.T) {
+			if value := CanIndexRustRepo(NewMockGitClient(), testCase.paths); value != testCase.expected {
+				t.Errorf("unexpected result from CanIndex. want=%v have=%v", testCase.expected, value)
+			}
+		})
+	}
+}
+
+func TestInferRustIndexJobs(t *testing.T) {
+	mockGit := NewMockGitClient()
+	mockGit.RawContentsFunc.SetDefaultHook(func(ctx context.Context, file string) ([]byte, error) {
+		switch file {
+		case "Cargo.toml":
+			return []byte("[workspace]\nmembers = [\"crates/*\"]\n"), nil
+		case "tools/Cargo.toml":
+			return []byte("[package]\nname = \"tools\"\n\n[dependencies]\n"), nil
+		}
+		return nil, nil
+	})
+
+	paths := []string{
+		"Cargo.toml",
+		"crates/a/Cargo.toml",
+		"crates/b/Cargo.toml",
+		"tools/Cargo.toml",
+	}
+
+	expectedIndexJobs := []config.IndexJob{
+		{
+			Steps: []config.DockerStep{
+				{
+					Root:     "",
+					Image:    lsifRustImage,
+					Commands: []string{"cargo fetch"},
+				},
+			},
+			Root:        "",
+			Indexer:     lsifRustImage,
+			IndexerArgs: []string{"lsif-rust", "index"},
+			Outfile:     "dump.lsif",
+		},
+	}
+	if diff := cmp.Diff(expectedIndexJobs, InferRustIndexJobs(mockGit, paths)); diff != "" {
+		t.Errorf("unexpected index jobs (-want +got):\n%s", diff)
+	}
+}
+
+func TestInferRustIndexJobsWithoutWorkspace(t *testing.T) {
+	paths := []string{
+		"a/Cargo.toml",
+		"b/Cargo.toml",
+	}
+
+	expectedIndexJobs := []config.IndexJob{
+		{
+			Steps: []config.DockerStep{
+				{
+					Root:     "a",
+					Image:    lsifRustImage,
+					Commands: []string{"cargo fetch"},
+				},
+			},
+			Root:        "a",
+			Indexer:     lsifRustImage,
+			IndexerArgs: []string{"lsif-rust", "index"},
+			Outfile:     "dump.lsif",
+		},
+		{
+			Steps: []config.DockerStep{
+				{
+					Root:     "b",
+					Image:    lsifRustImage,
+					Commands: []string{"cargo fetch"},
+				},
+			},
+			Root:        "b",
+			Indexer:     lsifRustImage,
+			IndexerArgs: []string{"lsif-rust", "index"},
+			Outfile:     "dump.lsif",
+		},
+	}
+	if diff := cmp.Diff(expectedIndexJobs, InferRustIndexJobs(NewMockGitClient(), paths)); diff != "" {
+		t.Errorf("unexpected index jobs (-want +got):\n%s", diff)
+	}
+}
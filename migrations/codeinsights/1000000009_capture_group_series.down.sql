@@ -0,0 +1,8 @@
+BEGIN;
+
+DROP TABLE IF EXISTS insight_series_captures;
+
+ALTER TABLE series_points DROP COLUMN IF EXISTS capture;
+ALTER TABLE insight_series DROP COLUMN IF EXISTS generated_from_capture_groups;
+
+COMMIT;
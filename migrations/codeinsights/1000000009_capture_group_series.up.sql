@@ -0,0 +1,26 @@
+BEGIN;
+
+ALTER TABLE insight_series
+    ADD COLUMN generated_from_capture_groups BOOLEAN NOT NULL DEFAULT FALSE;
+
+comment on column insight_series.generated_from_capture_groups is 'When true, the values of the first capture group of the regexp query are recorded as separate series.';
+
+ALTER TABLE series_points
+    ADD COLUMN capture TEXT;
+
+comment on column series_points.capture is 'If the series was generated from capture groups, the value of the capture group this data point was recorded for.';
+
+CREATE TABLE insight_series_captures
+(
+    series_id     TEXT      NOT NULL,
+    capture       TEXT      NOT NULL,
+    first_seen_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (series_id, capture)
+);
+
+comment on table insight_series_captures is 'Capture group values discovered for series generated from capture groups.';
+comment on column insight_series_captures.series_id is 'Unique Series ID of the series the capture value was discovered for.';
+comment on column insight_series_captures.capture is 'The value of the capture group.';
+comment on column insight_series_captures.first_seen_at is 'Timestamp when this capture value was first recorded.';
+
+COMMIT;
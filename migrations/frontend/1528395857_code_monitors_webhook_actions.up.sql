@@ -0,0 +1,57 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS cm_slack_webhooks (
+    id BIGSERIAL PRIMARY KEY,
+    monitor BIGINT NOT NULL,
+    url TEXT NOT NULL,
+    enabled BOOLEAN NOT NULL,
+    created_by INTEGER NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    changed_by INTEGER NOT NULL,
+    changed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT cm_slack_webhooks_monitor_fk FOREIGN KEY (monitor) REFERENCES cm_monitors(id) ON DELETE CASCADE,
+    CONSTRAINT cm_slack_webhooks_created_by_fk FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT cm_slack_webhooks_changed_by_fk FOREIGN KEY (changed_by) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS cm_slack_webhooks_monitor ON cm_slack_webhooks (monitor);
+
+COMMENT ON COLUMN cm_slack_webhooks.url IS 'The Slack incoming webhook URL that notifications are posted to.';
+
+CREATE TABLE IF NOT EXISTS cm_webhooks (
+    id BIGSERIAL PRIMARY KEY,
+    monitor BIGINT NOT NULL,
+    url TEXT NOT NULL,
+    enabled BOOLEAN NOT NULL,
+    created_by INTEGER NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    changed_by INTEGER NOT NULL,
+    changed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT cm_webhooks_monitor_fk FOREIGN KEY (monitor) REFERENCES cm_monitors(id) ON DELETE CASCADE,
+    CONSTRAINT cm_webhooks_created_by_fk FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT cm_webhooks_changed_by_fk FOREIGN KEY (changed_by) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS cm_webhooks_monitor ON cm_webhooks (monitor);
+
+COMMENT ON COLUMN cm_webhooks.url IS 'The URL that the JSON payload of a notification is posted to.';
+
+ALTER TABLE cm_action_jobs
+    ALTER COLUMN email DROP NOT NULL,
+    ADD COLUMN IF NOT EXISTS slack_webhook BIGINT,
+    ADD COLUMN IF NOT EXISTS webhook BIGINT,
+    ADD CONSTRAINT cm_action_jobs_slack_webhook_fk FOREIGN KEY (slack_webhook) REFERENCES cm_slack_webhooks(id) ON DELETE CASCADE,
+    ADD CONSTRAINT cm_action_jobs_webhook_fk FOREIGN KEY (webhook) REFERENCES cm_webhooks(id) ON DELETE CASCADE,
+    ADD CONSTRAINT cm_action_jobs_only_one_action_type CHECK (
+        (
+            CASE WHEN email IS NULL THEN 0 ELSE 1 END +
+            CASE WHEN slack_webhook IS NULL THEN 0 ELSE 1 END +
+            CASE WHEN webhook IS NULL THEN 0 ELSE 1 END
+        ) = 1
+    );
+
+ALTER TABLE cm_trigger_jobs ADD COLUMN IF NOT EXISTS search_results JSONB;
+
+COMMENT ON COLUMN cm_trigger_jobs.search_results IS 'The search results of the run, which are included in the payload of webhook actions.';
+
+COMMIT;
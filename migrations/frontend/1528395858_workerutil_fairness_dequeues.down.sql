@@ -0,0 +1,5 @@
+BEGIN;
+
+DROP TABLE IF EXISTS workerutil_fairness_dequeues;
+
+COMMIT;
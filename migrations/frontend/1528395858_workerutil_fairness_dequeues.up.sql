@@ -0,0 +1,14 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS workerutil_fairness_dequeues (
+    queue_name text NOT NULL,
+    fairness_key text NOT NULL,
+    last_dequeued_at timestamp with time zone NOT NULL,
+    PRIMARY KEY (queue_name, fairness_key)
+);
+
+COMMENT ON TABLE workerutil_fairness_dequeues IS 'Tracks when a record was last dequeued for each fairness key of a workerutil queue, so that dequeues can round-robin across keys.';
+COMMENT ON COLUMN workerutil_fairness_dequeues.queue_name IS 'The name of the dbworker store the key belongs to.';
+COMMENT ON COLUMN workerutil_fairness_dequeues.fairness_key IS 'The value of the store''s fairness key expression, e.g. a user, repository, or namespace identifier.';
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE search_contexts DROP COLUMN IF EXISTS query_repos_updated_at;
+ALTER TABLE search_contexts DROP COLUMN IF EXISTS query;
+
+COMMIT;
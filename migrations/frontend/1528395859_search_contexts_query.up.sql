@@ -0,0 +1,9 @@
+BEGIN;
+
+ALTER TABLE search_contexts ADD COLUMN IF NOT EXISTS query text;
+ALTER TABLE search_contexts ADD COLUMN IF NOT EXISTS query_repos_updated_at timestamp with time zone;
+
+COMMENT ON COLUMN search_contexts.query IS 'Search query that defines the repositories of the search context. If set, search_context_repos caches the repositories that match it.';
+COMMENT ON COLUMN search_contexts.query_repos_updated_at IS 'When the repositories matching the query were last resolved.';
+
+COMMIT;
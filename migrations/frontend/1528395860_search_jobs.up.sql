@@ -0,0 +1,64 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS search_jobs (
+    id                bigserial PRIMARY KEY,
+    query             text NOT NULL,
+    pattern_type      text NOT NULL,
+    initiator_id      integer NOT NULL REFERENCES users(id) ON DELETE CASCADE DEFERRABLE,
+    state             text DEFAULT 'queued',
+    failure_message   text,
+    started_at        timestamp with time zone,
+    finished_at       timestamp with time zone,
+    process_after     timestamp with time zone,
+    num_resets        integer NOT NULL DEFAULT 0,
+    num_failures      integer NOT NULL DEFAULT 0,
+    execution_logs    json[],
+    worker_hostname   text NOT NULL DEFAULT '',
+    last_heartbeat_at timestamp with time zone,
+    canceled_at       timestamp with time zone,
+    created_at        timestamp with time zone NOT NULL DEFAULT now(),
+    updated_at        timestamp with time zone NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS search_jobs_initiator_id ON search_jobs (initiator_id);
+CREATE INDEX IF NOT EXISTS search_jobs_state ON search_jobs (state);
+
+COMMENT ON TABLE search_jobs IS 'Long-running searches that are run exhaustively, repository by repository, by the worker.';
+COMMENT ON COLUMN search_jobs.canceled_at IS 'When the search job was canceled. Repositories that were not searched yet are not searched anymore.';
+
+CREATE TABLE IF NOT EXISTS search_job_repos (
+    id                bigserial PRIMARY KEY,
+    search_job_id     bigint NOT NULL REFERENCES search_jobs(id) ON DELETE CASCADE,
+    repo_id           integer NOT NULL REFERENCES repo(id) ON DELETE CASCADE,
+    state             text DEFAULT 'queued',
+    failure_message   text,
+    started_at        timestamp with time zone,
+    finished_at       timestamp with time zone,
+    process_after     timestamp with time zone,
+    num_resets        integer NOT NULL DEFAULT 0,
+    num_failures      integer NOT NULL DEFAULT 0,
+    execution_logs    json[],
+    worker_hostname   text NOT NULL DEFAULT '',
+    last_heartbeat_at timestamp with time zone,
+    num_results       integer NOT NULL DEFAULT 0
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS search_job_repos_search_job_id_repo_id ON search_job_repos (search_job_id, repo_id);
+CREATE INDEX IF NOT EXISTS search_job_repos_state ON search_job_repos (state);
+
+COMMENT ON TABLE search_job_repos IS 'The repositories searched by a search job. Each repository is searched by a separate worker record, so that a search job resumes where it left off.';
+
+CREATE TABLE IF NOT EXISTS search_job_results (
+    id                 bigserial PRIMARY KEY,
+    search_job_id      bigint NOT NULL REFERENCES search_jobs(id) ON DELETE CASCADE,
+    search_job_repo_id bigint NOT NULL REFERENCES search_job_repos(id) ON DELETE CASCADE,
+    result             jsonb NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS search_job_results_search_job_id ON search_job_results (search_job_id, id);
+CREATE INDEX IF NOT EXISTS search_job_results_search_job_repo_id ON search_job_results (search_job_repo_id);
+
+COMMENT ON TABLE search_job_results IS 'The results of search jobs.';
+COMMENT ON COLUMN search_job_results.result IS 'A single search result, in the format of search result exports.';
+
+COMMIT;
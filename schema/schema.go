@@ -33,6 +33,8 @@ type AWSCodeCommitConnection struct {
 	RepositoryPathPattern string `json:"repositoryPathPattern,omitempty"`
 	// SecretAccessKey description: The AWS secret access key (that corresponds to the AWS access key ID set in `accessKeyID`).
 	SecretAccessKey string `json:"secretAccessKey"`
+	// Webhooks description: An array of webhook configurations. Webhooks are used by batch changes to receive pull request updates from Amazon EventBridge as they happen.
+	Webhooks []*AWSCodeCommitWebhook `json:"webhooks,omitempty"`
 }
 
 // AWSCodeCommitGitCredentials description: The Git credentials used for authentication when cloning an AWS CodeCommit repository over HTTPS.
@@ -45,6 +47,10 @@ type AWSCodeCommitGitCredentials struct {
 	// Username description: The Git username
 	Username string `json:"username"`
 }
+type AWSCodeCommitWebhook struct {
+	// Secret description: The secret used to authenticate incoming webhook requests
+	Secret string `json:"secret"`
+}
 
 // AWSKMSEncryptionKey description: AWS KMS Encryption Key, used to encrypt data in AWS environments
 type AWSKMSEncryptionKey struct {
@@ -168,12 +174,18 @@ type BatchSpec struct {
 	Workspaces []*WorkspaceConfiguration `json:"workspaces,omitempty"`
 }
 
+// BitbucketCloudAuthorization description: If non-null, enforces Bitbucket Cloud repository permissions for the workspaces listed in "teams". The configured user must be an administrator of these workspaces. Sourcegraph assumes usernames are identical to Bitbucket Cloud nicknames, so `auth.enableUsernameChanges` must be set to false for security reasons.
+type BitbucketCloudAuthorization struct {
+}
+
 // BitbucketCloudConnection description: Configuration for a connection to Bitbucket Cloud.
 type BitbucketCloudConnection struct {
 	// ApiURL description: The API URL of Bitbucket Cloud, such as https://api.bitbucket.org. Generally, admin should not modify the value of this option because Bitbucket Cloud is a public hosting platform.
 	ApiURL string `json:"apiURL,omitempty"`
 	// AppPassword description: The app password to use when authenticating to the Bitbucket Cloud. Also set the corresponding "username" field.
 	AppPassword string `json:"appPassword"`
+	// Authorization description: If non-null, enforces Bitbucket Cloud repository permissions for the workspaces listed in "teams". The configured user must be an administrator of these workspaces. Sourcegraph assumes usernames are identical to Bitbucket Cloud nicknames, so `auth.enableUsernameChanges` must be set to false for security reasons.
+	Authorization *BitbucketCloudAuthorization `json:"authorization,omitempty"`
 	// Exclude description: A list of repositories to never mirror from Bitbucket Cloud. Takes precedence over "teams" configuration.
 	//
 	// Supports excluding by name ({"name": "myorg/myrepo"}) or by UUID ({"uuid": "{fceb73c7-cef6-4abe-956d-e471281126bd}"}).
@@ -200,6 +212,8 @@ type BitbucketCloudConnection struct {
 	Url string `json:"url"`
 	// Username description: The username to use when authenticating to the Bitbucket Cloud. Also set the corresponding "appPassword" field.
 	Username string `json:"username"`
+	// Webhooks description: An array of webhook configurations. Webhooks are used by batch changes to receive pull request updates as they happen.
+	Webhooks []*BitbucketCloudWebhook `json:"webhooks,omitempty"`
 }
 
 // BitbucketCloudRateLimit description: Rate limit applied when making background API requests to Bitbucket Cloud.
@@ -209,6 +223,10 @@ type BitbucketCloudRateLimit struct {
 	// RequestsPerHour description: Requests per hour permitted. This is an average, calculated per second. Internally, the burst limit is set to 500, which implies that for a requests per hour limit as low as 1, users will continue to be able to send a maximum of 500 requests immediately, provided that the complexity cost of each request is 1.
 	RequestsPerHour float64 `json:"requestsPerHour"`
 }
+type BitbucketCloudWebhook struct {
+	// Secret description: The secret used to authenticate incoming webhook requests
+	Secret string `json:"secret"`
+}
 
 // BitbucketServerAuthorization description: If non-null, enforces Bitbucket Server repository permissions.
 type BitbucketServerAuthorization struct {
@@ -237,7 +255,8 @@ type BitbucketServerConnection struct {
 	// If "ssh", Sourcegraph will access Bitbucket Server repositories using Git URLs of the form ssh://git@example.bitbucket.com/myproject/myrepo.git. See the documentation for how to provide SSH private keys and known_hosts: https://docs.sourcegraph.com/admin/repo/auth#repositories-that-need-http-s-or-ssh-authentication.
 	GitURLType string `json:"gitURLType,omitempty"`
 	// InitialRepositoryEnablement description: Deprecated and ignored field which will be removed entirely in the next release. BitBucket repositories can no longer be enabled or disabled explicitly.
-	InitialRepositoryEnablement bool `json:"initialRepositoryEnablement,omitempty"`
+	InitialRepositoryEnablement bool                `json:"initialRepositoryEnablement,omitempty"`
+	PartialClone                []*PartialCloneRule `json:"partialClone,omitempty"`
 	// Password description: The password to use when authenticating to the Bitbucket Server instance. Also set the corresponding "username" field.
 	//
 	// For Bitbucket Server instances that support personal access tokens (Bitbucket Server version 5.5 and newer), it is recommended to provide a token instead (in the "token" field).
@@ -484,6 +503,12 @@ type ExcludedBitbucketServerRepo struct {
 	// Pattern description: Regular expression which matches against the name of a Bitbucket Server repo.
 	Pattern string `json:"pattern,omitempty"`
 }
+type ExcludedGerritProject struct {
+	// Name description: The name of a Gerrit project to exclude from mirroring.
+	Name string `json:"name,omitempty"`
+	// Pattern description: Regular expression which matches against the name of a Gerrit project.
+	Pattern string `json:"pattern,omitempty"`
+}
 type ExcludedGitHubRepo struct {
 	// Archived description: If set to true, archived repositories will be excluded.
 	Archived bool `json:"archived,omitempty"`
@@ -502,6 +527,14 @@ type ExcludedGitLabProject struct {
 	// Name description: The name of a GitLab project ("group/name") to exclude from mirroring.
 	Name string `json:"name,omitempty"`
 }
+type ExcludedGiteaRepo struct {
+	// Id description: The ID of a Gitea repository (as returned by the Gitea instance's API) to exclude from mirroring.
+	Id int `json:"id,omitempty"`
+	// Name description: The name of a Gitea repository ("owner/name") to exclude from mirroring.
+	Name string `json:"name,omitempty"`
+	// Pattern description: Regular expression which matches against the name of a Gitea repository ("owner/name").
+	Pattern string `json:"pattern,omitempty"`
+}
 type ExcludedGitoliteRepo struct {
 	// Name description: The name of a Gitolite repo ("my-repo") to exclude from mirroring.
 	Name string `json:"name,omitempty"`
@@ -533,8 +566,14 @@ type ExperimentalFeatures struct {
 	EnablePostSignupFlow bool `json:"enablePostSignupFlow,omitempty"`
 	// EventLogging description: Enables user event logging inside of the Sourcegraph instance. This will allow admins to have greater visibility of user activity, such as frequently viewed pages, frequent searches, and more. These event logs (and any specific user actions) are only stored locally, and never leave this Sourcegraph instance.
 	EventLogging string `json:"eventLogging,omitempty"`
+	// GitServerConsistentHashing description: Assign repositories to gitserver instances with consistent (rendezvous) hashing instead of hashing modulo the number of instances. Adding or removing a gitserver instance then only moves the repositories assigned to that instance. Enabling this moves most repositories once; they are copied between gitserver instances rather than recloned.
+	GitServerConsistentHashing bool `json:"gitServerConsistentHashing,omitempty"`
+	// GoModules description: Allow adding Go modules code host connections
+	GoModules string `json:"goModules,omitempty"`
 	// JvmPackages description: Allow adding JVM packages code host connections
 	JvmPackages string `json:"jvmPackages,omitempty"`
+	// NpmPackages description: Allow adding npm packages code host connections
+	NpmPackages string `json:"npmPackages,omitempty"`
 	// Perforce description: Allow adding Perforce code host connections
 	Perforce string `json:"perforce,omitempty"`
 	// Ranking description: Experimental search result ranking options.
@@ -574,6 +613,52 @@ type ExternalIdentity struct {
 	Type           string `json:"type"`
 }
 
+// GerritAuthorization description: If non-null, enforces Gerrit project permissions. Sourcegraph assumes usernames are identical in Sourcegraph and Gerrit accounts, so `auth.enableUsernameChanges` must be set to false for security reasons.
+type GerritAuthorization struct {
+}
+
+// GerritConnection description: Configuration for a connection to Gerrit.
+type GerritConnection struct {
+	// Authorization description: If non-null, enforces Gerrit project permissions. Sourcegraph assumes usernames are identical in Sourcegraph and Gerrit accounts, so `auth.enableUsernameChanges` must be set to false for security reasons.
+	Authorization *GerritAuthorization `json:"authorization,omitempty"`
+	// Exclude description: A list of projects to never mirror from this Gerrit instance. Takes precedence over "projects" configuration.
+	//
+	// Supports excluding by name ({"name": "platform/build"}) or by regular expression ({"pattern": "^archived/.*"}).
+	Exclude []*ExcludedGerritProject `json:"exclude,omitempty"`
+	// GitURLType description: The type of Git URLs to use for cloning and fetching Git repositories on this Gerrit instance.
+	//
+	// If "http", Sourcegraph will access Gerrit projects using authenticated Git URLs of the form https://gerrit.example.com/a/myproject.
+	//
+	// If "ssh", Sourcegraph will access Gerrit projects using Git URLs of the form ssh://username@gerrit.example.com:29418/myproject. See the documentation for how to provide SSH private keys and known_hosts: https://docs.sourcegraph.com/admin/repo/auth#repositories-that-need-http-s-or-ssh-authentication.
+	GitURLType string `json:"gitURLType,omitempty"`
+	// Password description: The HTTP password of the Gerrit account used to authenticate to the REST API, as generated on the account's "HTTP Credentials" settings page. If you are enabling permissions, the account must have the "Run As" global capability.
+	Password string `json:"password"`
+	// Projects description: An array of project names specifying which Gerrit projects to mirror on Sourcegraph. If empty, all projects visible to the configured account are mirrored, except for the All-Projects and All-Users projects that hold Gerrit's own configuration.
+	Projects []string `json:"projects,omitempty"`
+	// RateLimit description: Rate limit applied when making background API requests to Gerrit.
+	RateLimit *GerritRateLimit `json:"rateLimit,omitempty"`
+	// RepositoryPathPattern description: The pattern used to generate the corresponding Sourcegraph repository name for a Gerrit project. In the pattern, the variable "{host}" is replaced with the Gerrit URL's host (such as gerrit.example.com), and "{name}" is replaced with the Gerrit project's name (such as "platform/build").
+	//
+	// For example, if your Gerrit is https://gerrit.example.com and your Sourcegraph is https://src.example.com, then a repositoryPathPattern of "{host}/{name}" would mean that a Gerrit project at https://gerrit.example.com/platform/build is available on Sourcegraph at https://src.example.com/gerrit.example.com/platform/build.
+	//
+	// It is important that the Sourcegraph repository name generated with this pattern be unique to this code host. If different code hosts generate repository names that collide, Sourcegraph's behavior is undefined.
+	RepositoryPathPattern string `json:"repositoryPathPattern,omitempty"`
+	// SshPort description: The port of the Gerrit SSH daemon. Only used when "gitURLType" is "ssh".
+	SshPort int `json:"sshPort,omitempty"`
+	// Url description: URL of a Gerrit instance, such as https://gerrit.example.com.
+	Url string `json:"url"`
+	// Username description: The username of the Gerrit account used to authenticate to the REST API. Also set the corresponding "password" field.
+	Username string `json:"username"`
+}
+
+// GerritRateLimit description: Rate limit applied when making background API requests to Gerrit.
+type GerritRateLimit struct {
+	// Enabled description: true if rate limiting is enabled.
+	Enabled bool `json:"enabled"`
+	// RequestsPerHour description: Requests per hour permitted. This is an average, calculated per second. Internally, the burst limit is set to 500, which implies that for a requests per hour limit as low as 1, users will continue to be able to send a maximum of 500 requests immediately, provided that the complexity cost of each request is 1.
+	RequestsPerHour float64 `json:"requestsPerHour"`
+}
+
 // GitCommitAuthor description: The author of the Git commit.
 type GitCommitAuthor struct {
 	// Email description: The Git commit author email.
@@ -639,7 +724,8 @@ type GitHubConnection struct {
 	// InitialRepositoryEnablement description: Deprecated and ignored field which will be removed entirely in the next release. GitHub repositories can no longer be enabled or disabled explicitly. Configure repositories to be mirrored via "repos", "exclude" and "repositoryQuery" instead.
 	InitialRepositoryEnablement bool `json:"initialRepositoryEnablement,omitempty"`
 	// Orgs description: An array of organization names identifying GitHub organizations whose repositories should be mirrored on Sourcegraph.
-	Orgs []string `json:"orgs,omitempty"`
+	Orgs         []string            `json:"orgs,omitempty"`
+	PartialClone []*PartialCloneRule `json:"partialClone,omitempty"`
 	// RateLimit description: Rate limit applied when making background API requests to GitHub.
 	RateLimit *GitHubRateLimit `json:"rateLimit,omitempty"`
 	// Repos description: An array of repository "owner/name" strings specifying which GitHub or GitHub Enterprise repositories to mirror on Sourcegraph.
@@ -729,6 +815,7 @@ type GitLabConnection struct {
 	InitialRepositoryEnablement bool `json:"initialRepositoryEnablement,omitempty"`
 	// NameTransformations description: An array of transformations will apply to the repository name. Currently, only regex replacement is supported. All transformations happen after "repositoryPathPattern" is processed.
 	NameTransformations []*GitLabNameTransformation `json:"nameTransformations,omitempty"`
+	PartialClone        []*PartialCloneRule         `json:"partialClone,omitempty"`
 	// ProjectQuery description: An array of strings specifying which GitLab projects to mirror on Sourcegraph. Each string is a URL path and query that targets a GitLab API endpoint returning a list of projects. If the string only contains a query, then "projects" is used as the path. Examples: "?membership=true&search=foo", "groups/mygroup/projects".
 	//
 	// The special string "none" can be used as the only element to disable this feature. Projects matched by multiple query strings are only imported once. Here are a few endpoints that return a list of projects: https://docs.gitlab.com/ee/api/projects.html#list-all-projects, https://docs.gitlab.com/ee/api/groups.html#list-a-groups-projects, https://docs.gitlab.com/ee/api/search.html#scope-projects.
@@ -777,6 +864,50 @@ type GitLabWebhook struct {
 	Secret string `json:"secret"`
 }
 
+// GiteaAuthorization description: If non-null, enforces Gitea repository permissions. Sourcegraph assumes usernames are identical in Sourcegraph and Gitea accounts, so `auth.enableUsernameChanges` must be set to false for security reasons.
+type GiteaAuthorization struct {
+}
+
+// GiteaConnection description: Configuration for a connection to Gitea.
+type GiteaConnection struct {
+	// Authorization description: If non-null, enforces Gitea repository permissions. Sourcegraph assumes usernames are identical in Sourcegraph and Gitea accounts, so `auth.enableUsernameChanges` must be set to false for security reasons.
+	Authorization *GiteaAuthorization `json:"authorization,omitempty"`
+	// Exclude description: A list of repositories to never mirror from this Gitea instance. Takes precedence over "orgs" and "repos" configuration.
+	//
+	// Supports excluding by name ({"name": "owner/name"}), by ID ({"id": 42}) or by regular expression ({"pattern": "^myorg/.*"}).
+	Exclude []*ExcludedGiteaRepo `json:"exclude,omitempty"`
+	// GitURLType description: The type of Git URLs to use for cloning and fetching Git repositories on this Gitea instance.
+	//
+	// If "http", Sourcegraph will access Gitea repositories using Git URLs of the form http(s)://gitea.example.com/myteam/myproject.git (using https: if the Gitea instance uses HTTPS).
+	//
+	// If "ssh", Sourcegraph will access Gitea repositories using Git URLs of the form git@gitea.example.com:myteam/myproject.git. See the documentation for how to provide SSH private keys and known_hosts: https://docs.sourcegraph.com/admin/repo/auth#repositories-that-need-http-s-or-ssh-authentication.
+	GitURLType string `json:"gitURLType,omitempty"`
+	// Orgs description: An array of organization names identifying Gitea organizations whose repositories should be mirrored on Sourcegraph. If neither "orgs" nor "repos" is set, all repositories accessible to the token are mirrored.
+	Orgs []string `json:"orgs,omitempty"`
+	// RateLimit description: Rate limit applied when making background API requests to Gitea.
+	RateLimit *GiteaRateLimit `json:"rateLimit,omitempty"`
+	// Repos description: An array of repository "owner/name" strings specifying which Gitea repositories to mirror on Sourcegraph.
+	Repos []string `json:"repos,omitempty"`
+	// RepositoryPathPattern description: The pattern used to generate the corresponding Sourcegraph repository name for a Gitea repository. In the pattern, the variable "{host}" is replaced with the Gitea URL's host (such as gitea.example.com), and "{nameWithOwner}" is replaced with the Gitea repository's "owner/path" (such as "myteam/myrepo").
+	//
+	// For example, if your Gitea is https://gitea.example.com and your Sourcegraph is https://src.example.com, then a repositoryPathPattern of "{host}/{nameWithOwner}" would mean that a Gitea repository at https://gitea.example.com/myteam/myrepo is available on Sourcegraph at https://src.example.com/gitea.example.com/myteam/myrepo.
+	//
+	// It is important that the Sourcegraph repository name generated with this pattern be unique to this code host. If different code hosts generate repository names that collide, Sourcegraph's behavior is undefined.
+	RepositoryPathPattern string `json:"repositoryPathPattern,omitempty"`
+	// Token description: A Gitea access token with read access to the repositories that should be mirrored. If you are enabling permissions, this token must belong to a site admin so that Sourcegraph can act on behalf of other users.
+	Token string `json:"token"`
+	// Url description: URL of a Gitea instance, such as https://gitea.example.com.
+	Url string `json:"url"`
+}
+
+// GiteaRateLimit description: Rate limit applied when making background API requests to Gitea.
+type GiteaRateLimit struct {
+	// Enabled description: true if rate limiting is enabled.
+	Enabled bool `json:"enabled"`
+	// RequestsPerHour description: Requests per hour permitted. This is an average, calculated per second. Internally, the burst limit is set to 500, which implies that for a requests per hour limit as low as 1, users will continue to be able to send a maximum of 500 requests immediately, provided that the complexity cost of each request is 1.
+	RequestsPerHour float64 `json:"requestsPerHour"`
+}
+
 // GitoliteConnection description: Configuration for a connection to Gitolite.
 type GitoliteConnection struct {
 	// Exclude description: A list of repositories to never mirror from this Gitolite instance. Supports excluding by exact name ({"name": "foo"}).
@@ -793,6 +924,24 @@ type GitoliteConnection struct {
 	Prefix string `json:"prefix"`
 }
 
+// GoModulesConnection description: Configuration for a connection to Go module proxies.
+type GoModulesConnection struct {
+	// Dependencies description: An array of "module@version" strings specifying which Go modules to mirror on Sourcegraph.
+	Dependencies []string `json:"dependencies,omitempty"`
+	// RateLimit description: Rate limit applied when making background API requests to the Go module proxies.
+	RateLimit *GoRateLimit `json:"rateLimit,omitempty"`
+	// Urls description: The list of Go module proxy URLs to fetch modules from. Proxies are tried in order until one of them has the requested module.
+	Urls []string `json:"urls,omitempty"`
+}
+
+// GoRateLimit description: Rate limit applied when making background API requests to the Go module proxies.
+type GoRateLimit struct {
+	// Enabled description: true if rate limiting is enabled.
+	Enabled bool `json:"enabled"`
+	// RequestsPerHour description: Requests per hour permitted. This is an average, calculated per second. Internally, the burst limit is set to 100, which implies that for a requests per hour limit as low as 1, users will continue to be able to send a maximum of 100 requests immediately, provided that the complexity cost of each request is 1.
+	RequestsPerHour float64 `json:"requestsPerHour"`
+}
+
 // HTTPHeaderAuthProvider description: Configures the HTTP header authentication provider (which authenticates users by consulting an HTTP request header set by an authentication proxy such as https://github.com/bitly/oauth2_proxy).
 type HTTPHeaderAuthProvider struct {
 	// EmailHeader description: The name (case-insensitive) of an HTTP header whose value is taken to be the email of the client requesting the page. Set this value when using an HTTP proxy that authenticates requests, and you don't want the extra configurability of the other authentication methods.
@@ -865,6 +1014,8 @@ type InsightDashboard struct {
 	Title string `json:"title"`
 }
 type InsightSeries struct {
+	// GeneratedFromCaptureGroups description: Record one series per value of the first capture group of the regular expression search query, instead of a single series with the number of results. For example, the query `github.com/foo/bar v(\d+\.\d+)` shows one series per version.
+	GeneratedFromCaptureGroups bool `json:"generatedFromCaptureGroups,omitempty"`
 	// Label description: The label to use for the series in the graph.
 	Label string `json:"label"`
 	// RepositoriesList description: Performs a search query and shows the number of results returned.
@@ -1029,6 +1180,26 @@ type NotifierWebhook struct {
 	Url         string `json:"url"`
 	Username    string `json:"username,omitempty"`
 }
+
+// NpmPackagesConnection description: Configuration for a connection to an npm packages registry.
+type NpmPackagesConnection struct {
+	// Credentials description: Access token for logging into the npm registry.
+	Credentials string `json:"credentials,omitempty"`
+	// Dependencies description: An array of "(@scope/)?packageName@version" strings specifying which npm packages to mirror on Sourcegraph.
+	Dependencies []string `json:"dependencies,omitempty"`
+	// RateLimit description: Rate limit applied when making background API requests to the npm registry.
+	RateLimit *NpmRateLimit `json:"rateLimit,omitempty"`
+	// Registry description: The URL at which the npm registry can be found.
+	Registry string `json:"registry,omitempty"`
+}
+
+// NpmRateLimit description: Rate limit applied when making background API requests to the npm registry.
+type NpmRateLimit struct {
+	// Enabled description: true if rate limiting is enabled.
+	Enabled bool `json:"enabled"`
+	// RequestsPerHour description: Requests per hour permitted. This is an average, calculated per second. Internally, the burst limit is set to 100, which implies that for a requests per hour limit as low as 1, users will continue to be able to send a maximum of 100 requests immediately, provided that the complexity cost of each request is 1.
+	RequestsPerHour float64 `json:"requestsPerHour"`
+}
 type OAuthIdentity struct {
 	Type string `json:"type"`
 }
@@ -1088,7 +1259,8 @@ type OpenIDConnectAuthProvider struct {
 
 // OtherExternalServiceConnection description: Configuration for a Connection to Git repositories for which an external service integration isn't yet available.
 type OtherExternalServiceConnection struct {
-	Repos []string `json:"repos"`
+	PartialClone []*PartialCloneRule `json:"partialClone,omitempty"`
+	Repos        []string            `json:"repos"`
 	// RepositoryPathPattern description: The pattern used to generate the corresponding Sourcegraph repository name for the repositories. In the pattern, the variable "{base}" is replaced with the Git clone base URL host and path, and "{repo}" is replaced with the repository path taken from the `repos` field.
 	//
 	// For example, if your Git clone base URL is https://git.example.com/repos and `repos` contains the value "my/repo", then a repositoryPathPattern of "{base}/{repo}" would mean that a repository at https://git.example.com/repos/my/repo is available on Sourcegraph at https://sourcegraph.example.com/git.example.com/repos/my/repo.
@@ -1108,6 +1280,14 @@ type Overrides struct {
 type ParentSourcegraph struct {
 	Url string `json:"url,omitempty"`
 }
+type PartialCloneRule struct {
+	// BlobSizeLimit description: Only file contents larger than this size are left out of the clone, e.g. "1m". Units k, m and g are supported. If unset, no file contents are cloned.
+	BlobSizeLimit string `json:"blobSizeLimit,omitempty"`
+	// ExcludePaths description: Paths in the repository, such as vendored or binary directories, which are never searched. Their file contents are not fetched for searching.
+	ExcludePaths []string `json:"excludePaths,omitempty"`
+	// Pattern description: Regular expression which matches against the name of the repository on Sourcegraph.
+	Pattern string `json:"pattern"`
+}
 
 // PerforceAuthorization description: If non-null, enforces Perforce depot permissions.
 type PerforceAuthorization struct {
@@ -1452,6 +1632,8 @@ type SiteConfiguration struct {
 	CampaignsRestrictToAdmins *bool `json:"campaigns.restrictToAdmins,omitempty"`
 	// CodeIntelAutoIndexingEnabled description: Enables/disables the code intel auto indexing feature. This feature is currently supported only on certain managed Sourcegraph instances.
 	CodeIntelAutoIndexingEnabled *bool `json:"codeIntelAutoIndexing.enabled,omitempty"`
+	// CodeMonitorsWebhookAllowedPrivateAddresses description: Private addresses that code monitor webhook and Slack actions may post to. By default, webhooks may only be posted to public addresses. Each entry is either an IP address range in CIDR notation, or a host name, which may then resolve to any address. If outgoing requests go through a proxy on a private address, the proxy must be allowed too.
+	CodeMonitorsWebhookAllowedPrivateAddresses []string `json:"codeMonitors.webhookAllowedPrivateAddresses,omitempty"`
 	// CorsOrigin description: Required when using any of the native code host integrations for Phabricator, GitLab, or Bitbucket Server. It is a space-separated list of allowed origins for cross-origin HTTP requests which should be the base URL for your Phabricator, GitLab, or Bitbucket Server instance.
 	CorsOrigin string `json:"corsOrigin,omitempty"`
 	// DebugSearchSymbolsParallelism description: (debug) controls the amount of symbol search parallelism. Defaults to 20. It is not recommended to change this outside of debugging scenarios. This option will be removed in a future version.
@@ -22,6 +22,10 @@ var BitbucketServerSchemaJSON string
 //go:embed changeset_spec.schema.json
 var ChangesetSpecSchemaJSON string
 
+// GerritSchemaJSON is the content of the file "gerrit.schema.json".
+//go:embed gerrit.schema.json
+var GerritSchemaJSON string
+
 // GitHubSchemaJSON is the content of the file "github.schema.json".
 //go:embed github.schema.json
 var GitHubSchemaJSON string
@@ -30,18 +34,34 @@ var GitHubSchemaJSON string
 //go:embed gitlab.schema.json
 var GitLabSchemaJSON string
 
+// GiteaSchemaJSON is the content of the file "gitea.schema.json".
+//go:embed gitea.schema.json
+var GiteaSchemaJSON string
+
 // GitoliteSchemaJSON is the content of the file "gitolite.schema.json".
 //go:embed gitolite.schema.json
 var GitoliteSchemaJSON string
 
+// GoModulesSchemaJSON is the content of the file "go-modules.schema.json".
+//go:embed go-modules.schema.json
+var GoModulesSchemaJSON string
+
 // JVMPackagesSchemaJSON is the content of the file "jvm-packages.schema.json".
 //go:embed jvm-packages.schema.json
 var JVMPackagesSchemaJSON string
 
+// NpmPackagesSchemaJSON is the content of the file "npm-packages.schema.json".
+//go:embed npm-packages.schema.json
+var NpmPackagesSchemaJSON string
+
 // OtherExternalServiceSchemaJSON is the content of the file "other_external_service.schema.json".
 //go:embed other_external_service.schema.json
 var OtherExternalServiceSchemaJSON string
 
+// PartialCloneSchemaJSON is the content of the file "partial_clone.schema.json".
+//go:embed partial_clone.schema.json
+var PartialCloneSchemaJSON string
+
 // PerforceSchemaJSON is the content of the file "perforce.schema.json".
 //go:embed perforce.schema.json
 var PerforceSchemaJSON string